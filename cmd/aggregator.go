@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"bootstrap/pkg/inventory"
+	"bootstrap/pkg/redfish"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	aggregatorHost   string
+	aggregatorMap    string
+	aggregatorPrefix string
+)
+
+// configureAggregator sends Redfish requests through --aggregator when set. BMCs
+// are looked up by xname (or the host given with --hosts) in --aggregator-map, a
+// YAML mapping of BMC to path prefix, falling back to the --aggregator-prefix
+// template.
+func configureAggregator() error {
+	if aggregatorHost == "" {
+		if aggregatorMap != "" || aggregatorPrefix != "" {
+			return invalidf("--aggregator-map and --aggregator-prefix require --aggregator")
+		}
+		redfish.SetAggregator(nil)
+		return nil
+	}
+	if aggregatorMap == "" && aggregatorPrefix == "" {
+		return invalidf("--aggregator requires --aggregator-map or --aggregator-prefix")
+	}
+	agg := &redfish.Aggregator{Host: canonicalHost(aggregatorHost), Template: aggregatorPrefix, Prefixes: map[string]string{}}
+	if aggregatorMap != "" {
+		raw, err := os.ReadFile(aggregatorMap)
+		if err != nil {
+			return invalid(err)
+		}
+		var m map[string]string
+		if err := yaml.Unmarshal(raw, &m); err != nil {
+			return invalidf("%s: %w", aggregatorMap, err)
+		}
+		for k, v := range m {
+			agg.Prefixes[canonicalHost(k)] = v
+		}
+	}
+	redfish.SetAggregator(agg)
+	return nil
+}
+
+// redfishHost returns the host to open a client for the BMC of bmcs reached at
+// host. Behind an aggregator BMCs are addressed by xname, so an IP is replaced
+// by the xname of its bmcs[] entry.
+func redfishHost(host string, bmcs []inventory.Entry) string {
+	if aggregatorHost == "" {
+		return host
+	}
+	for _, b := range bmcs {
+		if b.Xname != "" && strings.EqualFold(b.IP, host) {
+			return b.Xname
+		}
+	}
+	return host
+}
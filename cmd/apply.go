@@ -0,0 +1,242 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"bootstrap/internal/desired"
+	"bootstrap/pkg/inventory"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyFile      string
+	applyHostsCSV  string
+	applyHostsFile string
+	applyInsecure  bool
+	applyTimeout   time.Duration
+	applyBatchSize int
+	applyDryRun    bool
+	applyFormat    string
+)
+
+// Per-host reconciliation outcomes
+const (
+	reconcileInSync  = "in-sync"
+	reconcileDrift   = "drift"
+	reconcilePlanned = "planned"
+	reconcileApplied = "applied"
+	reconcileFailed  = "failed"
+	reconcileAborted = "aborted"
+)
+
+// hostReport is the reconciliation result for one BMC.
+type hostReport struct {
+	Host    string           `json:"host"`
+	Status  string           `json:"status"`
+	Changes []desired.Change `json:"changes"`
+	Error   string           `json:"error,omitempty"`
+	err     error
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Reconcile BMCs toward a desired-state file",
+	Long: `Read a desired-state YAML (inventory, firmware versions, BIOS profile, BMC
+settings and SSH keys), probe each BMC, and apply the changes needed to reach
+that state. Changes are applied in dependency order: SSH keys, BMC settings,
+BMC firmware, other firmware, then BIOS attributes.`,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		st, hosts, user, pass, err := loadDesired(applyFile, applyHostsCSV, applyHostsFile)
+		if err != nil {
+			return err
+		}
+		ctx, launch := cmd.Context(), cmd.Context()
+		if !applyDryRun {
+			if stop, err := needsApproval(cmd, args, "apply", hosts); stop || err != nil {
+				return err
+			}
+			var stopLaunch context.CancelFunc
+			launch, stopLaunch = untilWindowCloses(ctx)
+			defer stopLaunch()
+		}
+		reports := reconcileHosts(ctx, launch, st, hosts, user, pass, applyInsecure, applyTimeout, applyBatchSize, !applyDryRun)
+		if applyDryRun {
+			markPlanned(reports)
+		}
+		if err := printReconcileReport(reports, applyFormat); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+		if stopped := launchStopped(ctx, launch); stopped != nil {
+			failed := map[string]error{}
+			var aborted []string
+			for _, r := range reports {
+				if r.Status == reconcileAborted {
+					aborted = append(aborted, r.Host)
+				} else if r.err != nil {
+					failed[r.Host] = r.err
+				}
+			}
+			return reportStopped(stopped, hosts, aborted, failed)
+		}
+		return reconcileFailures(len(hosts), reports)
+	},
+}
+
+// loadDesired reads the desired-state file and resolves the hosts to target and the
+// Redfish credentials. hostsCSV or hostsFile, when set, override the hosts in the file.
+func loadDesired(file, hostsCSV, hostsFile string) (desired.State, []string, string, string, error) {
+	if file == "" {
+		return desired.State{}, nil, "", "", invalidf("--file is required")
+	}
+	st, err := desired.Load(file)
+	if err != nil {
+		return desired.State{}, nil, "", "", invalid(err)
+	}
+	hostNames, resolvedBMCs = aliasNames(inventory.FileFormat{BMCs: st.BMCs}), st.BMCs
+	hosts, err := hostList(hostsCSV, hostsFile, inventory.FileFormat{BMCs: st.BMCs})
+	if err != nil {
+		return desired.State{}, nil, "", "", err
+	}
+	if hosts == nil {
+		hosts = bmcHosts(dedupeBMCs(st.BMCs))
+	}
+	if hosts, err = selectTagged(hosts, inventory.FileFormat{BMCs: st.BMCs}, file); err != nil {
+		return desired.State{}, nil, "", "", err
+	}
+	if len(hosts) == 0 {
+		return desired.State{}, nil, "", "", invalidf("no hosts to reconcile")
+	}
+	user, pass, err := redfishCreds()
+	if err != nil {
+		return desired.State{}, nil, "", "", err
+	}
+	return st, hosts, user, pass, nil
+}
+
+// reconcileHosts probes every host and diffs it against st. With apply set the
+// changes are executed; otherwise they are only reported. Hosts are started
+// under launch, ctx or a context derived from it that may end first.
+func reconcileHosts(ctx, launch context.Context, st desired.State, hosts []string, user, pass string, insecure bool, timeout time.Duration, batch int, apply bool) []hostReport {
+	var mu sync.Mutex
+	var reports []hostReport
+	record := func(r hostReport) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.err != nil {
+			r.Error = r.err.Error()
+			fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", hostName(r.Host), r.err)
+		}
+		reports = append(reports, r)
+	}
+	forEachHost(launch, hosts, batch, func(_ context.Context, h string) {
+		conn := withLedger(newRedfishClient(h, user, pass, insecure, timeout), h)
+		obs, err := desired.Probe(ctx, conn, st)
+		if err != nil {
+			record(hostReport{Host: h, Status: reconcileFailed, err: fmt.Errorf("probe: %w", err)})
+			return
+		}
+		changes := desired.Diff(h, st, obs)
+		r := hostReport{Host: h, Changes: changes}
+		switch {
+		case len(changes) == 0:
+			r.Status = reconcileInSync
+		case !apply:
+			r.Status = reconcileDrift
+		default:
+			r.Status = reconcileApplied
+			if err := desired.Apply(ctx, conn, st, obs, r.Changes); err != nil {
+				r.Status = reconcileFailed
+				r.err = err
+			}
+		}
+		record(r)
+	}, func(h string) {
+		record(hostReport{Host: h, Status: reconcileAborted})
+	})
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Host < reports[j].Host })
+	return reports
+}
+
+// markPlanned turns drift into planned changes for an apply --dry-run.
+func markPlanned(reports []hostReport) {
+	for i := range reports {
+		if reports[i].Status != reconcileDrift {
+			continue
+		}
+		reports[i].Status = reconcilePlanned
+		for j := range reports[i].Changes {
+			reports[i].Changes[j].Status = desired.StatusPlanned
+		}
+	}
+}
+
+func printReconcileReport(reports []hostReport, format string) error {
+	if strings.EqualFold(format, "json") {
+		out, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	counts := map[string]int{}
+	for _, r := range reports {
+		counts[r.Status]++
+		if len(r.Changes) == 0 {
+			continue
+		}
+		fmt.Printf("%s: %d change(s)\n", hostName(r.Host), len(r.Changes))
+		for _, c := range r.Changes {
+			what := c.Kind
+			if c.Target != "" {
+				what += " " + c.Target
+			}
+			fmt.Printf("    %s: %q -> %q [%s]\n", what, c.Current, c.Desired, c.Status)
+		}
+	}
+	fmt.Println("Reconciliation summary:")
+	for _, s := range []string{reconcileInSync, reconcileDrift, reconcilePlanned, reconcileApplied, reconcileFailed, reconcileAborted} {
+		if counts[s] > 0 {
+			fmt.Printf("  %s: %d\n", s, counts[s])
+		}
+	}
+	return nil
+}
+
+// reconcileFailures converts failed hosts into the command's exit error.
+func reconcileFailures(total int, reports []hostReport) error {
+	failed := map[string]error{}
+	for _, r := range reports {
+		if r.err != nil {
+			failed[r.Host] = r.err
+		}
+	}
+	return hostFailures(total, failed)
+}
+
+func init() {
+	applyCmd.Flags().StringVarP(&applyFile, "file", "f", "", "desired-state YAML file")
+	applyCmd.Flags().StringVar(&applyHostsCSV, "hosts", "", "comma-separated BMC hosts (overrides the hosts in --file)")
+	applyCmd.Flags().StringVar(&applyHostsFile, "hosts-file", "", "file listing BMC hosts or xnames, one per line (overrides the hosts in --file)")
+	applyCmd.Flags().BoolVar(&applyInsecure, "insecure", false, "allow insecure TLS to BMCs")
+	applyCmd.Flags().DurationVar(&applyTimeout, "timeout", 30*time.Second, "per-request timeout")
+	applyCmd.Flags().IntVar(&applyBatchSize, "batch-size", 10, "number of BMCs to reconcile concurrently")
+	applyCmd.Flags().BoolVar(&applyDryRun, "dry-run", false, "probe and report planned changes without applying them")
+	applyCmd.Flags().StringVar(&applyFormat, "format", "", "output format: json")
+	rootCmd.AddCommand(applyCmd)
+}
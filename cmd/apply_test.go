@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockNetworkProtocolServer emulates the NTP and OEM SSH key settings of a BMC.
+func mockNetworkProtocolServer(t *testing.T) (*httptest.Server, func() (string, []string)) {
+	t.Helper()
+	var mu sync.Mutex
+	keys := "ssh-rsa OLD admin\n"
+	ntp := []string{"192.0.2.1"}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/Managers/BMC/NetworkProtocol") {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPatch:
+			var body struct {
+				NTP *struct{ NTPServers []string }
+				Oem *struct {
+					SSHAdmin struct{ AuthorizedKeys string }
+				}
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if body.NTP != nil {
+				ntp = body.NTP.NTPServers
+			}
+			if body.Oem != nil {
+				keys = body.Oem.SSHAdmin.AuthorizedKeys
+			}
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+				"NTP": map[string]any{"ProtocolEnabled": true, "NTPServers": ntp},
+				"Oem": map[string]any{"SSHAdmin": map[string]any{"AuthorizedKeys": keys}},
+			})
+		}
+	})
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+	return server, func() (string, []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		return keys, ntp
+	}
+}
+
+// writeTestState writes a desired-state file targeting server.
+func writeTestState(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+	state := filepath.Join(t.TempDir(), "state.yaml")
+	content := "bmcs:\n  - xname: x1000c0s0b0\n    ip: " + strings.TrimPrefix(server.URL, "https://") + `
+bmc:
+  ntp_servers: [10.0.0.1, 10.0.0.2]
+ssh_keys:
+  - ssh-ed25519 NEW ops
+`
+	if err := os.WriteFile(state, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+func TestApplyReconcilesBMC(t *testing.T) {
+	server, current := mockNetworkProtocolServer(t)
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+
+	applyFile = writeTestState(t, server)
+	applyHostsCSV = ""
+	applyInsecure = true
+	applyTimeout = 2 * time.Second
+	applyBatchSize = 1
+	applyFormat = ""
+
+	cmd := applyCmd
+	cmd.SetContext(context.Background())
+
+	// Dry run leaves the BMC untouched
+	applyDryRun = true
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if keys, ntp := current(); keys != "ssh-rsa OLD admin\n" || len(ntp) != 1 {
+		t.Fatalf("dry run changed BMC: %q %v", keys, ntp)
+	}
+
+	applyDryRun = false
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	keys, ntp := current()
+	if keys != "ssh-rsa OLD admin\nssh-ed25519 NEW ops\n" {
+		t.Errorf("unexpected keys: %q", keys)
+	}
+	if strings.Join(ntp, ",") != "10.0.0.1,10.0.0.2" {
+		t.Errorf("unexpected ntp servers: %v", ntp)
+	}
+
+	// Converged: a second run has nothing to do
+	st, hosts, user, pass, err := loadDesired(applyFile, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reports := reconcileHosts(context.Background(), context.Background(), st, hosts, user, pass, true, 2*time.Second, 1, false)
+	if len(reports) != 1 || reports[0].Status != reconcileInSync {
+		t.Fatalf("expected in-sync after apply, got %+v", reports)
+	}
+}
+
+func TestDiffReportsDrift(t *testing.T) {
+	server, current := mockNetworkProtocolServer(t)
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+
+	diffFile = writeTestState(t, server)
+	diffHostsCSV = ""
+	diffInsecure = true
+	diffTimeout = 2 * time.Second
+	diffBatchSize = 1
+	diffFormat = "json"
+
+	cmd := diffCmd
+	cmd.SetContext(context.Background())
+	if err := cmd.RunE(cmd, nil); exitCode(err) != exitPartial {
+		t.Fatalf("expected drift exit status, got %v", err)
+	}
+	if keys, _ := current(); keys != "ssh-rsa OLD admin\n" {
+		t.Fatalf("diff changed BMC keys: %q", keys)
+	}
+}
@@ -0,0 +1,260 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"bootstrap/internal/approval"
+	"bootstrap/internal/redact"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	requireApproval bool
+	approvalFile    string
+	operatorKeyFile string
+	rolesFile       string
+	keygenOut       string
+)
+
+// executing is the plan being run by "execute". Its command passes the
+// approval gate if it resolves to the hosts that were approved.
+var executing *approval.Plan
+
+// approvalFlags configure the approval itself and are left out of plans.
+var approvalFlags = []string{"require-approval", "approval-file", "operator-key", "roles", "progress-fd", "yes"}
+
+// needsApproval is called by destructive commands once their hosts are known.
+// With --require-approval (or $BOOTSTRAP_REQUIRE_APPROVAL) it writes a signed
+// plan for the command instead of running it and returns true; the command
+// then stops. Otherwise the command must be inside a maintenance window (see
+// checkWindow) and the operator confirms its impact (see confirmImpact);
+// approved plans need no confirmation but must respect the window too.
+func needsApproval(cmd *cobra.Command, args []string, op string, hosts []string) (bool, error) {
+	if executing != nil {
+		if !slices.Equal(slices.Sorted(slices.Values(hosts)), slices.Sorted(slices.Values(executing.Hosts))) {
+			return false, invalidf("plan %s: the command now targets %s, not the approved %s",
+				executing.ID, strings.Join(hosts, ","), strings.Join(executing.Hosts, ","))
+		}
+		return false, checkWindow(op)
+	}
+	if on, _ := strconv.ParseBool(os.Getenv("BOOTSTRAP_REQUIRE_APPROVAL")); !requireApproval && !on {
+		if err := checkWindow(op); err != nil {
+			return false, err
+		}
+		return false, confirmImpact(cmd, args, op, hosts)
+	}
+	key, roles, err := approvalIdentity(approval.RoleOperator)
+	if err != nil {
+		return false, err
+	}
+	argv, inputs := commandLine(cmd, args)
+	for _, a := range argv {
+		if redact.String(a) != a {
+			return false, invalidf("%s holds a secret, and plans are stored and shown to approvers; pass it through the environment instead", redact.String(a))
+		}
+	}
+	plan, err := approval.NewPlan(op, argv, hosts, roles.TTL())
+	if err != nil {
+		return false, err
+	}
+	for _, in := range inputs {
+		if err := plan.AddInput(in); err != nil {
+			return false, err
+		}
+	}
+	plan.Sign(key)
+	path := approvalFile
+	if path == "" {
+		path = "plan-" + plan.ID + ".json"
+	}
+	if err := plan.Save(path); err != nil {
+		return false, err
+	}
+	fmt.Printf("Wrote plan %s (%s on %d host(s)) to %s\n", plan.ID, op, len(hosts), path)
+	fmt.Printf("It runs once approved by another operator: approve %s, then execute %s\n", path, path)
+	return true, nil
+}
+
+// commandLine rebuilds the command line of cmd from its path, the flags that
+// were set and args, and returns it with the files named by flag values.
+func commandLine(cmd *cobra.Command, args []string) (argv, inputs []string) {
+	argv = strings.Fields(cmd.CommandPath())[1:]
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if slices.Contains(approvalFlags, f.Name) {
+			return
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range sv.GetSlice() {
+				argv = append(argv, "--"+f.Name+"="+v)
+			}
+			return
+		}
+		argv = append(argv, "--"+f.Name+"="+f.Value.String())
+		if fi, err := os.Stat(f.Value.String()); err == nil && fi.Mode().IsRegular() {
+			inputs = append(inputs, f.Value.String())
+		}
+	})
+	return append(argv, args...), inputs
+}
+
+// approvalIdentity loads --operator-key and --roles and checks that the key
+// holds role.
+func approvalIdentity(role string) (approval.Key, approval.Roles, error) {
+	keyFile := cmpOrEnv(operatorKeyFile, "BOOTSTRAP_OPERATOR_KEY")
+	roles := cmpOrEnv(rolesFile, "BOOTSTRAP_ROLES")
+	if keyFile == "" || roles == "" {
+		return approval.Key{}, approval.Roles{}, invalidf("--operator-key and --roles (or $BOOTSTRAP_OPERATOR_KEY and $BOOTSTRAP_ROLES) are required for approvals")
+	}
+	key, err := approval.LoadKey(keyFile)
+	if err != nil {
+		return approval.Key{}, approval.Roles{}, invalid(err)
+	}
+	r, err := approval.LoadRoles(roles)
+	if err != nil {
+		return approval.Key{}, approval.Roles{}, invalid(err)
+	}
+	if err := r.Check(key, role); err != nil {
+		return approval.Key{}, approval.Roles{}, &exitError{code: exitAuth, err: err}
+	}
+	return key, r, nil
+}
+
+func cmpOrEnv(v, env string) string {
+	if v != "" {
+		return v
+	}
+	return os.Getenv(env)
+}
+
+var approveCmd = &cobra.Command{
+	Use:   "approve PLAN",
+	Short: "Review and approve another operator's plan",
+	Long: `Show a plan written by a command run with --require-approval and sign it as
+approver. Plans need approval from an operator other than their creator holding the
+approver role in the roles file; approvals_required there sets how many.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		plan, err := approval.Load(args[0])
+		if err != nil {
+			return invalid(err)
+		}
+		key, roles, err := approvalIdentity(approval.RoleApprover)
+		if err != nil {
+			return err
+		}
+		if err := plan.Verify(roles, time.Now()); err != nil {
+			return &exitError{code: exitAuth, err: err}
+		}
+		printPlan(plan)
+		if !assumeYes {
+			fmt.Printf("Type the plan ID to approve it: ")
+			line, _ := bufio.NewReader(confirmIn).ReadString('\n')
+			if strings.TrimSpace(line) != plan.ID {
+				return invalidf("plan %s not approved", plan.ID)
+			}
+		}
+		if err := plan.Approve(key, roles, time.Now()); err != nil {
+			return &exitError{code: exitAuth, err: err}
+		}
+		if err := plan.Save(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Approved plan %s as %s (%d of %d approval(s))\n", plan.ID, key.Operator, len(plan.Approvals), roles.Required())
+		return nil
+	},
+}
+
+var executeCmd = &cobra.Command{
+	Use:   "execute PLAN",
+	Short: "Run an approved plan",
+	Long: `Run the command recorded in an approved plan. The plan must carry valid
+signatures and enough approvals, must not have expired, the files it read must be
+unchanged and the command must resolve to the same hosts as when it was approved.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		plan, err := approval.Load(args[0])
+		if err != nil {
+			return invalid(err)
+		}
+		_, roles, err := approvalIdentity(approval.RoleOperator)
+		if err != nil {
+			return err
+		}
+		if err := plan.Approved(roles, time.Now()); err != nil {
+			return &exitError{code: exitAuth, err: err}
+		}
+		if err := plan.CheckInputs(); err != nil {
+			return invalid(err)
+		}
+		var by []string
+		for _, a := range plan.Approvals {
+			by = append(by, a.By)
+		}
+		fmt.Printf("Executing plan %s approved by %s: %s\n", plan.ID, strings.Join(by, ", "), strings.Join(redact.Strings(plan.Args), " "))
+
+		executing = plan
+		rootCmd.SetArgs(plan.Args)
+		rootCmd.SilenceErrors, rootCmd.SilenceUsage = true, true // Execute reports the error
+		defer func() {
+			executing = nil
+			rootCmd.SetArgs(nil)
+			rootCmd.SilenceErrors, rootCmd.SilenceUsage = false, false
+		}()
+		return rootCmd.ExecuteContext(cmd.Context())
+	},
+}
+
+func printPlan(p *approval.Plan) {
+	fmt.Printf("Plan %s: %s on %d host(s)\n", p.ID, p.Operation, len(p.Hosts))
+	fmt.Printf("  created by %s at %s, expires %s\n", p.CreatedBy, p.Created.Format(time.RFC3339), p.Expires.Format(time.RFC3339))
+	fmt.Printf("  command: %s\n", strings.Join(redact.Strings(p.Args), " "))
+	fmt.Printf("  hosts: %s\n", strings.Join(p.Hosts, ", "))
+	for _, in := range p.Inputs {
+		fmt.Printf("  input: %s (sha256 %s)\n", in.Path, in.SHA256)
+	}
+	for _, a := range p.Approvals {
+		fmt.Printf("  approved by %s at %s\n", a.By, a.At.Format(time.RFC3339))
+	}
+}
+
+var keygenCmd = &cobra.Command{
+	Use:   "keygen NAME",
+	Short: "Create an operator signing key for approvals",
+	Long: `Write a new ed25519 signing key for operator NAME to --out and print the roles
+file entry holding its public key.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if keygenOut == "" {
+			return invalidf("--out is required")
+		}
+		pub, err := approval.Generate(args[0], keygenOut)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s. Add the operator to the roles file:\n", keygenOut)
+		fmt.Printf("  - name: %s\n    key: %s\n    roles: [operator, approver]\n", args[0], pub)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&requireApproval, "require-approval", false, "write destructive operations (firmware, firmware apply, apply, power off|soft|cycle|reset, slot power off, restore, powercap apply, bmc ssh-keys) to a signed plan for approval instead of running them (default $BOOTSTRAP_REQUIRE_APPROVAL)")
+	rootCmd.PersistentFlags().StringVar(&approvalFile, "approval-file", "", "plan file written by --require-approval (default plan-<id>.json)")
+	rootCmd.PersistentFlags().StringVar(&operatorKeyFile, "operator-key", "", "your operator signing key from keygen (default $BOOTSTRAP_OPERATOR_KEY)")
+	rootCmd.PersistentFlags().StringVar(&rolesFile, "roles", "", "roles file listing operators, their public keys and roles (default $BOOTSTRAP_ROLES)")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "run destructive operations without typing the number of hosts to confirm, and approve plans without typing their ID")
+	keygenCmd.Flags().StringVarP(&keygenOut, "out", "o", "", "file to write the private key to (must not exist)")
+	rootCmd.AddCommand(approveCmd, executeCmd, keygenCmd)
+}
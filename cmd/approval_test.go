@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bootstrap/internal/approval"
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+
+	"github.com/spf13/pflag"
+)
+
+// runRoot runs the CLI with args and returns the exit code.
+func runRoot(t *testing.T, args ...string) int {
+	t.Helper()
+	rootCmd.SetArgs(args)
+	defer rootCmd.SetArgs(nil)
+	rootCmd.SilenceErrors, rootCmd.SilenceUsage = true, true
+	defer func() { rootCmd.SilenceErrors, rootCmd.SilenceUsage = false, false }()
+	return exitCode(rootCmd.ExecuteContext(context.Background()))
+}
+
+func TestTwoPersonApproval(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	yes := assumeYes
+	t.Cleanup(func() {
+		requireApproval, approvalFile, operatorKeyFile, rolesFile, assumeYes = false, "", "", "", yes
+		pwHostsFile = ""
+		for _, fs := range []*pflag.FlagSet{rootCmd.PersistentFlags(), powerCmd.Flags(), approveCmd.Flags()} {
+			fs.VisitAll(func(f *pflag.Flag) { f.Changed = false })
+		}
+	})
+	var resets []string
+	bmc := func(host string) *redfishtest.MockClient {
+		return &redfishtest.MockClient{
+			GetSystemsFunc: func(context.Context) ([]redfish.System, error) {
+				var s redfish.System
+				s.ID = "Node0"
+				return []redfish.System{s}, nil
+			},
+			ResetSystemFunc: func(_ context.Context, _, rt string) error {
+				resets = append(resets, host+" "+rt)
+				return nil
+			},
+		}
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"a": bmc("a"), "b": bmc("b")})
+
+	dir := t.TempDir()
+	roles := "operators:\n"
+	for _, name := range []string{"alice", "bob"} {
+		pub, err := approval.Generate(name, filepath.Join(dir, name+".key"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		roles += "  - {name: " + name + ", key: " + pub + ", roles: [operator, approver]}\n"
+	}
+	rolesPath := filepath.Join(dir, "roles.yaml")
+	hostsPath := filepath.Join(dir, "hosts.txt")
+	planPath := filepath.Join(dir, "plan.json")
+	for path, content := range map[string]string{rolesPath: roles, hostsPath: "a\nb\n"} {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	alice := []string{"--roles", rolesPath, "--operator-key", filepath.Join(dir, "alice.key")}
+	bob := []string{"--roles", rolesPath, "--operator-key", filepath.Join(dir, "bob.key")}
+
+	// Alice's power off only records a plan
+	if code := runRoot(t, append(alice, "power", "off", "--hosts-file", hostsPath, "--require-approval", "--approval-file", planPath)...); code != exitOK {
+		t.Fatalf("plan: exit %d", code)
+	}
+	if len(resets) != 0 {
+		t.Fatalf("power off ran before approval: %v", resets)
+	}
+	plan, err := approval.Load(planPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(plan.Args, " "); got != "power --hosts-file="+hostsPath+" off" || strings.Join(plan.Hosts, ",") != "a,b" {
+		t.Fatalf("plan args %q, hosts %v", got, plan.Hosts)
+	}
+
+	// Nobody can execute it yet, and alice cannot approve her own plan
+	if code := runRoot(t, append(alice, "execute", planPath)...); code != exitAuth {
+		t.Errorf("unapproved execute: exit %d, want %d", code, exitAuth)
+	}
+	if code := runRoot(t, append(alice, "approve", "--yes", planPath)...); code != exitAuth {
+		t.Errorf("self-approval: exit %d, want %d", code, exitAuth)
+	}
+	if code := runRoot(t, append(bob, "approve", "--yes", planPath)...); code != exitOK {
+		t.Fatalf("approve: exit %d", code)
+	}
+
+	// Changing the hosts file after approval blocks the run
+	if err := os.WriteFile(hostsPath, []byte("a\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if code := runRoot(t, append(alice, "execute", planPath)...); code != exitInvalid || len(resets) != 0 {
+		t.Errorf("changed input: exit %d, resets %v", code, resets)
+	}
+	if err := os.WriteFile(hostsPath, []byte("a\nb\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if code := runRoot(t, append(alice, "execute", planPath)...); code != exitOK {
+		t.Fatalf("execute: exit %d", code)
+	}
+	if strings.Join(resets, ",") != "a ForceOff,b ForceOff" && strings.Join(resets, ",") != "b ForceOff,a ForceOff" {
+		t.Errorf("resets = %v", resets)
+	}
+}
@@ -0,0 +1,288 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"bootstrap/internal/sshkeys"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	bmcFile      string
+	bmcHostsCSV  string
+	bmcHostsFile string
+	bmcInsecure  bool
+	bmcTimeout   time.Duration
+	bmcBatchSize int
+	bmcDryRun    bool
+
+	sshKeyFiles []string
+	sshAppend   bool
+	sshVerify   bool
+	sshExact    bool
+)
+
+var bmcCmd = &cobra.Command{
+	Use:   "bmc",
+	Short: "Configure BMC settings via Redfish",
+}
+
+var bmcSSHKeysCmd = &cobra.Command{
+	Use:   "ssh-keys",
+	Short: "Set SSH authorized keys on BMCs",
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if bmcFile == "" && bmcHostsCSV == "" && bmcHostsFile == "" {
+			return invalidf("at least one of --file, --hosts or --hosts-file is required")
+		}
+		if len(sshKeyFiles) == 0 {
+			return invalidf("--ssh-pubkey is required")
+		}
+		keys, err := sshkeys.ReadFiles(sshKeyFiles)
+		if err != nil {
+			return invalidf("read ssh pubkey: %w", err)
+		}
+		if len(keys) == 0 {
+			return invalidf("no keys found in %s", strings.Join(sshKeyFiles, ", "))
+		}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+		hosts, err := resolveHosts(bmcFile, bmcHostsCSV, bmcHostsFile)
+		if err != nil {
+			return err
+		}
+
+		mode := "replace"
+		if sshAppend {
+			mode = "append"
+		}
+		if bmcDryRun {
+			for _, h := range hosts {
+				fmt.Printf("[dry-run] would %s %d SSH key(s) on %s\n", mode, len(keys), hostName(h))
+			}
+			return nil
+		}
+		if stop, err := needsApproval(cmd, args, "bmc ssh-keys", hosts); stop || err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		opts := sshKeyOptions{Insecure: bmcInsecure, Timeout: bmcTimeout, BatchSize: bmcBatchSize, Append: sshAppend, Verify: sshVerify}
+		results := provisionSSHKeys(ctx, hosts, user, pass, keys, opts)
+		printSSHKeySummary(results)
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+		return sshKeyFailures(len(hosts), results)
+	},
+}
+
+var bmcSSHKeysVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check that BMCs currently have the expected SSH authorized keys",
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if bmcFile == "" && bmcHostsCSV == "" && bmcHostsFile == "" {
+			return invalidf("at least one of --file, --hosts or --hosts-file is required")
+		}
+		if len(sshKeyFiles) == 0 {
+			return invalidf("--ssh-pubkey is required")
+		}
+		keys, err := sshkeys.ReadFiles(sshKeyFiles)
+		if err != nil {
+			return invalidf("read ssh pubkey: %w", err)
+		}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+		hosts, err := resolveHosts(bmcFile, bmcHostsCSV, bmcHostsFile)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		var mu sync.Mutex
+		failed := map[string]error{}
+		var drifted, inSync, aborted []string
+		forEachHost(ctx, hosts, bmcBatchSize, func(ctx context.Context, h string) {
+			if bmcTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, bmcTimeout)
+				defer cancel()
+			}
+			current, err := newRedfishClient(h, user, pass, bmcInsecure, bmcTimeout).GetAuthorizedKeys(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s: read authorized keys: %v\n", hostName(h), err)
+				failed[h] = err
+				return
+			}
+			have := sshkeys.Parse(current)
+			missing := sshkeys.Missing(have, keys)
+			extra := sshkeys.Missing(keys, have)
+			if len(missing) == 0 && (!sshExact || len(extra) == 0) {
+				inSync = append(inSync, h)
+				return
+			}
+			drifted = append(drifted, h)
+			fmt.Printf("DRIFT: %s: %d missing, %d unexpected key(s)\n", hostName(h), len(missing), len(extra))
+			for _, k := range missing {
+				fmt.Printf("    - %s\n", k)
+			}
+			if sshExact {
+				for _, k := range extra {
+					fmt.Printf("    + %s\n", k)
+				}
+			}
+			failed[h] = fmt.Errorf("authorized keys drift")
+		}, func(h string) {
+			mu.Lock()
+			defer mu.Unlock()
+			aborted = append(aborted, h)
+		})
+
+		sort.Strings(inSync)
+		sort.Strings(drifted)
+		fmt.Println("SSH key verification summary:")
+		fmt.Printf("  in sync: %d\n", len(inSync))
+		fmt.Printf("  drifted: %d\n", len(drifted))
+		fmt.Printf("  errors: %d\n", len(failed)-len(drifted))
+		if ctx.Err() != nil {
+			fmt.Fprintf(os.Stderr, "Interrupted: %d host(s) not checked\n", len(aborted))
+			return errInterrupted
+		}
+		return hostFailures(len(hosts), failed)
+	},
+}
+
+// SSH key provisioning outcomes
+const (
+	sshUpdated   = "updated"
+	sshUnchanged = "unchanged"
+	sshFailed    = "failed"
+	sshAborted   = "aborted"
+)
+
+// sshKeyOptions controls how provisionSSHKeys talks to BMCs.
+type sshKeyOptions struct {
+	Insecure  bool
+	Timeout   time.Duration
+	BatchSize int
+	Append    bool
+	Verify    bool
+}
+
+type sshKeyResult struct {
+	Host   string
+	Status string
+	Err    error
+}
+
+// provisionSSHKeys sets (or, with Append, adds) keys on each host concurrently and,
+// with Verify, reads them back to confirm the BMC applied them.
+func provisionSSHKeys(ctx context.Context, hosts []string, user, pass string, keys []string, opts sshKeyOptions) []sshKeyResult {
+	var mu sync.Mutex
+	var results []sshKeyResult
+	record := func(r sshKeyResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Status == sshFailed {
+			fmt.Fprintf(os.Stderr, "WARN: %s: set authorized keys: %v\n", hostName(r.Host), r.Err)
+		}
+		results = append(results, r)
+	}
+	forEachHost(ctx, hosts, opts.BatchSize, func(ctx context.Context, h string) {
+		record(setHostSSHKeys(ctx, h, user, pass, keys, opts))
+	}, func(h string) {
+		record(sshKeyResult{Host: h, Status: sshAborted})
+	})
+	sort.Slice(results, func(i, j int) bool { return results[i].Host < results[j].Host })
+	return results
+}
+
+func setHostSSHKeys(ctx context.Context, host, user, pass string, keys []string, opts sshKeyOptions) sshKeyResult {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	rf := withLedger(newRedfishClient(host, user, pass, opts.Insecure, opts.Timeout), host)
+	desired := keys
+	if opts.Append {
+		current, err := rf.GetAuthorizedKeys(ctx)
+		if err != nil {
+			return sshKeyResult{Host: host, Status: sshFailed, Err: fmt.Errorf("read current keys: %w", err)}
+		}
+		have := sshkeys.Parse(current)
+		if len(sshkeys.Missing(have, keys)) == 0 {
+			return sshKeyResult{Host: host, Status: sshUnchanged}
+		}
+		desired = sshkeys.Merge(have, keys)
+	}
+	if err := rf.SetAuthorizedKeys(ctx, sshkeys.Join(desired)); err != nil {
+		return sshKeyResult{Host: host, Status: sshFailed, Err: err}
+	}
+	if opts.Verify {
+		current, err := rf.GetAuthorizedKeys(ctx)
+		if err != nil {
+			return sshKeyResult{Host: host, Status: sshFailed, Err: fmt.Errorf("verify: %w", err)}
+		}
+		if missing := sshkeys.Missing(sshkeys.Parse(current), desired); len(missing) > 0 {
+			return sshKeyResult{Host: host, Status: sshFailed, Err: fmt.Errorf("verify: %d key(s) not present after update", len(missing))}
+		}
+	}
+	return sshKeyResult{Host: host, Status: sshUpdated}
+}
+
+func printSSHKeySummary(results []sshKeyResult) {
+	byStatus := map[string][]string{}
+	for _, r := range results {
+		byStatus[r.Status] = append(byStatus[r.Status], r.Host)
+	}
+	fmt.Println("SSH key summary:")
+	for _, st := range []string{sshUpdated, sshUnchanged, sshFailed, sshAborted} {
+		if hs := byStatus[st]; len(hs) > 0 {
+			fmt.Printf("  %s (%d): %s\n", st, len(hs), strings.Join(hs, ", "))
+		}
+	}
+}
+
+func sshKeyFailures(total int, results []sshKeyResult) error {
+	failed := map[string]error{}
+	for _, r := range results {
+		if r.Status == sshFailed {
+			failed[r.Host] = r.Err
+		}
+	}
+	return hostFailures(total, failed)
+}
+
+func init() {
+	rootCmd.AddCommand(bmcCmd)
+	bmcCmd.AddCommand(bmcSSHKeysCmd)
+	bmcSSHKeysCmd.AddCommand(bmcSSHKeysVerifyCmd)
+	bmcCmd.PersistentFlags().StringVarP(&bmcFile, "file", "f", "", "Inventory file to read bmcs[] from when --hosts is not provided")
+	bmcCmd.PersistentFlags().StringVar(&bmcHostsCSV, "hosts", "", "Comma-separated list of BMC hosts to target (overrides --file)")
+	bmcCmd.PersistentFlags().StringVar(&bmcHostsFile, "hosts-file", "", "file listing BMC hosts or bmcs[] xnames to target, one per line; '#' starts a comment (overrides --file)")
+	bmcCmd.PersistentFlags().BoolVar(&bmcInsecure, "insecure", true, "allow insecure TLS to BMCs")
+	bmcCmd.PersistentFlags().DurationVar(&bmcTimeout, "timeout", 30*time.Second, "per-BMC request timeout")
+	bmcCmd.PersistentFlags().IntVar(&bmcBatchSize, "batch-size", 10, "number of BMCs to configure concurrently (0 or 1 = serial)")
+	bmcCmd.PersistentFlags().BoolVar(&bmcDryRun, "dry-run", false, "plan only: print actions without contacting BMCs")
+	bmcSSHKeysCmd.PersistentFlags().StringSliceVar(&sshKeyFiles, "ssh-pubkey", nil, "SSH public key file(s) to install (repeatable)")
+	bmcSSHKeysCmd.Flags().BoolVar(&sshAppend, "append", false, "add keys to those already on the BMC instead of replacing them")
+	bmcSSHKeysVerifyCmd.Flags().BoolVar(&sshExact, "exact", false, "also report keys on the BMC that are not in --ssh-pubkey as drift")
+	bmcSSHKeysCmd.Flags().BoolVar(&sshVerify, "verify", true, "read keys back after setting them and fail if they are missing")
+}
@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"bootstrap/internal/certaudit"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	bcFile      string
+	bcHostsCSV  string
+	bcHostsFile string
+	bcPort      int
+	bcWarnDays  int
+	bcCAFile    string
+	bcTimeout   time.Duration
+	bcBatchSize int
+	bcFormat    string
+)
+
+var bmcCertsCmd = &cobra.Command{
+	Use:   "bmc-certs",
+	Short: "Inspect the TLS certificates BMCs serve",
+}
+
+var bmcCertsAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Report BMC certificates that expire soon, are self-signed or not issued by the site CA",
+	Long: `Connect to the HTTPS port of every BMC and record the certificate it serves:
+subject, issuer, validity, self-signed status and SHA-256 fingerprint. A
+certificate is flagged when it has expired or expires within --warn-days, is
+self-signed, or, with --ca-file, does not chain to the site CA (through the
+intermediates the BMC sends).
+
+The command exits 2 when any certificate is flagged, so it can run from cron or
+CI. Connections go through --proxy and --ssh-jump like Redfish requests.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if bcFormat != "" && bcFormat != "json" && bcFormat != "csv" {
+			return invalidf("--format must be json or csv when set")
+		}
+		if bcFile == "" && bcHostsCSV == "" && bcHostsFile == "" {
+			return invalidf("at least one of --file, --hosts or --hosts-file is required")
+		}
+		if bcWarnDays < 0 {
+			return invalidf("--warn-days must not be negative")
+		}
+		var pool *x509.CertPool
+		if bcCAFile != "" {
+			var err error
+			if pool, err = certaudit.LoadCA(bcCAFile); err != nil {
+				return invalidf("--ca-file: %w", err)
+			}
+		}
+		hosts, err := resolveHosts(bcFile, bcHostsCSV, bcHostsFile)
+		if err != nil {
+			return err
+		}
+		bmcs, err := loadBMCs(bcFile)
+		if err != nil {
+			return err
+		}
+		xnames := hostXnames(hosts, bmcs)
+
+		ctx := cmd.Context()
+		now := time.Now()
+		warn := time.Duration(bcWarnDays) * 24 * time.Hour
+		var mu sync.Mutex
+		var certs []certaudit.Cert
+		failed := map[string]error{}
+		forEachHost(ctx, hosts, bcBatchSize, func(ctx context.Context, h string) {
+			ctx, cancel := context.WithTimeout(ctx, bcTimeout)
+			defer cancel()
+			chain, err := certaudit.Fetch(ctx, bmcDial, bmcTLSAddr(h))
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", hostName(h), err)
+				failed[h] = err
+				return
+			}
+			c := certaudit.Check(chain, pool, now, warn)
+			c.Host, c.Xname = h, xnames[h]
+			certs = append(certs, c)
+		}, func(string) {})
+
+		hostIdx := map[string]int{}
+		for i, h := range hosts {
+			hostIdx[h] = i
+		}
+		slices.SortFunc(certs, func(a, b certaudit.Cert) int { return cmp.Compare(hostIdx[a.Host], hostIdx[b.Host]) })
+		if err := printCertAudit(certs, len(failed)); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+		if err := hostFailures(len(hosts), failed); err != nil {
+			return err
+		}
+		flagged := 0
+		for _, c := range certs {
+			if len(c.Problems) > 0 {
+				flagged++
+			}
+		}
+		if flagged > 0 {
+			return &exitError{code: exitPartial, err: fmt.Errorf("%d of %d certificate(s) flagged", flagged, len(certs))}
+		}
+		return nil
+	},
+}
+
+// bmcTLSAddr returns host with --port unless it names a port itself.
+func bmcTLSAddr(h string) string {
+	if _, _, err := net.SplitHostPort(h); err == nil {
+		return h
+	}
+	return net.JoinHostPort(h, strconv.Itoa(bcPort))
+}
+
+func printCertAudit(certs []certaudit.Cert, failures int) error {
+	switch bcFormat {
+	case "json":
+		return printJSON(certs)
+	case "csv":
+		var rows [][]string
+		for _, c := range certs {
+			siteCA := ""
+			if c.SiteCA != nil {
+				siteCA = strconv.FormatBool(*c.SiteCA)
+			}
+			rows = append(rows, []string{c.Host, c.Xname, c.Subject, c.Issuer, c.NotBefore.UTC().Format(time.RFC3339), c.NotAfter.UTC().Format(time.RFC3339),
+				strconv.Itoa(c.DaysLeft), strconv.FormatBool(c.SelfSigned), siteCA, c.SHA256, strings.Join(c.Problems, "; ")})
+		}
+		return writeCSV(os.Stdout, []string{"host", "xname", "subject", "issuer", "not_before", "not_after", "days_left", "self_signed", "site_ca", "sha256", "problems"}, rows)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "HOST\tSUBJECT\tISSUER\tEXPIRES\tDAYS\tPROBLEMS")
+	flagged := 0
+	for _, c := range certs {
+		if len(c.Problems) > 0 {
+			flagged++
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\n", hostName(c.Host), c.Subject, c.Issuer, c.NotAfter.UTC().Format("2006-01-02"),
+			c.DaysLeft, cmp.Or(strings.Join(c.Problems, "; "), "-"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Println("Certificate audit summary:")
+	fmt.Printf("  ok: %d\n", len(certs)-flagged)
+	fmt.Printf("  flagged: %d\n", flagged)
+	fmt.Printf("  errors: %d\n", failures)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(bmcCertsCmd)
+	bmcCertsCmd.AddCommand(bmcCertsAuditCmd)
+	bmcCertsAuditCmd.Flags().StringVarP(&bcFile, "file", "f", "", "inventory whose bmcs[] are audited")
+	bmcCertsAuditCmd.Flags().StringVar(&bcHostsCSV, "hosts", "", "comma-separated BMC hosts, xnames or aliases (overrides --hosts-file)")
+	bmcCertsAuditCmd.Flags().StringVar(&bcHostsFile, "hosts-file", "", "file listing BMC hosts or bmcs[] xnames, one per line ('#' starts a comment)")
+	bmcCertsAuditCmd.Flags().IntVar(&bcPort, "port", 443, "HTTPS port of the BMCs, for hosts without one")
+	bmcCertsAuditCmd.Flags().IntVar(&bcWarnDays, "warn-days", 30, "flag certificates expiring within this many days")
+	bmcCertsAuditCmd.Flags().StringVar(&bcCAFile, "ca-file", "", "PEM bundle of the site CA; certificates not issued by it are flagged")
+	bmcCertsAuditCmd.Flags().DurationVar(&bcTimeout, "timeout", 10*time.Second, "time limit for reading one BMC's certificate")
+	bmcCertsAuditCmd.Flags().IntVar(&bcBatchSize, "batch-size", 20, "number of BMCs to contact concurrently (0 or 1 = serial)")
+	bmcCertsAuditCmd.Flags().StringVar(&bcFormat, "format", "", "output format: json or csv (default: table)")
+}
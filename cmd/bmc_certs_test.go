@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBMCCertsAudit(t *testing.T) {
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	defer ts.Close()
+	host := ts.Listener.Addr().String()
+	dir := t.TempDir()
+	ca := filepath.Join(dir, "site-ca.pem")
+	if err := os.WriteFile(ca, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { bcHostsCSV, bcCAFile, bcFormat, bcWarnDays = "", "", "", 30 }()
+	bcHostsCSV, bcFormat, bcBatchSize = host, "csv", 1
+	bmcCertsAuditCmd.SetContext(context.Background())
+
+	run := func() ([][]string, error) {
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := bmcCertsAuditCmd.RunE(bmcCertsAuditCmd, nil)
+		w.Close() //nolint:errcheck
+		os.Stdout = old
+		b, _ := io.ReadAll(r)
+		rows, cerr := csv.NewReader(strings.NewReader(string(b))).ReadAll()
+		if cerr != nil {
+			t.Fatalf("%v: %s", cerr, b)
+		}
+		return rows, err
+	}
+
+	// The test server's certificate is its own CA: issued by the site CA given
+	// it, but self-signed, so it is flagged
+	bcCAFile = ca
+	rows, err := run()
+	if got := exitCode(err); got != exitPartial {
+		t.Errorf("exit code %d, want %d (%v)", got, exitPartial, err)
+	}
+	if len(rows) != 2 || rows[1][0] != host || rows[1][7] != "true" || rows[1][8] != "true" || rows[1][10] != "self-signed" {
+		t.Errorf("rows = %v", rows)
+	}
+
+	// Without the site CA, and with a --warn-days beyond its expiry
+	bcCAFile, bcWarnDays = "", 50000
+	rows, _ = run()
+	if len(rows) != 2 || rows[1][8] != "" || !strings.HasPrefix(rows[1][10], "expires in ") {
+		t.Errorf("rows = %v", rows)
+	}
+
+	bcHostsCSV = "127.0.0.1:1"
+	if _, err := run(); exitCode(err) == 0 {
+		t.Error("unreachable BMC: want an error")
+	}
+}
@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"bootstrap/internal/render"
+	"bootstrap/pkg/inventory"
+	"bootstrap/pkg/redfish"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	bhTemplate string
+	bhDomain   string
+)
+
+var bmcHostnameCmd = &cobra.Command{
+	Use:   "hostname",
+	Short: "Set BMC host names (and FQDNs) derived from their xnames",
+	Long: `Set the HostName of every BMC's manager from --template, rendered with the
+BMC's bmcs[] entry of --file (.Xname, .Alias, .IP, .MAC) and .Domain. With
+--domain the FQDN <name>.<domain> is also set on the manager's Ethernet
+interfaces, so web UIs, certificates and syslog entries name the BMC instead of
+a vendor default. BMCs that already have the name are left alone.
+
+Every name is checked to be a valid DNS label before any BMC is contacted.`,
+	Example: `  ochami_bootstrap bmc hostname -f inventory.yaml --domain hsm.example.com
+  ochami_bootstrap bmc hostname -f inventory.yaml --template '{{.Xname}}-bmc'`,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if bmcFile == "" && bmcHostsCSV == "" && bmcHostsFile == "" {
+			return invalidf("at least one of --file, --hosts or --hosts-file is required")
+		}
+		tmpl, err := template.New("hostname").Funcs(render.Funcs).Option("missingkey=error").Parse(bhTemplate)
+		if err != nil {
+			return invalidf("--template: %w", err)
+		}
+		if bhDomain != "" && !validDomain(bhDomain) {
+			return invalidf("--domain %q is not a valid DNS domain", bhDomain)
+		}
+		hosts, err := resolveHosts(bmcFile, bmcHostsCSV, bmcHostsFile)
+		if err != nil {
+			return err
+		}
+		doc, err := loadInventory(bmcFile)
+		if err != nil {
+			return err
+		}
+		names, err := bmcHostNames(tmpl, hosts, doc.BMCs)
+		if err != nil {
+			return err
+		}
+		fqdn := func(name string) string {
+			if bhDomain == "" {
+				return ""
+			}
+			return name + "." + bhDomain
+		}
+		if bmcDryRun {
+			for _, h := range hosts {
+				if f := fqdn(names[h]); f != "" {
+					fmt.Printf("[dry-run] would set host name of %s to %s (%s)\n", hostName(h), names[h], f)
+				} else {
+					fmt.Printf("[dry-run] would set host name of %s to %s\n", hostName(h), names[h])
+				}
+			}
+			return nil
+		}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		var mu sync.Mutex
+		byStatus := map[string][]string{}
+		failed := map[string]error{}
+		forEachHost(ctx, hosts, bmcBatchSize, func(ctx context.Context, h string) {
+			if bmcTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, bmcTimeout)
+				defer cancel()
+			}
+			status, err := setBMCHostName(ctx, withLedger(newRedfishClient(h, user, pass, bmcInsecure, bmcTimeout), h), names[h], fqdn(names[h]))
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s: set host name: %v\n", hostName(h), err)
+				failed[h] = err
+			}
+			byStatus[status] = append(byStatus[status], hostName(h))
+		}, func(h string) {
+			mu.Lock()
+			defer mu.Unlock()
+			byStatus[sshAborted] = append(byStatus[sshAborted], hostName(h))
+		})
+
+		fmt.Println("Host name summary:")
+		for _, st := range []string{sshUpdated, sshUnchanged, sshFailed, sshAborted} {
+			if hs := byStatus[st]; len(hs) > 0 {
+				sort.Strings(hs)
+				fmt.Printf("  %s (%d): %s\n", st, len(hs), strings.Join(hs, ", "))
+			}
+		}
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+		return hostFailures(len(hosts), failed)
+	},
+}
+
+// setBMCHostName sets name (and fqdn, when set) on one BMC unless it already
+// has them, and returns the outcome: updated, unchanged or failed.
+func setBMCHostName(ctx context.Context, rf redfish.Client, name, fqdn string) (string, error) {
+	np, err := rf.GetNetworkProtocol(ctx)
+	if err != nil {
+		return sshFailed, fmt.Errorf("read NetworkProtocol: %w", err)
+	}
+	if strings.EqualFold(np.HostName, name) && (fqdn == "" || strings.EqualFold(np.FQDN, fqdn)) {
+		return sshUnchanged, nil
+	}
+	if err := rf.SetHostName(ctx, name, fqdn); err != nil {
+		return sshFailed, err
+	}
+	return sshUpdated, nil
+}
+
+// bmcHostNameData is what --template is rendered with.
+type bmcHostNameData struct {
+	inventory.Entry
+	Domain string
+}
+
+// bmcHostNames renders tmpl for every host and checks the results are valid
+// DNS labels. Hosts are matched to bmcs[] by IP or xname.
+func bmcHostNames(tmpl *template.Template, hosts []string, bmcs []inventory.Entry) (map[string]string, error) {
+	xnames := hostXnames(hosts, bmcs)
+	byXname := map[string]inventory.Entry{}
+	for _, b := range bmcs {
+		byXname[strings.ToLower(b.Xname)] = b
+	}
+	out := map[string]string{}
+	var bad []string
+	for _, h := range hosts {
+		e, ok := byXname[strings.ToLower(xnames[h])]
+		if !ok {
+			e = inventory.Entry{Xname: xnames[h], IP: h}
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, bmcHostNameData{Entry: e, Domain: bhDomain}); err != nil {
+			return nil, invalidf("--template for %s: %w", hostName(h), err)
+		}
+		name := strings.ToLower(strings.TrimSpace(buf.String()))
+		if !dnsLabel.MatchString(name) {
+			bad = append(bad, fmt.Sprintf("%s: %q", hostName(h), name))
+			continue
+		}
+		out[h] = name
+	}
+	if len(bad) > 0 {
+		return nil, invalidf("--template gives invalid host names (letters, digits and '-', at most 63 characters; hosts need a bmcs[] xname for .Xname):\n  %s", strings.Join(bad, "\n  "))
+	}
+	return out, nil
+}
+
+// dnsLabel matches one RFC 1123 host name label.
+var dnsLabel = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+func validDomain(domain string) bool {
+	if len(domain) > 253-64 {
+		return false
+	}
+	for _, l := range strings.Split(strings.ToLower(domain), ".") {
+		if !dnsLabel.MatchString(l) {
+			return false
+		}
+	}
+	return true
+}
+
+func init() {
+	bmcCmd.AddCommand(bmcHostnameCmd)
+	bmcHostnameCmd.Flags().StringVar(&bhTemplate, "template", "{{.Xname}}", "Go text/template for the host name, rendered with the BMC's bmcs[] entry (.Xname, .Alias, .IP, .MAC) and .Domain")
+	bmcHostnameCmd.Flags().StringVar(&bhDomain, "domain", "", "DNS domain; also set the FQDN <name>.<domain>")
+}
@@ -0,0 +1,276 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"bootstrap/pkg/redfish"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	syslogServers   []string
+	syslogClear     bool
+	syslogVerify    bool
+	syslogTestEvent bool
+)
+
+var bmcSyslogCmd = &cobra.Command{
+	Use:   "syslog",
+	Short: "Point BMC syslog forwarding at the site log servers",
+	Long: `Make every BMC forward its logs to exactly the --server targets (host,
+host:port, udp://host:port or tcp://host:port; port 514 by default). BMCs with
+the HPE OEM RemoteSyslog settings (iLO) are configured through them; others get
+standard EventService subscriptions with the SyslogUDP/SyslogTCP protocol, and
+syslog subscriptions to other servers are removed. --clear turns forwarding off.`,
+	Example: `  ochami_bootstrap bmc syslog -f inventory.yaml --server 10.0.0.5 --server tcp://logs.example.com:6514
+  ochami_bootstrap bmc syslog verify -f inventory.yaml --server 10.0.0.5`,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		targets, err := syslogTargets()
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 && !syslogClear {
+			return invalidf("--server is required (or --clear to turn forwarding off)")
+		}
+		if len(targets) > 0 && syslogClear {
+			return invalidf("--server and --clear are mutually exclusive")
+		}
+		hosts, err := resolveHosts(bmcFile, bmcHostsCSV, bmcHostsFile)
+		if err != nil {
+			return err
+		}
+		if bmcDryRun {
+			for _, h := range hosts {
+				if syslogClear {
+					fmt.Printf("[dry-run] would turn off syslog forwarding on %s\n", hostName(h))
+				} else {
+					fmt.Printf("[dry-run] would forward syslog of %s to %s\n", hostName(h), syslogList(targets))
+				}
+			}
+			return nil
+		}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		var mu sync.Mutex
+		byStatus := map[string][]string{}
+		failed := map[string]error{}
+		forEachHost(ctx, hosts, bmcBatchSize, func(ctx context.Context, h string) {
+			if bmcTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, bmcTimeout)
+				defer cancel()
+			}
+			status, err := setBMCSyslog(ctx, withLedger(newRedfishClient(h, user, pass, bmcInsecure, bmcTimeout), h), targets)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s: set syslog forwarding: %v\n", hostName(h), err)
+				failed[h] = err
+			}
+			byStatus[status] = append(byStatus[status], hostName(h))
+		}, func(h string) {
+			mu.Lock()
+			defer mu.Unlock()
+			byStatus[sshAborted] = append(byStatus[sshAborted], hostName(h))
+		})
+
+		fmt.Println("Syslog forwarding summary:")
+		for _, st := range []string{sshUpdated, sshUnchanged, sshFailed, sshAborted} {
+			if hs := byStatus[st]; len(hs) > 0 {
+				sort.Strings(hs)
+				fmt.Printf("  %s (%d): %s\n", st, len(hs), strings.Join(hs, ", "))
+			}
+		}
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+		return hostFailures(len(hosts), failed)
+	},
+}
+
+var bmcSyslogVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check BMC syslog forwarding and send a test event where supported",
+	Long: `Report every BMC whose syslog forwarding differs from --server. With
+--test-event (the default) each BMC that matches is asked to send a test event
+(iLO's SendTestSyslog, else EventService.SubmitTestEvent) so delivery can be
+confirmed in the log aggregator; BMCs without either action are listed but not
+counted as failures.`,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		targets, err := syslogTargets()
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			return invalidf("--server is required")
+		}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+		hosts, err := resolveHosts(bmcFile, bmcHostsCSV, bmcHostsFile)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		message := fmt.Sprintf("ochami_bootstrap syslog test at %s", time.Now().UTC().Format(time.RFC3339))
+		var mu sync.Mutex
+		failed := map[string]error{}
+		var inSync, drifted, tested, untested []string
+		forEachHost(ctx, hosts, bmcBatchSize, func(ctx context.Context, h string) {
+			if bmcTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, bmcTimeout)
+				defer cancel()
+			}
+			rf := newRedfishClient(h, user, pass, bmcInsecure, bmcTimeout)
+			cfg, err := rf.GetSyslog(ctx)
+			match := err == nil && sameSyslogTargets(cfg.Targets, targets)
+			var testErr error
+			if match && syslogTestEvent {
+				testErr = rf.SendSyslogTest(ctx, message)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", hostName(h), err)
+				failed[h] = err
+				return
+			case !match:
+				fmt.Printf("DRIFT: %s: forwards to %s (%s)\n", hostName(h), syslogList(cfg.Targets), cfg.Method)
+				drifted = append(drifted, hostName(h))
+				failed[h] = errors.New("syslog forwarding drift")
+				return
+			}
+			inSync = append(inSync, hostName(h))
+			switch {
+			case !syslogTestEvent:
+			case testErr == nil:
+				tested = append(tested, hostName(h))
+			case unsupported(testErr):
+				untested = append(untested, hostName(h))
+			default:
+				fmt.Fprintf(os.Stderr, "WARN: %s: send test event: %v\n", hostName(h), testErr)
+				failed[h] = fmt.Errorf("send test event: %w", testErr)
+			}
+		}, func(string) {})
+
+		fmt.Println("Syslog verification summary:")
+		fmt.Printf("  in sync: %d\n", len(inSync))
+		fmt.Printf("  drifted: %d\n", len(drifted))
+		fmt.Printf("  errors: %d\n", len(failed)-len(drifted))
+		if syslogTestEvent {
+			sort.Strings(tested)
+			sort.Strings(untested)
+			fmt.Printf("  test events sent (%d): %s\n", len(tested), strings.Join(tested, ", "))
+			if len(untested) > 0 {
+				fmt.Printf("  test events not supported (%d): %s\n", len(untested), strings.Join(untested, ", "))
+			}
+		}
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+		return hostFailures(len(hosts), failed)
+	},
+}
+
+// setBMCSyslog points one BMC at targets unless it already forwards to them,
+// and with --verify reads the settings back.
+func setBMCSyslog(ctx context.Context, rf redfish.Client, targets []redfish.SyslogTarget) (string, error) {
+	cfg, err := rf.GetSyslog(ctx)
+	if err != nil {
+		return sshFailed, fmt.Errorf("read: %w", err)
+	}
+	if sameSyslogTargets(cfg.Targets, targets) {
+		return sshUnchanged, nil
+	}
+	if err := rf.SetSyslog(ctx, targets); err != nil {
+		return sshFailed, err
+	}
+	if syslogVerify {
+		cfg, err := rf.GetSyslog(ctx)
+		if err != nil {
+			return sshFailed, fmt.Errorf("verify: %w", err)
+		}
+		if !sameSyslogTargets(cfg.Targets, targets) {
+			return sshFailed, fmt.Errorf("verify: BMC forwards to %s after update", syslogList(cfg.Targets))
+		}
+	}
+	return sshUpdated, nil
+}
+
+// syslogTargets parses --server.
+func syslogTargets() ([]redfish.SyslogTarget, error) {
+	if bmcFile == "" && bmcHostsCSV == "" && bmcHostsFile == "" {
+		return nil, invalidf("at least one of --file, --hosts or --hosts-file is required")
+	}
+	var out []redfish.SyslogTarget
+	for _, s := range syslogServers {
+		t, err := redfish.ParseSyslogTarget(s)
+		if err != nil {
+			return nil, invalid(err)
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+func sameSyslogTargets(a, b []redfish.SyslogTarget) bool {
+	as, bs := syslogStrings(a), syslogStrings(b)
+	slices.Sort(as)
+	slices.Sort(bs)
+	return slices.Equal(slices.Compact(as), slices.Compact(bs))
+}
+
+func syslogStrings(targets []redfish.SyslogTarget) []string {
+	out := make([]string, len(targets))
+	for i, t := range targets {
+		out[i] = t.String()
+	}
+	return out
+}
+
+func syslogList(targets []redfish.SyslogTarget) string {
+	if len(targets) == 0 {
+		return "nothing"
+	}
+	return strings.Join(syslogStrings(targets), ", ")
+}
+
+// unsupported reports whether err is a BMC saying it lacks the action.
+func unsupported(err error) bool {
+	var se *redfish.StatusError
+	if !errors.As(err, &se) {
+		return false
+	}
+	return se.StatusCode == http.StatusNotFound || se.StatusCode == http.StatusMethodNotAllowed || se.StatusCode == http.StatusNotImplemented
+}
+
+func init() {
+	bmcCmd.AddCommand(bmcSyslogCmd)
+	bmcSyslogCmd.AddCommand(bmcSyslogVerifyCmd)
+	bmcSyslogCmd.PersistentFlags().StringArrayVar(&syslogServers, "server", nil, "syslog server: host, host:port, udp://host:port or tcp://host:port (repeatable)")
+	bmcSyslogCmd.Flags().BoolVar(&syslogClear, "clear", false, "turn syslog forwarding off")
+	bmcSyslogCmd.Flags().BoolVar(&syslogVerify, "verify", true, "read the settings back after changing them and fail the host if they differ")
+	bmcSyslogVerifyCmd.Flags().BoolVar(&syslogTestEvent, "test-event", true, "ask BMCs that match to send a test event")
+}
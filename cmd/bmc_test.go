@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+)
+
+// mockSSHKeysServer emulates the OEM SSHAdmin.AuthorizedKeys property of a BMC.
+func mockSSHKeysServer(t *testing.T, initial string) (*httptest.Server, func() string) {
+	t.Helper()
+	var mu sync.Mutex
+	keys := initial
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/Managers/BMC/NetworkProtocol") {
+			http.NotFound(w, r)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPatch:
+			var body struct {
+				Oem struct {
+					SSHAdmin struct {
+						AuthorizedKeys string
+					}
+				}
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			keys = body.Oem.SSHAdmin.AuthorizedKeys
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+				"Oem": map[string]any{"SSHAdmin": map[string]any{"AuthorizedKeys": keys}},
+			})
+		}
+	})
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+	return server, func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		return keys
+	}
+}
+
+func TestBMCSSHKeysAppend(t *testing.T) {
+	server, current := mockSSHKeysServer(t, "ssh-rsa OLD admin\n")
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+
+	keyFile := filepath.Join(t.TempDir(), "id.pub")
+	if err := os.WriteFile(keyFile, []byte("ssh-ed25519 NEW ops\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	host := strings.TrimPrefix(server.URL, "https://")
+	bmcFile = ""
+	bmcHostsCSV = host
+	bmcInsecure = true
+	bmcTimeout = 2 * time.Second
+	bmcBatchSize = 2
+	bmcDryRun = false
+	sshKeyFiles = []string{keyFile}
+	sshAppend = true
+	sshVerify = true
+
+	cmd := bmcSSHKeysCmd
+	cmd.SetContext(context.Background())
+	if err := cmd.RunE(cmd, []string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := current(); got != "ssh-rsa OLD admin\nssh-ed25519 NEW ops\n" {
+		t.Fatalf("unexpected keys on BMC: %q", got)
+	}
+
+	// Second run is a no-op because the key is already present
+	if err := cmd.RunE(cmd, []string{}); err != nil {
+		t.Fatalf("unexpected error on rerun: %v", err)
+	}
+
+	// Replace mode drops the old key, once confirmed
+	sshAppend = false
+	if code := unconfirmed(func() error { return cmd.RunE(cmd, []string{}) }); code != exitInvalid || current() != "ssh-rsa OLD admin\nssh-ed25519 NEW ops\n" {
+		t.Fatalf("unconfirmed replace: exit code %d, keys %q", code, current())
+	}
+	if err := cmd.RunE(cmd, []string{}); err != nil {
+		t.Fatalf("unexpected error on replace: %v", err)
+	}
+	if got := current(); got != "ssh-ed25519 NEW ops\n" {
+		t.Fatalf("unexpected keys after replace: %q", got)
+	}
+}
+
+func TestBMCSSHKeysVerify(t *testing.T) {
+	server, _ := mockSSHKeysServer(t, "ssh-rsa OLD admin\nssh-ed25519 NEW ops\n")
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+
+	keyFile := filepath.Join(t.TempDir(), "id.pub")
+	if err := os.WriteFile(keyFile, []byte("ssh-ed25519 NEW ops\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bmcFile = ""
+	bmcHostsCSV = strings.TrimPrefix(server.URL, "https://")
+	bmcInsecure = true
+	bmcTimeout = 2 * time.Second
+	bmcBatchSize = 1
+	sshKeyFiles = []string{keyFile}
+
+	cmd := bmcSSHKeysVerifyCmd
+	cmd.SetContext(context.Background())
+	sshExact = false
+	if err := cmd.RunE(cmd, []string{}); err != nil {
+		t.Fatalf("expected keys to verify, got %v", err)
+	}
+	sshExact = true
+	if err := cmd.RunE(cmd, []string{}); exitCode(err) != exitPartial {
+		t.Fatalf("expected drift with --exact, got %v", err)
+	}
+	sshExact = false
+}
+
+func TestBMCHostname(t *testing.T) {
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+	inv := filepath.Join(t.TempDir(), "inventory.yaml")
+	doc := "bmcs:\n  - xname: x1000c0s0b0\n    ip: 10.1.0.1\n  - xname: x1000c0s1b0\n    ip: 10.1.0.2\n"
+	if err := os.WriteFile(inv, []byte(doc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var set []string
+	named := &redfishtest.MockClient{
+		GetNetworkProtocolFunc: func(context.Context) (redfish.NetworkProtocol, error) {
+			return redfish.NetworkProtocol{HostName: "x1000c0s0b0-bmc", FQDN: "x1000c0s0b0-bmc.hsm.local"}, nil
+		},
+	}
+	vendor := &redfishtest.MockClient{
+		GetNetworkProtocolFunc: func(context.Context) (redfish.NetworkProtocol, error) {
+			return redfish.NetworkProtocol{HostName: "AMI0123456789"}, nil
+		},
+		SetHostNameFunc: func(_ context.Context, name, fqdn string) error {
+			set = append(set, name+" "+fqdn)
+			return nil
+		},
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"10.1.0.1": named, "10.1.0.2": vendor})
+	bmcFile, bmcHostsCSV, bmcHostsFile = inv, "", ""
+	bmcBatchSize, bmcTimeout, bmcDryRun = 1, time.Second, false
+	bhTemplate, bhDomain = "{{.Xname}}-bmc", "hsm.local"
+	t.Cleanup(func() { bmcFile, bhTemplate, bhDomain = "", "{{.Xname}}", "" })
+
+	bmcHostnameCmd.SetContext(context.Background())
+	if err := bmcHostnameCmd.RunE(bmcHostnameCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(set) != 1 || set[0] != "x1000c0s1b0-bmc x1000c0s1b0-bmc.hsm.local" {
+		t.Errorf("SetHostName calls = %q", set)
+	}
+	if len(named.Calls) != 1 {
+		t.Errorf("named BMC calls = %v, want only a read", named.Calls)
+	}
+
+	// Invalid names are refused before any BMC is contacted
+	set = nil
+	bhTemplate = "{{.Xname}}_bmc"
+	if err := bmcHostnameCmd.RunE(bmcHostnameCmd, nil); exitCode(err) != exitInvalid || !strings.Contains(err.Error(), `"x1000c0s0b0_bmc"`) {
+		t.Errorf("invalid name: err = %v", err)
+	}
+	if len(set) != 0 {
+		t.Errorf("BMCs contacted with an invalid template: %q", set)
+	}
+}
+
+func TestBMCSyslog(t *testing.T) {
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+	want := []redfish.SyslogTarget{{Host: "10.0.0.5", Port: 514, Protocol: "udp"}}
+	configured := &redfishtest.MockClient{
+		GetSyslogFunc: func(context.Context) (redfish.SyslogConfig, error) {
+			return redfish.SyslogConfig{Method: redfish.SyslogHPE, Targets: want}, nil
+		},
+		SendSyslogTestFunc: func(context.Context, string) error { return nil },
+	}
+	var current []redfish.SyslogTarget
+	fresh := &redfishtest.MockClient{
+		GetSyslogFunc: func(context.Context) (redfish.SyslogConfig, error) {
+			return redfish.SyslogConfig{Method: redfish.SyslogEventService, Targets: current}, nil
+		},
+		SetSyslogFunc: func(_ context.Context, targets []redfish.SyslogTarget) error {
+			current = targets
+			return nil
+		},
+		SendSyslogTestFunc: func(context.Context, string) error {
+			return &redfish.StatusError{Method: "POST", StatusCode: 405, Status: "405 Method Not Allowed"}
+		},
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"a": configured, "b": fresh})
+	bmcFile, bmcHostsCSV, bmcHostsFile = "", "a,b", ""
+	bmcBatchSize, bmcTimeout, bmcDryRun = 1, time.Second, false
+	syslogServers, syslogVerify, syslogTestEvent = []string{"10.0.0.5"}, true, true
+	t.Cleanup(func() { bmcHostsCSV, syslogServers = "", nil })
+	ctx := context.Background()
+
+	// Before the change b drifts
+	bmcSyslogVerifyCmd.SetContext(ctx)
+	if err := bmcSyslogVerifyCmd.RunE(bmcSyslogVerifyCmd, nil); exitCode(err) != exitPartial {
+		t.Fatalf("verify before: err = %v, want partial failure", err)
+	}
+	bmcSyslogCmd.SetContext(ctx)
+	if err := bmcSyslogCmd.RunE(bmcSyslogCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(current) != 1 || current[0] != want[0] {
+		t.Errorf("b forwards to %+v", current)
+	}
+	if slices.Contains(configured.Calls, "SetSyslog") {
+		t.Error("a was already configured but SetSyslog was called")
+	}
+	// An unsupported test event is not a failure
+	if err := bmcSyslogVerifyCmd.RunE(bmcSyslogVerifyCmd, nil); err != nil {
+		t.Errorf("verify after: %v", err)
+	}
+}
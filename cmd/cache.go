@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"bootstrap/pkg/redfish"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	cacheTTL time.Duration
+	cacheDir string
+)
+
+// addCacheFlags registers --cache-ttl and --cache-dir on a read-only command.
+func addCacheFlags(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(&cacheTTL, "cache-ttl", 0, "reuse FirmwareInventory/UpdateService responses cached on disk for this long (0 = disabled)")
+	cmd.Flags().StringVar(&cacheDir, "cache-dir", "", "directory for the response cache (default: user cache dir)")
+}
+
+// useResponseCache turns on the response cache for the run of a read-only
+// command and returns a function turning it off, so commands that change BMCs
+// never act on cached state.
+func useResponseCache() (func(), error) {
+	if err := redfish.EnableCache(cacheDir, cacheTTL); err != nil {
+		return nil, fmt.Errorf("response cache: %w", err)
+	}
+	return func() { redfish.EnableCache("", 0) }, nil //nolint:errcheck
+}
@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCacheFlags(t *testing.T) {
+	// Read-only commands take the response cache; commands changing BMCs do not
+	for _, c := range []struct {
+		name   string
+		cached bool
+	}{
+		{"firmware status", true}, {"firmware plan", true}, {"firmware targets", true}, {"diff", true},
+		{"firmware", false}, {"firmware apply", false}, {"apply", false},
+	} {
+		cmd, _, err := rootCmd.Find(strings.Fields(c.name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := cmd.Flags().Lookup("cache-ttl") != nil; got != c.cached {
+			t.Errorf("%s: --cache-ttl registered = %v, want %v", c.name, got, c.cached)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"bootstrap/internal/cloudinit"
+	"bootstrap/internal/render"
+	"bootstrap/internal/sshkeys"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	ciFile        string
+	ciNodes       string
+	ciKeys        []string
+	ciOutDir      string
+	ciTemplateDir []string
+)
+
+var cloudInitCmd = &cobra.Command{
+	Use:   "cloud-init",
+	Short: "Write per-node cloud-init data that installs SSH authorized keys",
+	Long: `Write cloud-init NoCloud data for every nodes[] entry of --file (or those named
+by --nodes): <output-dir>/<xname>/meta-data with the xname as instance-id and
+the alias (or xname) as host name, and <output-dir>/<xname>/user-data that adds
+the keys of --ssh-pubkey to the default user's authorized_keys.
+
+Both come from the cloud-init-meta-data and cloud-init-user-data templates
+(see render --list); put files of those names in a --template-dir to change
+them, e.g. to add packages or mounts.
+
+Serve the directory from the cloud-init data source the nodes boot with, or
+merge the user-data into the node groups of an existing cloud-init server.`,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if ciFile == "" {
+			return invalidf("--file is required")
+		}
+		if ciOutDir == "" {
+			return invalidf("--output-dir is required")
+		}
+		if len(ciKeys) == 0 {
+			return invalidf("--ssh-pubkey is required")
+		}
+		keys, err := sshkeys.ReadFiles(ciKeys)
+		if err != nil {
+			return invalidf("read ssh pubkey: %w", err)
+		}
+		if len(keys) == 0 {
+			return invalidf("no keys found in %s", strings.Join(ciKeys, ", "))
+		}
+		doc, err := readInventory(ciFile)
+		if err != nil {
+			return err
+		}
+		nodes, err := nodeTargets(doc, ciFile, ciNodes, false)
+		if err != nil {
+			return err
+		}
+		engine := render.New(ciTemplateDir...)
+		rctx := render.NewContext(doc)
+		rctx.Keys = keys
+		for _, n := range nodes {
+			rctx.Node = &n
+			var meta, user bytes.Buffer
+			if err := engine.Render(&meta, "cloud-init-meta-data", rctx); err != nil {
+				return invalidf("%s: %w", n.Xname, err)
+			}
+			if err := engine.Render(&user, "cloud-init-user-data", rctx); err != nil {
+				return invalidf("%s: %w", n.Xname, err)
+			}
+			if err := cloudinit.Write(ciOutDir, n.Xname, meta.Bytes(), user.Bytes()); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("Wrote cloud-init data with %d key(s) for %d node(s) to %s\n", len(keys), len(nodes), ciOutDir)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cloudInitCmd)
+	cloudInitCmd.Flags().StringVarP(&ciFile, "file", "f", "", "inventory whose nodes[] get cloud-init data")
+	cloudInitCmd.Flags().StringVar(&ciNodes, "nodes", "", "comma-separated node xnames or aliases (default: every node)")
+	cloudInitCmd.Flags().StringSliceVar(&ciKeys, "ssh-pubkey", nil, "public key file(s) to authorize on the nodes (repeatable)")
+	cloudInitCmd.Flags().StringVarP(&ciOutDir, "output-dir", "o", "", "directory to write <xname>/meta-data and user-data into")
+	cloudInitCmd.Flags().StringArrayVar(&ciTemplateDir, "template-dir", nil, "directory with templates overriding the built-in ones; repeatable, earlier ones win")
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"bootstrap/internal/redact"
+	"bootstrap/internal/xname"
+	"bootstrap/pkg/inventory"
+
+	"github.com/spf13/cobra"
+)
+
+// assumeYes skips typed confirmations (--yes).
+var assumeYes bool
+
+// confirmIn is where typed confirmations are read from; tests replace it.
+var confirmIn io.Reader = os.Stdin
+
+// resolvedBMCs are the bmcs[] the hosts of this run were resolved from, used to
+// place hosts in chassis; set by resolveHosts.
+var resolvedBMCs []inventory.Entry
+
+// impactListed is how many hosts and chassis the impact summary names.
+const impactListed = 10
+
+// confirmImpact prints what op is about to change and has the operator type
+// the number of hosts to go ahead, unless --yes was given. Without an answer
+// (stdin closed, as in scripts) the command does not run.
+func confirmImpact(cmd *cobra.Command, args []string, op string, hosts []string) error {
+	if assumeYes {
+		return nil
+	}
+	printImpact(os.Stderr, cmd, args, op, hosts)
+	fmt.Fprintf(os.Stderr, "Type %d (the number of hosts) to continue: ", len(hosts))
+	line, _ := bufio.NewReader(confirmIn).ReadString('\n')
+	if strings.TrimSpace(line) != strconv.Itoa(len(hosts)) {
+		fmt.Fprintln(os.Stderr)
+		return invalidf("%s not confirmed; pass --yes to run without typing the number of hosts", op)
+	}
+	return nil
+}
+
+// printImpact writes the hosts, chassis and cabinets op touches and the
+// command that will run.
+func printImpact(w io.Writer, cmd *cobra.Command, args []string, op string, hosts []string) {
+	xnames := hostXnames(hosts, resolvedBMCs)
+	chassis := map[string]bool{}
+	cabinets := map[string]bool{}
+	unplaced := 0
+	for _, h := range hosts {
+		c := xname.Chassis(xnames[h])
+		if c == "" {
+			unplaced++
+			continue
+		}
+		chassis[c] = true
+		cabinets[c[:strings.LastIndexByte(c, 'c')]] = true
+	}
+	fmt.Fprintf(w, "About to run %s on %d host(s) in %d chassis of %d cabinet(s)\n", op, len(hosts), len(chassis), len(cabinets))
+	argv, _ := commandLine(cmd, args)
+	fmt.Fprintf(w, "  command: %s\n", strings.Join(redact.Strings(argv), " "))
+	if len(chassis) > 0 {
+		fmt.Fprintf(w, "  chassis: %s\n", listed(slices.Sorted(maps.Keys(chassis))))
+	}
+	if unplaced > 0 {
+		fmt.Fprintf(w, "  hosts without a chassis xname: %d\n", unplaced)
+	}
+	names := make([]string, len(hosts))
+	for i, h := range hosts {
+		names[i] = hostName(h)
+	}
+	fmt.Fprintf(w, "  hosts: %s\n", listed(names))
+}
+
+// listed joins the first impactListed items and counts the rest.
+func listed(items []string) string {
+	if len(items) <= impactListed {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(items[:impactListed], ", "), len(items)-impactListed)
+}
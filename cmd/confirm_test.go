@@ -0,0 +1,94 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+)
+
+func TestConfirmImpact(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	inv := filepath.Join(t.TempDir(), "inventory.yaml")
+	yaml := "bmcs:\n  - {xname: x1000c0s0b0, ip: 10.0.0.1}\n  - {xname: x1000c0s1b0, ip: 10.0.0.2}\n  - {xname: x1000c1s0b0, ip: 10.0.0.3}\n"
+	if err := os.WriteFile(inv, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	resets := 0
+	bmc := &redfishtest.MockClient{
+		GetSystemsFunc: func(context.Context) ([]redfish.System, error) {
+			var s redfish.System
+			s.ID = "Node0"
+			return []redfish.System{s}, nil
+		},
+		ResetSystemFunc: func(context.Context, string, string) error {
+			resets++
+			return nil
+		},
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"10.0.0.1": bmc, "10.0.0.2": bmc, "10.0.0.3": bmc})
+	pwFile, pwBatchSize, assumeYes = inv, 1, false
+	t.Cleanup(func() { pwFile, assumeYes, confirmIn, resolvedBMCs = "", true, os.Stdin, nil })
+
+	run := func(answer string) (int, string) {
+		confirmIn = strings.NewReader(answer)
+		oldStderr := os.Stderr
+		r, w, _ := os.Pipe()
+		os.Stderr = w
+		cmd := powerCmd
+		cmd.SetContext(context.Background())
+		err := cmd.RunE(cmd, []string{"cycle"})
+		w.Close() //nolint: errcheck
+		os.Stderr = oldStderr
+		var buf bytes.Buffer
+		io.Copy(&buf, r) //nolint: errcheck
+		return exitCode(err), buf.String()
+	}
+
+	for _, answer := range []string{"", "yes\n", "2\n"} {
+		code, out := run(answer)
+		if code != exitInvalid || resets != 0 {
+			t.Errorf("answer %q: exit code %d, %d reset(s); want %d and none", answer, code, resets, exitInvalid)
+		}
+		if !strings.Contains(out, "About to run power cycle on 3 host(s) in 2 chassis of 1 cabinet(s)") ||
+			!strings.Contains(out, "chassis: x1000c0, x1000c1") || !strings.Contains(out, "Type 3 (the number of hosts)") {
+			t.Errorf("answer %q: impact summary:\n%s", answer, out)
+		}
+	}
+	if code, out := run("3\n"); code != exitOK || resets != 3 {
+		t.Errorf("confirmed: exit code %d, %d reset(s)\n%s", code, resets, out)
+	}
+
+	// power on is not destructive and --yes skips the prompt
+	confirmIn = strings.NewReader("")
+	cmd := powerCmd
+	cmd.SetContext(context.Background())
+	if err := cmd.RunE(cmd, []string{"on"}); err != nil {
+		t.Errorf("power on: %v", err)
+	}
+	assumeYes = true
+	if code, out := run(""); code != exitOK || strings.Contains(out, "About to run") {
+		t.Errorf("--yes: exit code %d\n%s", code, out)
+	}
+}
+
+// unconfirmed runs f as an operator who does not type the host count and
+// returns its exit code.
+func unconfirmed(f func() error) int {
+	oldStderr := os.Stderr
+	os.Stderr, _ = os.Open(os.DevNull)
+	assumeYes, confirmIn = false, strings.NewReader("")
+	defer func() { os.Stderr, assumeYes, confirmIn = oldStderr, true, os.Stdin }()
+	return exitCode(f())
+}
@@ -0,0 +1,238 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"bootstrap/internal/xname"
+	"bootstrap/pkg/redfish"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	cnFile        string
+	cnHostsCSV    string
+	cnHostsFile   string
+	cnInsecure    bool
+	cnTimeout     time.Duration
+	cnBatchSize   int
+	cnURLTemplate string
+	cnOpen        bool
+	cnFormat      string
+)
+
+// openURL opens a URL in the desktop browser; tests replace it.
+var openURL = func(u string) error {
+	var c *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		c = exec.Command("open", u)
+	case "windows":
+		c = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	default:
+		c = exec.Command("xdg-open", u)
+	}
+	return c.Start()
+}
+
+// consoleRow is the graphical console of one node.
+type consoleRow struct {
+	Node         string   `json:"node"`
+	Host         string   `json:"host"`
+	System       string   `json:"system"`
+	Available    bool     `json:"available"`
+	Enabled      bool     `json:"enabled"`
+	ConnectTypes []string `json:"connect_types,omitempty"`
+	URL          string   `json:"url"`
+	FromBMC      bool     `json:"from_bmc"` // false when URL comes from --url-template
+}
+
+var consoleCmd = &cobra.Command{
+	Use:   "console [xname|host]...",
+	Short: "Print or open the graphical console (KVM) URL of nodes",
+	Long: `Read the GraphicalConsole of every system (or its manager) and print where the
+node's KVM console is launched, so boot problems can be watched without hunting
+through BMC web interfaces. Targets are given as for locate: BMC hosts or
+xnames, node xnames (e.g. x1000c0s0b0n1) to select one system, or the hosts of
+--hosts, --hosts-file or --file.
+
+Redfish has no standard launch URL. One named in the vendor Oem data is used;
+otherwise --url-template is expanded with {host} (the BMC), {xname} (the node)
+and {system} (the Redfish system Id). With --open every distinct URL of an
+enabled console is opened in the desktop browser.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if cnFormat != "" && cnFormat != "json" && cnFormat != "csv" {
+			return invalidf("--format must be json or csv when set")
+		}
+		targets, err := locateTargets(args, cnFile, cnHostsCSV, cnHostsFile)
+		if err != nil {
+			return err
+		}
+		bmcs, err := loadBMCs(cnFile)
+		if err != nil {
+			return err
+		}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+		hosts := targetHosts(targets)
+		xnames := hostXnames(hosts, bmcs)
+
+		ctx := cmd.Context()
+		var mu sync.Mutex
+		byHost := map[string][]consoleRow{}
+		failed := map[string]error{}
+		forEachHost(ctx, hosts, cnBatchSize, func(ctx context.Context, h string) {
+			cons, err := hostConsoles(ctx, h, user, pass)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", hostName(h), err)
+				failed[h] = err
+				return
+			}
+			byHost[h] = consoleRows(h, xnames[h], cons)
+		}, func(string) {})
+
+		var rows []consoleRow
+		for _, t := range targets {
+			for i, r := range byHost[t.Host] {
+				if t.Node < 0 || t.Node == i {
+					rows = append(rows, r)
+				}
+			}
+			if got, ok := byHost[t.Host]; ok && t.Node >= len(got) {
+				fmt.Fprintf(os.Stderr, "WARN: %s: node %d not found (%d system(s))\n", hostName(t.Host), t.Node, len(got))
+			}
+		}
+		if err := printConsoles(rows); err != nil {
+			return err
+		}
+		if cnOpen {
+			openConsoles(rows)
+		}
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+		return hostFailures(len(hosts), failed)
+	},
+}
+
+func hostConsoles(ctx context.Context, host, user, pass string) ([]redfish.Console, error) {
+	if cnTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cnTimeout)
+		defer cancel()
+	}
+	return newRedfishClient(host, user, pass, cnInsecure, cnTimeout).GetConsoles(ctx)
+}
+
+// consoleRows names the systems of one BMC after its xname (bmcX + "n" + node number)
+// when it is a valid BMC xname, and fills in --url-template for consoles without a vendor URL.
+func consoleRows(host, bmcX string, cons []redfish.Console) []consoleRow {
+	rows := make([]consoleRow, 0, len(cons))
+	paths := make([]string, len(cons))
+	for i, c := range cons {
+		paths[i] = c.SystemPath
+	}
+	nodes := redfish.NodeNumbers(paths)
+	for i, c := range cons {
+		r := consoleRow{
+			Node:         hostName(host) + " " + c.SystemID,
+			Host:         host,
+			System:       c.SystemID,
+			Available:    c.Available,
+			Enabled:      c.Enabled,
+			ConnectTypes: c.ConnectTypes,
+			URL:          c.URL,
+			FromBMC:      c.URL != "",
+		}
+		nodeX, err := xname.BMCXnameToNodeN(bmcX, nodes[i])
+		if err == nil {
+			r.Node = nodeX
+		}
+		if r.URL == "" {
+			x := host
+			if nodeX != "" {
+				x = nodeX
+			}
+			r.URL = strings.NewReplacer("{host}", host, "{xname}", x, "{system}", c.SystemID).Replace(cnURLTemplate)
+		}
+		rows = append(rows, r)
+	}
+	return rows
+}
+
+// openConsoles opens each distinct URL of an enabled console once.
+func openConsoles(rows []consoleRow) {
+	seen := map[string]bool{}
+	for _, r := range rows {
+		switch {
+		case r.Available && !r.Enabled:
+			fmt.Fprintf(os.Stderr, "WARN: %s: graphical console is disabled; not opening it\n", r.Node)
+		case seen[r.URL]:
+		default:
+			seen[r.URL] = true
+			if err := openURL(r.URL); err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s: open %s: %v\n", r.Node, r.URL, err)
+			}
+		}
+	}
+}
+
+func consoleState(r consoleRow) string {
+	switch {
+	case !r.Available:
+		return "unknown"
+	case r.Enabled:
+		return "enabled"
+	}
+	return "disabled"
+}
+
+func printConsoles(rows []consoleRow) error {
+	switch cnFormat {
+	case "json":
+		return printJSON(rows)
+	case "csv":
+		var out [][]string
+		for _, r := range rows {
+			out = append(out, []string{r.Node, r.Host, r.System, consoleState(r), strings.Join(r.ConnectTypes, " "), r.URL, strconv.FormatBool(r.FromBMC)})
+		}
+		return writeCSV(os.Stdout, []string{"node", "host", "system", "console", "connect_types", "url", "from_bmc"}, out)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NODE\tHOST\tCONSOLE\tTYPES\tURL")
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Node, hostName(r.Host), consoleState(r), cmp.Or(strings.Join(r.ConnectTypes, ","), "-"), r.URL)
+	}
+	return tw.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(consoleCmd)
+	consoleCmd.Flags().StringVarP(&cnFile, "file", "f", "", "Inventory file to read bmcs[] from (also resolves xname arguments to BMC IPs)")
+	consoleCmd.Flags().StringVar(&cnHostsCSV, "hosts", "", "Comma-separated list of BMC hosts to target when no arguments are given")
+	consoleCmd.Flags().StringVar(&cnHostsFile, "hosts-file", "", "file listing BMC hosts or bmcs[] xnames to target when no arguments are given")
+	consoleCmd.Flags().BoolVar(&cnInsecure, "insecure", true, "allow insecure TLS to BMCs")
+	consoleCmd.Flags().DurationVar(&cnTimeout, "timeout", 30*time.Second, "per-BMC timeout")
+	consoleCmd.Flags().IntVar(&cnBatchSize, "batch-size", 10, "number of BMCs to contact concurrently (0 or 1 = serial)")
+	consoleCmd.Flags().StringVar(&cnURLTemplate, "url-template", "https://{host}/", "console URL for BMCs that name none; {host}, {xname} and {system} are expanded")
+	consoleCmd.Flags().BoolVar(&cnOpen, "open", false, "open the console URLs in the desktop browser")
+	consoleCmd.Flags().StringVar(&cnFormat, "format", "", "output format: json or csv (default: table)")
+}
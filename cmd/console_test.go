@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+)
+
+func TestConsole(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	blade := &redfishtest.MockClient{
+		GetConsolesFunc: func(context.Context) ([]redfish.Console, error) {
+			return []redfish.Console{
+				{SystemID: "Node0", Available: true, Enabled: true, ConnectTypes: []string{"KVMIP"}, URL: "https://x1000c0s0b0/kvm"},
+				{SystemID: "Node1", Available: true, ConnectTypes: []string{"KVMIP"}},
+			}, nil
+		},
+	}
+	plain := &redfishtest.MockClient{
+		GetConsolesFunc: func(context.Context) ([]redfish.Console, error) {
+			return []redfish.Console{{SystemID: "1"}}, nil
+		},
+	}
+	broken := &redfishtest.MockClient{
+		GetConsolesFunc: func(context.Context) ([]redfish.Console, error) { return nil, errors.New("401 Unauthorized") },
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"x1000c0s0b0": blade, "10.0.0.9": plain, "b": broken})
+	var opened []string
+	oldOpen := openURL
+	openURL = func(u string) error { opened = append(opened, u); return nil }
+	defer func() { openURL, cnOpen, cnFormat, cnURLTemplate = oldOpen, false, "", "https://{host}/" }()
+	cnBatchSize, cnFormat, cnOpen = 1, "json", true
+	cnURLTemplate = "https://{host}/console?node={xname}&sys={system}"
+	consoleCmd.SetContext(context.Background())
+
+	run := func(args ...string) ([]consoleRow, error) {
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := consoleCmd.RunE(consoleCmd, args)
+		w.Close() //nolint:errcheck
+		os.Stdout = old
+		b, _ := io.ReadAll(r)
+		var rows []consoleRow
+		if jerr := json.Unmarshal(b, &rows); jerr != nil {
+			t.Fatalf("%v: %s", jerr, b)
+		}
+		return rows, err
+	}
+
+	rows, err := run("x1000c0s0b0", "10.0.0.9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []consoleRow{
+		{Node: "x1000c0s0b0n0", Host: "x1000c0s0b0", System: "Node0", Available: true, Enabled: true, ConnectTypes: []string{"KVMIP"}, URL: "https://x1000c0s0b0/kvm", FromBMC: true},
+		{Node: "x1000c0s0b0n1", Host: "x1000c0s0b0", System: "Node1", Available: true, ConnectTypes: []string{"KVMIP"}, URL: "https://x1000c0s0b0/console?node=x1000c0s0b0n1&sys=Node1"},
+		{Node: "10.0.0.9 1", Host: "10.0.0.9", System: "1", URL: "https://10.0.0.9/console?node=10.0.0.9&sys=1"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows =\n%+v\nwant\n%+v", rows, want)
+	}
+	// The disabled console of n1 is not opened
+	if want := []string{"https://x1000c0s0b0/kvm", "https://10.0.0.9/console?node=10.0.0.9&sys=1"}; !reflect.DeepEqual(opened, want) {
+		t.Errorf("opened = %v, want %v", opened, want)
+	}
+
+	// A node xname selects one system; a failing BMC exits 2
+	opened = nil
+	rows, err = run("x1000c0s0b0n0", "b")
+	if got := exitCode(err); got != exitPartial {
+		t.Errorf("exit code %d, want %d (%v)", got, exitPartial, err)
+	}
+	if len(rows) != 1 || rows[0].Node != "x1000c0s0b0n0" || len(opened) != 1 {
+		t.Errorf("rows = %+v, opened = %v", rows, opened)
+	}
+}
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"cmp"
+	"errors"
+	"os"
+	"sync"
+
+	"bootstrap/internal/diag"
+	"bootstrap/internal/keychain"
+	"bootstrap/internal/redact"
+)
+
+// credentialSource is where secrets come from: env (environment variables) or
+// keychain (the OS credential store, falling back to the environment).
+var credentialSource = cmp.Or(os.Getenv("BOOTSTRAP_CREDENTIALS"), "env")
+
+// keychainService is the keychain service secrets are stored under; the
+// account is the environment variable name, e.g. REDFISH_PASSWORD.
+const keychainService = "bootstrap"
+
+var (
+	secretsMu sync.Mutex
+	secrets   = map[string]string{} // keychain lookups of this run, by name
+)
+
+// secret returns the secret named by the environment variable name from
+// --credentials. Keychain values are registered with package redact and read
+// once per run, so the OS asks to unlock the keychain at most once.
+func secret(name string) (string, error) {
+	switch credentialSource {
+	case "env":
+		return os.Getenv(name), nil
+	case "keychain":
+	default:
+		return "", invalidf("--credentials must be env or keychain, not %q", credentialSource)
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	if v, ok := secrets[name]; ok {
+		return v, nil
+	}
+	v, err := keychain.Get(keychainService, name)
+	if errors.Is(err, keychain.ErrNotFound) {
+		diag.Logf("%v; using $%s", err, name)
+		v, err = os.Getenv(name), nil
+	}
+	if err != nil {
+		return "", invalid(err)
+	}
+	redact.Add(v)
+	secrets[name] = v
+	return v, nil
+}
+
+// redfishCreds returns the Redfish credentials: REDFISH_USER from the
+// environment and REDFISH_PASSWORD from --credentials.
+func redfishCreds() (string, string, error) {
+	user := os.Getenv("REDFISH_USER")
+	pass, err := secret("REDFISH_PASSWORD")
+	if err != nil {
+		return "", "", err
+	}
+	if user == "" || pass == "" {
+		if credentialSource == "keychain" {
+			return "", "", invalidf("REDFISH_USER and a REDFISH_PASSWORD keychain item (service %q) or env var are required", keychainService)
+		}
+		return "", "", invalidf("REDFISH_USER and REDFISH_PASSWORD env vars are required")
+	}
+	return user, pass, nil
+}
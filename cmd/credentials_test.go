@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bootstrap/internal/redact"
+)
+
+func TestRedfishCredsKeychain(t *testing.T) {
+	dir := t.TempDir()
+	script := `#!/bin/sh
+[ "$5" = "REDFISH_PASSWORD" ] || exit 1
+echo kc-password
+`
+	if err := os.WriteFile(filepath.Join(dir, "secret-tool"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	t.Setenv("REDFISH_USER", "root")
+	t.Setenv("REDFISH_PASSWORD", "env-password")
+	t.Setenv("IPMI_USER", "")
+	credentialSource = "keychain"
+	t.Cleanup(func() {
+		credentialSource = "env"
+		secrets = map[string]string{}
+		redact.Reset()
+	})
+
+	user, pass, err := redfishCreds()
+	if err != nil || user != "root" || pass != "kc-password" {
+		t.Fatalf("redfishCreds = %q, %q, %v", user, pass, err)
+	}
+	if got := redact.String("login kc-password"); got != "login "+redact.Mask {
+		t.Errorf("keychain secret not redacted: %q", got)
+	}
+	// Missing from the keychain: falls back to the environment
+	t.Setenv("IPMI_USER", "admin")
+	t.Setenv("IPMI_PASSWORD", "ipmi-env")
+	if u, p := ipmiCreds(user, pass); u != "admin" || p != "ipmi-env" {
+		t.Errorf("ipmiCreds = %q, %q", u, p)
+	}
+
+	credentialSource = "vault"
+	if _, _, err := redfishCreds(); exitCode(err) != exitInvalid {
+		t.Errorf("unknown backend: err = %v", err)
+	}
+}
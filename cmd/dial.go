@@ -0,0 +1,66 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bootstrap/internal/netdial"
+	"bootstrap/pkg/redfish"
+)
+
+var (
+	proxyURL          string
+	sshJump           string
+	sshJumpKey        string
+	sshJumpKnownHosts string
+	sourceIPs         []string
+	sourceIfaces      []string
+)
+
+// bmcDial is the dialer configureDialer set up, for commands that reach BMCs
+// other than through Redfish; nil dials directly.
+var bmcDial netdial.DialContextFunc
+
+// configureDialer routes all Redfish connections through --proxy and/or
+// --ssh-jump, from the local addresses chosen by --source-ip and --interface.
+// With both set, the jump host itself is reached through the proxy.
+func configureDialer() error {
+	if proxyURL == "" && sshJump == "" && len(sourceIPs) == 0 && len(sourceIfaces) == 0 {
+		redfish.SetDialer(nil)
+		bmcDial = nil
+		return nil
+	}
+	var rules []netdial.SourceRule
+	for _, v := range sourceIPs {
+		r, err := netdial.ParseSourceIP(v)
+		if err != nil {
+			return invalidf("--source-ip: %w", err)
+		}
+		rules = append(rules, r)
+	}
+	for _, v := range sourceIfaces {
+		r, err := netdial.ParseInterface(v)
+		if err != nil {
+			return invalidf("--interface: %w", err)
+		}
+		rules = append(rules, r)
+	}
+	dial := netdial.Bound(rules)
+	if proxyURL != "" {
+		var err error
+		if dial, err = netdial.Proxy(proxyURL, dial); err != nil {
+			return invalid(err)
+		}
+	}
+	if sshJump != "" {
+		j, err := netdial.NewSSHJump(sshJump, sshJumpKey, sshJumpKnownHosts, dial)
+		if err != nil {
+			return invalid(err)
+		}
+		dial = j.DialContext
+	}
+	redfish.SetDialer(dial)
+	bmcDial = dial
+	return nil
+}
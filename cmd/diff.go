@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffFile      string
+	diffHostsCSV  string
+	diffHostsFile string
+	diffInsecure  bool
+	diffTimeout   time.Duration
+	diffBatchSize int
+	diffFormat    string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Report drift between a desired-state file and live BMC state",
+	Long: `Probe each BMC named in a desired-state file and report where its firmware
+versions, BIOS attributes, NTP settings, accounts or SSH keys differ from the
+file. Nothing is changed. Exits with status 2 when any BMC has drifted.`,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		st, hosts, user, pass, err := loadDesired(diffFile, diffHostsCSV, diffHostsFile)
+		if err != nil {
+			return err
+		}
+		stopCache, err := useResponseCache()
+		if err != nil {
+			return err
+		}
+		defer stopCache()
+		ctx := cmd.Context()
+		reports := reconcileHosts(ctx, ctx, st, hosts, user, pass, diffInsecure, diffTimeout, diffBatchSize, false)
+		if err := printReconcileReport(reports, diffFormat); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+		failed := map[string]error{}
+		for _, r := range reports {
+			switch {
+			case r.err != nil:
+				failed[r.Host] = r.err
+			case r.Status == reconcileDrift:
+				failed[r.Host] = fmt.Errorf("%d drifted setting(s)", len(r.Changes))
+			}
+		}
+		return hostFailures(len(hosts), failed)
+	},
+}
+
+func init() {
+	diffCmd.Flags().StringVarP(&diffFile, "file", "f", "", "desired-state YAML file")
+	diffCmd.Flags().StringVar(&diffHostsCSV, "hosts", "", "comma-separated BMC hosts (overrides the hosts in --file)")
+	diffCmd.Flags().StringVar(&diffHostsFile, "hosts-file", "", "file listing BMC hosts or xnames, one per line (overrides the hosts in --file)")
+	diffCmd.Flags().BoolVar(&diffInsecure, "insecure", false, "allow insecure TLS to BMCs")
+	diffCmd.Flags().DurationVar(&diffTimeout, "timeout", 30*time.Second, "per-request timeout")
+	diffCmd.Flags().IntVar(&diffBatchSize, "batch-size", 10, "number of BMCs to probe concurrently")
+	diffCmd.Flags().StringVar(&diffFormat, "format", "", "output format: json")
+	addCacheFlags(diffCmd)
+	rootCmd.AddCommand(diffCmd)
+}
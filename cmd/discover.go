@@ -6,28 +6,43 @@
 package cmd
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"bootstrap/internal/discover"
-	"bootstrap/internal/inventory"
-	"bootstrap/internal/redfish"
+	"bootstrap/internal/initbmcs"
+	"bootstrap/internal/notify"
+	"bootstrap/internal/progress"
+	"bootstrap/internal/sshkeys"
+	"bootstrap/internal/topology"
+	"bootstrap/internal/xname"
+	"bootstrap/pkg/inventory"
+	"bootstrap/pkg/redfish"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var (
-	discFile        string
-	discBMCSubnet   string
-	discNodeSubnet  string
-	discNodeStartIP string
-	discInsecure    bool
-	discTimeout     time.Duration
-	discSSHPubKey   string
-	discDryRun      bool
+	discFile          string
+	discBMCSubnet     string
+	discNodeSubnet    string
+	discNodeStartIP   string
+	discInsecure      bool
+	discTimeouts      timeouts
+	discSSHPubKey     string
+	discDryRun        bool
+	discNICPrefer     []string
+	discHostsFile     string
+	discHostsCSV      string
+	discFailedOut     string
+	discNodesPerBlade int
+	discGeometry      string
+	discNodesPerBMC   int
+	discStats         bool
 )
 
 var discoverCmd = &cobra.Command{
@@ -35,11 +50,11 @@ var discoverCmd = &cobra.Command{
 	Short: "Discover bootable node NICs via Redfish and update nodes[]",
 	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
 		if discFile == "" {
-			return fmt.Errorf("--file is required")
+			return invalidf("--file is required")
 		}
 		// Validate subnet flags - at least one must be provided
 		if discBMCSubnet == "" && discNodeSubnet == "" {
-			return fmt.Errorf("at least one of --bmc-subnet or --node-subnet is required")
+			return invalidf("at least one of --bmc-subnet or --node-subnet is required")
 		}
 		// If only one subnet is provided, use it for both
 		if discBMCSubnet == "" {
@@ -48,34 +63,90 @@ var discoverCmd = &cobra.Command{
 		if discNodeSubnet == "" {
 			discNodeSubnet = discBMCSubnet
 		}
-		user := os.Getenv("REDFISH_USER")
-		pass := os.Getenv("REDFISH_PASSWORD")
-		if user == "" || pass == "" {
-			return fmt.Errorf("REDFISH_USER and REDFISH_PASSWORD env vars are required")
+		prefer, err := redfish.ParseNICPreference(discNICPrefer)
+		if err != nil {
+			return invalid(err)
 		}
-
-		raw, err := os.ReadFile(discFile)
+		if discNodesPerBMC > 0 && discGeometry == "" {
+			return invalidf("--nodes-per-bmc needs --geometry")
+		}
+		var geo initbmcs.Geometry
+		if discGeometry != "" {
+			if geo, err = initbmcs.LoadGeometry(discGeometry); err != nil {
+				return invalid(err)
+			}
+			if discNodesPerBMC > 0 {
+				geo.NodesPerBMC = discNodesPerBMC
+			}
+		}
+		user, pass, err := redfishCreds()
 		if err != nil {
 			return err
 		}
-		var doc inventory.FileFormat
-		if err := yaml.Unmarshal(raw, &doc); err != nil {
+
+		if !discDryRun {
+			unlock, err := lockInventory(discFile)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+		}
+		doc, err := readInventory(discFile)
+		if err != nil {
 			return err
 		}
 		if len(doc.BMCs) == 0 {
-			return fmt.Errorf("input must contain non-empty bmcs[]")
+			return invalidf("input must contain non-empty bmcs[]")
+		}
+		// Discover each BMC once even if bmcs[] lists it twice; the file keeps all entries
+		scan := doc
+		scan.BMCs = dedupeBMCs(doc.BMCs)
+		if strings.TrimSpace(discHostsCSV) != "" || discHostsFile != "" {
+			only, from := strings.Split(discHostsCSV, ","), "--hosts"
+			if strings.TrimSpace(discHostsCSV) == "" {
+				if only, err = readHostsFile(discHostsFile); err != nil {
+					return err
+				}
+				from = discHostsFile
+			}
+			aliases, topo := aliasXnames(doc), topology.Build(doc)
+			var names []string
+			for _, h := range only {
+				if group := topologyGroup(topo, h, aliases); group != nil {
+					for _, b := range group {
+						names = append(names, b.Xname)
+					}
+					continue
+				}
+				names = append(names, bmcAddr(h, nil, aliases))
+			}
+			if scan.BMCs = selectBMCs(scan.BMCs, names); len(scan.BMCs) == 0 {
+				return invalidf("none of the hosts in %s are in bmcs[]", from)
+			}
+		}
+		if len(tagSelectors) > 0 {
+			tagged, err := selectTagged(bmcHosts(scan.BMCs), doc, discFile)
+			if err != nil {
+				return err
+			}
+			scan.BMCs = selectBMCs(scan.BMCs, tagged)
+		}
+		if quarantineList != nil {
+			addrs := bmcHosts(scan.BMCs)
+			xnames := map[string]string{}
+			for i, b := range scan.BMCs {
+				xnames[addrs[i]] = b.Xname
+			}
+			kept, err := skipQuarantined(addrs, xnames)
+			if err != nil {
+				return err
+			}
+			scan.BMCs = selectBMCs(scan.BMCs, kept)
 		}
+		hosts := bmcHosts(scan.BMCs)
 
 		// Dry-run: only show what would be contacted and exit.
 		if discDryRun {
-			hosts := make([]string, 0, len(doc.BMCs))
-			for _, b := range doc.BMCs {
-				host := b.IP
-				if host == "" {
-					host = b.Xname
-				}
-				hosts = append(hosts, host)
-			}
 			fmt.Printf("[dry-run] would contact %d BMC(s): %v\n", len(hosts), hosts)
 			if discBMCSubnet == discNodeSubnet {
 				fmt.Printf("[dry-run] would allocate BMC and node IPs from subnet %s and write back to %s\n", discNodeSubnet, discFile)
@@ -87,48 +158,172 @@ var discoverCmd = &cobra.Command{
 			}
 			return nil
 		}
+		if discStats {
+			redfish.EnableStats()
+			defer printRequestStats(os.Stderr)
+		}
+
+		ctx, cancel := discTimeouts.forCommand(cmd.Context())
+		defer cancel()
+		note := newNotifier("discover", len(hosts))
 
 		// Optionally set SSH authorized keys on each BMC if provided.
 		if discSSHPubKey != "" {
-			keyBytes, err := os.ReadFile(discSSHPubKey)
+			keys, err := sshkeys.ReadFiles([]string{discSSHPubKey})
 			if err != nil {
-				return fmt.Errorf("read ssh pubkey: %w", err)
+				return invalidf("read ssh pubkey: %w", err)
 			}
-			authorized := string(keyBytes)
-			for _, b := range doc.BMCs {
-				host := b.IP
-				if host == "" {
-					host = b.Xname
+			opts := sshKeyOptions{Insecure: discInsecure, Timeout: discTimeouts.Request, BatchSize: 10}
+			provisionSSHKeys(ctx, hosts, user, pass, keys, opts)
+			if ctx.Err() != nil {
+				return finishNotify(note, discTimeouts.stopped(ctx))
+			}
+		}
+
+		prog := newProgress(len(hosts))
+		nodes, failed, partial, err := discover.UpdateNodes(ctx, &scan, discBMCSubnet, discNodeSubnet, discNodeStartIP, func(host string) redfish.Discoverer {
+			c := newRedfishClient(redfishHost(host, scan.BMCs), user, pass, discInsecure, discTimeouts.Request)
+			return progressDiscoverer{Discoverer: c, host: host, prog: prog, note: note}
+		}, prefer, discTimeouts.Host)
+		if err != nil {
+			if ctx.Err() != nil {
+				fmt.Fprintf(os.Stderr, "Interrupted: discovered %d node(s) before cancel; %s not written\n", len(nodes), discFile)
+				if discFailedOut != "" {
+					// Nothing was saved, so every BMC has to be retried
+					retry := map[string]error{}
+					for _, b := range scan.BMCs {
+						retry[bmcHosts([]inventory.Entry{b})[0]] = cmp.Or(failed[b.Xname], fmt.Errorf("interrupted before %s was written", discFile))
+					}
+					if err := writeFailedHosts(discFailedOut, hosts, retry); err != nil {
+						return finishNotify(note, err)
+					}
 				}
-				ctx := cmd.Context()
-				if discTimeout > 0 {
-					var cancel context.CancelFunc
-					ctx, cancel = context.WithTimeout(ctx, discTimeout)
-					defer cancel()
+				return finishNotify(note, discTimeouts.stopped(ctx))
+			}
+			return finishNotify(note, err)
+		}
+		if discFailedOut != "" {
+			retry := map[string]error{}
+			for _, b := range scan.BMCs {
+				if err, ok := failed[b.Xname]; ok {
+					retry[bmcHosts([]inventory.Entry{b})[0]] = err
 				}
-				if err := redfish.SetAuthorizedKeys(ctx, host, user, pass, discInsecure, discTimeout, authorized); err != nil {
-					fmt.Fprintf(os.Stderr, "WARN: %s: set authorized keys: %v\n", b.Xname, err)
+			}
+			if err := writeFailedHosts(discFailedOut, hosts, retry); err != nil {
+				return finishNotify(note, err)
+			}
+		}
+		discovered := map[string]bool{}
+		for _, b := range scan.BMCs {
+			if _, ok := failed[b.Xname]; !ok {
+				discovered[b.Xname] = true
+			}
+		}
+		doc.Nodes = mergeNodes(dedupeBMCs(doc.BMCs), doc.Nodes, nodes, discovered)
+		// Carry the MACs read from each BMC back to every bmcs[] entry for it
+		for _, b := range scan.BMCs {
+			for i := range doc.BMCs {
+				if b.MAC != "" && doc.BMCs[i].Xname == b.Xname {
+					doc.BMCs[i].MAC = b.MAC
 				}
 			}
 		}
-
-		nodes, err := discover.UpdateNodes(&doc, discBMCSubnet, discNodeSubnet, discNodeStartIP, user, pass, discInsecure, discTimeout)
-		if err != nil {
-			return err
+		if err := writeInventory(discFile, &doc); err != nil {
+			return finishNotify(note, err)
 		}
-		doc.Nodes = nodes
-		bytes, err := yaml.Marshal(&doc)
-		if err != nil {
-			return err
+		fmt.Printf("Updated %s with %d node record(s)\n", discFile, len(doc.Nodes))
+		if len(partial) > 0 {
+			bmcs := map[string]bool{}
+			for _, p := range partial {
+				bmcs[p.BMC] = true
+			}
+			fmt.Fprintf(os.Stderr, "WARN: %d system(s) on %d BMC(s) could not be read; their node entries were left unchanged:\n", len(partial), len(bmcs))
+			for _, p := range partial {
+				fmt.Fprintf(os.Stderr, "  %s %s: %v\n", p.BMC, p.SystemPath, p.Err)
+			}
 		}
-		if err := os.WriteFile(discFile, bytes, 0o644); err != nil {
-			return err
+		if discGeometry != "" {
+			report := discover.CompareExpected(geo, scan.BMCs, nodes, failed)
+			if err := report.Write(os.Stderr); err != nil {
+				return finishNotify(note, err)
+			}
 		}
-		fmt.Printf("Updated %s with %d node record(s)\n", discFile, len(nodes))
-		return nil
+		if discNodesPerBlade > 0 {
+			// Only the blades read in this run say anything about what discovery found
+			read := map[string]bool{}
+			for x := range discovered {
+				read[strings.ToLower(x)] = true
+			}
+			for _, m := range topology.Build(doc).CheckNodes(discNodesPerBlade, func(blade string) bool { return !read[blade] }) {
+				fmt.Fprintf(os.Stderr, "WARN: %s\n", m)
+			}
+		}
+		return finishNotify(note, hostFailures(len(scan.BMCs), failed))
 	},
 }
 
+// progressDiscoverer reports each BMC's NIC discovery, the step that decides
+// whether the BMC is discovered or failed, to --progress-fd and the webhooks.
+type progressDiscoverer struct {
+	redfish.Discoverer
+	host string
+	prog *progress.Reporter
+	note *notify.Tracker
+}
+
+func (d progressDiscoverer) DiscoverAllBootableMACs(ctx context.Context, prefer redfish.NICPreference) ([]redfish.SystemMACs, error) {
+	d.prog.Start(d.host)
+	systems, err := d.Discoverer.DiscoverAllBootableMACs(ctx, prefer)
+	failure := err
+	if err == nil && len(systems) > 0 {
+		failure = discover.AllFailed(systems) // UpdateNodes counts such a BMC as failed
+	}
+	state := "failed"
+	switch {
+	case failure == nil:
+		state = "discovered"
+	case ctx.Err() != nil:
+		state = "aborted"
+	}
+	d.prog.Done(d.host, state, failure)
+	d.note.Host(d.host, state, state == "failed", failure)
+	return systems, err
+}
+
+// selectBMCs returns the bmcs whose host or xname is listed in hosts.
+func selectBMCs(bmcs []inventory.Entry, hosts []string) []inventory.Entry {
+	want := map[string]bool{}
+	for _, h := range hosts {
+		want[canonicalHost(h)] = true
+	}
+	var out []inventory.Entry
+	for _, b := range bmcs {
+		if want[bmcHosts([]inventory.Entry{b})[0]] || want[strings.ToLower(b.Xname)] {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// mergeNodes builds nodes[] in bmcs order from the nodes discovered for the BMCs
+// in discovered and, for every other BMC (failed or not selected), the nodes
+// already in the file, so a partial run never drops nodes it did not rediscover.
+func mergeNodes(bmcs, existing, found []inventory.Entry, discovered map[string]bool) []inventory.Entry {
+	out := make([]inventory.Entry, 0, len(found))
+	for _, b := range bmcs {
+		from := existing
+		if discovered[b.Xname] {
+			from = found
+		}
+		for _, n := range from {
+			if xname.NodeToBMCXname(n.Xname) == b.Xname {
+				out = append(out, n)
+			}
+		}
+	}
+	return out
+}
+
 func init() {
 	rootCmd.AddCommand(discoverCmd)
 	discoverCmd.Flags().StringVarP(&discFile, "file", "f", "", "YAML file containing bmcs[] and nodes[] (nodes will be overwritten)")
@@ -136,7 +331,15 @@ func init() {
 	discoverCmd.Flags().StringVar(&discNodeSubnet, "node-subnet", "", "CIDR for node IPs, e.g. 10.42.0.0/24 (if not specified, uses --bmc-subnet)")
 	discoverCmd.Flags().StringVar(&discNodeStartIP, "node-start-ip", "", "Start node IP allocation at this address (skips all IPs before it)")
 	discoverCmd.Flags().BoolVar(&discInsecure, "insecure", true, "allow insecure TLS to BMCs")
-	discoverCmd.Flags().DurationVar(&discTimeout, "timeout", 12*time.Second, "per-BMC discovery timeout")
-	discoverCmd.Flags().StringVar(&discSSHPubKey, "ssh-pubkey", "", "Path to an SSH public key to set as AuthorizedKeys on each BMC (optional)")
+	discTimeouts.addFlags(discoverCmd.Flags(), 12*time.Second, 12*time.Second)
+	discoverCmd.Flags().StringVar(&discSSHPubKey, "ssh-pubkey", "", "Path to an SSH public key to set as AuthorizedKeys on each BMC (optional; see also `bmc ssh-keys`)")
+	discoverCmd.Flags().StringArrayVar(&discNICPrefer, "nic-preference", nil, "regular expression matched against interface Id/Name/Description to choose each node's boot MAC; repeat for a ranked list, most preferred first")
+	discoverCmd.Flags().StringVar(&discHostsCSV, "hosts", "", "comma-separated bmcs[] hosts or xnames to discover (overrides --hosts-file)")
+	discoverCmd.Flags().StringVar(&discHostsFile, "hosts-file", "", "only discover the bmcs[] entries whose host or xname is listed in this file, one per line ('#' starts a comment)")
+	discoverCmd.Flags().StringVar(&discFailedOut, "failed-hosts-out", "", "write the BMCs that could not be discovered, with the reason, to this file (usable as --hosts-file)")
+	discoverCmd.Flags().IntVar(&discNodesPerBlade, "nodes-per-blade", 0, "warn about discovered blades that do not hold this many nodes (0 = no check)")
+	discoverCmd.Flags().StringVar(&discGeometry, "geometry", "", "after discovery, report missing nodes, unexpected systems and empty blades against this init-bmcs geometry (ex2500|ex3000|ex4000 or a YAML file)")
+	discoverCmd.Flags().IntVar(&discNodesPerBMC, "nodes-per-bmc", 0, "nodes expected per BMC in the --geometry report (0 = the geometry's value)")
+	discoverCmd.Flags().BoolVar(&discStats, "stats", false, "print per-BMC Redfish request latency (p50/p95/max) and error counts to stderr at the end, slowest first")
 	discoverCmd.Flags().BoolVar(&discDryRun, "dry-run", false, "plan only: print which BMCs would be contacted and exit")
 }
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"bootstrap/internal/discover"
+	"bootstrap/internal/smd"
+	"bootstrap/pkg/redfish"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	hsnFile     string
+	hsnInsecure bool
+	hsnTimeouts timeouts
+	hsnPattern  string
+	hsnSMDURL   string
+	hsnDryRun   bool
+)
+
+var discoverHSNCmd = &cobra.Command{
+	Use:   "hsn",
+	Short: "Record node HSN interfaces in nodes[].hsn and optionally push them to SMD",
+	Long: `Re-read the EthernetInterfaces of every node after it has booted and record
+the high-speed network interfaces (matched by --hsn-pattern against Id, Name and
+Description) in the hsn[] list of each node. Their MACs read "Not Available" until
+the node's HSN driver is up, so run this after the first boot. nodes[] must already
+exist (see discover).
+
+With --smd-url each HSN interface is also created or updated in the SMD
+EthernetInterfaces inventory; set ACCESS_TOKEN if SMD requires a bearer token.`,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if hsnFile == "" {
+			return invalidf("--file is required")
+		}
+		pattern, err := regexp.Compile(hsnPattern)
+		if err != nil {
+			return invalidf("--hsn-pattern: %w", err)
+		}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+		if !hsnDryRun {
+			unlock, err := lockInventory(hsnFile)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+		}
+		doc, err := readInventory(hsnFile)
+		if err != nil {
+			return err
+		}
+		if len(doc.BMCs) == 0 || len(doc.Nodes) == 0 {
+			return invalidf("input must contain non-empty bmcs[] and nodes[]")
+		}
+		scan := doc
+		scan.BMCs = dedupeBMCs(doc.BMCs)
+
+		if hsnDryRun {
+			hosts := bmcHosts(scan.BMCs)
+			fmt.Printf("[dry-run] would read HSN interfaces from %d BMC(s): %v and write them to %s\n", len(hosts), hosts, hsnFile)
+			if hsnSMDURL != "" {
+				fmt.Printf("[dry-run] would push HSN interfaces to SMD at %s\n", hsnSMDURL)
+			}
+			return nil
+		}
+
+		ctx, cancel := hsnTimeouts.forCommand(cmd.Context())
+		defer cancel()
+		updated, failed, err := discover.UpdateHSN(ctx, &scan, func(host string) redfish.Discoverer {
+			return newRedfishClient(redfishHost(host, scan.BMCs), user, pass, hsnInsecure, hsnTimeouts.Request)
+		}, pattern, hsnTimeouts.Host)
+		if err != nil {
+			if ctx.Err() != nil {
+				fmt.Fprintf(os.Stderr, "Interrupted: %s not written\n", hsnFile)
+				return hsnTimeouts.stopped(ctx)
+			}
+			return err
+		}
+		if err := writeInventory(hsnFile, &scan); err != nil {
+			return err
+		}
+		fmt.Printf("Updated %s with HSN interfaces for %d node(s)\n", hsnFile, updated)
+
+		if hsnSMDURL != "" {
+			token, err := secret("ACCESS_TOKEN")
+			if err != nil {
+				return err
+			}
+			sc := smd.New(hsnSMDURL, token, hsnTimeouts.Request)
+			pushed := 0
+			for _, n := range scan.Nodes {
+				for _, nic := range n.HSN {
+					ei := smd.EthernetInterface{MACAddress: nic.MAC, ComponentID: n.Xname, Description: nic.Description}
+					if err := sc.PutEthernetInterface(ctx, ei); err != nil {
+						return fmt.Errorf("push %s (%s) to SMD: %w", nic.MAC, n.Xname, err)
+					}
+					pushed++
+				}
+			}
+			fmt.Printf("Pushed %d HSN interface(s) to SMD\n", pushed)
+		}
+		return hostFailures(len(scan.BMCs), failed)
+	},
+}
+
+func init() {
+	discoverCmd.AddCommand(discoverHSNCmd)
+	discoverHSNCmd.Flags().StringVarP(&hsnFile, "file", "f", "", "YAML file containing bmcs[] and discovered nodes[]")
+	discoverHSNCmd.Flags().BoolVar(&hsnInsecure, "insecure", true, "allow insecure TLS to BMCs")
+	hsnTimeouts.addFlags(discoverHSNCmd.Flags(), 12*time.Second, 12*time.Second)
+	discoverHSNCmd.Flags().StringVar(&hsnPattern, "hsn-pattern", discover.DefaultHSNPattern, "regular expression matched against interface Id/Name/Description to select HSN interfaces")
+	discoverHSNCmd.Flags().StringVar(&hsnSMDURL, "smd-url", "", "base URL of SMD to push HSN interfaces to (optional)")
+	discoverHSNCmd.Flags().BoolVar(&hsnDryRun, "dry-run", false, "plan only: print which BMCs would be contacted and exit")
+}
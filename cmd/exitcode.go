@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"bootstrap/pkg/redfish"
+)
+
+// Exit status contract for automation. Any other error exits with 1.
+const (
+	exitOK          = 0
+	exitPartial     = 2 // some hosts failed
+	exitAuth        = 3 // BMC rejected the credentials
+	exitInvalid     = 4 // bad flags, inventory or other input
+	exitUnreachable = 5 // BMCs could not be reached
+)
+
+// exitError carries the process exit status for an error returned by a command.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// invalidf returns an input/validation error (exit status 4).
+func invalidf(format string, args ...any) error {
+	return &exitError{code: exitInvalid, err: fmt.Errorf(format, args...)}
+}
+
+// invalid marks err as an input/validation error (exit status 4).
+func invalid(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitError{code: exitInvalid, err: err}
+}
+
+// exitCode maps an error returned by a command to the process exit status.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	if errors.Is(err, errInterrupted) {
+		return exitInterrupted
+	}
+	var ee *exitError
+	if errors.As(err, &ee) {
+		return ee.code
+	}
+	return 1
+}
+
+// classifyHostError returns the exit status a single host failure maps to.
+func classifyHostError(err error) int {
+	if redfish.IsAuthError(err) {
+		return exitAuth
+	}
+	var ne net.Error
+	if errors.As(err, &ne) || errors.Is(err, redfish.ErrTransport) || errors.Is(err, context.DeadlineExceeded) {
+		return exitUnreachable
+	}
+	return exitPartial
+}
+
+// hostFailures aggregates per-host failures into a command error. When every host
+// failed for the same reason (auth or unreachable) that class is used, otherwise any
+// failure is reported as a partial failure.
+func hostFailures(total int, errs map[string]error) error {
+	noteHostFailures(errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	code := 0
+	for _, err := range errs {
+		c := classifyHostError(err)
+		if code == 0 {
+			code = c
+		} else if code != c {
+			code = exitPartial
+		}
+	}
+	if len(errs) < total {
+		code = exitPartial
+	}
+	return &exitError{code: code, err: fmt.Errorf("%d of %d host(s) failed", len(errs), total)}
+}
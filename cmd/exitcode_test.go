@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"bootstrap/pkg/redfish"
+)
+
+func TestExitCodes(t *testing.T) {
+	auth := &redfish.StatusError{Method: "GET", StatusCode: 401, Status: "401 Unauthorized"}
+	unreachable := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	other := errors.New("boom")
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, exitOK},
+		{"generic", other, 1},
+		{"validation", invalidf("--file is required"), exitInvalid},
+		{"interrupted", errInterrupted, exitInterrupted},
+		{"all auth", hostFailures(2, map[string]error{"a": auth, "b": fmt.Errorf("wrapped: %w", auth)}), exitAuth},
+		{"all unreachable", hostFailures(2, map[string]error{"a": unreachable, "b": context.DeadlineExceeded}), exitUnreachable},
+		{"some hosts failed", hostFailures(3, map[string]error{"a": auth}), exitPartial},
+		{"mixed failures", hostFailures(2, map[string]error{"a": auth, "b": unreachable}), exitPartial},
+		{"no failures", hostFailures(2, nil), exitOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
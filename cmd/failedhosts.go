@@ -0,0 +1,32 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// writeFailedHosts writes the hosts that have an entry in errs to path, in the
+// order of hosts, each followed by its error as a comment. The file is written
+// even when nothing failed so that a stale list is never retried.
+func writeFailedHosts(path string, hosts []string, errs map[string]error) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %d of %d host(s) failed; retry with --hosts-file %s\n", len(errs), len(hosts), path)
+	for _, h := range hosts {
+		if err, ok := errs[h]; ok {
+			reason := "failed"
+			if err != nil {
+				reason = strings.Join(strings.Fields(err.Error()), " ")
+			}
+			fmt.Fprintf(&b, "%s # %s\n", h, reason)
+		}
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("write failed hosts: %w", err)
+	}
+	return nil
+}
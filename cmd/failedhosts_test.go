@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bootstrap/pkg/inventory"
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+)
+
+func TestFailedHostsOutRetry(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	update := func(err error) *redfishtest.MockClient {
+		return &redfishtest.MockClient{
+			SimpleUpdateFunc: func(context.Context, string, []string, string, string, bool) (redfish.UpdateResult, error) {
+				return redfish.UpdateResult{}, err
+			},
+		}
+	}
+	out := filepath.Join(t.TempDir(), "failed.txt")
+	fwFile, fwHostsCSV, fwHostsFile, fwFailedOut = "", "a,b,c", "", out
+	fwType, fwImageURI, fwTargets, fwExpectedVersion = "bmc", "http://10.0.0.1/firmware.bin", bmcTarget, ""
+	fwDryRun, fwBatchSize = false, 1
+	defer func() { fwHostsCSV, fwHostsFile, fwFailedOut = "", "", "" }()
+	cmd := firmwareCmd
+	cmd.SetContext(context.Background())
+
+	useMockClients(t, map[string]*redfishtest.MockClient{
+		"a": update(nil), "b": update(errors.New("connection\nrefused")), "c": update(nil),
+	})
+	if got := exitCode(cmd.RunE(cmd, nil)); got != exitPartial {
+		t.Fatalf("exit code %d, want %d", got, exitPartial)
+	}
+	raw, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), "b # connection refused\n") {
+		t.Errorf("failed hosts file:\n%s", raw)
+	}
+
+	// The file drives the retry: only b is contacted, and the list is rewritten empty
+	retry := update(nil)
+	useMockClients(t, map[string]*redfishtest.MockClient{"b": retry})
+	fwFile, fwHostsCSV, fwHostsFile, fwTargets = "", "", out, bmcTarget
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if len(retry.Calls) == 0 {
+		t.Error("retry did not contact b")
+	}
+	if _, err := readHostsFile(out); err == nil {
+		t.Error("expected no hosts left after a clean retry")
+	}
+}
+
+func TestMergeNodesKeepsUndiscovered(t *testing.T) {
+	bmcs := []inventory.Entry{{Xname: "x1000c0s0b0"}, {Xname: "x1000c0s1b0"}}
+	existing := []inventory.Entry{
+		{Xname: "x1000c0s0b0n0", MAC: "old-0"},
+		{Xname: "x1000c0s1b0n0", MAC: "old-1"},
+	}
+	found := []inventory.Entry{{Xname: "x1000c0s0b0n0", MAC: "new-0"}}
+	got := mergeNodes(bmcs, existing, found, map[string]bool{"x1000c0s0b0": true})
+	want := []inventory.Entry{{Xname: "x1000c0s0b0n0", MAC: "new-0"}, {Xname: "x1000c0s1b0n0", MAC: "old-1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -6,206 +6,589 @@ package cmd
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
-	"bootstrap/internal/inventory"
-	"bootstrap/internal/redfish"
+	"bootstrap/internal/fwplan"
+	"bootstrap/internal/fwversion"
+	"bootstrap/internal/imageserver"
+	"bootstrap/internal/redact"
+	"bootstrap/internal/snapshot"
+	"bootstrap/internal/topology"
+	"bootstrap/pkg/redfish"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var (
 	fwFile            string
 	fwHostsCSV        string
+	fwHostsFile       string
+	fwFailedOut       string
 	fwType            string
 	fwImageURI        string
 	fwTargets         []string
 	fwProtocol        string
 	fwInsecure        bool
-	fwTimeout         time.Duration
+	fwTimeouts        timeouts
 	fwDryRun          bool
 	fwForce           bool
 	fwExpectedVersion string
 	fwBatchSize       int
+	fwMaxFailures     string
+	fwActivate        string
+	fwActivateTimeout time.Duration
+	fwResetType       string
+	fwAllowDowngrade  bool
+	fwAllowList       string
+	fwUpdateFormat    string
+	fwServeDir        string
+	fwServeListen     string
+	fwServeURL        string
+	fwServeTTL        time.Duration
 )
 
-// defaultTargets returns target list for shorthand types.
-func defaultTargets(t string) ([]string, error) {
-	switch strings.ToLower(t) {
-	case "cc", "bmc":
-		return []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}, nil
-	case "nc":
-		return []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}, nil
-	case "bios":
-		return []string{
-			"/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS",
-			"/redfish/v1/UpdateService/FirmwareInventory/Node1.BIOS",
-		}, nil
-	default:
-		return nil, fmt.Errorf("unknown firmware type: %s (use cc|nc|bios or specify --targets)", t)
-	}
-}
+// fwImages serves --serve-dir during a firmware run; nil otherwise.
+var fwImages *imageserver.Server
+
+// fwImageID identifies the image fwImages serves, its file and digest, for
+// ledger keys; set with fwImages.
+var fwImageID string
 
 var firmwareCmd = &cobra.Command{
 	Use:   "firmware",
 	Short: "Update firmware via Redfish SimpleUpdate",
 	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
-		if fwFile == "" && fwHostsCSV == "" {
-			return errors.New("at least one of --file or --hosts is required")
+		if fwFile == "" && fwHostsCSV == "" && fwHostsFile == "" && fwNodes == "" {
+			return invalidf("at least one of --file, --hosts, --hosts-file or --nodes is required")
 		}
 		if fwImageURI == "" {
-			return errors.New("--image-uri is required")
+			return invalidf("--image-uri is required")
+		}
+		if fwServeDir != "" {
+			if fwServeURL == "" {
+				return invalidf("--serve-dir requires --serve-url (the address BMCs reach this host at)")
+			}
+			if strings.Contains(fwImageURI, "://") {
+				return invalidf("with --serve-dir, --image-uri is a file under it, not a URL")
+			}
+			if _, err := os.Stat(filepath.Join(fwServeDir, fwImageURI)); err != nil {
+				return invalid(err)
+			}
+		}
+		switch fwActivate {
+		case activateNone, activateBMCReset, activateSystemReset, activateDefer:
+		default:
+			return invalidf("--activate must be one of none, bmc-reset, system-reset, defer")
+		}
+		format := strings.ToLower(fwUpdateFormat)
+		if format != "" && format != "json" && format != "csv" {
+			return invalidf("--format must be json or csv when set")
 		}
 		if len(fwTargets) == 0 {
 			if fwType == "" {
-				return errors.New("--type is required when --targets is not provided (one of cc|nc|bios)")
+				return invalidf("--type is required when --targets is not provided (one of cc|nc|bmc|bios|nic)")
+			}
+			if _, err := firmwareType(fwType); err != nil {
+				return invalid(err)
+			}
+		}
+
+		if fwAllowList != "" {
+			if fwExpectedVersion == "" {
+				return invalidf("--allow-list requires --expected-version (the version of the image)")
 			}
-			var err error
-			fwTargets, err = defaultTargets(fwType)
+			allowed, err := fwversion.LoadAllowList(fwAllowList)
 			if err != nil {
-				return err
+				return invalid(err)
+			}
+			if !allowed.Allows(fwExpectedVersion) {
+				return invalidf("version %s is not on the allow-list %s", fwExpectedVersion, fwAllowList)
 			}
 		}
 
-		user := os.Getenv("REDFISH_USER")
-		pass := os.Getenv("REDFISH_PASSWORD")
-		if user == "" || pass == "" {
-			return fmt.Errorf("REDFISH_USER and REDFISH_PASSWORD env vars are required")
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
 		}
 
 		// Determine hosts to target
-		hosts := []string{}
-		if strings.TrimSpace(fwHostsCSV) != "" {
-			for _, h := range strings.Split(fwHostsCSV, ",") {
-				h = strings.TrimSpace(h)
-				if h != "" {
-					hosts = append(hosts, h)
-				}
+		hosts, err := firmwareHosts()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := fwTimeouts.forCommand(cmd.Context())
+		defer cancel()
+		budget, launch, err := newFailureBudget(ctx, fwMaxFailures, len(hosts))
+		if err != nil {
+			return err
+		}
+		defer budget.stop()
+		if !fwDryRun {
+			if stop, err := needsApproval(cmd, args, "firmware", hosts); stop || err != nil {
+				return err
 			}
-		} else {
-			// Load from inventory file
-			raw, err := os.ReadFile(fwFile)
+			var stopLaunch context.CancelFunc
+			launch, stopLaunch = untilWindowCloses(launch)
+			defer stopLaunch()
+		}
+
+		// Apply firmware update to each host
+		if fwServeDir != "" && !fwDryRun {
+			stop, err := serveImages()
 			if err != nil {
 				return err
 			}
-			var doc inventory.FileFormat
-			if err := yaml.Unmarshal(raw, &doc); err != nil {
-				return err
+			defer stop()
+		}
+		var mu sync.Mutex // Protect stdout/stderr writes and results
+		var results []fwResult
+		prog := newProgress(len(hosts))
+		note := newNotifier("firmware", len(hosts))
+		record := func(r fwResult) {
+			mu.Lock()
+			defer mu.Unlock()
+			if format == "" {
+				r.print()
+			}
+			results = append(results, r)
+			prog.Done(r.Host, r.Status, r.Err)
+			note.Host(r.Host, r.Status, r.Status == fwFailed, r.Err)
+			if r.Status == fwFailed {
+				budget.Fail()
 			}
-			if len(doc.BMCs) == 0 {
-				return fmt.Errorf("input must contain non-empty bmcs[]")
+		}
+		// Hosts are started under launch so --max-failures and the maintenance
+		// window stop further hosts without cancelling the updates in flight.
+		forEachHost(launch, hosts, fwBatchSize, func(_ context.Context, h string) {
+			prog.Start(h)
+			record(updateFirmwareHost(ctx, h, user, pass))
+		}, func(h string) {
+			record(fwResult{Host: h, Status: fwAborted})
+		})
+		if format != "" {
+			if err := printFirmwareResults(format, hosts, results); err != nil {
+				return finishNotify(note, err)
+			}
+		}
+		if fwImages != nil && ctx.Err() == nil {
+			if pending := fwImages.Pending(); len(pending) > 0 {
+				fmt.Fprintf(os.Stderr, "Waiting for %d BMC(s) to download %s (tokens expire after %s)\n", len(pending), fwImageURI, fwServeTTL)
+				_ = fwImages.Wait(ctx, imageWaitInterval)
 			}
-			for _, b := range doc.BMCs {
-				host := b.IP
-				if host == "" {
-					host = b.Xname
+		}
+		stopped := launchStopped(ctx, launch)
+		failed := map[string]error{}
+		retry := map[string]error{}
+		var notStarted []string
+		for _, r := range results {
+			switch r.Status {
+			case fwFailed:
+				failed[r.Host] = r.Err
+				retry[r.Host] = r.Err
+			case fwAborted:
+				notStarted = append(notStarted, r.Host)
+				retry[r.Host] = errors.New("not started: interrupted")
+				if stopped != nil {
+					retry[r.Host] = fmt.Errorf("not started: %w", stopped)
 				}
-				hosts = append(hosts, host)
 			}
 		}
+		if fwFailedOut != "" {
+			if err := writeFailedHosts(fwFailedOut, hosts, retry); err != nil {
+				return finishNotify(note, err)
+			}
+		}
+		if ctx.Err() != nil {
+			printInterruptSummary(results)
+			return finishNotify(note, fwTimeouts.stopped(ctx))
+		}
+		if stopped != nil {
+			return finishNotify(note, reportStopped(stopped, hosts, notStarted, failed))
+		}
+		return finishNotify(note, hostFailures(len(hosts), failed))
+	},
+}
 
-		// Apply firmware update to each host
-		if fwBatchSize <= 1 {
-			// Serial execution
-			for _, host := range hosts {
-				ctx := cmd.Context()
-				var cancel context.CancelFunc
-				if fwTimeout > 0 {
-					ctx, cancel = context.WithTimeout(ctx, fwTimeout)
-				}
-				if fwDryRun {
-					dryRunMsg := fmt.Sprintf("[dry-run] would POST SimpleUpdate on %s with image=%s targets=%v protocol=%s",
-						host, fwImageURI, fwTargets, fwProtocol)
-					if fwExpectedVersion != "" {
-						dryRunMsg += fmt.Sprintf(" expected-version=%s", fwExpectedVersion)
-						if fwForce {
-							dryRunMsg += " (force=true)"
-						}
-					}
-					fmt.Println(dryRunMsg)
-					if cancel != nil {
-						cancel()
-					}
-					continue
-				}
-				err := redfish.SimpleUpdate(ctx, host, user, pass, fwInsecure, fwTimeout, fwImageURI, fwTargets, fwProtocol, fwExpectedVersion, fwForce)
-				if cancel != nil {
-					cancel()
-				}
-				if err != nil {
-					// Check if this is a "skipping update" message
-					if strings.Contains(err.Error(), "skipping update") {
-						fmt.Printf("%s: %v\n", host, err)
-					} else {
-						fmt.Fprintf(os.Stderr, "WARN: %s: firmware update failed: %v\n", host, err)
-					}
-				} else {
-					fmt.Printf("Triggered firmware update on %s\n", host)
-				}
+// imageWaitInterval is how often a run polls for BMCs still to download their image.
+var imageWaitInterval = 2 * time.Second
+
+// serveImages starts the --serve-dir image server and returns its stop function.
+func serveImages() (func(), error) {
+	srv, err := imageserver.New(fwServeDir, fwServeTTL)
+	if err != nil {
+		return nil, invalid(err)
+	}
+	digest, err := srv.Digest(fwImageURI)
+	if err != nil {
+		srv.Close() //nolint:errcheck
+		return nil, invalid(err)
+	}
+	ln, err := net.Listen("tcp", fwServeListen)
+	if err != nil {
+		srv.Close() //nolint:errcheck
+		return nil, fmt.Errorf("--serve-listen: %w", err)
+	}
+	hs := &http.Server{Handler: srv, ReadHeaderTimeout: 30 * time.Second}
+	go hs.Serve(ln) //nolint:errcheck
+	fwImages, fwImageID = srv, fwImageURI+"@sha256:"+digest
+	return func() {
+		hs.Close()  //nolint:errcheck
+		ln.Close()  //nolint:errcheck // Serve may not have taken it yet
+		srv.Close() //nolint:errcheck
+		fwImages, fwImageID = nil, ""
+	}, nil
+}
+
+// downloadHost returns the address host downloads images from: its bmcs[] IP
+// when the inventory has one (hosts may be xnames behind --aggregator), else
+// host itself.
+func downloadHost(host string) string {
+	for _, b := range resolvedBMCs {
+		if b.IP != "" && b.Xname != "" && canonicalHost(b.Xname) == canonicalHost(host) {
+			return b.IP
+		}
+	}
+	return host
+}
+
+// hostImageURI returns the image URI to post to host and, when it was granted
+// by the --serve-dir server, a function revoking the grant.
+func hostImageURI(host string) (string, func(), error) {
+	if fwImages == nil {
+		return fwImageURI, func() {}, nil
+	}
+	p, err := fwImages.Grant(fwImageURI, downloadHost(host))
+	if err != nil {
+		return "", nil, err
+	}
+	return strings.TrimSuffix(fwServeURL, "/") + p, func() { fwImages.Revoke(p) }, nil
+}
+
+// ledgerImage returns the image identity the ledger keys an update of imageURI
+// on: for a one-time --serve-dir URL, the served file and its digest, so the
+// same update in a later run has the same key.
+func ledgerImage(imageURI string) string {
+	if fwImages == nil || !strings.HasPrefix(imageURI, strings.TrimSuffix(fwServeURL, "/")+"/") {
+		return imageURI
+	}
+	return fwImageID
+}
+
+// Firmware per-host outcomes
+const (
+	fwPlanned = "planned"
+	fwUpdated = "updated"
+	fwSkipped = "skipped"
+	fwFailed  = "failed"
+	fwAborted = "aborted"
+)
+
+// fwResult is the outcome of a firmware action against a single host.
+type fwResult struct {
+	Host    string
+	Status  string
+	Message string
+	TaskURI string   // task monitor of an accepted SimpleUpdate, if the BMC returned one
+	Skipped []string // targets left out because they already run --expected-version
+	Err     error
+}
+
+// fwReport is the --format json form of a fwResult.
+type fwReport struct {
+	Host           string   `json:"host"`
+	Alias          string   `json:"alias,omitempty"`
+	Action         string   `json:"action"` // planned, updated, skipped, failed or aborted
+	TaskURI        string   `json:"task_uri,omitempty"`
+	SkippedReason  string   `json:"skipped_reason,omitempty"`
+	SkippedTargets []string `json:"skipped_targets,omitempty"`
+	Message        string   `json:"message,omitempty"`
+	Error          string   `json:"error,omitempty"`
+}
+
+func (r fwResult) report() fwReport {
+	out := fwReport{Host: r.Host, Alias: hostAlias(r.Host), Action: r.Status, TaskURI: r.TaskURI, SkippedTargets: r.Skipped, Message: r.Message}
+	if r.Err != nil {
+		if r.Status == fwSkipped {
+			out.SkippedReason = r.Err.Error()
+		} else {
+			out.Error = r.Err.Error()
+		}
+	}
+	return out
+}
+
+// printFirmwareResults writes one fwReport per host, in the order of hosts, as
+// JSON or CSV.
+func printFirmwareResults(format string, hosts []string, results []fwResult) error {
+	byHost := make(map[string]fwResult, len(results))
+	for _, r := range results {
+		byHost[r.Host] = r
+	}
+	reports := make([]fwReport, 0, len(hosts))
+	for _, h := range hosts {
+		if r, ok := byHost[h]; ok {
+			reports = append(reports, r.report())
+		}
+	}
+	if format == "csv" {
+		rows := make([][]string, 0, len(reports))
+		for _, r := range reports {
+			rows = append(rows, []string{r.Host, r.Alias, r.Action, r.TaskURI, r.SkippedReason, strings.Join(r.SkippedTargets, " "), r.Message, r.Error})
+		}
+		return writeCSV(os.Stdout, []string{"host", "alias", "action", "task_uri", "skipped_reason", "skipped_targets", "message", "error"}, rows)
+	}
+	out, err := json.MarshalIndent(reports, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+// writeCSV writes a header line and rows for spreadsheets.
+func writeCSV(w io.Writer, header []string, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	return cw.WriteAll(rows)
+}
+
+func (r fwResult) print() {
+	switch r.Status {
+	case fwPlanned:
+		fmt.Println(r.Message)
+	case fwSkipped:
+		fmt.Printf("%s: %v\n", hostName(r.Host), r.Err)
+	case fwFailed:
+		fmt.Fprintf(os.Stderr, "WARN: %s: firmware update failed: %v\n", hostName(r.Host), r.Err)
+	case fwUpdated:
+		msg := fmt.Sprintf("Triggered firmware update on %s", hostName(r.Host))
+		if r.TaskURI != "" {
+			msg += fmt.Sprintf(" (task %s)", r.TaskURI)
+		}
+		if len(r.Skipped) > 0 {
+			msg += fmt.Sprintf("; skipped %s (already at %s)", strings.Join(r.Skipped, ", "), fwExpectedVersion)
+		}
+		if r.Message != "" {
+			msg += ": " + r.Message
+		}
+		fmt.Println(msg)
+	}
+}
+
+// updateFirmwareHost runs (or plans, with --dry-run) SimpleUpdate on a single host.
+// Without --targets the components matching --type are looked up on the host,
+// and with --nodes only those of the selected nodes are kept.
+func updateFirmwareHost(ctx context.Context, host, user, pass string) fwResult {
+	if fwDryRun {
+		targets := fmt.Sprint(fwTargets)
+		if len(fwTargets) == 0 {
+			targets = fmt.Sprintf("(%s components of FirmwareInventory)", fwType)
+		}
+		if nodes := hostNodes(host); nodes != nil {
+			targets += fmt.Sprintf(" of nodes %s", strings.Join(nodes, ","))
+		}
+		image := fwImageURI
+		if fwServeDir != "" {
+			image = strings.TrimSuffix(fwServeURL, "/") + "/<one-time token>/" + fwImageURI
+		}
+		dryRunMsg := fmt.Sprintf("[dry-run] would POST SimpleUpdate on %s with image=%s targets=%s protocol=%s",
+			host, image, targets, fwProtocol)
+		if fwExpectedVersion != "" {
+			dryRunMsg += fmt.Sprintf(" expected-version=%s", fwExpectedVersion)
+			if fwForce {
+				dryRunMsg += " (force=true)"
 			}
+		}
+		if fwActivate != activateNone {
+			dryRunMsg += fmt.Sprintf(" then activate=%s", fwActivate)
+		}
+		if fwBIOSBackupDir != "" && (strings.EqualFold(fwType, "bios") || biosUpdate(fwTargets)) {
+			dryRunMsg += fmt.Sprintf(" after saving BIOS attributes to %s", fwBIOSBackupDir)
+		}
+		return fwResult{Host: host, Status: fwPlanned, Message: redact.String(dryRunMsg)}
+	}
+	rf := withLedger(newRedfishClient(host, user, pass, fwInsecure, fwTimeouts.Request), host)
+	reqCtx, cancel := fwTimeouts.forHost(ctx)
+	defer cancel()
+	targets := fwTargets
+	if len(targets) == 0 {
+		var err error
+		if targets, err = typeTargets(reqCtx, rf, fwType); err != nil {
+			return fwResult{Host: host, Status: fwFailed, Err: err}
+		}
+	}
+	if nodes := hostNodes(host); nodes != nil {
+		var err error
+		if targets, err = nodeFirmwareTargets(reqCtx, rf, targets, nodes); err != nil {
+			return fwResult{Host: host, Status: fwFailed, Err: err}
+		}
+	}
+	if fwExpectedVersion != "" && !fwAllowDowngrade {
+		if err := checkDowngrade(reqCtx, rf, host, targets, fwExpectedVersion); err != nil {
+			return fwResult{Host: host, Status: fwFailed, Err: err}
+		}
+	}
+	proto, err := redfish.ResolveProtocol(reqCtx, rf, fwProtocol)
+	if err != nil {
+		return fwResult{Host: host, Status: fwFailed, Err: err}
+	}
+	var before []string
+	if fwActivate != activateNone && fwExpectedVersion == "" {
+		before = targetVersions(reqCtx, rf, targets)
+	}
+	var backup *snapshot.Host
+	if fwBIOSBackupDir != "" && biosUpdate(targets) {
+		if b, err := backupBIOS(reqCtx, rf, host); err != nil {
+			fmt.Fprintf(os.Stderr, "WARN: %s: BIOS backup failed, updating without one: %v\n", hostName(host), err)
 		} else {
-			// Parallel execution with semaphore to limit concurrency
-			var wg sync.WaitGroup
-			sem := make(chan struct{}, fwBatchSize)
-			var mu sync.Mutex // Protect stdout/stderr writes
-
-			for _, host := range hosts {
-				wg.Add(1)
-				go func(h string) {
-					defer wg.Done()
-					sem <- struct{}{}        // Acquire semaphore
-					defer func() { <-sem }() // Release semaphore
-
-					ctx := cmd.Context()
-					var cancel context.CancelFunc
-					if fwTimeout > 0 {
-						ctx, cancel = context.WithTimeout(ctx, fwTimeout)
-					}
-					if cancel != nil {
-						defer cancel()
-					}
-
-					if fwDryRun {
-						dryRunMsg := fmt.Sprintf("[dry-run] would POST SimpleUpdate on %s with image=%s targets=%v protocol=%s",
-							h, fwImageURI, fwTargets, fwProtocol)
-						if fwExpectedVersion != "" {
-							dryRunMsg += fmt.Sprintf(" expected-version=%s", fwExpectedVersion)
-							if fwForce {
-								dryRunMsg += " (force=true)"
-							}
-						}
-						mu.Lock()
-						fmt.Println(dryRunMsg)
-						mu.Unlock()
-						return
-					}
-
-					err := redfish.SimpleUpdate(ctx, h, user, pass, fwInsecure, fwTimeout, fwImageURI, fwTargets, fwProtocol, fwExpectedVersion, fwForce)
-
-					mu.Lock()
-					if err != nil {
-						// Check if this is a "skipping update" message
-						if strings.Contains(err.Error(), "skipping update") {
-							fmt.Printf("%s: %v\n", h, err)
-						} else {
-							fmt.Fprintf(os.Stderr, "WARN: %s: firmware update failed: %v\n", h, err)
-						}
-					} else {
-						fmt.Printf("Triggered firmware update on %s\n", h)
-					}
-					mu.Unlock()
-				}(host)
-			}
-			wg.Wait()
-		}
-		return nil
-	},
+			backup = &b
+		}
+	}
+	image, revoke, err := hostImageURI(host)
+	if err != nil {
+		return fwResult{Host: host, Status: fwFailed, Err: err}
+	}
+	up, err := rf.SimpleUpdate(reqCtx, image, targets, proto, fwExpectedVersion, fwForce)
+	if err != nil {
+		revoke()
+	}
+	res := fwResult{Host: host, TaskURI: up.TaskURI, Skipped: up.Skipped, Err: err}
+	switch {
+	case err == nil:
+	case errors.Is(err, redfish.ErrAlreadyAtVersion), errors.Is(err, errAlreadySent):
+		res.Status, res.Skipped = fwSkipped, nil
+		return res
+	case errors.Is(err, context.Canceled):
+		res.Status = fwAborted
+		return res
+	default:
+		res.Status = fwFailed
+		return res
+	}
+	if fwActivate != activateNone {
+		r := activateFirmwareHost(ctx, rf, host, up.TaskURI, targets, before)
+		res.Status, res.Message, res.Err = r.Status, r.Message, r.Err
+		if backup != nil && res.Status == fwUpdated && fwActivate == activateSystemReset {
+			res.Message += "; " + checkBIOSAfterUpdate(ctx, rf, *backup)
+		}
+		return res
+	}
+	res.Status = fwUpdated
+	return res
+}
+
+// Activation modes for --activate
+const (
+	activateNone        = "none"
+	activateBMCReset    = "bmc-reset"
+	activateSystemReset = "system-reset"
+	activateDefer       = "defer"
+)
+
+// activatePollInterval is how often --activate polls update and BMC state.
+var activatePollInterval = 15 * time.Second
+
+// activateFirmwareHost waits for the posted update of targets, whose task monitor is task, to finish,
+// performs the --activate reset and verifies the new version.
+func activateFirmwareHost(ctx context.Context, rf redfish.Client, host, task string, targets, before []string) fwResult {
+	ph := fwplan.Phase{Targets: targets, Version: fwExpectedVersion, ResetType: fwResetType, Reset: fwplan.ResetNone, Task: task}
+	switch fwActivate {
+	case activateBMCReset:
+		ph.Reset = fwplan.ResetManager
+	case activateSystemReset:
+		ph.Reset = fwplan.ResetSystem
+	case activateDefer:
+		ph.Version = "" // the new image is not running until the next reset
+	}
+	if fwActivateTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, fwActivateTimeout)
+		defer cancel()
+	}
+	plan := fwplan.Plan{PollInterval: activatePollInterval, PhaseTimeout: fwActivateTimeout}
+	if err := fwplan.Activate(ctx, rf, plan, ph); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return fwResult{Host: host, Status: fwAborted, Err: err}
+		}
+		return fwResult{Host: host, Status: fwFailed, Err: fmt.Errorf("activate: %w", err)}
+	}
+	if fwActivate == activateDefer {
+		return fwResult{Host: host, Status: fwUpdated, Message: "staged; activation deferred to the next reset"}
+	}
+	after := targetVersions(ctx, rf, targets)
+	msg := fmt.Sprintf("activated, version %s", strings.Join(after, ","))
+	if fwExpectedVersion == "" && len(before) > 0 && strings.Join(before, ",") == strings.Join(after, ",") {
+		msg = fmt.Sprintf("activated, but version unchanged (%s)", strings.Join(after, ","))
+	}
+	return fwResult{Host: host, Status: fwUpdated, Message: msg}
+}
+
+// checkDowngrade refuses an update when any target currently runs a newer version
+// than version. Versions that cannot be compared only produce a warning.
+func checkDowngrade(ctx context.Context, rf redfish.Client, host string, targets []string, version string) error {
+	for _, t := range targets {
+		inv, err := rf.GetFirmwareInventory(ctx, t)
+		if err != nil || inv.Version == "" {
+			continue // SimpleUpdate reports unreachable targets
+		}
+		c, err := fwversion.Compare(version, inv.Version)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "WARN: %s: %s: downgrade check skipped: %v\n", hostName(host), t, err)
+			continue
+		}
+		if c < 0 {
+			return fmt.Errorf("refusing to downgrade %s from %s to %s (use --allow-downgrade)", t, inv.Version, version)
+		}
+	}
+	return nil
+}
+
+// targetVersions returns the reported version of each target ("?" when unreadable).
+func targetVersions(ctx context.Context, rf redfish.Client, targets []string) []string {
+	out := make([]string, 0, len(targets))
+	for _, t := range targets {
+		v := "?"
+		if inv, err := rf.GetFirmwareInventory(ctx, t); err == nil {
+			v = inv.Version
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+// printInterruptSummary reports which hosts completed and which were aborted.
+func printInterruptSummary(results []fwResult) {
+	var done, aborted []string
+	for _, r := range results {
+		if r.Status == fwAborted {
+			aborted = append(aborted, r.Host)
+		} else {
+			done = append(done, r.Host)
+		}
+	}
+	printInterrupted(len(done), aborted)
+}
+
+// printInterrupted reports an interrupted batch run: how many hosts completed
+// and which were aborted, in flight or never started.
+func printInterrupted(done int, aborted []string) {
+	fmt.Fprintf(os.Stderr, "Interrupted: %d host(s) completed, %d aborted\n", done, len(aborted))
+	if len(aborted) > 0 {
+		fmt.Fprintf(os.Stderr, "  Aborted: %s\n", strings.Join(aborted, ", "))
+	}
 }
 
 func init() {
@@ -213,14 +596,29 @@ func init() {
 	// Make flags persistent so subcommands (like `firmware status`) inherit them
 	firmwareCmd.PersistentFlags().StringVarP(&fwFile, "file", "f", "", "Inventory file to read bmcs[] from when --hosts is not provided")
 	firmwareCmd.PersistentFlags().StringVar(&fwHostsCSV, "hosts", "", "Comma-separated list of BMC hosts to target (overrides --file)")
-	firmwareCmd.PersistentFlags().StringVar(&fwType, "type", "", "Firmware type preset: cc|nc|bios (ignored if --targets provided)")
-	firmwareCmd.PersistentFlags().StringVar(&fwImageURI, "image-uri", "", "Firmware image URI accessible by BMC (required)")
-	firmwareCmd.PersistentFlags().StringSliceVar(&fwTargets, "targets", nil, "Explicit FirmwareInventory target URIs (advanced)")
-	firmwareCmd.PersistentFlags().StringVar(&fwProtocol, "protocol", "HTTP", "TransferProtocol for SimpleUpdate (HTTP/HTTPS)")
+	firmwareCmd.PersistentFlags().StringVar(&fwHostsFile, "hosts-file", "", "file listing BMC hosts or bmcs[] xnames to target, one per line; '#' starts a comment (overrides --file)")
+	firmwareCmd.PersistentFlags().StringVar(&fwOrder, "order", topology.OrderInventory, orderUsage)
+	firmwareCmd.PersistentFlags().StringVar(&fwMaxFailures, "max-failures", "", maxFailuresUsage)
+	firmwareCmd.PersistentFlags().StringVar(&fwNodes, "nodes", "", "comma-separated node xnames (or node aliases) whose firmware to target; their BMCs are contacted and only the targets of those nodes are used")
+	firmwareCmd.PersistentFlags().StringVar(&fwFailedOut, "failed-hosts-out", "", "write the hosts that failed or were not started, with the reason, to this file (usable as --hosts-file)")
+	firmwareCmd.PersistentFlags().StringVar(&fwType, "type", "", "Firmware type preset: cc|nc|bmc|bios|nic, matched against each BMC's FirmwareInventory (ignored if --targets provided)")
+	firmwareCmd.PersistentFlags().StringVar(&fwImageURI, "image-uri", "", "Firmware image URI accessible by BMC (required); with --serve-dir, a file under that directory")
+	firmwareCmd.PersistentFlags().StringSliceVar(&fwTargets, "targets", nil, "Explicit FirmwareInventory target URIs (advanced; 'firmware targets <host>' lists a BMC's)")
+	firmwareCmd.PersistentFlags().StringVar(&fwProtocol, "protocol", "HTTP", "TransferProtocol for SimpleUpdate (HTTP/HTTPS/...), checked against what each BMC supports; auto picks one per BMC")
 	firmwareCmd.PersistentFlags().BoolVar(&fwInsecure, "insecure", true, "allow insecure TLS to BMCs")
-	firmwareCmd.PersistentFlags().DurationVar(&fwTimeout, "timeout", 5*time.Minute, "per-BMC firmware request timeout")
+	fwTimeouts.addFlags(firmwareCmd.PersistentFlags(), 5*time.Minute, 0)
 	firmwareCmd.PersistentFlags().BoolVar(&fwDryRun, "dry-run", false, "plan only: print SimpleUpdate actions without posting")
 	firmwareCmd.PersistentFlags().BoolVar(&fwForce, "force", false, "force update even if already at expected version")
 	firmwareCmd.PersistentFlags().StringVar(&fwExpectedVersion, "expected-version", "", "expected version string; skip update if already at this version (unless --force)")
+	firmwareCmd.Flags().StringVar(&fwActivate, "activate", activateNone, "after the update: none, bmc-reset, system-reset, or defer (wait for the update to finish without resetting)")
+	firmwareCmd.Flags().DurationVar(&fwActivateTimeout, "activate-timeout", 30*time.Minute, "how long to wait for the update task and reset to complete per BMC")
+	firmwareCmd.Flags().StringVar(&fwResetType, "reset-type", "GracefulRestart", "Redfish ResetType used by --activate bmc-reset/system-reset")
+	firmwareCmd.Flags().BoolVar(&fwAllowDowngrade, "allow-downgrade", false, "allow flashing a version older than the one installed (checked against --expected-version)")
+	firmwareCmd.Flags().StringVar(&fwAllowList, "allow-list", "", "file of approved firmware versions, one per line; --expected-version must be listed")
+	firmwareCmd.Flags().StringVar(&fwUpdateFormat, "format", "", "output format: json or csv (one result per host: action, task URI, skipped reason, error)")
+	firmwareCmd.Flags().StringVar(&fwServeDir, "serve-dir", "", "serve --image-uri, a file under this directory, from a built-in image server with a one-time download token per BMC")
+	firmwareCmd.Flags().StringVar(&fwServeListen, "serve-listen", ":8000", "address the --serve-dir image server listens on")
+	firmwareCmd.Flags().StringVar(&fwServeURL, "serve-url", "", "base URL BMCs reach the --serve-dir image server at, e.g. http://10.0.0.1:8000")
+	firmwareCmd.Flags().DurationVar(&fwServeTTL, "serve-token-ttl", time.Hour, "how long a BMC's download token stays valid")
 	firmwareCmd.PersistentFlags().IntVar(&fwBatchSize, "batch-size", 0, "number of concurrent firmware updates (0 or 1 = serial, >1 = parallel)")
 }
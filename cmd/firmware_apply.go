@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"bootstrap/internal/fwplan"
+
+	"github.com/spf13/cobra"
+)
+
+var fwPlanFile string
+
+var firmwareApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Run a phased firmware update plan on each BMC",
+	Long: `Apply the phases of an update plan (e.g. node controller, then BIOS) to each
+host in order. Each phase is flashed with its own SimpleUpdate, waited on until the
+BMC reports no running update tasks, and optionally followed by a manager or
+system reset before the next phase starts. A failed phase stops that host.`,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if fwPlanFile == "" {
+			return invalidf("--plan is required")
+		}
+		if fwFile == "" && fwHostsCSV == "" && fwHostsFile == "" {
+			return invalidf("at least one of --file, --hosts or --hosts-file is required")
+		}
+		if fwNodes != "" {
+			return invalidf("firmware apply does not take --nodes; name the node's targets in the plan phases")
+		}
+		plan, err := fwplan.Load(fwPlanFile)
+		if err != nil {
+			return invalid(err)
+		}
+		hosts, err := resolveHosts(fwFile, fwHostsCSV, fwHostsFile)
+		if err != nil {
+			return err
+		}
+		if hosts, err = orderHosts(hosts, fwOrder, fwFile); err != nil {
+			return err
+		}
+		if fwDryRun {
+			for _, h := range hosts {
+				for i, ph := range plan.Phases {
+					fmt.Printf("[dry-run] %s: phase %d/%d %s: would update %s from %s (reset: %s)\n",
+						h, i+1, len(plan.Phases), ph.Name, strings.Join(ph.Targets, ","), ph.ImageURI, ph.Reset)
+				}
+			}
+			return nil
+		}
+		ctx, cancel := fwTimeouts.forCommand(cmd.Context())
+		defer cancel()
+		budget, launch, err := newFailureBudget(ctx, fwMaxFailures, len(hosts))
+		if err != nil {
+			return err
+		}
+		defer budget.stop()
+		if stop, err := needsApproval(cmd, args, "firmware apply", hosts); stop || err != nil {
+			return err
+		}
+		launch, stopLaunch := untilWindowCloses(launch)
+		defer stopLaunch()
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+
+		var mu sync.Mutex
+		failed := map[string]error{}
+		counts := map[string]int{}
+		var aborted []string
+		note := newNotifier("firmware apply", len(hosts))
+		forEachHost(launch, hosts, fwBatchSize, func(_ context.Context, h string) {
+			rf := withLedger(newRedfishClient(h, user, pass, fwInsecure, fwTimeouts.Request), h)
+			ctx, cancel := fwTimeouts.forHost(ctx)
+			defer cancel()
+			logf := func(format string, args ...any) {
+				mu.Lock()
+				defer mu.Unlock()
+				fmt.Printf("%s: "+format+"\n", append([]any{hostName(h)}, args...)...)
+			}
+			results := fwplan.Run(ctx, rf, plan, logf)
+			mu.Lock()
+			defer mu.Unlock()
+			state := fwplan.StatusSkipped
+			for _, r := range results {
+				counts[r.Status]++
+				if r.Err != nil {
+					fmt.Fprintf(os.Stderr, "WARN: %s: phase %s: %v\n", hostName(h), r.Phase, r.Err)
+					failed[h] = r.Err
+				} else {
+					fmt.Printf("%s: phase %s %s\n", hostName(h), r.Phase, r.Status)
+				}
+				if r.Status == fwplan.StatusUpdated {
+					state = fwplan.StatusUpdated
+				}
+			}
+			if err, ok := failed[h]; ok {
+				note.Host(h, fwplan.StatusFailed, true, err)
+				budget.Fail()
+			} else {
+				note.Host(h, state, false, nil)
+			}
+		}, func(h string) {
+			mu.Lock()
+			defer mu.Unlock()
+			aborted = append(aborted, h)
+			note.Host(h, fwAborted, false, nil)
+		})
+
+		fmt.Println("Firmware plan summary:")
+		fmt.Printf("  hosts: %d\n", len(hosts))
+		for _, st := range []string{fwplan.StatusUpdated, fwplan.StatusSkipped, fwplan.StatusFailed, fwplan.StatusNotRun} {
+			if counts[st] > 0 {
+				fmt.Printf("  phases %s: %d\n", st, counts[st])
+			}
+		}
+		if fwFailedOut != "" {
+			retry := maps.Clone(failed)
+			reason := errors.New("not started: interrupted")
+			if stopped := launchStopped(ctx, launch); stopped != nil {
+				reason = fmt.Errorf("not started: %w", stopped)
+			}
+			for _, h := range aborted {
+				retry[h] = reason
+			}
+			if err := writeFailedHosts(fwFailedOut, hosts, retry); err != nil {
+				return finishNotify(note, err)
+			}
+		}
+		if ctx.Err() != nil {
+			sort.Strings(aborted)
+			fmt.Fprintf(os.Stderr, "Interrupted: %d host(s) not started: %s\n", len(aborted), strings.Join(aborted, ", "))
+			return finishNotify(note, fwTimeouts.stopped(ctx))
+		}
+		if stopped := launchStopped(ctx, launch); stopped != nil {
+			return finishNotify(note, reportStopped(stopped, hosts, aborted, failed))
+		}
+		return finishNotify(note, hostFailures(len(hosts), failed))
+	},
+}
+
+func init() {
+	firmwareCmd.AddCommand(firmwareApplyCmd)
+	firmwareApplyCmd.Flags().StringVar(&fwPlanFile, "plan", "", "update plan YAML listing phases in order")
+}
@@ -0,0 +1,216 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"bootstrap/internal/snapshot"
+	"bootstrap/pkg/redfish"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	fwBIOSBackupDir string
+	fwBIOSReapply   bool
+	fbFormat        string
+)
+
+var firmwareBIOSCheckCmd = &cobra.Command{
+	Use:   "bios-check [host]...",
+	Short: "Report BIOS attributes that changed since the backup taken before a BIOS update",
+	Long: `Compare the current BIOS attributes of each host with the backup the firmware
+command saved in --bios-backup-dir before updating its BIOS, and list every
+attribute whose value changed or that is no longer reported. Firmware updates
+often reset BIOS settings to their defaults without saying so.
+
+Without arguments every backup in the directory is checked. --bios-reapply
+writes the saved values of changed attributes back (staged for the next boot).
+The command exits 2 when attributes changed and were not re-applied.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if fbFormat != "" && fbFormat != "json" && fbFormat != "csv" {
+			return invalidf("--format must be json or csv when set")
+		}
+		backups, err := snapshot.Load(fwBIOSBackupDir)
+		if err != nil {
+			return invalid(err)
+		}
+		if len(args) > 0 {
+			want := map[string]bool{}
+			for _, a := range args {
+				want[canonicalHost(a)] = true
+			}
+			kept := backups[:0]
+			for _, b := range backups {
+				if want[canonicalHost(b.Host)] {
+					kept = append(kept, b)
+				}
+			}
+			if len(kept) == 0 {
+				return invalidf("no backup of %s in %s", strings.Join(args, ", "), fwBIOSBackupDir)
+			}
+			backups = kept
+		}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+		byHost := map[string]snapshot.Host{}
+		var hosts []string
+		for _, b := range backups {
+			byHost[b.Host] = b
+			hosts = append(hosts, b.Host)
+		}
+
+		ctx := cmd.Context()
+		var mu sync.Mutex
+		var changes []snapshot.BIOSChange
+		failed := map[string]error{}
+		changedHosts := map[string]bool{}
+		forEachHost(ctx, hosts, fwBatchSize, func(ctx context.Context, h string) {
+			ctx, cancel := fwTimeouts.forHost(ctx)
+			defer cancel()
+			rf := newRedfishClient(h, user, pass, fwInsecure, fwTimeouts.Request)
+			diff, err := biosChanges(ctx, rf, byHost[h])
+			if err == nil && len(diff) > 0 && fwBIOSReapply {
+				var n int
+				if n, err = snapshot.ReapplyBIOS(ctx, rf, diff); err == nil {
+					mu.Lock()
+					fmt.Fprintf(os.Stderr, "%s: re-applied %d BIOS attribute(s), staged for next boot\n", hostName(h), n)
+					mu.Unlock()
+				}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", hostName(h), err)
+				failed[h] = err
+				return
+			}
+			if len(diff) > 0 {
+				changedHosts[h] = true
+			}
+			changes = append(changes, diff...)
+		}, func(string) {})
+
+		hostIdx := map[string]int{}
+		for i, h := range hosts {
+			hostIdx[h] = i
+		}
+		slices.SortStableFunc(changes, func(a, b snapshot.BIOSChange) int { return cmp.Compare(hostIdx[a.Host], hostIdx[b.Host]) })
+		if err := printBIOSChanges(changes, len(hosts), len(changedHosts), len(failed)); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+		if err := hostFailures(len(hosts), failed); err != nil {
+			return err
+		}
+		if len(changedHosts) > 0 && !fwBIOSReapply {
+			return &exitError{code: exitPartial, err: fmt.Errorf("BIOS attributes changed on %d of %d host(s)", len(changedHosts), len(hosts))}
+		}
+		return nil
+	},
+}
+
+// biosUpdate reports whether targets include host firmware (BIOS/UEFI).
+func biosUpdate(targets []string) bool {
+	for _, t := range targets {
+		if firmwareTypes["bios"].MatchString(path.Base(t)) {
+			return true
+		}
+	}
+	return false
+}
+
+// backupBIOS saves the BIOS attributes of host to --bios-backup-dir.
+func backupBIOS(ctx context.Context, rf redfish.Client, host string) (snapshot.Host, error) {
+	b, err := snapshot.TakeBIOS(ctx, rf, host, time.Now())
+	if err != nil {
+		return b, err
+	}
+	return b, snapshot.Save(fwBIOSBackupDir, b)
+}
+
+func biosChanges(ctx context.Context, rf redfish.Client, backup snapshot.Host) ([]snapshot.BIOSChange, error) {
+	current, err := rf.GetBiosAttributes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("read BIOS attributes: %w", err)
+	}
+	return snapshot.DiffBIOS(backup, current), nil
+}
+
+// checkBIOSAfterUpdate compares the BIOS of a freshly updated and reset host
+// with its backup, re-applying changed attributes with --bios-reapply, and
+// returns a note for the host's result.
+func checkBIOSAfterUpdate(ctx context.Context, rf redfish.Client, backup snapshot.Host) string {
+	diff, err := biosChanges(ctx, rf, backup)
+	switch {
+	case err != nil:
+		return fmt.Sprintf("BIOS check failed: %v", err)
+	case len(diff) == 0:
+		return "BIOS settings unchanged"
+	case fwBIOSReapply:
+		n, err := snapshot.ReapplyBIOS(ctx, rf, diff)
+		if err != nil {
+			return fmt.Sprintf("%d BIOS attribute(s) changed; re-apply failed after %d: %v", len(diff), n, err)
+		}
+		return fmt.Sprintf("%d BIOS attribute(s) changed; re-applied %d, staged for next boot", len(diff), n)
+	}
+	return fmt.Sprintf("%d BIOS attribute(s) changed by the update ('firmware bios-check' lists them)", len(diff))
+}
+
+func printBIOSChanges(changes []snapshot.BIOSChange, hosts, changed, failures int) error {
+	switch fbFormat {
+	case "json":
+		return printJSON(changes)
+	case "csv":
+		var rows [][]string
+		for _, c := range changes {
+			rows = append(rows, []string{c.Host, c.System, c.Attribute, fmt.Sprint(c.Saved), biosValue(c.Current)})
+		}
+		return writeCSV(os.Stdout, []string{"host", "system", "attribute", "saved", "current"}, rows)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "HOST\tSYSTEM\tATTRIBUTE\tSAVED\tCURRENT")
+	for _, c := range changes {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%v\t%s\n", hostName(c.Host), path.Base(c.System), c.Attribute, c.Saved, biosValue(c.Current))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Println("BIOS check summary:")
+	fmt.Printf("  unchanged: %d\n", hosts-changed-failures)
+	fmt.Printf("  changed: %d\n", changed)
+	fmt.Printf("  errors: %d\n", failures)
+	return nil
+}
+
+// biosValue prints the current value of an attribute; "(gone)" when the system
+// no longer reports it.
+func biosValue(v any) string {
+	if v == nil {
+		return "(gone)"
+	}
+	return fmt.Sprint(v)
+}
+
+func init() {
+	firmwareCmd.AddCommand(firmwareBIOSCheckCmd)
+	firmwareCmd.PersistentFlags().StringVar(&fwBIOSBackupDir, "bios-backup-dir", "bios-backups", "directory the BIOS attributes of each host are saved to before a BIOS update and read by bios-check ('' = no backup)")
+	firmwareCmd.PersistentFlags().BoolVar(&fwBIOSReapply, "bios-reapply", false, "write back BIOS attributes the update changed (after --activate system-reset, or with bios-check)")
+	firmwareBIOSCheckCmd.Flags().StringVar(&fbFormat, "format", "", "output format: json or csv (default: table)")
+}
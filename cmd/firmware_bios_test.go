@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"bootstrap/internal/snapshot"
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+)
+
+func TestFirmwareBIOSBackup(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	const node0 = "/redfish/v1/Systems/Node0"
+	smt := "Enabled"
+	var patched map[string]any
+	m := &redfishtest.MockClient{
+		GetBiosAttributesFunc: func(context.Context) ([]redfish.SystemBios, error) {
+			return []redfish.SystemBios{{SystemPath: node0, Attributes: map[string]any{"SMT": smt, "BootMode": "Uefi"}}}, nil
+		},
+		SetBiosAttributesFunc: func(_ context.Context, path string, attrs map[string]any) error {
+			patched = attrs
+			return nil
+		},
+		SimpleUpdateFunc: func(context.Context, string, []string, string, string, bool) (redfish.UpdateResult, error) {
+			smt = "Disabled" // the new BIOS resets SMT
+			return redfish.UpdateResult{}, nil
+		},
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"a": m})
+	dir := filepath.Join(t.TempDir(), "bios")
+	fwFile, fwHostsCSV, fwHostsFile = "", "a", ""
+	fwImageURI, fwTargets, fwExpectedVersion = "http://10.0.0.1/bios.bin", []string{"/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS"}, ""
+	fwDryRun, fwBatchSize, fwActivate, fwUpdateFormat = false, 0, activateNone, ""
+	fwBIOSBackupDir = dir
+	defer func() { fwHostsCSV, fwTargets, fwBIOSBackupDir, fwBIOSReapply = "", nil, "bios-backups", false }()
+
+	old := os.Stdout
+	os.Stdout, _ = os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	defer func() { os.Stdout = old }()
+	firmwareCmd.SetContext(context.Background())
+	if err := firmwareCmd.RunE(firmwareCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := snapshot.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := backups[0].Systems[0].BIOS; backups[0].Host != "a" || got["SMT"] != "Enabled" {
+		t.Errorf("backup = %+v", backups[0])
+	}
+
+	// bios-check reports the reset attribute and exits 2 until it is re-applied
+	firmwareBIOSCheckCmd.SetContext(context.Background())
+	if got := exitCode(firmwareBIOSCheckCmd.RunE(firmwareBIOSCheckCmd, nil)); got != exitPartial {
+		t.Errorf("bios-check: exit code %d, want %d", got, exitPartial)
+	}
+	fwBIOSReapply = true
+	if err := firmwareBIOSCheckCmd.RunE(firmwareBIOSCheckCmd, []string{"a"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]any{"SMT": "Enabled"}; !reflect.DeepEqual(patched, want) {
+		t.Errorf("re-applied %v, want %v", patched, want)
+	}
+	if got := exitCode(firmwareBIOSCheckCmd.RunE(firmwareBIOSCheckCmd, []string{"b"})); got != exitInvalid {
+		t.Errorf("unknown host: exit code %d, want %d", got, exitInvalid)
+	}
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"bootstrap/internal/statusdb"
+)
+
+var (
+	fwStateDB    string
+	fwStuckAfter time.Duration
+)
+
+// stateDBTimeout is how long a status run waits for another one holding --state-db.
+const stateDBTimeout = 10 * time.Second
+
+// recordStatusRun stores run in --state-db and writes the changes since the
+// previous run to w.
+func recordStatusRun(w io.Writer, run statusdb.Run) error {
+	db, err := statusdb.Open(fwStateDB, stateDBTimeout)
+	if err != nil {
+		return fmt.Errorf("--state-db: %w", err)
+	}
+	defer db.Close() //nolint:errcheck
+	prev, changes, err := db.Record(run, fwStuckAfter)
+	if err != nil {
+		return fmt.Errorf("--state-db: %w", err)
+	}
+	if prev.IsZero() {
+		fmt.Fprintf(w, "  First run recorded in %s; later runs report changes since the previous one\n", fwStateDB)
+		return nil
+	}
+	if len(changes) == 0 {
+		fmt.Fprintf(w, "  No changes since the last run (%s, %s ago)\n", prev.Local().Format(time.RFC3339), run.Time.Sub(prev).Round(time.Second))
+		return nil
+	}
+	fmt.Fprintf(w, "  Changes since the last run (%s, %s ago):\n", prev.Local().Format(time.RFC3339), run.Time.Sub(prev).Round(time.Second))
+	for _, c := range changes {
+		fmt.Fprintf(w, "    %s %s: %s\n", cmp.Or(hostAlias(c.Host), c.Xname, c.Host), path.Base(c.Target), changeText(c, run.Time))
+	}
+	return nil
+}
+
+// changeText describes c for the summary.
+func changeText(c statusdb.Change, now time.Time) string {
+	switch c.Kind {
+	case statusdb.VersionChanged:
+		return fmt.Sprintf("version %s -> %s", c.From, c.To)
+	case statusdb.NewError:
+		return "new error: " + c.To
+	case statusdb.Recovered:
+		return fmt.Sprintf("recovered (%s; was: %s)", c.To, c.From)
+	case statusdb.Stuck:
+		return fmt.Sprintf("in progress for %s, since %s (--stuck-after %s)", now.Sub(c.Since).Round(time.Second), c.Since.Local().Format(time.RFC3339), fwStuckAfter)
+	}
+	return c.Kind
+}
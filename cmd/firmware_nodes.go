@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"bootstrap/internal/xname"
+	"bootstrap/pkg/redfish"
+)
+
+// fwNodes is --nodes; fwNodeSel maps each BMC host it selects (canonical form)
+// to the node xnames selected on it. Both are empty without --nodes.
+var (
+	fwNodes   string
+	fwNodeSel map[string][]string
+)
+
+// fwOrder is --order.
+var fwOrder string
+
+// firmwareHosts resolves the BMCs firmware commands act on: the BMCs of --nodes
+// when set, else --file, --hosts or --hosts-file, in --order.
+func firmwareHosts() ([]string, error) {
+	fwNodeSel = nil
+	if strings.TrimSpace(fwNodes) == "" {
+		hosts, err := resolveHosts(fwFile, fwHostsCSV, fwHostsFile)
+		if err != nil {
+			return nil, err
+		}
+		return orderHosts(hosts, fwOrder, fwFile)
+	}
+	if fwHostsCSV != "" || fwHostsFile != "" {
+		return nil, invalidf("--nodes and --hosts/--hosts-file are mutually exclusive")
+	}
+	doc, err := loadInventory(fwFile)
+	if err != nil {
+		return nil, err
+	}
+	aliases := aliasXnames(doc)
+	sel := map[string][]string{}
+	var bmcs []string
+	for _, n := range strings.Split(fwNodes, ",") {
+		n = strings.TrimSpace(n)
+		if n == "" {
+			continue
+		}
+		x := cmp.Or(aliases[strings.ToLower(n)], n)
+		bmc := xname.NodeToBMCXname(x)
+		if bmc == "" {
+			return nil, invalidf("--nodes: %s is not a node xname (like x1000c0s0b0n1) or node alias", n)
+		}
+		h := canonicalHost(bmcAddr(bmc, doc.BMCs, nil))
+		if len(sel[h]) == 0 {
+			bmcs = append(bmcs, bmc)
+		}
+		sel[h] = append(sel[h], strings.ToLower(x))
+	}
+	if len(bmcs) == 0 {
+		return nil, invalidf("--nodes names no nodes")
+	}
+	hosts, err := resolveHosts(fwFile, strings.Join(bmcs, ","), "")
+	if err != nil {
+		return nil, err
+	}
+	fwNodeSel = sel
+	return orderHosts(hosts, fwOrder, fwFile)
+}
+
+// hostNodes returns the node xnames --nodes selects on host, or nil when the
+// whole BMC is targeted.
+func hostNodes(host string) []string {
+	return fwNodeSel[canonicalHost(host)]
+}
+
+// nodeFirmwareTargets keeps the targets that belong to the given nodes of one
+// BMC. Node n is the system with Id Node<n>, or the BMC's n-th ComputerSystem
+// when the Ids carry no node numbers, as discover numbers them; a component
+// belongs to it when its RelatedItem links the system, or, for BMCs without
+// RelatedItem, when its Id or Name carries the system's Id (Node1.BIOS).
+func nodeFirmwareTargets(ctx context.Context, rf redfish.Client, targets, nodes []string) ([]string, error) {
+	systems, err := rf.GetSystems(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list Systems for --nodes: %w", err)
+	}
+	comps, err := rf.ListFirmwareInventory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list FirmwareInventory for --nodes: %w", err)
+	}
+	byPath := map[string]redfish.FirmwareComponent{}
+	for _, c := range comps {
+		byPath[c.Path] = c
+	}
+	paths := make([]string, len(systems))
+	for i, s := range systems {
+		paths[i] = cmp.Or(s.ODataID, s.ID)
+	}
+	nums := redfish.NodeNumbers(paths)
+	keep := map[string]bool{}
+	for _, n := range nodes {
+		i := slices.Index(nums, xname.NodeNumber(n))
+		if i < 0 {
+			return nil, fmt.Errorf("%s: the BMC reports %d system(s)", n, len(systems))
+		}
+		sys := systems[i]
+		found := false
+		for _, t := range targets {
+			c, ok := byPath[t]
+			if !ok {
+				c = redfish.FirmwareComponent{Path: t, ID: path.Base(t)}
+			}
+			if belongsTo(c, sys) {
+				keep[t], found = true, true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%s: no target belongs to %s; 'firmware targets <host>' lists them", n, cmp.Or(sys.ODataID, sys.ID))
+		}
+	}
+	var out []string
+	for _, t := range targets {
+		if keep[t] {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+// belongsTo reports whether component c is firmware of system sys.
+func belongsTo(c redfish.FirmwareComponent, sys redfish.System) bool {
+	if len(c.RelatedItems) > 0 {
+		for _, r := range c.RelatedItems {
+			if sys.ODataID != "" && (r == sys.ODataID || strings.HasPrefix(r, sys.ODataID+"/")) {
+				return true
+			}
+		}
+		return false
+	}
+	id := cmp.Or(sys.ID, path.Base(sys.ODataID))
+	if id == "" {
+		return false
+	}
+	word := regexp.MustCompile(`(?i)(^|[^a-z0-9])` + regexp.QuoteMeta(id) + `([^a-z0-9]|$)`)
+	return word.MatchString(c.ID) || word.MatchString(c.Name)
+}
@@ -0,0 +1,305 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+	"text/tabwriter"
+
+	"bootstrap/internal/fwcatalog"
+	"bootstrap/internal/fwplan"
+	"bootstrap/internal/fwversion"
+	"bootstrap/internal/safefile"
+	"bootstrap/pkg/redfish"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	fpCatalog   string
+	fpImageBase string
+	fpOut       string
+	fpAll       bool
+	fpFormat    string
+)
+
+// Catalog plan actions
+const (
+	fpUpdate  = "update"
+	fpCurrent = "current"
+	fpNewer   = "newer" // the installed version is newer than the catalog's
+	fpNone    = "none"  // no catalog entry
+)
+
+// fwPlanRow is one FirmwareInventory component and its catalog image.
+type fwPlanRow struct {
+	Host      string `json:"host"`
+	Target    string `json:"target"`
+	Component string `json:"component"`
+	Current   string `json:"current"`
+	Version   string `json:"version,omitempty"`
+	ImageURI  string `json:"image_uri,omitempty"`
+	Action    string `json:"action"`
+	entry     int
+}
+
+var firmwarePlanCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Pick the image of every firmware component from a vendor catalog",
+	Long: `Match the updateable FirmwareInventory components of each BMC against a
+firmware catalog and report which need an update, with the image URI and
+version the catalog gives for the BMC's model, so --image-uri need not be looked
+up by hand.
+
+--catalog is a local path or an http(s) URL of a YAML/JSON catalog, a directory
+of them (one image or catalog per file), or a Dell Catalog.xml. Components match
+on their Id or Name (the catalog's component glob) or SoftwareId, and on the
+Model of the BMC's systems and managers. Relative image URIs are resolved
+against the catalog's base_uri (Dell: baseLocation) or URL; --image-base points
+them at a local mirror instead.
+
+--out writes the updates as a plan for 'firmware apply --plan', one phase per
+catalog image in catalog order. The hosts must then agree on the image of every
+component; plan different models separately.`,
+	Example: `  ochami_bootstrap firmware plan --catalog https://repo.example/fw/catalog.yaml -f inventory.yaml
+  ochami_bootstrap firmware plan --catalog Catalog.xml --image-base http://mirror.local/dell/ --hosts x1000c0s0b0 --out plan.yaml`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if fpCatalog == "" {
+			return invalidf("--catalog is required")
+		}
+		if fpFormat != "" && fpFormat != "json" && fpFormat != "csv" {
+			return invalidf("--format must be json or csv when set")
+		}
+		if fwFile == "" && fwHostsCSV == "" && fwHostsFile == "" {
+			return invalidf("at least one of --file, --hosts or --hosts-file is required")
+		}
+		if fwNodes != "" {
+			return invalidf("firmware plan does not take --nodes")
+		}
+		ctx := cmd.Context()
+		cat, err := fwcatalog.Load(ctx, nil, fpCatalog, fpImageBase)
+		if err != nil {
+			return invalid(err)
+		}
+		hosts, err := resolveHosts(fwFile, fwHostsCSV, fwHostsFile)
+		if err != nil {
+			return err
+		}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+		stopCache, err := useResponseCache()
+		if err != nil {
+			return err
+		}
+		defer stopCache()
+
+		var mu sync.Mutex
+		byHost := map[string][]fwPlanRow{}
+		failed := map[string]error{}
+		forEachHost(ctx, hosts, fwBatchSize, func(ctx context.Context, h string) {
+			rows, err := planHostFirmware(ctx, h, user, pass, cat)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", hostName(h), err)
+				failed[h] = err
+				return
+			}
+			byHost[h] = rows
+		}, func(string) {})
+
+		var rows []fwPlanRow
+		for _, h := range hosts {
+			rows = append(rows, byHost[h]...)
+		}
+		if err := printFirmwarePlan(rows, len(failed)); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+		if fpOut != "" {
+			if err := writeCatalogPlan(fpOut, cat, rows, len(byHost)); err != nil {
+				return err
+			}
+		}
+		return hostFailures(len(hosts), failed)
+	},
+}
+
+// planHostFirmware matches the updateable components of one BMC to the catalog.
+func planHostFirmware(ctx context.Context, host, user, pass string, cat fwcatalog.Catalog) ([]fwPlanRow, error) {
+	ctx, cancel := fwTimeouts.forHost(ctx)
+	defer cancel()
+	rf := newRedfishClient(host, user, pass, fwInsecure, fwTimeouts.Request)
+	comps, err := rf.ListFirmwareInventory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list FirmwareInventory: %w", err)
+	}
+	models, err := bmcModels(ctx, rf)
+	if err != nil {
+		return nil, err
+	}
+	var rows []fwPlanRow
+	for _, c := range comps {
+		if !c.Updateable {
+			continue
+		}
+		r := fwPlanRow{Host: host, Target: c.Path, Component: cmp.Or(c.ID, c.Name, c.Path), Current: c.Version, Action: fpNone, entry: cat.Match(c, models)}
+		if r.entry >= 0 {
+			e := cat.Entries[r.entry]
+			r.Version, r.ImageURI = e.Version, e.ImageURI
+			r.Action = fpUpdate
+			if n, err := fwversion.Compare(e.Version, c.Version); e.Version == c.Version || (err == nil && n == 0) {
+				r.Action = fpCurrent
+			} else if err == nil && n < 0 {
+				r.Action = fpNewer
+			}
+		}
+		rows = append(rows, r)
+	}
+	return rows, nil
+}
+
+// bmcModels returns the distinct models of the systems and managers of a BMC.
+func bmcModels(ctx context.Context, rf redfish.Client) ([]string, error) {
+	systems, err := rf.GetSystems(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list systems: %w", err)
+	}
+	managers, err := rf.GetManagers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list managers: %w", err)
+	}
+	var models []string
+	for _, s := range systems {
+		models = append(models, s.Model)
+	}
+	for _, m := range managers {
+		models = append(models, m.Model)
+	}
+	slices.Sort(models)
+	return slices.Compact(slices.DeleteFunc(models, func(m string) bool { return m == "" })), nil
+}
+
+// writeCatalogPlan writes the updates of rows as a firmware apply plan with one
+// phase per catalog entry. Every component with a catalog entry must be on
+// each of the hosts planned and resolve to the same entry there.
+func writeCatalogPlan(out string, cat fwcatalog.Catalog, rows []fwPlanRow, hosts int) error {
+	type target struct {
+		entries map[int]bool
+		hosts   int
+		update  bool
+	}
+	targets := map[string]*target{}
+	for _, r := range rows {
+		if r.entry < 0 {
+			continue
+		}
+		t := targets[r.Target]
+		if t == nil {
+			t = &target{entries: map[int]bool{}}
+			targets[r.Target] = t
+		}
+		t.entries[r.entry] = true
+		t.hosts++
+		t.update = t.update || r.Action == fpUpdate
+	}
+	phases := map[int]*fwplan.Phase{}
+	for _, path := range slices.Sorted(maps.Keys(targets)) {
+		t := targets[path]
+		if !t.update {
+			continue
+		}
+		if len(t.entries) > 1 {
+			return invalidf("--out: hosts need different images for %s; plan each model separately", path)
+		}
+		if t.hosts != hosts {
+			return invalidf("--out: %s is on %d of %d host(s); plan them separately", path, t.hosts, hosts)
+		}
+		for i := range t.entries {
+			if phases[i] == nil {
+				e := cat.Entries[i]
+				phases[i] = &fwplan.Phase{Name: cmp.Or(e.Name, e.Component, fmt.Sprintf("entry%d", i+1)), ImageURI: e.ImageURI, Version: e.Version, Reset: e.Reset}
+			}
+			phases[i].Targets = append(phases[i].Targets, path)
+		}
+	}
+	if len(phases) == 0 {
+		fmt.Println("Every component matches the catalog; no plan written")
+		return nil
+	}
+	var plan fwplan.Plan
+	for i := range cat.Entries {
+		if ph := phases[i]; ph != nil {
+			plan.Phases = append(plan.Phases, *ph)
+		}
+	}
+	b, err := yaml.Marshal(plan)
+	if err != nil {
+		return err
+	}
+	if err := safefile.Write(out, b, 0o644, 1); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %d phase(s) to %s; run 'firmware apply --plan %s'\n", len(plan.Phases), out, out)
+	return nil
+}
+
+func printFirmwarePlan(rows []fwPlanRow, failures int) error {
+	shown := make([]fwPlanRow, 0, len(rows))
+	counts := map[string]int{}
+	for _, r := range rows {
+		counts[r.Action]++
+		if r.Action != fpNone || fpAll {
+			shown = append(shown, r)
+		}
+	}
+	switch fpFormat {
+	case "json":
+		return printJSON(shown)
+	case "csv":
+		var out [][]string
+		for _, r := range shown {
+			out = append(out, []string{r.Host, r.Target, r.Component, r.Current, r.Version, r.ImageURI, r.Action})
+		}
+		return writeCSV(os.Stdout, []string{"host", "target", "component", "current", "version", "image_uri", "action"}, out)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "HOST\tCOMPONENT\tCURRENT\tCATALOG\tACTION\tIMAGE")
+	for _, r := range shown {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", hostName(r.Host), r.Component, cmp.Or(r.Current, "-"), cmp.Or(r.Version, "-"), r.Action, cmp.Or(r.ImageURI, "-"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Println("Firmware catalog summary:")
+	fmt.Printf("  update: %d\n", counts[fpUpdate])
+	fmt.Printf("  current: %d\n", counts[fpCurrent])
+	fmt.Printf("  newer installed: %d\n", counts[fpNewer])
+	fmt.Printf("  no catalog entry: %d\n", counts[fpNone])
+	fmt.Printf("  errors: %d\n", failures)
+	return nil
+}
+
+func init() {
+	firmwareCmd.AddCommand(firmwarePlanCmd)
+	firmwarePlanCmd.Flags().StringVar(&fpCatalog, "catalog", "", "firmware catalog: path or http(s) URL of a YAML/JSON catalog, a directory of them, or a Dell Catalog.xml")
+	firmwarePlanCmd.Flags().StringVar(&fpImageBase, "image-base", "", "base URL for relative image paths, overriding the catalog's (e.g. a local mirror)")
+	firmwarePlanCmd.Flags().StringVar(&fpOut, "out", "", "write the updates as a plan file for 'firmware apply --plan'")
+	firmwarePlanCmd.Flags().BoolVar(&fpAll, "all", false, "also list components without a catalog entry")
+	firmwarePlanCmd.Flags().StringVar(&fpFormat, "format", "", "output format: json or csv (default: table)")
+	addCacheFlags(firmwarePlanCmd)
+}
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"bootstrap/internal/fwplan"
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+)
+
+func TestFirmwarePlan(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	dir := t.TempDir()
+	catalog := filepath.Join(dir, "catalog.yaml")
+	if err := os.WriteFile(catalog, []byte(`base_uri: http://repo.example/fw/
+components:
+  - name: nc
+    component: BMC
+    version: nc.1.10.1
+    image_uri: nc-1.10.1.tar.gz
+    reset: manager
+  - name: bios
+    component: "Node*.BIOS"
+    models: ["EX425"]
+    version: "1.2.0"
+    image_uri: bios-1.2.0.bin
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	blade := func(nc string) *redfishtest.MockClient {
+		return &redfishtest.MockClient{
+			ListFirmwareInventoryFunc: func(context.Context) ([]redfish.FirmwareComponent, error) {
+				return []redfish.FirmwareComponent{
+					{Path: "/redfish/v1/UpdateService/FirmwareInventory/BMC", ID: "BMC", Version: nc, Updateable: true},
+					{Path: "/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS", ID: "Node0.BIOS", Version: "1.2.0", Updateable: true},
+					{Path: "/redfish/v1/UpdateService/FirmwareInventory/CPLD", ID: "CPLD", Version: "3", Updateable: true},
+				}, nil
+			},
+			GetSystemsFunc:  func(context.Context) ([]redfish.System, error) { return []redfish.System{{Model: "EX425"}}, nil },
+			GetManagersFunc: func(context.Context) ([]redfish.Manager, error) { return []redfish.Manager{{Model: "nC"}}, nil },
+		}
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"a": blade("nc.1.9.8"), "b": blade("nc.1.10.1")})
+	out := filepath.Join(dir, "plan.yaml")
+	defer func() { fwHostsCSV, fpCatalog, fpOut, fpFormat = "", "", "", "" }()
+	fwHostsCSV, fpCatalog, fpOut, fpFormat = "a,b", catalog, out, "json"
+	firmwarePlanCmd.SetContext(context.Background())
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := firmwarePlanCmd.RunE(firmwarePlanCmd, nil)
+	w.Close() //nolint:errcheck
+	os.Stdout = old
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(r)
+	dec := json.NewDecoder(bytes.NewReader(b))
+	var rows []fwPlanRow
+	if err := dec.Decode(&rows); err != nil {
+		t.Fatalf("%v: %s", err, b)
+	}
+	var actions []string
+	for _, r := range rows {
+		actions = append(actions, r.Host+" "+r.Component+" "+r.Action)
+	}
+	if want := []string{"a BMC update", "a Node0.BIOS current", "b BMC current", "b Node0.BIOS current"}; !reflect.DeepEqual(actions, want) {
+		t.Errorf("actions = %v, want %v", actions, want)
+	}
+	if rows[0].ImageURI != "http://repo.example/fw/nc-1.10.1.tar.gz" || rows[0].Version != "nc.1.10.1" {
+		t.Errorf("row = %+v", rows[0])
+	}
+
+	// The plan only has the BMC phase; firmware apply skips b, already at the version
+	plan, err := fwplan.Load(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ph := plan.Phases[0]
+	if len(plan.Phases) != 1 || ph.Name != "nc" || ph.Version != "nc.1.10.1" || ph.Reset != fwplan.ResetManager ||
+		!reflect.DeepEqual(ph.Targets, []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}) {
+		t.Errorf("plan = %+v", plan)
+	}
+}
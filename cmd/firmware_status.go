@@ -8,276 +8,422 @@
 package cmd
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"path"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
-	"bootstrap/internal/inventory"
-	"bootstrap/internal/redfish"
+	"bootstrap/internal/junit"
+	"bootstrap/internal/statusdb"
+	"bootstrap/internal/xname"
+	"bootstrap/pkg/redfish"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var (
 	// reuse firmware flags (made persistent)
 	fwStatusInterval time.Duration
 	fwFormat         string
+	fwRegistryDir    string
+	fwMinSeverity    string
+	fwFailOn         string
+	fwStats          bool
 )
 
 var firmwareStatusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Query BMC firmware versions and in-progress updates",
 	RunE: func(cmd *cobra.Command, args []string) error { // nolint:revive
-		user := os.Getenv("REDFISH_USER")
-		pass := os.Getenv("REDFISH_PASSWORD")
-		if user == "" || pass == "" {
-			return errors.New("REDFISH_USER and REDFISH_PASSWORD env vars are required")
-		}
-
-		// Determine hosts to target (reuse logic from firmware.go)
-		hosts := []string{}
-		if strings.TrimSpace(fwHostsCSV) != "" {
-			for _, h := range strings.Split(fwHostsCSV, ",") {
-				h = strings.TrimSpace(h)
-				if h != "" {
-					hosts = append(hosts, h)
-				}
-			}
-		} else {
-			raw, err := os.ReadFile(fwFile)
-			if err != nil {
-				return err
-			}
-			var doc inventory.FileFormat
-			if err := yaml.Unmarshal(raw, &doc); err != nil {
-				return err
-			}
-			if len(doc.BMCs) == 0 {
-				return fmt.Errorf("input must contain non-empty bmcs[]")
-			}
-			for _, b := range doc.BMCs {
-				host := b.IP
-				if host == "" {
-					host = b.Xname
-				}
-				hosts = append(hosts, host)
-			}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+		stopCache, err := useResponseCache()
+		if err != nil {
+			return err
+		}
+		defer stopCache()
+		if fwStats {
+			redfish.EnableStats()
+			defer printRequestStats(os.Stderr)
+		}
+
+		// Determine hosts to target (same rules as firmware)
+		hosts, err := firmwareHosts()
+		if err != nil {
+			return err
 		}
 
 		if len(hosts) == 0 {
-			return fmt.Errorf("no hosts to query")
+			return invalidf("no hosts to query")
 		}
 
-		// Determine targets. Honor --targets if provided, otherwise use --type like the update command.
-		targets := fwTargets
-		if len(targets) == 0 {
-			typeName := fwType
-			if strings.TrimSpace(typeName) == "" {
-				// default to bmc when not specified
-				typeName = "bmc"
-			}
-			var err error
-			targets, err = defaultTargets(typeName)
-			if err != nil {
-				return err
+		// Targets are --targets, or else the components matching --type on each host.
+		typeName := cmp.Or(strings.TrimSpace(fwType), "bmc")
+		if len(fwTargets) == 0 {
+			if _, err := firmwareType(typeName); err != nil {
+				return invalid(err)
 			}
 		}
 
+		format := strings.ToLower(fwFormat)
+		if format != "" && format != "json" && format != "csv" && format != "junit" {
+			return invalidf("--format must be json, csv or junit when set")
+		}
+		minSeverity := severityRank(fwMinSeverity)
+		if minSeverity < 0 {
+			return invalidf("--min-severity must be ok, warning or critical, got %q", fwMinSeverity)
+		}
+		failOn := fwFailOn
+		switch failOn {
+		case "", "error", "warning", "in-progress":
+		default:
+			return invalidf("--fail-on must be error, warning or in-progress, got %q", failOn)
+		}
+
+		regs, err := redfish.NewRegistries(fwRegistryDir)
+		if err != nil {
+			return invalidf("--registry-dir: %v", err)
+		}
+		msgs := &messageResolver{regs: regs, tried: map[string]bool{}}
+
+		bmcs, err := loadBMCs(fwFile)
+		if err != nil {
+			return err
+		}
+		xnames := hostXnames(hosts, bmcs)
+
 		// Results aggregation
 		var mu sync.Mutex
-		versionCounts := map[string]int{}
 		inProgress := int32(0)
 		errorsList := map[string]string{}
+		progress := map[string][]redfish.UpdateProgress{} // running update tasks by host
+		queryErrs := map[string]error{}                   // hosts whose Redfish queries failed
 
 		// Collect per-target summaries for JSON output
 		type hostSummary struct {
 			Host             string `json:"host"`
+			Alias            string `json:"alias,omitempty"`
+			Xname            string `json:"xname,omitempty"`
+			Chassis          string `json:"chassis,omitempty"`
 			Target           string `json:"target"`
 			ObservedVersion  string `json:"observed_version"`
 			RequestedVersion string `json:"requested_version,omitempty"`
-			Status           string `json:"status"` // one of: in-progress, error, idle
+			Status           string `json:"status"`             // one of: in-progress, error, idle
+			Severity         string `json:"severity,omitempty"` // worst severity behind Error: Warning or Critical
 			Error            string `json:"error,omitempty"`
+			PercentComplete  *int   `json:"percent_complete,omitempty"` // of the least advanced update task, while in progress
+			Phase            string `json:"phase,omitempty"`            // downloading, verifying or installing, while in progress
 		}
 		var hostSummaries []hostSummary
+		multiTarget := false // some host has more than one target
 
-		sem := make(chan struct{}, max(1, fwBatchSize))
-		var wg sync.WaitGroup
-		for _, host := range hosts {
-			wg.Add(1)
-			h := host
-			go func() {
-				defer wg.Done()
-				sem <- struct{}{}
-				defer func() { <-sem }()
-
-				ctx := cmd.Context()
-				if fwTimeout > 0 {
-					var cancel context.CancelFunc
-					ctx, cancel = context.WithTimeout(ctx, fwTimeout)
-					defer cancel()
-				}
+		start := time.Now()
+		runCtx, cancel := fwTimeouts.forCommand(cmd.Context())
+		defer cancel()
+		forEachHost(runCtx, hosts, fwBatchSize, func(runCtx context.Context, h string) {
+			ctx, cancel := fwTimeouts.forHost(runCtx)
+			defer cancel()
 
-				// Check UpdateService first (preferred source for overall update activity)
-				var perr string
-				var anyInProgress bool
-				us, err := redfish.GetUpdateServiceStatus(ctx, h, user, pass, fwInsecure, fwTimeout)
-				if err == nil {
-					health := strings.ToLower(us.Health)
-					state := strings.ToLower(us.State)
-					if health != "ok" {
-						// collect condition messages as errors
-						for _, c := range us.Conditions {
-							if c.MessageID != "" {
-								if perr == "" {
-									perr = fmt.Sprintf("%s (%s)", c.MessageID, c.Message)
-								} else {
-									perr = perr + "; " + fmt.Sprintf("%s (%s)", c.MessageID, c.Message)
-								}
-							} else {
-								if perr == "" {
-									perr = c.Message
-								} else {
-									perr = perr + "; " + c.Message
-								}
-							}
+			rf := newRedfishClient(h, user, pass, fwInsecure, fwTimeouts.Request)
+
+			// Check UpdateService first (preferred source for overall update activity)
+			var perr, perrSeverity string
+			var anyInProgress bool
+			us, err := rf.GetUpdateServiceStatus(ctx)
+			if err == nil {
+				health := strings.ToLower(us.Health)
+				state := strings.ToLower(us.State)
+				if health != "ok" {
+					// collect condition messages as errors
+					for _, c := range us.Conditions {
+						sev := msgs.severity(ctx, h, rf, c.MessageID, c.Severity)
+						if severityRank(sev) < minSeverity {
+							continue
 						}
-					} else if state == "updating" {
-						anyInProgress = true
+						perr = joinErr(perr, msgs.text(ctx, h, rf, c.MessageID, c.Message, c.MessageArgs))
+						perrSeverity = worseSeverity(perrSeverity, sev)
 					}
+				} else if state == "updating" {
+					anyInProgress = true
 				}
+			}
 
-				// If UpdateService and inventory did not indicate progress, check TaskService for running jobs
-				if !anyInProgress {
-					if tasks, err := redfish.GetActiveUpdateTasks(ctx, h, user, pass, fwInsecure, fwTimeout); err == nil {
-						if len(tasks) > 0 {
-							anyInProgress = true
-						}
-					}
+			// Running update tasks in TaskService show updates the UpdateService
+			// does not, and how far they have come
+			tasks, _ := rf.GetUpdateProgress(ctx)
+			if len(tasks) > 0 {
+				anyInProgress = true
+				mu.Lock()
+				progress[h] = tasks
+				mu.Unlock()
+			}
+			percent, phase := leastProgress(tasks)
+
+			targets := fwTargets
+			if len(targets) == 0 {
+				if targets, err = typeTargets(ctx, rf, typeName); err != nil {
+					mu.Lock()
+					queryErrs[h] = err
+					errorsList[hostName(h)] = err.Error()
+					hostSummaries = append(hostSummaries, hostSummary{Host: h, Alias: hostAlias(h), Xname: xnames[h], Chassis: xname.Chassis(xnames[h]),
+						ObservedVersion: "(unknown)", RequestedVersion: fwExpectedVersion, Status: "error", Error: err.Error()})
+					mu.Unlock()
+					return
 				}
+			}
+			if nodes := hostNodes(h); nodes != nil {
+				if targets, err = nodeFirmwareTargets(ctx, rf, targets, nodes); err != nil {
+					mu.Lock()
+					queryErrs[h] = err
+					errorsList[hostName(h)] = err.Error()
+					hostSummaries = append(hostSummaries, hostSummary{Host: h, Alias: hostAlias(h), Xname: xnames[h], Chassis: xname.Chassis(xnames[h]),
+						ObservedVersion: "(unknown)", RequestedVersion: fwExpectedVersion, Status: "error", Error: err.Error()})
+					mu.Unlock()
+					return
+				}
+			}
+			if len(targets) > 1 {
+				mu.Lock()
+				multiTarget = true
+				mu.Unlock()
+			}
 
-				// Query each target separately and record per-target summaries
-				for _, target := range targets {
-					var perrTarget string
-					var verTarget string
-					var anyInProgressTarget bool
+			// Fetch every target over the host's one client and record per-target summaries
+			invs, invErr := rf.GetFirmwareInventories(ctx, targets, len(targets))
+			var targetErrs redfish.TargetErrors
+			errors.As(invErr, &targetErrs)
+			for _, target := range targets {
+				var perrTarget, severity string
+				var verTarget string
+				var anyInProgressTarget bool
 
-					inv, err := redfish.GetFirmwareInventory(ctx, h, user, pass, fwInsecure, fwTimeout, target)
-					if err != nil {
-						perrTarget = err.Error()
-					} else {
-						verTarget = inv.Version
-						// If the inventory reports a non-OK Health, treat as error and include conditions
-						if strings.ToLower(inv.Health) != "" && !strings.EqualFold(inv.Health, "OK") {
-							if len(inv.Conditions) > 0 {
-								for _, c := range inv.Conditions {
-									if c.MessageID != "" {
-										if perrTarget == "" {
-											perrTarget = fmt.Sprintf("%s (%s)", c.MessageID, c.Message)
-										} else {
-											perrTarget = perrTarget + "; " + fmt.Sprintf("%s (%s)", c.MessageID, c.Message)
-										}
-									} else {
-										if perrTarget == "" {
-											perrTarget = c.Message
-										} else {
-											perrTarget = perrTarget + "; " + c.Message
-										}
-									}
+				inv, ok := invs[target]
+				err := targetErrs[target]
+				if !ok && err == nil {
+					err = cmp.Or(invErr, fmt.Errorf("%s not fetched", target))
+				}
+				if err != nil {
+					perrTarget = err.Error()
+					mu.Lock()
+					queryErrs[h] = err
+					mu.Unlock()
+				} else {
+					verTarget = inv.Version
+					// If the inventory reports a non-OK Health, treat as error and include conditions
+					if strings.ToLower(inv.Health) != "" && !strings.EqualFold(inv.Health, "OK") {
+						if len(inv.Conditions) > 0 {
+							for _, c := range inv.Conditions {
+								sev := msgs.severity(ctx, h, rf, c.MessageID, c.Severity)
+								if severityRank(sev) < minSeverity {
+									continue
 								}
-							} else {
-								perrTarget = fmt.Sprintf("health: %s", inv.Health)
+								perrTarget = joinErr(perrTarget, msgs.text(ctx, h, rf, c.MessageID, c.Message, c.MessageArgs))
+								severity = worseSeverity(severity, sev)
 							}
+						} else if sev := worseSeverity("Warning", inv.Health); severityRank(sev) >= minSeverity {
+							perrTarget = fmt.Sprintf("health: %s", inv.Health)
+							severity = worseSeverity(severity, sev)
 						}
+					}
 
-						st := strings.ToLower(inv.State)
-						if st != "" && st != "enabled" && st != "ok" {
-							anyInProgressTarget = true
-						}
-						for _, c := range inv.Conditions {
-							m := strings.ToLower(c.Message)
-							if c.Severity == "Critical" || strings.Contains(m, "failed") || strings.Contains(m, "error") {
-								if c.MessageID != "" {
-									if perrTarget == "" {
-										perrTarget = fmt.Sprintf("%s (%s)", c.MessageID, c.Message)
-									} else {
-										perrTarget = perrTarget + "; " + fmt.Sprintf("%s (%s)", c.MessageID, c.Message)
-									}
-								} else {
-									if perrTarget == "" {
-										perrTarget = c.Message
-									} else {
-										perrTarget = perrTarget + "; " + c.Message
-									}
-								}
-								continue
-							}
-							if strings.Contains(m, "in progress") || strings.Contains(m, "install") || strings.Contains(m, "installing") || strings.Contains(m, "running") || strings.Contains(m, "downloading") || strings.Contains(m, "download in progress") {
-								anyInProgressTarget = true
+					st := strings.ToLower(inv.State)
+					if st != "" && st != "enabled" && st != "ok" {
+						anyInProgressTarget = true
+					}
+					for _, c := range inv.Conditions {
+						m := strings.ToLower(c.Message)
+						if c.Severity == "Critical" || strings.Contains(m, "failed") || strings.Contains(m, "error") {
+							if sev := msgs.severity(ctx, h, rf, c.MessageID, c.Severity); severityRank(sev) >= minSeverity {
+								perrTarget = joinErr(perrTarget, msgs.text(ctx, h, rf, c.MessageID, c.Message, c.MessageArgs))
+								severity = worseSeverity(severity, sev)
 							}
+							continue
+						}
+						if strings.Contains(m, "in progress") || strings.Contains(m, "install") || strings.Contains(m, "installing") || strings.Contains(m, "running") || strings.Contains(m, "downloading") || strings.Contains(m, "download in progress") {
+							anyInProgressTarget = true
 						}
 					}
+				}
 
-					// Determine observed version fallback
-					if verTarget == "" {
-						verTarget = "(unknown)"
-					}
+				// Determine observed version fallback
+				if verTarget == "" {
+					verTarget = "(unknown)"
+				}
 
-					// Build status for this target: combine host-level and target-level info
-					status := "idle"
-					// perr (host-level) may have been set from UpdateService; include it
-					combinedErr := perr
-					if perrTarget != "" {
-						if combinedErr == "" {
-							combinedErr = perrTarget
-						} else {
-							combinedErr = combinedErr + "; " + perrTarget
-						}
-					}
-					if combinedErr != "" {
-						status = "error"
-					} else if anyInProgress || anyInProgressTarget {
-						status = "in-progress"
+				// Build status for this target: combine host-level and target-level info
+				status := "idle"
+				// perr (host-level) may have been set from UpdateService; include it
+				combinedErr := perr
+				severity = worseSeverity(severity, perrSeverity)
+				if perrTarget != "" {
+					if combinedErr == "" {
+						combinedErr = perrTarget
+					} else {
+						combinedErr = combinedErr + "; " + perrTarget
 					}
+				}
+				if combinedErr != "" {
+					status = "error"
+				} else if anyInProgress || anyInProgressTarget {
+					status = "in-progress"
+				}
 
-					// Update aggregates and per-target list
-					mu.Lock()
-					versionCounts[verTarget]++
-					if combinedErr != "" {
-						// use host+target key so multiple targets per host are visible
-						errorsList[fmt.Sprintf("%s %s", h, target)] = combinedErr
-					}
-					if status == "in-progress" {
-						atomic.AddInt32(&inProgress, 1)
-					}
-					hostSummaries = append(hostSummaries, hostSummary{
-						Host:             h,
-						Target:           target,
-						ObservedVersion:  verTarget,
-						RequestedVersion: fwExpectedVersion,
-						Status:           status,
-						Error:            combinedErr,
-					})
-					mu.Unlock()
+				// Update aggregates and per-target list
+				mu.Lock()
+				if combinedErr != "" {
+					// use host+target key so multiple targets per host are visible
+					errorsList[fmt.Sprintf("%s %s", hostName(h), target)] = combinedErr
+				}
+				var tgtPercent *int
+				var tgtPhase string
+				if status == "in-progress" {
+					atomic.AddInt32(&inProgress, 1)
+					tgtPercent, tgtPhase = percent, phase
+				}
+				hostSummaries = append(hostSummaries, hostSummary{
+					Host:             h,
+					Alias:            hostAlias(h),
+					Xname:            xnames[h],
+					Chassis:          xname.Chassis(xnames[h]),
+					Target:           target,
+					ObservedVersion:  verTarget,
+					RequestedVersion: fwExpectedVersion,
+					Status:           status,
+					Severity:         severity,
+					Error:            combinedErr,
+					PercentComplete:  tgtPercent,
+					Phase:            tgtPhase,
+				})
+				mu.Unlock()
+			}
+		}, func(string) {})
+		queried := map[string]bool{}
+		for _, hs := range hostSummaries {
+			queried[hs.Host] = true
+		}
+		if fwFailedOut != "" {
+			retry := maps.Clone(queryErrs)
+			for _, h := range hosts {
+				if !queried[h] {
+					retry[h] = errors.New("not queried: interrupted")
+				}
+			}
+			if err := writeFailedHosts(fwFailedOut, hosts, retry); err != nil {
+				return err
+			}
+		}
+		if runCtx.Err() != nil {
+			fmt.Fprintf(os.Stderr, "Interrupted: %d of %d host(s) queried before cancel\n", len(queried), len(hosts))
+			return fwTimeouts.stopped(runCtx)
+		}
+
+		// --state-db keeps the results and reports what changed since the last
+		// run; beside JSON or CSV the report goes to stderr
+		history := func(w io.Writer) error {
+			if fwStateDB == "" {
+				return nil
+			}
+			run := statusdb.Run{Time: start.UTC()}
+			for _, hs := range hostSummaries {
+				run.Results = append(run.Results, statusdb.Result{Host: hs.Host, Xname: hs.Xname, Target: hs.Target, Version: hs.ObservedVersion,
+					Status: hs.Status, Error: hs.Error, PercentComplete: hs.PercentComplete})
+			}
+			return recordStatusRun(w, run)
+		}
+
+		// Query failures decide the exit status; after them, --fail-on does.
+		result := func() error {
+			if err := hostFailures(len(hosts), queryErrs); err != nil {
+				return err
+			}
+			failing := 0
+			for _, hs := range hostSummaries {
+				if failsOn(failOn, hs.Status, hs.Severity) {
+					failing++
 				}
-			}()
+			}
+			if failing > 0 {
+				return &exitError{code: exitPartial, err: fmt.Errorf("%d target(s) at or above --fail-on %s", failing, failOn)}
+			}
+			return nil
 		}
-		wg.Wait()
 
-		// JSON format option
-		if strings.EqualFold(fwFormat, "json") {
+		if format == "csv" || format == "junit" {
+			// hosts in the order given, each with its targets in the order queried
+			hostIdx := map[string]int{}
+			for i, h := range hosts {
+				hostIdx[h] = i
+			}
+			slices.SortStableFunc(hostSummaries, func(a, b hostSummary) int {
+				return cmp.Compare(hostIdx[a.Host], hostIdx[b.Host])
+			})
+		}
+		switch format {
+		case "junit":
+			// one test case per host and target; errors and --fail-on fail it
+			suite := junit.Suite{Name: "firmware status", Time: start, Duration: time.Since(start)}
+			for _, hs := range hostSummaries {
+				c := junit.Case{Class: cmp.Or(hs.Alias, hs.Xname, hs.Host), Name: cmp.Or(hs.Target, typeName), Output: "version " + hs.ObservedVersion}
+				if hs.RequestedVersion != "" {
+					c.Output += ", expected " + hs.RequestedVersion
+				}
+				if hs.Status == "in-progress" {
+					c.Output += ", update " + progressText(redfish.UpdateProgress{PercentComplete: hs.PercentComplete, Phase: hs.Phase}, time.Time{})
+				}
+				switch {
+				case hs.Status == "error":
+					c.Failure, c.Type = hs.Error, cmp.Or(hs.Severity, "error")
+				case failsOn(failOn, hs.Status, hs.Severity):
+					c.Failure, c.Type = "update in progress", hs.Status
+				}
+				suite.Cases = append(suite.Cases, c)
+			}
+			if err := junit.Write(os.Stdout, suite); err != nil {
+				return err
+			}
+			if err := history(os.Stderr); err != nil {
+				return err
+			}
+			return result()
+		case "csv":
+			rows := make([][]string, 0, len(hostSummaries))
+			for _, hs := range hostSummaries {
+				pct := ""
+				if hs.PercentComplete != nil {
+					pct = strconv.Itoa(*hs.PercentComplete)
+				}
+				rows = append(rows, []string{hs.Host, hs.Alias, hs.Xname, hs.Chassis, hs.Target, hs.ObservedVersion, hs.RequestedVersion, hs.Status, hs.Severity, hs.Error, pct, hs.Phase})
+			}
+			if err := writeCSV(os.Stdout, []string{"host", "alias", "xname", "chassis", "target", "observed_version", "requested_version", "status", "severity", "error", "percent_complete", "phase"}, rows); err != nil {
+				return err
+			}
+			if err := history(os.Stderr); err != nil {
+				return err
+			}
+			return result()
+		case "json":
 			out, err := json.MarshalIndent(hostSummaries, "", "  ")
 			if err != nil {
 				return err
 			}
 			fmt.Println(string(out))
-			return nil
+			if err := history(os.Stderr); err != nil {
+				return err
+			}
+			return result()
 		}
 
 		// Print human-readable summary
@@ -290,23 +436,266 @@ var firmwareStatusCmd = &cobra.Command{
 			fmt.Printf("  Total hosts: %d\n", len(hosts))
 		}
 		fmt.Printf("  In-progress updates: %d\n", atomic.LoadInt32(&inProgress))
-		fmt.Println("  Versions:")
-		for v, c := range versionCounts {
-			fmt.Printf("    %s: %d\n", v, c)
+		updating := map[string]bool{}
+		for _, hs := range hostSummaries {
+			if hs.Status == "in-progress" {
+				updating[hs.Host] = true
+			}
+		}
+		now := time.Now()
+		for _, h := range hosts {
+			if !updating[h] {
+				continue
+			}
+			label := cmp.Or(hostAlias(h), xnames[h], h)
+			if len(progress[h]) == 0 {
+				fmt.Printf("    %s: in progress (no update task reported)\n", label)
+			}
+			for _, p := range progress[h] {
+				fmt.Printf("    %s: %s\n", label, progressText(p, now))
+			}
+		}
+		rows := make([]versionRow, 0, len(hostSummaries))
+		for _, hs := range hostSummaries {
+			label := cmp.Or(hs.Alias, hs.Xname, hs.Host)
+			if multiTarget && hs.Target != "" {
+				label += " " + path.Base(hs.Target)
+			}
+			rows = append(rows, versionRow{Label: label, Chassis: hs.Chassis, Version: hs.ObservedVersion})
 		}
+		printVersions(os.Stdout, rows, fwExpectedVersion)
 		if len(errorsList) > 0 {
 			fmt.Println("  Errors:")
 			for h, e := range errorsList {
 				fmt.Printf("    %s: %s\n", h, e)
 			}
 		}
+		if err := history(os.Stdout); err != nil {
+			return err
+		}
 
-		return nil
+		return result()
 	},
 }
 
 func init() {
 	firmwareCmd.AddCommand(firmwareStatusCmd)
 	firmwareStatusCmd.Flags().DurationVar(&fwStatusInterval, "interval", 5*time.Second, "poll interval (not used in single-run summary, reserved for future watch command)")
-	firmwareStatusCmd.Flags().StringVar(&fwFormat, "format", "", "output format: json")
+	firmwareStatusCmd.Flags().StringVar(&fwFormat, "format", "", "output format: json, csv (one row per host and target) or junit (one test case per host and target, for CI)")
+	addCacheFlags(firmwareStatusCmd)
+	firmwareStatusCmd.Flags().StringVar(&fwMinSeverity, "min-severity", "ok", "ignore status conditions below this severity: ok, warning or critical")
+	firmwareStatusCmd.Flags().StringVar(&fwFailOn, "fail-on", "", "exit 2 when a target reports a critical error (error), any warning or error (warning), or that or an update in progress (in-progress)")
+	firmwareStatusCmd.Flags().BoolVar(&fwStats, "stats", false, "print per-BMC Redfish request latency (p50/p95/max) and error counts to stderr at the end, slowest first")
+	firmwareStatusCmd.Flags().StringVar(&fwStateDB, "state-db", "", "append the results to this database and report changes since the last run: version changes, new errors, recoveries and stuck updates")
+	firmwareStatusCmd.Flags().DurationVar(&fwStuckAfter, "stuck-after", 2*time.Hour, "with --state-db, report targets in progress across runs for longer than this as stuck; 0 to disable")
+	firmwareStatusCmd.Flags().StringVar(&fwRegistryDir, "registry-dir", "", "directory of Redfish message registry JSON files used to explain condition MessageIds, in addition to the bundled DMTF ones")
+}
+
+// leastProgress returns the PercentComplete and phase of the least advanced of
+// tasks; a task without PercentComplete counts as least advanced.
+func leastProgress(tasks []redfish.UpdateProgress) (*int, string) {
+	if len(tasks) == 0 {
+		return nil, ""
+	}
+	least := tasks[0]
+	for _, t := range tasks[1:] {
+		if least.PercentComplete != nil && (t.PercentComplete == nil || *t.PercentComplete < *least.PercentComplete) {
+			least = t
+		}
+	}
+	return least.PercentComplete, least.Phase
+}
+
+// progressText describes a running update task, e.g. "installing 45%, task 7
+// started 12m ago".
+func progressText(p redfish.UpdateProgress, now time.Time) string {
+	s := cmp.Or(p.Phase, "in progress")
+	if p.PercentComplete != nil {
+		s += fmt.Sprintf(" %d%%", *p.PercentComplete)
+	} else {
+		s += ", percent unknown"
+	}
+	if p.Task != "" {
+		s += ", task " + path.Base(p.Task)
+	}
+	if !p.StartTime.IsZero() {
+		s += fmt.Sprintf(" started %s ago", now.Sub(p.StartTime).Truncate(time.Second))
+	}
+	return s
+}
+
+// messageResolver turns condition MessageIds into registry text. A registry
+// missing from the bundled and --registry-dir ones is asked of each BMC once.
+type messageResolver struct {
+	regs  *redfish.Registries
+	mu    sync.Mutex
+	tried map[string]bool // host + " " + MessageId registry prefix
+}
+
+// load adds the registry of id from the BMC if it is not known yet.
+func (m *messageResolver) load(ctx context.Context, host string, rf redfish.Client, id string) {
+	if id == "" || m.regs.Has(id) {
+		return
+	}
+	prefix, _, _ := strings.Cut(id, ".")
+	key := host + " " + prefix
+	m.mu.Lock()
+	tried := m.tried[key]
+	m.tried[key] = true
+	m.mu.Unlock()
+	if tried {
+		return
+	}
+	if reg, err := rf.GetMessageRegistry(ctx, id); err == nil {
+		m.regs.Add(reg)
+	}
+}
+
+// text describes a condition: "MessageId: message (resolution: ...)" when its
+// registry is known, else "MessageId (Message)" as the BMC sent it.
+func (m *messageResolver) text(ctx context.Context, host string, rf redfish.Client, id, msg string, args []string) string {
+	if id == "" {
+		return msg
+	}
+	m.load(ctx, host, rf, id)
+	if r, ok := m.regs.Resolve(id, args); ok {
+		return r.String()
+	}
+	return fmt.Sprintf("%s (%s)", id, msg)
+}
+
+// severity is the Severity a condition reports, else that of its registry
+// message, else Warning.
+func (m *messageResolver) severity(ctx context.Context, host string, rf redfish.Client, id, sev string) string {
+	if sev != "" {
+		return sev
+	}
+	m.load(ctx, host, rf, id)
+	if r, ok := m.regs.Resolve(id, nil); ok && r.Severity != "" {
+		return r.Severity
+	}
+	return "Warning"
+}
+
+// severityRank orders Redfish severities (and health values): OK 0, Warning 1,
+// Critical 2, and -1 for anything else.
+func severityRank(s string) int {
+	switch strings.ToLower(s) {
+	case "ok":
+		return 0
+	case "warning":
+		return 1
+	case "critical":
+		return 2
+	}
+	return -1
+}
+
+// worseSeverity returns the more severe of a and b.
+func worseSeverity(a, b string) string {
+	if severityRank(b) > severityRank(a) {
+		return b
+	}
+	return a
+}
+
+// versionRow is one target of the firmware status summary.
+type versionRow struct {
+	Label   string // alias, xname or host, plus the target when several are queried
+	Chassis string // "" when the BMC's xname is unknown
+	Version string
+}
+
+// outlierLimit caps the hosts listed per outlying version.
+const outlierLimit = 20
+
+// printVersions prints the version counts, the versions found in each chassis
+// and, per version other than the expected one (by default the most common),
+// the targets running it.
+func printVersions(w io.Writer, rows []versionRow, expected string) {
+	counts := map[string]int{}
+	byChassis := map[string]map[string]int{}
+	for _, r := range rows {
+		counts[r.Version]++
+		ch := cmp.Or(r.Chassis, "(no xname)")
+		if byChassis[ch] == nil {
+			byChassis[ch] = map[string]int{}
+		}
+		byChassis[ch][r.Version]++
+	}
+	versions := slices.SortedFunc(maps.Keys(counts), func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+	if expected == "" && len(versions) > 0 {
+		expected = versions[0]
+	}
+
+	fmt.Fprintln(w, "  Versions:")
+	for _, v := range versions {
+		fmt.Fprintf(w, "    %s: %d\n", v, counts[v])
+	}
+	if len(byChassis) > 1 || len(versions) > 1 {
+		fmt.Fprintln(w, "  Versions by chassis:")
+		for _, ch := range slices.Sorted(maps.Keys(byChassis)) {
+			var parts []string
+			for _, v := range versions {
+				if n := byChassis[ch][v]; n > 0 {
+					parts = append(parts, fmt.Sprintf("%s (%d)", v, n))
+				}
+			}
+			mark := ""
+			if len(parts) > 1 || byChassis[ch][expected] == 0 {
+				mark = "  <-"
+			}
+			fmt.Fprintf(w, "    %s: %s%s\n", ch, strings.Join(parts, ", "), mark)
+		}
+	}
+
+	outliers := map[string][]string{}
+	for _, r := range rows {
+		if r.Version != expected {
+			outliers[r.Version] = append(outliers[r.Version], r.Label)
+		}
+	}
+	if len(outliers) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "  Not at %s:\n", expected)
+	for _, v := range versions {
+		labels := outliers[v]
+		if len(labels) == 0 {
+			continue
+		}
+		slices.Sort(labels)
+		more := ""
+		if len(labels) > outlierLimit {
+			more = fmt.Sprintf(" (+%d more)", len(labels)-outlierLimit)
+			labels = labels[:outlierLimit]
+		}
+		fmt.Fprintf(w, "    %s: %s%s\n", v, strings.Join(labels, ", "), more)
+	}
+}
+
+// failsOn reports whether a target's status and severity meet --fail-on.
+func failsOn(failOn, status, severity string) bool {
+	switch failOn {
+	case "in-progress":
+		if status == "in-progress" {
+			return true
+		}
+		fallthrough
+	case "warning":
+		return status == "error" && severityRank(severity) >= 1
+	case "error":
+		return status == "error" && severityRank(severity) >= 2
+	}
+	return false
+}
+
+// joinErr appends msg to a "; "-separated list of errors.
+func joinErr(list, msg string) string {
+	if list == "" {
+		return msg
+	}
+	return list + "; " + msg
 }
@@ -6,15 +6,22 @@ package cmd
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
+
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
 )
 
 func makeInventoryFile(t *testing.T, host string) string {
@@ -67,7 +74,7 @@ func TestFirmwareStatusDetectsFailure(t *testing.T) {
 	fwBatchSize = 1
 	fwTargets = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}
 	fwInsecure = true
-	fwTimeout = 2 * time.Second
+	fwTimeouts = timeouts{Request: 2 * time.Second}
 	// Ensure env
 	t.Setenv("REDFISH_USER", "user")
 	t.Setenv("REDFISH_PASSWORD", "pass")
@@ -130,7 +137,7 @@ func TestFirmwareStatusDetectsInstalling(t *testing.T) {
 	fwBatchSize = 1
 	fwTargets = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}
 	fwInsecure = true
-	fwTimeout = 2 * time.Second
+	fwTimeouts = timeouts{Request: 2 * time.Second}
 	// Ensure env
 	t.Setenv("REDFISH_USER", "user")
 	t.Setenv("REDFISH_PASSWORD", "pass")
@@ -197,7 +204,7 @@ func TestFirmwareStatusPrefersUpdateServiceUpdating(t *testing.T) {
 	fwBatchSize = 1
 	fwTargets = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}
 	fwInsecure = true
-	fwTimeout = 2 * time.Second
+	fwTimeouts = timeouts{Request: 2 * time.Second}
 	// Ensure env
 	t.Setenv("REDFISH_USER", "user")
 	t.Setenv("REDFISH_PASSWORD", "pass")
@@ -269,7 +276,7 @@ func TestFirmwareStatusPrefersUpdateServiceHealthCritical(t *testing.T) {
 	fwBatchSize = 1
 	fwTargets = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}
 	fwInsecure = true
-	fwTimeout = 2 * time.Second
+	fwTimeouts = timeouts{Request: 2 * time.Second}
 	// Ensure env
 	t.Setenv("REDFISH_USER", "user")
 	t.Setenv("REDFISH_PASSWORD", "pass")
@@ -324,7 +331,7 @@ func TestFirmwareStatusDetectsInventoryHealthWarningNoConditions(t *testing.T) {
 	fwBatchSize = 1
 	fwTargets = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}
 	fwInsecure = true
-	fwTimeout = 2 * time.Second
+	fwTimeouts = timeouts{Request: 2 * time.Second}
 	// Ensure env
 	t.Setenv("REDFISH_USER", "user")
 	t.Setenv("REDFISH_PASSWORD", "pass")
@@ -387,7 +394,7 @@ func TestFirmwareStatusDetectsInventoryHealthCriticalWithCondition(t *testing.T)
 	fwBatchSize = 1
 	fwTargets = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}
 	fwInsecure = true
-	fwTimeout = 2 * time.Second
+	fwTimeouts = timeouts{Request: 2 * time.Second}
 	// Ensure env
 	t.Setenv("REDFISH_USER", "user")
 	t.Setenv("REDFISH_PASSWORD", "pass")
@@ -429,10 +436,12 @@ func TestFirmwareStatusDetectsTaskServiceRunning(t *testing.T) {
 		if r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/TaskService/Tasks/1") {
 			w.Header().Set("Content-Type", "application/json")
 			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
-				"Id":        "1",
-				"Name":      "Firmware Update",
-				"TaskState": "Running",
-				"Message":   "Updating BIOS",
+				"@odata.id":       "/redfish/v1/TaskService/Tasks/1",
+				"Id":              "1",
+				"Name":            "Firmware Update",
+				"TaskState":       "Running",
+				"Message":         "Updating BIOS",
+				"PercentComplete": 45,
 			})
 			return
 		}
@@ -471,7 +480,7 @@ func TestFirmwareStatusDetectsTaskServiceRunning(t *testing.T) {
 	fwBatchSize = 1
 	fwTargets = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}
 	fwInsecure = true
-	fwTimeout = 2 * time.Second
+	fwTimeouts = timeouts{Request: 2 * time.Second}
 	// Ensure env
 	t.Setenv("REDFISH_USER", "user")
 	t.Setenv("REDFISH_PASSWORD", "pass")
@@ -495,4 +504,423 @@ func TestFirmwareStatusDetectsTaskServiceRunning(t *testing.T) {
 	if !strings.Contains(output, "In-progress updates: 1") {
 		t.Fatalf("expected one in-progress update via TaskService, got:\n%s", output)
 	}
+	if !strings.Contains(output, ": installing 45%, task 1") {
+		t.Fatalf("expected the task's progress and phase, got:\n%s", output)
+	}
+}
+
+func TestFirmwareStatusFaultProfiles(t *testing.T) {
+	healthy := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/UpdateService/FirmwareInventory/BMC") {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{"Id": "BMC", "Version": "1.0.0"}) //nolint:errcheck
+			return
+		}
+		http.NotFound(w, r)
+	})
+	newHost := func(spec string) string {
+		p, err := redfishtest.ParseProfile(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := httptest.NewTLSServer(redfishtest.WithFaults(healthy, p))
+		t.Cleanup(s.Close)
+		return strings.TrimPrefix(s.URL, "https://")
+	}
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+	fwFile = ""
+	fwBatchSize = 2
+	fwTargets = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}
+	fwInsecure = true
+	fwTimeouts = timeouts{Request: 500 * time.Millisecond}
+	fwFormat = "json"
+	defer func() { fwHostsCSV, fwFormat = "", "" }()
+
+	cases := []struct {
+		name  string
+		hosts []string
+		want  int
+	}{
+		{"all auth failures", []string{newHost("auth"), newHost("auth")}, exitAuth},
+		{"all too slow", []string{newHost("slow=2s"), newHost("slow=2s")}, exitUnreachable},
+		{"mixed", []string{newHost(""), newHost("truncate")}, exitPartial},
+	}
+	cmd := firmwareStatusCmd
+	cmd.SetContext(context.Background())
+	for _, tc := range cases {
+		fwHostsCSV = strings.Join(tc.hosts, ",")
+		if got := exitCode(cmd.RunE(cmd, []string{})); got != tc.want {
+			t.Errorf("%s: exit code %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestFirmwareStatusResolvesMessageRegistry(t *testing.T) {
+	// The condition's registry is not bundled, so it is fetched from the BMC's /Registries.
+	var registryGets int
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/redfish/v1/UpdateService/FirmwareInventory/BMC":
+			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+				"Version": "nc.1.10.1",
+				"Status": map[string]any{
+					"Health": "Warning",
+					"State":  "Enabled",
+					"Conditions": []map[string]any{
+						{"MessageId": "ExampleFirmware.1.0.DownloadFailed", "MessageArgs": []string{"http://repo/bmc.bin"}, "Severity": "Warning"},
+						{"MessageId": "Update.1.0.TransferFailed", "MessageArgs": []string{"bmc.bin", "BMC"}, "Severity": "Critical"},
+					},
+				},
+			})
+		case "/redfish/v1/Registries":
+			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+				"Members": []map[string]any{{"@odata.id": "/redfish/v1/Registries/ExampleFirmware.1.0"}},
+			})
+		case "/redfish/v1/Registries/ExampleFirmware.1.0":
+			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+				"Registry": "ExampleFirmware.1.0",
+				"Location": []map[string]any{
+					{"Language": "ja", "Uri": "/redfish/v1/RegistryStore/ja/ExampleFirmware.json"},
+					{"Language": "en", "Uri": "/redfish/v1/RegistryStore/en/ExampleFirmware.json"},
+				},
+			})
+		case "/redfish/v1/RegistryStore/en/ExampleFirmware.json":
+			registryGets++
+			json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+				"Id":              "ExampleFirmware.1.0.0",
+				"RegistryPrefix":  "ExampleFirmware",
+				"RegistryVersion": "1.0.0",
+				"Messages": map[string]any{
+					"DownloadFailed": map[string]any{
+						"Message":      "The firmware package at %1 failed to download.",
+						"NumberOfArgs": 1,
+						"Resolution":   "Check that the image URI is reachable from the BMC.",
+					},
+				},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	server := httptest.NewTLSServer(handler)
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "https://")
+	fwFile = makeInventoryFile(t, host)
+	fwBatchSize = 1
+	fwTargets = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}
+	fwInsecure = true
+	fwTimeouts = timeouts{Request: 2 * time.Second}
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = old }()
+
+	cmd := firmwareStatusCmd
+	cmd.SetContext(context.Background())
+	if err := cmd.RunE(cmd, []string{}); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+
+	w.Close() //nolint:errcheck
+	out, _ := io.ReadAll(r)
+	output := string(out)
+
+	for _, want := range []string{
+		"ExampleFirmware.1.0.DownloadFailed: The firmware package at http://repo/bmc.bin failed to download. (resolution: Check that the image URI is reachable from the BMC.)",
+		"Update.1.0.TransferFailed: Transfer of image 'bmc.bin' to 'BMC' failed.",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output lacks %q:\n%s", want, output)
+		}
+	}
+	if registryGets != 1 {
+		t.Errorf("registry fetched %d times, want 1", registryGets)
+	}
+}
+
+func TestFirmwareStatusMinSeverityAndFailOn(t *testing.T) {
+	inventory := func(health, severity string) *redfishtest.MockClient {
+		return &redfishtest.MockClient{
+			GetUpdateServiceStatusFunc: func(context.Context) (redfish.UpdateServiceStatus, error) {
+				return redfish.UpdateServiceStatus{Health: "OK", State: "Enabled"}, nil
+			},
+			GetActiveUpdateTasksFunc: func(context.Context) ([]string, error) { return nil, nil },
+			GetFirmwareInventoryFunc: func(context.Context, string) (redfish.FirmwareInventory, error) {
+				inv := redfish.FirmwareInventory{Version: "1.0", State: "Enabled", Health: health}
+				if severity != "" {
+					inv.Conditions = []redfish.FirmwareCondition{{MessageID: "Update.1.0.ApplyFailed", MessageArgs: []string{"bmc.bin", "BMC"}, Severity: severity}}
+				}
+				return inv, nil
+			},
+		}
+	}
+	updating := inventory("OK", "")
+	updating.GetUpdateServiceStatusFunc = func(context.Context) (redfish.UpdateServiceStatus, error) {
+		return redfish.UpdateServiceStatus{Health: "OK", State: "Updating"}, nil
+	}
+	mocks := map[string]*redfishtest.MockClient{
+		"warn":     inventory("Warning", "Warning"),
+		"crit":     inventory("Critical", "Critical"),
+		"updating": updating,
+		"idle":     inventory("OK", ""),
+	}
+	useMockClients(t, mocks)
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+	fwFile = ""
+	fwBatchSize = 4
+	fwTargets = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}
+	fwTimeouts = timeouts{}
+	fwFormat = "json"
+	defer func() { fwHostsCSV, fwFormat, fwMinSeverity, fwFailOn = "", "", "ok", "" }()
+
+	cases := []struct {
+		hosts       string
+		minSeverity string
+		failOn      string
+		want        int
+	}{
+		{"warn,crit,updating,idle", "ok", "", exitOK},
+		{"warn,idle", "ok", "error", exitOK},
+		{"crit,idle", "ok", "error", exitPartial},
+		{"warn,idle", "ok", "warning", exitPartial},
+		{"warn,idle", "critical", "warning", exitOK}, // the warning is filtered out
+		{"updating,idle", "ok", "warning", exitOK},
+		{"updating,idle", "ok", "in-progress", exitPartial},
+		{"idle", "ok", "in-progress", exitOK},
+		{"idle", "severe", "", exitInvalid},
+		{"idle", "ok", "always", exitInvalid},
+	}
+	cmd := firmwareStatusCmd
+	cmd.SetContext(context.Background())
+	for _, tc := range cases {
+		fwHostsCSV, fwMinSeverity, fwFailOn = tc.hosts, tc.minSeverity, tc.failOn
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := cmd.RunE(cmd, []string{})
+		w.Close() //nolint:errcheck
+		os.Stdout = old
+		out, _ := io.ReadAll(r)
+		if got := exitCode(err); got != tc.want {
+			t.Errorf("%s --min-severity %s --fail-on %s: exit code %d (%v), want %d", tc.hosts, tc.minSeverity, tc.failOn, got, err, tc.want)
+		}
+		if tc.minSeverity == "critical" && strings.Contains(string(out), "ApplyFailed") {
+			t.Errorf("warning condition not filtered by --min-severity critical:\n%s", out)
+		}
+	}
+}
+
+func TestPrintVersions(t *testing.T) {
+	rows := []versionRow{
+		{Label: "x1000c0s0b0", Chassis: "x1000c0", Version: "1.2.0"},
+		{Label: "x1000c0s1b0", Chassis: "x1000c0", Version: "1.2.0"},
+		{Label: "x1000c1s0b0", Chassis: "x1000c1", Version: "1.2.0"},
+		{Label: "x1000c1s1b0", Chassis: "x1000c1", Version: "1.1.0"},
+		{Label: "x1001c0s0b0", Chassis: "x1001c0", Version: "1.1.0"},
+		{Label: "10.0.0.9", Version: "(unknown)"},
+	}
+	var b strings.Builder
+	printVersions(&b, rows, "")
+	want := `  Versions:
+    1.2.0: 3
+    1.1.0: 2
+    (unknown): 1
+  Versions by chassis:
+    (no xname): (unknown) (1)  <-
+    x1000c0: 1.2.0 (2)
+    x1000c1: 1.2.0 (1), 1.1.0 (1)  <-
+    x1001c0: 1.1.0 (1)  <-
+  Not at 1.2.0:
+    1.1.0: x1000c1s1b0, x1001c0s0b0
+    (unknown): 10.0.0.9
+`
+	if b.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+
+	// An expected version makes every other version an outlier, even the most common.
+	b.Reset()
+	printVersions(&b, rows[3:5], "1.2.0")
+	if !strings.Contains(b.String(), "Not at 1.2.0:\n    1.1.0: x1000c1s1b0, x1001c0s0b0\n") {
+		t.Errorf("outliers against --expected-version missing:\n%s", b.String())
+	}
+}
+
+func TestFirmwareStatusCSV(t *testing.T) {
+	bmc := func(version, health string) *redfishtest.MockClient {
+		return &redfishtest.MockClient{
+			GetUpdateServiceStatusFunc: func(context.Context) (redfish.UpdateServiceStatus, error) {
+				return redfish.UpdateServiceStatus{Health: "OK", State: "Enabled"}, nil
+			},
+			GetActiveUpdateTasksFunc: func(context.Context) ([]string, error) { return nil, nil },
+			GetFirmwareInventoryFunc: func(_ context.Context, target string) (redfish.FirmwareInventory, error) {
+				return redfish.FirmwareInventory{Version: version + "-" + path.Base(target), State: "Enabled", Health: health}, nil
+			},
+		}
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{
+		"10.0.0.2": bmc("1.1", "Critical"),
+		"10.0.0.1": bmc("1.2", "OK"),
+	})
+	inv := filepath.Join(t.TempDir(), "inventory.yaml")
+	if err := os.WriteFile(inv, []byte("bmcs:\n  - xname: x1000c0s0b0\n    ip: 10.0.0.2\n  - xname: x1000c1s0b0\n    ip: 10.0.0.1\nnodes:\n  - xname: x1000c0s0b0n0\n    alias: login01\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+	fwFile = inv
+	fwBatchSize = 2
+	fwTargets = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC", "/redfish/v1/UpdateService/FirmwareInventory/BIOS"}
+	fwTimeouts = timeouts{}
+	fwExpectedVersion = ""
+	fwFormat = "csv"
+	defer func() { fwFile, fwFormat = "", "" }()
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	cmd := firmwareStatusCmd
+	cmd.SetContext(context.Background())
+	err := cmd.RunE(cmd, []string{})
+	w.Close() //nolint:errcheck
+	os.Stdout = old
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		t.Fatalf("stdout is not CSV: %v", err)
+	}
+	want := [][]string{
+		{"host", "alias", "xname", "chassis", "target", "observed_version", "requested_version", "status", "severity", "error", "percent_complete", "phase"},
+		{"10.0.0.2", "login01", "x1000c0s0b0", "x1000c0", fwTargets[0], "1.1-BMC", "", "error", "Critical", "health: Critical", "", ""},
+		{"10.0.0.2", "login01", "x1000c0s0b0", "x1000c0", fwTargets[1], "1.1-BIOS", "", "error", "Critical", "health: Critical", "", ""},
+		{"10.0.0.1", "", "x1000c1s0b0", "x1000c1", fwTargets[0], "1.2-BMC", "", "idle", "", "", "", ""},
+		{"10.0.0.1", "", "x1000c1s0b0", "x1000c1", fwTargets[1], "1.2-BIOS", "", "idle", "", "", "", ""},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows =\n%q\nwant\n%q", rows, want)
+	}
+
+	fwFormat = "junit"
+	r, w, _ = os.Pipe()
+	os.Stdout = w
+	err = cmd.RunE(cmd, []string{})
+	w.Close() //nolint:errcheck
+	os.Stdout = old
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, _ := io.ReadAll(r)
+	for _, want := range []string{
+		`<testsuite name="firmware status" tests="4" failures="2"`,
+		`<testcase classname="login01" name="/redfish/v1/UpdateService/FirmwareInventory/BMC"`,
+		`<failure message="health: Critical" type="Critical">`,
+		`<system-out>version 1.2-BIOS</system-out>`,
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("junit missing %s:\n%s", want, out)
+		}
+	}
+	if i, j := strings.Index(string(out), "login01"), strings.Index(string(out), "x1000c1s0b0"); i > j {
+		t.Errorf("junit cases not in host order:\n%s", out)
+	}
+}
+
+func TestFirmwareTargets(t *testing.T) {
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+	useMockClients(t, map[string]*redfishtest.MockClient{"10.1.0.5": {
+		ListFirmwareInventoryFunc: func(context.Context) ([]redfish.FirmwareComponent, error) {
+			return []redfish.FirmwareComponent{
+				{Path: "/redfish/v1/UpdateService/FirmwareInventory/iDRAC", ID: "iDRAC", Version: "7.00.00.171", Updateable: true},
+				{Path: "/redfish/v1/UpdateService/FirmwareInventory/CPLD", ID: "CPLD", Version: "1.0.6"},
+			}, nil
+		},
+	}})
+	fwFile, fwtFormat, fwtAll = "", "csv", false
+	t.Cleanup(func() { fwtFormat, fwtAll = "", false })
+
+	run := func() string {
+		t.Helper()
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		firmwareTargetsCmd.SetContext(context.Background())
+		err := firmwareTargetsCmd.RunE(firmwareTargetsCmd, []string{"10.1.0.5"})
+		w.Close() //nolint:errcheck
+		os.Stdout = old
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, _ := io.ReadAll(r)
+		return string(out)
+	}
+	want := "target,id,name,version,software_id,updateable,state,health\n" +
+		"/redfish/v1/UpdateService/FirmwareInventory/iDRAC,iDRAC,,7.00.00.171,,true,,\n"
+	if got := run(); got != want {
+		t.Errorf("output:\n%s\nwant:\n%s", got, want)
+	}
+	fwtAll = true
+	if got := run(); !strings.Contains(got, "FirmwareInventory/CPLD,CPLD,,1.0.6,,false") {
+		t.Errorf("--all output lacks CPLD:\n%s", got)
+	}
+}
+
+func TestFirmwareStatusStateDB(t *testing.T) {
+	version, health := "1.1", "OK"
+	m := &redfishtest.MockClient{
+		GetUpdateServiceStatusFunc: func(context.Context) (redfish.UpdateServiceStatus, error) {
+			return redfish.UpdateServiceStatus{Health: "OK", State: "Enabled"}, nil
+		},
+		GetActiveUpdateTasksFunc: func(context.Context) ([]string, error) { return nil, nil },
+		GetFirmwareInventoryFunc: func(context.Context, string) (redfish.FirmwareInventory, error) {
+			return redfish.FirmwareInventory{Version: version, State: "Enabled", Health: health}, nil
+		},
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"10.0.0.1": m})
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+	fwFile, fwHostsCSV, fwBatchSize, fwTargets, fwTimeouts, fwFormat = "", "10.0.0.1", 1, bmcTarget, timeouts{}, ""
+	fwStateDB = filepath.Join(t.TempDir(), "status.db")
+	defer func() { fwHostsCSV, fwStateDB = "", "" }()
+
+	run := func() string {
+		t.Helper()
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		cmd := firmwareStatusCmd
+		cmd.SetContext(context.Background())
+		err := cmd.RunE(cmd, []string{})
+		w.Close() //nolint:errcheck
+		os.Stdout = old
+		out, _ := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("%v\n%s", err, out)
+		}
+		return string(out)
+	}
+
+	if out := run(); !strings.Contains(out, "First run recorded in ") {
+		t.Fatalf("first run:\n%s", out)
+	}
+	if out := run(); !strings.Contains(out, "No changes since the last run") {
+		t.Fatalf("unchanged run:\n%s", out)
+	}
+	version, health = "1.2", "Warning"
+	out := run()
+	for _, want := range []string{"Changes since the last run", "10.0.0.1 BMC: version 1.1 -> 1.2", "10.0.0.1 BMC: new error: health: Warning"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
 }
@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"bootstrap/pkg/redfish"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	fwtAll    bool
+	fwtFormat string
+)
+
+var firmwareTargetsCmd = &cobra.Command{
+	Use:   "targets <host>",
+	Short: "List the FirmwareInventory components of a BMC usable as --targets",
+	Long: `Walk the UpdateService FirmwareInventory of one BMC (a host, IP, bmcs[] xname of
+--file or alias) and print every component the BMC can update: the path to pass
+to --targets, its Id, name and version. Vendors name components differently
+(BMC, iDRAC, Node0.BIOS, CPLD, ...), so use this before the first update of a
+new platform. --all also lists components the BMC reports as not updateable.`,
+	Example: `  ochami_bootstrap firmware targets x1000c0s0b0 -f inventory.yaml
+  ochami_bootstrap firmware targets 10.1.0.5 --format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if fwtFormat != "" && fwtFormat != "json" && fwtFormat != "csv" {
+			return invalidf("--format must be json or csv when set")
+		}
+		hosts, err := resolveHosts(fwFile, args[0], "")
+		if err != nil {
+			return err
+		}
+		if len(hosts) != 1 {
+			return invalidf("%s names %d BMCs; give one host", args[0], len(hosts))
+		}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+		stopCache, err := useResponseCache()
+		if err != nil {
+			return err
+		}
+		defer stopCache()
+		h := hosts[0]
+		ctx := cmd.Context()
+		if fwTimeouts.Host > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, fwTimeouts.Host)
+			defer cancel()
+		}
+		comps, err := newRedfishClient(h, user, pass, fwInsecure, fwTimeouts.Request).ListFirmwareInventory(ctx)
+		if err != nil {
+			return hostFailures(1, map[string]error{h: err})
+		}
+		shown := comps[:0]
+		for _, c := range comps {
+			if c.Updateable || fwtAll {
+				shown = append(shown, c)
+			}
+		}
+		return printFirmwareTargets(shown)
+	},
+}
+
+// firmwareTargetRow is the JSON form of one component.
+type firmwareTargetRow struct {
+	Target     string `json:"target"`
+	ID         string `json:"id,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Version    string `json:"version,omitempty"`
+	SoftwareID string `json:"software_id,omitempty"`
+	Updateable bool   `json:"updateable"`
+	State      string `json:"state,omitempty"`
+	Health     string `json:"health,omitempty"`
+}
+
+func printFirmwareTargets(comps []redfish.FirmwareComponent) error {
+	switch fwtFormat {
+	case "json":
+		rows := make([]firmwareTargetRow, len(comps))
+		for i, c := range comps {
+			rows[i] = firmwareTargetRow{Target: c.Path, ID: c.ID, Name: c.Name, Version: c.Version,
+				SoftwareID: c.SoftwareID, Updateable: c.Updateable, State: c.State, Health: c.Health}
+		}
+		out, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	case "csv":
+		records := make([][]string, len(comps))
+		for i, c := range comps {
+			records[i] = []string{c.Path, c.ID, c.Name, c.Version, c.SoftwareID, strconv.FormatBool(c.Updateable), c.State, c.Health}
+		}
+		return writeCSV(os.Stdout, []string{"target", "id", "name", "version", "software_id", "updateable", "state", "health"}, records)
+	}
+	if len(comps) == 0 {
+		fmt.Println("No updateable firmware components reported.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TARGET\tID\tNAME\tVERSION\tUPDATEABLE")
+	for _, c := range comps {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%t\n", c.Path, cmp.Or(c.ID, "-"), cmp.Or(c.Name, "-"), cmp.Or(c.Version, "-"), c.Updateable)
+	}
+	return tw.Flush()
+}
+
+// firmwareTypes are the --type presets, matched against the Id and Name of each
+// FirmwareInventory component since vendors and blade types name them
+// differently (BMC, iDRAC.Embedded.1-1, iLO 5, Node0.BIOS, System ROM, ...).
+var firmwareTypes = map[string]*regexp.Regexp{
+	"bmc":  regexp.MustCompile(`(?i)(^|[^a-z])(bmc|idrac|ilo|xcc)([^a-z]|$)|baseboard management|lights-out`),
+	"bios": regexp.MustCompile(`(?i)(^|[^a-z])(bios|uefi)([^a-z]|$)|system rom`),
+	"nic":  regexp.MustCompile(`(?i)(^|[^a-z])nic([^a-z]|$)|network|ethernet|connectx`),
+}
+
+// firmwareType returns the preset --type t stands for; cc and nc (chassis and
+// node controllers) are BMCs.
+func firmwareType(t string) (string, error) {
+	t = strings.ToLower(t)
+	switch t {
+	case "cc", "nc":
+		return "bmc", nil
+	}
+	if _, ok := firmwareTypes[t]; !ok {
+		return "", fmt.Errorf("unknown firmware type: %s (use cc|nc|bmc|bios|nic or specify --targets; 'firmware targets <host>' lists them)", t)
+	}
+	return t, nil
+}
+
+// typeTargets returns the paths of the updateable components of one BMC that
+// match the --type preset t.
+func typeTargets(ctx context.Context, rf redfish.Client, t string) ([]string, error) {
+	kind, err := firmwareType(t)
+	if err != nil {
+		return nil, err
+	}
+	comps, err := rf.ListFirmwareInventory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list FirmwareInventory for --type %s: %w", t, err)
+	}
+	var out, seen []string
+	for _, c := range comps {
+		if !c.Updateable {
+			continue
+		}
+		seen = append(seen, cmp.Or(c.ID, c.Path))
+		if firmwareTypes[kind].MatchString(c.ID) || firmwareTypes[kind].MatchString(c.Name) {
+			out = append(out, c.Path)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no updateable %s component in FirmwareInventory (have %s); use --targets", kind, cmp.Or(strings.Join(seen, ", "), "none"))
+	}
+	return out, nil
+}
+
+func init() {
+	firmwareCmd.AddCommand(firmwareTargetsCmd)
+	firmwareTargetsCmd.Flags().BoolVar(&fwtAll, "all", false, "also list components the BMC reports as not updateable")
+	firmwareTargetsCmd.Flags().StringVar(&fwtFormat, "format", "", "output format: json or csv (default: table)")
+	addCacheFlags(firmwareTargetsCmd)
+}
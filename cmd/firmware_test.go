@@ -7,16 +7,29 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
+
+	"bootstrap/internal/notify"
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+
+	"github.com/spf13/pflag"
 )
 
 // Mock Redfish server for firmware testing
@@ -114,7 +127,6 @@ func TestFirmwareParallelExecution(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var maxConcurrent, currentConcurrent int32
-			server := mockRedfishFirmwareServer(t, tt.responseDelay, &maxConcurrent, &currentConcurrent)
 
 			// Set env
 			t.Setenv("REDFISH_USER", "testuser")
@@ -127,10 +139,11 @@ func TestFirmwareParallelExecution(t *testing.T) {
 			}
 			defer os.Remove(tmpFile.Name()) //nolint: errcheck
 
-			// Generate BMC entries pointing to mock server
-			host := strings.TrimPrefix(server.URL, "https://")
+			// Generate BMC entries, each pointing to its own mock server
 			var bmcs []string
 			for i := 0; i < tt.numHosts; i++ {
+				server := mockRedfishFirmwareServer(t, tt.responseDelay, &maxConcurrent, &currentConcurrent)
+				host := strings.TrimPrefix(server.URL, "https://")
 				bmcs = append(bmcs, fmt.Sprintf("  - xname: x9000c1s%db0\n    ip: %s", i, host))
 			}
 			inventory := fmt.Sprintf("bmcs:\n%s\n", strings.Join(bmcs, "\n"))
@@ -145,10 +158,10 @@ func TestFirmwareParallelExecution(t *testing.T) {
 			fwImageURI = "http://10.0.0.1/firmware.bin"
 			fwProtocol = "HTTP"
 			fwInsecure = true
-			fwTimeout = 5 * time.Second
+			fwTimeouts = timeouts{Request: 5 * time.Second}
 			fwDryRun = false
 			fwBatchSize = tt.batchSize
-			fwTargets = nil
+			fwTargets = bmcTarget
 			fwExpectedVersion = ""
 			fwForce = false
 
@@ -248,7 +261,7 @@ func TestFirmwareDryRunParallel(t *testing.T) {
 	fwProtocol = "HTTP"
 	fwDryRun = true
 	fwBatchSize = 3
-	fwTargets = nil
+	fwTargets = bmcTarget
 	fwExpectedVersion = "1.2.3"
 	fwForce = false
 
@@ -285,7 +298,6 @@ func TestFirmwareDryRunParallel(t *testing.T) {
 // TestFirmwareSemaphoreLimiting tests that semaphore correctly limits concurrency
 func TestFirmwareSemaphoreLimiting(t *testing.T) {
 	var maxConcurrent, currentConcurrent int32
-	server := mockRedfishFirmwareServer(t, 200*time.Millisecond, &maxConcurrent, &currentConcurrent)
 
 	t.Setenv("REDFISH_USER", "testuser")
 	t.Setenv("REDFISH_PASSWORD", "testpass")
@@ -297,10 +309,11 @@ func TestFirmwareSemaphoreLimiting(t *testing.T) {
 	}
 	defer os.Remove(tmpFile.Name()) //nolint: errcheck
 
-	host := strings.TrimPrefix(server.URL, "https://")
 	var bmcs []string
 	numHosts := 15
 	for i := 0; i < numHosts; i++ {
+		server := mockRedfishFirmwareServer(t, 200*time.Millisecond, &maxConcurrent, &currentConcurrent)
+		host := strings.TrimPrefix(server.URL, "https://")
 		bmcs = append(bmcs, fmt.Sprintf("  - xname: x9000c1s%db0\n    ip: %s", i, host))
 	}
 	inventory := fmt.Sprintf("bmcs:\n%s\n", strings.Join(bmcs, "\n"))
@@ -315,10 +328,10 @@ func TestFirmwareSemaphoreLimiting(t *testing.T) {
 	fwImageURI = "http://10.0.0.1/firmware.bin"
 	fwProtocol = "HTTP"
 	fwInsecure = true
-	fwTimeout = 10 * time.Second
+	fwTimeouts = timeouts{Request: 10 * time.Second}
 	fwDryRun = false
 	fwBatchSize = 3
-	fwTargets = nil
+	fwTargets = bmcTarget
 
 	// Suppress output
 	oldStdout := os.Stdout
@@ -347,24 +360,51 @@ func TestFirmwareSemaphoreLimiting(t *testing.T) {
 	t.Logf("Max concurrent with batch-size 3: %d", actualMax)
 }
 
-// TestDefaultTargets tests the defaultTargets helper function
-func TestDefaultTargets(t *testing.T) {
+// TestTypeTargets verifies --type presets match each vendor's FirmwareInventory names
+func TestTypeTargets(t *testing.T) {
+	const inv = "/redfish/v1/UpdateService/FirmwareInventory/"
+	vendors := map[string][]redfish.FirmwareComponent{
+		"cray": {
+			{Path: inv + "BMC", ID: "BMC", Updateable: true},
+			{Path: inv + "Node0.BIOS", ID: "Node0.BIOS", Updateable: true},
+			{Path: inv + "Node1.BIOS", ID: "Node1.BIOS", Updateable: true},
+			{Path: inv + "Node0.NIC", ID: "Node0.NIC", Name: "Node0 HSN NIC", Updateable: true},
+		},
+		"hpe": {
+			{Path: inv + "1", ID: "1", Name: "iLO 5", Updateable: true},
+			{Path: inv + "2", ID: "2", Name: "System ROM", Updateable: true},
+			{Path: inv + "3", ID: "3", Name: "Redundant System ROM", Updateable: false},
+			{Path: inv + "4", ID: "4", Name: "HPE Ethernet 10/25Gb 2-port Adapter", Updateable: true},
+		},
+		"dell": {
+			{Path: inv + "Installed-25227__iDRAC.Embedded.1-1", ID: "Installed-25227__iDRAC.Embedded.1-1", Name: "Integrated Dell Remote Access Controller", Updateable: true},
+			{Path: inv + "Installed-159__BIOS.Setup.1-1", ID: "Installed-159__BIOS.Setup.1-1", Name: "BIOS", Updateable: true},
+			{Path: inv + "Installed-101561__NIC.Slot.1-1-1", ID: "Installed-101561__NIC.Slot.1-1-1", Name: "Mellanox ConnectX-6", Updateable: true},
+		},
+	}
 	tests := []struct {
-		name        string
-		fwType      string
-		wantTargets []string
-		wantErr     bool
+		vendor, fwType string
+		want           []string
+		wantErr        bool
 	}{
-		{"cc type", "cc", []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}, false},
-		{"bmc type", "bmc", []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}, false},
-		{"nc type", "nc", []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}, false},
-		{"bios type", "bios", []string{"/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS", "/redfish/v1/UpdateService/FirmwareInventory/Node1.BIOS"}, false},
-		{"unknown type", "unknown", nil, true},
+		{"cray", "cc", []string{inv + "BMC"}, false},
+		{"cray", "nc", []string{inv + "BMC"}, false},
+		{"cray", "bios", []string{inv + "Node0.BIOS", inv + "Node1.BIOS"}, false},
+		{"cray", "nic", []string{inv + "Node0.NIC"}, false},
+		{"hpe", "bmc", []string{inv + "1"}, false},
+		{"hpe", "bios", []string{inv + "2"}, false},
+		{"hpe", "nic", []string{inv + "4"}, false},
+		{"dell", "bmc", []string{inv + "Installed-25227__iDRAC.Embedded.1-1"}, false},
+		{"dell", "bios", []string{inv + "Installed-159__BIOS.Setup.1-1"}, false},
+		{"dell", "nic", []string{inv + "Installed-101561__NIC.Slot.1-1-1"}, false},
+		{"cray", "unknown", nil, true},
 	}
-
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			targets, err := defaultTargets(tt.fwType)
+		t.Run(tt.vendor+" "+tt.fwType, func(t *testing.T) {
+			m := &redfishtest.MockClient{ListFirmwareInventoryFunc: func(context.Context) ([]redfish.FirmwareComponent, error) {
+				return vendors[tt.vendor], nil
+			}}
+			got, err := typeTargets(context.Background(), m, tt.fwType)
 			if tt.wantErr {
 				if err == nil {
 					t.Fatal("expected error, got nil")
@@ -374,14 +414,843 @@ func TestDefaultTargets(t *testing.T) {
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
-			if len(targets) != len(tt.wantTargets) {
-				t.Fatalf("wrong number of targets: got %d, want %d", len(targets), len(tt.wantTargets))
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("targets = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	none := &redfishtest.MockClient{ListFirmwareInventoryFunc: func(context.Context) ([]redfish.FirmwareComponent, error) {
+		return []redfish.FirmwareComponent{{Path: inv + "CPLD", ID: "CPLD", Updateable: true}}, nil
+	}}
+	if _, err := typeTargets(context.Background(), none, "bios"); err == nil || !strings.Contains(err.Error(), "have CPLD") {
+		t.Fatalf("want an error naming the components found, got %v", err)
+	}
+}
+
+// TestFirmwareTypePerHost verifies --type is resolved on each BMC separately
+func TestFirmwareTypePerHost(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	var mu sync.Mutex
+	got := map[string][]string{}
+	bmc := func(host string, comps ...redfish.FirmwareComponent) *redfishtest.MockClient {
+		return &redfishtest.MockClient{
+			ListFirmwareInventoryFunc: func(context.Context) ([]redfish.FirmwareComponent, error) { return comps, nil },
+			SimpleUpdateFunc: func(_ context.Context, _ string, targets []string, _, _ string, _ bool) (redfish.UpdateResult, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				got[host] = targets
+				return redfish.UpdateResult{}, nil
+			},
+		}
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{
+		"a": bmc("a", redfish.FirmwareComponent{Path: "/fw/Node0.BIOS", ID: "Node0.BIOS", Updateable: true}, redfish.FirmwareComponent{Path: "/fw/BMC", ID: "BMC", Updateable: true}),
+		"b": bmc("b", redfish.FirmwareComponent{Path: "/fw/2", ID: "2", Name: "System ROM", Updateable: true}),
+	})
+	fwFile, fwHostsCSV, fwHostsFile = "", "a,b", ""
+	fwType, fwImageURI, fwTargets, fwExpectedVersion = "bios", "http://10.0.0.1/bios.bin", nil, ""
+	fwDryRun, fwBatchSize, fwActivate, fwUpdateFormat = false, 0, activateNone, "json"
+	defer func() { fwHostsCSV, fwType, fwUpdateFormat = "", "bmc", "" }()
+
+	old := os.Stdout
+	os.Stdout, _ = os.Open(os.DevNull)
+	defer func() { os.Stdout = old }()
+	cmd := firmwareCmd
+	cmd.SetContext(context.Background())
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{"a": {"/fw/Node0.BIOS"}, "b": {"/fw/2"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("targets = %v, want %v", got, want)
+	}
+	if fwTargets != nil {
+		t.Fatalf("--targets changed to %v", fwTargets)
+	}
+}
+
+// TestFirmwareNodes verifies --nodes updates only the targets of the named nodes
+func TestFirmwareNodes(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	inv := filepath.Join(t.TempDir(), "inventory.yaml")
+	doc := "bmcs:\n  - xname: x1000c0s0b0\n    ip: 10.1.0.1\nnodes:\n  - xname: x1000c0s0b0n1\n    alias: nid002\n"
+	if err := os.WriteFile(inv, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	m := &redfishtest.MockClient{
+		GetSystemsFunc: func(context.Context) ([]redfish.System, error) {
+			return []redfish.System{
+				{Resource: redfish.Resource{ODataID: "/redfish/v1/Systems/Node0", ID: "Node0"}},
+				{Resource: redfish.Resource{ODataID: "/redfish/v1/Systems/Node1", ID: "Node1"}},
+			}, nil
+		},
+		ListFirmwareInventoryFunc: func(context.Context) ([]redfish.FirmwareComponent, error) {
+			return []redfish.FirmwareComponent{
+				{Path: "/fw/BMC", ID: "BMC", Updateable: true},
+				{Path: "/fw/Node0.BIOS", ID: "Node0.BIOS", Updateable: true},
+				{Path: "/fw/Node1.BIOS", ID: "Node1.BIOS", Updateable: true},
+			}, nil
+		},
+		SimpleUpdateFunc: func(_ context.Context, _ string, targets []string, _, _ string, _ bool) (redfish.UpdateResult, error) {
+			got = targets
+			return redfish.UpdateResult{}, nil
+		},
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"10.1.0.1": m})
+	fwFile, fwHostsCSV, fwHostsFile, fwNodes = inv, "", "", "nid002"
+	fwType, fwImageURI, fwTargets, fwExpectedVersion = "bios", "http://10.0.0.1/bios.bin", nil, ""
+	fwDryRun, fwBatchSize, fwActivate, fwUpdateFormat = false, 0, activateNone, "json"
+	defer func() { fwFile, fwNodes, fwType, fwUpdateFormat = "", "", "bmc", "" }()
+
+	old := os.Stdout
+	os.Stdout, _ = os.Open(os.DevNull)
+	defer func() { os.Stdout = old }()
+	cmd := firmwareCmd
+	cmd.SetContext(context.Background())
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/fw/Node1.BIOS"}; !slices.Equal(got, want) {
+		t.Fatalf("targets = %v, want %v", got, want)
+	}
+
+	// RelatedItem wins over names, and a node without targets is an error
+	m.ListFirmwareInventoryFunc = func(context.Context) ([]redfish.FirmwareComponent, error) {
+		return []redfish.FirmwareComponent{
+			{Path: "/fw/1", ID: "1", Name: "System ROM", RelatedItems: []string{"/redfish/v1/Systems/Node0"}},
+			{Path: "/fw/2", ID: "2", Name: "System ROM", RelatedItems: []string{"/redfish/v1/Systems/Node1/Bios"}},
+		}, nil
+	}
+	targets, err := nodeFirmwareTargets(context.Background(), m, []string{"/fw/1", "/fw/2"}, []string{"x1000c0s0b0n1"})
+	if err != nil || !slices.Equal(targets, []string{"/fw/2"}) {
+		t.Fatalf("targets = %v, %v; want [/fw/2]", targets, err)
+	}
+	if _, err := nodeFirmwareTargets(context.Background(), m, []string{"/fw/1"}, []string{"x1000c0s0b0n1"}); err == nil {
+		t.Fatal("expected an error for a node without targets")
+	}
+	if _, err := nodeFirmwareTargets(context.Background(), m, []string{"/fw/1"}, []string{"x1000c0s0b0n2"}); err == nil {
+		t.Fatal("expected an error for a node the BMC does not have")
+	}
+}
+
+// TestFirmwareInterrupted verifies a cancelled context aborts remaining hosts
+func TestFirmwareInterrupted(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+
+	for _, batch := range []int{1, 3} {
+		fwFile = ""
+		fwHostsCSV = "10.1.1.10,10.1.1.11,10.1.1.12"
+		fwType = "bmc"
+		fwImageURI = "http://10.0.0.1/firmware.bin"
+		fwProtocol = "HTTP"
+		fwDryRun = false
+		fwBatchSize = batch
+		fwTargets = bmcTarget
+		fwExpectedVersion = ""
+
+		oldStderr := os.Stderr
+		r, w, _ := os.Pipe()
+		os.Stderr = w
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		cmd := firmwareCmd
+		cmd.SetContext(ctx)
+		err := cmd.RunE(cmd, []string{})
+
+		w.Close() //nolint: errcheck
+		os.Stderr = oldStderr
+		var buf bytes.Buffer
+		io.Copy(&buf, r) //nolint: errcheck
+
+		if !errors.Is(err, errInterrupted) {
+			t.Fatalf("batch %d: expected errInterrupted, got %v", batch, err)
+		}
+		if !strings.Contains(buf.String(), "0 host(s) completed, 3 aborted") {
+			t.Fatalf("batch %d: unexpected summary: %s", batch, buf.String())
+		}
+	}
+	fwHostsCSV = ""
+}
+
+// bmcTarget is what --type bmc resolves to on Cray EX BMCs; tests that are not about
+// target resolution pass it as --targets.
+var bmcTarget = []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}
+
+// useMockClients makes commands talk to the given mocks, keyed by host, instead of
+// real BMCs for the rest of the test.
+func useMockClients(t *testing.T, mocks map[string]*redfishtest.MockClient) {
+	t.Helper()
+	old := newRedfishClient
+	newRedfishClient = func(host, _, _ string, _ bool, _ time.Duration) redfish.Client {
+		m, ok := mocks[host]
+		if !ok {
+			t.Errorf("unexpected host %s", host)
+			return &redfishtest.MockClient{}
+		}
+		return m
+	}
+	t.Cleanup(func() { newRedfishClient = old })
+}
+
+func TestFirmwareExitCodesWithMockClient(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	authErr := &redfish.StatusError{Method: "POST", StatusCode: http.StatusUnauthorized, Status: "401 Unauthorized"}
+	update := func(err error) *redfishtest.MockClient {
+		return &redfishtest.MockClient{
+			SimpleUpdateFunc: func(context.Context, string, []string, string, string, bool) (redfish.UpdateResult, error) {
+				return redfish.UpdateResult{}, err
+			},
+		}
+	}
+
+	cases := []struct {
+		name  string
+		mocks map[string]*redfishtest.MockClient
+		want  int
+	}{
+		{"all updated", map[string]*redfishtest.MockClient{"a": update(nil), "b": update(nil)}, exitOK},
+		{"skipped is not a failure", map[string]*redfishtest.MockClient{"a": update(nil), "b": update(fmt.Errorf("%w 1.0", redfish.ErrAlreadyAtVersion))}, exitOK},
+		{"all auth failures", map[string]*redfishtest.MockClient{"a": update(authErr), "b": update(authErr)}, exitAuth},
+		{"all timed out", map[string]*redfishtest.MockClient{"a": update(context.DeadlineExceeded), "b": update(context.DeadlineExceeded)}, exitUnreachable},
+		{"partial", map[string]*redfishtest.MockClient{"a": update(nil), "b": update(authErr)}, exitPartial},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			useMockClients(t, tc.mocks)
+			fwFile = ""
+			fwHostsCSV = "a,b"
+			fwType = "bmc"
+			fwImageURI = "http://10.0.0.1/firmware.bin"
+			fwDryRun = false
+			fwBatchSize = 2
+			fwTargets = bmcTarget
+			fwExpectedVersion = ""
+			defer func() { fwHostsCSV = "" }()
+
+			cmd := firmwareCmd
+			cmd.SetContext(context.Background())
+			if got := exitCode(cmd.RunE(cmd, []string{})); got != tc.want {
+				t.Errorf("exit code %d, want %d", got, tc.want)
 			}
-			for i := range targets {
-				if targets[i] != tt.wantTargets[i] {
-					t.Fatalf("target[%d] = %q, want %q", i, targets[i], tt.wantTargets[i])
+			for h, m := range tc.mocks {
+				if strings.Join(m.Calls, ",") != "GetUpdateService,SimpleUpdate" {
+					t.Errorf("%s: calls %v", h, m.Calls)
 				}
 			}
 		})
 	}
 }
+
+func TestFirmwareActivateWithMockClient(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	oldPoll := activatePollInterval
+	activatePollInterval = time.Millisecond
+	defer func() { activatePollInterval = oldPoll }()
+
+	newBMC := func(resetErr error) (*redfishtest.MockClient, *string) {
+		version := "nc.1.9.0"
+		pending := ""
+		m := &redfishtest.MockClient{
+			SimpleUpdateFunc: func(context.Context, string, []string, string, string, bool) (redfish.UpdateResult, error) {
+				pending = "nc.1.10.1"
+				return redfish.UpdateResult{}, nil
+			},
+			GetActiveUpdateTasksFunc: func(context.Context) ([]string, error) { return nil, nil },
+			GetFirmwareInventoryFunc: func(context.Context, string) (redfish.FirmwareInventory, error) {
+				return redfish.FirmwareInventory{Version: version, State: "Enabled"}, nil
+			},
+			ResetManagerFunc: func(context.Context, string) error {
+				if resetErr != nil {
+					return resetErr
+				}
+				version = pending // new image becomes active on reset
+				return nil
+			},
+			GetUpdateServiceStatusFunc: func(context.Context) (redfish.UpdateServiceStatus, error) {
+				return redfish.UpdateServiceStatus{Health: "OK"}, nil
+			},
+		}
+		return m, &version
+	}
+
+	fwFile = ""
+	fwHostsCSV = "a"
+	fwType = "bmc"
+	fwImageURI = "http://10.0.0.1/firmware.bin"
+	fwDryRun = false
+	fwBatchSize = 1
+	fwTargets = bmcTarget
+	fwExpectedVersion = "nc.1.10.1"
+	fwActivateTimeout = time.Second
+	defer func() { fwHostsCSV, fwExpectedVersion, fwActivate = "", "", activateNone }()
+	cmd := firmwareCmd
+	cmd.SetContext(context.Background())
+
+	// bmc-reset activates and verifies the expected version
+	m, version := newBMC(nil)
+	useMockClients(t, map[string]*redfishtest.MockClient{"a": m})
+	fwActivate = activateBMCReset
+	if err := cmd.RunE(cmd, []string{}); err != nil {
+		t.Fatalf("bmc-reset: %v", err)
+	}
+	if *version != "nc.1.10.1" || !strings.Contains(strings.Join(m.Calls, ","), "SimpleUpdate,GetActiveUpdateTasks,GetFirmwareInventory,ResetManager,GetUpdateServiceStatus") {
+		t.Errorf("bmc-reset: version %s, calls %v", *version, m.Calls)
+	}
+
+	// defer waits for the update but never resets or checks the running version
+	m, version = newBMC(nil)
+	useMockClients(t, map[string]*redfishtest.MockClient{"a": m})
+	fwActivate = activateDefer
+	fwTargets = bmcTarget
+	if err := cmd.RunE(cmd, []string{}); err != nil {
+		t.Errorf("defer: %v", err)
+	}
+	if *version != "nc.1.9.0" {
+		t.Errorf("defer: version changed to %s", *version)
+	}
+	for _, c := range m.Calls {
+		if strings.HasPrefix(c, "Reset") {
+			t.Errorf("defer: unexpected %s", c)
+		}
+	}
+
+	// A failing reset fails the host
+	m, _ = newBMC(errors.New("reset refused"))
+	useMockClients(t, map[string]*redfishtest.MockClient{"a": m})
+	fwActivate = activateBMCReset
+	fwTargets = bmcTarget
+	if err := cmd.RunE(cmd, []string{}); exitCode(err) != exitPartial {
+		t.Errorf("reset failure: got %v", err)
+	}
+}
+
+func TestFirmwareDowngradeProtection(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	var updated bool
+	m := &redfishtest.MockClient{
+		GetFirmwareInventoryFunc: func(context.Context, string) (redfish.FirmwareInventory, error) {
+			return redfish.FirmwareInventory{Version: "nc.1.10.1"}, nil
+		},
+		SimpleUpdateFunc: func(context.Context, string, []string, string, string, bool) (redfish.UpdateResult, error) {
+			updated = true
+			return redfish.UpdateResult{}, nil
+		},
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"a": m})
+	fwFile = ""
+	fwHostsCSV = "a"
+	fwType = "bmc"
+	fwImageURI = "http://10.0.0.1/firmware.bin"
+	fwDryRun = false
+	fwBatchSize = 1
+	fwExpectedVersion = "nc.1.9.8"
+	defer func() {
+		fwHostsCSV, fwExpectedVersion, fwAllowList = "", "", ""
+		fwAllowDowngrade = false
+	}()
+	cmd := firmwareCmd
+	cmd.SetContext(context.Background())
+
+	fwTargets = bmcTarget
+	if err := cmd.RunE(cmd, []string{}); exitCode(err) != exitPartial || updated {
+		t.Fatalf("expected downgrade to be refused, got %v (updated=%v)", err, updated)
+	}
+
+	fwTargets = bmcTarget
+	fwAllowDowngrade = true
+	if err := cmd.RunE(cmd, []string{}); err != nil || !updated {
+		t.Fatalf("expected downgrade with --allow-downgrade, got %v (updated=%v)", err, updated)
+	}
+
+	// Versions missing from the allow-list are rejected before contacting any BMC
+	allow := filepath.Join(t.TempDir(), "allowed.txt")
+	if err := os.WriteFile(allow, []byte("nc.1.10.1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fwAllowList = allow
+	fwTargets = bmcTarget
+	if err := cmd.RunE(cmd, []string{}); exitCode(err) != exitInvalid {
+		t.Fatalf("expected allow-list rejection, got %v", err)
+	}
+}
+
+func TestFirmwareTimeouts(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	hang := &redfishtest.MockClient{
+		SimpleUpdateFunc: func(ctx context.Context, _ string, _ []string, _, _ string, _ bool) (redfish.UpdateResult, error) {
+			<-ctx.Done()
+			return redfish.UpdateResult{}, ctx.Err()
+		},
+	}
+	fwFile = ""
+	fwHostsCSV = "a,b"
+	fwType = "bmc"
+	fwImageURI = "http://10.0.0.1/firmware.bin"
+	fwDryRun = false
+	fwBatchSize = 1
+	fwTargets = bmcTarget
+	fwExpectedVersion = ""
+	defer func() { fwHostsCSV, fwTimeouts = "", timeouts{} }()
+
+	// A hung host only costs its own --host-timeout; the next host still runs.
+	ok := &redfishtest.MockClient{}
+	ok.SimpleUpdateFunc = func(context.Context, string, []string, string, string, bool) (redfish.UpdateResult, error) {
+		return redfish.UpdateResult{}, nil
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"a": hang, "b": ok})
+	fwTimeouts = timeouts{Host: 50 * time.Millisecond}
+	cmd := firmwareCmd
+	cmd.SetContext(context.Background())
+	if got := exitCode(cmd.RunE(cmd, []string{})); got != exitPartial {
+		t.Errorf("host timeout: exit code %d, want %d", got, exitPartial)
+	}
+	if !slices.Contains(ok.Calls, "SimpleUpdate") {
+		t.Errorf("host timeout: second host calls %v", ok.Calls)
+	}
+
+	// --total-timeout stops the run and reports a partial failure, not an interrupt.
+	ok.Calls = nil
+	fwTimeouts = timeouts{Total: 50 * time.Millisecond}
+	err := cmd.RunE(cmd, []string{})
+	if got := exitCode(err); got != exitPartial {
+		t.Errorf("total timeout: exit code %d (%v), want %d", got, err, exitPartial)
+	}
+	if len(ok.Calls) != 0 {
+		t.Errorf("total timeout: second host should not start, calls %v", ok.Calls)
+	}
+}
+
+func TestLegacyTimeoutFlag(t *testing.T) {
+	var to timeouts
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	to.addFlags(fs, time.Minute, 0)
+	if err := fs.Parse([]string{"--timeout=7s", "--total-timeout=1m"}); err != nil {
+		t.Fatal(err)
+	}
+	want := timeouts{Request: 7 * time.Second, Host: 7 * time.Second, Total: time.Minute}
+	if to != want {
+		t.Errorf("got %+v, want %+v", to, want)
+	}
+}
+
+func TestFirmwareJSONResults(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	update := func(task string, err error, skipped ...string) *redfishtest.MockClient {
+		return &redfishtest.MockClient{
+			SimpleUpdateFunc: func(context.Context, string, []string, string, string, bool) (redfish.UpdateResult, error) {
+				return redfish.UpdateResult{TaskURI: task, Skipped: skipped}, err
+			},
+		}
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{
+		"a": update("/redfish/v1/TaskService/Tasks/1", nil, "/redfish/v1/UpdateService/FirmwareInventory/Node1.BIOS"),
+		"b": update("", fmt.Errorf("%w 1.0", redfish.ErrAlreadyAtVersion)),
+		"c": update("", errors.New("boom")),
+	})
+	fwFile = ""
+	fwHostsCSV = "a,b,c"
+	fwType = "bmc"
+	fwImageURI = "http://10.0.0.1/firmware.bin"
+	fwDryRun = false
+	fwBatchSize = 3
+	fwTargets = bmcTarget
+	fwExpectedVersion = ""
+	fwUpdateFormat = "json"
+	defer func() { fwHostsCSV, fwUpdateFormat = "", "" }()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	cmd := firmwareCmd
+	cmd.SetContext(context.Background())
+	err := cmd.RunE(cmd, []string{})
+	w.Close() //nolint: errcheck
+	os.Stdout = oldStdout
+	if got := exitCode(err); got != exitPartial {
+		t.Errorf("exit code %d, want %d", got, exitPartial)
+	}
+
+	var reports []fwReport
+	if err := json.NewDecoder(r).Decode(&reports); err != nil {
+		t.Fatalf("stdout is not JSON: %v", err)
+	}
+	want := []fwReport{
+		{Host: "a", Action: fwUpdated, TaskURI: "/redfish/v1/TaskService/Tasks/1", SkippedTargets: []string{"/redfish/v1/UpdateService/FirmwareInventory/Node1.BIOS"}},
+		{Host: "b", Action: fwSkipped, SkippedReason: "skipping update: all targets already at expected version 1.0"},
+		{Host: "c", Action: fwFailed, Error: "boom"},
+	}
+	if len(reports) != len(want) {
+		t.Fatalf("got %d reports, want %d: %+v", len(reports), len(want), reports)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(reports[i], want[i]) {
+			t.Errorf("report %d = %+v, want %+v", i, reports[i], want[i])
+		}
+	}
+}
+
+func TestFirmwareCSVResults(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	useMockClients(t, map[string]*redfishtest.MockClient{
+		"a": {SimpleUpdateFunc: func(context.Context, string, []string, string, string, bool) (redfish.UpdateResult, error) {
+			return redfish.UpdateResult{TaskURI: "/redfish/v1/TaskService/Tasks/1"}, nil
+		}},
+		"b": {SimpleUpdateFunc: func(context.Context, string, []string, string, string, bool) (redfish.UpdateResult, error) {
+			return redfish.UpdateResult{}, errors.New("boom, \"quoted\"\nsecond line")
+		}},
+	})
+	fwFile = ""
+	fwHostsCSV = "a,b"
+	fwType = "bmc"
+	fwImageURI = "http://10.0.0.1/firmware.bin"
+	fwDryRun = false
+	fwBatchSize = 2
+	fwTargets = bmcTarget
+	fwExpectedVersion = ""
+	fwUpdateFormat = "csv"
+	defer func() { fwHostsCSV, fwUpdateFormat = "", "" }()
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	cmd := firmwareCmd
+	cmd.SetContext(context.Background())
+	err := cmd.RunE(cmd, []string{})
+	w.Close() //nolint: errcheck
+	os.Stdout = oldStdout
+	if got := exitCode(err); got != exitPartial {
+		t.Errorf("exit code %d, want %d", got, exitPartial)
+	}
+
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		t.Fatalf("stdout is not CSV: %v", err)
+	}
+	want := [][]string{
+		{"host", "alias", "action", "task_uri", "skipped_reason", "skipped_targets", "message", "error"},
+		{"a", "", fwUpdated, "/redfish/v1/TaskService/Tasks/1", "", "", "", ""},
+		{"b", "", fwFailed, "", "", "", "", "boom, \"quoted\"\nsecond line"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %q, want %q", rows, want)
+	}
+}
+
+func TestFirmwareProtocolCapabilities(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	bmc := func(allowed ...string) (*redfishtest.MockClient, *string) {
+		var posted string
+		var us redfish.UpdateService
+		us.Actions.SimpleUpdate.TransferProtocols = allowed
+		us.HTTPPushURI = "/redfish/v1/UpdateService/update"
+		return &redfishtest.MockClient{
+			GetUpdateServiceFunc: func(context.Context) (redfish.UpdateService, error) { return us, nil },
+			SimpleUpdateFunc: func(_ context.Context, _ string, _ []string, proto, _ string, _ bool) (redfish.UpdateResult, error) {
+				posted = proto
+				return redfish.UpdateResult{}, nil
+			},
+		}, &posted
+	}
+	fwFile = ""
+	fwHostsCSV = "a,b"
+	fwType = "bmc"
+	fwImageURI = "http://10.0.0.1/firmware.bin"
+	fwDryRun = false
+	fwBatchSize = 1
+	fwTargets = bmcTarget
+	fwExpectedVersion = ""
+	defer func() { fwHostsCSV, fwProtocol = "", "HTTP" }()
+	cmd := firmwareCmd
+	cmd.SetContext(context.Background())
+
+	// An explicit protocol the BMC does not list fails that host before posting.
+	a, aProto := bmc("HTTP", "HTTPS")
+	b, bProto := bmc("SFTP")
+	useMockClients(t, map[string]*redfishtest.MockClient{"a": a, "b": b})
+	fwProtocol = "https"
+	if got := exitCode(cmd.RunE(cmd, []string{})); got != exitPartial {
+		t.Errorf("explicit protocol: exit code %d, want %d", got, exitPartial)
+	}
+	if *aProto != "HTTPS" || slices.Contains(b.Calls, "SimpleUpdate") {
+		t.Errorf("explicit protocol: a posted %q, b calls %v", *aProto, b.Calls)
+	}
+
+	// auto picks a supported protocol per BMC.
+	a, aProto = bmc("HTTP")
+	b, bProto = bmc("SFTP")
+	useMockClients(t, map[string]*redfishtest.MockClient{"a": a, "b": b})
+	fwProtocol = redfish.ProtocolAuto
+	if err := cmd.RunE(cmd, []string{}); err != nil {
+		t.Fatalf("auto: %v", err)
+	}
+	if *aProto != "HTTP" || *bProto != "SFTP" {
+		t.Errorf("auto: posted %q and %q, want HTTP and SFTP", *aProto, *bProto)
+	}
+}
+
+func TestFirmwareWebhook(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	got := make(chan notify.Summary, 4)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var s notify.Summary
+		if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+			t.Errorf("webhook body: %v", err)
+		}
+		got <- s
+	}))
+	defer hook.Close()
+	update := func(err error) *redfishtest.MockClient {
+		return &redfishtest.MockClient{
+			SimpleUpdateFunc: func(context.Context, string, []string, string, string, bool) (redfish.UpdateResult, error) {
+				return redfish.UpdateResult{}, err
+			},
+		}
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"a": update(nil), "b": update(errors.New("boom"))})
+	webhookURLs, webhookThreshold = []string{hook.URL}, "50%"
+	defer func() { webhookURLs, webhookThreshold, notifiers = nil, "", nil }()
+	if err := configureNotifiers(); err != nil {
+		t.Fatal(err)
+	}
+	fwFile = ""
+	fwHostsCSV = "a,b"
+	fwType = "bmc"
+	fwImageURI = "http://10.0.0.1/firmware.bin"
+	fwDryRun = false
+	fwBatchSize = 1
+	fwTargets = bmcTarget
+	fwExpectedVersion = ""
+	defer func() { fwHostsCSV = "" }()
+
+	cmd := firmwareCmd
+	cmd.SetContext(context.Background())
+	if got := exitCode(cmd.RunE(cmd, []string{})); got != exitPartial {
+		t.Errorf("exit code %d, want %d", got, exitPartial)
+	}
+	close(got)
+	var events []string
+	for s := range got {
+		events = append(events, s.Event)
+		if s.Event != notify.EventFinished {
+			continue
+		}
+		if s.Operation != "firmware" || s.Status != "partial" || s.Total != 2 || s.Failed != 1 || s.Counts[fwUpdated] != 1 {
+			t.Errorf("summary = %+v", s)
+		}
+		if len(s.FailedHosts) != 1 || s.FailedHosts[0] != (notify.HostError{Host: "b", Error: "boom"}) {
+			t.Errorf("failed hosts = %+v", s.FailedHosts)
+		}
+	}
+	slices.Sort(events)
+	if want := []string{notify.EventFinished, notify.EventThreshold}; !slices.Equal(events, want) {
+		t.Errorf("events = %q, want %q", events, want)
+	}
+}
+
+// TestFirmwareServeDir verifies --serve-dir gives each BMC a one-time image URL
+func TestFirmwareServeDir(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "nc.bin"), []byte("firmware"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close() //nolint:errcheck
+
+	fetch := func(u string) (int, string) {
+		resp, err := http.Get(u) //nolint:gosec,noctx
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close() //nolint:errcheck
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+	var mu sync.Mutex
+	images := map[string]string{}
+	bmc := func(host string, fail bool) *redfishtest.MockClient {
+		return &redfishtest.MockClient{
+			SimpleUpdateFunc: func(_ context.Context, image string, _ []string, _, _ string, _ bool) (redfish.UpdateResult, error) {
+				mu.Lock()
+				images[host] = image
+				mu.Unlock()
+				if fail {
+					return redfish.UpdateResult{}, errors.New("400 Bad Request")
+				}
+				// The BMC downloads the image
+				if code, body := fetch(image); code != http.StatusOK || body != "firmware" {
+					t.Errorf("%s: download: %d %q", host, code, body)
+				}
+				return redfish.UpdateResult{}, nil
+			},
+		}
+	}
+	// The image server only answers the BMC a download was granted to
+	useMockClients(t, map[string]*redfishtest.MockClient{"127.0.0.1": bmc("a", false), "localhost": bmc("b", true)})
+	fwFile, fwHostsCSV, fwHostsFile = "", "127.0.0.1,localhost", ""
+	fwImageURI, fwTargets, fwExpectedVersion = "nc.bin", bmcTarget, ""
+	fwDryRun, fwBatchSize, fwActivate, fwUpdateFormat = false, 0, activateNone, ""
+	fwServeDir, fwServeListen, fwServeURL = dir, addr, "http://"+addr
+	defer func() { fwHostsCSV, fwTargets, fwServeDir, fwServeListen, fwServeURL = "", nil, "", ":8000", "" }()
+
+	old := os.Stdout
+	os.Stdout, _ = os.Open(os.DevNull)
+	defer func() { os.Stdout = old }()
+	firmwareCmd.SetContext(context.Background())
+	if got := exitCode(firmwareCmd.RunE(firmwareCmd, nil)); got != exitPartial {
+		t.Errorf("exit code %d, want %d", got, exitPartial)
+	}
+	if images["a"] == images["b"] || !strings.HasPrefix(images["a"], "http://"+addr+"/") || !strings.HasSuffix(images["a"], "/nc.bin") {
+		t.Errorf("images = %v", images)
+	}
+	if fwImages != nil {
+		t.Error("image server still running")
+	}
+
+	// The image must be a file of --serve-dir
+	fwImageURI = "http://10.0.0.1/nc.bin"
+	if got := exitCode(firmwareCmd.RunE(firmwareCmd, nil)); got != exitInvalid {
+		t.Errorf("URL with --serve-dir: exit code %d, want %d", got, exitInvalid)
+	}
+}
+
+func TestFirmwareServeDirLedger(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "nc.bin"), []byte("firmware"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ledgerFile, ledgerWindow = filepath.Join(t.TempDir(), "ledger.jsonl"), time.Hour
+	defer func() { ledgerFile, ledgerWindow, opLedger = "", 12*time.Hour, nil }()
+	if err := configureLedger(); err != nil {
+		t.Fatal(err)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close() //nolint:errcheck
+
+	var images []string
+	m := &redfishtest.MockClient{
+		SimpleUpdateFunc: func(_ context.Context, image string, _ []string, _, _ string, _ bool) (redfish.UpdateResult, error) {
+			images = append(images, image)
+			resp, err := http.Get(image) //nolint:gosec,noctx
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close() //nolint:errcheck
+			return redfish.UpdateResult{}, nil
+		},
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"127.0.0.1": m})
+	fwFile, fwHostsCSV, fwHostsFile = "", "127.0.0.1", ""
+	fwImageURI, fwTargets, fwExpectedVersion = "nc.bin", bmcTarget, ""
+	fwDryRun, fwBatchSize, fwActivate, fwUpdateFormat = false, 0, activateNone, ""
+	fwServeDir, fwServeListen, fwServeURL = dir, addr, "http://"+addr
+	defer func() { fwHostsCSV, fwTargets, fwServeDir, fwServeListen, fwServeURL = "", nil, "", ":8000", "" }()
+
+	old := os.Stdout
+	os.Stdout, _ = os.Open(os.DevNull)
+	defer func() { os.Stdout = old }()
+	// Each run posts a new one-time URL; the ledger still knows the update
+	for run := 1; run <= 2; run++ {
+		firmwareCmd.SetContext(context.Background())
+		if err := firmwareCmd.RunE(firmwareCmd, nil); err != nil {
+			t.Fatalf("run %d: %v", run, err)
+		}
+	}
+	if len(images) != 1 {
+		t.Errorf("SimpleUpdate sent %d times, want 1: %v", len(images), images)
+	}
+
+	// A changed image is a new update
+	if err := os.WriteFile(filepath.Join(dir, "nc.bin"), []byte("firmware 2"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	firmwareCmd.SetContext(context.Background())
+	if err := firmwareCmd.RunE(firmwareCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(images) != 2 {
+		t.Errorf("changed image: SimpleUpdate sent %d times, want 2", len(images))
+	}
+}
+
+func TestFirmwareMaxFailures(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	update := func(err error) *redfishtest.MockClient {
+		return &redfishtest.MockClient{
+			SimpleUpdateFunc: func(context.Context, string, []string, string, string, bool) (redfish.UpdateResult, error) {
+				return redfish.UpdateResult{}, err
+			},
+		}
+	}
+	bad := errors.New("image rejected")
+	fwFile, fwType, fwImageURI, fwDryRun, fwTargets, fwExpectedVersion = "", "bmc", "http://10.0.0.1/firmware.bin", false, bmcTarget, ""
+	fwHostsCSV, fwBatchSize = "a,b,c,d,e", 1
+	defer func() { fwHostsCSV, fwMaxFailures, fwFailedOut = "", "", "" }()
+
+	for _, limit := range []string{"1", "20%"} {
+		mocks := map[string]*redfishtest.MockClient{"a": update(bad), "b": update(bad), "c": update(nil), "d": update(nil), "e": update(nil)}
+		useMockClients(t, mocks)
+		fwMaxFailures = limit
+		fwFailedOut = filepath.Join(t.TempDir(), "failed.txt")
+
+		oldStderr := os.Stderr
+		r, w, _ := os.Pipe()
+		os.Stderr = w
+		cmd := firmwareCmd
+		cmd.SetContext(context.Background())
+		err := cmd.RunE(cmd, []string{})
+		w.Close() //nolint: errcheck
+		os.Stderr = oldStderr
+		var buf bytes.Buffer
+		io.Copy(&buf, r) //nolint: errcheck
+
+		if got := exitCode(err); got != exitPartial {
+			t.Fatalf("--max-failures %s: exit code %d, want %d (%v)", limit, got, exitPartial, err)
+		}
+		for _, h := range []string{"c", "d", "e"} {
+			if len(mocks[h].Calls) != 0 {
+				t.Errorf("--max-failures %s: %s was started: %v", limit, h, mocks[h].Calls)
+			}
+		}
+		if !strings.Contains(buf.String(), "2 host(s) attempted, 2 failed, 3 not started") || !strings.Contains(buf.String(), "Not started: c, d, e") {
+			t.Errorf("--max-failures %s: unexpected summary: %s", limit, buf.String())
+		}
+		out, _ := os.ReadFile(fwFailedOut)
+		if !strings.Contains(string(out), "c # not started: --max-failures "+limit+" exceeded") {
+			t.Errorf("--max-failures %s: failed hosts file:\n%s", limit, out)
+		}
+	}
+
+	fwMaxFailures = "0"
+	cmd := firmwareCmd
+	cmd.SetContext(context.Background())
+	if got := exitCode(cmd.RunE(cmd, []string{})); got != exitInvalid {
+		t.Errorf("--max-failures 0: exit code %d, want %d", got, exitInvalid)
+	}
+}
@@ -0,0 +1,522 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"bootstrap/internal/adaptive"
+	"bootstrap/internal/diag"
+	"bootstrap/internal/topology"
+	"bootstrap/internal/xname"
+	"bootstrap/pkg/inventory"
+	"bootstrap/pkg/redfish"
+)
+
+// newRedfishClient opens a Redfish client for a BMC. Tests replace it to run
+// commands against a mock.
+var newRedfishClient = redfish.New
+
+// resolveHosts returns the BMC hosts to target: the comma-separated hostsCSV when
+// set, else the hosts listed in hostsFile, otherwise the bmcs[] of the inventory
+// file (IP, falling back to xname). Listed hosts that name a bmcs[] xname or the
+// alias of a BMC or node of the inventory file, when one is given, are replaced
+// by that BMC's address. Duplicates are dropped with a warning (see dedupeHosts
+// and dedupeBMCs), and so are hosts set aside in the quarantine file (see
+// skipQuarantined). With --tag only the BMCs the tags select are kept (see
+// selectTagged). The aliases of the hosts are recorded for hostName.
+func resolveHosts(file, hostsCSV, hostsFile string) ([]string, error) {
+	doc, err := loadInventory(file)
+	if err != nil {
+		return nil, err
+	}
+	hostNames, resolvedBMCs = aliasNames(doc), doc.BMCs
+	hosts, err := hostList(hostsCSV, hostsFile, doc)
+	if err != nil {
+		return nil, err
+	}
+	if hosts == nil {
+		if len(doc.BMCs) == 0 {
+			return nil, invalidf("input must contain non-empty bmcs[]")
+		}
+		hosts = bmcHosts(dedupeBMCs(doc.BMCs))
+	}
+	if hosts, err = selectTagged(hosts, doc, file); err != nil {
+		return nil, err
+	}
+	return skipQuarantined(hosts, hostXnames(hosts, doc.BMCs))
+}
+
+// orderHosts puts hosts in the rollout order named by --order (see
+// topology.Order), using the xnames the bmcs[] of file give them.
+func orderHosts(hosts []string, order, file string) ([]string, error) {
+	if order == "" || order == topology.OrderInventory {
+		return hosts, nil
+	}
+	bmcs, err := loadBMCs(file)
+	if err != nil {
+		return nil, err
+	}
+	xnames := hostXnames(hosts, bmcs)
+	list := make([]string, len(hosts))
+	for i, h := range hosts {
+		list[i] = xnames[h]
+	}
+	idx, err := topology.Order(list, order, rand.Shuffle)
+	if err != nil {
+		return nil, invalidf("--order: %v", err)
+	}
+	out := make([]string, len(hosts))
+	for i, j := range idx {
+		out[i] = hosts[j]
+	}
+	return out, nil
+}
+
+// orderUsage is the help of the --order flags.
+var orderUsage = "order the hosts are worked through in: " + strings.Join(topology.Orders, ", ") + "; by-chassis and by-slot-interleaved spread each batch over chassis and over the node cards of a blade"
+
+// tagSelectors is --tag: key=value, key!=value or key selectors that must all
+// match the tags of a BMC, or of one of its nodes, for it to be targeted.
+var tagSelectors []string
+
+// selectTagged keeps the hosts naming a BMC of doc (read from file) that
+// --tag selects, and returns hosts unchanged without --tag. Nodes inherit the
+// tags of their BMC.
+func selectTagged(hosts []string, doc inventory.FileFormat, file string) ([]string, error) {
+	if len(tagSelectors) == 0 {
+		return hosts, nil
+	}
+	sels, err := parseTagSelectors(file)
+	if err != nil {
+		return nil, err
+	}
+	want := map[string]bool{}
+	for _, b := range doc.SelectBMCs(sels) {
+		want[canonicalHost(b.IP)], want[canonicalHost(b.Xname)] = true, true
+	}
+	delete(want, "")
+	var out []string
+	for _, h := range hosts {
+		if want[canonicalHost(h)] {
+			out = append(out, h)
+		}
+	}
+	if len(out) == 0 {
+		return nil, invalidf("no BMC of %s matches --tag %s", file, strings.Join(tagSelectors, ","))
+	}
+	return out, nil
+}
+
+// parseTagSelectors parses --tag, which needs an inventory file to read tags from.
+func parseTagSelectors(file string) ([]inventory.Selector, error) {
+	if file == "" {
+		return nil, invalidf("--tag needs an inventory --file")
+	}
+	sels, err := inventory.ParseSelectors(tagSelectors)
+	if err != nil {
+		return nil, invalidf("--tag: %v", err)
+	}
+	return sels, nil
+}
+
+// loadInventory reads the inventory file, or returns an empty one when file is empty.
+func loadInventory(file string) (inventory.FileFormat, error) {
+	if file == "" {
+		return inventory.FileFormat{}, nil
+	}
+	return readInventory(file)
+}
+
+// loadBMCs returns the bmcs[] of the inventory file, or nil when file is empty.
+func loadBMCs(file string) ([]inventory.Entry, error) {
+	doc, err := loadInventory(file)
+	return doc.BMCs, err
+}
+
+// hostNames maps BMC hosts to their aliases for output; set by resolveHosts.
+var hostNames map[string]string
+
+// hostName returns the alias of a BMC host for output, or host itself when it
+// has none.
+func hostName(host string) string {
+	return cmp.Or(hostAlias(host), host)
+}
+
+// hostAlias returns the alias of a BMC host, or "".
+func hostAlias(host string) string {
+	return hostNames[canonicalHost(host)]
+}
+
+// aliasNames maps the address and xname of each BMC of doc to its alias, or to
+// the aliases of its nodes joined by "+" when the BMC has none.
+func aliasNames(doc inventory.FileFormat) map[string]string {
+	nodeAliases := map[string][]string{}
+	for _, n := range doc.Nodes {
+		if n.Alias != "" {
+			bmc := strings.ToLower(xname.NodeToBMCXname(n.Xname))
+			nodeAliases[bmc] = append(nodeAliases[bmc], n.Alias)
+		}
+	}
+	out := map[string]string{}
+	for _, b := range doc.BMCs {
+		name := cmp.Or(b.Alias, strings.Join(nodeAliases[strings.ToLower(b.Xname)], "+"))
+		if name == "" {
+			continue
+		}
+		for _, h := range []string{b.IP, b.Xname} {
+			if h != "" {
+				out[canonicalHost(h)] = name
+			}
+		}
+	}
+	return out
+}
+
+// aliasXnames maps the lowercased alias of each entry of doc to its xname.
+func aliasXnames(doc inventory.FileFormat) map[string]string {
+	out := map[string]string{}
+	for _, list := range [][]inventory.Entry{doc.BMCs, doc.Nodes} {
+		for _, e := range list {
+			if a := strings.ToLower(e.Alias); a != "" && e.Xname != "" && out[a] == "" {
+				out[a] = e.Xname
+			}
+		}
+	}
+	return out
+}
+
+// hostList returns the hosts given by --hosts (hostsCSV) or, when that is empty,
+// by --hosts-file, or nil when neither is set. Entries matching the xname of one
+// of doc's BMCs, or the alias of one of its BMCs or nodes, are resolved to the
+// BMC's address.
+func hostList(hostsCSV, hostsFile string, doc inventory.FileFormat) ([]string, error) {
+	var hosts []string
+	switch {
+	case strings.TrimSpace(hostsCSV) != "":
+		hosts = strings.Split(hostsCSV, ",")
+	case hostsFile != "":
+		var err error
+		if hosts, err = readHostsFile(hostsFile); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, nil
+	}
+	aliases := aliasXnames(doc)
+	topo := topology.Build(doc)
+	var out []string
+	for _, h := range hosts {
+		if group := topologyGroup(topo, h, aliases); group != nil {
+			out = append(out, bmcHosts(group)...)
+			continue
+		}
+		out = append(out, bmcAddr(h, doc.BMCs, aliases))
+	}
+	return dedupeHosts(out), nil
+}
+
+// topologyGroup returns the bmcs[] entries of the blades below h when h names a
+// cabinet, chassis or slot of the inventory, and nil otherwise. A blade, node or
+// alias is left to bmcAddr.
+func topologyGroup(topo *topology.Topology, h string, aliases map[string]string) []inventory.Entry {
+	h = strings.TrimSpace(h)
+	if _, ok := aliases[strings.ToLower(h)]; ok {
+		return nil
+	}
+	c := topo.Find(h)
+	if c == nil || c.Kind == topology.Blade || c.Kind == topology.Node {
+		return nil
+	}
+	return topo.BMCs(h)
+}
+
+// bmcAddr returns the IP of the bmcs[] entry whose xname is name, or name itself.
+// A name found in aliases is first replaced by the xname of its BMC. Behind
+// --aggregator xnames are kept as they are.
+func bmcAddr(name string, bmcs []inventory.Entry, aliases map[string]string) string {
+	if x, ok := aliases[strings.ToLower(strings.TrimSpace(name))]; ok {
+		name = cmp.Or(xname.NodeToBMCXname(x), x)
+	}
+	if aggregatorHost != "" {
+		return name
+	}
+	for _, b := range bmcs {
+		if b.IP != "" && strings.EqualFold(b.Xname, strings.TrimSpace(name)) {
+			return b.IP
+		}
+	}
+	return name
+}
+
+// hostXnames maps each of hosts to its BMC xname: the host itself when it is an
+// xname, else the xname of the bmcs[] entry with that address.
+func hostXnames(hosts []string, bmcs []inventory.Entry) map[string]string {
+	byAddr := map[string]string{}
+	for _, b := range bmcs {
+		if b.Xname != "" {
+			byAddr[canonicalHost(b.IP)] = b.Xname
+			byAddr[canonicalHost(b.Xname)] = b.Xname
+		}
+	}
+	out := map[string]string{}
+	for _, h := range hosts {
+		if x, ok := byAddr[canonicalHost(h)]; ok {
+			out[h] = x
+		} else if xname.Chassis(h) != "" {
+			out[h] = h
+		}
+	}
+	return out
+}
+
+// readHostsFile reads BMC hosts one per line. Blank lines and text after '#' are
+// ignored, so a file written by writeFailedHosts can be passed back as is.
+func readHostsFile(path string) ([]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, invalid(err)
+	}
+	var hosts []string
+	sc := bufio.NewScanner(bytes.NewReader(raw))
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		if f := strings.Fields(line); len(f) > 0 {
+			hosts = append(hosts, f[0])
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, invalid(err)
+	}
+	if len(hosts) == 0 {
+		return nil, invalidf("%s lists no hosts", path)
+	}
+	return hosts, nil
+}
+
+// bmcHosts returns the address of each BMC: its IP, falling back to the xname.
+// Behind --aggregator the xname is used when set.
+func bmcHosts(bmcs []inventory.Entry) []string {
+	hosts := make([]string, 0, len(bmcs))
+	for _, b := range bmcs {
+		host := b.IP
+		if host == "" || aggregatorHost != "" && b.Xname != "" {
+			host = b.Xname
+		}
+		hosts = append(hosts, canonicalHost(host))
+	}
+	return hosts
+}
+
+// canonicalHost normalizes a BMC address so that spellings of the same BMC
+// compare equal: lower case, without a URL scheme, path or the default HTTPS port.
+func canonicalHost(h string) string {
+	h = strings.ToLower(strings.TrimSpace(h))
+	h = strings.TrimPrefix(strings.TrimPrefix(h, "https://"), "http://")
+	if i := strings.Index(h, "/"); i >= 0 {
+		h = h[:i]
+	}
+	return strings.TrimSuffix(h, ":443")
+}
+
+// dedupeHosts canonicalizes hosts, drops empty entries and repeats, and warns
+// about the duplicates it collapsed.
+func dedupeHosts(hosts []string) []string {
+	out := make([]string, 0, len(hosts))
+	seen := map[string]bool{}
+	var dups []string
+	for _, h := range hosts {
+		c := canonicalHost(h)
+		if c == "" {
+			continue
+		}
+		if seen[c] {
+			dups = append(dups, fmt.Sprintf("%s (same as %s)", strings.TrimSpace(h), c))
+			continue
+		}
+		seen[c] = true
+		out = append(out, c)
+	}
+	warnDuplicates(dups)
+	return out
+}
+
+// dedupeBMCs drops bmcs[] entries naming a BMC already listed, by xname or by
+// IP, so that a BMC listed once by IP and once by xname is only contacted once.
+func dedupeBMCs(bmcs []inventory.Entry) []inventory.Entry {
+	out := make([]inventory.Entry, 0, len(bmcs))
+	seen := map[string]string{} // xname or IP -> host of the entry kept
+	var dups []string
+	for _, b := range bmcs {
+		keys := []string{strings.ToLower(b.Xname), canonicalHost(b.IP)}
+		kept := ""
+		for _, k := range keys {
+			if k != "" && seen[k] != "" {
+				kept = seen[k]
+				break
+			}
+		}
+		host := bmcHosts([]inventory.Entry{b})[0]
+		if kept != "" {
+			dups = append(dups, fmt.Sprintf("%s (same as %s)", host, kept))
+			// An xname-only duplicate may still add an IP to match later entries
+			for _, k := range keys {
+				if k != "" && seen[k] == "" {
+					seen[k] = kept
+				}
+			}
+			continue
+		}
+		for _, k := range keys {
+			if k != "" {
+				seen[k] = host
+			}
+		}
+		out = append(out, b)
+	}
+	warnDuplicates(dups)
+	return out
+}
+
+func warnDuplicates(dups []string) {
+	if len(dups) > 0 {
+		fmt.Fprintf(os.Stderr, "WARN: ignoring %d duplicate host(s): %s\n", len(dups), strings.Join(dups, ", "))
+	}
+}
+
+// nodeTargets returns the nodes[] entries of doc (read from file) named in
+// namesCSV by xname or alias, or every node when namesCSV is empty. With needIP
+// only nodes with an IP are returned, and naming one without is an error. With
+// --tag only the nodes the tags select are returned.
+func nodeTargets(doc inventory.FileFormat, file, namesCSV string, needIP bool) ([]inventory.Entry, error) {
+	if len(tagSelectors) > 0 {
+		sels, err := parseTagSelectors(file)
+		if err != nil {
+			return nil, err
+		}
+		doc.Nodes = doc.SelectNodes(sels)
+		if len(doc.Nodes) == 0 {
+			return nil, invalidf("no node of %s matches --tag %s", file, strings.Join(tagSelectors, ","))
+		}
+	}
+	if strings.TrimSpace(namesCSV) == "" {
+		var out []inventory.Entry
+		for _, n := range doc.Nodes {
+			if n.IP != "" || !needIP {
+				out = append(out, n)
+			}
+		}
+		switch {
+		case len(out) > 0:
+			return out, nil
+		case needIP:
+			return nil, invalidf("no nodes[] entry of %s has an IP; run discover first", file)
+		}
+		return nil, invalidf("%s has no nodes[] entries; run discover first", file)
+	}
+	var out []inventory.Entry
+	for _, name := range strings.Split(namesCSV, ",") {
+		name = strings.TrimSpace(name)
+		i := slices.IndexFunc(doc.Nodes, func(n inventory.Entry) bool {
+			return strings.EqualFold(n.Xname, name) || (n.Alias != "" && strings.EqualFold(n.Alias, name))
+		})
+		switch {
+		case i < 0:
+			return nil, invalidf("%s is not in nodes[] of %s", name, file)
+		case needIP && doc.Nodes[i].IP == "":
+			return nil, invalidf("%s has no IP in %s", name, file)
+		}
+		out = append(out, doc.Nodes[i])
+	}
+	return out, nil
+}
+
+// adaptiveBatch is --adaptive-batch; adaptiveCeiling is its limit when the
+// command's --batch-size does not set one.
+var adaptiveBatch bool
+
+const adaptiveCeiling = 32
+
+// forEachHost calls fn for every host with at most batch calls in flight (serially
+// when batch <= 1). With --adaptive-batch the number in flight instead follows
+// how the BMCs answer, up to batch (or adaptiveCeiling). Hosts not yet started
+// when ctx is cancelled are passed to aborted instead.
+func forEachHost(ctx context.Context, hosts []string, batch int, fn func(ctx context.Context, host string), aborted func(host string)) {
+	if adaptiveBatch {
+		ceiling := batch
+		if batch <= 1 {
+			ceiling = adaptiveCeiling
+		}
+		forEachHostAdaptive(ctx, hosts, ceiling, fn, aborted)
+		return
+	}
+	if batch <= 1 {
+		for _, h := range hosts {
+			if ctx.Err() != nil {
+				aborted(h)
+				continue
+			}
+			fn(ctx, h)
+		}
+		return
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, batch)
+	for _, h := range hosts {
+		wg.Add(1)
+		go func(h string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				aborted(h)
+				return
+			}
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				aborted(h)
+				return
+			}
+			fn(ctx, h)
+		}(h)
+	}
+	wg.Wait()
+}
+
+// forEachHostAdaptive is forEachHost under an adaptive.Limiter fed by every
+// Redfish request of the run.
+func forEachHostAdaptive(ctx context.Context, hosts []string, ceiling int, fn func(ctx context.Context, host string), aborted func(host string)) {
+	lim := adaptive.New(ceiling, diag.Logf)
+	remove := redfish.AddRequestObserver(func(_ string, d time.Duration, status int, err error) { lim.Observe(d, status, err) })
+	defer remove()
+	var wg sync.WaitGroup
+	for _, h := range hosts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if lim.Acquire(ctx) != nil {
+				aborted(h)
+				return
+			}
+			defer lim.Release()
+			if ctx.Err() != nil {
+				aborted(h)
+				return
+			}
+			fn(ctx, h)
+		}()
+	}
+	wg.Wait()
+}
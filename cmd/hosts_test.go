@@ -0,0 +1,328 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"bootstrap/internal/adaptive"
+	"bootstrap/pkg/inventory"
+)
+
+func TestResolveHostsDedupes(t *testing.T) {
+	got, err := resolveHosts("", "10.0.0.1, https://10.0.0.1:443/,BMC-A,bmc-a,,10.0.0.2", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"10.0.0.1", "bmc-a", "10.0.0.2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("csv: got %v, want %v", got, want)
+	}
+
+	// The same BMC listed by IP and again by xname (with and without its IP)
+	inv := filepath.Join(t.TempDir(), "inventory.yaml")
+	doc := `bmcs:
+  - xname: x1000c0s0b0
+    ip: 10.0.0.1
+  - xname: x1000c0s0b0
+  - xname: x1000c0s1b0
+    ip: 10.0.0.2
+  - xname: x1000c0s9b0
+    ip: 10.0.0.2
+`
+	if err := os.WriteFile(inv, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err = resolveHosts(inv, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"10.0.0.1", "10.0.0.2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("inventory: got %v, want %v", got, want)
+	}
+}
+
+func TestResolveHostsFile(t *testing.T) {
+	dir := t.TempDir()
+	inv := filepath.Join(dir, "inventory.yaml")
+	doc := `bmcs:
+  - xname: x1000c0s0b0
+    ip: 10.0.0.1
+  - xname: x1000c0s1b0
+    ip: 10.0.0.2
+`
+	list := filepath.Join(dir, "hosts.txt")
+	hosts := `# rack 1
+x1000c0s1b0   # resolved through the inventory
+10.0.0.9
+
+10.0.0.2 # same BMC as x1000c0s1b0
+`
+	if err := os.WriteFile(inv, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(list, []byte(hosts), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := resolveHosts(inv, "", list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"10.0.0.2", "10.0.0.9"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Without an inventory xnames are used as host names
+	got, err = resolveHosts("", "", list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"x1000c0s1b0", "10.0.0.9", "10.0.0.2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("no inventory: got %v, want %v", got, want)
+	}
+
+	if err := os.WriteFile(list, []byte("# nothing yet\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resolveHosts("", "", list); exitCode(err) != exitInvalid {
+		t.Errorf("empty hosts file: got %v", err)
+	}
+}
+
+func TestAggregatorAddressesBMCsByXname(t *testing.T) {
+	dir := t.TempDir()
+	m := filepath.Join(dir, "agg.yaml")
+	if err := os.WriteFile(m, []byte("X1000C0S0B0: /redfish/v1/Aggregate/x1000c0s0b0\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	aggregatorHost, aggregatorMap = "https://agg.example:8443", m
+	defer func() {
+		aggregatorHost, aggregatorMap = "", ""
+		_ = configureAggregator()
+	}()
+	if err := configureAggregator(); err != nil {
+		t.Fatal(err)
+	}
+
+	bmcs := []inventory.Entry{{Xname: "x1000c0s0b0", IP: "10.1.0.2"}, {IP: "10.1.0.3"}}
+	if got, want := bmcHosts(bmcs), []string{"x1000c0s0b0", "10.1.0.3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("bmcHosts = %v, want %v", got, want)
+	}
+	if got := redfishHost("10.1.0.2", bmcs); got != "x1000c0s0b0" {
+		t.Errorf("redfishHost = %s", got)
+	}
+
+	aggregatorMap = ""
+	if err := configureAggregator(); exitCode(err) != exitInvalid {
+		t.Errorf("--aggregator without a map or prefix: got %v", err)
+	}
+}
+
+func TestResolveHostsAliases(t *testing.T) {
+	inv := filepath.Join(t.TempDir(), "inventory.yaml")
+	doc := `bmcs:
+  - xname: x1000c0s0b0
+    ip: 10.0.0.1
+    alias: rack1-bmc0
+  - xname: x1000c0s1b0
+    ip: 10.0.0.2
+  - xname: x1000c0s2b0
+    ip: 10.0.0.3
+nodes:
+  - xname: x1000c0s1b0n0
+    alias: login01
+  - xname: x1000c0s1b0n1
+    alias: login02
+  - xname: x1000c0s0b0n0
+    alias: nid000001
+`
+	if err := os.WriteFile(inv, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { hostNames = nil })
+
+	// Node aliases select their BMC; both nodes of one BMC target it once
+	got, err := resolveHosts(inv, "LOGIN01,login02,rack1-bmc0,10.0.0.3", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"10.0.0.2", "10.0.0.1", "10.0.0.3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// A BMC's own alias wins over those of its nodes
+	for host, want := range map[string]string{
+		"10.0.0.1":    "rack1-bmc0",
+		"10.0.0.2":    "login01+login02",
+		"x1000c0s1b0": "login01+login02",
+		"10.0.0.3":    "10.0.0.3",
+	} {
+		if got := hostName(host); got != want {
+			t.Errorf("hostName(%s) = %q, want %q", host, got, want)
+		}
+	}
+}
+
+func TestResolveHostsTopologyGroups(t *testing.T) {
+	inv := filepath.Join(t.TempDir(), "inventory.yaml")
+	doc := `bmcs:
+  - xname: x1000c0s0b0
+    ip: 10.0.0.1
+  - xname: x1000c0s1b0
+    ip: 10.0.0.2
+  - xname: x1000c1s0b0
+    ip: 10.0.0.3
+  - xname: x1001c0s0b0
+    ip: 10.0.0.4
+`
+	if err := os.WriteFile(inv, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { hostNames = nil })
+
+	for hosts, want := range map[string][]string{
+		"x1000c0":             {"10.0.0.1", "10.0.0.2"},
+		"x1000":               {"10.0.0.1", "10.0.0.2", "10.0.0.3"},
+		"x1000c1s0,x1000c0s0": {"10.0.0.3", "10.0.0.1"},
+		"x1001,x1001c0s0b0":   {"10.0.0.4"},
+		"x1000c0s1b0n1":       {"x1000c0s1b0n1"}, // nodes are not groups
+	} {
+		got, err := resolveHosts(inv, hosts, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", hosts, got, want)
+		}
+	}
+}
+
+func TestResolveHostsTags(t *testing.T) {
+	inv := filepath.Join(t.TempDir(), "inventory.yaml")
+	doc := `bmcs:
+  - xname: x1000c0s0b0
+    ip: 10.0.0.1
+    tags: {rack: r1}
+  - xname: x1000c0s1b0
+    ip: 10.0.0.2
+    tags: {rack: r1, pool: canary}
+  - xname: x1000c0s2b0
+    ip: 10.0.0.3
+    tags: {rack: r2}
+nodes:
+  - xname: x1000c0s0b0n0
+    tags: {role: login}
+  - xname: x1000c0s2b0n1
+    tags: {role: compute}
+    ip: 10.100.0.3
+`
+	if err := os.WriteFile(inv, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { hostNames, tagSelectors = nil, nil })
+
+	for _, tt := range []struct {
+		tags  []string
+		hosts string
+		want  []string
+	}{
+		{[]string{"rack=r1"}, "", []string{"10.0.0.1", "10.0.0.2"}},
+		{[]string{"rack=r*", "pool!=canary"}, "", []string{"10.0.0.1", "10.0.0.3"}},
+		{[]string{"pool"}, "", []string{"10.0.0.2"}},
+		{[]string{"role=compute"}, "", []string{"10.0.0.3"}},                // through a node
+		{[]string{"role=login", "rack=r1"}, "", []string{"10.0.0.1"}},       // the node inherits rack
+		{[]string{"rack=r1"}, "x1000c0s1b0,10.0.0.3", []string{"10.0.0.2"}}, // and --hosts
+	} {
+		tagSelectors = tt.tags
+		got, err := resolveHosts(inv, tt.hosts, "")
+		if err != nil {
+			t.Fatalf("%v: %v", tt.tags, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v %s: got %v, want %v", tt.tags, tt.hosts, got, tt.want)
+		}
+	}
+
+	tagSelectors = []string{"rack=r9"}
+	if _, err := resolveHosts(inv, "", ""); exitCode(err) != exitInvalid {
+		t.Errorf("no match: %v", err)
+	}
+	tagSelectors = []string{"rack=r1"}
+	if _, err := resolveHosts("", "10.0.0.1", ""); exitCode(err) != exitInvalid {
+		t.Errorf("without --file: %v", err)
+	}
+
+	// Node commands select nodes by their own and their BMC's tags
+	d, err := readInventory(inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tagSelectors = []string{"rack=r2"}
+	nodes, err := nodeTargets(d, inv, "", true)
+	if err != nil || len(nodes) != 1 || nodes[0].Xname != "x1000c0s2b0n1" {
+		t.Errorf("nodeTargets = %v, %v", nodes, err)
+	}
+}
+
+func TestOrderHosts(t *testing.T) {
+	inv := filepath.Join(t.TempDir(), "inventory.yaml")
+	doc := `bmcs:
+  - {xname: x1000c0s0b0, ip: 10.0.0.1}
+  - {xname: x1000c0s0b1, ip: 10.0.0.2}
+  - {xname: x1000c1s0b0, ip: 10.0.0.3}
+  - {xname: x1000c1s0b1, ip: 10.0.0.4}
+`
+	if err := os.WriteFile(inv, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	hosts, err := resolveHosts(inv, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := orderHosts(append(hosts, "10.9.9.9"), "by-chassis", inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"10.0.0.1", "10.0.0.3", "10.9.9.9", "10.0.0.2", "10.0.0.4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("by-chassis: got %v, want %v", got, want)
+	}
+	if got, _ := orderHosts(hosts, "inventory", inv); !reflect.DeepEqual(got, hosts) {
+		t.Errorf("inventory: got %v", got)
+	}
+	if _, err := orderHosts(hosts, "sideways", inv); exitCode(err) != exitInvalid {
+		t.Errorf("unknown order: %v", err)
+	}
+}
+
+func TestForEachHostAdaptive(t *testing.T) {
+	adaptiveBatch = true
+	defer func() { adaptiveBatch = false }()
+	hosts := []string{"a", "b", "c", "d", "e", "f"}
+	var mu sync.Mutex
+	ran, inFlight, peak := map[string]bool{}, 0, 0
+	forEachHost(context.Background(), hosts, 10, func(_ context.Context, h string) {
+		mu.Lock()
+		ran[h] = true
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}, func(h string) { t.Errorf("%s aborted", h) })
+	if len(ran) != len(hosts) {
+		t.Fatalf("ran %d of %d hosts", len(ran), len(hosts))
+	}
+	// Without Redfish answers to learn from, the limit stays where it starts
+	if peak > adaptive.Start {
+		t.Fatalf("%d hosts in flight, want at most %d", peak, adaptive.Start)
+	}
+}
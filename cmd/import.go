@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"bootstrap/internal/leases"
+	"bootstrap/internal/neighbor"
+	"bootstrap/internal/sls"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	impFile    string
+	impDnsmasq string
+	impISC     string
+	impKea     string
+	impDryRun  bool
+
+	impInterface string
+	impARPTable  string
+	impSweep     string
+	impSweepWait time.Duration
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import data from external sources into the inventory",
+}
+
+var importLeasesCmd = &cobra.Command{
+	Use:   "leases",
+	Short: "Fill in bmcs[] IPs from DHCP server leases matched by MAC",
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if impFile == "" {
+			return invalidf("--file is required")
+		}
+		if impDnsmasq == "" && impISC == "" && impKea == "" {
+			return invalidf("at least one of --dnsmasq, --isc or --kea is required")
+		}
+		sources := []struct{ path, format string }{
+			{impDnsmasq, "dnsmasq"},
+			{impISC, "isc"},
+			{impKea, "kea"},
+		}
+		var all []leases.Lease
+		for _, s := range sources {
+			if s.path == "" {
+				continue
+			}
+			ls, err := leases.Load(s.path, s.format)
+			if err != nil {
+				return invalidf("read %s leases: %w", s.format, err)
+			}
+			all = append(all, ls...)
+		}
+
+		return applyBindings(all, "lease(s)")
+	},
+}
+
+var importSLSCmd = &cobra.Command{
+	Use:   "sls DUMP",
+	Short: "Fill the inventory from a CSM System Layout Service dump",
+	Long: `Convert an SLS dump (GET /v1/dumpstate, or the hardware alone from
+GET /v1/hardware) into bmcs[] and nodes[] and merge them into --file as
+'inventory pull' does; without --file the inventory is printed as YAML.
+
+SLS Node hardware becomes nodes[], with the first SLS alias as the alias, and
+each node's parent NodeBMC becomes a bmcs[] entry. BMC IPs are the reservations
+of the HMN networks and node IPs those of the NMN networks, matched by xname,
+alias or comment. SLS records no MACs: discover fills them in.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		raw, err := os.ReadFile(args[0])
+		if err != nil {
+			return invalid(err)
+		}
+		d, err := sls.Parse(raw)
+		if err != nil {
+			return invalidf("%s: %w", args[0], err)
+		}
+		doc := sls.ToInventory(d)
+		if len(doc.Nodes) == 0 && len(doc.BMCs) == 0 {
+			return invalidf("%s: no Node or NodeBMC hardware", args[0])
+		}
+		return mergeInventory(impFile, doc, args[0], impDryRun)
+	},
+}
+
+var importARPCmd = &cobra.Command{
+	Use:   "arp",
+	Short: "Fill in bmcs[] IPs from the local ARP/neighbor table matched by MAC",
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if impFile == "" {
+			return invalidf("--file is required")
+		}
+		if impSweep != "" {
+			if err := neighbor.Sweep(cmd.Context(), impSweep); err != nil {
+				return fmt.Errorf("sweep %s: %w", impSweep, err)
+			}
+			// give outstanding ARP resolutions a moment to complete
+			time.Sleep(impSweepWait)
+		}
+		entries, err := neighbor.ReadTable(impARPTable, impInterface)
+		if err != nil {
+			return invalidf("read neighbor table: %w", err)
+		}
+		bindings := make([]leases.Lease, 0, len(entries))
+		for _, e := range entries {
+			bindings = append(bindings, leases.Lease{MAC: e.MAC, IP: e.IP})
+		}
+		return applyBindings(bindings, "neighbor entries")
+	},
+}
+
+// applyBindings updates bmcs[] IPs in --file from MAC/IP bindings and writes it back.
+func applyBindings(bindings []leases.Lease, what string) error {
+	if !impDryRun {
+		unlock, err := lockInventory(impFile)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+	doc, err := readInventory(impFile)
+	if err != nil {
+		return err
+	}
+	if len(doc.BMCs) == 0 {
+		return invalidf("input must contain non-empty bmcs[]")
+	}
+
+	changed := leases.Apply(doc.BMCs, bindings)
+	updated := map[string]bool{}
+	for _, x := range changed {
+		updated[x] = true
+	}
+	for _, b := range doc.BMCs {
+		if updated[b.Xname] {
+			fmt.Printf("%s (%s): IP %s\n", b.Xname, b.MAC, b.IP)
+		}
+	}
+	if impDryRun {
+		fmt.Printf("[dry-run] would update %d BMC IP(s) in %s\n", len(changed), impFile)
+		return nil
+	}
+	if err := writeInventory(impFile, &doc); err != nil {
+		return err
+	}
+	fmt.Printf("Updated %s with %d BMC IP(s) from %d %s\n", impFile, len(changed), len(bindings), what)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(importLeasesCmd)
+	importCmd.AddCommand(importARPCmd)
+	importCmd.AddCommand(importSLSCmd)
+	importCmd.PersistentFlags().StringVarP(&impFile, "file", "f", "", "Inventory file updated in place (bmcs[] IPs for leases and arp)")
+	importCmd.PersistentFlags().BoolVar(&impDryRun, "dry-run", false, "plan only: print the changes without writing --file")
+	importLeasesCmd.Flags().StringVar(&impDnsmasq, "dnsmasq", "", "dnsmasq leases file, e.g. /var/lib/misc/dnsmasq.leases")
+	importLeasesCmd.Flags().StringVar(&impISC, "isc", "", "ISC dhcpd leases file, e.g. /var/lib/dhcp/dhcpd.leases")
+	importLeasesCmd.Flags().StringVar(&impKea, "kea", "", "Kea DHCPv4 memfile lease CSV, e.g. /var/lib/kea/kea-leases4.csv")
+	importARPCmd.Flags().StringVar(&impInterface, "interface", "", "only use neighbor entries learned on this interface, e.g. eth1")
+	importARPCmd.Flags().StringVar(&impARPTable, "arp-table", neighbor.DefaultARPTable, "path to the ARP table in /proc/net/arp format")
+	importARPCmd.Flags().StringVar(&impSweep, "sweep", "", "CIDR to sweep first so the kernel resolves every host, e.g. 192.168.100.0/24")
+	importARPCmd.Flags().DurationVar(&impSweepWait, "sweep-wait", 3*time.Second, "time to wait after --sweep before reading the table")
+}
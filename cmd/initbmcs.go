@@ -5,14 +5,16 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"io/fs"
+	"sort"
 
 	"bootstrap/internal/initbmcs"
-	"bootstrap/internal/inventory"
+	"bootstrap/internal/leases"
+	"bootstrap/pkg/inventory"
 
 	"github.com/spf13/cobra"
-	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -23,6 +25,13 @@ var (
 	initNodesPerChas int
 	initNodesPerBMC  int
 	initStartNID     int
+	initGeometry     string
+	initRack         string
+	initMACCSV       string
+	initMerge        bool
+	initLearnLeases  string
+	initLeaseFormat  string
+	initCabinet      string
 )
 
 var initBmcsCmd = &cobra.Command{
@@ -30,39 +39,128 @@ var initBmcsCmd = &cobra.Command{
 	Short: "Generate initial inventory with BMC entries",
 	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
 		if initFile == "" {
-			return fmt.Errorf("--file is required")
+			return invalidf("--file is required")
 		}
 		if initBMCSubnet == "" {
-			return fmt.Errorf("--bmc-subnet is required")
+			return invalidf("--bmc-subnet is required")
 		}
-		chassis := initbmcs.ParseChassisSpec(initChassis)
-		if len(chassis) == 0 {
-			return fmt.Errorf("--chassis must specify at least one entry, e.g. x9000c1=02:23:28:01")
+		var bmcs []inventory.Entry
+		var err error
+		if initRack != "" {
+			bmcs, err = generateRiverBMCs()
+		} else {
+			bmcs, err = generateChassisBMCs(cmd)
 		}
-		bmcs, err := initbmcs.Generate(chassis, initNodesPerChas, initNodesPerBMC, initStartNID, initBMCSubnet, initStartIP)
 		if err != nil {
-			return err
+			return invalid(err)
 		}
-		doc := inventory.FileFormat{BMCs: bmcs, Nodes: nil}
-		bytes, err := yaml.Marshal(&doc)
+		unlock, err := lockInventory(initFile)
 		if err != nil {
 			return err
 		}
-		if err := os.WriteFile(initFile, bytes, 0o644); err != nil {
+		defer unlock()
+		doc := inventory.FileFormat{BMCs: bmcs, Nodes: nil}
+		if initMerge {
+			existing, err := readInventory(initFile)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			if err == nil {
+				merged, err := initbmcs.Merge(existing.BMCs, bmcs, initBMCSubnet, initStartIP)
+				if err != nil {
+					return invalid(err)
+				}
+				doc = inventory.FileFormat{BMCs: merged, Nodes: existing.Nodes}
+			}
+		}
+		if err := writeInventory(initFile, &doc); err != nil {
 			return err
 		}
-		fmt.Printf("Wrote initial BMC inventory to %s with %d entries\n", initFile, len(bmcs))
+		fmt.Printf("Wrote initial BMC inventory to %s with %d entries\n", initFile, len(doc.BMCs))
 		return nil
 	},
 }
 
+// generateChassisBMCs builds BMC entries for liquid-cooled chassis per --chassis and --geometry.
+func generateChassisBMCs(cmd *cobra.Command) ([]inventory.Entry, error) {
+	geo, err := initbmcs.LoadGeometry(initGeometry)
+	if err != nil {
+		return nil, err
+	}
+	chassis := initbmcs.ParseChassisSpec(initChassis)
+	// Learn MAC prefixes from DHCP leases unless --chassis was given explicitly
+	if initLearnLeases != "" && !cmd.Flags().Changed("chassis") {
+		ls, err := leases.Load(initLearnLeases, initLeaseFormat)
+		if err != nil {
+			return nil, err
+		}
+		macs := make([]string, 0, len(ls))
+		for _, l := range ls {
+			macs = append(macs, l.MAC)
+		}
+		chassis, err = initbmcs.LearnChassisPrefixes(macs, geo, initCabinet)
+		if err != nil {
+			return nil, err
+		}
+		if len(chassis) == 0 {
+			return nil, fmt.Errorf("no BMC MACs in %s match the %s geometry MAC format", initLearnLeases, geo.Name)
+		}
+		learned := make([]string, 0, len(chassis))
+		for c := range chassis {
+			learned = append(learned, c)
+		}
+		sort.Strings(learned)
+		for _, c := range learned {
+			fmt.Printf("Learned chassis %s=%s\n", c, chassis[c])
+		}
+	}
+	if len(chassis) == 0 {
+		return nil, fmt.Errorf("--chassis must specify at least one entry, e.g. x9000c1=02:23:28:01")
+	}
+	// Explicit flags override the geometry profile
+	if initNodesPerBMC > 0 {
+		geo.NodesPerBMC = initNodesPerBMC
+	}
+	nodesPerChassis := geo.NodesPerChassis()
+	if initNodesPerChas > 0 {
+		nodesPerChassis = initNodesPerChas
+	}
+	return initbmcs.GenerateGeometry(chassis, geo, nodesPerChassis, initStartNID, initBMCSubnet, initStartIP)
+}
+
+// generateRiverBMCs builds BMC entries for rackmount nodes per --rack and --mac-csv.
+func generateRiverBMCs() ([]inventory.Entry, error) {
+	if initMACCSV == "" {
+		return nil, fmt.Errorf("--mac-csv is required with --rack")
+	}
+	units, err := initbmcs.ParseRackSpec(initRack)
+	if err != nil {
+		return nil, err
+	}
+	if len(units) == 0 {
+		return nil, fmt.Errorf("--rack must specify at least one entry, e.g. x3000u01-u40")
+	}
+	macs, err := initbmcs.LoadMACCSV(initMACCSV)
+	if err != nil {
+		return nil, err
+	}
+	return initbmcs.GenerateRiver(units, macs, initBMCSubnet, initStartIP)
+}
+
 func init() {
 	rootCmd.AddCommand(initBmcsCmd)
 	initBmcsCmd.Flags().StringVarP(&initFile, "file", "f", "", "Output YAML file containing bmcs[] and nodes[]")
 	initBmcsCmd.Flags().StringVar(&initChassis, "chassis", "x9000c1=02:23:28:01,x9000c3=02:23:28:03", "comma-separated chassis=macprefix list")
 	initBmcsCmd.Flags().StringVar(&initBMCSubnet, "bmc-subnet", "192.168.100.0/24", "BMC subnet in CIDR notation, e.g. 192.168.100.0/24")
 	initBmcsCmd.Flags().StringVar(&initStartIP, "start-ip", "1", "Start IP allocation at this address (skips all IPs before it)")
-	initBmcsCmd.Flags().IntVar(&initNodesPerChas, "nodes-per-chassis", 32, "number of nodes per chassis")
-	initBmcsCmd.Flags().IntVar(&initNodesPerBMC, "nodes-per-bmc", 2, "number of nodes managed by each BMC")
+	initBmcsCmd.Flags().IntVar(&initNodesPerChas, "nodes-per-chassis", 0, "number of nodes per chassis (0 = the geometry's capacity)")
+	initBmcsCmd.Flags().IntVar(&initNodesPerBMC, "nodes-per-bmc", 0, "number of nodes (systems) managed by each BMC, e.g. 4 for riser cards (0 = the geometry's value)")
 	initBmcsCmd.Flags().IntVar(&initStartNID, "start-nid", 1, "starting node id (1-based)")
+	initBmcsCmd.Flags().StringVar(&initRack, "rack", "", "rackmount (river) elevation spec, e.g. x3000u01-u40 (replaces --chassis)")
+	initBmcsCmd.Flags().StringVar(&initMACCSV, "mac-csv", "", "CSV of position,mac rows for --rack (position is x3000u01 or BMC xname)")
+	initBmcsCmd.Flags().BoolVar(&initMerge, "merge", false, "merge generated BMCs into an existing --file (dedupe by xname/MAC, keep nodes[] and existing IPs)")
+	initBmcsCmd.Flags().StringVar(&initLearnLeases, "learn-leases", "", "infer chassis MAC prefixes from a DHCP lease file instead of --chassis")
+	initBmcsCmd.Flags().StringVar(&initLeaseFormat, "lease-format", "dnsmasq", "lease file format for --learn-leases: dnsmasq|isc|kea")
+	initBmcsCmd.Flags().StringVar(&initCabinet, "cabinet", "x9000", "cabinet xname used for chassis learned with --learn-leases")
+	initBmcsCmd.Flags().StringVar(&initGeometry, "geometry", initbmcs.DefaultGeometry, "hardware geometry profile: ex2500|ex3000|ex4000 or path to a custom geometry YAML file")
 }
@@ -0,0 +1,227 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"bootstrap/internal/safefile"
+	"bootstrap/internal/sls"
+	"bootstrap/pkg/inventory"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	invDiffFormat    string
+	inventoryBackups int
+	invFile          string
+	invOut           string
+	invGetFormat     string
+	invBMCSubnet     string
+	invNodeSubnet    string
+)
+
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Work with inventory files and databases",
+}
+
+var inventoryDiffCmd = &cobra.Command{
+	Use:   "diff OLD NEW",
+	Short: "Compare two inventory files entry by entry",
+	Long: `Report the bmcs[] and nodes[] entries added, removed or changed between two
+inventory files. Entries are matched by xname and then by MAC, so a discovery run
+that rewrites or reorders nodes[] only shows what actually changed; an entry whose
+xname changed but whose MAC did not is reported as changed. Exits with status 2
+when the files differ.`,
+	Args: cobra.ExactArgs(2),
+	// Differences are a result, not a usage error
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if invDiffFormat != "" && !strings.EqualFold(invDiffFormat, "json") {
+			return invalidf("--format must be json when set")
+		}
+		oldDoc, err := readInventory(args[0])
+		if err != nil {
+			return err
+		}
+		newDoc, err := readInventory(args[1])
+		if err != nil {
+			return err
+		}
+		changes := inventory.Diff(oldDoc, newDoc)
+		if err := printInventoryDiff(changes); err != nil {
+			return err
+		}
+		if len(changes) > 0 {
+			return &exitError{code: exitPartial, err: fmt.Errorf("%d entry(ies) differ", len(changes))}
+		}
+		return nil
+	},
+}
+
+var inventoryExportCmd = &cobra.Command{
+	Use:       "export yaml|db|sls",
+	Short:     "Convert an inventory between a YAML file, a database and SLS",
+	ValidArgs: []string{"yaml", "db", "sls"},
+	Args:      cobra.ExactArgs(1),
+	Long: `Write the inventory in --file (YAML or database) as YAML, to --out or stdout,
+or into the database named by --out. Use it to move a large inventory into a
+database, or to hand a database inventory to tools that read YAML.
+
+sls writes a CSM System Layout Service dump, to --out or stdout, for tools
+that read SLS: nodes[] as Node hardware under their NodeBMC, and with
+--bmc-subnet and --node-subnet HMN and NMN networks reserving the BMC and node
+IPs. 'import sls' reads it back.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if invFile == "" {
+			return invalidf("--file is required")
+		}
+		doc, err := readInventory(invFile)
+		if err != nil {
+			return err
+		}
+		switch args[0] {
+		case "sls":
+			d, err := sls.FromInventory(doc, sls.Subnets{BMC: invBMCSubnet, Node: invNodeSubnet})
+			if err != nil {
+				return invalid(err)
+			}
+			out, err := json.MarshalIndent(d, "", "  ")
+			if err != nil {
+				return err
+			}
+			out = append(out, '\n')
+			if invOut == "" {
+				_, err = os.Stdout.Write(out)
+				return err
+			}
+			return safefile.Write(invOut, out, 0o644, inventoryBackups)
+		case "yaml":
+			if invOut == "" {
+				out, err := yaml.Marshal(&doc)
+				if err != nil {
+					return err
+				}
+				_, err = os.Stdout.Write(out)
+				return err
+			}
+			if isInventoryDB(invOut) {
+				return invalidf("--out %s names a database; use export db", invOut)
+			}
+		case "db":
+			if !isInventoryDB(invOut) {
+				return invalidf("--out must name a .db or .bolt file")
+			}
+		default:
+			return invalidf("unknown format %q (use yaml, db or sls)", args[0])
+		}
+		unlock, err := lockInventory(invOut)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+		if err := writeInventory(invOut, &doc); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %d BMC(s) and %d node(s) to %s\n", len(doc.BMCs), len(doc.Nodes), invOut)
+		return nil
+	},
+}
+
+var inventoryGetCmd = &cobra.Command{
+	Use:   "get XNAME|MAC|IP",
+	Short: "Look up inventory entries by xname, MAC or IP",
+	Long: `Print the bmcs[] and nodes[] entries whose xname, MAC, IP or HSN interface MAC
+matches the argument. Databases answer from their index without loading the whole
+inventory.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if invFile == "" {
+			return invalidf("--file is required")
+		}
+		if invGetFormat != "" && !strings.EqualFold(invGetFormat, "json") {
+			return invalidf("--format must be json when set")
+		}
+		st, err := openInventory(invFile, true)
+		if err != nil {
+			return invalid(err)
+		}
+		defer st.Close()
+		found, err := st.Find(args[0])
+		if err != nil {
+			return invalid(err)
+		}
+		if len(found) == 0 {
+			return invalidf("%s: not found in %s", args[0], invFile)
+		}
+		if strings.EqualFold(invGetFormat, "json") {
+			out, err := json.MarshalIndent(found, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(out))
+			return nil
+		}
+		for _, m := range found {
+			e := m.Entry
+			fmt.Printf("%s %s: mac %s, ip %s\n", m.Section, e.Xname, orNone(e.MAC), orNone(e.IP))
+		}
+		return nil
+	},
+}
+
+func printInventoryDiff(changes []inventory.Change) error {
+	if strings.EqualFold(invDiffFormat, "json") {
+		if changes == nil {
+			changes = []inventory.Change{}
+		}
+		out, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	marks := map[string]string{inventory.Added: "+", inventory.Removed: "-", inventory.Changed: "~"}
+	counts := map[string]map[string]int{"bmcs": {}, "nodes": {}}
+	for _, c := range changes {
+		counts[c.Section][c.Kind]++
+		var parts []string
+		for _, f := range c.Fields {
+			switch c.Kind {
+			case inventory.Added:
+				parts = append(parts, fmt.Sprintf("%s %s", f.Field, f.New))
+			case inventory.Removed:
+				parts = append(parts, fmt.Sprintf("%s %s", f.Field, f.Old))
+			default:
+				parts = append(parts, fmt.Sprintf("%s %s -> %s", f.Field, orNone(f.Old), orNone(f.New)))
+			}
+		}
+		fmt.Printf("%s %s %s: %s\n", marks[c.Kind], c.Section, c.Xname, strings.Join(parts, ", "))
+	}
+	fmt.Println("Inventory diff summary:")
+	for _, s := range []string{"bmcs", "nodes"} {
+		fmt.Printf("  %s: %d added, %d removed, %d changed\n", s, counts[s][inventory.Added], counts[s][inventory.Removed], counts[s][inventory.Changed])
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(inventoryCmd)
+	inventoryCmd.AddCommand(inventoryDiffCmd, inventoryExportCmd, inventoryGetCmd)
+	inventoryExportCmd.Flags().StringVarP(&invFile, "file", "f", "", "inventory to read (YAML file, or .db/.bolt database)")
+	inventoryExportCmd.Flags().StringVarP(&invOut, "out", "o", "", "file to write (default: YAML to stdout)")
+	inventoryExportCmd.Flags().StringVar(&invBMCSubnet, "bmc-subnet", "", "sls: CIDR of the HMN network reserving the BMC IPs, e.g. 10.254.0.0/17")
+	inventoryExportCmd.Flags().StringVar(&invNodeSubnet, "node-subnet", "", "sls: CIDR of the NMN network reserving the node IPs, e.g. 10.252.0.0/17")
+	inventoryGetCmd.Flags().StringVarP(&invFile, "file", "f", "", "inventory to search (YAML file, or .db/.bolt database)")
+	inventoryGetCmd.Flags().StringVar(&invGetFormat, "format", "", "output format: json")
+	inventoryDiffCmd.Flags().StringVar(&invDiffFormat, "format", "", "output format: json")
+}
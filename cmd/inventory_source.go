@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"bootstrap/pkg/inventory"
+	"bootstrap/pkg/inventory/netbox"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	srcFile    string
+	srcURL     string
+	srcTag     string
+	srcTimeout time.Duration
+	srcDryRun  bool
+	srcFormat  string
+)
+
+// inventorySources are the names accepted by inventory pull and push.
+var inventorySources = []string{"netbox"}
+
+// newInventorySource returns the inventory source called name.
+func newInventorySource(name string) (inventory.Source, error) {
+	switch name {
+	case "netbox":
+		url := cmpOrEnv(srcURL, "NETBOX_URL")
+		if url == "" {
+			return nil, invalidf("--url or $NETBOX_URL is required")
+		}
+		token, err := secret("NETBOX_TOKEN")
+		if err != nil {
+			return nil, err
+		}
+		c := netbox.New(url, token, srcTag, srcTimeout)
+		c.Warn = func(msg string) { fmt.Fprintf(os.Stderr, "WARN: netbox: %s\n", msg) }
+		return c, nil
+	}
+	return nil, invalidf("unknown inventory source %q (use %s)", name, strings.Join(inventorySources, " or "))
+}
+
+var inventoryPullCmd = &cobra.Command{
+	Use:       "pull SOURCE",
+	Short:     "Fill the inventory from a system of record such as NetBox",
+	ValidArgs: inventorySources,
+	Args:      cobra.ExactArgs(1),
+	Long: `Read the BMCs and nodes the source knows about and merge them into --file,
+matching entries by xname: the xname, MAC, IP and alias from the source replace
+those in the file, discovered fields such as the model and HSN interfaces are
+kept, new entries are added and entries the source lacks are left alone. The
+changes are printed as by 'inventory diff'. Without --file the pulled inventory
+is printed as YAML.
+
+netbox reads the devices carrying --tag from the NetBox at --url
+($NETBOX_URL) with the API token in $NETBOX_TOKEN. Each device is a node named
+by its "xname" custom field, else its name; its first management-only
+interface is the node's BMC.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		src, err := newInventorySource(args[0])
+		if err != nil {
+			return err
+		}
+		pulled, err := src.Pull(cmd.Context())
+		if err != nil {
+			return fmt.Errorf("pull from %s: %w", args[0], err)
+		}
+		return mergeInventory(srcFile, pulled, args[0], srcDryRun)
+	},
+}
+
+// mergeInventory merges src into the inventory at path (see inventory.Merge),
+// creating it if needed, and prints the changes; without a path src is printed
+// as YAML. from names src in messages.
+func mergeInventory(path string, src inventory.FileFormat, from string, dryRun bool) error {
+	if path == "" {
+		out, err := yaml.Marshal(&src)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(out)
+		return err
+	}
+	if !dryRun {
+		unlock, err := lockInventory(path)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+	var doc inventory.FileFormat
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		if doc, err = readInventory(path); err != nil {
+			return err
+		}
+	}
+	merged := inventory.Merge(doc, src)
+	changes := inventory.Diff(doc, merged)
+	if err := printInventoryDiff(changes); err != nil {
+		return err
+	}
+	for mac, xs := range merged.DuplicateMACs() {
+		fmt.Fprintf(os.Stderr, "WARN: MAC %s is used by %s\n", mac, strings.Join(xs, ", "))
+	}
+	if dryRun {
+		fmt.Printf("[dry-run] would write %d change(s) to %s\n", len(changes), path)
+		return nil
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	if err := writeInventory(path, &merged); err != nil {
+		return err
+	}
+	fmt.Printf("Updated %s from %s with %d change(s)\n", path, from, len(changes))
+	return nil
+}
+
+var inventoryPushCmd = &cobra.Command{
+	Use:       "push SOURCE",
+	Short:     "Record discovered MACs and IPs in a system of record such as NetBox",
+	ValidArgs: inventorySources,
+	Args:      cobra.ExactArgs(1),
+	Long: `Write the MACs and IPs of the bmcs[] and nodes[] entries in --file to the
+source where it has none. The source stays authoritative: values it already has
+that differ are reported as conflicts and left as they are, and the command
+then exits 2. Entries the source has no device or interface for are reported as
+missing.
+
+netbox sets the MAC of the matching interface and creates the IP address on it,
+with the prefix length of the most specific NetBox prefix containing it.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if srcFile == "" {
+			return invalidf("--file is required")
+		}
+		if srcFormat != "" && !strings.EqualFold(srcFormat, "json") {
+			return invalidf("--format must be json when set")
+		}
+		src, err := newInventorySource(args[0])
+		if err != nil {
+			return err
+		}
+		doc, err := readInventory(srcFile)
+		if err != nil {
+			return err
+		}
+		res, err := src.Push(cmd.Context(), doc, srcDryRun)
+		if err != nil {
+			return fmt.Errorf("push to %s: %w", args[0], err)
+		}
+		if err := printPushResult(res, args[0]); err != nil {
+			return err
+		}
+		if len(res.Conflicts) > 0 {
+			return &exitError{code: exitPartial, err: fmt.Errorf("%d value(s) differ from %s; %s was left unchanged for them", len(res.Conflicts), args[0], args[0])}
+		}
+		return nil
+	},
+}
+
+func printPushResult(res inventory.PushResult, source string) error {
+	if strings.EqualFold(srcFormat, "json") {
+		return printJSON(res)
+	}
+	verb := "set"
+	if srcDryRun {
+		verb = "would set"
+	}
+	for _, u := range res.Updated {
+		fmt.Printf("%s %s %s: %s %s\n", verb, u.Section, u.Xname, u.Field, u.New)
+	}
+	for _, u := range res.Conflicts {
+		fmt.Printf("CONFLICT %s %s: %s is %s in %s, %s in the inventory\n", u.Section, u.Xname, u.Field, u.Old, source, u.New)
+	}
+	for _, x := range res.Missing {
+		fmt.Printf("MISSING %s: no %s device or interface\n", x, source)
+	}
+	fmt.Printf("Push summary: %d %s, %d conflict(s), %d missing\n", len(res.Updated), verb, len(res.Conflicts), len(res.Missing))
+	return nil
+}
+
+func init() {
+	inventoryCmd.AddCommand(inventoryPullCmd, inventoryPushCmd)
+	for _, c := range []*cobra.Command{inventoryPullCmd, inventoryPushCmd} {
+		c.Flags().StringVarP(&srcFile, "file", "f", "", "inventory to update or push (YAML file, or .db/.bolt database)")
+		c.Flags().StringVar(&srcURL, "url", "", "base URL of the source, e.g. https://netbox.example (default: $NETBOX_URL)")
+		c.Flags().StringVar(&srcTag, "tag", "", "only use devices carrying this tag slug, e.g. hpc")
+		c.Flags().DurationVar(&srcTimeout, "timeout", 30*time.Second, "time limit for each request to the source")
+		c.Flags().BoolVar(&srcDryRun, "dry-run", false, "plan only: print the changes without writing")
+	}
+	inventoryPushCmd.Flags().StringVar(&srcFormat, "format", "", "output format: json")
+}
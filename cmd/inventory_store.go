@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"bootstrap/internal/safefile"
+	"bootstrap/pkg/inventory"
+	"bootstrap/pkg/inventory/boltstore"
+
+	"gopkg.in/yaml.v3"
+)
+
+// dbLockTimeout bounds how long opening an inventory database waits for another
+// process to release it.
+const dbLockTimeout = 30 * time.Second
+
+// isInventoryDB reports whether path names an inventory database rather than a
+// YAML file, by its extension (.db or .bolt).
+func isInventoryDB(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".db", ".bolt":
+		return true
+	}
+	return false
+}
+
+// openInventory opens the inventory at path: a bbolt database for .db/.bolt
+// files, otherwise a YAML file.
+func openInventory(path string, readOnly bool) (inventory.Store, error) {
+	if isInventoryDB(path) {
+		return boltstore.Open(path, readOnly, dbLockTimeout)
+	}
+	return yamlStore{path: path}, nil
+}
+
+// yamlStore is an inventory.Store backed by a YAML file, written atomically
+// with --backups previous versions kept.
+type yamlStore struct {
+	path string
+}
+
+func (s yamlStore) Load() (inventory.FileFormat, error) {
+	var doc inventory.FileFormat
+	raw, err := os.ReadFile(s.path)
+	if err != nil {
+		return doc, err
+	}
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return doc, fmt.Errorf("parse %s: %w", s.path, err)
+	}
+	return doc, nil
+}
+
+func (s yamlStore) Save(doc inventory.FileFormat) error {
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return err
+	}
+	return safefile.Write(s.path, out, 0o644, inventoryBackups)
+}
+
+func (s yamlStore) Find(key string) ([]inventory.Match, error) {
+	doc, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+	return doc.Find(key), nil
+}
+
+func (s yamlStore) Close() error { return nil }
+
+// readInventory loads the inventory at path (YAML file or database).
+func readInventory(path string) (inventory.FileFormat, error) {
+	st, err := openInventory(path, true)
+	if err != nil {
+		return inventory.FileFormat{}, invalid(err)
+	}
+	defer st.Close()
+	doc, err := st.Load()
+	if err != nil {
+		return doc, invalid(err)
+	}
+	return doc, nil
+}
+
+// writeInventory replaces the inventory at path. YAML files are replaced
+// atomically, keeping --backups previous versions as path.1, path.2, ...;
+// databases are updated in a single transaction.
+func writeInventory(path string, doc *inventory.FileFormat) error {
+	st, err := openInventory(path, false)
+	if err != nil {
+		return err
+	}
+	if err := st.Save(*doc); err != nil {
+		st.Close()
+		return err
+	}
+	return st.Close()
+}
+
+// lockInventory takes the lock of an inventory file for a command that rewrites
+// it. Hold it from before the file is read until after it is written, so
+// overlapping runs cannot overwrite each other's changes.
+func lockInventory(path string) (func(), error) {
+	unlock, err := safefile.Lock(path)
+	if err != nil {
+		return nil, fmt.Errorf("inventory %s: %w", path, err)
+	}
+	return unlock, nil
+}
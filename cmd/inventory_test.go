@@ -0,0 +1,170 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"bootstrap/pkg/inventory"
+)
+
+func TestInventoryDiffExitCode(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.yaml")
+	b := filepath.Join(dir, "b.yaml")
+	if err := os.WriteFile(a, []byte("nodes:\n  - {xname: x1000c0s0b0n0, mac: aa:00:00:00:00:01, ip: 10.0.0.1}\n  - {xname: x1000c0s0b0n1, mac: aa:00:00:00:00:02, ip: 10.0.0.2}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Same entries in another order
+	if err := os.WriteFile(b, []byte("nodes:\n  - {xname: x1000c0s0b0n1, mac: AA:00:00:00:00:02, ip: 10.0.0.2}\n  - {xname: x1000c0s0b0n0, mac: aa:00:00:00:00:01, ip: 10.0.0.1}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := inventoryDiffCmd
+	if err := cmd.RunE(cmd, []string{a, b}); err != nil {
+		t.Errorf("reordered files: %v", err)
+	}
+	if err := os.WriteFile(b, []byte("nodes:\n  - {xname: x1000c0s0b0n0, mac: aa:00:00:00:00:01, ip: 10.0.0.9}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := exitCode(cmd.RunE(cmd, []string{a, b})); got != exitPartial {
+		t.Errorf("changed files: exit code %d, want %d", got, exitPartial)
+	}
+	if got := exitCode(cmd.RunE(cmd, []string{a, filepath.Join(dir, "missing.yaml")})); got != exitInvalid {
+		t.Errorf("missing file: exit code %d, want %d", got, exitInvalid)
+	}
+}
+
+func TestInventoryExportDB(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "inventory.yaml")
+	db := filepath.Join(dir, "inventory.db")
+	if err := os.WriteFile(src, []byte("bmcs:\n  - {xname: x1000c0s0b0, mac: 02:00:00:00:00:01, ip: 10.1.0.2}\nnodes:\n  - {xname: x1000c0s0b0n0, mac: aa:00:00:00:00:01, ip: 10.2.0.1}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	invFile, invOut = src, db
+	defer func() { invFile, invOut = "", "" }()
+	if err := inventoryExportCmd.RunE(inventoryExportCmd, []string{"db"}); err != nil {
+		t.Fatalf("export db: %v", err)
+	}
+	// Commands read the database like a YAML file
+	bmcs, err := loadBMCs(db)
+	if err != nil || len(bmcs) != 1 || bmcs[0].IP != "10.1.0.2" {
+		t.Fatalf("loadBMCs(db) = %+v, %v", bmcs, err)
+	}
+	invFile = db
+	if err := inventoryGetCmd.RunE(inventoryGetCmd, []string{"10.2.0.1"}); err != nil {
+		t.Errorf("get: %v", err)
+	}
+	if got := exitCode(inventoryGetCmd.RunE(inventoryGetCmd, []string{"10.9.9.9"})); got != exitInvalid {
+		t.Errorf("get unknown: exit code %d, want %d", got, exitInvalid)
+	}
+	invOut = filepath.Join(dir, "back.yaml")
+	if err := inventoryExportCmd.RunE(inventoryExportCmd, []string{"yaml"}); err != nil {
+		t.Fatalf("export yaml: %v", err)
+	}
+	if d := mustDiff(t, src, invOut); d != 0 {
+		t.Errorf("round trip changed %d entry(ies)", d)
+	}
+}
+
+func mustDiff(t *testing.T, a, b string) int {
+	t.Helper()
+	x, err := readInventory(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	y, err := readInventory(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(inventory.Diff(x, y))
+}
+
+func TestInventoryPullNetBox(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/dcim/devices/":
+			fmt.Fprint(w, `{"next": null, "results": [{"id": 1, "name": "nid000001", "custom_fields": {"xname": "x1000c0s0b0n0"}}]}`)
+		case "/api/dcim/interfaces/":
+			fmt.Fprint(w, `{"next": null, "results": [{"id": 10, "name": "eth0", "device": {"id": 1}, "mac_address": "AA:00:00:00:00:01"},
+				{"id": 11, "name": "bmc", "device": {"id": 1}, "mgmt_only": true, "mac_address": "02:00:00:00:00:01"}]}`)
+		default:
+			fmt.Fprint(w, `{"next": null, "results": []}`)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("NETBOX_TOKEN", "tok")
+	file := filepath.Join(t.TempDir(), "inventory.yaml")
+	if err := os.WriteFile(file, []byte("nodes:\n  - {xname: x1000c0s0b0n0, ip: 10.0.0.1, model: XD225v}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srcFile, srcURL, srcTimeout = file, srv.URL, 5*time.Second
+	defer func() { srcFile, srcURL = "", "" }()
+
+	cmd := inventoryPullCmd
+	cmd.SetContext(context.Background())
+	if err := cmd.RunE(cmd, []string{"netbox"}); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := readInventory(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := inventory.FileFormat{
+		BMCs:  []inventory.Entry{{Xname: "x1000c0s0b0", MAC: "02:00:00:00:00:01"}},
+		Nodes: []inventory.Entry{{Xname: "x1000c0s0b0n0", Alias: "nid000001", MAC: "aa:00:00:00:00:01", IP: "10.0.0.1", Model: "XD225v"}},
+	}
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("got %+v\nwant %+v", doc, want)
+	}
+	if err := cmd.RunE(cmd, []string{"dcim"}); exitCode(err) != exitInvalid {
+		t.Errorf("unknown source: %v", err)
+	}
+}
+
+func TestInventorySLSRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "inventory.yaml")
+	if err := os.WriteFile(src, []byte("bmcs:\n  - {xname: x3000c0s9b0, mac: 02:00:00:00:00:01, ip: 10.254.1.9}\nnodes:\n  - {xname: x3000c0s9b0n0, alias: nid000001, mac: aa:00:00:00:00:01, ip: 10.252.1.7}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dump := filepath.Join(dir, "sls.json")
+	invFile, invOut, invBMCSubnet, invNodeSubnet = src, dump, "10.254.0.0/17", "10.252.0.0/17"
+	defer func() { invFile, invOut, invBMCSubnet, invNodeSubnet = "", "", "", "" }()
+	if err := inventoryExportCmd.RunE(inventoryExportCmd, []string{"sls"}); err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(dir, "imported.yaml")
+	impFile = out
+	defer func() { impFile = "" }()
+	if err := importSLSCmd.RunE(importSLSCmd, []string{dump}); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := readInventory(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := inventory.FileFormat{
+		BMCs:  []inventory.Entry{{Xname: "x3000c0s9b0", IP: "10.254.1.9"}},
+		Nodes: []inventory.Entry{{Xname: "x3000c0s9b0n0", Alias: "nid000001", IP: "10.252.1.7"}},
+	}
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("got %+v\nwant %+v", doc, want)
+	}
+
+	invNodeSubnet = "10.0.0.0/24"
+	if err := inventoryExportCmd.RunE(inventoryExportCmd, []string{"sls"}); exitCode(err) != exitInvalid {
+		t.Errorf("node IP outside --node-subnet: %v", err)
+	}
+}
@@ -0,0 +1,280 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"bootstrap/internal/apiserver"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	jbServer    string
+	jbTokenFile string
+	jbInsecure  bool
+	jbSchedule  string
+	jbFormat    string
+
+	jbDiscover apiserver.DiscoverRequest
+	jbFirmware apiserver.FirmwareRequest
+)
+
+var jobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "Submit, list, inspect and cancel jobs on a serve instance",
+	Long: `Talk to the job API of a running "serve": submit discovery or firmware runs,
+now or at a scheduled time (--schedule 2025-07-01T02:00Z), list jobs, print a
+job's log and cancel queued or running jobs.
+
+The server is --server (default $BOOTSTRAP_API_URL, else http://127.0.0.1:8080)
+and the token comes from --token-file or $BOOTSTRAP_API_TOKEN.`,
+}
+
+var jobsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List jobs, newest first",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		var list []apiserver.Job
+		if err := apiCall(cmd.Context(), http.MethodGet, "/v1/jobs", nil, &list); err != nil {
+			return err
+		}
+		if strings.EqualFold(jbFormat, "json") {
+			return printJSON(list)
+		}
+		for _, j := range list {
+			fmt.Println(jobLine(j))
+		}
+		return nil
+	},
+}
+
+var jobsGetCmd = &cobra.Command{
+	Use:   "get ID",
+	Short: "Show a job",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var j apiserver.Job
+		if err := apiCall(cmd.Context(), http.MethodGet, "/v1/jobs/"+args[0], nil, &j); err != nil {
+			return err
+		}
+		if strings.EqualFold(jbFormat, "json") {
+			return printJSON(j)
+		}
+		fmt.Println(jobLine(j))
+		fmt.Printf("  args: %s\n", strings.Join(j.Args, " "))
+		if j.Error != "" {
+			fmt.Printf("  error: %s\n", j.Error)
+		}
+		return nil
+	},
+}
+
+var jobsLogsCmd = &cobra.Command{
+	Use:   "logs ID",
+	Short: "Print a job's output",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var j apiserver.Job
+		if err := apiCall(cmd.Context(), http.MethodGet, "/v1/jobs/"+args[0], nil, &j); err != nil {
+			return err
+		}
+		fmt.Print(j.Output)
+		return nil
+	},
+}
+
+var jobsCancelCmd = &cobra.Command{
+	Use:   "cancel ID",
+	Short: "Cancel a queued or running job",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var j apiserver.Job
+		if err := apiCall(cmd.Context(), http.MethodDelete, "/v1/jobs/"+args[0], nil, &j); err != nil {
+			return err
+		}
+		fmt.Printf("Canceling job %s (%s)\n", j.ID, j.Kind)
+		return nil
+	},
+}
+
+var jobsSubmitCmd = &cobra.Command{
+	Use:   "submit",
+	Short: "Submit a discovery or firmware job",
+}
+
+var jobsSubmitDiscoverCmd = &cobra.Command{
+	Use:   "discover",
+	Short: "Submit a discover run against the server's inventory",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		req := jbDiscover
+		req.Schedule = jbSchedule
+		return submitJob(cmd.Context(), "/v1/discover", req)
+	},
+}
+
+var jobsSubmitFirmwareCmd = &cobra.Command{
+	Use:   "firmware",
+	Short: "Submit a firmware update run against the server's inventory",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		req := jbFirmware
+		req.Schedule = jbSchedule
+		return submitJob(cmd.Context(), "/v1/firmware", req)
+	},
+}
+
+// submitJob posts req to path and prints the job the server queued.
+func submitJob(ctx context.Context, path string, req any) error {
+	if jbSchedule != "" {
+		if _, err := apiserver.ParseSchedule(jbSchedule); err != nil {
+			return invalid(err)
+		}
+	}
+	var j apiserver.Job
+	if err := apiCall(ctx, http.MethodPost, path, req, &j); err != nil {
+		return err
+	}
+	if j.Scheduled != nil {
+		fmt.Printf("Queued %s job %s for %s\n", j.Kind, j.ID, j.Scheduled.Format(time.RFC3339))
+	} else {
+		fmt.Printf("Started %s job %s\n", j.Kind, j.ID)
+	}
+	return nil
+}
+
+// jobLine summarizes a job on one line: id, kind, state, the time of its last
+// transition and, once finished, the exit code.
+func jobLine(j apiserver.Job) string {
+	line := fmt.Sprintf("%s  %-8s  %-11s", j.ID, j.Kind, j.State)
+	switch {
+	case j.Finished != nil:
+		line += "  finished " + j.Finished.Format(time.RFC3339)
+	case j.Started != nil:
+		line += "  started " + j.Started.Format(time.RFC3339)
+	case j.Scheduled != nil:
+		line += "  scheduled " + j.Scheduled.Format(time.RFC3339)
+	default:
+		line += "  submitted " + j.Submitted.Format(time.RFC3339)
+	}
+	if j.ExitCode != nil {
+		line += fmt.Sprintf("  exit %d", *j.ExitCode)
+	}
+	return line
+}
+
+func printJSON(v any) error {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+// apiCall sends body (if not nil) as JSON to the server and decodes the reply
+// into out. Error replies become errors carrying the server's message.
+func apiCall(ctx context.Context, method, path string, body, out any) error {
+	token, err := apiToken(jbTokenFile)
+	if err != nil {
+		return err
+	}
+	base := jbServer
+	if base == "" {
+		base = os.Getenv("BOOTSTRAP_API_URL")
+	}
+	if base == "" {
+		base = "http://127.0.0.1:8080"
+	}
+	var rd io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(base, "/")+path, rd)
+	if err != nil {
+		return invalid(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	if jbInsecure {
+		tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // opt-in for self-signed servers
+	}
+	resp, err := (&http.Client{Timeout: 30 * time.Second, Transport: tr}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(raw, &e) != nil || e.Error == "" {
+			e.Error = strings.TrimSpace(string(raw))
+		}
+		err := fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, e.Error)
+		switch resp.StatusCode {
+		case http.StatusUnauthorized:
+			return &exitError{code: exitAuth, err: err}
+		case http.StatusBadRequest, http.StatusNotFound, http.StatusConflict:
+			return invalid(err)
+		}
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
+
+func init() {
+	jobsCmd.PersistentFlags().StringVar(&jbServer, "server", "", "URL of the serve instance (default $BOOTSTRAP_API_URL, else http://127.0.0.1:8080)")
+	jobsCmd.PersistentFlags().StringVar(&jbTokenFile, "token-file", "", "file holding the API token (default: $BOOTSTRAP_API_TOKEN)")
+	jobsCmd.PersistentFlags().BoolVar(&jbInsecure, "insecure", false, "do not verify the server's TLS certificate")
+	jobsListCmd.Flags().StringVar(&jbFormat, "format", "", "output format: json")
+	jobsGetCmd.Flags().StringVar(&jbFormat, "format", "", "output format: json")
+
+	jobsSubmitCmd.PersistentFlags().StringVar(&jbSchedule, "schedule", "", "start the job at this time, RFC 3339 (e.g. 2025-07-01T02:00Z); default now")
+
+	df := jobsSubmitDiscoverCmd.Flags()
+	df.StringSliceVar(&jbDiscover.Hosts, "hosts", nil, "bmcs[] hosts or xnames to discover (default all)")
+	df.StringVar(&jbDiscover.BMCSubnet, "bmc-subnet", "", "CIDR for BMC IPs")
+	df.StringVar(&jbDiscover.NodeSubnet, "node-subnet", "", "CIDR for node IPs")
+	df.BoolVar(&jbDiscover.DryRun, "dry-run", false, "plan only")
+
+	ff := jobsSubmitFirmwareCmd.Flags()
+	ff.StringSliceVar(&jbFirmware.Hosts, "hosts", nil, "BMC hosts to update (default all bmcs[])")
+	ff.StringVar(&jbFirmware.Type, "type", "", "firmware type preset: cc|nc|bios (ignored if --targets provided)")
+	ff.StringSliceVar(&jbFirmware.Targets, "targets", nil, "explicit FirmwareInventory target URIs")
+	ff.StringVar(&jbFirmware.ImageURI, "image-uri", "", "firmware image URI accessible by BMC (required)")
+	ff.StringVar(&jbFirmware.Protocol, "protocol", "", "TransferProtocol for SimpleUpdate (default HTTP)")
+	ff.StringVar(&jbFirmware.ExpectedVersion, "expected-version", "", "skip hosts already at this version (unless --force)")
+	ff.BoolVar(&jbFirmware.Force, "force", false, "update even if already at --expected-version")
+	ff.IntVar(&jbFirmware.BatchSize, "batch-size", 0, "number of concurrent updates")
+	ff.BoolVar(&jbFirmware.DryRun, "dry-run", false, "plan only")
+
+	jobsSubmitCmd.AddCommand(jobsSubmitDiscoverCmd, jobsSubmitFirmwareCmd)
+	jobsCmd.AddCommand(jobsListCmd, jobsGetCmd, jobsLogsCmd, jobsCancelCmd, jobsSubmitCmd)
+	rootCmd.AddCommand(jobsCmd)
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"bootstrap/internal/apiserver"
+	"bootstrap/pkg/inventory"
+)
+
+// runJobsCmd runs a jobs subcommand and returns what it printed.
+func runJobsCmd(t *testing.T, run func() error) string {
+	t.Helper()
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := run()
+	w.Close() //nolint:errcheck
+	os.Stdout = old
+	out, _ := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("command failed: %v\n%s", err, out)
+	}
+	return string(out)
+}
+
+func TestJobsSubmitScheduledAndCancel(t *testing.T) {
+	ran := make(chan []string, 1)
+	api, err := apiserver.New(context.Background(), apiserver.Config{
+		Token:         "secret",
+		InventoryFile: "inventory.yaml",
+		Open:          func(string) (inventory.Store, error) { return yamlStore{path: "inventory.yaml"}, nil },
+		Run: func(_ context.Context, args []string, _, _, _ io.Writer) (int, error) {
+			ran <- args
+			return 0, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(api.Handler())
+	defer ts.Close()
+
+	jbServer, jbTokenFile, jbFormat = ts.URL, "", ""
+	t.Setenv("BOOTSTRAP_API_TOKEN", "secret")
+	jbFirmware = apiserver.FirmwareRequest{Type: "cc", ImageURI: "http://10.0.0.1/cc.bin", Hosts: []string{"a"}}
+	jbSchedule = time.Now().Add(time.Hour).UTC().Format("2006-01-02T15:04Z")
+
+	jobsSubmitFirmwareCmd.SetContext(context.Background())
+	out := runJobsCmd(t, func() error { return jobsSubmitFirmwareCmd.RunE(jobsSubmitFirmwareCmd, nil) })
+	if !strings.HasPrefix(out, "Queued firmware job ") {
+		t.Fatalf("submit output = %q", out)
+	}
+	id := strings.Fields(out)[3]
+
+	jobsListCmd.SetContext(context.Background())
+	out = runJobsCmd(t, func() error { return jobsListCmd.RunE(jobsListCmd, nil) })
+	if !strings.Contains(out, id) || !strings.Contains(out, "queued") || !strings.Contains(out, "scheduled") {
+		t.Errorf("list output = %q", out)
+	}
+
+	jobsCancelCmd.SetContext(context.Background())
+	runJobsCmd(t, func() error { return jobsCancelCmd.RunE(jobsCancelCmd, []string{id}) })
+	select {
+	case args := <-ran:
+		t.Fatalf("canceled job ran: %q", args)
+	default:
+	}
+
+	// Without a schedule the job starts at once
+	jbSchedule = ""
+	out = runJobsCmd(t, func() error { return jobsSubmitFirmwareCmd.RunE(jobsSubmitFirmwareCmd, nil) })
+	if !strings.HasPrefix(out, "Started firmware job ") {
+		t.Fatalf("submit output = %q", out)
+	}
+	if args := <-ran; !slices.Contains(args, "--hosts=a") || !slices.Contains(args, "--type=cc") {
+		t.Errorf("job args = %q", args)
+	}
+
+	jbSchedule = "next tuesday"
+	if err := jobsSubmitFirmwareCmd.RunE(jobsSubmitFirmwareCmd, nil); exitCode(err) != exitInvalid {
+		t.Errorf("bad schedule: err = %v, want exit %d", err, exitInvalid)
+	}
+	jbSchedule = ""
+	t.Setenv("BOOTSTRAP_API_TOKEN", "wrong")
+	if err := jobsListCmd.RunE(jobsListCmd, nil); exitCode(err) != exitAuth {
+		t.Errorf("bad token: err = %v, want exit %d", err, exitAuth)
+	}
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"bootstrap/internal/hostkeys"
+	"bootstrap/internal/safefile"
+	"bootstrap/pkg/inventory"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	khFile      string
+	khNodes     string
+	khOutput    string
+	khKeyTypes  []string
+	khPort      int
+	khHash      bool
+	khTimeout   time.Duration
+	khBatchSize int
+)
+
+var knownHostsCmd = &cobra.Command{
+	Use:   "known-hosts",
+	Short: "Collect node SSH host keys into a known_hosts file",
+	Long: `Connect to the SSH port of every nodes[] entry of --file that has an IP (or
+those named by --nodes), like ssh-keyscan, and write a known_hosts line per host
+key. Each line lists the node's alias, xname and IP, so ssh to any of them is
+checked against the key. Nodes that do not answer are reported and left out.
+
+Run it once the nodes are up, e.g. after verify-boot, and point ssh (or
+verify-boot --known-hosts) at the result.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if khFile == "" {
+			return invalidf("--file is required")
+		}
+		algs, err := hostkeys.Algorithms(khKeyTypes)
+		if err != nil {
+			return invalidf("--key-types: %w", err)
+		}
+		doc, err := readInventory(khFile)
+		if err != nil {
+			return err
+		}
+		nodes, err := nodeTargets(doc, khFile, khNodes, true)
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		var mu sync.Mutex
+		keys := map[string][]ssh.PublicKey{}
+		failed := map[string]error{}
+		byIP := map[string]inventory.Entry{}
+		ips := make([]string, len(nodes))
+		for i, n := range nodes {
+			byIP[n.IP], ips[i] = n, n.IP
+		}
+		forEachHost(ctx, ips, khBatchSize, func(ctx context.Context, ip string) {
+			ctx, cancel := context.WithTimeout(ctx, khTimeout)
+			defer cancel()
+			n := byIP[ip]
+			found, err := hostkeys.Scan(ctx, nil, net.JoinHostPort(ip, strconv.Itoa(khPort)), algs)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s (%s): %v\n", n.Xname, ip, err)
+				failed[n.Xname] = err
+				return
+			}
+			keys[n.Xname] = found
+		}, func(string) {})
+
+		var b strings.Builder
+		lines := 0
+		for _, n := range nodes {
+			names := knownHostNames(n, khPort)
+			for _, k := range keys[n.Xname] {
+				b.WriteString(hostkeys.Line(names, k, khHash) + "\n")
+				lines++
+			}
+		}
+		if khOutput == "" || khOutput == "-" {
+			fmt.Print(b.String())
+		} else {
+			if err := safefile.Write(khOutput, []byte(b.String()), 0o644, 1); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Wrote %d key(s) of %d node(s) to %s\n", lines, len(keys), khOutput)
+		}
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+		return hostFailures(len(nodes), failed)
+	},
+}
+
+// knownHostNames returns the names a node is reached by: alias, xname and IP,
+// with the port when it is not 22.
+func knownHostNames(n inventory.Entry, port int) []string {
+	var names []string
+	for _, name := range []string{n.Alias, n.Xname, n.IP} {
+		if name == "" {
+			continue
+		}
+		if port != 22 {
+			name = net.JoinHostPort(name, strconv.Itoa(port))
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func init() {
+	rootCmd.AddCommand(knownHostsCmd)
+	knownHostsCmd.Flags().StringVarP(&khFile, "file", "f", "", "inventory whose nodes[] IPs are scanned")
+	knownHostsCmd.Flags().StringVar(&khNodes, "nodes", "", "comma-separated node xnames or aliases to scan (default: every node with an IP)")
+	knownHostsCmd.Flags().StringVarP(&khOutput, "output", "o", "", "known_hosts file to write; the previous one is kept as <file>.1 (default: stdout)")
+	knownHostsCmd.Flags().StringSliceVar(&khKeyTypes, "key-types", []string{"ed25519", "ecdsa", "rsa"}, "host key types to collect: ed25519, ecdsa, rsa")
+	knownHostsCmd.Flags().IntVar(&khPort, "port", 22, "SSH port of the nodes")
+	knownHostsCmd.Flags().BoolVar(&khHash, "hash", false, "hash host names, as ssh-keygen -H does")
+	knownHostsCmd.Flags().DurationVar(&khTimeout, "timeout", 10*time.Second, "time limit for scanning one node")
+	knownHostsCmd.Flags().IntVar(&khBatchSize, "batch-size", 20, "number of nodes to scan concurrently (0 or 1 = serial)")
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestKnownHosts(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &ssh.ServerConfig{NoClientAuth: true}
+	cfg.AddHostKey(signer)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close() //nolint:errcheck
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, _, _, _ = ssh.NewServerConn(conn, cfg)
+				conn.Close() //nolint:errcheck
+			}()
+		}
+	}()
+
+	dir := t.TempDir()
+	inv := filepath.Join(dir, "inventory.yaml")
+	doc := `nodes:
+  - xname: x1000c0s0b0n0
+    alias: nid000001
+    ip: 127.0.0.1
+  - xname: x1000c0s0b0n1
+    ip: 127.0.0.2
+`
+	if err := os.WriteFile(inv, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		khFile, khOutput, khKeyTypes, khPort, khTimeout = "", "", []string{"ed25519", "ecdsa", "rsa"}, 22, 10*time.Second
+	})
+	out := filepath.Join(dir, "known_hosts")
+	khFile, khOutput, khKeyTypes, khPort, khTimeout = inv, out, []string{"ed25519"}, ln.Addr().(*net.TCPAddr).Port, 5*time.Second
+	knownHostsCmd.SetContext(context.Background())
+
+	err = knownHostsCmd.RunE(knownHostsCmd, nil)
+	if got := exitCode(err); got != exitPartial {
+		t.Errorf("exit code %d, want %d (127.0.0.2 is down)", got, exitPartial)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+	want := "[nid000001]:" + strconv.Itoa(khPort) + ",[x1000c0s0b0n0]:" + strconv.Itoa(khPort) + ",[127.0.0.1]:" + strconv.Itoa(khPort) + " " + pub + "\n"
+	if string(got) != want {
+		t.Errorf("known_hosts:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCloudInit(t *testing.T) {
+	dir := t.TempDir()
+	inv := filepath.Join(dir, "inventory.yaml")
+	doc := `nodes:
+  - xname: x1000c0s0b0n0
+    alias: nid000001
+  - xname: x1000c0s0b0n1
+`
+	key := filepath.Join(dir, "id_ed25519.pub")
+	for path, data := range map[string]string{inv: doc, key: "ssh-ed25519 AAAA admin@mgmt\n"} {
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() { ciFile, ciNodes, ciKeys, ciOutDir = "", "", nil, "" })
+	ciFile, ciNodes, ciKeys, ciOutDir = inv, "x1000c0s0b0n1", []string{key}, filepath.Join(dir, "seed")
+	if err := cloudInitCmd.RunE(cloudInitCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "seed", "x1000c0s0b0n1", "user-data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), "hostname: x1000c0s0b0n1\n") || !strings.Contains(string(got), "- ssh-ed25519 AAAA admin@mgmt") {
+		t.Errorf("user-data:\n%s", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "seed", "x1000c0s0b0n0")); !os.IsNotExist(err) {
+		t.Errorf("node not named by --nodes was written: %v", err)
+	}
+}
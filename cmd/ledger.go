@@ -0,0 +1,174 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"bootstrap/internal/ledger"
+	"bootstrap/pkg/redfish"
+)
+
+var (
+	ledgerFile   string
+	ledgerWindow time.Duration
+)
+
+// opLedger records BMC write operations; nil without --ledger.
+var opLedger *ledger.Ledger
+
+// errAlreadySent is returned for an update the ledger shows reached the BMC.
+var errAlreadySent = errors.New("skipping update")
+
+func configureLedger() error {
+	opLedger = nil
+	path := cmpOrEnv(ledgerFile, "BOOTSTRAP_LEDGER")
+	if path == "" {
+		return nil
+	}
+	l, err := ledger.Open(path)
+	if err != nil {
+		return invalidf("--ledger: %w", err)
+	}
+	opLedger = l
+	return nil
+}
+
+// withLedger guards the write operations of c, a client for host, with the
+// ledger: SimpleUpdate and settings PATCHes the BMC already accepted within
+// --ledger-window are not sent again. Without a ledger c is returned as is.
+func withLedger(c redfish.Client, host string) redfish.Client {
+	if opLedger == nil {
+		return c
+	}
+	return ledgerClient{Client: c, host: host, l: opLedger}
+}
+
+type ledgerClient struct {
+	redfish.Client
+	host string
+	l    *ledger.Ledger
+}
+
+// previous returns the entry for key if it is recent enough to count.
+func (c ledgerClient) previous(key string) (ledger.Entry, bool) {
+	e, ok := c.l.Last(key)
+	if !ok || ledgerWindow <= 0 || time.Since(e.Time) > ledgerWindow {
+		return ledger.Entry{}, false
+	}
+	return e, true
+}
+
+// run records op as submitted, calls send and records its outcome.
+func (c ledgerClient) run(key, op, target string, send func() (string, error), sent func(error) string) error {
+	e := ledger.Entry{Host: c.host, Op: op, Target: target, Key: key, State: ledger.Submitted}
+	if err := c.l.Record(e); err != nil {
+		return fmt.Errorf("ledger: %w", err)
+	}
+	task, err := send()
+	e.Time, e.State, e.TaskURI = time.Time{}, sent(err), task
+	if err != nil {
+		e.Error = err.Error()
+	}
+	if lerr := c.l.Record(e); lerr != nil {
+		fmt.Fprintf(os.Stderr, "WARN: %s: ledger: %v\n", hostName(c.host), lerr)
+	}
+	return err
+}
+
+func (c ledgerClient) SimpleUpdate(ctx context.Context, imageURI string, targets []string, transferProtocol string, expectedVersion string, force bool) (redfish.UpdateResult, error) {
+	key := ledger.Key(c.host, "SimpleUpdate", map[string]any{"ImageURI": ledgerImage(imageURI), "Targets": targets, "TransferProtocol": transferProtocol})
+	if e, ok := c.previous(key); ok {
+		switch e.State {
+		case ledger.Accepted:
+			return redfish.UpdateResult{TaskURI: e.TaskURI}, fmt.Errorf("%w: the BMC accepted the same update at %s (task %s); see --ledger-window",
+				errAlreadySent, e.Time.Format(time.RFC3339), cmp.Or(e.TaskURI, "none"))
+		case ledger.Submitted, ledger.Unknown:
+			// The earlier attempt may have reached the BMC; an update task
+			// still running means it did
+			tasks, err := c.GetActiveUpdateTasks(ctx)
+			if err != nil {
+				return redfish.UpdateResult{}, fmt.Errorf("an earlier attempt at %s may have reached the BMC and its update tasks cannot be checked: %w", e.Time.Format(time.RFC3339), err)
+			}
+			if len(tasks) > 0 {
+				return redfish.UpdateResult{}, fmt.Errorf("%w: an earlier attempt at %s may have reached the BMC and update task(s) %s are running",
+					errAlreadySent, e.Time.Format(time.RFC3339), strings.Join(tasks, ", "))
+			}
+		}
+	}
+	var res redfish.UpdateResult
+	err := c.run(key, "SimpleUpdate", strings.Join(targets, ","), func() (string, error) {
+		var err error
+		res, err = c.Client.SimpleUpdate(ctx, imageURI, targets, transferProtocol, expectedVersion, force)
+		return res.TaskURI, err
+	}, func(err error) string {
+		var warn *redfish.UpdateWarningsError
+		if errors.As(err, &warn) {
+			return ledger.Accepted // reported after the BMC took the update
+		}
+		return sendOutcome(err)
+	})
+	return res, err
+}
+
+// patch runs a settings PATCH unless the BMC already accepted the same one.
+func (c ledgerClient) patch(op, target string, payload any, send func() error) error {
+	key := ledger.Key(c.host, op, map[string]any{"target": target, "payload": payload})
+	if e, ok := c.previous(key); ok && e.State == ledger.Accepted {
+		fmt.Fprintf(os.Stderr, "WARN: %s: %s %s was accepted at %s; not sending it again\n", hostName(c.host), op, target, e.Time.Format(time.RFC3339))
+		return nil
+	}
+	return c.run(key, op, target, func() (string, error) { return "", send() }, sendOutcome)
+}
+
+func (c ledgerClient) SetAuthorizedKeys(ctx context.Context, authorizedKey string) error {
+	return c.patch("SetAuthorizedKeys", "NetworkProtocol", authorizedKey, func() error { return c.Client.SetAuthorizedKeys(ctx, authorizedKey) })
+}
+
+func (c ledgerClient) SetNTPServers(ctx context.Context, servers []string) error {
+	return c.patch("SetNTPServers", "NetworkProtocol", servers, func() error { return c.Client.SetNTPServers(ctx, servers) })
+}
+
+func (c ledgerClient) SetHostName(ctx context.Context, hostName, fqdn string) error {
+	return c.patch("SetHostName", "NetworkProtocol", []string{hostName, fqdn}, func() error { return c.Client.SetHostName(ctx, hostName, fqdn) })
+}
+
+func (c ledgerClient) SetSyslog(ctx context.Context, targets []redfish.SyslogTarget) error {
+	servers := make([]string, len(targets))
+	for i, t := range targets {
+		servers[i] = t.String()
+	}
+	return c.patch("SetSyslog", "Syslog", servers, func() error { return c.Client.SetSyslog(ctx, targets) })
+}
+
+func (c ledgerClient) SetBiosAttributes(ctx context.Context, systemPath string, attrs map[string]any) error {
+	return c.patch("SetBiosAttributes", systemPath, attrs, func() error { return c.Client.SetBiosAttributes(ctx, systemPath, attrs) })
+}
+
+// sendOutcome maps the error of a write to its ledger state.
+func sendOutcome(err error) string {
+	switch {
+	case err == nil:
+		return ledger.Accepted
+	case !isSendFailure(err) && (classifyHostError(err) == exitUnreachable || errors.Is(err, context.Canceled)):
+		return ledger.Unknown
+	}
+	return ledger.Rejected
+}
+
+// isSendFailure reports whether err shows the request never reached the BMC
+// or was refused by it.
+func isSendFailure(err error) bool {
+	var se *redfish.StatusError
+	var oe *net.OpError
+	return errors.As(err, &se) || (errors.As(err, &oe) && oe.Op == "dial")
+}
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"bootstrap/internal/ledger"
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+)
+
+func TestLedgerGuardsRetries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	ledgerFile, ledgerWindow = path, time.Hour
+	defer func() { ledgerFile, ledgerWindow, opLedger = "", 12*time.Hour, nil }()
+	if err := configureLedger(); err != nil {
+		t.Fatal(err)
+	}
+
+	var updateErr error
+	var tasks []string
+	m := &redfishtest.MockClient{
+		SimpleUpdateFunc: func(context.Context, string, []string, string, string, bool) (redfish.UpdateResult, error) {
+			return redfish.UpdateResult{TaskURI: "/redfish/v1/TaskService/Tasks/7"}, updateErr
+		},
+		GetActiveUpdateTasksFunc: func(context.Context) ([]string, error) { return tasks, nil },
+		SetBiosAttributesFunc:    func(context.Context, string, map[string]any) error { return nil },
+	}
+	c := withLedger(m, "10.0.0.1")
+	update := func() error {
+		_, err := c.SimpleUpdate(context.Background(), "http://10.0.0.1/cc.bin", []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}, "HTTP", "", false)
+		return err
+	}
+	count := func(name string) int {
+		n := 0
+		for _, call := range m.Calls {
+			if call == name {
+				n++
+			}
+		}
+		return n
+	}
+
+	// The response is lost: the BMC may have taken the update
+	updateErr = &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+	if err := update(); err == nil {
+		t.Fatal("lost response not reported")
+	}
+	// Its update task is running, so the retry does not flash again
+	updateErr, tasks = nil, []string{"/redfish/v1/TaskService/Tasks/7"}
+	if err := update(); !errors.Is(err, errAlreadySent) {
+		t.Fatalf("retry with a running task: %v", err)
+	}
+	if n := count("SimpleUpdate"); n != 1 {
+		t.Fatalf("SimpleUpdate sent %d times", n)
+	}
+	// No task: the first attempt never started, so it is sent
+	tasks = nil
+	if err := update(); err != nil {
+		t.Fatal(err)
+	}
+	// Accepted now, also for a new process reading the ledger
+	if err := configureLedger(); err != nil {
+		t.Fatal(err)
+	}
+	c = withLedger(m, "10.0.0.1")
+	if err := update(); err == nil || !strings.Contains(err.Error(), "accepted the same update") {
+		t.Fatalf("retry after accept: %v", err)
+	}
+	if n := count("SimpleUpdate"); n != 2 {
+		t.Errorf("SimpleUpdate sent %d times, want 2", n)
+	}
+	// Other hosts are not affected
+	if _, err := withLedger(m, "10.0.0.2").SimpleUpdate(context.Background(), "http://10.0.0.1/cc.bin", []string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}, "HTTP", "", false); err != nil {
+		t.Fatal(err)
+	}
+
+	attrs := map[string]any{"BootMode": "Uefi"}
+	for range 2 {
+		if err := c.SetBiosAttributes(context.Background(), "/redfish/v1/Systems/Node0", attrs); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := count("SetBiosAttributes"); n != 1 {
+		t.Errorf("SetBiosAttributes sent %d times, want 1", n)
+	}
+
+	e, ok := opLedger.Last(ledger.Key("10.0.0.1", "SetBiosAttributes", map[string]any{"target": "/redfish/v1/Systems/Node0", "payload": attrs}))
+	if !ok || e.State != ledger.Accepted {
+		t.Errorf("ledger entry = %+v", e)
+	}
+}
@@ -0,0 +1,289 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"bootstrap/internal/xname"
+	"bootstrap/pkg/redfish"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	lcFile         string
+	lcHostsCSV     string
+	lcHostsFile    string
+	lcInsecure     bool
+	lcTimeout      time.Duration
+	lcBatchSize    int
+	lcIPMIFallback bool
+)
+
+// nodeXname matches a node xname such as x1000c0s0b0n1.
+var nodeXname = regexp.MustCompile(`^x\d+c\d+s\d+b\d+n(\d+)$`)
+
+// locateTarget is one BMC and, when a node xname was given, the position of the
+// node's system on it (-1 = every system).
+type locateTarget struct {
+	Host string
+	Node int
+}
+
+func (t locateTarget) String() string {
+	if t.Node < 0 {
+		return t.Host
+	}
+	return fmt.Sprintf("%s node %d", t.Host, t.Node)
+}
+
+var locateCmd = &cobra.Command{
+	Use:   "locate",
+	Short: "Control the identify (locator) LEDs of blades and nodes",
+	Long: `Turn the identify LED of nodes on or off so staff can find the physical blade,
+and audit which LEDs were left lit. Targets are BMC hosts or xnames, or node
+xnames (e.g. x1000c0s0b0n1) to select one system of a BMC; without arguments the
+hosts of --hosts, --hosts-file or --file are used. The system's
+LocationIndicatorActive or IndicatorLED is used, or its chassis' when the system
+has none.`,
+}
+
+var locateOnCmd = &cobra.Command{
+	Use:   "on [xname|host]...",
+	Short: "Light the identify LED",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLocate(cmd.Context(), args, true)
+	},
+}
+
+var locateOffCmd = &cobra.Command{
+	Use:   "off [xname|host]...",
+	Short: "Turn the identify LED off",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLocate(cmd.Context(), args, false)
+	},
+}
+
+var locateStatusCmd = &cobra.Command{
+	Use:   "status [xname|host]...",
+	Short: "Report which identify LEDs are lit",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targets, err := locateTargets(args, lcFile, lcHostsCSV, lcHostsFile)
+		if err != nil {
+			return err
+		}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+		var mu sync.Mutex
+		failed := map[string]error{}
+		counts := map[string]int{}
+		var lit []string
+		forEachHost(ctx, targetHosts(targets), lcBatchSize, func(ctx context.Context, h string) {
+			locs, err := hostLocators(ctx, h, user, pass)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", hostName(h), err)
+				failed[h] = err
+				return
+			}
+			for _, t := range targets {
+				if t.Host != h {
+					continue
+				}
+				for i, l := range locs {
+					if t.Node >= 0 && t.Node != i {
+						continue
+					}
+					state := l.State
+					if state == "" {
+						state = "unknown"
+					}
+					counts[state]++
+					if state != "Off" && state != "unknown" {
+						lit = append(lit, fmt.Sprintf("%s %s: %s (%s)", h, l.SystemID, state, l.Path))
+					}
+				}
+			}
+		}, func(string) {})
+		sort.Strings(lit)
+		for _, l := range lit {
+			fmt.Printf("LIT: %s\n", l)
+		}
+		fmt.Println("Locator summary:")
+		fmt.Printf("  lit: %d\n", len(lit))
+		fmt.Printf("  off: %d\n", counts["Off"])
+		fmt.Printf("  unknown: %d\n", counts["unknown"])
+		fmt.Printf("  errors: %d\n", len(failed))
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+		return hostFailures(len(targetHosts(targets)), failed)
+	},
+}
+
+// runLocate turns the LEDs of the targets on or off.
+func runLocate(ctx context.Context, args []string, on bool) error {
+	targets, err := locateTargets(args, lcFile, lcHostsCSV, lcHostsFile)
+	if err != nil {
+		return err
+	}
+	user, pass, err := redfishCreds()
+	if err != nil {
+		return err
+	}
+	state := "off"
+	if on {
+		state = "on"
+	}
+	byHost := map[string][]locateTarget{}
+	for _, t := range targets {
+		byHost[t.Host] = append(byHost[t.Host], t)
+	}
+	var mu sync.Mutex
+	failed := map[string]error{}
+	forEachHost(ctx, targetHosts(targets), lcBatchSize, func(ctx context.Context, h string) {
+		via, err := setHostLocators(ctx, byHost[h], user, pass, on)
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "WARN: %s: locator %s: %v\n", hostName(h), state, err)
+			failed[h] = err
+			return
+		}
+		for _, t := range byHost[h] {
+			fmt.Printf("%s: locator %s (%s)\n", t, state, via)
+		}
+	}, func(string) {})
+	if ctx.Err() != nil {
+		return errInterrupted
+	}
+	return hostFailures(len(byHost), failed)
+}
+
+func hostLocators(ctx context.Context, host, user, pass string) ([]redfish.Locator, error) {
+	if lcTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, lcTimeout)
+		defer cancel()
+	}
+	return newRedfishClient(host, user, pass, lcInsecure, lcTimeout).GetLocators(ctx)
+}
+
+// setHostLocators sets the LEDs of targets on one BMC and returns the protocol
+// used. When the Redfish probe fails and --allow-ipmi-fallback is set, the
+// chassis identify light is set over IPMI instead.
+func setHostLocators(ctx context.Context, targets []locateTarget, user, pass string, on bool) (string, error) {
+	if lcTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, lcTimeout)
+		defer cancel()
+	}
+	host := targets[0].Host
+	rf := newRedfishClient(host, user, pass, lcInsecure, lcTimeout)
+	locs, err := rf.GetLocators(ctx)
+	if err != nil {
+		if !lcIPMIFallback || ctx.Err() != nil {
+			return "", err
+		}
+		fmt.Fprintf(os.Stderr, "WARN: %s: Redfish probe failed (%v); falling back to IPMI\n", hostName(host), err)
+		u, p := ipmiCreds(user, pass)
+		return "ipmi", newIPMIClient(host, u, p, lcTimeout).Identify(ctx, on)
+	}
+	for _, t := range targets {
+		path := ""
+		if t.Node >= 0 {
+			if t.Node >= len(locs) {
+				return "", fmt.Errorf("node %d not found (%d system(s))", t.Node, len(locs))
+			}
+			path = locs[t.Node].SystemPath
+		}
+		if err := rf.SetLocator(ctx, path, on); err != nil {
+			return "", err
+		}
+	}
+	return "redfish", nil
+}
+
+// locateTargets turns the command arguments (or, without any, the host flags)
+// into targets. Node xnames select one system of their BMC.
+func locateTargets(args []string, file, hostsCSV, hostsFile string) ([]locateTarget, error) {
+	if len(args) == 0 {
+		if file == "" && hostsCSV == "" && hostsFile == "" {
+			return nil, invalidf("give xnames or hosts, or one of --file, --hosts or --hosts-file")
+		}
+		hosts, err := resolveHosts(file, hostsCSV, hostsFile)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]locateTarget, 0, len(hosts))
+		for _, h := range hosts {
+			out = append(out, locateTarget{Host: h, Node: -1})
+		}
+		return out, nil
+	}
+	doc, err := loadInventory(file)
+	if err != nil {
+		return nil, err
+	}
+	hostNames = aliasNames(doc)
+	aliases := aliasXnames(doc)
+	var out []locateTarget
+	seen := map[locateTarget]bool{}
+	for _, a := range args {
+		if x, ok := aliases[strings.ToLower(a)]; ok {
+			a = x
+		}
+		t := locateTarget{Host: a, Node: -1}
+		if m := nodeXname.FindStringSubmatch(strings.ToLower(a)); m != nil {
+			t.Host = xname.NodeToBMCXname(strings.ToLower(a))
+			t.Node, _ = strconv.Atoi(m[1])
+		}
+		t.Host = canonicalHost(bmcAddr(t.Host, doc.BMCs, nil))
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+// targetHosts returns the distinct hosts of targets in order.
+func targetHosts(targets []locateTarget) []string {
+	var hosts []string
+	seen := map[string]bool{}
+	for _, t := range targets {
+		if !seen[t.Host] {
+			seen[t.Host] = true
+			hosts = append(hosts, t.Host)
+		}
+	}
+	return hosts
+}
+
+func init() {
+	rootCmd.AddCommand(locateCmd)
+	locateCmd.AddCommand(locateOnCmd, locateOffCmd, locateStatusCmd)
+	locateCmd.PersistentFlags().StringVarP(&lcFile, "file", "f", "", "Inventory file to read bmcs[] from (also resolves xname arguments to BMC IPs)")
+	locateCmd.PersistentFlags().StringVar(&lcHostsCSV, "hosts", "", "Comma-separated list of BMC hosts to target when no arguments are given")
+	locateCmd.PersistentFlags().StringVar(&lcHostsFile, "hosts-file", "", "file listing BMC hosts or bmcs[] xnames to target when no arguments are given")
+	locateCmd.PersistentFlags().BoolVar(&lcInsecure, "insecure", true, "allow insecure TLS to BMCs")
+	locateCmd.PersistentFlags().DurationVar(&lcTimeout, "timeout", 30*time.Second, "per-BMC timeout")
+	locateCmd.PersistentFlags().IntVar(&lcBatchSize, "batch-size", 10, "number of BMCs to contact concurrently (0 or 1 = serial)")
+	locateOnCmd.Flags().BoolVar(&lcIPMIFallback, "allow-ipmi-fallback", false, "use ipmitool chassis identify for BMCs whose Redfish service cannot be probed")
+	locateOffCmd.Flags().BoolVar(&lcIPMIFallback, "allow-ipmi-fallback", false, "use ipmitool chassis identify for BMCs whose Redfish service cannot be probed")
+}
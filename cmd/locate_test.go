@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"bootstrap/internal/ipmi"
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+)
+
+func TestLocate(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	var set []string
+	locs := []redfish.Locator{
+		{SystemPath: "/redfish/v1/Systems/Node0", SystemID: "Node0", State: "Off"},
+		{SystemPath: "/redfish/v1/Systems/Node1", SystemID: "Node1", State: "Lit"},
+	}
+	good := &redfishtest.MockClient{
+		GetLocatorsFunc: func(context.Context) ([]redfish.Locator, error) { return locs, nil },
+		SetLocatorFunc: func(_ context.Context, path string, on bool) error {
+			if on {
+				set = append(set, "on "+path)
+			} else {
+				set = append(set, "off "+path)
+			}
+			return nil
+		},
+	}
+	broken := &redfishtest.MockClient{
+		GetLocatorsFunc: func(context.Context) ([]redfish.Locator, error) {
+			return nil, errors.New("404 Not Found")
+		},
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"x1000c0s0b0": good, "b": broken})
+	var ipmiCalls []string
+	oldIPMI := newIPMIClient
+	newIPMIClient = func(host, user, pass string, timeout time.Duration) *ipmi.Client {
+		return &ipmi.Client{Host: host, User: user, Pass: pass, Run: func(_ context.Context, args, _ []string) ([]byte, error) {
+			ipmiCalls = append(ipmiCalls, host+" "+strings.Join(args[len(args)-3:], " "))
+			return nil, nil
+		}}
+	}
+	defer func() { newIPMIClient = oldIPMI }()
+	lcBatchSize = 1
+	defer func() { lcIPMIFallback = false }()
+	ctx := context.Background()
+
+	// A node xname selects one system of its BMC
+	locateOnCmd.SetContext(ctx)
+	if err := locateOnCmd.RunE(locateOnCmd, []string{"x1000c0s0b0n1"}); err != nil {
+		t.Fatalf("on: %v", err)
+	}
+	if len(set) != 1 || set[0] != "on /redfish/v1/Systems/Node1" {
+		t.Errorf("set = %v", set)
+	}
+
+	// Without the fallback the broken BMC fails; with it, IPMI is used
+	locateOffCmd.SetContext(ctx)
+	if got := exitCode(locateOffCmd.RunE(locateOffCmd, []string{"x1000c0s0b0", "b"})); got != exitPartial {
+		t.Errorf("off: exit code %d, want %d", got, exitPartial)
+	}
+	lcIPMIFallback = true
+	if err := locateOffCmd.RunE(locateOffCmd, []string{"b"}); err != nil {
+		t.Fatalf("off with fallback: %v", err)
+	}
+	if len(ipmiCalls) != 1 || ipmiCalls[0] != "b chassis identify 0" {
+		t.Errorf("ipmi calls = %v", ipmiCalls)
+	}
+
+	locateStatusCmd.SetContext(ctx)
+	if err := locateStatusCmd.RunE(locateStatusCmd, []string{"x1000c0s0b0"}); err != nil {
+		t.Errorf("status: %v", err)
+	}
+	if err := locateStatusCmd.RunE(locateStatusCmd, nil); exitCode(err) != exitInvalid {
+		t.Errorf("status without targets: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"bootstrap/internal/maintwindow"
+)
+
+var (
+	windowSpecs []string
+	windowFile  string
+)
+
+// maintenanceWindows are the windows destructive operations may run in; empty
+// for no restriction. Set by configureWindows.
+var maintenanceWindows maintwindow.Set
+
+// windowNow is the clock windows are checked against; tests replace it.
+var windowNow = time.Now
+
+// windowTime formats window openings and closings.
+const windowTime = "Mon 2006-01-02 15:04 MST"
+
+func configureWindows() error {
+	maintenanceWindows = nil
+	specs := windowSpecs
+	if env := os.Getenv("BOOTSTRAP_MAINTENANCE_WINDOW"); len(specs) == 0 && env != "" {
+		specs = strings.Split(env, ";")
+	}
+	s, err := maintwindow.ParseSet(specs)
+	if err != nil {
+		return invalidf("--maintenance-window: %v", err)
+	}
+	if path := cmpOrEnv(windowFile, "BOOTSTRAP_MAINTENANCE_WINDOW_FILE"); path != "" {
+		f, err := maintwindow.Load(path)
+		if err != nil {
+			return invalidf("--maintenance-window-file: %v", err)
+		}
+		s = append(s, f...)
+	}
+	maintenanceWindows = s
+	return nil
+}
+
+// checkWindow refuses to start op outside the maintenance windows.
+func checkWindow(op string) error {
+	if len(maintenanceWindows) == 0 {
+		return nil
+	}
+	now := windowNow()
+	if _, open := maintenanceWindows.Open(now); open {
+		return nil
+	}
+	msg := fmt.Sprintf("%s is only allowed in the maintenance window (%s)", op, maintenanceWindows)
+	if next := maintenanceWindows.Next(now); !next.IsZero() {
+		msg += "; the next one opens " + next.Format(windowTime)
+	}
+	return invalidf("%s", msg)
+}
+
+// untilWindowCloses returns a context, derived from launch, for starting the
+// hosts of a destructive run: it ends when the maintenance window open now
+// closes, so hosts not started by then are left alone.
+func untilWindowCloses(launch context.Context) (context.Context, context.CancelFunc) {
+	if len(maintenanceWindows) == 0 {
+		return context.WithCancel(launch)
+	}
+	now := windowNow()
+	closes, open := maintenanceWindows.Open(now)
+	if !open {
+		closes = now // closed while the operator confirmed
+	}
+	return context.WithTimeoutCause(launch, closes.Sub(now), fmt.Errorf("maintenance window closed at %s", closes.Format(windowTime)))
+}
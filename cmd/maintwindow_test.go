@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"bootstrap/internal/maintwindow"
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+)
+
+func TestMaintenanceWindow(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	var resets []string
+	bmc := func(host string) *redfishtest.MockClient {
+		return &redfishtest.MockClient{
+			GetSystemsFunc: func(context.Context) ([]redfish.System, error) {
+				var s redfish.System
+				s.ID = "Node0"
+				return []redfish.System{s}, nil
+			},
+			ResetSystemFunc: func(context.Context, string, string) error {
+				resets = append(resets, host)
+				time.Sleep(600 * time.Millisecond)
+				return nil
+			},
+		}
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"a": bmc("a"), "b": bmc("b"), "c": bmc("c")})
+	var err error
+	if maintenanceWindows, err = maintwindow.ParseSet([]string{"Sat 00:00-06:00 UTC"}); err != nil {
+		t.Fatal(err)
+	}
+	pwHostsCSV, pwBatchSize = "a,b,c", 1
+	t.Cleanup(func() { pwHostsCSV, maintenanceWindows, windowNow = "", nil, time.Now })
+	cmd := powerCmd
+	cmd.SetContext(context.Background())
+
+	// Outside the window nothing starts
+	windowNow = func() time.Time { return time.Date(2025, 7, 2, 12, 0, 0, 0, time.UTC) }
+	err = cmd.RunE(cmd, []string{"cycle"})
+	if exitCode(err) != exitInvalid || !strings.Contains(err.Error(), "the next one opens Sat 2025-07-05 00:00 UTC") || len(resets) != 0 {
+		t.Fatalf("outside the window: %v, resets %v", err, resets)
+	}
+	// Status is not destructive
+	if err := cmd.RunE(cmd, []string{"status"}); err != nil {
+		t.Errorf("status outside the window: %v", err)
+	}
+
+	// The window closes while the first host is being reset
+	windowNow = func() time.Time { return time.Date(2025, 7, 5, 5, 59, 59, 700e6, time.UTC) }
+	err = cmd.RunE(cmd, []string{"cycle"})
+	if exitCode(err) != exitPartial || !strings.Contains(err.Error(), "maintenance window closed at Sat 2025-07-05 06:00 UTC") {
+		t.Errorf("window closing: %v", err)
+	}
+	if strings.Join(resets, ",") != "a" {
+		t.Errorf("resets = %v, want only a", resets)
+	}
+}
+
+func TestConfigureWindows(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "windows.yaml")
+	if err := os.WriteFile(file, []byte("windows:\n  - Sun 00:00-06:00 UTC\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { windowSpecs, windowFile, maintenanceWindows = nil, "", nil })
+	t.Setenv("BOOTSTRAP_MAINTENANCE_WINDOW", "Sat 00:00-06:00 UTC; Tue 22:00-02:00 UTC")
+	t.Setenv("BOOTSTRAP_MAINTENANCE_WINDOW_FILE", file)
+	if err := configureWindows(); err != nil || len(maintenanceWindows) != 3 {
+		t.Fatalf("from the environment: %v, %v", maintenanceWindows, err)
+	}
+	windowSpecs = []string{"Mon 01:00-02:00 UTC"}
+	if err := configureWindows(); err != nil || maintenanceWindows.String() != "Mon 01:00-02:00 UTC; Sun 00:00-06:00 UTC" {
+		t.Errorf("--maintenance-window overrides the variable: %v, %v", maintenanceWindows, err)
+	}
+	windowSpecs = []string{"Caturday 00:00-06:00"}
+	if err := configureWindows(); exitCode(err) != exitInvalid {
+		t.Errorf("bad window: %v", err)
+	}
+}
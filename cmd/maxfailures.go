@@ -0,0 +1,100 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// maxFailuresUsage is the help text of the --max-failures flag.
+const maxFailuresUsage = "stop starting hosts once more than this many hosts (N) or this share of hosts (N%) have failed; hosts already in flight finish, the rest are not attempted"
+
+// failureBudget implements --max-failures. Once more hosts than its limit have
+// failed it cancels the context a batch command starts hosts under, so
+// forEachHost hands the hosts not yet started to its aborted callback while
+// the ones in flight run to completion on the command's own context.
+type failureBudget struct {
+	spec   string
+	limit  int // 0: no limit
+	cancel context.CancelCauseFunc
+
+	mu     sync.Mutex
+	failed int
+}
+
+// newFailureBudget parses --max-failures spec for total hosts and returns the
+// budget and the context, derived from ctx, to start hosts under. The caller
+// must call stop once the hosts are done.
+func newFailureBudget(ctx context.Context, spec string, total int) (*failureBudget, context.Context, error) {
+	limit, err := parseThreshold(spec, total)
+	if err != nil {
+		return nil, nil, invalidf("--max-failures: %v", err)
+	}
+	launch, cancel := context.WithCancelCause(ctx)
+	return &failureBudget{spec: spec, limit: limit, cancel: cancel}, launch, nil
+}
+
+// Fail records a failed host, cancelling the launch context once the limit is
+// exceeded.
+func (b *failureBudget) Fail() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failed++
+	if b.limit > 0 && b.failed == b.limit+1 {
+		fmt.Fprintf(os.Stderr, "WARN: %d host(s) failed, more than --max-failures %s; not starting further hosts\n", b.failed, b.spec)
+		b.cancel(fmt.Errorf("--max-failures %s exceeded", b.spec))
+	}
+}
+
+func (b *failureBudget) stop() { b.cancel(nil) }
+
+// launchStopped returns why launch, the context a command starts hosts under,
+// ended before ctx, the command's own: the failure budget or the maintenance
+// window. It returns nil when launch did not end early.
+func launchStopped(ctx, launch context.Context) error {
+	if ctx.Err() != nil || launch.Err() == nil {
+		return nil
+	}
+	return context.Cause(launch)
+}
+
+// reportStopped prints which hosts were attempted and which were not started
+// after cause stopped a run over hosts early, and returns the command error: a
+// partial failure, whatever the failures were.
+func reportStopped(cause error, hosts, notStarted []string, failed map[string]error) error {
+	noteHostFailures(failed)
+	skip := map[string]bool{}
+	for _, h := range notStarted {
+		skip[h] = true
+	}
+	var attempted, pending []string
+	for _, h := range hosts {
+		if skip[h] {
+			pending = append(pending, h)
+		} else {
+			attempted = append(attempted, h)
+		}
+	}
+	failedHosts := make([]string, 0, len(failed))
+	for h := range failed {
+		failedHosts = append(failedHosts, h)
+	}
+	slices.Sort(failedHosts)
+	fmt.Fprintf(os.Stderr, "Stopped early (%v): %d host(s) attempted, %d failed, %d not started\n",
+		cause, len(attempted), len(failed), len(pending))
+	fmt.Fprintf(os.Stderr, "  Attempted: %s\n", strings.Join(attempted, ", "))
+	if len(failedHosts) > 0 {
+		fmt.Fprintf(os.Stderr, "  Failed: %s\n", strings.Join(failedHosts, ", "))
+	}
+	if len(pending) > 0 {
+		fmt.Fprintf(os.Stderr, "  Not started: %s\n", strings.Join(pending, ", "))
+	}
+	return &exitError{code: exitPartial, err: fmt.Errorf("%w: %d of %d host(s) failed, %d not started", cause, len(failed), len(hosts), len(pending))}
+}
@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"bootstrap/internal/xname"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	nicFile      string
+	nicHostsCSV  string
+	nicHostsFile string
+	nicInsecure  bool
+	nicTimeout   time.Duration
+	nicBatchSize int
+	nicFormat    string
+	nicBootOnly  bool
+)
+
+// nicRow is one interface of one node in the nics report.
+type nicRow struct {
+	Host       string `json:"host"`
+	Alias      string `json:"alias,omitempty"`
+	Node       string `json:"node"` // node xname, or host[index] when the BMC xname is unknown
+	ID         string `json:"id"`
+	MAC        string `json:"mac"`
+	Boot       bool   `json:"boot"` // the MAC nodes[] records for the node
+	Enabled    *bool  `json:"enabled,omitempty"`
+	LinkStatus string `json:"link_status,omitempty"`
+	SpeedMbps  int    `json:"speed_mbps,omitempty"`
+	VLAN       int    `json:"vlan,omitempty"`
+	HostName   string `json:"hostname,omitempty"`
+	FQDN       string `json:"fqdn,omitempty"`
+}
+
+var nicsCmd = &cobra.Command{
+	Use:   "nics",
+	Short: "Tabulate the network interfaces of every node: link, speed, VLAN and host name",
+	Long: `Read the EthernetInterfaces of every system behind each BMC and print one row per
+interface with a MAC: whether it is enabled, its LinkStatus, SpeedMbps, VLAN and
+HostName/FQDN. The interface whose MAC nodes[] of --file records for the node
+(the one discover chose to boot from) is marked in the BOOT column, so a boot
+NIC without link stands out.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if nicFormat != "" && nicFormat != "json" && nicFormat != "csv" {
+			return invalidf("--format must be json or csv when set")
+		}
+		if nicFile == "" && nicHostsCSV == "" && nicHostsFile == "" {
+			return invalidf("at least one of --file, --hosts or --hosts-file is required")
+		}
+		hosts, err := resolveHosts(nicFile, nicHostsCSV, nicHostsFile)
+		if err != nil {
+			return err
+		}
+		doc, err := loadInventory(nicFile)
+		if err != nil {
+			return err
+		}
+		bootMACs := map[string]string{}
+		for _, n := range doc.Nodes {
+			bootMACs[strings.ToLower(n.Xname)] = strings.ToLower(n.MAC)
+		}
+		bmcXnames := hostXnames(hosts, doc.BMCs)
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		var mu sync.Mutex
+		var rows []nicRow
+		failed := map[string]error{}
+		forEachHost(ctx, hosts, nicBatchSize, func(ctx context.Context, h string) {
+			if nicTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, nicTimeout)
+				defer cancel()
+			}
+			nics, err := newRedfishClient(h, user, pass, nicInsecure, nicTimeout).DiscoverSystemNICs(ctx)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", hostName(h), err)
+				failed[h] = err
+				return
+			}
+			for _, n := range nics {
+				node := fmt.Sprintf("%s[%d]", h, n.SystemIndex)
+				if x, err := xname.BMCXnameToNodeN(bmcXnames[h], n.SystemIndex); err == nil {
+					node = x
+				}
+				r := nicRow{
+					Host: h, Alias: hostAlias(h), Node: node, ID: n.ID, MAC: n.MAC,
+					Boot:    bootMACs[strings.ToLower(node)] == n.MAC,
+					Enabled: n.Enabled, LinkStatus: n.LinkStatus, SpeedMbps: n.SpeedMbps,
+					VLAN: n.VLAN, HostName: n.HostName, FQDN: n.FQDN,
+				}
+				if r.Boot || !nicBootOnly {
+					rows = append(rows, r)
+				}
+			}
+		}, func(string) {})
+
+		hostIdx := map[string]int{}
+		for i, h := range hosts {
+			hostIdx[h] = i
+		}
+		slices.SortStableFunc(rows, func(a, b nicRow) int {
+			return cmp.Or(cmp.Compare(hostIdx[a.Host], hostIdx[b.Host]), cmp.Compare(a.Node, b.Node))
+		})
+		if err := printNICs(rows); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+		return hostFailures(len(hosts), failed)
+	},
+}
+
+func printNICs(rows []nicRow) error {
+	switch nicFormat {
+	case "json":
+		out, err := json.MarshalIndent(rows, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	case "csv":
+		var records [][]string
+		for _, r := range rows {
+			records = append(records, []string{r.Host, r.Alias, r.Node, r.ID, r.MAC, strconv.FormatBool(r.Boot),
+				enabledText(r.Enabled), r.LinkStatus, speedText(r.SpeedMbps), vlanText(r.VLAN), r.HostName, r.FQDN})
+		}
+		return writeCSV(os.Stdout, []string{"host", "alias", "node", "id", "mac", "boot", "enabled", "link_status", "speed_mbps", "vlan", "hostname", "fqdn"}, records)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NODE\tNIC\tMAC\tBOOT\tENABLED\tLINK\tSPEED\tVLAN\tHOSTNAME")
+	for _, r := range rows {
+		boot := ""
+		if r.Boot {
+			boot = "*"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Node, r.ID, r.MAC, boot,
+			cmp.Or(enabledText(r.Enabled), "-"), cmp.Or(r.LinkStatus, "-"), cmp.Or(speedText(r.SpeedMbps), "-"),
+			cmp.Or(vlanText(r.VLAN), "-"), cmp.Or(r.FQDN, r.HostName, "-"))
+	}
+	return tw.Flush()
+}
+
+func enabledText(b *bool) string {
+	if b == nil {
+		return ""
+	}
+	return strconv.FormatBool(*b)
+}
+
+func speedText(mbps int) string {
+	if mbps == 0 {
+		return ""
+	}
+	return strconv.Itoa(mbps)
+}
+
+func vlanText(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
+
+func init() {
+	rootCmd.AddCommand(nicsCmd)
+	nicsCmd.Flags().StringVarP(&nicFile, "file", "f", "", "inventory whose bmcs[] are read and whose nodes[] MACs mark the boot NIC")
+	nicsCmd.Flags().StringVar(&nicHostsCSV, "hosts", "", "comma-separated BMC hosts, xnames or aliases (overrides --hosts-file)")
+	nicsCmd.Flags().StringVar(&nicHostsFile, "hosts-file", "", "file listing BMC hosts or bmcs[] xnames, one per line ('#' starts a comment)")
+	nicsCmd.Flags().BoolVar(&nicInsecure, "insecure", true, "allow insecure TLS to BMCs")
+	nicsCmd.Flags().DurationVar(&nicTimeout, "timeout", 30*time.Second, "per-BMC timeout")
+	nicsCmd.Flags().IntVar(&nicBatchSize, "batch-size", 10, "number of BMCs to contact concurrently (0 or 1 = serial)")
+	nicsCmd.Flags().StringVar(&nicFormat, "format", "", "output format: json or csv (default: table)")
+	nicsCmd.Flags().BoolVar(&nicBootOnly, "boot-only", false, "list only the boot NIC of each node")
+}
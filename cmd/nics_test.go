@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+)
+
+func TestNICs(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	inv := filepath.Join(t.TempDir(), "inventory.yaml")
+	doc := `bmcs:
+  - xname: x1000c0s0b0
+    ip: 10.0.0.1
+  - xname: x1000c0s1b0
+    ip: 10.0.0.2
+nodes:
+  - xname: x1000c0s0b0n1
+    mac: aa:00:00:00:00:02
+`
+	if err := os.WriteFile(inv, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	on := true
+	good := &redfishtest.MockClient{
+		DiscoverSystemNICsFunc: func(context.Context) ([]redfish.SystemNIC, error) {
+			return []redfish.SystemNIC{
+				{SystemIndex: 1, ID: "eth0", MAC: "aa:00:00:00:00:02", Enabled: &on, LinkStatus: "LinkDown", SpeedMbps: 25000},
+				{SystemIndex: 1, ID: "hsn0", MAC: "bb:00:00:00:00:02", LinkStatus: "LinkUp", VLAN: 10, HostName: "nid000002"},
+			}, nil
+		},
+	}
+	broken := &redfishtest.MockClient{
+		DiscoverSystemNICsFunc: func(context.Context) ([]redfish.SystemNIC, error) {
+			return nil, errors.New("503 Service Unavailable")
+		},
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"10.0.0.1": good, "10.0.0.2": broken})
+	t.Cleanup(func() { nicFile, nicFormat, nicBootOnly, hostNames = "", "", false, nil })
+	nicFile = inv
+	nicsCmd.SetContext(context.Background())
+
+	run := func() (string, error) {
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := nicsCmd.RunE(nicsCmd, nil)
+		w.Close() //nolint:errcheck
+		os.Stdout = old
+		out, _ := io.ReadAll(r)
+		return string(out), err
+	}
+
+	out, err := run()
+	if got := exitCode(err); got != exitPartial {
+		t.Errorf("exit code %d, want %d", got, exitPartial)
+	}
+	want := `NODE           NIC   MAC                BOOT  ENABLED  LINK      SPEED  VLAN  HOSTNAME
+x1000c0s0b0n1  eth0  aa:00:00:00:00:02  *     true     LinkDown  25000  -     -
+x1000c0s0b0n1  hsn0  bb:00:00:00:00:02        -        LinkUp    -      10    nid000002
+`
+	if out != want {
+		t.Errorf("table:\n%s\nwant:\n%s", out, want)
+	}
+
+	nicFormat, nicBootOnly = "csv", true
+	out, _ = run()
+	if want := "x1000c0s0b0n1,eth0,aa:00:00:00:00:02,true,true,LinkDown,25000,,,\n"; !strings.HasSuffix(out, want) || strings.Count(out, "\n") != 2 {
+		t.Errorf("csv:\n%s", out)
+	}
+}
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"bootstrap/internal/notify"
+)
+
+var (
+	notifyConfig     string
+	webhookURLs      []string
+	webhookFormat    string
+	webhookTemplate  string
+	webhookThreshold string
+)
+
+// notifiers are the sinks from --notify-config and --webhook, and
+// notifyThreshold the failure threshold; set by configureNotifiers.
+var (
+	notifiers       []notify.Notifier
+	notifyThreshold string
+)
+
+func configureNotifiers() error {
+	notifiers, notifyThreshold = nil, webhookThreshold
+	if notifyConfig != "" {
+		c, err := notify.LoadConfig(notifyConfig)
+		if err != nil {
+			return invalidf("--notify-config: %w", err)
+		}
+		if notifiers, err = c.Notifiers(); err != nil {
+			return invalidf("--notify-config %s: %w", notifyConfig, err)
+		}
+		if notifyThreshold == "" {
+			notifyThreshold = c.FailureThreshold
+		}
+	}
+	if _, err := parseThreshold(notifyThreshold, 1); err != nil {
+		return err
+	}
+	if len(webhookURLs) == 0 {
+		return nil
+	}
+	switch webhookFormat {
+	case "", notify.FormatJSON, notify.FormatSlack:
+	default:
+		return invalidf("--webhook-format must be json or slack")
+	}
+	var body *template.Template
+	if webhookTemplate != "" {
+		var err error
+		if body, err = notify.ParseTemplate(webhookTemplate); err != nil {
+			return invalidf("--webhook-template: %w", err)
+		}
+	}
+	for _, u := range webhookURLs {
+		if !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+			return invalidf("--webhook %q: must be an http:// or https:// URL", u)
+		}
+		format := webhookFormat
+		if format == "" {
+			format = notify.FormatFor(u)
+		}
+		notifiers = append(notifiers, notify.Hook{URL: u, Format: format, Body: body})
+	}
+	return nil
+}
+
+// parseThreshold turns a failure threshold, a host count or a percentage of
+// total, into a host count (0 when unset).
+func parseThreshold(s string, total int) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if pct, ok := strings.CutSuffix(s, "%"); ok {
+		p, err := strconv.ParseFloat(pct, 64)
+		if err != nil || p <= 0 || p > 100 {
+			return 0, invalidf("failure threshold %q: percentage must be in (0, 100]", s)
+		}
+		n := int(p*float64(total)/100 + 0.999999)
+		return max(n, 1), nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, invalidf("failure threshold %q: must be a positive number of hosts or a percentage", s)
+	}
+	return n, nil
+}
+
+// newNotifier returns the notification tracker for op over total hosts (nil,
+// which does nothing, without --notify-config or --webhook).
+func newNotifier(op string, total int) *notify.Tracker {
+	threshold, _ := parseThreshold(notifyThreshold, total) // validated by configureNotifiers
+	return notify.NewTracker(notifiers, op, total, threshold)
+}
+
+// finishNotify sends the end-of-run notification for err and returns err.
+func finishNotify(t *notify.Tracker, err error) error {
+	t.Finish(err, errors.Is(err, errInterrupted))
+	return err
+}
@@ -0,0 +1,245 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"bootstrap/internal/diag"
+	"bootstrap/internal/ipmi"
+	"bootstrap/internal/topology"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	pwFile         string
+	pwHostsCSV     string
+	pwHostsFile    string
+	pwInsecure     bool
+	pwTimeout      time.Duration
+	pwBatchSize    int
+	pwDryRun       bool
+	pwOrder        string
+	pwMaxFailures  string
+	pwIPMIFallback bool
+	pwFailedOut    string
+)
+
+// Redfish ResetType of each power action
+var powerResetTypes = map[string]string{
+	ipmi.PowerOn:    "On",
+	ipmi.PowerOff:   "ForceOff",
+	ipmi.PowerSoft:  "GracefulShutdown",
+	ipmi.PowerCycle: "PowerCycle",
+	ipmi.PowerReset: "ForceRestart",
+}
+
+// newIPMIClient opens an IPMI client for a BMC. Tests replace it to fake ipmitool.
+var newIPMIClient = func(host, user, pass string, timeout time.Duration) *ipmi.Client {
+	return &ipmi.Client{Host: host, User: user, Pass: pass, Timeout: timeout}
+}
+
+var powerCmd = &cobra.Command{
+	Use:       "power status|on|off|soft|cycle|reset",
+	Short:     "Query or change node power via Redfish, optionally falling back to IPMI",
+	ValidArgs: []string{"status", ipmi.PowerOn, ipmi.PowerOff, ipmi.PowerSoft, ipmi.PowerCycle, ipmi.PowerReset},
+	Args:      cobra.ExactArgs(1),
+	Long: `Query or change the power state of every system behind each BMC. Actions map to
+Redfish ComputerSystem.Reset types: on (On), off (ForceOff), soft (GracefulShutdown),
+cycle (PowerCycle) and reset (ForceRestart).
+
+With --allow-ipmi-fallback, BMCs whose Redfish service cannot be probed are driven
+with ipmitool over IPMI-over-LAN instead. IPMI_USER and IPMI_PASSWORD are used when
+set, otherwise the Redfish credentials.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		action := args[0]
+		if _, ok := powerResetTypes[action]; !ok && action != "status" {
+			return invalidf("unknown power action %q (use status, on, off, soft, cycle or reset)", action)
+		}
+		if pwFile == "" && pwHostsCSV == "" && pwHostsFile == "" {
+			return invalidf("at least one of --file, --hosts or --hosts-file is required")
+		}
+		hosts, err := resolveHosts(pwFile, pwHostsCSV, pwHostsFile)
+		if err != nil {
+			return err
+		}
+		if hosts, err = orderHosts(hosts, pwOrder, pwFile); err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+		budget, launch, err := newFailureBudget(ctx, pwMaxFailures, len(hosts))
+		if err != nil {
+			return err
+		}
+		defer budget.stop()
+		if pwDryRun && action != "status" {
+			fmt.Printf("[dry-run] would power %s %d host(s): %s\n", action, len(hosts), strings.Join(hosts, ", "))
+			return nil
+		}
+		if action != "status" && action != ipmi.PowerOn {
+			if stop, err := needsApproval(cmd, args, "power "+action, hosts); stop || err != nil {
+				return err
+			}
+			var stopLaunch context.CancelFunc
+			launch, stopLaunch = untilWindowCloses(launch)
+			defer stopLaunch()
+		}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+
+		var mu sync.Mutex
+		var results []powerResult
+		var notStarted []string
+		forEachHost(launch, hosts, pwBatchSize, func(_ context.Context, h string) {
+			r := powerHost(ctx, h, action, user, pass)
+			mu.Lock()
+			defer mu.Unlock()
+			r.print(action)
+			results = append(results, r)
+			if r.Err != nil {
+				budget.Fail()
+			}
+		}, func(h string) {
+			mu.Lock()
+			defer mu.Unlock()
+			notStarted = append(notStarted, h)
+		})
+
+		failed := map[string]error{}
+		counts := map[string]int{}
+		for _, r := range results {
+			if r.Err != nil {
+				failed[r.Host] = r.Err
+				continue
+			}
+			counts[r.Via]++
+		}
+		if pwFailedOut != "" {
+			if err := writeFailedHosts(pwFailedOut, hosts, failed); err != nil {
+				return err
+			}
+		}
+		fmt.Println("Power summary:")
+		fmt.Printf("  hosts: %d\n", len(hosts))
+		fmt.Printf("  via redfish: %d\n", counts["redfish"])
+		if pwIPMIFallback {
+			fmt.Printf("  via ipmi: %d\n", counts["ipmi"])
+		}
+		fmt.Printf("  failed: %d\n", len(failed))
+		if ctx.Err() != nil {
+			fmt.Fprintf(os.Stderr, "Interrupted: %d of %d host(s) done\n", len(results), len(hosts))
+			return errInterrupted
+		}
+		if stopped := launchStopped(ctx, launch); stopped != nil {
+			return reportStopped(stopped, hosts, notStarted, failed)
+		}
+		return hostFailures(len(hosts), failed)
+	},
+}
+
+// powerResult is the outcome of a power query or action on one BMC.
+type powerResult struct {
+	Host   string
+	Via    string   // redfish or ipmi
+	States []string // status only: "<system>=<PowerState>"
+	Err    error
+}
+
+func (r powerResult) print(action string) {
+	switch {
+	case r.Err != nil:
+		fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", hostName(r.Host), r.Err)
+	case action == "status":
+		fmt.Printf("%s: %s (%s)\n", hostName(r.Host), strings.Join(r.States, ", "), r.Via)
+	default:
+		fmt.Printf("%s: power %s requested (%s)\n", hostName(r.Host), action, r.Via)
+	}
+}
+
+// powerHost runs action on every system of the BMC at host over Redfish, or over
+// IPMI when the Redfish probe fails and --allow-ipmi-fallback is set.
+func powerHost(ctx context.Context, host, action, user, pass string) powerResult {
+	if pwTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, pwTimeout)
+		defer cancel()
+	}
+	r := powerResult{Host: host, Via: "redfish"}
+	rf := newRedfishClient(host, user, pass, pwInsecure, pwTimeout)
+	systems, err := rf.GetSystems(ctx)
+	if err == nil && len(systems) == 0 {
+		err = errors.New("no systems found")
+	}
+	if err != nil {
+		if !pwIPMIFallback || ctx.Err() != nil {
+			r.Err = fmt.Errorf("redfish: %w", err)
+			return r
+		}
+		fmt.Fprintf(os.Stderr, "WARN: %s: Redfish probe failed (%v); falling back to IPMI\n", hostName(host), err)
+		return powerViaIPMI(ctx, host, action, user, pass)
+	}
+	if action == "status" {
+		for _, s := range systems {
+			r.States = append(r.States, fmt.Sprintf("%s=%s", s.ID, s.PowerState))
+		}
+		sort.Strings(r.States)
+		return r
+	}
+	if err := rf.ResetSystem(ctx, "", powerResetTypes[action]); err != nil {
+		r.Err = fmt.Errorf("redfish: %w", err)
+	}
+	return r
+}
+
+func powerViaIPMI(ctx context.Context, host, action, user, pass string) powerResult {
+	r := powerResult{Host: host, Via: "ipmi"}
+	u, p := ipmiCreds(user, pass)
+	c := newIPMIClient(host, u, p, pwTimeout)
+	if action == "status" {
+		st, err := c.PowerStatus(ctx)
+		r.States, r.Err = []string{"chassis=" + st}, err
+		return r
+	}
+	r.Err = c.Power(ctx, action)
+	return r
+}
+
+// ipmiCreds returns IPMI_USER/IPMI_PASSWORD (from --credentials) when set, else
+// the given Redfish credentials.
+func ipmiCreds(user, pass string) (string, string) {
+	p, err := secret("IPMI_PASSWORD")
+	if err != nil {
+		diag.Logf("IPMI password: %v", err)
+	}
+	if u := os.Getenv("IPMI_USER"); u != "" && p != "" {
+		return u, p
+	}
+	return user, pass
+}
+
+func init() {
+	rootCmd.AddCommand(powerCmd)
+	powerCmd.Flags().StringVarP(&pwFile, "file", "f", "", "Inventory file to read bmcs[] from when --hosts is not provided")
+	powerCmd.Flags().StringVar(&pwHostsCSV, "hosts", "", "Comma-separated list of BMC hosts to target (overrides --file)")
+	powerCmd.Flags().StringVar(&pwOrder, "order", topology.OrderInventory, orderUsage)
+	powerCmd.Flags().StringVar(&pwMaxFailures, "max-failures", "", maxFailuresUsage)
+	powerCmd.Flags().StringVar(&pwHostsFile, "hosts-file", "", "file listing BMC hosts or bmcs[] xnames to target, one per line; '#' starts a comment (overrides --file)")
+	powerCmd.Flags().BoolVar(&pwInsecure, "insecure", true, "allow insecure TLS to BMCs")
+	powerCmd.Flags().DurationVar(&pwTimeout, "timeout", 30*time.Second, "per-BMC timeout")
+	powerCmd.Flags().IntVar(&pwBatchSize, "batch-size", 10, "number of BMCs to act on concurrently (0 or 1 = serial)")
+	powerCmd.Flags().BoolVar(&pwDryRun, "dry-run", false, "plan only: print which hosts would be powered on/off and exit")
+	powerCmd.Flags().BoolVar(&pwIPMIFallback, "allow-ipmi-fallback", false, "use ipmitool (IPMI-over-LAN) for BMCs whose Redfish service cannot be probed")
+	powerCmd.Flags().StringVar(&pwFailedOut, "failed-hosts-out", "", "write the hosts that failed, with the reason, to this file (usable as --hosts-file)")
+}
@@ -0,0 +1,114 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"bootstrap/internal/ipmi"
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+)
+
+func TestPowerIPMIFallback(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	var resetType string
+	good := &redfishtest.MockClient{
+		GetSystemsFunc: func(context.Context) ([]redfish.System, error) {
+			var s redfish.System
+			s.ID = "Node0"
+			return []redfish.System{s}, nil
+		},
+		ResetSystemFunc: func(_ context.Context, _, rt string) error {
+			resetType = rt
+			return nil
+		},
+	}
+	broken := &redfishtest.MockClient{
+		GetSystemsFunc: func(context.Context) ([]redfish.System, error) {
+			return nil, errors.New("404 Not Found")
+		},
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"a": good, "b": broken})
+	var ipmiCalls []string
+	oldIPMI := newIPMIClient
+	newIPMIClient = func(host, user, pass string, timeout time.Duration) *ipmi.Client {
+		return &ipmi.Client{Host: host, User: user, Pass: pass, Run: func(_ context.Context, args, _ []string) ([]byte, error) {
+			ipmiCalls = append(ipmiCalls, host+" "+strings.Join(args[len(args)-3:], " "))
+			return nil, nil
+		}}
+	}
+	defer func() { newIPMIClient = oldIPMI }()
+
+	pwHostsCSV, pwBatchSize = "a,b", 1
+	defer func() { pwHostsCSV, pwIPMIFallback = "", false }()
+	cmd := powerCmd
+	cmd.SetContext(context.Background())
+
+	// Without the fallback the broken BMC fails
+	if got := exitCode(cmd.RunE(cmd, []string{"cycle"})); got != exitPartial {
+		t.Errorf("no fallback: exit code %d, want %d", got, exitPartial)
+	}
+	if resetType != "PowerCycle" || len(ipmiCalls) != 0 {
+		t.Errorf("no fallback: reset %q, ipmi %v", resetType, ipmiCalls)
+	}
+
+	pwIPMIFallback = true
+	if err := cmd.RunE(cmd, []string{"cycle"}); err != nil {
+		t.Fatalf("fallback: %v", err)
+	}
+	if len(ipmiCalls) != 1 || ipmiCalls[0] != "b chassis power cycle" {
+		t.Errorf("ipmi calls = %v", ipmiCalls)
+	}
+
+	if err := cmd.RunE(cmd, []string{"explode"}); exitCode(err) != exitInvalid {
+		t.Errorf("unknown action: %v", err)
+	}
+}
+
+func TestPowerMaxFailures(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	bmc := func(err error) *redfishtest.MockClient {
+		return &redfishtest.MockClient{
+			GetSystemsFunc: func(context.Context) ([]redfish.System, error) {
+				var s redfish.System
+				s.ID = "Node0"
+				return []redfish.System{s}, nil
+			},
+			ResetSystemFunc: func(context.Context, string, string) error { return err },
+		}
+	}
+	mocks := map[string]*redfishtest.MockClient{"a": bmc(nil), "b": bmc(errors.New("reset refused")), "c": bmc(nil)}
+	useMockClients(t, mocks)
+	pwHostsCSV, pwBatchSize, pwMaxFailures = "b,a,c", 1, "0%"
+	defer func() { pwHostsCSV, pwMaxFailures = "", "" }()
+	cmd := powerCmd
+	cmd.SetContext(context.Background())
+	if got := exitCode(cmd.RunE(cmd, []string{"on"})); got != exitInvalid {
+		t.Errorf("--max-failures 0%%: exit code %d, want %d", got, exitInvalid)
+	}
+
+	pwMaxFailures = "1"
+	if err := cmd.RunE(cmd, []string{"on"}); exitCode(err) != exitPartial || strings.Contains(err.Error(), "max-failures") {
+		t.Errorf("one failure within the budget: %v", err)
+	}
+	for _, m := range mocks {
+		m.Calls = nil
+	}
+	mocks["a"].ResetSystemFunc = func(context.Context, string, string) error { return errors.New("reset refused") }
+	err := cmd.RunE(cmd, []string{"on"})
+	if exitCode(err) != exitPartial || !strings.Contains(err.Error(), "1 not started") {
+		t.Errorf("budget exceeded: %v", err)
+	}
+	if len(mocks["c"].Calls) != 0 {
+		t.Errorf("c was started: %v", mocks["c"].Calls)
+	}
+}
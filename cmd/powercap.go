@@ -0,0 +1,476 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"bootstrap/internal/powercap"
+	"bootstrap/internal/xname"
+	"bootstrap/pkg/inventory"
+	"bootstrap/pkg/redfish"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	pcFile      string
+	pcHostsCSV  string
+	pcHostsFile string
+	pcPolicy    string
+	pcInsecure  bool
+	pcTimeout   time.Duration
+	pcBatchSize int
+	pcDryRun    bool
+	pcFormat    string
+)
+
+// capTarget is a node or chassis on one BMC. Node is the position of the
+// node's system on the BMC, -1 for the chassis enclosure; All selects every
+// chassis of the BMC with a power limit.
+type capTarget struct {
+	Name  string
+	Host  string
+	Node  int
+	All   bool
+	Watts float64 // apply only
+}
+
+// capRow is one power limit in the powercap show report.
+type capRow struct {
+	Target   string   `json:"target"`
+	Host     string   `json:"host"`
+	Chassis  string   `json:"chassis"`
+	Limit    *float64 `json:"limit_watts"`
+	Min      *float64 `json:"min_watts,omitempty"`
+	Max      *float64 `json:"max_watts,omitempty"`
+	Consumed *float64 `json:"consumed_watts,omitempty"`
+	Capacity *float64 `json:"capacity_watts,omitempty"`
+}
+
+var powercapCmd = &cobra.Command{
+	Use:   "powercap",
+	Short: "Report and set node and chassis power caps",
+	Long: `Read and set the Redfish power limits of nodes and chassis, for bring-up
+when facility power is constrained. A node is capped through the chassis of its
+system on the node's BMC; a chassis (x1000c0) through the Enclosure of its
+chassis controller, reached at the bmcs[] IP of x1000c0b0 in --file or at the
+xname itself. The PowerLimitWatts of the chassis' EnvironmentMetrics is used,
+or PowerControl of the older Power resource on BMCs without it.`,
+}
+
+var powercapShowCmd = &cobra.Command{
+	Use:   "show [xname|host]...",
+	Short: "Report the current power caps and consumption",
+	Long: `Print the power limit, the allowed range, the present consumption and the
+capacity of node and chassis xnames, aliases or BMC hosts. Without arguments
+every chassis with a power limit on the BMCs of --hosts, --hosts-file or --file
+is reported.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if pcFormat != "" && pcFormat != "json" && pcFormat != "csv" {
+			return invalidf("--format must be json or csv when set")
+		}
+		targets, err := capTargets(args)
+		if err != nil {
+			return err
+		}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+		byHost, hosts := groupCapTargets(targets)
+		doc, err := loadInventory(pcFile)
+		if err != nil {
+			return err
+		}
+		bmcXnames := hostXnames(hosts, doc.BMCs)
+
+		ctx := cmd.Context()
+		var mu sync.Mutex
+		var rows []capRow
+		failed := map[string]error{}
+		forEachHost(ctx, hosts, pcBatchSize, func(ctx context.Context, h string) {
+			hostRows, err := showHostCaps(ctx, h, bmcXnames[h], byHost[h], user, pass)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", hostName(h), err)
+				failed[h] = err
+				return
+			}
+			rows = append(rows, hostRows...)
+		}, func(string) {})
+		hostIdx := map[string]int{}
+		for i, h := range hosts {
+			hostIdx[h] = i
+		}
+		slices.SortStableFunc(rows, func(a, b capRow) int {
+			return cmp.Or(cmp.Compare(hostIdx[a.Host], hostIdx[b.Host]), cmp.Compare(a.Target, b.Target))
+		})
+		if err := printCaps(rows); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+		return hostFailures(len(hosts), failed)
+	},
+}
+
+var powercapApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Set the power caps of a policy file on the nodes and chassis of the inventory",
+	Long: `Cap the nodes[] of --file, and the chassis they and the bmcs[] are in, as the
+--policy file says. The policy is an ordered list of rules; the last rule whose
+match (an xname glob or a node alias) fits a node or chassis gives its limit in
+watts, 0 removing the cap:
+
+  caps:
+    - match: "x1000c*s*b*n*"
+      watts: 550
+    - match: x1000c0
+      watts: 60000
+
+Caps already at the wanted value are left alone, and a limit outside the range
+the BMC allows is refused. Rules that match nothing are warned about.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if pcPolicy == "" {
+			return invalidf("--policy is required")
+		}
+		if pcFile == "" {
+			return invalidf("--file is required")
+		}
+		pol, err := powercap.Load(pcPolicy)
+		if err != nil {
+			return invalidf("--policy: %w", err)
+		}
+		doc, err := readInventory(pcFile)
+		if err != nil {
+			return err
+		}
+		hostNames = aliasNames(doc)
+		want, unused := pol.Targets(doc)
+		for _, m := range unused {
+			fmt.Fprintf(os.Stderr, "WARN: policy rule %q matches no node or chassis of %s\n", m, pcFile)
+		}
+		if len(want) == 0 {
+			return invalidf("%s caps no node or chassis of %s", pcPolicy, pcFile)
+		}
+		var targets []capTarget
+		for _, w := range want {
+			t := xnameCapTarget(w.Xname, doc.BMCs)
+			t.Watts = w.Watts
+			targets = append(targets, t)
+		}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+		byHost, hosts := groupCapTargets(targets)
+		if !pcDryRun {
+			resolvedBMCs = doc.BMCs
+			if stop, err := needsApproval(cmd, args, "powercap apply", hosts); stop || err != nil {
+				return err
+			}
+		}
+
+		ctx := cmd.Context()
+		launch, stopLaunch := ctx, context.CancelFunc(func() {})
+		if !pcDryRun {
+			launch, stopLaunch = untilWindowCloses(ctx)
+		}
+		defer stopLaunch()
+		var mu sync.Mutex
+		failed := map[string]error{}
+		counts := map[string]int{}
+		var aborted, notStarted []string
+		forEachHost(launch, hosts, pcBatchSize, func(_ context.Context, h string) {
+			lines, changed, err := applyHostCaps(ctx, h, byHost[h], user, pass)
+			mu.Lock()
+			defer mu.Unlock()
+			for _, l := range lines {
+				fmt.Println(l)
+			}
+			counts["set"] += changed
+			counts["unchanged"] += len(lines) - changed
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", hostName(h), err)
+				failed[h] = err
+				if errors.Is(err, context.Canceled) {
+					aborted = append(aborted, h)
+				}
+			}
+		}, func(h string) {
+			mu.Lock()
+			defer mu.Unlock()
+			notStarted = append(notStarted, h)
+		})
+		verb := "set"
+		if pcDryRun {
+			verb = "to set"
+		}
+		fmt.Println("Power cap summary:")
+		fmt.Printf("  %s: %d\n", verb, counts["set"])
+		fmt.Printf("  unchanged: %d\n", counts["unchanged"])
+		fmt.Printf("  errors: %d\n", len(failed))
+		if ctx.Err() != nil {
+			aborted = append(aborted, notStarted...)
+			printInterrupted(len(hosts)-len(aborted), aborted)
+			return errInterrupted
+		}
+		if stopped := launchStopped(ctx, launch); stopped != nil {
+			return reportStopped(stopped, hosts, notStarted, failed)
+		}
+		return hostFailures(len(hosts), failed)
+	},
+}
+
+// hostPowerLimits reads the power limits of a BMC and, when a node is
+// targeted or named, the paths of its systems in order.
+func hostPowerLimits(ctx context.Context, rf redfish.Client, targets []capTarget) ([]redfish.PowerLimit, []string, error) {
+	limits, err := rf.GetPowerLimits(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	linked := slices.ContainsFunc(limits, func(l redfish.PowerLimit) bool { return len(l.Systems) > 0 })
+	if !slices.ContainsFunc(targets, func(t capTarget) bool { return t.Node >= 0 || (t.All && linked) }) {
+		return limits, nil, nil
+	}
+	systems, err := rf.GetSystems(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read systems: %w", err)
+	}
+	paths := make([]string, len(systems))
+	for i, s := range systems {
+		paths[i] = s.ODataID
+	}
+	return limits, paths, nil
+}
+
+// selectLimit returns the limit t is capped through.
+func selectLimit(t capTarget, limits []redfish.PowerLimit, systems []string) (redfish.PowerLimit, error) {
+	if t.Node < 0 {
+		return powercap.Select(limits, "")
+	}
+	if t.Node >= len(systems) {
+		return redfish.PowerLimit{}, fmt.Errorf("node %d not found (%d system(s))", t.Node, len(systems))
+	}
+	return powercap.Select(limits, systems[t.Node])
+}
+
+func showHostCaps(ctx context.Context, h, bmcX string, targets []capTarget, user, pass string) ([]capRow, error) {
+	if pcTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, pcTimeout)
+		defer cancel()
+	}
+	limits, systems, err := hostPowerLimits(ctx, newRedfishClient(h, user, pass, pcInsecure, pcTimeout), targets)
+	if err != nil {
+		return nil, err
+	}
+	row := func(name string, l redfish.PowerLimit) capRow {
+		return capRow{Target: name, Host: h, Chassis: l.ChassisID, Limit: l.Limit, Min: l.Min, Max: l.Max, Consumed: l.Consumed, Capacity: l.Capacity}
+	}
+	var rows []capRow
+	for _, t := range targets {
+		if !t.All {
+			l, err := selectLimit(t, limits, systems)
+			if err != nil {
+				return rows, fmt.Errorf("%s: %w", t.Name, err)
+			}
+			rows = append(rows, row(t.Name, l))
+			continue
+		}
+		for _, l := range limits {
+			rows = append(rows, row(limitName(h, bmcX, l, systems), l))
+		}
+	}
+	return rows, nil
+}
+
+// limitName names the node or chassis a limit of host caps: the node xname
+// when the chassis holds one system, the chassis xname for the enclosure of a
+// chassis controller, otherwise the host.
+func limitName(h, bmcX string, l redfish.PowerLimit, systems []string) string {
+	if len(l.Systems) == 1 {
+		if i := slices.Index(systems, l.Systems[0]); i >= 0 {
+			if x, err := xname.BMCXnameToNodeN(bmcX, redfish.NodeNumbers(systems)[i]); err == nil {
+				return x
+			}
+		}
+	}
+	if c := xname.Chassis(bmcX); c != "" && bmcX == c+"b0" && l.ChassisType == "Enclosure" {
+		return c
+	}
+	return hostName(h)
+}
+
+// applyHostCaps sets the caps of the targets on one BMC. It returns a line per
+// target handled and how many caps it set (or, with --dry-run, would set).
+func applyHostCaps(ctx context.Context, h string, targets []capTarget, user, pass string) ([]string, int, error) {
+	if pcTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, pcTimeout)
+		defer cancel()
+	}
+	rf := newRedfishClient(h, user, pass, pcInsecure, pcTimeout)
+	limits, systems, err := hostPowerLimits(ctx, rf, targets)
+	if err != nil {
+		return nil, 0, err
+	}
+	var lines []string
+	changed := 0
+	for _, t := range targets {
+		l, err := selectLimit(t, limits, systems)
+		if err != nil {
+			return lines, changed, fmt.Errorf("%s: %w", t.Name, err)
+		}
+		now := "no cap"
+		if l.Limit != nil {
+			now = fmt.Sprintf("%g W", *l.Limit)
+		}
+		if (t.Watts == 0 && l.Limit == nil) || (l.Limit != nil && *l.Limit == t.Watts) {
+			lines = append(lines, fmt.Sprintf("%s: %s, as wanted", t.Name, now))
+			continue
+		}
+		what, done := fmt.Sprintf("cap at %g W", t.Watts), fmt.Sprintf("capped at %g W", t.Watts)
+		if t.Watts == 0 {
+			what, done = "remove the cap", "cap removed"
+		}
+		if pcDryRun {
+			lines = append(lines, fmt.Sprintf("[dry-run] %s: would %s (now %s)", t.Name, what, now))
+			changed++
+			continue
+		}
+		if err := rf.SetPowerLimit(ctx, l.ChassisPath, t.Watts); err != nil {
+			return lines, changed, fmt.Errorf("%s: %w", t.Name, err)
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s (was %s)", t.Name, done, now))
+		changed++
+	}
+	return lines, changed, nil
+}
+
+// capTargets turns the command arguments (or, without any, the host flags)
+// into targets.
+func capTargets(args []string) ([]capTarget, error) {
+	if len(args) == 0 {
+		if pcFile == "" && pcHostsCSV == "" && pcHostsFile == "" {
+			return nil, invalidf("give xnames or hosts, or one of --file, --hosts or --hosts-file")
+		}
+		hosts, err := resolveHosts(pcFile, pcHostsCSV, pcHostsFile)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]capTarget, 0, len(hosts))
+		for _, h := range hosts {
+			out = append(out, capTarget{Name: hostName(h), Host: h, Node: -1, All: true})
+		}
+		return out, nil
+	}
+	doc, err := loadInventory(pcFile)
+	if err != nil {
+		return nil, err
+	}
+	hostNames = aliasNames(doc)
+	aliases := aliasXnames(doc)
+	var out []capTarget
+	for _, a := range args {
+		if x, ok := aliases[strings.ToLower(a)]; ok {
+			a = x
+		}
+		out = append(out, xnameCapTarget(a, doc.BMCs))
+	}
+	return out, nil
+}
+
+// xnameCapTarget returns the target of a node or chassis xname; anything else
+// is taken as a BMC host and selects all of its chassis.
+func xnameCapTarget(x string, bmcs []inventory.Entry) capTarget {
+	lx := strings.ToLower(x)
+	switch {
+	case nodeXname.MatchString(lx):
+		return capTarget{Name: lx, Host: canonicalHost(bmcAddr(xname.NodeToBMCXname(lx), bmcs, nil)), Node: xname.NodeNumber(lx)}
+	case xname.Chassis(lx) == lx && lx != "":
+		return capTarget{Name: lx, Host: canonicalHost(bmcAddr(lx+"b0", bmcs, nil)), Node: -1}
+	}
+	h := canonicalHost(bmcAddr(x, bmcs, nil))
+	return capTarget{Name: hostName(h), Host: h, Node: -1, All: true}
+}
+
+// groupCapTargets groups targets by host, keeping the order hosts first appear in.
+func groupCapTargets(targets []capTarget) (map[string][]capTarget, []string) {
+	byHost := map[string][]capTarget{}
+	var hosts []string
+	for _, t := range targets {
+		if _, ok := byHost[t.Host]; !ok {
+			hosts = append(hosts, t.Host)
+		}
+		byHost[t.Host] = append(byHost[t.Host], t)
+	}
+	return byHost, hosts
+}
+
+func wattsText(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%g", *v)
+}
+
+func printCaps(rows []capRow) error {
+	switch pcFormat {
+	case "json":
+		return printJSON(rows)
+	case "csv":
+		var records [][]string
+		for _, r := range rows {
+			records = append(records, []string{r.Target, r.Host, r.Chassis, wattsText(r.Limit), wattsText(r.Min), wattsText(r.Max), wattsText(r.Consumed), wattsText(r.Capacity)})
+		}
+		return writeCSV(os.Stdout, []string{"target", "host", "chassis", "limit_watts", "min_watts", "max_watts", "consumed_watts", "capacity_watts"}, records)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TARGET\tCHASSIS\tLIMIT\tALLOWED\tCONSUMED\tCAPACITY")
+	for _, r := range rows {
+		limit := "none"
+		if r.Limit != nil {
+			limit = wattsText(r.Limit) + " W"
+		}
+		allowed := redfish.PowerLimit{Min: r.Min, Max: r.Max}.Range()
+		consumed, capacity := "-", "-"
+		if r.Consumed != nil {
+			consumed = wattsText(r.Consumed) + " W"
+		}
+		if r.Capacity != nil {
+			capacity = wattsText(r.Capacity) + " W"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Target, r.Chassis, limit, allowed, consumed, capacity)
+	}
+	return tw.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(powercapCmd)
+	powercapCmd.AddCommand(powercapShowCmd, powercapApplyCmd)
+	powercapCmd.PersistentFlags().StringVarP(&pcFile, "file", "f", "", "inventory whose bmcs[] are read (also resolves xname and alias arguments)")
+	powercapCmd.PersistentFlags().BoolVar(&pcInsecure, "insecure", true, "allow insecure TLS to BMCs")
+	powercapCmd.PersistentFlags().DurationVar(&pcTimeout, "timeout", 30*time.Second, "per-BMC timeout")
+	powercapCmd.PersistentFlags().IntVar(&pcBatchSize, "batch-size", 10, "number of BMCs to contact concurrently (0 or 1 = serial)")
+	powercapShowCmd.Flags().StringVar(&pcHostsCSV, "hosts", "", "comma-separated BMC hosts to report when no arguments are given")
+	powercapShowCmd.Flags().StringVar(&pcHostsFile, "hosts-file", "", "file listing BMC hosts or bmcs[] xnames to report when no arguments are given")
+	powercapShowCmd.Flags().StringVar(&pcFormat, "format", "", "output format: json or csv (default: table)")
+	powercapApplyCmd.Flags().StringVar(&pcPolicy, "policy", "", "power cap policy file (YAML)")
+	powercapApplyCmd.Flags().BoolVar(&pcDryRun, "dry-run", false, "plan only: print the caps that would change without writing to the BMCs")
+}
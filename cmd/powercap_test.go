@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+)
+
+func TestPowercap(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	dir := t.TempDir()
+	inv := filepath.Join(dir, "inventory.yaml")
+	if err := os.WriteFile(inv, []byte(`bmcs:
+  - xname: x1000c0s0b0
+    ip: 10.1.0.10
+  - xname: x1000c0b0
+    ip: 10.1.0.100
+nodes:
+  - xname: x1000c0s0b0n0
+    alias: nid000001
+  - xname: x1000c0s0b0n1
+    alias: nid000002
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	policy := filepath.Join(dir, "powercap.yaml")
+	if err := os.WriteFile(policy, []byte(`caps:
+  - match: "x1000c0s*b*n*"
+    watts: 600
+  - match: nid000002
+    watts: 450
+  - match: x1000c0
+    watts: 60000
+  - match: x2000c0
+    watts: 1
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	w := func(v float64) *float64 { return &v }
+	var sets []string
+	node := &redfishtest.MockClient{
+		GetSystemsFunc: func(context.Context) ([]redfish.System, error) {
+			return []redfish.System{
+				{Resource: redfish.Resource{ODataID: "/redfish/v1/Systems/Node0"}},
+				{Resource: redfish.Resource{ODataID: "/redfish/v1/Systems/Node1"}},
+			}, nil
+		},
+		GetPowerLimitsFunc: func(context.Context) ([]redfish.PowerLimit, error) {
+			return []redfish.PowerLimit{
+				{ChassisPath: "/redfish/v1/Chassis/Node0", ChassisID: "Node0", Systems: []string{"/redfish/v1/Systems/Node0"}, Limit: w(600), Consumed: w(412)},
+				{ChassisPath: "/redfish/v1/Chassis/Node1", ChassisID: "Node1", Systems: []string{"/redfish/v1/Systems/Node1"}, Min: w(300), Max: w(900)},
+			}, nil
+		},
+		SetPowerLimitFunc: func(_ context.Context, path string, watts float64) error {
+			sets = append(sets, path+" "+wattsText(&watts))
+			return nil
+		},
+	}
+	cmm := &redfishtest.MockClient{
+		GetPowerLimitsFunc: func(context.Context) ([]redfish.PowerLimit, error) {
+			return []redfish.PowerLimit{{ChassisPath: "/redfish/v1/Chassis/Enclosure", ChassisID: "Enclosure", ChassisType: "Enclosure", Capacity: w(96000)}}, nil
+		},
+		SetPowerLimitFunc: func(_ context.Context, path string, watts float64) error {
+			sets = append(sets, path+" "+wattsText(&watts))
+			return nil
+		},
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"10.1.0.10": node, "10.1.0.100": cmm})
+	defer func() { pcFile, pcPolicy, pcDryRun, pcFormat = "", "", false, "" }()
+	pcFile, pcPolicy, pcBatchSize = inv, policy, 1
+	ctx := context.Background()
+
+	run := func(c func() error) (string, error) {
+		old := os.Stdout
+		r, wr, _ := os.Pipe()
+		os.Stdout = wr
+		err := c()
+		wr.Close() //nolint:errcheck
+		os.Stdout = old
+		b, _ := io.ReadAll(r)
+		return string(b), err
+	}
+
+	// Dry run: node 0 is already at 600 W; the rest would change
+	pcDryRun = true
+	powercapApplyCmd.SetContext(ctx)
+	out, err := run(func() error { return powercapApplyCmd.RunE(powercapApplyCmd, nil) })
+	if err != nil {
+		t.Fatalf("apply --dry-run: %v", err)
+	}
+	for _, want := range []string{
+		"x1000c0s0b0n0: 600 W, as wanted",
+		"[dry-run] x1000c0s0b0n1: would cap at 450 W (now no cap)",
+		"[dry-run] x1000c0: would cap at 60000 W (now no cap)",
+		"to set: 2",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dry-run output lacks %q:\n%s", want, out)
+		}
+	}
+	if len(sets) != 0 {
+		t.Errorf("dry run set %v", sets)
+	}
+
+	pcDryRun = false
+	if code := unconfirmed(func() error {
+		_, err := run(func() error { return powercapApplyCmd.RunE(powercapApplyCmd, nil) })
+		return err
+	}); code != exitInvalid || len(sets) != 0 {
+		t.Errorf("unconfirmed apply: exit code %d, sets %v", code, sets)
+	}
+	if _, err := run(func() error { return powercapApplyCmd.RunE(powercapApplyCmd, nil) }); err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if want := []string{"/redfish/v1/Chassis/Node1 450", "/redfish/v1/Chassis/Enclosure 60000"}; !reflect.DeepEqual(sets, want) {
+		t.Errorf("sets = %v, want %v", sets, want)
+	}
+
+	// An interrupted run names the BMCs whose caps it did not set
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	powercapApplyCmd.SetContext(cancelled)
+	oldStderr := os.Stderr
+	r, wr, _ := os.Pipe()
+	os.Stderr = wr
+	_, err = run(func() error { return powercapApplyCmd.RunE(powercapApplyCmd, nil) })
+	wr.Close() //nolint:errcheck
+	os.Stderr = oldStderr
+	stderr, _ := io.ReadAll(r)
+	if !errors.Is(err, errInterrupted) || !strings.Contains(string(stderr), "0 host(s) completed, 2 aborted") ||
+		!strings.Contains(string(stderr), "Aborted: 10.1.0.10, 10.1.0.100") {
+		t.Errorf("interrupted apply: %v\n%s", err, stderr)
+	}
+
+	powercapShowCmd.SetContext(ctx)
+	pcFormat = "csv"
+	out, err = run(func() error { return powercapShowCmd.RunE(powercapShowCmd, []string{"nid000002", "x1000c0"}) })
+	if err != nil {
+		t.Fatalf("show: %v", err)
+	}
+	want := "target,host,chassis,limit_watts,min_watts,max_watts,consumed_watts,capacity_watts\n" +
+		"x1000c0s0b0n1,10.1.0.10,Node1,,300,900,,\n" +
+		"x1000c0,10.1.0.100,Enclosure,,,,,96000\n"
+	if out != want {
+		t.Errorf("show output = %q, want %q", out, want)
+	}
+
+	// Without arguments every limit of every BMC is named after its node or chassis
+	pcFormat = ""
+	out, err = run(func() error { return powercapShowCmd.RunE(powercapShowCmd, nil) })
+	if err != nil {
+		t.Fatalf("show all: %v", err)
+	}
+	for _, want := range []string{"x1000c0s0b0n0  Node0", "600 W", "412 W", "x1000c0s0b0n1  Node1", "300-900 W", "x1000c0        Enclosure"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("show all output lacks %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"bootstrap/internal/progress"
+)
+
+// progressFD is the hidden --progress-fd flag: serve passes a pipe to the
+// commands it runs so it can stream their per-host progress.
+var progressFD int
+
+// progressOut receives progress events; nil unless --progress-fd is set.
+var progressOut io.Writer
+
+func configureProgress() error {
+	progressOut = nil
+	if progressFD <= 0 {
+		return nil
+	}
+	f := os.NewFile(uintptr(progressFD), "progress")
+	if f == nil {
+		return invalidf("--progress-fd %d: not an open file", progressFD)
+	}
+	progressOut = f
+	return nil
+}
+
+// newProgress returns the reporter for a run over total hosts (nil, which
+// discards events, without --progress-fd).
+func newProgress(total int) *progress.Reporter {
+	return progress.New(progressOut, total)
+}
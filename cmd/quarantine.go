@@ -0,0 +1,271 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"bootstrap/internal/quarantine"
+	"bootstrap/pkg/redfish"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	quarantineFile     string
+	quarantineAfter    int
+	includeQuarantined bool
+	qrReason           string
+	qrFormat           string
+)
+
+// quarantineList is the --quarantine file as read when the command started;
+// nil without --quarantine.
+var quarantineList *quarantine.List
+
+// quarantineRun maps the hosts a command resolved (canonical address and
+// xname) to their key in the quarantine file; quarantineFailed holds the
+// failures hostFailures saw and quarantineAnswered the hosts that answered a
+// Redfish request with anything but an authentication error.
+var (
+	quarantineRun      map[string]string
+	quarantineFailed   map[string]error
+	quarantineMu       sync.Mutex
+	quarantineAnswered map[string]bool
+	stopObserving      = func() {}
+)
+
+func configureQuarantine() error {
+	stopObserving()
+	stopObserving = func() {}
+	quarantineList, quarantineRun, quarantineFailed, quarantineAnswered = nil, nil, nil, map[string]bool{}
+	path := quarantinePath()
+	if path == "" {
+		return nil
+	}
+	l, err := quarantine.Load(path)
+	if err != nil {
+		return invalidf("--quarantine: %w", err)
+	}
+	quarantineList = l
+	stopObserving = redfish.AddRequestObserver(func(host string, _ time.Duration, status int, _ error) {
+		if status != 0 && status != http.StatusUnauthorized && status != http.StatusForbidden {
+			quarantineMu.Lock()
+			quarantineAnswered[canonicalHost(host)] = true
+			quarantineMu.Unlock()
+		}
+	})
+	return nil
+}
+
+func quarantinePath() string {
+	return cmpOrEnv(quarantineFile, "BOOTSTRAP_QUARANTINE")
+}
+
+// skipQuarantined drops the quarantined hosts from hosts, unless
+// --include-quarantined, and remembers the others so the outcome of the run is
+// recorded for them. xnames maps hosts to their BMC xname where known.
+func skipQuarantined(hosts []string, xnames map[string]string) ([]string, error) {
+	if quarantineList == nil {
+		return hosts, nil
+	}
+	if quarantineRun == nil {
+		quarantineRun = map[string]string{}
+	}
+	var keep, skipped []string
+	for _, h := range hosts {
+		key := strings.ToLower(cmp.Or(xnames[h], canonicalHost(h)))
+		e, ok := quarantineList.Get(key)
+		if !ok {
+			e, ok = quarantineList.Get(canonicalHost(h))
+		}
+		if ok && e.Quarantined() && !includeQuarantined {
+			skipped = append(skipped, hostName(h))
+			continue
+		}
+		quarantineRun[canonicalHost(h)], quarantineRun[key] = key, key
+		keep = append(keep, h)
+	}
+	if len(skipped) == 0 {
+		return keep, nil
+	}
+	if len(keep) == 0 {
+		return nil, invalidf("all %d host(s) are quarantined in %s; use --include-quarantined or 'quarantine remove'", len(hosts), quarantinePath())
+	}
+	fmt.Fprintf(os.Stderr, "WARN: skipping %d quarantined host(s): %s (see 'quarantine list'; --include-quarantined to run them)\n", len(skipped), strings.Join(skipped, ", "))
+	return keep, nil
+}
+
+// noteHostFailures remembers the host failures of the run for recordQuarantine.
+func noteHostFailures(errs map[string]error) {
+	if quarantineRun == nil {
+		return
+	}
+	if quarantineFailed == nil {
+		quarantineFailed = map[string]error{}
+	}
+	for h, err := range errs {
+		if key, ok := quarantineRun[canonicalHost(h)]; ok {
+			quarantineFailed[key] = err
+		}
+	}
+}
+
+// recordQuarantine updates the quarantine file with the outcome of the run:
+// hosts that failed because they rejected the credentials or did not answer
+// count a failed run and are quarantined after --quarantine-after of them in a
+// row; hosts that answered without failing are released. Hosts the run never
+// contacted, as in a dry run, keep their entry.
+func recordQuarantine() {
+	if quarantineList == nil || len(quarantineRun) == 0 {
+		return
+	}
+	quarantineMu.Lock()
+	answered := map[string]bool{}
+	for h, key := range quarantineRun {
+		if quarantineAnswered[h] {
+			answered[key] = true
+		}
+	}
+	quarantineMu.Unlock()
+	now := time.Now()
+	err := quarantine.Update(quarantinePath(), func(l *quarantine.List) error {
+		for _, key := range slices.Compact(slices.Sorted(maps.Values(quarantineRun))) {
+			herr, failed := quarantineFailed[key]
+			switch {
+			case failed && classifyHostError(herr) != exitPartial:
+				reason := strings.Join(strings.Fields(herr.Error()), " ")
+				if l.Fail(key, reason, quarantineAfter, now) {
+					fmt.Fprintf(os.Stderr, "WARN: %s quarantined after %d failed run(s) in a row: %s\n", key, quarantineAfter, reason)
+				}
+			case !failed && answered[key]:
+				l.Succeed(key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARN: --quarantine: %v\n", err)
+	}
+}
+
+var quarantineCmd = &cobra.Command{
+	Use:   "quarantine",
+	Short: "List, add and remove quarantined BMCs",
+	Long: `Manage the quarantine file (--quarantine, default $BOOTSTRAP_QUARANTINE).
+
+Commands skip the hosts it lists unless --include-quarantined. A host is added
+after --quarantine-after runs in a row in which it rejected the credentials or
+did not answer, and released by the next run that reaches it. Hosts added with
+'quarantine add' stay until 'quarantine remove'.`,
+}
+
+// quarantineFilePath returns the quarantine file the quarantine subcommands manage.
+func quarantineFilePath() (string, error) {
+	path := quarantinePath()
+	if path == "" {
+		return "", invalidf("set --quarantine or $BOOTSTRAP_QUARANTINE")
+	}
+	return path, nil
+}
+
+var quarantineListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List quarantined hosts and hosts with failed runs",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		path, err := quarantineFilePath()
+		if err != nil {
+			return err
+		}
+		l, err := quarantine.Load(path)
+		if err != nil {
+			return err
+		}
+		if strings.EqualFold(qrFormat, "json") {
+			return printJSON(l.Hosts)
+		}
+		if len(l.Hosts) == 0 {
+			fmt.Println("No quarantined hosts")
+			return nil
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "HOST\tSTATE\tFAILURES\tSINCE\tREASON")
+		for _, e := range l.Hosts {
+			state, since := "failing", "-"
+			if e.Quarantined() {
+				state, since = "quarantined", e.Since.Local().Format(time.RFC3339)
+				if e.Manual {
+					state = "quarantined (manual)"
+				}
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", e.Host, state, e.Failures, since, e.Reason)
+		}
+		return tw.Flush()
+	},
+}
+
+var quarantineAddCmd = &cobra.Command{
+	Use:   "add HOST...",
+	Short: "Quarantine hosts until they are removed",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		path, err := quarantineFilePath()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		return quarantine.Update(path, func(l *quarantine.List) error {
+			for _, h := range args {
+				l.Add(canonicalHost(h), cmp.Or(qrReason, "added by hand"), now)
+				fmt.Printf("%s: quarantined\n", canonicalHost(h))
+			}
+			return nil
+		})
+	},
+}
+
+var quarantineRemoveCmd = &cobra.Command{
+	Use:   "remove HOST...",
+	Short: "Release hosts and forget their failed runs",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		path, err := quarantineFilePath()
+		if err != nil {
+			return err
+		}
+		var missing []string
+		err = quarantine.Update(path, func(l *quarantine.List) error {
+			for _, h := range args {
+				if l.Remove(canonicalHost(h)) {
+					fmt.Printf("%s: released\n", canonicalHost(h))
+				} else {
+					missing = append(missing, h)
+				}
+			}
+			return nil
+		})
+		if err == nil && len(missing) > 0 {
+			err = invalidf("not in the quarantine file: %s", strings.Join(missing, ", "))
+		}
+		return err
+	},
+}
+
+func init() {
+	quarantineAddCmd.Flags().StringVar(&qrReason, "reason", "", "why the hosts are quarantined, shown by 'quarantine list'")
+	quarantineListCmd.Flags().StringVar(&qrFormat, "format", "", "output format: json")
+	quarantineCmd.AddCommand(quarantineListCmd, quarantineAddCmd, quarantineRemoveCmd)
+	rootCmd.AddCommand(quarantineCmd)
+}
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"bootstrap/internal/quarantine"
+	"bootstrap/pkg/redfish"
+)
+
+func TestQuarantineSkipsAndRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quarantine.yaml")
+	quarantineFile, quarantineAfter = path, 2
+	defer func() {
+		quarantineFile, quarantineAfter, includeQuarantined = "", 3, false
+		_ = configureQuarantine()
+	}()
+	l := &quarantine.List{}
+	l.Add("10.0.0.3", "crash-looping", time.Now())
+	if err := l.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	authErr := &redfish.StatusError{Method: "GET", StatusCode: http.StatusUnauthorized, Status: "401 Unauthorized"}
+	run := func() []string {
+		t.Helper()
+		if err := configureQuarantine(); err != nil {
+			t.Fatal(err)
+		}
+		hosts, err := resolveHosts("", "10.0.0.1,10.0.0.2,10.0.0.3", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 10.0.0.1 rejects the credentials, 10.0.0.2 answers but fails otherwise
+		quarantineAnswered["10.0.0.2"] = true
+		_ = hostFailures(len(hosts), map[string]error{"10.0.0.1": authErr, "10.0.0.2": errors.New("no such target")})
+		recordQuarantine()
+		return hosts
+	}
+
+	if hosts := run(); !slices.Equal(hosts, []string{"10.0.0.1", "10.0.0.2"}) {
+		t.Fatalf("hosts = %v, want the manually quarantined host skipped", hosts)
+	}
+	run()
+	got, err := quarantine.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := got.Get("10.0.0.1"); !ok || !e.Quarantined() || e.Failures != 2 {
+		t.Fatalf("10.0.0.1 = %+v, %v; want quarantined after 2 auth failures", e, ok)
+	}
+	if _, ok := got.Get("10.0.0.2"); ok {
+		t.Fatal("a host failing for other reasons was recorded")
+	}
+	if hosts := run(); !slices.Equal(hosts, []string{"10.0.0.2"}) {
+		t.Fatalf("hosts = %v, want both quarantined hosts skipped", hosts)
+	}
+
+	// --include-quarantined runs them; the one that answers is released,
+	// the one added by hand is not
+	includeQuarantined = true
+	if err := configureQuarantine(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resolveHosts("", "10.0.0.1,10.0.0.3", ""); err != nil {
+		t.Fatal(err)
+	}
+	quarantineAnswered["10.0.0.1"], quarantineAnswered["10.0.0.3"] = true, true
+	recordQuarantine()
+	if got, _ = quarantine.Load(path); len(got.Hosts) != 1 || got.Hosts[0].Host != "10.0.0.3" {
+		t.Fatalf("after --include-quarantined: %+v", got.Hosts)
+	}
+
+	includeQuarantined = false
+	if err := configureQuarantine(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resolveHosts("", "10.0.0.3", ""); exitCode(err) != exitInvalid {
+		t.Fatalf("all hosts quarantined: err = %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"bootstrap/internal/discover"
+	"bootstrap/pkg/inventory"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	rmFile      string
+	rmInsecure  bool
+	rmTimeout   time.Duration
+	rmBatchSize int
+	rmWrite     bool
+	rmFormat    string
+)
+
+// MAC check outcomes
+const (
+	macMatch    = "match"
+	macMismatch = "mismatch"
+	macUnknown  = "unknown" // the BMC reported no usable MAC
+	macError    = "error"
+)
+
+// macCheck compares the inventory MAC of one BMC with the MAC it reports.
+type macCheck struct {
+	Xname        string `json:"xname"`
+	Host         string `json:"host"`
+	InventoryMAC string `json:"inventory_mac,omitempty"`
+	BMCMAC       string `json:"bmc_mac,omitempty"`
+	Status       string `json:"status"`
+	Error        string `json:"error,omitempty"`
+	err          error
+}
+
+var reconcileMACsCmd = &cobra.Command{
+	Use:   "reconcile-macs",
+	Short: "Compare bmcs[] MACs with the MACs the BMCs report",
+	Long: `Read the MAC of every BMC in the inventory from its manager interfaces and
+report entries whose MAC differs from the file, e.g. a MAC predicted by init-bmcs
+for a slot that now holds another blade. With --write the mismatched bmcs[]
+entries are corrected. MACs assigned to more than one entry across bmcs[] and
+nodes[] are reported as conflicts; the file is not written while any remain.`,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if rmFile == "" {
+			return invalidf("--file is required")
+		}
+		if rmFormat != "" && !strings.EqualFold(rmFormat, "json") {
+			return invalidf("--format must be json when set")
+		}
+		if rmWrite {
+			unlock, err := lockInventory(rmFile)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+		}
+		doc, err := readInventory(rmFile)
+		if err != nil {
+			return err
+		}
+		if len(doc.BMCs) == 0 {
+			return invalidf("input must contain non-empty bmcs[]")
+		}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+
+		bmcs := dedupeBMCs(doc.BMCs)
+		hosts := bmcHosts(bmcs)
+		byHost := map[string]inventory.Entry{}
+		for i, h := range hosts {
+			byHost[h] = bmcs[i]
+		}
+		ctx := cmd.Context()
+		var mu sync.Mutex
+		checks := map[string]macCheck{}
+		forEachHost(ctx, hosts, rmBatchSize, func(ctx context.Context, h string) {
+			c := checkBMCMAC(ctx, h, byHost[h], user, pass)
+			mu.Lock()
+			defer mu.Unlock()
+			checks[h] = c
+		}, func(string) {})
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+
+		// Apply the reported MACs to a copy to find conflicts they would create
+		fixed := doc
+		fixed.BMCs = append([]inventory.Entry(nil), doc.BMCs...)
+		for i, b := range fixed.BMCs {
+			for _, c := range checks {
+				if c.Status == macMismatch && c.Xname == b.Xname {
+					fixed.BMCs[i].MAC = c.BMCMAC
+				}
+			}
+		}
+		dups := fixed.DuplicateMACs()
+
+		ordered := make([]macCheck, 0, len(hosts))
+		failed := map[string]error{}
+		mismatched := 0
+		for _, h := range hosts {
+			c := checks[h]
+			ordered = append(ordered, c)
+			if c.err != nil {
+				failed[h] = c.err
+			}
+			if c.Status == macMismatch {
+				mismatched++
+			}
+		}
+		if err := printMACChecks(ordered, dups); err != nil {
+			return err
+		}
+
+		if len(dups) > 0 {
+			return fmt.Errorf("%d MAC address(es) assigned to more than one entry; %s not written", len(dups), rmFile)
+		}
+		if rmWrite && mismatched > 0 {
+			if err := writeInventory(rmFile, &fixed); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Updated %d MAC(s) in %s\n", mismatched, rmFile)
+		}
+		return hostFailures(len(hosts), failed)
+	},
+}
+
+// checkBMCMAC reads the MAC a BMC reports and compares it with its inventory entry.
+func checkBMCMAC(ctx context.Context, host string, b inventory.Entry, user, pass string) macCheck {
+	c := macCheck{Xname: b.Xname, Host: host, InventoryMAC: strings.ToLower(b.MAC)}
+	if rmTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, rmTimeout)
+		defer cancel()
+	}
+	nics, err := newRedfishClient(host, user, pass, rmInsecure, rmTimeout).DiscoverManagerNICs(ctx)
+	if err != nil {
+		c.Status, c.Error, c.err = macError, err.Error(), err
+		return c
+	}
+	c.BMCMAC = discover.ManagerMAC(nics, b.IP)
+	switch {
+	case c.BMCMAC == "":
+		c.Status = macUnknown
+	case c.BMCMAC == c.InventoryMAC:
+		c.Status = macMatch
+	default:
+		c.Status = macMismatch
+	}
+	return c
+}
+
+func printMACChecks(checks []macCheck, dups map[string][]string) error {
+	if strings.EqualFold(rmFormat, "json") {
+		out, err := json.MarshalIndent(struct {
+			BMCs       []macCheck          `json:"bmcs"`
+			Duplicates map[string][]string `json:"duplicates,omitempty"`
+		}{checks, dups}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	counts := map[string]int{}
+	for _, c := range checks {
+		counts[c.Status]++
+		switch c.Status {
+		case macMismatch:
+			fmt.Printf("MISMATCH: %s (%s): inventory %s, BMC reports %s\n", c.Xname, c.Host, orNone(c.InventoryMAC), c.BMCMAC)
+		case macUnknown:
+			fmt.Fprintf(os.Stderr, "WARN: %s: BMC reported no MAC address\n", c.Host)
+		case macError:
+			fmt.Fprintf(os.Stderr, "WARN: %s: %s\n", c.Host, c.Error)
+		}
+	}
+	if len(dups) > 0 {
+		macs := make([]string, 0, len(dups))
+		for m := range dups {
+			macs = append(macs, m)
+		}
+		sort.Strings(macs)
+		for _, m := range macs {
+			fmt.Printf("CONFLICT: %s assigned to %s\n", m, strings.Join(dups[m], ", "))
+		}
+	}
+	fmt.Println("MAC reconciliation summary:")
+	for _, st := range []string{macMatch, macMismatch, macUnknown, macError} {
+		fmt.Printf("  %s: %d\n", st, counts[st])
+	}
+	fmt.Printf("  conflicts: %d\n", len(dups))
+	return nil
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+func init() {
+	rootCmd.AddCommand(reconcileMACsCmd)
+	reconcileMACsCmd.Flags().StringVarP(&rmFile, "file", "f", "", "inventory YAML with bmcs[] (and optionally nodes[])")
+	reconcileMACsCmd.Flags().BoolVar(&rmInsecure, "insecure", true, "allow insecure TLS to BMCs")
+	reconcileMACsCmd.Flags().DurationVar(&rmTimeout, "timeout", 30*time.Second, "per-BMC timeout")
+	reconcileMACsCmd.Flags().IntVar(&rmBatchSize, "batch-size", 10, "number of BMCs to query concurrently")
+	reconcileMACsCmd.Flags().BoolVar(&rmWrite, "write", false, "update mismatched bmcs[] MACs in --file (refused while conflicts exist)")
+	reconcileMACsCmd.Flags().StringVar(&rmFormat, "format", "", "output format: json")
+}
@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+)
+
+func TestReconcileMACs(t *testing.T) {
+	t.Setenv("REDFISH_USER", "user")
+	t.Setenv("REDFISH_PASSWORD", "pass")
+	reports := func(mac string) *redfishtest.MockClient {
+		return &redfishtest.MockClient{
+			DiscoverManagerNICsFunc: func(context.Context) ([]redfish.ManagerNIC, error) {
+				return []redfish.ManagerNIC{{ID: "eth0", MAC: mac}}, nil
+			},
+		}
+	}
+	inv := filepath.Join(t.TempDir(), "inventory.yaml")
+	write := func(nodeMAC string) {
+		doc := `bmcs:
+  - xname: x1000c0s0b0
+    mac: 02:00:00:00:00:01
+    ip: 10.1.0.1
+  - xname: x1000c0s1b0
+    mac: 02:00:00:00:00:02
+    ip: 10.1.0.2
+nodes:
+  - xname: x1000c0s0b0n0
+    mac: ` + nodeMAC + `
+    ip: 10.2.0.1
+`
+		if err := os.WriteFile(inv, []byte(doc), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{
+		"10.1.0.1": reports("02:00:00:00:00:01"),
+		"10.1.0.2": reports("02:00:00:00:00:99"), // blade swapped into slot 1
+	})
+	rmFile, rmWrite, rmBatchSize = inv, true, 2
+	defer func() { rmFile, rmWrite = "", false }()
+	cmd := reconcileMACsCmd
+	cmd.SetContext(context.Background())
+
+	// The swapped blade's MAC now also belongs to a node: conflict, nothing written
+	write("02:00:00:00:00:99")
+	if err := cmd.RunE(cmd, nil); err == nil || !strings.Contains(err.Error(), "not written") {
+		t.Fatalf("conflict: err = %v", err)
+	}
+	if raw, _ := os.ReadFile(inv); strings.Contains(string(raw), "mac: 02:00:00:00:00:99\n    ip: 10.1.0.2") {
+		t.Error("conflict: file was written")
+	}
+
+	write("aa:00:00:00:00:01")
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := os.ReadFile(inv)
+	if !strings.Contains(string(raw), "mac: 02:00:00:00:00:99\n      ip: 10.1.0.2") {
+		t.Errorf("mismatched MAC not corrected:\n%s", raw)
+	}
+}
@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"bootstrap/internal/render"
+	"bootstrap/internal/safefile"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	rdFile        string
+	rdOutput      string
+	rdTemplateDir []string
+	rdList        bool
+	rdNodeSubnet  string
+	rdBMCSubnet   string
+	rdGateway     string
+	rdBMCGateway  string
+	rdDNS         []string
+	rdDomain      string
+	rdVars        []string
+)
+
+var renderCmd = &cobra.Command{
+	Use:   "render TEMPLATE",
+	Short: "Generate dnsmasq, iPXE, Ansible or other configuration from the inventory",
+	Long: `Execute a Go text/template with the inventory and print the result, or write it
+to --output. Built-in templates are dnsmasq, ipxe and ansible (and the per-node
+cloud-init ones used by the cloud-init command); --list shows them all.
+
+A file TEMPLATE.tmpl in a --template-dir replaces the built-in template of that
+name, and any other .tmpl file there becomes a template of its own. Templates
+see .Nodes, .BMCs, .Topology (cabinets with .Entries "node"), .NodeNet and
+.BMCNet (from --node-subnet, --bmc-subnet, --gateway, --dns, --domain) and
+.Vars (from --set key=value), plus the functions lower, upper, join, default,
+hostname and required.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		engine := render.New(rdTemplateDir...)
+		if rdList {
+			list, err := engine.List()
+			if err != nil {
+				return err
+			}
+			tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "TEMPLATE\tSOURCE")
+			for _, t := range list {
+				fmt.Fprintf(tw, "%s\t%s\n", t.Name, t.Source)
+			}
+			return tw.Flush()
+		}
+		if len(args) != 1 {
+			return invalidf("name a template to render (see --list)")
+		}
+		if rdFile == "" {
+			return invalidf("--file is required")
+		}
+		doc, err := readInventory(rdFile)
+		if err != nil {
+			return err
+		}
+		rctx := render.NewContext(doc)
+		if rctx.NodeNet, err = render.ParseNetwork(rdNodeSubnet, rdGateway, rdDNS, rdDomain); err != nil {
+			return invalidf("--node-subnet: %w", err)
+		}
+		if rctx.BMCNet, err = render.ParseNetwork(rdBMCSubnet, rdBMCGateway, rdDNS, rdDomain); err != nil {
+			return invalidf("--bmc-subnet: %w", err)
+		}
+		for _, kv := range rdVars {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok || k == "" {
+				return invalidf("--set %q: want key=value", kv)
+			}
+			rctx.Vars[k] = v
+		}
+		// Render fully before writing so a template error leaves no partial file.
+		var out bytes.Buffer
+		if err := engine.Render(&out, args[0], rctx); err != nil {
+			return invalid(err)
+		}
+		if rdOutput == "" || rdOutput == "-" {
+			_, err := os.Stdout.Write(out.Bytes())
+			return err
+		}
+		if err := safefile.Write(rdOutput, out.Bytes(), 0o644, 1); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s to %s\n", args[0], rdOutput)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renderCmd)
+	renderCmd.Flags().StringVarP(&rdFile, "file", "f", "", "inventory to render")
+	renderCmd.Flags().StringVarP(&rdOutput, "output", "o", "", "file to write; the previous one is kept as <file>.1 (default: stdout)")
+	renderCmd.Flags().StringArrayVar(&rdTemplateDir, "template-dir", nil, "directory with templates overriding the built-in ones; repeatable, earlier ones win")
+	renderCmd.Flags().BoolVar(&rdList, "list", false, "list the available templates and where each comes from")
+	renderCmd.Flags().StringVar(&rdNodeSubnet, "node-subnet", "", "CIDR node IPs are allocated from (.NodeNet)")
+	renderCmd.Flags().StringVar(&rdBMCSubnet, "bmc-subnet", "", "CIDR BMC IPs are allocated from (.BMCNet)")
+	renderCmd.Flags().StringVar(&rdGateway, "gateway", "", "default router of the node network (default: first address of --node-subnet)")
+	renderCmd.Flags().StringVar(&rdBMCGateway, "bmc-gateway", "", "default router of the BMC network (default: first address of --bmc-subnet)")
+	renderCmd.Flags().StringSliceVar(&rdDNS, "dns", nil, "name servers handed out to nodes")
+	renderCmd.Flags().StringVar(&rdDomain, "domain", "", "DNS domain of the nodes")
+	renderCmd.Flags().StringArrayVar(&rdVars, "set", nil, "template variable key=value, available as .Vars.key; repeatable (e.g. boot_url=http://10.42.0.1/boot)")
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	dir := t.TempDir()
+	inv := filepath.Join(dir, "inventory.yaml")
+	doc := `nodes:
+  - xname: x1000c0s0b0n0
+    alias: nid000001
+    mac: aa:00:00:00:00:01
+    ip: 10.42.0.11
+`
+	if err := os.WriteFile(inv, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { rdFile, rdOutput, rdNodeSubnet, rdVars, rdTemplateDir = "", "", "", nil, nil })
+	out := filepath.Join(dir, "boot.ipxe")
+	rdFile, rdOutput, rdNodeSubnet, rdVars = inv, out, "10.42.0.0/24", []string{"boot_url=http://10.42.0.1/boot", "kernel_args=console=ttyS0"}
+
+	if err := renderCmd.RunE(renderCmd, []string{"ipxe"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"iseq ${mac} aa:00:00:00:00:01 && set hostname nid000001 && set xname x1000c0s0b0n0 ||\n",
+		"set base http://10.42.0.1/boot\nkernel ${base}/vmlinuz initrd=initrd.img hostname=${hostname} xname=${xname} console=ttyS0\n",
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("boot.ipxe missing %q:\n%s", want, got)
+		}
+	}
+
+	rdVars = []string{"boot_url"}
+	if err := renderCmd.RunE(renderCmd, []string{"ipxe"}); exitCode(err) != exitInvalid {
+		t.Errorf("--set without '=': err = %v, want invalid", err)
+	}
+	rdVars = nil
+	if err := renderCmd.RunE(renderCmd, []string{"ipxe"}); exitCode(err) != exitInvalid {
+		t.Errorf("missing boot_url: err = %v, want invalid", err)
+	}
+	if err := renderCmd.RunE(renderCmd, []string{"no-such-template"}); exitCode(err) != exitInvalid {
+		t.Errorf("unknown template: err = %v, want invalid", err)
+	}
+}
@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"bootstrap/internal/report"
+	"bootstrap/internal/safefile"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	rpFirmware string
+	rpFile     string
+	rpBoot     string
+	rpTitle    string
+	rpOutput   string
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Render command results for people who do not run the CLI",
+}
+
+var reportHTMLCmd = &cobra.Command{
+	Use:   "html",
+	Short: "Render firmware, discovery and boot validation results as a standalone HTML page",
+	Long: `Render the results of earlier runs as one HTML page with sortable tables,
+failures highlighted and charts of the versions found, for sharing with teams
+that never run the CLI. The page has no external dependencies.
+
+Sections are included for the inputs given:
+  --firmware  output of 'firmware status --format json'
+  --file      an inventory written by discover (BMCs, nodes, models, BIOS versions)
+  --boot      output of 'verify-boot --format json'`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if rpFirmware == "" && rpFile == "" && rpBoot == "" {
+			return invalidf("give at least one of --firmware, --file and --boot")
+		}
+		if rpFirmware == "-" && rpBoot == "-" {
+			return invalidf("only one of --firmware and --boot can read stdin")
+		}
+		r := report.Report{Title: rpTitle, Generated: time.Now()}
+		if rpFirmware != "" {
+			if err := readJSONFile(rpFirmware, &r.Firmware); err != nil {
+				return err
+			}
+		}
+		if rpFile != "" {
+			doc, err := readInventory(rpFile)
+			if err != nil {
+				return err
+			}
+			r.Inventory = &doc
+		}
+		if rpBoot != "" {
+			if err := readJSONFile(rpBoot, &r.Boot); err != nil {
+				return err
+			}
+		}
+		var out bytes.Buffer
+		if err := report.WriteHTML(&out, r); err != nil {
+			return err
+		}
+		if rpOutput == "" || rpOutput == "-" {
+			_, err := os.Stdout.Write(out.Bytes())
+			return err
+		}
+		if err := safefile.Write(rpOutput, out.Bytes(), 0o644, 0); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Wrote report to %s\n", rpOutput)
+		return nil
+	},
+}
+
+// readJSONFile decodes the JSON file at path, or stdin for "-", into v.
+func readJSONFile(path string, v any) error {
+	var b []byte
+	var err error
+	if path == "-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return invalid(err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return invalidf("%s: %w", path, err)
+	}
+	return nil
+}
+
+func init() {
+	reportHTMLCmd.Flags().StringVar(&rpFirmware, "firmware", "", "JSON from 'firmware status --format json' (- for stdin)")
+	reportHTMLCmd.Flags().StringVarP(&rpFile, "file", "f", "", "inventory file written by discover")
+	reportHTMLCmd.Flags().StringVar(&rpBoot, "boot", "", "JSON from 'verify-boot --format json' (- for stdin)")
+	reportHTMLCmd.Flags().StringVar(&rpTitle, "title", "Bootstrap report", "page title")
+	reportHTMLCmd.Flags().StringVarP(&rpOutput, "output", "o", "", "file to write (default: stdout)")
+	reportCmd.AddCommand(reportHTMLCmd)
+	rootCmd.AddCommand(reportCmd)
+}
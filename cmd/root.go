@@ -5,10 +5,17 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"bootstrap/internal/diag"
+	"bootstrap/internal/redact"
 
 	"github.com/spf13/cobra"
 )
@@ -16,22 +23,111 @@ import (
 var rootCmd = &cobra.Command{
 	Use:   "ochami_bootstrap",
 	Short: "Bootstrap inventory generation and NIC discovery via Redfish",
-	PersistentPreRun: func(cmd *cobra.Command, args []string) { //nolint:revive
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
 		// propagate debug flag to internal diagnostics
 		diag.Debug = debugFlag
+		registerSecrets()
+		if credentialSource != "env" && credentialSource != "keychain" {
+			return invalidf("--credentials must be env or keychain, not %q", credentialSource)
+		}
+		if err := configureAggregator(); err != nil {
+			return err
+		}
+		if err := configureProgress(); err != nil {
+			return err
+		}
+		if err := configureNotifiers(); err != nil {
+			return err
+		}
+		if err := configureLedger(); err != nil {
+			return err
+		}
+		if err := configureQuarantine(); err != nil {
+			return err
+		}
+		if err := configureWindows(); err != nil {
+			return err
+		}
+		return configureDialer()
 	},
 }
 
 var debugFlag bool
 
+// secretEnv are environment variables holding passwords or tokens; their values
+// are masked in logs, errors and plans.
+var secretEnv = []string{"REDFISH_PASSWORD", "IPMI_PASSWORD", "ACCESS_TOKEN", "BOOTSTRAP_API_TOKEN"}
+
+// registerSecrets registers the secrets this run knows of up front with
+// package redact. Secrets read later (token files, notify passwords) are
+// registered where they are read.
+func registerSecrets() {
+	for _, env := range secretEnv {
+		redact.Add(os.Getenv(env))
+	}
+	if u, err := url.Parse(proxyURL); err == nil && u.User != nil {
+		p, _ := u.User.Password()
+		redact.Add(p)
+	}
+}
+
+// errInterrupted is returned by commands that stopped early because the run was
+// cancelled (SIGINT/SIGTERM).
+var errInterrupted = errors.New("interrupted")
+
+// exitInterrupted is the exit status after SIGINT/SIGTERM (128 + SIGINT).
+const exitInterrupted = 130
+
 // Execute is the entry point for the CLI.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	// Cancel the command context on Ctrl-C so in-flight Redfish calls stop. The
+	// first signal is then released, so a second Ctrl-C kills the process even
+	// while a hung call or an image download wait winds down.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	context.AfterFunc(ctx, stop)
+	err := rootCmd.ExecuteContext(ctx)
+	interrupted := ctx.Err() != nil
+	stop()
+	recordQuarantine()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, redact.String(err.Error()))
+	}
+	code := exitCode(err)
+	if interrupted {
+		code = exitInterrupted
+	}
+	if code != exitOK {
+		os.Exit(code)
 	}
 }
 
 func init() {
 	rootCmd.PersistentFlags().BoolVar(&debugFlag, "debug", false, "enable verbose debug logging")
+	rootCmd.PersistentFlags().StringVar(&proxyURL, "proxy", "", "reach BMCs through this proxy: socks5://[user:pass@]host:port, socks5h://... or http://[user:pass@]host:port")
+	rootCmd.PersistentFlags().StringVar(&sshJump, "ssh-jump", "", "reach BMCs through this SSH jump host, user@host[:port] (uses ssh-agent or --ssh-jump-key)")
+	rootCmd.PersistentFlags().StringVar(&sshJumpKey, "ssh-jump-key", "", "private key for --ssh-jump (default: ssh-agent and ~/.ssh/id_*)")
+	rootCmd.PersistentFlags().StringVar(&aggregatorHost, "aggregator", "", "reach BMCs through this Redfish aggregator (host[:port]); BMCs are addressed by xname")
+	rootCmd.PersistentFlags().StringVar(&aggregatorMap, "aggregator-map", "", "YAML file mapping BMC xname (or host) to its path prefix on the aggregator, e.g. x1000c0s0b0: /redfish/v1/Aggregate/x1000c0s0b0")
+	rootCmd.PersistentFlags().StringVar(&aggregatorPrefix, "aggregator-prefix", "", "path prefix of BMCs missing from --aggregator-map; {host} is replaced by the xname, e.g. /redfish/v1/Aggregate/{host}")
+	rootCmd.PersistentFlags().StringArrayVar(&sourceIPs, "source-ip", nil, "local address for connections to BMCs (or to the proxy/jump host): IP, or CIDR=IP for destinations in CIDR; repeatable")
+	rootCmd.PersistentFlags().StringArrayVar(&sourceIfaces, "interface", nil, "like --source-ip but uses the first IPv4 address of an interface: NAME or CIDR=NAME; repeatable")
+	rootCmd.PersistentFlags().StringArrayVar(&tagSelectors, "tag", nil, "only target BMCs (and nodes) whose inventory tags match: key=value (value may be a glob), key!=value or key; repeatable, all must match; nodes inherit their BMC's tags")
+	rootCmd.PersistentFlags().BoolVar(&adaptiveBatch, "adaptive-batch", false, "start with 2 BMCs at a time and add one per round of quick Redfish answers, halving on 503/429 answers and timeouts; --batch-size is the ceiling (default 32)")
+	rootCmd.PersistentFlags().IntVar(&inventoryBackups, "backups", 3, "number of previous versions kept (as FILE.1, FILE.2, ...) when a command rewrites an inventory file")
+	rootCmd.PersistentFlags().IntVar(&progressFD, "progress-fd", 0, "write per-host progress as JSON lines to this file descriptor")
+	_ = rootCmd.PersistentFlags().MarkHidden("progress-fd")
+	rootCmd.PersistentFlags().StringVar(&notifyConfig, "notify-config", "", "YAML file listing webhook, email and syslog sinks notified when a discover or firmware run finishes")
+	rootCmd.PersistentFlags().StringArrayVar(&webhookURLs, "webhook", nil, "POST a summary to this URL when a discover or firmware run finishes; repeatable")
+	rootCmd.PersistentFlags().StringVar(&webhookFormat, "webhook-format", "", "webhook payload: json or slack (default slack for hooks.slack.com URLs, else json)")
+	rootCmd.PersistentFlags().StringVar(&webhookTemplate, "webhook-template", "", "Go text/template file rendering the webhook body from the run summary (for slack: the message text)")
+	rootCmd.PersistentFlags().StringVar(&webhookThreshold, "webhook-failure-threshold", "", "also notify as soon as this many hosts (N) or this share of hosts (N%) have failed (overrides failure_threshold in --notify-config)")
+	rootCmd.PersistentFlags().StringVar(&ledgerFile, "ledger", "", "record firmware updates and settings PATCHes sent to BMCs in this file and do not resend ones a BMC already accepted (default $BOOTSTRAP_LEDGER)")
+	rootCmd.PersistentFlags().DurationVar(&ledgerWindow, "ledger-window", 12*time.Hour, "how long an accepted operation in --ledger keeps the same operation from being sent again; 0 to resend")
+	rootCmd.PersistentFlags().StringVar(&quarantineFile, "quarantine", "", "skip the BMCs listed in this file and add hosts that reject the credentials or do not answer several runs in a row (default $BOOTSTRAP_QUARANTINE)")
+	rootCmd.PersistentFlags().IntVar(&quarantineAfter, "quarantine-after", 3, "failed runs in a row after which --quarantine sets a host aside")
+	rootCmd.PersistentFlags().BoolVar(&includeQuarantined, "include-quarantined", false, "run hosts listed in --quarantine too; a host that answers is released")
+	rootCmd.PersistentFlags().StringVar(&credentialSource, "credentials", credentialSource, "where passwords and tokens are read from: env, or keychain (the OS credential store: macOS keychain, Secret Service, Windows Credential Manager; default $BOOTSTRAP_CREDENTIALS or env)")
+	rootCmd.PersistentFlags().StringArrayVar(&windowSpecs, "maintenance-window", nil, "only run destructive operations in this weekly window, e.g. \"Sat 00:00-06:00 America/Denver\" or \"Mon-Fri 22:00-02:00\"; hosts not started when it closes are left alone; repeatable (default $BOOTSTRAP_MAINTENANCE_WINDOW, windows separated by ';')")
+	rootCmd.PersistentFlags().StringVar(&windowFile, "maintenance-window-file", "", "YAML file listing maintenance windows under windows:, combined with --maintenance-window (default $BOOTSTRAP_MAINTENANCE_WINDOW_FILE)")
+	rootCmd.PersistentFlags().StringVar(&sshJumpKnownHosts, "ssh-jump-known-hosts", "", "known_hosts file used to verify the jump host (default ~/.ssh/known_hosts)")
 }
@@ -0,0 +1,276 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"bootstrap/internal/apiserver"
+	"bootstrap/internal/redact"
+	"bootstrap/pkg/inventory"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+var (
+	srvListen    string
+	srvFile      string
+	srvTokenFile string
+	srvTLSCert   string
+	srvTLSKey    string
+	srvMaxOutput int
+	srvKeepJobs  int
+	srvGRPC      string
+	srvJobsDB    string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve inventory, discovery and firmware operations over HTTP and gRPC APIs",
+	Long: `Listen on --listen and expose the inventory in --file and the discover and
+firmware commands over HTTP, so automation can drive them without a shell.
+
+Every /v1 request must carry "Authorization: Bearer <token>", with the token read
+from --token-file or $BOOTSTRAP_API_TOKEN. Discovery and firmware updates run as
+background jobs (this binary, with the same global flags and environment) whose
+state and output are polled under /v1/jobs; GET /healthz needs no token. A
+"schedule" time in the request (e.g. "2025-07-01T02:00Z") queues the job until
+then. Jobs are kept in --jobs-db, so queued work survives a restart; jobs that
+were running when the server stopped are marked interrupted.
+
+Routes:
+  GET    /v1/inventory           whole inventory (bmcs[] and nodes[])
+  GET    /v1/inventory/{key}     entries by xname, MAC or IP
+  POST   /v1/discover            start discover; {"bmc_subnet", "node_subnet", "hosts", "dry_run"}
+  POST   /v1/firmware            start firmware; {"image_uri", "type"|"targets", "hosts",
+                                 "protocol", "expected_version", "force", "batch_size", "dry_run"}
+  GET    /v1/firmware/status     firmware status report (?hosts=a,b&type=cc)
+  GET    /v1/jobs                jobs, newest first
+  GET    /v1/jobs/{id}           one job with its output
+  GET    /v1/jobs/{id}/log       the job's output as text
+  DELETE /v1/jobs/{id}           cancel a queued or running job
+
+With --grpc-listen, the Bootstrap gRPC service (pkg/api) is served there too,
+with the same token as "authorization" metadata. Its Discover and UpdateFirmware
+calls stream per-host progress and output while the run goes on.`,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if srvFile == "" {
+			return invalidf("--file is required")
+		}
+		if (srvTLSCert == "") != (srvTLSKey == "") {
+			return invalidf("--tls-cert and --tls-key must be given together")
+		}
+		token, err := apiToken(srvTokenFile)
+		if err != nil {
+			return err
+		}
+		if _, err := readInventory(srvFile); err != nil {
+			return err
+		}
+		self, err := os.Executable()
+		if err != nil {
+			return err
+		}
+
+		jobsDB := srvJobsDB
+		if jobsDB == "" {
+			jobsDB = srvFile + ".jobs"
+		}
+		store, err := apiserver.OpenJobDB(jobsDB)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+
+		ctx, stopJobs := context.WithCancel(cmd.Context())
+		defer stopJobs()
+		api, err := apiserver.New(ctx, apiserver.Config{
+			Token:         token,
+			InventoryFile: srvFile,
+			Open:          func(path string) (inventory.Store, error) { return openInventory(path, true) },
+			Run:           selfRunner(self, forwardedFlags()),
+			MaxOutput:     srvMaxOutput,
+			KeepJobs:      srvKeepJobs,
+			Store:         store,
+		})
+		if err != nil {
+			return err
+		}
+		// Record the jobs the shutdown interrupts before the store is closed
+		defer func() {
+			stopJobs()
+			api.Wait()
+		}()
+		srv := &http.Server{
+			Addr:              srvListen,
+			Handler:           api.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		errc := make(chan error, 2)
+		go func() {
+			if srvTLSCert != "" {
+				errc <- srv.ListenAndServeTLS(srvTLSCert, srvTLSKey)
+			} else {
+				errc <- srv.ListenAndServe()
+			}
+		}()
+		fmt.Fprintf(cmd.OutOrStdout(), "Serving API on %s\n", srvListen)
+
+		var gs *grpc.Server
+		if srvGRPC != "" {
+			opts := api.GRPCOptions()
+			if srvTLSCert != "" {
+				creds, err := credentials.NewServerTLSFromFile(srvTLSCert, srvTLSKey)
+				if err != nil {
+					return invalid(err)
+				}
+				opts = append(opts, grpc.Creds(creds))
+			}
+			gs = grpc.NewServer(opts...)
+			api.RegisterGRPC(gs)
+			lis, err := net.Listen("tcp", srvGRPC)
+			if err != nil {
+				return err
+			}
+			go func() { errc <- gs.Serve(lis) }()
+			fmt.Fprintf(cmd.OutOrStdout(), "Serving gRPC API on %s\n", srvGRPC)
+		}
+
+		select {
+		case err := <-errc:
+			if gs != nil {
+				gs.Stop()
+			}
+			return err
+		case <-ctx.Done():
+		}
+		if gs != nil {
+			// Streams last as long as their run, which ctx has just interrupted
+			gs.Stop()
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+		return errInterrupted
+	},
+}
+
+// apiToken returns the API token from file or, when file is empty,
+// $BOOTSTRAP_API_TOKEN.
+func apiToken(file string) (string, error) {
+	if file != "" {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return "", invalid(err)
+		}
+		if t := strings.TrimSpace(string(raw)); t != "" {
+			redact.Add(t)
+			return t, nil
+		}
+		return "", invalidf("%s: empty token", file)
+	}
+	t, err := secret("BOOTSTRAP_API_TOKEN")
+	if err != nil {
+		return "", err
+	}
+	if t := strings.TrimSpace(t); t != "" {
+		return t, nil
+	}
+	return "", invalidf("an API token is required: set --token-file or BOOTSTRAP_API_TOKEN")
+}
+
+// forwardedFlags returns the global flags set on this run (proxy, jump host,
+// aggregator, source address, ...) so jobs reach BMCs the same way.
+func forwardedFlags() []string {
+	var out []string
+	rootCmd.PersistentFlags().Visit(func(f *pflag.Flag) {
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range sv.GetSlice() {
+				out = append(out, "--"+f.Name+"="+v)
+			}
+			return
+		}
+		out = append(out, "--"+f.Name+"="+f.Value.String())
+	})
+	return out
+}
+
+// selfRunner runs jobs as child processes of the executable self, so each job
+// has its own flag state and can be killed on cancel. Progress is read from a
+// pipe handed to the child as --progress-fd.
+func selfRunner(self string, global []string) apiserver.Runner {
+	return func(ctx context.Context, args []string, stdout, stderr, progress io.Writer) (int, error) {
+		// Jobs were confirmed by whoever submitted them over the API
+		argv := append(append([]string(nil), global...), "--yes")
+		var pr, pw *os.File
+		if progress != nil {
+			var err error
+			if pr, pw, err = os.Pipe(); err != nil {
+				return -1, err
+			}
+			defer pr.Close()
+			argv = append(argv, "--progress-fd=3") // first of ExtraFiles
+		}
+		c := exec.CommandContext(ctx, self, append(argv, args...)...)
+		c.Stdout, c.Stderr = stdout, stderr
+		if pw != nil {
+			c.ExtraFiles = []*os.File{pw}
+		}
+		// SIGINT lets the job stop between hosts and report like an interrupted run
+		c.Cancel = func() error { return c.Process.Signal(os.Interrupt) }
+		c.WaitDelay = 30 * time.Second
+		err := c.Start()
+		if pw != nil {
+			pw.Close() // the child holds its own copy
+		}
+		if err != nil {
+			return -1, err
+		}
+		copied := make(chan struct{})
+		go func() {
+			defer close(copied)
+			if pr != nil {
+				_, _ = io.Copy(progress, pr)
+			}
+		}()
+		err = c.Wait()
+		<-copied
+		var ee *exec.ExitError
+		if errors.As(err, &ee) {
+			return ee.ExitCode(), nil
+		}
+		if err != nil {
+			return -1, err
+		}
+		return 0, nil
+	}
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&srvListen, "listen", "127.0.0.1:8080", "address to listen on")
+	serveCmd.Flags().StringVarP(&srvFile, "file", "f", "", "inventory file (YAML or .db) served and passed to discover/firmware jobs (required)")
+	serveCmd.Flags().StringVar(&srvTokenFile, "token-file", "", "file holding the bearer token clients must send (default: $BOOTSTRAP_API_TOKEN)")
+	serveCmd.Flags().StringVar(&srvTLSCert, "tls-cert", "", "serve HTTPS with this certificate (requires --tls-key)")
+	serveCmd.Flags().StringVar(&srvTLSKey, "tls-key", "", "private key for --tls-cert")
+	serveCmd.Flags().IntVar(&srvMaxOutput, "max-job-output", 1<<20, "bytes of output kept per job (older output is dropped)")
+	serveCmd.Flags().IntVar(&srvKeepJobs, "keep-jobs", 100, "number of finished jobs kept for status queries")
+	serveCmd.Flags().StringVar(&srvJobsDB, "jobs-db", "", "database keeping submitted jobs across restarts (default: <--file>.jobs)")
+	serveCmd.Flags().StringVar(&srvGRPC, "grpc-listen", "", "also serve the gRPC API on this address, e.g. 127.0.0.1:9090 (uses --tls-cert/--tls-key when set)")
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,272 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"bootstrap/internal/envcheck"
+	"bootstrap/internal/xname"
+	"bootstrap/pkg/redfish"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	slFile     string
+	slChassis  string
+	slCMMHost  string
+	slSlots    []int
+	slInsecure bool
+	slTimeout  time.Duration
+	slWait     time.Duration
+	slDryRun   bool
+	slCDUHosts []string
+	slOverride bool
+	slLimits   envcheck.Limits
+)
+
+// slotPollInterval is how often --wait re-reads the slots. Tests shorten it.
+var slotPollInterval = 5 * time.Second
+
+// Redfish Chassis.Reset type of each slot power action
+var slotResetTypes = map[string]string{"on": "On", "off": "ForceOff"}
+
+var slotCmd = &cobra.Command{
+	Use:   "slot",
+	Short: "Control the blade slots of a chassis through its chassis controller (CMM)",
+}
+
+var slotPowerCmd = &cobra.Command{
+	Use:       "power on|off|status",
+	Short:     "Power blade slots on or off, or report their power state",
+	ValidArgs: []string{"on", "off", "status"},
+	Args:      cobra.ExactArgs(1),
+	Long: `On EX hardware the node controllers (BMCs) of a blade only come up once the
+chassis controller powers its slot. This drives the Chassis.Reset action of the
+slot's chassis (BladeN) on the chassis controller of --chassis, which is reached
+at the bmcs[] IP of its xname (e.g. x9000c1b0) in --file, at --cmm-host, or at
+the xname itself.
+
+Before power on, the environmental sensors of the chassis controller and of any
+--cdu-host are checked: leak detectors must be OK, and no sensor may be Critical or
+past its critical thresholds or, for coolant temperatures, outside
+--min-coolant-temp/--max-coolant-temp. A failing check refuses the power on unless
+--override is given.
+
+status without --slot reports every blade slot.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		action := args[0]
+		if _, ok := slotResetTypes[action]; !ok && action != "status" {
+			return invalidf("unknown slot power action %q (use on, off or status)", action)
+		}
+		slChassis = strings.ToLower(strings.TrimSpace(slChassis))
+		if xname.Chassis(slChassis) == "" || xname.Chassis(slChassis) != slChassis {
+			return invalidf("--chassis must be a chassis xname such as x9000c1")
+		}
+		if len(slSlots) == 0 && action != "status" {
+			return invalidf("--slot is required for slot power %s", action)
+		}
+		host, err := cmmHost(slChassis)
+		if err != nil {
+			return err
+		}
+		targets := make([]string, len(slSlots))
+		for i, n := range slSlots {
+			targets[i] = fmt.Sprintf("%ss%d", slChassis, n)
+		}
+		if slDryRun && action != "status" {
+			fmt.Printf("[dry-run] would power %s %s via %s\n", action, strings.Join(targets, ", "), host)
+			return nil
+		}
+		if action == "off" {
+			if stop, err := needsApproval(cmd, args, "slot power off "+strings.Join(targets, ","), []string{host}); stop || err != nil {
+				return err
+			}
+		}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		rf := newRedfishClient(host, user, pass, slInsecure, slTimeout)
+		chassis, err := rf.GetChassis(ctx)
+		if err != nil {
+			return fmt.Errorf("%s: read chassis: %w", host, err)
+		}
+		if action == "status" {
+			return printSlotStatus(chassis)
+		}
+		if action == "on" {
+			if err := slotPreflight(ctx, rf, host, user, pass); err != nil {
+				return err
+			}
+		}
+
+		failed := map[string]error{}
+		var pending []int
+		for i, n := range slSlots {
+			ch, ok := redfish.BladeSlot(chassis, n)
+			if !ok {
+				failed[targets[i]] = fmt.Errorf("%s has no blade slot %d", host, n)
+				fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", targets[i], failed[targets[i]])
+				continue
+			}
+			if err := rf.ResetChassis(ctx, ch.ODataID, slotResetTypes[action]); err != nil {
+				failed[targets[i]] = err
+				fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", targets[i], err)
+				continue
+			}
+			fmt.Printf("%s: power %s requested\n", targets[i], action)
+			pending = append(pending, n)
+		}
+		if slWait > 0 && len(pending) > 0 {
+			for n, err := range waitSlots(ctx, rf, pending, action) {
+				x := fmt.Sprintf("%ss%d", slChassis, n)
+				failed[x] = err
+				fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", x, err)
+			}
+		}
+		return hostFailures(len(targets), failed)
+	},
+}
+
+// slotPreflight checks the environmental sensors of the chassis controller and
+// of --cdu-host before slots are powered on, and fails on a blocking reading
+// unless --override is set.
+func slotPreflight(ctx context.Context, cmm redfish.Client, host, user, pass string) error {
+	var res envcheck.Result
+	clients := map[string]redfish.Client{host: cmm}
+	hosts := []string{host}
+	for _, h := range slCDUHosts {
+		clients[h] = newRedfishClient(h, user, pass, slInsecure, slTimeout)
+		hosts = append(hosts, h)
+	}
+	for _, h := range hosts {
+		env, err := clients[h].GetEnvironment(ctx)
+		if err != nil {
+			res.Problems = append(res.Problems, envcheck.Problem{Host: h, Chassis: "-", Name: "-", Reason: fmt.Sprintf("read sensors: %v", err)})
+			continue
+		}
+		res.Check(h, env, slLimits)
+	}
+	fmt.Printf("Preflight: %d sensor(s) (%d coolant), %d leak detector(s) on %d host(s)\n", res.Sensors, res.Coolant, res.LeakDetectors, len(hosts))
+	if res.Sensors+res.LeakDetectors == 0 && len(res.Problems) == 0 {
+		fmt.Fprintf(os.Stderr, "WARN: no environmental sensors exposed; nothing to check\n")
+	}
+	for _, p := range res.Problems {
+		fmt.Fprintf(os.Stderr, "BLOCKED: %s\n", p)
+	}
+	if len(res.Problems) == 0 {
+		return nil
+	}
+	if slOverride {
+		fmt.Fprintf(os.Stderr, "WARN: --override given; powering on despite %d environmental problem(s)\n", len(res.Problems))
+		return nil
+	}
+	return fmt.Errorf("environmental preflight failed with %d problem(s); fix them or pass --override", len(res.Problems))
+}
+
+// cmmHost returns the address of the chassis controller of chassis: --cmm-host,
+// else the IP of its bmcs[] entry (<chassis>b0) in --file, else that xname.
+func cmmHost(chassis string) (string, error) {
+	if slCMMHost != "" {
+		return slCMMHost, nil
+	}
+	x := chassis + "b0"
+	if slFile == "" {
+		return x, nil
+	}
+	doc, err := readInventory(slFile)
+	if err != nil {
+		return "", err
+	}
+	return bmcAddr(x, doc.BMCs, nil), nil
+}
+
+// printSlotStatus prints the power state of every blade slot, or of the slots of
+// --slot.
+func printSlotStatus(chassis []redfish.Chassis) error {
+	failed := map[string]error{}
+	slots := slSlots
+	if len(slots) == 0 {
+		for _, ch := range chassis {
+			if n, ok := redfish.SlotNumber(ch); ok && !slices.Contains(slots, n) {
+				slots = append(slots, n)
+			}
+		}
+		slices.Sort(slots)
+	}
+	for _, n := range slots {
+		x := fmt.Sprintf("%ss%d", slChassis, n)
+		ch, ok := redfish.BladeSlot(chassis, n)
+		if !ok {
+			failed[x] = fmt.Errorf("no blade slot %d", n)
+			fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", x, failed[x])
+			continue
+		}
+		fmt.Printf("%s: %s\n", x, ch.PowerState)
+	}
+	return hostFailures(len(slots), failed)
+}
+
+// waitSlots polls the chassis controller until every slot in slots reports the
+// power state of action or --wait runs out, and returns the slots that did not
+// get there.
+func waitSlots(ctx context.Context, rf redfish.Client, slots []int, action string) map[int]error {
+	want := map[string]string{"on": "On", "off": "Off"}[action]
+	ctx, cancel := context.WithTimeout(ctx, slWait)
+	defer cancel()
+	state := map[int]string{}
+	for {
+		chassis, err := rf.GetChassis(ctx)
+		if err == nil {
+			done := true
+			for _, n := range slots {
+				ch, _ := redfish.BladeSlot(chassis, n)
+				state[n] = ch.PowerState
+				done = done && strings.EqualFold(ch.PowerState, want)
+			}
+			if done {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			out := map[int]error{}
+			for _, n := range slots {
+				if !strings.EqualFold(state[n], want) {
+					out[n] = fmt.Errorf("still %s after %s", cmp.Or(state[n], "unknown"), slWait)
+				}
+			}
+			return out
+		case <-time.After(slotPollInterval):
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(slotCmd)
+	slotCmd.AddCommand(slotPowerCmd)
+	slotPowerCmd.Flags().StringVar(&slChassis, "chassis", "", "chassis xname, e.g. x9000c1 (required)")
+	slotPowerCmd.Flags().IntSliceVar(&slSlots, "slot", nil, "blade slot number(s), e.g. 3 or 0,1,2 (required for on and off)")
+	slotPowerCmd.Flags().StringVarP(&slFile, "file", "f", "", "inventory whose bmcs[] lists the chassis controller as <chassis>b0")
+	slotPowerCmd.Flags().StringVar(&slCMMHost, "cmm-host", "", "address of the chassis controller (overrides --file)")
+	slotPowerCmd.Flags().BoolVar(&slInsecure, "insecure", true, "allow insecure TLS to the chassis controller")
+	slotPowerCmd.Flags().DurationVar(&slTimeout, "timeout", 30*time.Second, "per-request timeout")
+	slotPowerCmd.Flags().DurationVar(&slWait, "wait", 0, "after on/off, wait up to this long for the slots to report the new power state (0 = don't wait)")
+	slotPowerCmd.Flags().StringSliceVar(&slCDUHosts, "cdu-host", nil, "Redfish address(es) of coolant distribution units whose sensors are also checked before power on")
+	slotPowerCmd.Flags().Float64Var(&slLimits.MinCoolantC, "min-coolant-temp", 0, "refuse power on below this coolant temperature in °C (0 = only the sensors' own thresholds)")
+	slotPowerCmd.Flags().Float64Var(&slLimits.MaxCoolantC, "max-coolant-temp", 0, "refuse power on above this coolant temperature in °C (0 = only the sensors' own thresholds)")
+	slotPowerCmd.Flags().BoolVar(&slOverride, "override", false, "power on even when the environmental preflight fails")
+	slotPowerCmd.Flags().BoolVar(&slDryRun, "dry-run", false, "print the slots that would be powered and exit")
+}
@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"bootstrap/internal/envcheck"
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+)
+
+func TestSlotPower(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	inv := filepath.Join(t.TempDir(), "inventory.yaml")
+	if err := os.WriteFile(inv, []byte("bmcs:\n  - xname: x9000c1b0\n    ip: 10.1.0.100\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	power := map[string]string{"Blade0": "Off", "Blade1": "Off", "Blade3": "Off"}
+	var resets []string
+	cmm := &redfishtest.MockClient{
+		GetChassisFunc: func(context.Context) ([]redfish.Chassis, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			out := []redfish.Chassis{{Resource: redfish.Resource{ID: "Enclosure"}, ChassisType: "Enclosure"}}
+			for _, id := range []string{"Blade0", "Blade1", "Blade3"} {
+				out = append(out, redfish.Chassis{
+					Resource:    redfish.Resource{ID: id, ODataID: "/redfish/v1/Chassis/" + id},
+					ChassisType: "Blade",
+					PowerState:  power[id],
+				})
+			}
+			return out, nil
+		},
+		GetEnvironmentFunc: func(context.Context) (redfish.Environment, error) {
+			return redfish.Environment{}, nil
+		},
+		ResetChassisFunc: func(_ context.Context, path, resetType string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			resets = append(resets, path+" "+resetType)
+			if path == "/redfish/v1/Chassis/Blade3" && resetType == "On" {
+				power["Blade3"] = "On"
+			}
+			return nil
+		},
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"10.1.0.100": cmm})
+	oldPoll := slotPollInterval
+	slotPollInterval = time.Millisecond
+	defer func() {
+		slotPollInterval = oldPoll
+		slChassis, slFile, slSlots, slWait = "", "", nil, 0
+	}()
+	slotPowerCmd.SetContext(context.Background())
+
+	// Slot 3 reaches On; slot 2 does not exist
+	slChassis, slFile, slSlots, slWait = "X9000C1", inv, []int{3, 2}, 50*time.Millisecond
+	if got := exitCode(slotPowerCmd.RunE(slotPowerCmd, []string{"on"})); got != exitPartial {
+		t.Errorf("on: exit code %d, want %d", got, exitPartial)
+	}
+	if want := []string{"/redfish/v1/Chassis/Blade3 On"}; !reflect.DeepEqual(resets, want) {
+		t.Errorf("resets = %v, want %v", resets, want)
+	}
+
+	// Slot 1 stays Off, so --wait gives up on it
+	slSlots = []int{1}
+	if err := slotPowerCmd.RunE(slotPowerCmd, []string{"on"}); err == nil {
+		t.Error("on: want an error for a slot that stays Off")
+	}
+
+	slSlots, slWait = nil, 0
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := slotPowerCmd.RunE(slotPowerCmd, []string{"status"})
+	w.Close() //nolint:errcheck
+	os.Stdout = old
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	b, _ := io.ReadAll(r)
+	if want := "x9000c1s0: Off\nx9000c1s1: Off\nx9000c1s3: On\n"; string(b) != want {
+		t.Errorf("status output = %q, want %q", b, want)
+	}
+
+	slSlots = nil
+	if got := exitCode(slotPowerCmd.RunE(slotPowerCmd, []string{"off"})); got != exitInvalid {
+		t.Errorf("off without --slot: exit code %d, want %d", got, exitInvalid)
+	}
+}
+
+func TestSlotPowerPreflight(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	temp := func(v float64) *float64 { return &v }
+	var resets int
+	cmm := &redfishtest.MockClient{
+		GetChassisFunc: func(context.Context) ([]redfish.Chassis, error) {
+			return []redfish.Chassis{{Resource: redfish.Resource{ID: "Blade0", ODataID: "/redfish/v1/Chassis/Blade0"}}}, nil
+		},
+		GetEnvironmentFunc: func(context.Context) (redfish.Environment, error) {
+			s := redfish.Sensor{ReadingType: "Temperature", PhysicalContext: "LiquidInlet", Reading: temp(31), ReadingUnits: "Cel"}
+			s.ID = "CoolantSupply"
+			return redfish.Environment{Sensors: []redfish.EnvSensor{{Chassis: "Enclosure", Sensor: s}}}, nil
+		},
+		ResetChassisFunc: func(context.Context, string, string) error {
+			resets++
+			return nil
+		},
+	}
+	cdu := &redfishtest.MockClient{
+		GetEnvironmentFunc: func(context.Context) (redfish.Environment, error) {
+			d := redfish.LeakDetector{DetectorState: "OK"}
+			d.ID = "Tray1"
+			return redfish.Environment{LeakDetectors: []redfish.EnvLeakDetector{{Chassis: "CDU", LeakDetector: d}}}, nil
+		},
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"cmm": cmm, "cdu": cdu})
+	defer func() {
+		slChassis, slCMMHost, slSlots, slCDUHosts, slOverride, slLimits = "", "", nil, nil, false, envcheck.Limits{}
+	}()
+	slotPowerCmd.SetContext(context.Background())
+	slChassis, slCMMHost, slSlots, slCDUHosts = "x9000c1", "cmm", []int{0}, []string{"cdu"}
+
+	// Coolant within limits
+	slLimits.MaxCoolantC = 35
+	if err := slotPowerCmd.RunE(slotPowerCmd, []string{"on"}); err != nil || resets != 1 {
+		t.Fatalf("within limits: err %v, %d reset(s)", err, resets)
+	}
+
+	// Too warm: refused, unless overridden
+	slLimits.MaxCoolantC = 30
+	if err := slotPowerCmd.RunE(slotPowerCmd, []string{"on"}); err == nil || resets != 1 {
+		t.Errorf("too warm: err %v, %d reset(s); want refusal", err, resets)
+	}
+	slOverride = true
+	if err := slotPowerCmd.RunE(slotPowerCmd, []string{"on"}); err != nil || resets != 2 {
+		t.Errorf("override: err %v, %d reset(s)", err, resets)
+	}
+	if len(cdu.Calls) != 3 {
+		t.Errorf("CDU read %d time(s), want 3", len(cdu.Calls))
+	}
+}
@@ -0,0 +1,263 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"bootstrap/internal/snapshot"
+	"bootstrap/pkg/inventory"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	snFile      string
+	snHostsCSV  string
+	snHostsFile string
+	snOut       string
+	snInsecure  bool
+	snTimeout   time.Duration
+	snBatchSize int
+	snFailedOut string
+
+	rsSnapshot  string
+	rsHostsCSV  string
+	rsHostsFile string
+	rsOnly      []string
+	rsInsecure  bool
+	rsTimeout   time.Duration
+	rsBatchSize int
+	rsDryRun    bool
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Record the configuration of BMCs to a timestamped directory",
+	Long: `Read each BMC's network protocol settings, NTP servers, SSH authorized keys,
+accounts (names, roles and enabled state; never passwords), firmware versions and,
+per system, boot settings and BIOS attributes, and write them to one YAML file per
+BMC under --out (default snapshot-<UTC timestamp>). Take one before an invasive
+rollout; restore re-applies the settings that can be written back.`,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if snFile == "" && snHostsCSV == "" && snHostsFile == "" {
+			return invalidf("at least one of --file, --hosts or --hosts-file is required")
+		}
+		hosts, err := resolveHosts(snFile, snHostsCSV, snHostsFile)
+		if err != nil {
+			return err
+		}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		dir := snOut
+		if dir == "" {
+			dir = "snapshot-" + now.UTC().Format("20060102T150405Z")
+		}
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		var mu sync.Mutex
+		failed := map[string]error{}
+		partial := 0
+		forEachHost(ctx, hosts, snBatchSize, func(ctx context.Context, h string) {
+			if snTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, snTimeout)
+				defer cancel()
+			}
+			c := newRedfishClient(h, user, pass, snInsecure, snTimeout)
+			s, err := snapshot.Take(ctx, c, h, now)
+			if err == nil {
+				err = snapshot.Save(dir, s)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", hostName(h), err)
+				failed[h] = err
+				return
+			}
+			for _, area := range slices.Sorted(maps.Keys(s.Errors)) {
+				fmt.Fprintf(os.Stderr, "WARN: %s: %s not recorded: %s\n", hostName(h), area, s.Errors[area])
+			}
+			if len(s.Errors) > 0 {
+				partial++
+			}
+		}, func(string) {})
+
+		if snFailedOut != "" {
+			if err := writeFailedHosts(snFailedOut, hosts, failed); err != nil {
+				return err
+			}
+		}
+		fmt.Println("Snapshot summary:")
+		fmt.Printf("  directory: %s\n", dir)
+		fmt.Printf("  hosts: %d\n", len(hosts))
+		fmt.Printf("  recorded: %d\n", len(hosts)-len(failed))
+		fmt.Printf("  partial: %d\n", partial)
+		fmt.Printf("  failed: %d\n", len(failed))
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+		return hostFailures(len(hosts), failed)
+	},
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Re-apply the settings recorded by snapshot",
+	Long: `Re-apply the supported settings of a snapshot directory to its BMCs: SSH
+authorized keys (replaced with the recorded set), NTP servers and BIOS attributes
+(staged for the next boot of each system). Accounts, firmware versions, protocol
+enablement and boot settings are recorded for reference only and are not changed.
+Use --hosts or --hosts-file to restore a subset and --only to limit the areas.`,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if rsSnapshot == "" {
+			return invalidf("--snapshot is required")
+		}
+		for _, a := range rsOnly {
+			if !slices.Contains(snapshot.Restorable, a) {
+				return invalidf("--only: unknown area %q (use %s)", a, strings.Join(snapshot.Restorable, ", "))
+			}
+		}
+		recorded, err := snapshot.Load(rsSnapshot)
+		if err != nil {
+			return invalid(err)
+		}
+		byHost := map[string]snapshot.Host{}
+		var hosts []string
+		for _, h := range recorded {
+			byHost[h.Host] = h
+			hosts = append(hosts, h.Host)
+		}
+		if rsHostsCSV != "" || rsHostsFile != "" {
+			sel, err := hostList(rsHostsCSV, rsHostsFile, inventory.FileFormat{})
+			if err != nil {
+				return err
+			}
+			for _, h := range sel {
+				if _, ok := byHost[h]; !ok {
+					return invalidf("%s is not in snapshot %s", h, rsSnapshot)
+				}
+			}
+			hosts = sel
+		}
+
+		plans := map[string][]snapshot.Step{}
+		for _, h := range hosts {
+			plans[h] = snapshot.Plan(byHost[h], rsOnly)
+			for area := range byHost[h].Errors {
+				if slices.Contains(snapshot.Restorable, area) {
+					fmt.Fprintf(os.Stderr, "WARN: %s: %s was not recorded and is skipped\n", hostName(h), area)
+				}
+			}
+		}
+		if rsDryRun {
+			for _, h := range hosts {
+				for _, st := range plans[h] {
+					fmt.Printf("[dry-run] would restore %s: %s %s(%s)\n", hostName(h), st.Area, stepTarget(st.Target), st.Detail)
+				}
+			}
+			return nil
+		}
+		if stop, err := needsApproval(cmd, args, "restore", hosts); stop || err != nil {
+			return err
+		}
+		user, pass, err := redfishCreds()
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+		launch, stopLaunch := untilWindowCloses(ctx)
+		defer stopLaunch()
+		var mu sync.Mutex
+		failed := map[string]error{}
+		var aborted, notStarted []string
+		restored := 0
+		forEachHost(launch, hosts, rsBatchSize, func(_ context.Context, h string) {
+			ctx := ctx // hosts in flight finish when the maintenance window closes
+			if rsTimeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, rsTimeout)
+				defer cancel()
+			}
+			c := withLedger(newRedfishClient(h, user, pass, rsInsecure, rsTimeout), h)
+			err := snapshot.Restore(ctx, c, byHost[h], plans[h])
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s: %v\n", hostName(h), err)
+				failed[h] = err
+				if errors.Is(err, context.Canceled) {
+					aborted = append(aborted, h)
+				}
+				return
+			}
+			restored += len(plans[h])
+			fmt.Printf("%s: restored %d setting(s)\n", hostName(h), len(plans[h]))
+		}, func(h string) {
+			mu.Lock()
+			defer mu.Unlock()
+			notStarted = append(notStarted, h)
+		})
+
+		fmt.Println("Restore summary:")
+		fmt.Printf("  hosts: %d\n", len(hosts))
+		fmt.Printf("  settings restored: %d\n", restored)
+		fmt.Printf("  failed: %d\n", len(failed))
+		if ctx.Err() != nil {
+			aborted = append(aborted, notStarted...)
+			printInterrupted(len(hosts)-len(aborted), aborted)
+			return errInterrupted
+		}
+		if stopped := launchStopped(ctx, launch); stopped != nil {
+			return reportStopped(stopped, hosts, notStarted, failed)
+		}
+		return hostFailures(len(hosts), failed)
+	},
+}
+
+// stepTarget formats a restore step target for display, or nothing when empty.
+func stepTarget(t string) string {
+	if t == "" {
+		return ""
+	}
+	return t + " "
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd, restoreCmd)
+	snapshotCmd.Flags().StringVarP(&snFile, "file", "f", "", "Inventory file to read bmcs[] from when --hosts is not provided")
+	snapshotCmd.Flags().StringVar(&snHostsCSV, "hosts", "", "Comma-separated list of BMC hosts to snapshot (overrides --file)")
+	snapshotCmd.Flags().StringVar(&snHostsFile, "hosts-file", "", "file listing BMC hosts or bmcs[] xnames to snapshot, one per line (overrides --file)")
+	snapshotCmd.Flags().StringVar(&snOut, "out", "", "directory to write the snapshot to (default snapshot-<UTC timestamp>)")
+	snapshotCmd.Flags().BoolVar(&snInsecure, "insecure", true, "allow insecure TLS to BMCs")
+	snapshotCmd.Flags().DurationVar(&snTimeout, "timeout", 2*time.Minute, "per-BMC timeout")
+	snapshotCmd.Flags().IntVar(&snBatchSize, "batch-size", 10, "number of BMCs to read concurrently")
+	snapshotCmd.Flags().StringVar(&snFailedOut, "failed-hosts-out", "", "write the hosts that failed, with the reason, to this file (usable as --hosts-file)")
+
+	restoreCmd.Flags().StringVar(&rsSnapshot, "snapshot", "", "snapshot directory written by the snapshot command")
+	restoreCmd.Flags().StringVar(&rsHostsCSV, "hosts", "", "Comma-separated list of hosts in the snapshot to restore (default: all)")
+	restoreCmd.Flags().StringVar(&rsHostsFile, "hosts-file", "", "file listing the hosts in the snapshot to restore, one per line")
+	restoreCmd.Flags().StringSliceVar(&rsOnly, "only", nil, "restore only these areas: ssh-keys, network, bios (default: all)")
+	restoreCmd.Flags().BoolVar(&rsInsecure, "insecure", true, "allow insecure TLS to BMCs")
+	restoreCmd.Flags().DurationVar(&rsTimeout, "timeout", 2*time.Minute, "per-BMC timeout")
+	restoreCmd.Flags().IntVar(&rsBatchSize, "batch-size", 10, "number of BMCs to restore concurrently")
+	restoreCmd.Flags().BoolVar(&rsDryRun, "dry-run", false, "print the settings that would be restored without changing anything")
+}
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	t.Setenv("REDFISH_USER", "testuser")
+	t.Setenv("REDFISH_PASSWORD", "testpass")
+	ntp := []string{"10.0.0.1"}
+	good := &redfishtest.MockClient{
+		GetNetworkProtocolFunc: func(context.Context) (redfish.NetworkProtocol, error) {
+			return redfish.NetworkProtocol{NTPServers: ntp}, nil
+		},
+		SetNTPServersFunc: func(_ context.Context, s []string) error { ntp = s; return nil },
+	}
+	down := &redfishtest.MockClient{
+		GetNetworkProtocolFunc: func(context.Context) (redfish.NetworkProtocol, error) {
+			return redfish.NetworkProtocol{}, errors.New("connection refused")
+		},
+	}
+	useMockClients(t, map[string]*redfishtest.MockClient{"a": good, "b": down})
+	dir := filepath.Join(t.TempDir(), "snap")
+	snHostsCSV, snOut, snBatchSize = "a,b", dir, 1
+	defer func() { snHostsCSV, snOut = "", "" }()
+	snapshotCmd.SetContext(context.Background())
+	if got := exitCode(snapshotCmd.RunE(snapshotCmd, nil)); got != exitPartial {
+		t.Fatalf("snapshot: exit code %d, want %d", got, exitPartial)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.yaml")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b.yaml")); !os.IsNotExist(err) {
+		t.Errorf("b.yaml written for an unreachable BMC: %v", err)
+	}
+
+	ntp = []string{"192.0.2.9"}
+	rsSnapshot, rsBatchSize = dir, 1
+	defer func() { rsSnapshot, rsOnly, rsHostsCSV = "", nil, "" }()
+	restoreCmd.SetContext(context.Background())
+	rsOnly = []string{"accounts"}
+	if err := restoreCmd.RunE(restoreCmd, nil); exitCode(err) != exitInvalid {
+		t.Errorf("--only accounts: %v", err)
+	}
+	rsOnly, rsHostsCSV = nil, "b"
+	if err := restoreCmd.RunE(restoreCmd, nil); exitCode(err) != exitInvalid {
+		t.Errorf("host missing from snapshot: %v", err)
+	}
+	rsHostsCSV = ""
+	// restore replaces settings fleet-wide, so it asks first
+	if code := unconfirmed(func() error { return restoreCmd.RunE(restoreCmd, nil) }); code != exitInvalid || ntp[0] != "192.0.2.9" {
+		t.Errorf("unconfirmed restore: exit code %d, ntp %v", code, ntp)
+	}
+	if err := restoreCmd.RunE(restoreCmd, nil); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if !reflect.DeepEqual(ntp, []string{"10.0.0.1"}) {
+		t.Errorf("ntp after restore = %v", ntp)
+	}
+
+	// An interrupted restore names the hosts it did not restore
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	restoreCmd.SetContext(ctx)
+	oldStderr := os.Stderr
+	r, w, _ := os.Pipe()
+	os.Stderr = w
+	err := restoreCmd.RunE(restoreCmd, nil)
+	w.Close() //nolint:errcheck
+	os.Stderr = oldStderr
+	out, _ := io.ReadAll(r)
+	if !errors.Is(err, errInterrupted) || !strings.Contains(string(out), "0 host(s) completed, 1 aborted") || !strings.Contains(string(out), "Aborted: a") {
+		t.Errorf("interrupted restore: %v\n%s", err, out)
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"bootstrap/pkg/redfish"
+)
+
+// statsTop is how many of the slowest BMCs --stats lists; BMCs with errors are
+// listed as well.
+const statsTop = 10
+
+// printRequestStats writes the Redfish request stats recorded since
+// redfish.EnableStats: totals, then the slowest BMCs and those with errors.
+func printRequestStats(w io.Writer) {
+	hosts := redfish.RequestStats()
+	if len(hosts) == 0 {
+		fmt.Fprintln(w, "Request stats: no Redfish requests made")
+		return
+	}
+	requests, errs := 0, 0
+	for _, h := range hosts {
+		requests += h.Requests
+		errs += h.Errors
+	}
+	fmt.Fprintf(w, "Request stats: %d request(s) to %d BMC(s), %d error(s)\n", requests, len(hosts), errs)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  BMC\tREQUESTS\tP50\tP95\tMAX\tERRORS")
+	for i, h := range hosts {
+		if i >= statsTop && h.Errors == 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "  %s\t%d\t%s\t%s\t%s\t%s\n", hostName(h.Host), h.Requests, h.P50.Round(time.Millisecond), h.P95.Round(time.Millisecond), h.Max.Round(time.Millisecond), statsErrors(h))
+	}
+	tw.Flush() //nolint:errcheck
+	if len(hosts) > statsTop {
+		if n := countQuiet(hosts[statsTop:]); n > 0 {
+			fmt.Fprintf(w, "  (%d faster BMC(s) without errors not shown)\n", n)
+		}
+	}
+}
+
+// statsErrors is the error count of h with its statuses, e.g. "3 (503x2, transport x1)".
+func statsErrors(h redfish.HostStats) string {
+	if h.Errors == 0 {
+		return "0"
+	}
+	var parts []string
+	for _, code := range slices.Sorted(maps.Keys(h.Statuses)) {
+		switch {
+		case code == 0:
+			parts = append(parts, fmt.Sprintf("transport x%d", h.Statuses[code]))
+		case code >= 300:
+			parts = append(parts, fmt.Sprintf("%dx%d", code, h.Statuses[code]))
+		}
+	}
+	return fmt.Sprintf("%d (%s)", h.Errors, strings.Join(parts, ", "))
+}
+
+func countQuiet(hosts []redfish.HostStats) int {
+	n := 0
+	for _, h := range hosts {
+		if h.Errors == 0 {
+			n++
+		}
+	}
+	return n
+}
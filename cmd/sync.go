@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"bootstrap/internal/smd"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	syFile    string
+	sySMDURL  string
+	syBMCs    bool
+	syTimeout time.Duration
+	syDryRun  bool
+	syFormat  string
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Bring other services in line with the inventory",
+}
+
+var syncSMDEthsCmd = &cobra.Command{
+	Use:   "smd-eths",
+	Short: "Create missing SMD EthernetInterfaces from the inventory and report conflicts",
+	Long: `Compare the MAC and IP of every nodes[] entry of --file (and bmcs[] with --bmcs)
+with SMD's EthernetInterfaces, for deployments where DHCP is served from SMD.
+
+MACs SMD lacks are created with the component and IP, and entries SMD has
+without an IP get the inventory's. SMD is not changed where it disagrees:
+a MAC with another IP, a MAC of another component or an IP held by another MAC
+is reported as a conflict, and the command then exits 2.
+
+The bearer token is read from ACCESS_TOKEN.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if syFile == "" {
+			return invalidf("--file is required")
+		}
+		if sySMDURL == "" {
+			return invalidf("--smd-url is required")
+		}
+		if syFormat != "" && !strings.EqualFold(syFormat, "json") {
+			return invalidf("--format must be json when set")
+		}
+		doc, err := readInventory(syFile)
+		if err != nil {
+			return err
+		}
+		var bindings []smd.Binding
+		entries := doc.Nodes
+		if syBMCs {
+			entries = append(doc.BMCs, entries...)
+		}
+		for _, e := range entries {
+			if e.MAC != "" {
+				bindings = append(bindings, smd.Binding{ComponentID: e.Xname, MAC: e.MAC, IP: e.IP})
+			}
+		}
+		if len(bindings) == 0 {
+			return invalidf("%s: no entries with a MAC", syFile)
+		}
+
+		token, err := secret("ACCESS_TOKEN")
+		if err != nil {
+			return err
+		}
+		ctx := cmd.Context()
+		sc := smd.New(sySMDURL, token, syTimeout)
+		have, err := sc.EthernetInterfaces(ctx)
+		if err != nil {
+			return fmt.Errorf("read SMD EthernetInterfaces: %w", err)
+		}
+		items := smd.Compare(bindings, have)
+		if !syDryRun {
+			for _, it := range items {
+				var ips []smd.IPAddress
+				if it.IP != "" {
+					ips = []smd.IPAddress{{IPAddress: it.IP}}
+				}
+				switch it.Kind {
+				case smd.Create:
+					err = sc.CreateEthernetInterface(ctx, smd.EthernetInterface{MACAddress: it.MAC, ComponentID: it.ComponentID, IPAddresses: ips})
+				case smd.AddIP:
+					err = sc.SetIPAddresses(ctx, it.MAC, ips)
+				}
+				if err != nil {
+					return fmt.Errorf("%s (%s): %w", it.ComponentID, it.MAC, err)
+				}
+			}
+		}
+
+		conflicts, counts := 0, map[string]int{}
+		for _, it := range items {
+			counts[it.Kind]++
+			if it.Conflict() {
+				conflicts++
+			}
+		}
+		if strings.EqualFold(syFormat, "json") {
+			if err := printJSON(items); err != nil {
+				return err
+			}
+		} else {
+			printSMDSync(items, counts)
+		}
+		if conflicts > 0 {
+			return &exitError{code: exitPartial, err: fmt.Errorf("%d conflict(s) with SMD", conflicts)}
+		}
+		return nil
+	},
+}
+
+func printSMDSync(items []smd.SyncItem, counts map[string]int) {
+	prefix := ""
+	if syDryRun {
+		prefix = "[dry-run] "
+	}
+	for _, it := range items {
+		switch it.Kind {
+		case smd.Create:
+			fmt.Printf("%screate %s %s ip %s\n", prefix, it.ComponentID, it.MAC, orNone(it.IP))
+		case smd.AddIP:
+			fmt.Printf("%sadd ip %s to %s %s\n", prefix, it.IP, it.ComponentID, it.MAC)
+		case smd.IPDiffer:
+			fmt.Printf("CONFLICT %s %s: ip %s in the inventory, %s in SMD\n", it.ComponentID, it.MAC, it.IP, it.SMD)
+		case smd.Owner:
+			fmt.Printf("CONFLICT %s %s: SMD has the MAC for %s\n", it.ComponentID, it.MAC, it.SMD)
+		case smd.IPInUse:
+			fmt.Printf("CONFLICT %s %s: SMD has ip %s for %s\n", it.ComponentID, it.MAC, it.IP, it.SMD)
+		}
+	}
+	fmt.Println("SMD EthernetInterfaces sync summary:")
+	fmt.Printf("  in sync: %d\n", counts[smd.InSync])
+	fmt.Printf("  created: %d\n", counts[smd.Create])
+	fmt.Printf("  IPs added: %d\n", counts[smd.AddIP])
+	fmt.Printf("  conflicts: %d\n", counts[smd.IPDiffer]+counts[smd.Owner]+counts[smd.IPInUse])
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.AddCommand(syncSMDEthsCmd)
+	syncSMDEthsCmd.Flags().StringVarP(&syFile, "file", "f", "", "inventory whose nodes[] MACs and IPs are synced")
+	syncSMDEthsCmd.Flags().StringVar(&sySMDURL, "smd-url", "", "base URL of SMD, e.g. https://smd.example:27779")
+	syncSMDEthsCmd.Flags().BoolVar(&syBMCs, "bmcs", false, "sync bmcs[] too")
+	syncSMDEthsCmd.Flags().DurationVar(&syTimeout, "timeout", 30*time.Second, "time limit for each request to SMD")
+	syncSMDEthsCmd.Flags().BoolVar(&syDryRun, "dry-run", false, "plan only: print the changes and conflicts without writing to SMD")
+	syncSMDEthsCmd.Flags().StringVar(&syFormat, "format", "", "output format: json")
+}
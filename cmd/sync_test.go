@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSyncSMDEths(t *testing.T) {
+	var writes []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `[{"MACAddress": "aa:00:00:00:00:01", "ComponentID": "x1000c0s0b0n0", "IPAddresses": [{"IPAddress": "10.2.0.1"}]},
+				{"MACAddress": "aa:00:00:00:00:02", "ComponentID": "x1000c0s0b0n1", "IPAddresses": [{"IPAddress": "10.2.0.99"}]}]`)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		writes = append(writes, r.Method+" "+r.URL.Path+" "+string(body))
+	}))
+	defer srv.Close()
+	t.Setenv("ACCESS_TOKEN", "tok")
+	file := filepath.Join(t.TempDir(), "inventory.yaml")
+	if err := os.WriteFile(file, []byte("bmcs:\n  - {xname: x1000c0s0b0, mac: 02:00:00:00:00:01, ip: 10.1.0.1}\nnodes:\n"+
+		"  - {xname: x1000c0s0b0n0, mac: aa:00:00:00:00:01, ip: 10.2.0.1}\n"+
+		"  - {xname: x1000c0s0b0n1, mac: aa:00:00:00:00:02, ip: 10.2.0.2}\n"+
+		"  - {xname: x1000c0s1b0n0, mac: AA:00:00:00:00:03, ip: 10.2.0.3}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	syFile, sySMDURL, syDryRun = file, srv.URL, true
+	defer func() { syFile, sySMDURL, syDryRun = "", "", false }()
+
+	cmd := syncSMDEthsCmd
+	cmd.SetContext(context.Background())
+	if err := cmd.RunE(cmd, nil); exitCode(err) != exitPartial {
+		t.Errorf("conflict: err = %v, want exit %d", err, exitPartial)
+	}
+	if len(writes) != 0 {
+		t.Errorf("dry run wrote %q", writes)
+	}
+	syDryRun = false
+	_ = cmd.RunE(cmd, nil)
+	want := []string{`POST /hsm/v2/Inventory/EthernetInterfaces {"MACAddress":"aa:00:00:00:00:03","ComponentID":"x1000c0s1b0n0","IPAddresses":[{"IPAddress":"10.2.0.3"}]}`}
+	if !slices.Equal(writes, want) {
+		t.Errorf("writes = %q, want %q", writes, want)
+	}
+}
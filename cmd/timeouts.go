@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// timeouts holds the three deadlines of a multi-host Redfish command. Zero
+// disables a limit.
+type timeouts struct {
+	Request time.Duration // each HTTP request to a BMC
+	Host    time.Duration // all work against one BMC
+	Total   time.Duration // the whole command
+}
+
+// addFlags registers --request-timeout, --host-timeout and --total-timeout, plus
+// the deprecated --timeout which sets both the request and host limits.
+func (t *timeouts) addFlags(fs *pflag.FlagSet, request, host time.Duration) {
+	fs.DurationVar(&t.Request, "request-timeout", request, "timeout for each Redfish HTTP request (0 = none)")
+	fs.DurationVar(&t.Host, "host-timeout", host, "deadline for all requests to one BMC (0 = none)")
+	fs.DurationVar(&t.Total, "total-timeout", 0, "deadline for the whole command; hosts not finished by then are reported as failed (0 = none)")
+	fs.Var(legacyTimeout{t}, "timeout", "sets both --request-timeout and --host-timeout")
+	_ = fs.MarkDeprecated("timeout", "use --request-timeout and --host-timeout")
+}
+
+// forHost bounds ctx by the per-host deadline.
+func (t timeouts) forHost(ctx context.Context) (context.Context, context.CancelFunc) {
+	if t.Host > 0 {
+		return context.WithTimeout(ctx, t.Host)
+	}
+	return context.WithCancel(ctx)
+}
+
+// forCommand bounds ctx by the overall command deadline.
+func (t timeouts) forCommand(ctx context.Context) (context.Context, context.CancelFunc) {
+	if t.Total > 0 {
+		return context.WithTimeout(ctx, t.Total)
+	}
+	return context.WithCancel(ctx)
+}
+
+// stopped returns the error for a command whose ctx ended before all hosts were
+// done: errInterrupted after SIGINT/SIGTERM, or a partial failure when the
+// --total-timeout deadline passed.
+func (t timeouts) stopped(ctx context.Context) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &exitError{code: exitPartial, err: fmt.Errorf("total timeout %s exceeded", t.Total)}
+	}
+	return errInterrupted
+}
+
+// legacyTimeout implements the deprecated --timeout flag.
+type legacyTimeout struct{ t *timeouts }
+
+func (l legacyTimeout) String() string {
+	if l.t == nil {
+		return "0s"
+	}
+	return l.t.Request.String()
+}
+
+func (l legacyTimeout) Set(s string) error {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	l.t.Request, l.t.Host = d, d
+	return nil
+}
+
+func (legacyTimeout) Type() string { return "duration" }
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"strings"
+
+	"bootstrap/internal/topology"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	topoFile          string
+	topoNodesPerBlade int
+)
+
+var topologyCmd = &cobra.Command{
+	Use:   "topology",
+	Short: "Inspect the cabinet, chassis, slot, blade and node layout of an inventory",
+}
+
+var topologyShowCmd = &cobra.Command{
+	Use:   "show [XNAME...]",
+	Short: "Draw the inventory as a cabinet→chassis→slot→blade→node tree",
+	Long: `Arrange the bmcs[] (blades) and nodes[] of --file by xname and draw the tree,
+or the parts of it below the given xnames. With --nodes-per-blade, blades holding
+another number of nodes are listed and the command exits with status 2.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if topoFile == "" {
+			return invalidf("--file is required")
+		}
+		doc, err := readInventory(topoFile)
+		if err != nil {
+			return err
+		}
+		topo := topology.Build(doc)
+		var roots []*topology.Component
+		for _, x := range args {
+			c := topo.Find(x)
+			if c == nil {
+				return invalidf("%s: not in the topology of %s", x, topoFile)
+			}
+			roots = append(roots, c)
+		}
+		if err := topo.Write(os.Stdout, roots); err != nil {
+			return err
+		}
+		if len(args) == 0 && len(topo.Unplaced) > 0 {
+			fmt.Printf("Not placed (xname is not a blade or node):\n")
+			for _, e := range topo.Unplaced {
+				fmt.Printf("  %s %s\n", cmp.Or(e.Xname, "(no xname)"), e.IP)
+			}
+		}
+		if topoNodesPerBlade <= 0 {
+			return nil
+		}
+		var problems []topology.Mismatch
+		for _, m := range topo.CheckNodes(topoNodesPerBlade, nil) {
+			if len(args) == 0 || underAny(m.Blade, args) {
+				problems = append(problems, m)
+			}
+		}
+		for _, m := range problems {
+			fmt.Fprintf(os.Stderr, "MISMATCH: %s\n", m)
+		}
+		if len(problems) > 0 {
+			return &exitError{code: exitPartial, err: fmt.Errorf("%d blade(s) do not hold %d node(s)", len(problems), topoNodesPerBlade)}
+		}
+		return nil
+	},
+}
+
+// underAny reports whether the xname x lies at or below one of xnames.
+func underAny(x string, xnames []string) bool {
+	for _, p := range xnames {
+		if strings.HasPrefix(strings.ToLower(x), strings.ToLower(p)) && (len(x) == len(p) || !isDigit(x[len(p)])) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDigit(b byte) bool { return b >= '0' && b <= '9' }
+
+func init() {
+	rootCmd.AddCommand(topologyCmd)
+	topologyCmd.AddCommand(topologyShowCmd)
+	topologyShowCmd.Flags().StringVarP(&topoFile, "file", "f", "", "inventory to draw (YAML file, or .db/.bolt database)")
+	topologyShowCmd.Flags().IntVar(&topoNodesPerBlade, "nodes-per-blade", 0, "report blades that do not hold this many nodes (0 = no check)")
+}
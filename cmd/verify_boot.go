@@ -0,0 +1,246 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"bootstrap/internal/bootcheck"
+	"bootstrap/internal/junit"
+	"bootstrap/internal/netdial"
+	"bootstrap/pkg/inventory"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+)
+
+var (
+	vbFile            string
+	vbNodes           string
+	vbPing            bool
+	vbPorts           []int
+	vbSSHUser         string
+	vbSSHKey          string
+	vbSSHCommand      string
+	vbExpect          string
+	vbKnownHosts      string
+	vbInsecureHostKey bool
+	vbTimeout         time.Duration
+	vbWait            time.Duration
+	vbInterval        time.Duration
+	vbBatchSize       int
+	vbFormat          string
+)
+
+// newPinger returns the ping check of verify-boot. Tests replace it.
+var newPinger = bootcheck.ExecPing
+
+var verifyBootCmd = &cobra.Command{
+	Use:   "verify-boot",
+	Short: "Check that provisioned nodes answer on the IPs allocated to them",
+	Long: `After PXE provisioning, check every nodes[] entry of --file that has an IP (or
+those named by --nodes): it must answer ping, accept TCP connections on --port
+and, with --ssh-command, run that command over SSH as --ssh-user. --expect is a
+regular expression the command output must match, e.g. the image version in
+/etc/os-release.
+
+Nodes are reached directly, not through --proxy or --ssh-jump. With --wait,
+failing nodes are checked again every --interval until they pass or the time
+is up.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if vbFile == "" {
+			return invalidf("--file is required")
+		}
+		if vbFormat != "" && vbFormat != "json" && vbFormat != "junit" {
+			return invalidf("--format must be json or junit when set")
+		}
+		if vbSSHCommand != "" && vbSSHUser == "" {
+			return invalidf("--ssh-command needs --ssh-user")
+		}
+		if vbExpect != "" && vbSSHCommand == "" {
+			return invalidf("--expect needs --ssh-command")
+		}
+		doc, err := readInventory(vbFile)
+		if err != nil {
+			return err
+		}
+		nodes, err := nodeTargets(doc, vbFile, vbNodes, true)
+		if err != nil {
+			return err
+		}
+		checker := &bootcheck.Checker{Ports: vbPorts, Command: vbSSHCommand}
+		if vbPing {
+			checker.Ping = newPinger(vbTimeout)
+		}
+		if vbExpect != "" {
+			if checker.Expect, err = regexp.Compile(vbExpect); err != nil {
+				return invalidf("--expect: %w", err)
+			}
+		}
+		if vbSSHCommand != "" {
+			hostKeys := ssh.InsecureIgnoreHostKey() //nolint:gosec // only with --insecure-host-key
+			if !vbInsecureHostKey {
+				if hostKeys, err = netdial.KnownHosts(vbKnownHosts); err != nil {
+					return invalid(err)
+				}
+			}
+			if checker.SSH, err = netdial.SSHConfig(vbSSHUser, vbSSHKey, hostKeys); err != nil {
+				return invalid(err)
+			}
+			checker.SSH.Timeout = vbTimeout
+		}
+
+		ctx := cmd.Context()
+		start := time.Now()
+		deadline := time.Now().Add(vbWait)
+		results := map[string]bootcheck.Result{}
+		pending := nodes
+		for {
+			var mu sync.Mutex
+			var retry []inventory.Entry
+			byIP := map[string]inventory.Entry{}
+			ips := make([]string, len(pending))
+			for i, n := range pending {
+				byIP[n.IP], ips[i] = n, n.IP
+			}
+			forEachHost(ctx, ips, vbBatchSize, func(ctx context.Context, ip string) {
+				ctx, cancel := context.WithTimeout(ctx, vbTimeout)
+				defer cancel()
+				n := byIP[ip]
+				r := checker.Check(ctx, n.Xname, ip)
+				mu.Lock()
+				defer mu.Unlock()
+				results[n.Xname] = r
+				if !r.OK() {
+					retry = append(retry, n)
+				}
+			}, func(string) {})
+			if len(retry) == 0 || ctx.Err() != nil || vbWait <= 0 || time.Now().Add(vbInterval).After(deadline) {
+				break
+			}
+			fmt.Fprintf(os.Stderr, "%d of %d node(s) not up yet; checking again in %s\n", len(retry), len(nodes), vbInterval)
+			select {
+			case <-ctx.Done():
+			case <-time.After(vbInterval):
+			}
+			pending = retry
+		}
+
+		failed := map[string]error{}
+		aliases := map[string]string{}
+		ordered := make([]bootcheck.Result, 0, len(nodes))
+		for _, n := range nodes {
+			aliases[n.Xname] = n.Alias
+			r, ok := results[n.Xname]
+			if !ok {
+				continue
+			}
+			ordered = append(ordered, r)
+			for _, s := range r.Steps {
+				if s.Err != nil {
+					failed[n.Xname] = fmt.Errorf("%s: %w", s.Name, s.Err)
+					break
+				}
+			}
+		}
+		if err := printVerifyResults(ordered, aliases, start); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return errInterrupted
+		}
+		return hostFailures(len(nodes), failed)
+	},
+}
+
+// verifyReport is the JSON form of a node's result.
+type verifyReport struct {
+	Xname  string            `json:"xname"`
+	Alias  string            `json:"alias,omitempty"`
+	IP     string            `json:"ip"`
+	OK     bool              `json:"ok"`
+	Checks map[string]string `json:"checks"` // check name -> "ok" or the error
+	Output string            `json:"ssh_output,omitempty"`
+}
+
+func printVerifyResults(results []bootcheck.Result, aliases map[string]string, start time.Time) error {
+	if vbFormat == "junit" {
+		// one test case per node and check
+		suite := junit.Suite{Name: "verify-boot", Time: start, Duration: time.Since(start)}
+		for _, r := range results {
+			for _, s := range r.Steps {
+				c := junit.Case{Class: cmp.Or(aliases[r.Xname], r.Xname), Name: s.Name, Output: s.Output}
+				if s.Err != nil {
+					c.Failure = fmt.Sprintf("%s (%s): %v", s.Name, r.IP, s.Err)
+				}
+				suite.Cases = append(suite.Cases, c)
+			}
+		}
+		return junit.Write(os.Stdout, suite)
+	}
+	if vbFormat == "json" {
+		reports := make([]verifyReport, 0, len(results))
+		for _, r := range results {
+			rep := verifyReport{Xname: r.Xname, Alias: aliases[r.Xname], IP: r.IP, OK: r.OK(), Checks: map[string]string{}}
+			for _, s := range r.Steps {
+				rep.Checks[s.Name] = "ok"
+				if s.Err != nil {
+					rep.Checks[s.Name] = s.Err.Error()
+				}
+				if s.Name == "ssh" {
+					rep.Output = s.Output
+				}
+			}
+			reports = append(reports, rep)
+		}
+		out, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	up := 0
+	for _, r := range results {
+		state := "UP"
+		if r.OK() {
+			up++
+		} else {
+			state = "DOWN"
+		}
+		fmt.Printf("%s %s (%s): %s\n", state, cmp.Or(aliases[r.Xname], r.Xname), r.IP, r.String())
+	}
+	fmt.Println("Boot verification summary:")
+	fmt.Printf("  nodes: %d\n", len(results))
+	fmt.Printf("  up: %d\n", up)
+	fmt.Printf("  down: %d\n", len(results)-up)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(verifyBootCmd)
+	verifyBootCmd.Flags().StringVarP(&vbFile, "file", "f", "", "inventory whose nodes[] IPs are checked")
+	verifyBootCmd.Flags().StringVar(&vbNodes, "nodes", "", "comma-separated node xnames or aliases to check (default: every node with an IP)")
+	verifyBootCmd.Flags().BoolVar(&vbPing, "ping", true, "require an ICMP echo reply (uses the system ping command)")
+	verifyBootCmd.Flags().IntSliceVar(&vbPorts, "port", []int{22}, "TCP port(s) that must accept a connection")
+	verifyBootCmd.Flags().StringVar(&vbSSHUser, "ssh-user", "", "user for --ssh-command")
+	verifyBootCmd.Flags().StringVar(&vbSSHKey, "ssh-key", "", "private key for --ssh-command (default: ssh-agent and ~/.ssh/id_*)")
+	verifyBootCmd.Flags().StringVar(&vbSSHCommand, "ssh-command", "", "command to run on each node over SSH, e.g. 'cat /etc/os-release'")
+	verifyBootCmd.Flags().StringVar(&vbExpect, "expect", "", "regular expression the --ssh-command output must match")
+	verifyBootCmd.Flags().StringVar(&vbKnownHosts, "known-hosts", "", "known_hosts file with the node host keys (default ~/.ssh/known_hosts)")
+	verifyBootCmd.Flags().BoolVar(&vbInsecureHostKey, "insecure-host-key", false, "do not check node SSH host keys (freshly imaged nodes often have new ones)")
+	verifyBootCmd.Flags().DurationVar(&vbTimeout, "timeout", 10*time.Second, "time limit for all checks of one node")
+	verifyBootCmd.Flags().DurationVar(&vbWait, "wait", 0, "keep re-checking failing nodes for up to this long (0 = check once)")
+	verifyBootCmd.Flags().DurationVar(&vbInterval, "interval", 15*time.Second, "pause between rounds with --wait")
+	verifyBootCmd.Flags().IntVar(&vbBatchSize, "batch-size", 20, "number of nodes to check concurrently (0 or 1 = serial)")
+	verifyBootCmd.Flags().StringVar(&vbFormat, "format", "", "output format: json or junit (one test case per node and check, for CI) (default: text)")
+}
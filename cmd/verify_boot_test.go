@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyBoot(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close() //nolint:errcheck
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	inv := filepath.Join(t.TempDir(), "inventory.yaml")
+	doc := `nodes:
+  - xname: x1000c0s0b0n0
+    alias: nid000001
+    ip: 127.0.0.1
+  - xname: x1000c0s0b0n1
+    ip: 127.0.0.2
+  - xname: x1000c0s1b0n0
+`
+	if err := os.WriteFile(inv, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	oldPinger := newPinger
+	newPinger = func(time.Duration) func(context.Context, string) error {
+		return func(_ context.Context, ip string) error {
+			if ip == "127.0.0.2" {
+				return errors.New("no reply")
+			}
+			return nil
+		}
+	}
+	t.Cleanup(func() {
+		newPinger = oldPinger
+		vbFile, vbNodes, vbPing, vbPorts, vbTimeout, vbWait, vbFormat = "", "", true, []int{22}, 10*time.Second, 0, ""
+	})
+	vbFile, vbPing, vbPorts, vbTimeout = inv, true, []int{port}, 5*time.Second
+	verifyBootCmd.SetContext(context.Background())
+
+	run := func() (string, error) {
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := verifyBootCmd.RunE(verifyBootCmd, nil)
+		w.Close() //nolint:errcheck
+		os.Stdout = old
+		out, _ := io.ReadAll(r)
+		return string(out), err
+	}
+
+	out, err := run()
+	if got := exitCode(err); got != exitPartial {
+		t.Errorf("exit code %d, want %d", got, exitPartial)
+	}
+	for _, want := range []string{
+		"UP nid000001 (127.0.0.1): ping ok, tcp/",
+		"DOWN x1000c0s0b0n1 (127.0.0.2): ping failed: no reply, tcp/",
+		"  up: 1\n  down: 1\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "x1000c0s1b0n0") {
+		t.Errorf("node without IP was checked:\n%s", out)
+	}
+
+	vbFormat = "junit"
+	out, err = run()
+	if got := exitCode(err); got != exitPartial {
+		t.Errorf("junit: exit code %d, want %d", got, exitPartial)
+	}
+	for _, want := range []string{
+		`<testsuite name="verify-boot" tests="4" failures="2"`,
+		`<testcase classname="nid000001" name="ping"`,
+		`<failure message="ping (127.0.0.2): no reply">`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("junit missing %q:\n%s", want, out)
+		}
+	}
+
+	vbNodes, vbFormat = "nid000001", "json"
+	out, err = run()
+	if err != nil {
+		t.Fatalf("single up node: %v", err)
+	}
+	if !strings.Contains(out, `"ok": true`) || strings.Contains(out, "127.0.0.2") {
+		t.Errorf("json:\n%s", out)
+	}
+
+	vbNodes = "x1000c0s1b0n0"
+	if _, err := run(); exitCode(err) != exitInvalid {
+		t.Errorf("node without IP: err = %v, want invalid", err)
+	}
+}
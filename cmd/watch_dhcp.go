@@ -0,0 +1,212 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"bootstrap/internal/dhcpwatch"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	wdFile            string
+	wdNodes           string
+	wdLog             string
+	wdSniff           string
+	wdFromStart       bool
+	wdAll             bool
+	wdDuration        time.Duration
+	wdUntilAcked      bool
+	wdSummaryInterval time.Duration
+)
+
+// dhcpSniff reads DHCP messages off an interface. Tests replace it.
+var dhcpSniff = dhcpwatch.Sniff
+
+var watchDHCPCmd = &cobra.Command{
+	Use:   "watch-dhcp",
+	Short: "Show live which inventory MACs send DHCP requests and which stay silent",
+	Long: `Follow a dnsmasq or Kea DHCPv4 log (--log, or --log - for e.g. journalctl -f on
+stdin) or sniff DHCP on an interface (--sniff, Linux only, needs
+CAP_NET_RAW), and match DISCOVER/OFFER/REQUEST/ACK messages with the MACs of
+nodes[] and bmcs[] of --file. Each message from a known MAC is printed as it
+arrives; at the end (Ctrl-C, --duration or --until-acked) a summary lists the
+MACs that never got an ACK, including those that were silent.
+
+dnsmasq needs log-dhcp (or the default DHCP logging); Kea needs the
+kea-dhcp4.packets and kea-dhcp4.leases loggers at INFO or DEBUG.`,
+	RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
+		if wdFile == "" {
+			return invalidf("--file is required")
+		}
+		if (wdLog == "") == (wdSniff == "") {
+			return invalidf("exactly one of --log or --sniff is required")
+		}
+		doc, err := readInventory(wdFile)
+		if err != nil {
+			return err
+		}
+		tracker := dhcpwatch.NewTracker()
+		if wdNodes != "" {
+			nodes, err := nodeTargets(doc, wdFile, wdNodes, false)
+			if err != nil {
+				return err
+			}
+			for _, n := range nodes {
+				tracker.Add(n.Xname, n.Alias, n.MAC)
+			}
+		} else {
+			for _, n := range doc.Nodes {
+				tracker.Add(n.Xname, n.Alias, n.MAC)
+			}
+			for _, b := range doc.BMCs {
+				tracker.Add(b.Xname, b.Alias, b.MAC)
+			}
+		}
+		if len(tracker.States()) == 0 {
+			return invalidf("no MACs to watch in %s", wdFile)
+		}
+
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+		if wdDuration > 0 {
+			ctx, cancel = context.WithTimeout(ctx, wdDuration)
+			defer cancel()
+		}
+		events := make(chan dhcpwatch.Event, 64)
+		send := func(ev dhcpwatch.Event) {
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+			}
+		}
+		fromLine := func(line string) {
+			if ev, ok := dhcpwatch.ParseLine(line); ok {
+				ev.Time = time.Now()
+				send(ev)
+			}
+		}
+		srcErr := make(chan error, 1)
+		go func() {
+			switch {
+			case wdSniff != "":
+				srcErr <- dhcpSniff(ctx, wdSniff, send)
+			case wdLog == "-":
+				srcErr <- dhcpwatch.Scan(ctx, os.Stdin, fromLine)
+			default:
+				srcErr <- dhcpwatch.Follow(ctx, wdLog, wdFromStart, fromLine)
+			}
+		}()
+		fmt.Fprintf(os.Stderr, "Watching DHCP for %d MAC(s) from %s; Ctrl-C to stop\n", len(tracker.States()), cmp.Or(wdSniff, wdLog))
+
+		var tick <-chan time.Time
+		if wdSummaryInterval > 0 {
+			t := time.NewTicker(wdSummaryInterval)
+			defer t.Stop()
+			tick = t.C
+		}
+		var sourceErr error
+	loop:
+		for {
+			select {
+			case ev := <-events:
+				st, known := tracker.Observe(ev)
+				if !known && !wdAll {
+					continue
+				}
+				printDHCPEvent(ev, st, known)
+				if wdUntilAcked && tracker.Done() {
+					break loop
+				}
+			case <-tick:
+				printDHCPSummary(tracker)
+			case sourceErr = <-srcErr:
+				break loop
+			case <-ctx.Done():
+				break loop
+			}
+		}
+		printDHCPSummary(tracker)
+		if sourceErr != nil && ctx.Err() == nil {
+			return sourceErr
+		}
+		if !wdUntilAcked {
+			return nil
+		}
+		failed := map[string]error{}
+		for _, s := range tracker.States() {
+			if p := s.Phase(); p != "acked" {
+				failed[s.Name] = fmt.Errorf("%s: %s", s.MAC, p)
+			}
+		}
+		return hostFailures(len(tracker.States()), failed)
+	},
+}
+
+func printDHCPEvent(ev dhcpwatch.Event, st dhcpwatch.State, known bool) {
+	name := "?"
+	if known {
+		name = cmp.Or(st.Alias, st.Name)
+	}
+	line := fmt.Sprintf("%s %-8s %s %s", ev.Time.Format("15:04:05"), ev.Type, name, ev.MAC)
+	if ev.IP != "" {
+		line += " " + ev.IP
+	}
+	if ev.Interface != "" {
+		line += " on " + ev.Interface
+	}
+	fmt.Println(line)
+}
+
+func printDHCPSummary(t *dhcpwatch.Tracker) {
+	counts := map[string]int{}
+	var waiting []string
+	for _, s := range t.States() {
+		p := s.Phase()
+		counts[p]++
+		if p == "acked" {
+			continue
+		}
+		detail := p
+		if p != "silent" {
+			var seen []string
+			for _, typ := range []string{dhcpwatch.Discover, dhcpwatch.Offer, dhcpwatch.Request, dhcpwatch.Nak} {
+				if n := s.Counts[typ]; n > 0 {
+					seen = append(seen, fmt.Sprintf("%d %s", n, typ))
+				}
+			}
+			detail += " (" + strings.Join(seen, ", ") + ", last " + s.LastSeen.Format("15:04:05") + ")"
+		}
+		waiting = append(waiting, fmt.Sprintf("  %s %s: %s", cmp.Or(s.Alias, s.Name), s.MAC, detail))
+	}
+	fmt.Println("DHCP watch summary:")
+	fmt.Printf("  tracked: %d\n", len(t.States()))
+	fmt.Printf("  acked: %d\n", counts["acked"])
+	fmt.Printf("  in progress: %d\n", len(t.States())-counts["acked"]-counts["silent"])
+	fmt.Printf("  silent: %d\n", counts["silent"])
+	for _, w := range waiting {
+		fmt.Println(w)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(watchDHCPCmd)
+	watchDHCPCmd.Flags().StringVarP(&wdFile, "file", "f", "", "inventory whose nodes[] and bmcs[] MACs are watched")
+	watchDHCPCmd.Flags().StringVar(&wdNodes, "nodes", "", "comma-separated node xnames or aliases to watch (default: every node and BMC MAC)")
+	watchDHCPCmd.Flags().StringVar(&wdLog, "log", "", "dnsmasq or Kea log file to follow, or - for stdin (e.g. journalctl -fu dnsmasq -o cat)")
+	watchDHCPCmd.Flags().StringVar(&wdSniff, "sniff", "", "sniff DHCP on this interface instead of reading a log (Linux, needs CAP_NET_RAW)")
+	watchDHCPCmd.Flags().BoolVar(&wdFromStart, "from-start", false, "read --log from the beginning instead of only new lines")
+	watchDHCPCmd.Flags().BoolVar(&wdAll, "all", false, "also print messages from MACs not in the inventory")
+	watchDHCPCmd.Flags().DurationVar(&wdDuration, "duration", 0, "stop after this long (0 = until Ctrl-C)")
+	watchDHCPCmd.Flags().BoolVar(&wdUntilAcked, "until-acked", false, "stop once every watched MAC got an ACK; exit 2 if some did not")
+	watchDHCPCmd.Flags().DurationVar(&wdSummaryInterval, "summary-interval", 0, "also print the summary this often (0 = only at the end)")
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"bootstrap/internal/dhcpwatch"
+)
+
+func TestWatchDHCP(t *testing.T) {
+	inv := filepath.Join(t.TempDir(), "inventory.yaml")
+	doc := `bmcs:
+  - xname: x1000c0s0b0
+    mac: 02:00:00:00:00:01
+nodes:
+  - xname: x1000c0s0b0n0
+    alias: nid000001
+    mac: aa:00:00:00:00:01
+  - xname: x1000c0s0b0n1
+    mac: aa:00:00:00:00:02
+`
+	if err := os.WriteFile(inv, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := dhcpSniff
+	dhcpSniff = func(ctx context.Context, iface string, fn func(dhcpwatch.Event)) error {
+		now := time.Now()
+		for _, ev := range []dhcpwatch.Event{
+			{Type: dhcpwatch.Discover, MAC: "aa:00:00:00:00:01"},
+			{Type: dhcpwatch.Discover, MAC: "ff:00:00:00:00:99"},
+			{Type: dhcpwatch.Ack, MAC: "aa:00:00:00:00:01", IP: "10.42.0.1"},
+			{Type: dhcpwatch.Discover, MAC: "aa:00:00:00:00:02"},
+		} {
+			ev.Time, ev.Interface = now, iface
+			fn(ev)
+		}
+		<-ctx.Done()
+		return nil
+	}
+	t.Cleanup(func() {
+		dhcpSniff = old
+		wdFile, wdSniff, wdNodes, wdDuration, wdUntilAcked = "", "", "", 0, false
+	})
+	wdFile, wdSniff, wdDuration, wdUntilAcked = inv, "eth1", 200*time.Millisecond, true
+	watchDHCPCmd.SetContext(context.Background())
+
+	run := func() (string, error) {
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := watchDHCPCmd.RunE(watchDHCPCmd, nil)
+		w.Close() //nolint:errcheck
+		os.Stdout = old
+		out, _ := io.ReadAll(r)
+		return string(out), err
+	}
+
+	out, err := run()
+	if got := exitCode(err); got != exitPartial {
+		t.Errorf("exit code %d, want %d", got, exitPartial)
+	}
+	for _, want := range []string{
+		"DISCOVER nid000001 aa:00:00:00:00:01 on eth1\n",
+		"ACK      nid000001 aa:00:00:00:00:01 10.42.0.1 on eth1\n",
+		"  tracked: 3\n  acked: 1\n  in progress: 1\n  silent: 1\n",
+		"  x1000c0s0b0n1 aa:00:00:00:00:02: discovering (1 DISCOVER, last ",
+		"  x1000c0s0b0 02:00:00:00:00:01: silent\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "ff:00:00:00:00:99") {
+		t.Errorf("unknown MAC printed without --all:\n%s", out)
+	}
+
+	// Only nid000001 is watched, and it is acked: stop early, exit 0.
+	wdNodes, wdDuration = "nid000001", time.Minute
+	if _, err := run(); err != nil {
+		t.Errorf("--nodes nid000001: %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package adaptive sizes how many BMCs a command works on at once from how the
+// BMCs answer: one more host per round of quick answers, half as many after a
+// 503 or 429 answer or a timeout. Fleets mixing fast and weak BMC CPUs run near
+// the pace the weak ones can take instead of at a fixed --batch-size.
+package adaptive
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Tuning of Limiter.
+const (
+	// Start is the concurrency a Limiter begins with.
+	Start = 2
+	// hold is how long after a decrease further throttling is attributed to
+	// requests sent before it, and does not decrease again.
+	hold = 2 * time.Second
+	// slowSlack is how far the average latency may exceed the best seen before
+	// growth stops, in addition to doubling.
+	slowSlack = 100 * time.Millisecond
+)
+
+// Limiter is a concurrency limit between 1 and a ceiling, adjusted by Observe.
+type Limiter struct {
+	mu        sync.Mutex
+	changed   chan struct{} // closed when a slot may have become free
+	limit     int
+	ceiling   int
+	inFlight  int
+	ok        int           // quick answers since the limit last changed
+	avg, best time.Duration // moving average and lowest average of answer latencies
+	holdUntil time.Time
+	logf      func(format string, args ...any)
+	now       func() time.Time
+}
+
+// New returns a Limiter that starts at Start (or ceiling, if lower) and never
+// exceeds ceiling. logf, if not nil, is told about every change of the limit.
+func New(ceiling int, logf func(format string, args ...any)) *Limiter {
+	ceiling = max(1, ceiling)
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+	return &Limiter{changed: make(chan struct{}), limit: min(Start, ceiling), ceiling: ceiling, logf: logf, now: time.Now}
+}
+
+// Limit returns the current limit.
+func (l *Limiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// Acquire waits for a free slot, or until ctx is done.
+func (l *Limiter) Acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.inFlight < l.limit {
+			l.inFlight++
+			l.mu.Unlock()
+			return nil
+		}
+		ch := l.changed
+		l.mu.Unlock()
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Release frees a slot taken by Acquire.
+func (l *Limiter) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+	l.wake()
+}
+
+// Observe adjusts the limit from one request: its latency d, its HTTP status
+// (0 when no answer came) and the transport error, if any. Errors other than
+// timeouts, such as a refused connection, say nothing about load and are
+// ignored.
+func (l *Limiter) Observe(d time.Duration, status int, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if status == http.StatusServiceUnavailable || status == http.StatusTooManyRequests || (err != nil && timeout(err)) {
+		l.ok = 0
+		if l.now().Before(l.holdUntil) {
+			return
+		}
+		l.holdUntil = l.now().Add(hold)
+		if l.limit > 1 {
+			reason := http.StatusText(status)
+			if status == 0 {
+				reason = "timeout"
+			}
+			l.logf("adaptive concurrency %d -> %d (%s)", l.limit, max(1, l.limit/2), reason)
+			l.limit = max(1, l.limit/2)
+		}
+		return
+	}
+	if err != nil || status == 0 {
+		return
+	}
+	if l.avg == 0 {
+		l.avg = d
+	} else {
+		l.avg += (d - l.avg) / 5
+	}
+	if l.best == 0 || l.avg < l.best {
+		l.best = l.avg
+	}
+	if l.avg > max(2*l.best, l.best+slowSlack) {
+		l.ok = 0 // answers are slowing down: hold the limit
+		return
+	}
+	l.ok++
+	if l.ok >= l.limit && l.limit < l.ceiling {
+		l.logf("adaptive concurrency %d -> %d (average latency %s)", l.limit, l.limit+1, l.avg.Round(time.Millisecond))
+		l.limit++
+		l.ok = 0
+		l.wake()
+	}
+}
+
+// wake lets waiting Acquire calls look for a free slot again; l.mu must be held.
+func (l *Limiter) wake() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// timeout reports whether err is a request running out of time.
+func timeout(err error) bool {
+	var ne net.Error
+	return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &ne) && ne.Timeout())
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package adaptive
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestLimiterGrowsAndBacksOff(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := New(8, nil)
+	l.now = func() time.Time { return now }
+	if l.Limit() != Start {
+		t.Fatalf("start limit %d, want %d", l.Limit(), Start)
+	}
+	quick := func(n int) {
+		for range n {
+			l.Observe(50*time.Millisecond, http.StatusOK, nil)
+		}
+	}
+	quick(2 + 3 + 4 + 5 + 6 + 7 + 20)
+	if l.Limit() != 8 {
+		t.Fatalf("limit after quick answers %d, want the ceiling 8", l.Limit())
+	}
+
+	l.Observe(time.Second, http.StatusServiceUnavailable, nil)
+	if l.Limit() != 4 {
+		t.Fatalf("limit after 503 %d, want 4", l.Limit())
+	}
+	// Throttling right after a decrease comes from requests sent before it
+	l.Observe(time.Second, http.StatusTooManyRequests, nil)
+	if l.Limit() != 4 {
+		t.Fatalf("limit after 429 within hold %d, want 4", l.Limit())
+	}
+	now = now.Add(3 * time.Second)
+	l.Observe(time.Second, 0, context.DeadlineExceeded)
+	if l.Limit() != 2 {
+		t.Fatalf("limit after timeout %d, want 2", l.Limit())
+	}
+
+	// A refused connection says nothing about load
+	now = now.Add(3 * time.Second)
+	l.Observe(time.Millisecond, 0, syscall.ECONNREFUSED)
+	if l.Limit() != 2 {
+		t.Fatalf("limit after refused connection %d, want 2", l.Limit())
+	}
+	l.Observe(time.Millisecond, 0, errors.New("tls: handshake failure"))
+	if l.Limit() != 2 {
+		t.Fatalf("limit after transport error %d, want 2", l.Limit())
+	}
+}
+
+func TestLimiterHoldsWhenSlowing(t *testing.T) {
+	l := New(8, nil)
+	for range 4 {
+		l.Observe(20*time.Millisecond, http.StatusOK, nil)
+	}
+	limit := l.Limit()
+	for range 50 {
+		l.Observe(2*time.Second, http.StatusOK, nil)
+	}
+	if l.Limit() != limit {
+		t.Fatalf("limit grew from %d to %d while answers slowed down", limit, l.Limit())
+	}
+}
+
+func TestLimiterAcquire(t *testing.T) {
+	l := New(4, nil)
+	ctx := context.Background()
+	for range Start {
+		if err := l.Acquire(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := make(chan error, 1)
+	go func() { got <- l.Acquire(ctx) }()
+	select {
+	case <-got:
+		t.Fatal("Acquire returned beyond the limit")
+	case <-time.After(20 * time.Millisecond):
+	}
+	l.Release()
+	if err := <-got; err != nil {
+		t.Fatal(err)
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := l.Acquire(cctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Acquire on a cancelled context = %v", err)
+	}
+}
@@ -0,0 +1,225 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"bootstrap/internal/progress"
+	"bootstrap/pkg/api"
+	"bootstrap/pkg/inventory"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCOptions returns the server options enforcing the bearer token on every
+// gRPC call.
+func (s *Server) GRPCOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, h grpc.UnaryHandler) (any, error) {
+			if err := s.checkToken(ctx); err != nil {
+				return nil, err
+			}
+			return h(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, h grpc.StreamHandler) error {
+			if err := s.checkToken(ss.Context()); err != nil {
+				return err
+			}
+			return h(srv, ss)
+		}),
+	}
+}
+
+// RegisterGRPC registers the Bootstrap service on g.
+func (s *Server) RegisterGRPC(g *grpc.Server) {
+	api.RegisterBootstrapServer(g, grpcService{s: s})
+}
+
+func (s *Server) checkToken(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	want := []byte("Bearer " + s.cfg.Token)
+	for _, got := range md.Get("authorization") {
+		if s.cfg.Token != "" && subtle.ConstantTimeCompare([]byte(got), want) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+}
+
+// grpcService implements api.BootstrapServer. Streamed runs are tied to the
+// call: they are not listed under /v1/jobs and end when the client cancels.
+type grpcService struct {
+	api.UnimplementedBootstrapServer
+	s *Server
+}
+
+func (g grpcService) ListInventory(context.Context, *api.ListInventoryRequest) (*api.Inventory, error) {
+	st, err := g.s.cfg.Open(g.s.cfg.InventoryFile)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	defer st.Close()
+	doc, err := st.Load()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &api.Inventory{Bmcs: apiEntries(doc.BMCs), Nodes: apiEntries(doc.Nodes)}, nil
+}
+
+func apiEntries(in []inventory.Entry) []*api.Entry {
+	out := make([]*api.Entry, 0, len(in))
+	for _, e := range in {
+		a := &api.Entry{
+			Xname:            e.Xname,
+			Mac:              e.MAC,
+			Ip:               e.IP,
+			Model:            e.Model,
+			SerialNumber:     e.SerialNumber,
+			Sku:              e.SKU,
+			BiosVersion:      e.BiosVersion,
+			ProcessorSummary: e.ProcessorSummary,
+			MemorySummary:    e.MemorySummary,
+		}
+		for _, n := range e.HSN {
+			a.Hsn = append(a.Hsn, &api.NIC{Id: n.ID, Mac: n.MAC, Description: n.Description})
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+func (g grpcService) Discover(req *api.DiscoverRequest, stream grpc.ServerStreamingServer[api.Progress]) error {
+	args, err := g.s.discoverArgs(DiscoverRequest{
+		Hosts:      req.GetHosts(),
+		BMCSubnet:  req.GetBmcSubnet(),
+		NodeSubnet: req.GetNodeSubnet(),
+		DryRun:     req.GetDryRun(),
+	})
+	if err != nil {
+		return grpcError(err)
+	}
+	return g.stream(stream, args)
+}
+
+func (g grpcService) UpdateFirmware(req *api.UpdateFirmwareRequest, stream grpc.ServerStreamingServer[api.Progress]) error {
+	args, err := g.s.firmwareArgs(FirmwareRequest{
+		Hosts:           req.GetHosts(),
+		Type:            req.GetType(),
+		Targets:         req.GetTargets(),
+		ImageURI:        req.GetImageUri(),
+		Protocol:        req.GetProtocol(),
+		ExpectedVersion: req.GetExpectedVersion(),
+		Force:           req.GetForce(),
+		BatchSize:       int(req.GetBatchSize()),
+		DryRun:          req.GetDryRun(),
+	})
+	if err != nil {
+		return grpcError(err)
+	}
+	return g.stream(stream, args)
+}
+
+// stream runs args, sending output lines and host progress as they happen and
+// a Finished message at the end.
+func (g grpcService) stream(stream grpc.ServerStreamingServer[api.Progress], args []string) error {
+	ctx := stream.Context()
+	var mu sync.Mutex // Send is not safe for concurrent use
+	var sendErr error
+	send := func(p *api.Progress) {
+		mu.Lock()
+		defer mu.Unlock()
+		if sendErr == nil {
+			sendErr = stream.Send(p)
+		}
+	}
+	output := func(s api.OutputLine_Stream) *lineWriter {
+		return &lineWriter{line: func(text string) {
+			send(&api.Progress{Event: &api.Progress_Output{Output: &api.OutputLine{Stream: s, Text: text}}})
+		}}
+	}
+	stdout, stderr := output(api.OutputLine_STREAM_STDOUT), output(api.OutputLine_STREAM_STDERR)
+	events := &lineWriter{line: func(text string) {
+		var e progress.Event
+		if json.Unmarshal([]byte(text), &e) != nil {
+			return
+		}
+		send(&api.Progress{Event: &api.Progress_Host{Host: &api.HostProgress{
+			Host:      e.Host,
+			State:     e.State,
+			Message:   e.Message,
+			Completed: int32(e.Completed),
+			Total:     int32(e.Total),
+		}}})
+	}}
+
+	code, err := g.s.cfg.Run(ctx, args, stdout, stderr, events)
+	stdout.flush()
+	stderr.flush()
+	events.flush()
+	fin := &api.Finished{ExitCode: int32(code)}
+	switch {
+	case err != nil:
+		fin.State, fin.Error = JobFailed, err.Error()
+	case ctx.Err() != nil:
+		fin.State = JobCanceled
+	case code != 0:
+		fin.State = JobFailed
+	default:
+		fin.State = JobSucceeded
+	}
+	send(&api.Progress{Event: &api.Progress_Finished{Finished: fin}})
+	mu.Lock()
+	defer mu.Unlock()
+	return sendErr
+}
+
+func grpcError(err error) error {
+	var re requestError
+	if errors.As(err, &re) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// lineWriter calls line for each complete line written to it.
+type lineWriter struct {
+	mu   sync.Mutex
+	buf  []byte
+	line func(string)
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.line(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush passes on a last line that has no newline.
+func (w *lineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.line(string(w.buf))
+		w.buf = nil
+	}
+}
@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"bootstrap/internal/progress"
+	"bootstrap/pkg/api"
+	"bootstrap/pkg/inventory"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestGRPC(t *testing.T, run Runner) api.BootstrapClient {
+	t.Helper()
+	doc := inventory.FileFormat{
+		BMCs:  []inventory.Entry{{Xname: "x1000c0s0b0", IP: "192.168.100.10"}},
+		Nodes: []inventory.Entry{{Xname: "x1000c0s0b0n0", MAC: "02:00:00:00:10:01", HSN: []inventory.NIC{{ID: "hsn0", MAC: "02:00:00:00:20:01"}}}},
+	}
+	s, err := New(context.Background(), Config{
+		Token:         "secret",
+		InventoryFile: "inventory.yaml",
+		Open:          func(string) (inventory.Store, error) { return memStore{doc}, nil },
+		Run:           run,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer(s.GRPCOptions()...)
+	s.RegisterGRPC(gs)
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return api.NewBootstrapClient(conn)
+}
+
+func authed() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+}
+
+func TestGRPCListInventory(t *testing.T) {
+	c := newTestGRPC(t, nil)
+	if _, err := c.ListInventory(context.Background(), &api.ListInventoryRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("no token: err = %v, want Unauthenticated", err)
+	}
+	inv, err := c.ListInventory(authed(), &api.ListInventoryRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inv.GetBmcs()) != 1 || len(inv.GetNodes()) != 1 {
+		t.Fatalf("inventory = %v", inv)
+	}
+	if n := inv.GetNodes()[0]; n.GetMac() != "02:00:00:00:10:01" || len(n.GetHsn()) != 1 || n.GetHsn()[0].GetId() != "hsn0" {
+		t.Errorf("node = %v", n)
+	}
+}
+
+func TestGRPCUpdateFirmwareStream(t *testing.T) {
+	c := newTestGRPC(t, func(_ context.Context, args []string, stdout, stderr, prog io.Writer) (int, error) {
+		r := progress.New(prog, 2)
+		r.Start("a")
+		r.Done("a", "updated", nil)
+		fmt.Fprintln(stdout, "Triggered firmware update on a")
+		r.Start("b")
+		r.Done("b", "failed", errors.New("boom"))
+		fmt.Fprint(stderr, "WARN: b: firmware update failed: boom")
+		return 2, nil
+	})
+	// Server-streaming calls report their errors on the first Recv
+	stream, err := c.UpdateFirmware(authed(), &api.UpdateFirmwareRequest{Type: "cc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("missing image_uri: err = %v, want InvalidArgument", err)
+	}
+
+	stream, err = c.UpdateFirmware(authed(), &api.UpdateFirmwareRequest{Type: "cc", ImageUri: "http://10.0.0.1/cc.bin", Hosts: []string{"a", "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for {
+		p, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch e := p.GetEvent().(type) {
+		case *api.Progress_Host:
+			got = append(got, fmt.Sprintf("host %s %s %d/%d %s", e.Host.GetHost(), e.Host.GetState(), e.Host.GetCompleted(), e.Host.GetTotal(), e.Host.GetMessage()))
+		case *api.Progress_Output:
+			got = append(got, fmt.Sprintf("%s %s", e.Output.GetStream(), e.Output.GetText()))
+		case *api.Progress_Finished:
+			got = append(got, fmt.Sprintf("finished %s %d", e.Finished.GetState(), e.Finished.GetExitCode()))
+		}
+	}
+	// stdout, stderr and progress are separate writers, so only compare per kind
+	var hosts, rest []string
+	for _, g := range got {
+		if g[:4] == "host" {
+			hosts = append(hosts, g)
+		} else {
+			rest = append(rest, g)
+		}
+	}
+	wantHosts := []string{
+		"host a started 0/2 ",
+		"host a updated 1/2 ",
+		"host b started 1/2 ",
+		"host b failed 2/2 boom",
+	}
+	if fmt.Sprint(hosts) != fmt.Sprint(wantHosts) {
+		t.Errorf("host events = %q, want %q", hosts, wantHosts)
+	}
+	wantRest := []string{
+		"STREAM_STDOUT Triggered firmware update on a",
+		"STREAM_STDERR WARN: b: firmware update failed: boom",
+		"finished failed 2",
+	}
+	if fmt.Sprint(rest) != fmt.Sprint(wantRest) {
+		t.Errorf("other events = %q, want %q", rest, wantRest)
+	}
+}
+
+func TestGRPCDiscoverCanceled(t *testing.T) {
+	started := make(chan struct{})
+	c := newTestGRPC(t, func(ctx context.Context, _ []string, _, _, _ io.Writer) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 130, nil
+	})
+	ctx, cancel := context.WithCancel(authed())
+	stream, err := c.Discover(ctx, &api.DiscoverRequest{BmcSubnet: "192.168.100.0/24"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	cancel()
+	if _, err := stream.Recv(); status.Code(err) != codes.Canceled {
+		t.Errorf("err = %v, want Canceled", err)
+	}
+}
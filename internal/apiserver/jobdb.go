@@ -0,0 +1,87 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var jobsBucket = []byte("jobs")
+
+// JobDB is a JobStore in a bbolt database, one JSON record per job.
+type JobDB struct {
+	db *bolt.DB
+}
+
+var _ JobStore = (*JobDB)(nil)
+
+// OpenJobDB opens (creating if needed) the job database at path. Only one
+// server can use it at a time.
+func OpenJobDB(path string) (*JobDB, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		if errors.Is(err, bolt.ErrTimeout) {
+			return nil, fmt.Errorf("%s: in use by another process", path)
+		}
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &JobDB{db: db}, nil
+}
+
+// Close closes the database.
+func (d *JobDB) Close() error {
+	return d.db.Close()
+}
+
+// Put stores j, replacing any earlier record of it.
+func (d *JobDB) Put(j Job) error {
+	v, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	return d.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(jobsBucket)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(j.ID), v)
+	})
+}
+
+// List returns all stored jobs.
+func (d *JobDB) List() ([]Job, error) {
+	var out []Job
+	err := d.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(jobsBucket)
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			var j Job
+			if err := json.Unmarshal(v, &j); err != nil {
+				return fmt.Errorf("job %s: %w", k, err)
+			}
+			out = append(out, j)
+			return nil
+		})
+	})
+	return out, err
+}
+
+// Delete removes the record of the job with id.
+func (d *JobDB) Delete(id string) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(jobsBucket)
+		if b == nil {
+			return nil
+		}
+		return b.Delete([]byte(id))
+	})
+}
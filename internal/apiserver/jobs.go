@@ -0,0 +1,323 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package apiserver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"bootstrap/internal/redact"
+)
+
+// Job states
+const (
+	JobQueued      = "queued" // waiting for its scheduled time
+	JobRunning     = "running"
+	JobSucceeded   = "succeeded"
+	JobFailed      = "failed"
+	JobCanceled    = "canceled"
+	JobInterrupted = "interrupted" // the server stopped while it was running
+)
+
+// Runner runs the CLI with args and returns the process exit status. err is set
+// only when the command could not be run at all. If progress is not nil, the
+// command's per-host progress events (JSON lines) are written to it.
+type Runner func(ctx context.Context, args []string, stdout, stderr, progress io.Writer) (exitCode int, err error)
+
+// Job is one CLI run started through the API.
+type Job struct {
+	ID        string     `json:"id"`
+	Kind      string     `json:"kind"`
+	Args      []string   `json:"args"`
+	State     string     `json:"state"`
+	ExitCode  *int       `json:"exit_code,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	Submitted time.Time  `json:"submitted"`
+	Scheduled *time.Time `json:"scheduled,omitempty"` // not started before this time
+	Started   *time.Time `json:"started,omitempty"`
+	Finished  *time.Time `json:"finished,omitempty"`
+	Output    string     `json:"output,omitempty"`
+
+	out      *output
+	cancel   context.CancelFunc
+	canceled bool // canceled through the API rather than by shutdown
+}
+
+// JobStore persists jobs so queued work and finished logs survive a restart.
+type JobStore interface {
+	Put(j Job) error
+	List() ([]Job, error)
+	Delete(id string) error
+}
+
+// jobs tracks queued, running and recently finished jobs.
+type jobs struct {
+	ctx       context.Context // canceled when the server stops
+	run       Runner
+	maxOutput int
+	store     JobStore // nil keeps jobs in memory only
+	wg        sync.WaitGroup
+
+	mu   sync.Mutex
+	byID map[string]*Job
+	keep int // finished jobs kept for status queries
+}
+
+func newJobs(ctx context.Context, run Runner, maxOutput, keep int, store JobStore) *jobs {
+	return &jobs{ctx: ctx, run: run, maxOutput: maxOutput, store: store, byID: map[string]*Job{}, keep: keep}
+}
+
+// restore loads the stored jobs: queued jobs wait for their time again, and jobs
+// that were running when the server stopped are marked interrupted.
+func (js *jobs) restore() error {
+	if js.store == nil {
+		return nil
+	}
+	stored, err := js.store.List()
+	if err != nil {
+		return err
+	}
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	for i := range stored {
+		j := &stored[i]
+		switch j.State {
+		case JobQueued:
+			j.out = newOutput(js.maxOutput)
+			js.byID[j.ID] = j
+			js.schedule(j)
+			continue
+		case JobRunning:
+			now := time.Now().UTC()
+			j.State, j.Error, j.Finished = JobInterrupted, "the server stopped while the job was running", &now
+			js.save(j)
+		}
+		js.byID[j.ID] = j
+	}
+	js.prune()
+	return nil
+}
+
+// submit queues args as a new job that starts at at (now if zero) and returns a
+// snapshot of it. The job is stored before submit returns.
+func (js *jobs) submit(kind string, args []string, at time.Time) (Job, error) {
+	j := &Job{ID: newID(), Kind: kind, Args: args, State: JobQueued, Submitted: time.Now().UTC(), out: newOutput(js.maxOutput)}
+	if !at.IsZero() {
+		at = at.UTC()
+		j.Scheduled = &at
+	}
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	if js.store != nil {
+		if err := js.store.Put(j.snapshot(false)); err != nil {
+			return Job{}, fmt.Errorf("store job: %w", err)
+		}
+	}
+	js.byID[j.ID] = j
+	js.schedule(j)
+	return j.snapshot(false), nil
+}
+
+// schedule runs j in the background once its scheduled time has come. A job
+// still waiting when the server stops stays queued in the store. Callers hold mu.
+func (js *jobs) schedule(j *Job) {
+	ctx, cancel := context.WithCancel(js.ctx)
+	j.cancel = cancel
+	js.wg.Add(1)
+	go func() {
+		defer js.wg.Done()
+		defer cancel()
+		if j.Scheduled != nil {
+			t := time.NewTimer(time.Until(*j.Scheduled))
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				t.Stop()
+			}
+		}
+		js.mu.Lock()
+		if ctx.Err() != nil {
+			if j.canceled {
+				js.finish(j, JobCanceled, nil, nil)
+			}
+			js.mu.Unlock()
+			return
+		}
+		now := time.Now().UTC()
+		j.State, j.Started = JobRunning, &now
+		js.save(j)
+		js.mu.Unlock()
+
+		code, err := js.run(ctx, j.Args, j.out, j.out, nil)
+
+		js.mu.Lock()
+		defer js.mu.Unlock()
+		switch {
+		case err != nil:
+			js.finish(j, JobFailed, nil, err)
+		case j.canceled:
+			js.finish(j, JobCanceled, &code, nil)
+		case ctx.Err() != nil:
+			js.finish(j, JobInterrupted, &code, nil)
+		case code != 0:
+			js.finish(j, JobFailed, &code, nil)
+		default:
+			js.finish(j, JobSucceeded, &code, nil)
+		}
+	}()
+}
+
+// finish records the outcome of j. Callers hold mu.
+func (js *jobs) finish(j *Job, state string, code *int, err error) {
+	now := time.Now().UTC()
+	j.State, j.ExitCode, j.Finished = state, code, &now
+	if err != nil {
+		j.Error = redact.String(err.Error())
+	}
+	j.Output = redact.String(j.out.String())
+	js.save(j)
+	js.prune()
+}
+
+// save stores j, warning on failure: the job itself carries on. Callers hold mu.
+func (js *jobs) save(j *Job) {
+	if js.store == nil {
+		return
+	}
+	if err := js.store.Put(j.snapshot(true)); err != nil {
+		fmt.Fprintf(os.Stderr, "WARN: job %s: store: %v\n", j.ID, err)
+	}
+}
+
+// wait blocks until every job goroutine has returned.
+func (js *jobs) wait() {
+	js.wg.Wait()
+}
+
+// prune drops the oldest finished jobs beyond keep. Callers hold mu.
+func (js *jobs) prune() {
+	var done []*Job
+	for _, j := range js.byID {
+		if j.Finished != nil {
+			done = append(done, j)
+		}
+	}
+	if len(done) <= js.keep {
+		return
+	}
+	sort.Slice(done, func(a, b int) bool { return done[a].Finished.Before(*done[b].Finished) })
+	for _, j := range done[:len(done)-js.keep] {
+		delete(js.byID, j.ID)
+		if js.store != nil {
+			if err := js.store.Delete(j.ID); err != nil {
+				fmt.Fprintf(os.Stderr, "WARN: job %s: store: %v\n", j.ID, err)
+			}
+		}
+	}
+}
+
+// get returns a snapshot of the job with id, including its output.
+func (js *jobs) get(id string) (Job, bool) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	j, ok := js.byID[id]
+	if !ok {
+		return Job{}, false
+	}
+	return j.snapshot(true), true
+}
+
+// list returns snapshots of all jobs, newest first, without output.
+func (js *jobs) list() []Job {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	out := make([]Job, 0, len(js.byID))
+	for _, j := range js.byID {
+		out = append(out, j.snapshot(false))
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].Submitted.After(out[b].Submitted) })
+	return out
+}
+
+var errJobDone = errors.New("job already finished")
+
+// cancel stops a running job or drops a queued one.
+func (js *jobs) cancel(id string) (Job, error) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	j, ok := js.byID[id]
+	if !ok {
+		return Job{}, errNotFound
+	}
+	if j.Finished != nil {
+		return j.snapshot(false), errJobDone
+	}
+	j.canceled = true
+	j.cancel()
+	return j.snapshot(false), nil
+}
+
+// snapshot copies the job for encoding. Callers hold the jobs mutex.
+func (j *Job) snapshot(withOutput bool) Job {
+	c := *j
+	c.Args = append([]string(nil), j.Args...)
+	c.Output = ""
+	if withOutput {
+		c.Output = j.Output
+		if j.out != nil && j.Finished == nil {
+			c.Output = j.out.String()
+		}
+	}
+	c.out, c.cancel, c.canceled = nil, nil, false
+	return c
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// output collects a job's output, keeping at most the last max bytes.
+type output struct {
+	mu        sync.Mutex
+	buf       []byte
+	max       int
+	truncated bool
+}
+
+// newOutput returns an output that keeps the last max bytes written.
+func newOutput(max int) *output {
+	return &output{max: max}
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.buf = append(o.buf, p...)
+	if over := len(o.buf) - o.max; o.max > 0 && over > 0 {
+		o.buf = append(o.buf[:0], o.buf[over:]...)
+		o.truncated = true
+	}
+	return len(p), nil
+}
+
+// String returns the collected output, marked when the start was dropped.
+func (o *output) String() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.truncated {
+		return "[output truncated]\n" + string(o.buf)
+	}
+	return string(o.buf)
+}
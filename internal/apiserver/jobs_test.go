@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	for _, v := range []string{"2025-07-01T02:00Z", "2025-07-01T02:00:00Z", "2025-07-01T04:00+02:00"} {
+		got, err := ParseSchedule(v)
+		if err != nil {
+			t.Errorf("%s: %v", v, err)
+			continue
+		}
+		if want := time.Date(2025, 7, 1, 2, 0, 0, 0, time.UTC); !got.Equal(want) {
+			t.Errorf("%s = %v, want %v", v, got, want)
+		}
+	}
+	if _, err := ParseSchedule("2025-07-01"); err == nil {
+		t.Error("date without time accepted")
+	}
+}
+
+func waitState(t *testing.T, js *jobs, id, state string) Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		j, ok := js.get(id)
+		if ok && j.State == state {
+			return j
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s: state %q, want %q", id, j.State, state)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestScheduledJob(t *testing.T) {
+	ran := make(chan time.Time, 1)
+	js := newJobs(context.Background(), func(context.Context, []string, io.Writer, io.Writer, io.Writer) (int, error) {
+		ran <- time.Now()
+		return 0, nil
+	}, 0, 10, nil)
+	at := time.Now().Add(100 * time.Millisecond)
+	j, err := js.submit("firmware", []string{"firmware"}, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.State != JobQueued || j.Scheduled == nil {
+		t.Fatalf("job = %+v", j)
+	}
+	if started := <-ran; started.Before(at) {
+		t.Errorf("started %v before schedule %v", started, at)
+	}
+	waitState(t, js, j.ID, JobSucceeded)
+
+	// Canceling a queued job drops it without running
+	j, _ = js.submit("firmware", []string{"firmware"}, time.Now().Add(time.Hour))
+	if _, err := js.cancel(j.ID); err != nil {
+		t.Fatal(err)
+	}
+	if j = waitState(t, js, j.ID, JobCanceled); j.Started != nil {
+		t.Errorf("canceled job started: %+v", j)
+	}
+}
+
+func TestJobsSurviveRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	db, err := OpenJobDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	block := make(chan struct{})
+	js := newJobs(ctx, func(ctx context.Context, args []string, stdout, _, _ io.Writer) (int, error) {
+		fmt.Fprintln(stdout, "output of", args[0])
+		if args[0] == "slow" {
+			close(block)
+			<-ctx.Done()
+			return 130, nil
+		}
+		return 0, nil
+	}, 0, 10, db)
+	done, _ := js.submit("discover", []string{"fast"}, time.Time{})
+	waitState(t, js, done.ID, JobSucceeded)
+	queued, _ := js.submit("firmware", []string{"later"}, time.Now().Add(300*time.Millisecond))
+	running, _ := js.submit("firmware", []string{"slow"}, time.Time{})
+	<-block
+	stop()
+	js.wait()
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = OpenJobDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	ran := make(chan string, 1)
+	js = newJobs(context.Background(), func(_ context.Context, args []string, _, _, _ io.Writer) (int, error) {
+		ran <- args[0]
+		return 0, nil
+	}, 0, 10, db)
+	if err := js.restore(); err != nil {
+		t.Fatal(err)
+	}
+	if j, _ := js.get(done.ID); j.State != JobSucceeded || j.Output != "output of fast\n" {
+		t.Errorf("finished job after restart = %+v", j)
+	}
+	if j, _ := js.get(running.ID); j.State != JobInterrupted {
+		t.Errorf("running job after restart = %+v", j)
+	}
+	if got := <-ran; got != "later" {
+		t.Errorf("ran %q after restart, want the queued job", got)
+	}
+	waitState(t, js, queued.ID, JobSucceeded)
+}
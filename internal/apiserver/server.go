@@ -0,0 +1,364 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package apiserver exposes the bootstrap operations over HTTP. Long-running
+// operations (discovery, firmware updates) run the CLI as background jobs,
+// optionally scheduled for later, whose state and output can be polled; reads
+// answer directly.
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"bootstrap/pkg/inventory"
+)
+
+var errNotFound = errors.New("not found")
+
+// requestError is a request the server refuses to run.
+type requestError struct{ error }
+
+// errorStatus returns the HTTP status for an error building a command line.
+func errorStatus(err error) int {
+	var re requestError
+	if errors.As(err, &re) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// Config configures a Server.
+type Config struct {
+	// Token is the bearer token every /v1 request must carry.
+	Token string
+	// InventoryFile is passed as --file to the commands the server runs.
+	InventoryFile string
+	// Open opens the inventory read-only.
+	Open func(path string) (inventory.Store, error)
+	// Run runs the CLI.
+	Run Runner
+	// MaxOutput bounds the output kept per job, in bytes.
+	MaxOutput int
+	// KeepJobs is the number of finished jobs kept for status queries.
+	KeepJobs int
+	// Store persists jobs across restarts; nil keeps them in memory.
+	Store JobStore
+}
+
+// Server serves the bootstrap HTTP API.
+type Server struct {
+	cfg  Config
+	ctx  context.Context
+	jobs *jobs
+}
+
+// New returns a Server whose jobs are interrupted when ctx is done. Queued jobs
+// in cfg.Store are scheduled again.
+func New(ctx context.Context, cfg Config) (*Server, error) {
+	if cfg.MaxOutput <= 0 {
+		cfg.MaxOutput = 1 << 20
+	}
+	if cfg.KeepJobs <= 0 {
+		cfg.KeepJobs = 100
+	}
+	s := &Server{cfg: cfg, ctx: ctx, jobs: newJobs(ctx, cfg.Run, cfg.MaxOutput, cfg.KeepJobs, cfg.Store)}
+	if err := s.jobs.restore(); err != nil {
+		return nil, fmt.Errorf("restore jobs: %w", err)
+	}
+	return s, nil
+}
+
+// Wait blocks until the jobs interrupted by the end of the server context
+// have stopped and been recorded.
+func (s *Server) Wait() {
+	s.jobs.wait()
+}
+
+// ParseSchedule parses a job start time: RFC 3339, with or without seconds
+// (e.g. 2025-07-01T02:00Z).
+func ParseSchedule(v string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("schedule %q: want an RFC 3339 time such as 2025-07-01T02:00Z", v)
+}
+
+// Handler returns the HTTP handler for the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.Handle("GET /v1/inventory", s.auth(s.getInventory))
+	mux.Handle("GET /v1/inventory/{key}", s.auth(s.findInventory))
+	mux.Handle("POST /v1/discover", s.auth(s.postDiscover))
+	mux.Handle("POST /v1/firmware", s.auth(s.postFirmware))
+	mux.Handle("GET /v1/firmware/status", s.auth(s.getFirmwareStatus))
+	mux.Handle("GET /v1/jobs", s.auth(func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, s.jobs.list())
+	}))
+	mux.Handle("GET /v1/jobs/{id}", s.auth(s.getJob))
+	mux.Handle("GET /v1/jobs/{id}/log", s.auth(s.getJobLog))
+	mux.Handle("DELETE /v1/jobs/{id}", s.auth(s.cancelJob))
+	return mux
+}
+
+// auth rejects requests without the configured bearer token.
+func (s *Server) auth(h http.HandlerFunc) http.Handler {
+	want := []byte("Bearer " + s.cfg.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(r.Header.Get("Authorization"))
+		if s.cfg.Token == "" || subtle.ConstantTimeCompare(got, want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="bootstrap"`)
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+		h(w, r)
+	})
+}
+
+func (s *Server) getInventory(w http.ResponseWriter, _ *http.Request) {
+	st, err := s.cfg.Open(s.cfg.InventoryFile)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer st.Close()
+	doc, err := st.Load()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if doc.BMCs == nil {
+		doc.BMCs = []inventory.Entry{}
+	}
+	if doc.Nodes == nil {
+		doc.Nodes = []inventory.Entry{}
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func (s *Server) findInventory(w http.ResponseWriter, r *http.Request) {
+	st, err := s.cfg.Open(s.cfg.InventoryFile)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer st.Close()
+	found, err := st.Find(r.PathValue("key"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if len(found) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%s: %w", r.PathValue("key"), errNotFound))
+		return
+	}
+	writeJSON(w, http.StatusOK, found)
+}
+
+// DiscoverRequest is the body of POST /v1/discover.
+type DiscoverRequest struct {
+	Hosts      []string `json:"hosts,omitempty"` // BMC hosts or xnames; default all bmcs[]
+	BMCSubnet  string   `json:"bmc_subnet,omitempty"`
+	NodeSubnet string   `json:"node_subnet,omitempty"`
+	DryRun     bool     `json:"dry_run,omitempty"`
+	Schedule   string   `json:"schedule,omitempty"` // start time; default now
+}
+
+func (s *Server) postDiscover(w http.ResponseWriter, r *http.Request) {
+	var req DiscoverRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	args, err := s.discoverArgs(req)
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	s.submit(w, "discover", args, req.Schedule)
+}
+
+// discoverArgs returns the discover command line for req.
+func (s *Server) discoverArgs(req DiscoverRequest) ([]string, error) {
+	if req.BMCSubnet == "" && req.NodeSubnet == "" {
+		return nil, requestError{errors.New("bmc_subnet or node_subnet is required")}
+	}
+	args := []string{"discover", "--file=" + s.cfg.InventoryFile}
+	args = appendFlag(args, "bmc-subnet", req.BMCSubnet)
+	args = appendFlag(args, "node-subnet", req.NodeSubnet)
+	args = appendFlag(args, "hosts", strings.Join(req.Hosts, ","))
+	if req.DryRun {
+		args = append(args, "--dry-run")
+	}
+	return args, nil
+}
+
+// submit queues a job for args at the time in schedule (now when empty) and
+// answers with it.
+func (s *Server) submit(w http.ResponseWriter, kind string, args []string, schedule string) {
+	var at time.Time
+	if schedule != "" {
+		var err error
+		if at, err = ParseSchedule(schedule); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	j, err := s.jobs.submit(kind, args, at)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, j)
+}
+
+// FirmwareRequest is the body of POST /v1/firmware.
+type FirmwareRequest struct {
+	Hosts           []string `json:"hosts,omitempty"` // default all bmcs[]
+	Type            string   `json:"type,omitempty"`  // cc, nc or bios
+	Targets         []string `json:"targets,omitempty"`
+	ImageURI        string   `json:"image_uri"`
+	Protocol        string   `json:"protocol,omitempty"`
+	ExpectedVersion string   `json:"expected_version,omitempty"`
+	Force           bool     `json:"force,omitempty"`
+	BatchSize       int      `json:"batch_size,omitempty"`
+	DryRun          bool     `json:"dry_run,omitempty"`
+	Schedule        string   `json:"schedule,omitempty"` // start time; default now
+}
+
+func (s *Server) postFirmware(w http.ResponseWriter, r *http.Request) {
+	var req FirmwareRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	args, err := s.firmwareArgs(req)
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	s.submit(w, "firmware", args, req.Schedule)
+}
+
+// firmwareArgs returns the firmware command line for req.
+func (s *Server) firmwareArgs(req FirmwareRequest) ([]string, error) {
+	if req.ImageURI == "" {
+		return nil, requestError{errors.New("image_uri is required")}
+	}
+	if req.Type == "" && len(req.Targets) == 0 {
+		return nil, requestError{errors.New("type or targets is required")}
+	}
+	args := []string{"firmware", "--file=" + s.cfg.InventoryFile, "--image-uri=" + req.ImageURI}
+	args = appendFlag(args, "hosts", strings.Join(req.Hosts, ","))
+	args = appendFlag(args, "type", req.Type)
+	args = appendFlag(args, "targets", strings.Join(req.Targets, ","))
+	args = appendFlag(args, "protocol", req.Protocol)
+	args = appendFlag(args, "expected-version", req.ExpectedVersion)
+	if req.BatchSize > 0 {
+		args = appendFlag(args, "batch-size", strconv.Itoa(req.BatchSize))
+	}
+	if req.Force {
+		args = append(args, "--force")
+	}
+	if req.DryRun {
+		args = append(args, "--dry-run")
+	}
+	return append(args, "--format=json"), nil
+}
+
+// getFirmwareStatus runs firmware status for the hosts in the query (?hosts=a,b,
+// default all bmcs[]) and returns its JSON report.
+func (s *Server) getFirmwareStatus(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	args := []string{"firmware", "status", "--file=" + s.cfg.InventoryFile, "--format=json"}
+	args = appendFlag(args, "hosts", q.Get("hosts"))
+	args = appendFlag(args, "type", q.Get("type"))
+	var stdout, stderr bytes.Buffer
+	code, err := s.cfg.Run(r.Context(), args, &stdout, &stderr, nil)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if !json.Valid(stdout.Bytes()) {
+		writeError(w, http.StatusBadGateway, fmt.Errorf("firmware status exited with %d: %s", code, strings.TrimSpace(stderr.String())))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Exit-Code", strconv.Itoa(code))
+	_, _ = w.Write(stdout.Bytes())
+}
+
+func (s *Server) getJob(w http.ResponseWriter, r *http.Request) {
+	j, ok := s.jobs.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %s: %w", r.PathValue("id"), errNotFound))
+		return
+	}
+	writeJSON(w, http.StatusOK, j)
+}
+
+func (s *Server) getJobLog(w http.ResponseWriter, r *http.Request) {
+	j, ok := s.jobs.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %s: %w", r.PathValue("id"), errNotFound))
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = io.WriteString(w, j.Output)
+}
+
+func (s *Server) cancelJob(w http.ResponseWriter, r *http.Request) {
+	j, err := s.jobs.cancel(r.PathValue("id"))
+	switch {
+	case errors.Is(err, errNotFound):
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %s: %w", r.PathValue("id"), err))
+	case errors.Is(err, errJobDone):
+		writeError(w, http.StatusConflict, err)
+	default:
+		writeJSON(w, http.StatusAccepted, j)
+	}
+}
+
+// appendFlag adds --name=value when value is set. The = form keeps a value
+// starting with "-" from being read as another flag.
+func appendFlag(args []string, name, value string) []string {
+	if value == "" {
+		return args
+	}
+	return append(args, "--"+name+"="+value)
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("request body: %w", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
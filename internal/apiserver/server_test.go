@@ -0,0 +1,251 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"bootstrap/pkg/inventory"
+)
+
+type memStore struct{ doc inventory.FileFormat }
+
+func (m memStore) Load() (inventory.FileFormat, error)        { return m.doc, nil }
+func (m memStore) Save(inventory.FileFormat) error            { return nil }
+func (m memStore) Find(key string) ([]inventory.Match, error) { return m.doc.Find(key), nil }
+func (m memStore) Close() error                               { return nil }
+
+func newTestServer(t *testing.T, run Runner) *httptest.Server {
+	t.Helper()
+	doc := inventory.FileFormat{
+		BMCs:  []inventory.Entry{{Xname: "x1000c0s0b0", MAC: "02:00:00:00:00:01", IP: "192.168.100.10"}},
+		Nodes: []inventory.Entry{{Xname: "x1000c0s0b0n0", MAC: "02:00:00:00:10:01", IP: "10.42.0.10"}},
+	}
+	s, err := New(context.Background(), Config{
+		Token:         "secret",
+		InventoryFile: "inventory.yaml",
+		Open:          func(string) (inventory.Store, error) { return memStore{doc}, nil },
+		Run:           run,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func do(t *testing.T, ts *httptest.Server, method, path, body string) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	return resp, raw
+}
+
+func TestAuth(t *testing.T) {
+	ts := newTestServer(t, nil)
+	for _, h := range []string{"", "Bearer wrong", "secret"} {
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+"/v1/inventory", nil)
+		if h != "" {
+			req.Header.Set("Authorization", h)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: status %d, want 401", h, resp.StatusCode)
+		}
+	}
+	resp, err := http.Get(ts.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("healthz: status %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestInventory(t *testing.T) {
+	ts := newTestServer(t, nil)
+	resp, raw := do(t, ts, http.MethodGet, "/v1/inventory", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d: %s", resp.StatusCode, raw)
+	}
+	var doc inventory.FileFormat
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.BMCs) != 1 || len(doc.Nodes) != 1 {
+		t.Fatalf("inventory = %+v", doc)
+	}
+
+	resp, raw = do(t, ts, http.MethodGet, "/v1/inventory/02:00:00:00:10:01", "")
+	var found []inventory.Match
+	if err := json.Unmarshal(raw, &found); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("status %d: %s", resp.StatusCode, raw)
+	}
+	if len(found) != 1 || found[0].Section != "nodes" || found[0].Entry.Xname != "x1000c0s0b0n0" {
+		t.Errorf("found = %+v", found)
+	}
+
+	resp, _ = do(t, ts, http.MethodGet, "/v1/inventory/x9", "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown key: status %d, want 404", resp.StatusCode)
+	}
+}
+
+func waitJob(t *testing.T, ts *httptest.Server, id string) Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, raw := do(t, ts, http.MethodGet, "/v1/jobs/"+id, "")
+		var j Job
+		if err := json.Unmarshal(raw, &j); err != nil {
+			t.Fatal(err)
+		}
+		if j.State != JobRunning {
+			return j
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s still running", id)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDiscoverJob(t *testing.T) {
+	ts := newTestServer(t, func(_ context.Context, args []string, stdout, _, _ io.Writer) (int, error) {
+		fmt.Fprintln(stdout, "discovered")
+		return 0, nil
+	})
+	resp, raw := do(t, ts, http.MethodPost, "/v1/discover", `{"bmc_subnet":"192.168.100.0/24","hosts":["x1000c0s0b0"]}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("status %d: %s", resp.StatusCode, raw)
+	}
+	var started Job
+	if err := json.Unmarshal(raw, &started); err != nil {
+		t.Fatal(err)
+	}
+	j := waitJob(t, ts, started.ID)
+	if j.State != JobSucceeded || j.ExitCode == nil || *j.ExitCode != 0 {
+		t.Fatalf("job = %+v", j)
+	}
+	want := []string{"discover", "--file=inventory.yaml", "--bmc-subnet=192.168.100.0/24", "--hosts=x1000c0s0b0"}
+	if !slices.Equal(j.Args, want) {
+		t.Errorf("args = %q, want %q", j.Args, want)
+	}
+	if j.Output != "discovered\n" {
+		t.Errorf("output = %q", j.Output)
+	}
+	_, raw = do(t, ts, http.MethodGet, "/v1/jobs/"+j.ID+"/log", "")
+	if string(raw) != "discovered\n" {
+		t.Errorf("log = %q", raw)
+	}
+
+	resp, _ = do(t, ts, http.MethodPost, "/v1/discover", `{}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("missing subnet: status %d, want 400", resp.StatusCode)
+	}
+	resp, _ = do(t, ts, http.MethodPost, "/v1/discover", `{"bmc_subnet":"10.0.0.0/8","bogus":1}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("unknown field: status %d, want 400", resp.StatusCode)
+	}
+	resp, _ = do(t, ts, http.MethodPost, "/v1/discover", `{"bmc_subnet":"10.0.0.0/8","schedule":"tonight"}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("bad schedule: status %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestFirmwareJobCancel(t *testing.T) {
+	started := make(chan struct{})
+	ts := newTestServer(t, func(ctx context.Context, args []string, _, _, _ io.Writer) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 130, nil
+	})
+	resp, raw := do(t, ts, http.MethodPost, "/v1/firmware", `{"type":"cc","image_uri":"http://10.0.0.1/cc.bin","hosts":["a","b"],"batch_size":2}`)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("status %d: %s", resp.StatusCode, raw)
+	}
+	var j Job
+	if err := json.Unmarshal(raw, &j); err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []string{"--image-uri=http://10.0.0.1/cc.bin", "--hosts=a,b", "--type=cc", "--batch-size=2", "--format=json"} {
+		if !slices.Contains(j.Args, a) {
+			t.Errorf("args %q missing %s", j.Args, a)
+		}
+	}
+	<-started
+
+	resp, _ = do(t, ts, http.MethodDelete, "/v1/jobs/"+j.ID, "")
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("cancel: status %d", resp.StatusCode)
+	}
+	if j = waitJob(t, ts, j.ID); j.State != JobCanceled {
+		t.Errorf("state = %s, want %s", j.State, JobCanceled)
+	}
+	resp, _ = do(t, ts, http.MethodDelete, "/v1/jobs/"+j.ID, "")
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("cancel finished job: status %d, want 409", resp.StatusCode)
+	}
+	resp, _ = do(t, ts, http.MethodGet, "/v1/jobs/nope", "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown job: status %d, want 404", resp.StatusCode)
+	}
+
+	_, raw = do(t, ts, http.MethodGet, "/v1/jobs", "")
+	var list []Job
+	if err := json.Unmarshal(raw, &list); err != nil || len(list) != 1 {
+		t.Errorf("jobs = %s", raw)
+	}
+}
+
+func TestFirmwareStatus(t *testing.T) {
+	ts := newTestServer(t, func(_ context.Context, args []string, stdout, _, _ io.Writer) (int, error) {
+		if !slices.Contains(args, "--hosts=a") {
+			return 4, nil
+		}
+		fmt.Fprintln(stdout, `[{"host":"a"}]`)
+		return 0, nil
+	})
+	resp, raw := do(t, ts, http.MethodGet, "/v1/firmware/status?hosts=a", "")
+	if resp.StatusCode != http.StatusOK || strings.TrimSpace(string(raw)) != `[{"host":"a"}]` {
+		t.Errorf("status %d: %s", resp.StatusCode, raw)
+	}
+	resp, _ = do(t, ts, http.MethodGet, "/v1/firmware/status", "")
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("failed run: status %d, want 502", resp.StatusCode)
+	}
+}
+
+func TestOutputTruncated(t *testing.T) {
+	o := newOutput(4)
+	fmt.Fprint(o, "abcdef")
+	if got := o.String(); got != "[output truncated]\ncdef" {
+		t.Errorf("output = %q", got)
+	}
+}
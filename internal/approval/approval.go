@@ -0,0 +1,211 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package approval implements signed plans for destructive operations: one
+// operator records what a command would do, a different operator approves it,
+// and only then may the plan be executed.
+package approval
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"bootstrap/internal/safefile"
+)
+
+// Plan is a command recorded for approval. Everything but Approvals is covered
+// by the creator's signature.
+type Plan struct {
+	ID        string    `json:"id"`
+	Operation string    `json:"operation"` // e.g. "power off", "firmware"
+	Args      []string  `json:"args"`      // command line, without the program name
+	Hosts     []string  `json:"hosts"`     // the hosts the command resolved to
+	Inputs    []Input   `json:"inputs,omitempty"`
+	CreatedBy string    `json:"created_by"`
+	Created   time.Time `json:"created"`
+	Expires   time.Time `json:"expires"`
+	Signature []byte    `json:"signature"`
+
+	Approvals []Approval `json:"approvals,omitempty"`
+}
+
+// Input is a file the command reads, pinned by digest so it cannot change
+// between approval and execution.
+type Input struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// Approval is one operator's signed approval of a plan.
+type Approval struct {
+	By        string    `json:"by"`
+	At        time.Time `json:"at"`
+	Signature []byte    `json:"signature"`
+}
+
+// NewPlan returns an unsigned plan expiring after ttl.
+func NewPlan(op string, args, hosts []string, ttl time.Duration) (*Plan, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	return &Plan{ID: hex.EncodeToString(id), Operation: op, Args: args, Hosts: hosts, Created: now, Expires: now.Add(ttl)}, nil
+}
+
+// AddInput pins the content of the file at path.
+func (p *Plan) AddInput(path string) error {
+	sum, err := fileDigest(path)
+	if err != nil {
+		return err
+	}
+	p.Inputs = append(p.Inputs, Input{Path: path, SHA256: sum})
+	return nil
+}
+
+// CheckInputs reports inputs whose content changed since the plan was made.
+func (p *Plan) CheckInputs() error {
+	for _, in := range p.Inputs {
+		sum, err := fileDigest(in.Path)
+		if err != nil {
+			return err
+		}
+		if sum != in.SHA256 {
+			return fmt.Errorf("%s changed since plan %s was made", in.Path, p.ID)
+		}
+	}
+	return nil
+}
+
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// body is what the creator signs: the plan without signatures or approvals.
+func (p *Plan) body() []byte {
+	c := *p
+	c.Signature, c.Approvals = nil, nil
+	b, _ := json.Marshal(c) // only plain fields; cannot fail
+	return b
+}
+
+// approvalBody is what an approver signs: the digest of the signed plan, who
+// approves it and when.
+func (p *Plan) approvalBody(by string, at time.Time) []byte {
+	sum := sha256.Sum256(append(p.body(), p.Signature...))
+	b, _ := json.Marshal(struct {
+		Plan string    `json:"plan"`
+		By   string    `json:"by"`
+		At   time.Time `json:"at"`
+	}{hex.EncodeToString(sum[:]), by, at})
+	return b
+}
+
+// Sign records k's operator as the creator and signs the plan.
+func (p *Plan) Sign(k Key) {
+	p.CreatedBy = k.Operator
+	p.Signature = ed25519.Sign(k.Private, p.body())
+}
+
+// Approve adds k's operator's approval after checking the plan with Verify.
+func (p *Plan) Approve(k Key, r Roles, now time.Time) error {
+	if err := p.Verify(r, now); err != nil {
+		return err
+	}
+	if err := r.Check(k, RoleApprover); err != nil {
+		return err
+	}
+	if k.Operator == p.CreatedBy {
+		return fmt.Errorf("%s created plan %s and cannot approve it", k.Operator, p.ID)
+	}
+	if slices.ContainsFunc(p.Approvals, func(a Approval) bool { return a.By == k.Operator }) {
+		return fmt.Errorf("%s already approved plan %s", k.Operator, p.ID)
+	}
+	at := now.UTC().Truncate(time.Second)
+	p.Approvals = append(p.Approvals, Approval{By: k.Operator, At: at, Signature: ed25519.Sign(k.Private, p.approvalBody(k.Operator, at))})
+	return nil
+}
+
+// Verify checks the plan's signature and expiry.
+func (p *Plan) Verify(r Roles, now time.Time) error {
+	creator, ok := r.operator(p.CreatedBy)
+	if !ok || !slices.Contains(creator.Roles, RoleOperator) {
+		return fmt.Errorf("plan %s: creator %q is not an operator", p.ID, p.CreatedBy)
+	}
+	if !ed25519.Verify(creator.key, p.body(), p.Signature) {
+		return fmt.Errorf("plan %s: bad signature; the file was modified", p.ID)
+	}
+	if now.After(p.Expires) {
+		return fmt.Errorf("plan %s expired at %s", p.ID, p.Expires.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Approved checks that the plan is valid and carries the approvals r requires,
+// each from a different approver other than its creator.
+func (p *Plan) Approved(r Roles, now time.Time) error {
+	if err := p.Verify(r, now); err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	for _, a := range p.Approvals {
+		op, ok := r.operator(a.By)
+		switch {
+		case !ok || !slices.Contains(op.Roles, RoleApprover):
+			return fmt.Errorf("plan %s: %q is not an approver", p.ID, a.By)
+		case a.By == p.CreatedBy || seen[a.By]:
+			return fmt.Errorf("plan %s: duplicate approval by %s", p.ID, a.By)
+		case !ed25519.Verify(op.key, p.approvalBody(a.By, a.At), a.Signature):
+			return fmt.Errorf("plan %s: bad approval signature from %s", p.ID, a.By)
+		}
+		seen[a.By] = true
+	}
+	if len(seen) < r.Required() {
+		return fmt.Errorf("plan %s has %d of %d required approval(s)", p.ID, len(seen), r.Required())
+	}
+	return nil
+}
+
+// Load reads a plan file.
+func Load(path string) (*Plan, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Plan
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if p.ID == "" || len(p.Args) == 0 {
+		return nil, errors.New(path + ": not a plan file")
+	}
+	return &p, nil
+}
+
+// Save writes the plan to path atomically.
+func (p *Plan) Save(path string) error {
+	out, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return safefile.Write(path, append(out, '\n'), 0o644, 0)
+}
@@ -0,0 +1,149 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package approval
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testRoles creates keys for alice (operator), bob and carol (approvers) and
+// the matching roles file.
+func testRoles(t *testing.T, extra string) (map[string]Key, Roles) {
+	t.Helper()
+	dir := t.TempDir()
+	keys := map[string]Key{}
+	roles := "operators:\n"
+	for name, r := range map[string]string{"alice": "[operator]", "bob": "[approver]", "carol": "[operator, approver]"} {
+		pub, err := Generate(name, filepath.Join(dir, name+".key"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if keys[name], err = LoadKey(filepath.Join(dir, name+".key")); err != nil {
+			t.Fatal(err)
+		}
+		roles += "  - {name: " + name + ", key: " + pub + ", roles: " + r + "}\n"
+	}
+	path := filepath.Join(dir, "roles.yaml")
+	if err := os.WriteFile(path, []byte(roles+extra), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	r, err := LoadRoles(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys, r
+}
+
+func TestApprovalFlow(t *testing.T) {
+	keys, roles := testRoles(t, "approvals_required: 2\nplan_ttl: 1h\n")
+	now := time.Now()
+	p, err := NewPlan("power off", []string{"power", "--hosts=a,b", "off"}, []string{"a", "b"}, roles.TTL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Sign(keys["alice"])
+
+	path := filepath.Join(t.TempDir(), "plan.json")
+	if err := p.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if p, err = Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Approved(roles, now); err == nil || !strings.Contains(err.Error(), "0 of 2") {
+		t.Fatalf("unapproved plan: %v", err)
+	}
+	if err := p.Approve(keys["alice"], roles, now); err == nil {
+		t.Error("alice, an operator only, approved")
+	}
+	if err := p.Approve(keys["carol"], roles, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Approve(keys["carol"], roles, now); err == nil {
+		t.Error("carol approved twice")
+	}
+	if err := p.Approve(keys["bob"], roles, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Approved(roles, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Approved(roles, now.Add(2*time.Hour)); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("expired plan: %v", err)
+	}
+
+	// Editing the command voids the creator's signature
+	tampered := *p
+	tampered.Args = []string{"power", "--hosts=a,b,c", "off"}
+	if err := tampered.Approved(roles, now); err == nil || !strings.Contains(err.Error(), "bad signature") {
+		t.Errorf("tampered plan: %v", err)
+	}
+	// A forged approval does not verify
+	forged := *p
+	forged.Approvals = append([]Approval(nil), p.Approvals...)
+	forged.Approvals[1].At = forged.Approvals[1].At.Add(time.Minute)
+	if err := forged.Approved(roles, now); err == nil || !strings.Contains(err.Error(), "bad approval signature") {
+		t.Errorf("forged approval: %v", err)
+	}
+}
+
+func TestCreatorCannotApprove(t *testing.T) {
+	keys, roles := testRoles(t, "")
+	p, err := NewPlan("firmware", []string{"firmware"}, []string{"a"}, roles.TTL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Sign(keys["carol"])
+	if err := p.Approve(keys["carol"], roles, time.Now()); err == nil {
+		t.Error("creator approved own plan")
+	}
+	p.Approvals = []Approval{{By: "carol", At: p.Created, Signature: p.Signature}}
+	if err := p.Approved(roles, time.Now()); err == nil {
+		t.Error("self-approval accepted")
+	}
+}
+
+func TestInputsPinned(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts.txt")
+	if err := os.WriteFile(path, []byte("a\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewPlan("firmware", []string{"firmware"}, []string{"a"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddInput(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.CheckInputs(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("a\nb\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.CheckInputs(); err == nil {
+		t.Error("changed input accepted")
+	}
+}
+
+func TestKeys(t *testing.T) {
+	keys, roles := testRoles(t, "")
+	k := keys["bob"]
+	k.Operator = "carol" // bob's key claiming to be carol
+	if err := roles.Check(k, RoleApprover); err == nil {
+		t.Error("mismatched key accepted")
+	}
+	path := filepath.Join(t.TempDir(), "dave.key")
+	if _, err := Generate("dave", path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Generate("dave", path); err == nil {
+		t.Error("Generate overwrote an existing key")
+	}
+}
@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package approval
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Roles
+const (
+	RoleOperator = "operator" // may record and execute plans
+	RoleApprover = "approver" // may approve other operators' plans
+)
+
+// DefaultTTL is how long a plan stays valid when the roles file sets no plan_ttl.
+const DefaultTTL = 24 * time.Hour
+
+// Operator is a person allowed to take part in approvals.
+type Operator struct {
+	Name  string   `yaml:"name"`
+	Key   string   `yaml:"key"` // base64 ed25519 public key, as printed by Generate
+	Roles []string `yaml:"roles"`
+
+	key ed25519.PublicKey
+}
+
+// Roles is the roles file: who may do what, and how many approvals a plan needs.
+type Roles struct {
+	Operators []Operator `yaml:"operators"`
+	// ApprovalsRequired is the number of distinct approvers (default 1).
+	ApprovalsRequired int           `yaml:"approvals_required"`
+	PlanTTL           time.Duration `yaml:"plan_ttl"`
+}
+
+// LoadRoles reads and validates a roles file.
+func LoadRoles(path string) (Roles, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Roles{}, err
+	}
+	var r Roles
+	if err := yaml.Unmarshal(raw, &r); err != nil {
+		return Roles{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	seen := map[string]bool{}
+	for i := range r.Operators {
+		op := &r.Operators[i]
+		if op.Name == "" || seen[op.Name] {
+			return Roles{}, fmt.Errorf("%s: operators[%d]: missing or duplicate name", path, i)
+		}
+		seen[op.Name] = true
+		k, err := base64.StdEncoding.DecodeString(op.Key)
+		if err != nil || len(k) != ed25519.PublicKeySize {
+			return Roles{}, fmt.Errorf("%s: operator %s: key is not a base64 ed25519 public key", path, op.Name)
+		}
+		op.key = k
+		for _, role := range op.Roles {
+			if role != RoleOperator && role != RoleApprover {
+				return Roles{}, fmt.Errorf("%s: operator %s: unknown role %q", path, op.Name, role)
+			}
+		}
+	}
+	if r.ApprovalsRequired < 0 || r.PlanTTL < 0 {
+		return Roles{}, fmt.Errorf("%s: approvals_required and plan_ttl must not be negative", path)
+	}
+	return r, nil
+}
+
+// TTL returns how long a new plan stays valid.
+func (r Roles) TTL() time.Duration {
+	if r.PlanTTL > 0 {
+		return r.PlanTTL
+	}
+	return DefaultTTL
+}
+
+// Required returns the number of distinct approvals a plan needs.
+func (r Roles) Required() int {
+	return max(r.ApprovalsRequired, 1)
+}
+
+func (r Roles) operator(name string) (Operator, bool) {
+	i := slices.IndexFunc(r.Operators, func(o Operator) bool { return o.Name == name })
+	if i < 0 {
+		return Operator{}, false
+	}
+	return r.Operators[i], true
+}
+
+// Check verifies that k belongs to an operator holding role.
+func (r Roles) Check(k Key, role string) error {
+	op, ok := r.operator(k.Operator)
+	if !ok || !op.key.Equal(k.Private.Public()) {
+		return fmt.Errorf("key for %q does not match the roles file", k.Operator)
+	}
+	if !slices.Contains(op.Roles, role) {
+		return fmt.Errorf("%s does not have the %s role", k.Operator, role)
+	}
+	return nil
+}
+
+// Key is an operator's signing key.
+type Key struct {
+	Operator string
+	Private  ed25519.PrivateKey
+}
+
+// pemType and pemOperator describe the key file: a PKCS #8 PEM block naming
+// its operator in a header.
+const (
+	pemType     = "PRIVATE KEY"
+	pemOperator = "Operator"
+)
+
+// LoadKey reads a key file written by Generate.
+func LoadKey(path string) (Key, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, err
+	}
+	b, _ := pem.Decode(raw)
+	if b == nil || b.Type != pemType || b.Headers[pemOperator] == "" {
+		return Key{}, errors.New(path + ": not an operator key")
+	}
+	k, err := x509.ParsePKCS8PrivateKey(b.Bytes)
+	if err != nil {
+		return Key{}, fmt.Errorf("%s: %w", path, err)
+	}
+	priv, ok := k.(ed25519.PrivateKey)
+	if !ok {
+		return Key{}, errors.New(path + ": not an ed25519 key")
+	}
+	return Key{Operator: b.Headers[pemOperator], Private: priv}, nil
+}
+
+// Generate writes a new key for operator to path (mode 0600, refusing to
+// overwrite) and returns its public key for the roles file.
+func Generate(operator, path string) (string, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return "", err
+	}
+	if err := pem.Encode(f, &pem.Block{Type: pemType, Headers: map[string]string{pemOperator: operator}, Bytes: der}); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(pub), nil
+}
@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package bootcheck verifies that provisioned nodes came up on the addresses
+// allocated to them: they answer ping, accept TCP connections and, optionally,
+// run a command over SSH whose output shows the expected image booted.
+package bootcheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Checker runs the checks for one node at a time. The zero value checks nothing.
+type Checker struct {
+	// Ping sends an ICMP echo to ip; nil skips the ping check. See ExecPing.
+	Ping func(ctx context.Context, ip string) error
+	// Dial opens TCP connections; nil uses a net.Dialer.
+	Dial func(ctx context.Context, network, addr string) (net.Conn, error)
+	// Ports must accept a TCP connection.
+	Ports []int
+	// SSH, when set, runs Command on port SSHPort (default 22) and, when Expect
+	// is set, requires its output to match.
+	SSH     *ssh.ClientConfig
+	SSHPort int
+	Command string
+	Expect  *regexp.Regexp
+}
+
+// Step is the outcome of one check.
+type Step struct {
+	Name   string // ping, tcp/<port> or ssh
+	Err    error
+	Output string // ssh only: trimmed command output
+}
+
+// Result is the outcome of checking one node.
+type Result struct {
+	Xname string
+	IP    string
+	Steps []Step
+}
+
+// OK reports whether every step passed.
+func (r Result) OK() bool {
+	for _, s := range r.Steps {
+		if s.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// String is e.g. "ping ok, tcp/22 ok, ssh failed: ...".
+func (r Result) String() string {
+	parts := make([]string, len(r.Steps))
+	for i, s := range r.Steps {
+		if s.Err != nil {
+			parts[i] = fmt.Sprintf("%s failed: %v", s.Name, s.Err)
+		} else {
+			parts[i] = s.Name + " ok"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Check runs every configured check against ip. A later check still runs when
+// an earlier one fails, so the result shows how far the node got.
+func (c *Checker) Check(ctx context.Context, xname, ip string) Result {
+	r := Result{Xname: xname, IP: ip}
+	if c.Ping != nil {
+		r.Steps = append(r.Steps, Step{Name: "ping", Err: c.Ping(ctx, ip)})
+	}
+	for _, p := range c.Ports {
+		conn, err := c.dial(ctx, net.JoinHostPort(ip, strconv.Itoa(p)))
+		if err == nil {
+			conn.Close() //nolint:errcheck
+		}
+		r.Steps = append(r.Steps, Step{Name: fmt.Sprintf("tcp/%d", p), Err: err})
+	}
+	if c.SSH != nil {
+		out, err := c.runSSH(ctx, ip)
+		if err == nil && c.Expect != nil && !c.Expect.MatchString(out) {
+			err = fmt.Errorf("output %q does not match %q", firstLine(out), c.Expect)
+		}
+		r.Steps = append(r.Steps, Step{Name: "ssh", Err: err, Output: out})
+	}
+	return r
+}
+
+func (c *Checker) dial(ctx context.Context, addr string) (net.Conn, error) {
+	if c.Dial != nil {
+		return c.Dial(ctx, "tcp", addr)
+	}
+	var d net.Dialer
+	return d.DialContext(ctx, "tcp", addr)
+}
+
+// runSSH runs c.Command on the node and returns its trimmed combined output.
+func (c *Checker) runSSH(ctx context.Context, ip string) (string, error) {
+	port := c.SSHPort
+	if port == 0 {
+		port = 22
+	}
+	addr := net.JoinHostPort(ip, strconv.Itoa(port))
+	conn, err := c.dial(ctx, addr)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close() //nolint:errcheck
+	if dl, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(dl)
+	}
+	sc, chans, reqs, err := ssh.NewClientConn(conn, addr, c.SSH)
+	if err != nil {
+		return "", err
+	}
+	client := ssh.NewClient(sc, chans, reqs)
+	defer client.Close() //nolint:errcheck
+	sess, err := client.NewSession()
+	if err != nil {
+		return "", err
+	}
+	defer sess.Close() //nolint:errcheck
+	out, err := sess.CombinedOutput(c.Command)
+	text := strings.TrimSpace(string(out))
+	if err != nil {
+		return text, fmt.Errorf("%s: %w", c.Command, err)
+	}
+	return text, nil
+}
+
+// ExecPing sends one ICMP echo to ip with the system ping command, which holds
+// the privileges raw ICMP sockets need. timeout bounds the wait for the reply.
+func ExecPing(timeout time.Duration) func(ctx context.Context, ip string) error {
+	return func(ctx context.Context, ip string) error {
+		out, err := exec.CommandContext(ctx, "ping", pingArgs(runtime.GOOS, timeout, ip)...).CombinedOutput()
+		if err != nil {
+			if msg := firstLine(strings.TrimSpace(string(out))); msg != "" && !strings.HasPrefix(msg, "PING") {
+				return fmt.Errorf("%w: %s", err, msg)
+			}
+			return fmt.Errorf("no reply: %w", err)
+		}
+		return nil
+	}
+}
+
+// pingArgs returns the arguments that make goos's ping send one echo and wait
+// up to timeout: -W is in seconds on Linux but milliseconds on macOS and the
+// BSDs, and Windows spells both options differently.
+func pingArgs(goos string, timeout time.Duration, ip string) []string {
+	secs := max(1, int(timeout.Round(time.Second)/time.Second))
+	switch goos {
+	case "windows":
+		return []string{"-n", "1", "-w", strconv.Itoa(secs * 1000), ip}
+	case "darwin", "freebsd", "netbsd", "openbsd":
+		return []string{"-c", "1", "-W", strconv.Itoa(secs * 1000), ip}
+	}
+	return []string{"-c", "1", "-W", strconv.Itoa(secs), ip}
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package bootcheck
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"net"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// listen returns a listener on 127.0.0.1 and its port.
+func listen(t *testing.T) (net.Listener, int) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() }) //nolint:errcheck
+	return ln, ln.Addr().(*net.TCPAddr).Port
+}
+
+// closedPort returns a port nothing listens on.
+func closedPort(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close() //nolint:errcheck
+	return port
+}
+
+// serveSSH answers every exec request on ln with output and exit status 0.
+func serveSSH(t *testing.T, ln net.Listener, output string) {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &ssh.ServerConfig{NoClientAuth: true}
+	cfg.AddHostKey(signer)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, chans, reqs, err := ssh.NewServerConn(conn, cfg)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+				for nc := range chans {
+					ch, reqs, err := nc.Accept()
+					if err != nil {
+						continue
+					}
+					go func() {
+						for req := range reqs {
+							if req.Type != "exec" {
+								_ = req.Reply(false, nil)
+								continue
+							}
+							_ = req.Reply(true, nil)
+							_, _ = ch.Write([]byte(output + "\n"))
+							status := make([]byte, 4)
+							binary.BigEndian.PutUint32(status, 0)
+							_, _ = ch.SendRequest("exit-status", false, status)
+							ch.Close() //nolint:errcheck
+						}
+					}()
+				}
+			}()
+		}
+	}()
+}
+
+func TestCheckPorts(t *testing.T) {
+	_, open := listen(t)
+	closed := closedPort(t)
+	c := &Checker{
+		Ping:  func(context.Context, string) error { return nil },
+		Ports: []int{open, closed},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	r := c.Check(ctx, "x1000c0s0b0n0", "127.0.0.1")
+	if r.OK() {
+		t.Fatalf("want failure with port %d closed: %s", closed, r)
+	}
+	if len(r.Steps) != 3 || r.Steps[0].Err != nil || r.Steps[1].Err != nil || r.Steps[2].Err == nil {
+		t.Fatalf("steps = %+v", r.Steps)
+	}
+	want := "ping ok, tcp/" + strconv.Itoa(open) + " ok, tcp/" + strconv.Itoa(closed) + " failed: "
+	if !strings.HasPrefix(r.String(), want) {
+		t.Errorf("String() = %q, want prefix %q", r.String(), want)
+	}
+
+	c.Ping = func(context.Context, string) error { return errors.New("no reply") }
+	c.Ports = []int{open}
+	if r := c.Check(ctx, "x1000c0s0b0n0", "127.0.0.1"); r.OK() || r.Steps[0].Err == nil || r.Steps[1].Err != nil {
+		t.Errorf("ping failure: steps = %+v", r.Steps)
+	}
+}
+
+func TestCheckSSH(t *testing.T) {
+	ln, port := listen(t)
+	serveSSH(t, ln, `NAME="SLES"`+"\n"+`VERSION_ID="15.6"`)
+	c := &Checker{
+		SSH: &ssh.ClientConfig{
+			User:            "root",
+			HostKeyCallback: ssh.InsecureIgnoreHostKey(), //nolint:gosec
+		},
+		SSHPort: port,
+		Command: "cat /etc/os-release",
+		Expect:  regexp.MustCompile(`VERSION_ID="15\.6"`),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	r := c.Check(ctx, "x1000c0s0b0n0", "127.0.0.1")
+	if !r.OK() {
+		t.Fatalf("want ok: %s", r)
+	}
+	if got := r.Steps[0].Output; !strings.Contains(got, "SLES") {
+		t.Errorf("output = %q", got)
+	}
+
+	c.Expect = regexp.MustCompile(`VERSION_ID="15\.7"`)
+	r = c.Check(ctx, "x1000c0s0b0n0", "127.0.0.1")
+	if r.OK() || !strings.Contains(r.String(), "does not match") {
+		t.Errorf("mismatch: %s", r)
+	}
+}
+
+func TestPingArgs(t *testing.T) {
+	for goos, want := range map[string][]string{
+		"linux":   {"-c", "1", "-W", "3", "10.1.0.5"},
+		"darwin":  {"-c", "1", "-W", "3000", "10.1.0.5"},
+		"windows": {"-n", "1", "-w", "3000", "10.1.0.5"},
+	} {
+		if got := pingArgs(goos, 2500*time.Millisecond, "10.1.0.5"); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: pingArgs = %q, want %q", goos, got, want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package certaudit reads the TLS certificates BMCs serve and flags those that
+// expire soon, are self-signed or were not issued by the site CA.
+package certaudit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"os"
+	"time"
+)
+
+// Dialer opens the TCP connection to a BMC; a zero net.Dialer's DialContext fits.
+type Dialer func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// Cert is the certificate a BMC serves and what was found wrong with it.
+type Cert struct {
+	Host       string    `json:"host"`
+	Xname      string    `json:"xname,omitempty"`
+	Subject    string    `json:"subject"`
+	Issuer     string    `json:"issuer"`
+	DNSNames   []string  `json:"dns_names,omitempty"`
+	IPs        []string  `json:"ips,omitempty"`
+	NotBefore  time.Time `json:"not_before"`
+	NotAfter   time.Time `json:"not_after"`
+	DaysLeft   int       `json:"days_left"`
+	SelfSigned bool      `json:"self_signed"`
+	SiteCA     *bool     `json:"site_ca,omitempty"` // nil when no site CA was given
+	SHA256     string    `json:"sha256"`
+	Problems   []string  `json:"problems,omitempty"`
+}
+
+// Fetch returns the certificate chain addr presents, leaf first. The chain is
+// not verified: a BMC with a bad certificate is exactly what is looked for.
+func Fetch(ctx context.Context, dial Dialer, addr string) ([]*x509.Certificate, error) {
+	if dial == nil {
+		var d net.Dialer
+		dial = d.DialContext
+	}
+	raw, err := dial(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	host, _, _ := net.SplitHostPort(addr)
+	cfg := &tls.Config{InsecureSkipVerify: true} //nolint:gosec // the certificate is inspected, not trusted
+	if net.ParseIP(host) == nil {
+		cfg.ServerName = host
+	}
+	conn := tls.Client(raw, cfg)
+	defer conn.Close() //nolint:errcheck
+	if err := conn.HandshakeContext(ctx); err != nil {
+		return nil, fmt.Errorf("TLS handshake: %w", err)
+	}
+	chain := conn.ConnectionState().PeerCertificates
+	if len(chain) == 0 {
+		return nil, errors.New("no certificate presented")
+	}
+	return chain, nil
+}
+
+// Check describes the leaf of chain. It is flagged when it expires within warn
+// of now (or has expired), is self-signed, or, with a site CA pool, does not
+// chain to it.
+func Check(chain []*x509.Certificate, siteCA *x509.CertPool, now time.Time, warn time.Duration) Cert {
+	leaf := chain[0]
+	sum := sha256.Sum256(leaf.Raw)
+	c := Cert{
+		Subject:   leaf.Subject.String(),
+		Issuer:    leaf.Issuer.String(),
+		DNSNames:  leaf.DNSNames,
+		NotBefore: leaf.NotBefore,
+		NotAfter:  leaf.NotAfter,
+		DaysLeft:  int(math.Floor(leaf.NotAfter.Sub(now).Hours() / 24)),
+		SHA256:    hex.EncodeToString(sum[:]),
+	}
+	for _, ip := range leaf.IPAddresses {
+		c.IPs = append(c.IPs, ip.String())
+	}
+	c.SelfSigned = bytes.Equal(leaf.RawIssuer, leaf.RawSubject) && leaf.CheckSignature(leaf.SignatureAlgorithm, leaf.RawTBSCertificate, leaf.Signature) == nil
+	switch {
+	case !now.Before(leaf.NotAfter):
+		c.Problems = append(c.Problems, "expired")
+	case leaf.NotAfter.Sub(now) < warn:
+		c.Problems = append(c.Problems, fmt.Sprintf("expires in %d day(s)", c.DaysLeft))
+	}
+	if now.Before(leaf.NotBefore) {
+		c.Problems = append(c.Problems, "not valid yet")
+	}
+	if c.SelfSigned {
+		c.Problems = append(c.Problems, "self-signed")
+	}
+	if siteCA != nil {
+		ok := issuedBy(chain, siteCA)
+		c.SiteCA = &ok
+		if !ok {
+			c.Problems = append(c.Problems, "not issued by the site CA")
+		}
+	}
+	return c
+}
+
+// issuedBy reports whether the leaf chains to roots, through the intermediates
+// the BMC sent. Validity dates and names are left to the other checks.
+func issuedBy(chain []*x509.Certificate, roots *x509.CertPool) bool {
+	inter := x509.NewCertPool()
+	for _, c := range chain[1:] {
+		inter.AddCert(c)
+	}
+	_, err := chain[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: inter,
+		CurrentTime:   chain[0].NotBefore.Add(time.Second),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err == nil
+}
+
+// LoadCA reads the PEM certificates of the site CA bundle at path.
+func LoadCA(path string) (*x509.CertPool, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	n := 0
+	for {
+		var blk *pem.Block
+		blk, b = pem.Decode(b)
+		if blk == nil {
+			break
+		}
+		if blk.Type != "CERTIFICATE" {
+			continue
+		}
+		c, err := x509.ParseCertificate(blk.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		pool.AddCert(c)
+		n++
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("%s: no PEM certificates", path)
+	}
+	return pool, nil
+}
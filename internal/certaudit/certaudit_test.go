@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package certaudit
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newCert returns a certificate for cn signed by parent (self-signed when nil).
+func newCert(t *testing.T, cn string, notAfter time.Time, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:              notAfter,
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		IPAddresses:           []net.IP{net.ParseIP("10.1.0.10")},
+	}
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, key
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	ca, caKey := newCert(t, "Site CA", now.AddDate(5, 0, 0), true, nil, nil)
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	good, _ := newCert(t, "x1000c0s0b0", now.AddDate(1, 0, 0), false, ca, caKey)
+	soon, _ := newCert(t, "x1000c0s1b0", now.AddDate(0, 0, 10), false, ca, caKey)
+	vendor, _ := newCert(t, "iLO", now.AddDate(0, 0, -1), false, nil, nil)
+	warn := 30 * 24 * time.Hour
+
+	c := Check([]*x509.Certificate{good}, pool, now, warn)
+	if len(c.Problems) != 0 || c.SelfSigned || c.SiteCA == nil || !*c.SiteCA || c.DaysLeft != 365 || !reflect.DeepEqual(c.IPs, []string{"10.1.0.10"}) {
+		t.Errorf("good: %+v", c)
+	}
+	if c := Check([]*x509.Certificate{soon}, pool, now, warn); !reflect.DeepEqual(c.Problems, []string{"expires in 10 day(s)"}) {
+		t.Errorf("soon: %v", c.Problems)
+	}
+	c = Check([]*x509.Certificate{vendor}, pool, now, warn)
+	if want := []string{"expired", "self-signed", "not issued by the site CA"}; !reflect.DeepEqual(c.Problems, want) {
+		t.Errorf("vendor: %v, want %v", c.Problems, want)
+	}
+	if c := Check([]*x509.Certificate{vendor}, nil, now, warn); c.SiteCA != nil || len(c.Problems) != 2 {
+		t.Errorf("without a site CA: %+v", c)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	ts := httptest.NewTLSServer(http.NotFoundHandler())
+	defer ts.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	chain, err := Fetch(ctx, nil, ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(chain[0].Subject.String(), "Acme Co") {
+		t.Errorf("subject %s", chain[0].Subject)
+	}
+}
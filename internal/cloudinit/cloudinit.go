@@ -0,0 +1,26 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package cloudinit writes per-node cloud-init NoCloud data (meta-data and
+// user-data) for nodes[] entries.
+package cloudinit
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Write stores the meta-data and user-data documents of a node as
+// dir/<instanceID>/meta-data and user-data, the layout a NoCloud seed or an HTTP
+// data source serves.
+func Write(dir, instanceID string, metaData, userData []byte) error {
+	out := filepath.Join(dir, instanceID)
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(out, "meta-data"), metaData, 0o644); err != nil { //nolint:gosec // read by the data source
+		return err
+	}
+	return os.WriteFile(filepath.Join(out, "user-data"), userData, 0o644) //nolint:gosec
+}
@@ -0,0 +1,28 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package cloudinit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	meta, user := "instance-id: x1000c0s0b0n0\n", "#cloud-config\nhostname: nid000001\n"
+	if err := Write(dir, "x1000c0s0b0n0", []byte(meta), []byte(user)); err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]string{"meta-data": meta, "user-data": user} {
+		got, err := os.ReadFile(filepath.Join(dir, "x1000c0s0b0n0", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", file, got, want)
+		}
+	}
+}
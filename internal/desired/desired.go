@@ -0,0 +1,251 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package desired models a declarative desired state for BMCs and computes the
+// changes needed to reconcile live state toward it.
+package desired
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"bootstrap/internal/sshkeys"
+	"bootstrap/pkg/inventory"
+	"bootstrap/pkg/redfish"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Firmware is the desired version of one FirmwareInventory target.
+type Firmware struct {
+	Target   string `yaml:"target"`
+	Version  string `yaml:"version"`
+	ImageURI string `yaml:"image_uri"`
+	Protocol string `yaml:"protocol"`
+}
+
+// BMCSettings are manager-level settings applied to every BMC.
+type BMCSettings struct {
+	NTPServers []string `yaml:"ntp_servers"`
+}
+
+// BIOS is the desired BIOS profile applied to every system on every BMC.
+type BIOS struct {
+	Attributes map[string]any `yaml:"attributes"`
+}
+
+// Account is a BMC user account that must exist with the given role.
+type Account struct {
+	UserName string `yaml:"username"`
+	Role     string `yaml:"role"`
+}
+
+// State is the root of a desired-state YAML file.
+type State struct {
+	// Inventory is a path (relative to the state file) to an inventory YAML whose
+	// bmcs[] are targeted. BMCs listed inline are used in addition.
+	Inventory string            `yaml:"inventory"`
+	BMCs      []inventory.Entry `yaml:"bmcs"`
+	Firmware  []Firmware        `yaml:"firmware"`
+	BIOS      BIOS              `yaml:"bios"`
+	BMC       BMCSettings       `yaml:"bmc"`
+	Accounts  []Account         `yaml:"accounts"`
+	// SSHKeys are public keys (or paths to .pub files) that must be authorized.
+	SSHKeys []string `yaml:"ssh_keys"`
+}
+
+// Load reads a desired-state file, resolving the referenced inventory and any SSH
+// key files.
+func Load(path string) (State, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return State{}, err
+	}
+	var st State
+	if err := yaml.Unmarshal(raw, &st); err != nil {
+		return State{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	dir := filepath.Dir(path)
+	if st.Inventory != "" {
+		invPath := st.Inventory
+		if !filepath.IsAbs(invPath) {
+			invPath = filepath.Join(dir, invPath)
+		}
+		b, err := os.ReadFile(invPath)
+		if err != nil {
+			return State{}, err
+		}
+		var doc inventory.FileFormat
+		if err := yaml.Unmarshal(b, &doc); err != nil {
+			return State{}, fmt.Errorf("parse %s: %w", invPath, err)
+		}
+		st.BMCs = append(doc.BMCs, st.BMCs...)
+	}
+	var keys []string
+	for _, k := range st.SSHKeys {
+		if strings.HasPrefix(k, "ssh-") || strings.HasPrefix(k, "ecdsa-") {
+			keys = append(keys, k)
+			continue
+		}
+		p := k
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+		fk, err := sshkeys.ReadFiles([]string{p})
+		if err != nil {
+			return State{}, err
+		}
+		keys = append(keys, fk...)
+	}
+	st.SSHKeys = keys
+	for i, fw := range st.Firmware {
+		if fw.Target == "" || fw.Version == "" || fw.ImageURI == "" {
+			return State{}, fmt.Errorf("firmware[%d]: target, version and image_uri are required", i)
+		}
+		if fw.Protocol == "" {
+			st.Firmware[i].Protocol = "HTTP"
+		}
+	}
+	return st, nil
+}
+
+// Hosts returns the BMC hosts (IP, falling back to xname) in the state.
+func (st State) Hosts() []string {
+	out := make([]string, 0, len(st.BMCs))
+	for _, b := range st.BMCs {
+		h := b.IP
+		if h == "" {
+			h = b.Xname
+		}
+		out = append(out, h)
+	}
+	return out
+}
+
+// Change kinds, in the order they are applied.
+const (
+	KindSSHKeys  = "ssh-keys"
+	KindNTP      = "ntp"
+	KindFirmware = "firmware"
+	KindBIOS     = "bios"
+	KindAccount  = "account"
+)
+
+// Change statuses
+const (
+	StatusPending = "pending"
+	StatusPlanned = "planned"
+	StatusApplied = "applied"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// Change is one difference between live and desired state on a host.
+type Change struct {
+	Host    string `json:"host"`
+	Kind    string `json:"kind"`
+	Target  string `json:"target,omitempty"`
+	Current string `json:"current"`
+	Desired string `json:"desired"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Observed is the live state of a host, limited to what the desired state covers.
+type Observed struct {
+	SSHKeys    []string
+	NTPServers []string
+	Firmware   map[string]string
+	BIOS       []redfish.SystemBios
+	Accounts   []redfish.Account
+}
+
+// order ranks changes so BMC-level settings go first, then BMC firmware before
+// other firmware, then BIOS attributes last (they are staged for next boot).
+func order(c Change) int {
+	switch c.Kind {
+	case KindSSHKeys:
+		return 0
+	case KindNTP:
+		return 1
+	case KindAccount:
+		return 1
+	case KindFirmware:
+		if strings.Contains(strings.ToUpper(c.Target), "BMC") {
+			return 2
+		}
+		return 3
+	default:
+		return 4
+	}
+}
+
+// Diff returns the changes needed on host to move obs to st, in apply order.
+func Diff(host string, st State, obs Observed) []Change {
+	var out []Change
+	add := func(kind, target, cur, want string) {
+		out = append(out, Change{Host: host, Kind: kind, Target: target, Current: cur, Desired: want, Status: StatusPending})
+	}
+	if len(st.SSHKeys) > 0 {
+		if missing := sshkeys.Missing(obs.SSHKeys, st.SSHKeys); len(missing) > 0 {
+			add(KindSSHKeys, "", fmt.Sprintf("%d key(s)", len(obs.SSHKeys)), fmt.Sprintf("%d missing key(s) added", len(missing)))
+		}
+	}
+	if len(st.BMC.NTPServers) > 0 && strings.Join(obs.NTPServers, ",") != strings.Join(st.BMC.NTPServers, ",") {
+		add(KindNTP, "", strings.Join(obs.NTPServers, ","), strings.Join(st.BMC.NTPServers, ","))
+	}
+	for _, a := range st.Accounts {
+		cur := "(absent)"
+		for _, have := range obs.Accounts {
+			if have.UserName == a.UserName {
+				cur = "role=" + have.RoleID
+				if !have.Enabled {
+					cur += ",disabled"
+				}
+				break
+			}
+		}
+		want := "role=" + a.Role
+		if a.Role == "" {
+			if cur != "(absent)" && !strings.HasSuffix(cur, ",disabled") {
+				continue
+			}
+			want = "(present)"
+		} else if cur == want {
+			continue
+		}
+		add(KindAccount, a.UserName, cur, want)
+	}
+	for _, fw := range st.Firmware {
+		if cur := obs.Firmware[fw.Target]; cur != fw.Version {
+			add(KindFirmware, fw.Target, cur, fw.Version)
+		}
+	}
+	if len(st.BIOS.Attributes) > 0 {
+		names := make([]string, 0, len(st.BIOS.Attributes))
+		for n := range st.BIOS.Attributes {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, sys := range obs.BIOS {
+			for _, n := range names {
+				want := fmt.Sprint(st.BIOS.Attributes[n])
+				cur, ok := sys.Attributes[n]
+				if ok && fmt.Sprint(cur) == want {
+					continue
+				}
+				curStr := "(unset)"
+				if ok {
+					curStr = fmt.Sprint(cur)
+				}
+				add(KindBIOS, sys.SystemPath, n+"="+curStr, n+"="+want)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return order(out[i]) < order(out[j]) })
+	return out
+}
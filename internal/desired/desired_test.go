@@ -0,0 +1,130 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package desired
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"bootstrap/pkg/redfish"
+)
+
+const testKey = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIExample admin@example"
+
+func TestDiffOrderAndContent(t *testing.T) {
+	st := State{
+		Firmware: []Firmware{
+			{Target: "/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS", Version: "1.2"},
+			{Target: "/redfish/v1/UpdateService/FirmwareInventory/BMC", Version: "2.0"},
+		},
+		BIOS:    BIOS{Attributes: map[string]any{"SMT": "Enabled", "Cores": 8}},
+		BMC:     BMCSettings{NTPServers: []string{"10.0.0.1"}},
+		SSHKeys: []string{testKey},
+	}
+	obs := Observed{
+		Firmware: map[string]string{
+			"/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS": "1.1",
+			"/redfish/v1/UpdateService/FirmwareInventory/BMC":        "1.9",
+		},
+		BIOS: []redfish.SystemBios{{SystemPath: "/redfish/v1/Systems/Node0", Attributes: map[string]any{"SMT": "Enabled", "Cores": float64(4)}}},
+	}
+	changes := Diff("h1", st, obs)
+	want := []struct{ kind, target, desired string }{
+		{KindSSHKeys, "", "1 missing key(s) added"},
+		{KindNTP, "", "10.0.0.1"},
+		{KindFirmware, "/redfish/v1/UpdateService/FirmwareInventory/BMC", "2.0"},
+		{KindFirmware, "/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS", "1.2"},
+		{KindBIOS, "/redfish/v1/Systems/Node0", "Cores=8"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("got %d changes, want %d: %+v", len(changes), len(want), changes)
+	}
+	for i, w := range want {
+		c := changes[i]
+		if c.Kind != w.kind || c.Target != w.target || c.Desired != w.desired || c.Status != StatusPending {
+			t.Errorf("change %d = %+v, want %+v", i, c, w)
+		}
+	}
+}
+
+func TestDiffInSync(t *testing.T) {
+	st := State{
+		BIOS:    BIOS{Attributes: map[string]any{"SMT": true}},
+		BMC:     BMCSettings{NTPServers: []string{"a", "b"}},
+		SSHKeys: []string{testKey},
+	}
+	obs := Observed{
+		SSHKeys:    []string{testKey},
+		NTPServers: []string{"a", "b"},
+		BIOS:       []redfish.SystemBios{{SystemPath: "/s", Attributes: map[string]any{"SMT": true}}},
+	}
+	if changes := Diff("h1", st, obs); len(changes) != 0 {
+		t.Fatalf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestDiffAccounts(t *testing.T) {
+	st := State{Accounts: []Account{
+		{UserName: "root", Role: "Administrator"},
+		{UserName: "ops", Role: "Operator"},
+		{UserName: "monitor"},
+		{UserName: "backup"},
+	}}
+	obs := Observed{Accounts: []redfish.Account{
+		{UserName: "root", RoleID: "Administrator", Enabled: true},
+		{UserName: "ops", RoleID: "ReadOnly", Enabled: true},
+		{UserName: "backup", RoleID: "ReadOnly", Enabled: false},
+	}}
+	changes := Diff("h1", st, obs)
+	want := map[string][2]string{
+		"ops":     {"role=ReadOnly", "role=Operator"},
+		"monitor": {"(absent)", "(present)"},
+		"backup":  {"role=ReadOnly,disabled", "(present)"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("got %+v", changes)
+	}
+	for _, c := range changes {
+		w, ok := want[c.Target]
+		if !ok || c.Kind != KindAccount || c.Current != w[0] || c.Desired != w[1] {
+			t.Errorf("unexpected change %+v", c)
+		}
+	}
+}
+
+func TestLoadResolvesInventoryAndKeys(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("inventory.yaml", "bmcs:\n  - xname: x1000c0s0b0\n    mac: 02:00:00:00:00:01\n    ip: 10.1.0.2\n")
+	write("admin.pub", testKey+"\n")
+	write("state.yaml", `inventory: inventory.yaml
+bmcs:
+  - xname: x1000c0s1b0
+firmware:
+  - target: /redfish/v1/UpdateService/FirmwareInventory/BMC
+    version: "2.0"
+    image_uri: http://10.0.0.1/bmc.bin
+ssh_keys:
+  - admin.pub
+`)
+	st, err := Load(filepath.Join(dir, "state.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hosts := st.Hosts(); len(hosts) != 2 || hosts[0] != "10.1.0.2" || hosts[1] != "x1000c0s1b0" {
+		t.Errorf("hosts = %v", hosts)
+	}
+	if len(st.SSHKeys) != 1 || st.SSHKeys[0] != testKey {
+		t.Errorf("ssh keys = %v", st.SSHKeys)
+	}
+	if st.Firmware[0].Protocol != "HTTP" {
+		t.Errorf("protocol default = %q", st.Firmware[0].Protocol)
+	}
+}
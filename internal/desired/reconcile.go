@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package desired
+
+import (
+	"context"
+	"fmt"
+
+	"bootstrap/internal/sshkeys"
+	"bootstrap/pkg/redfish"
+)
+
+// Probe reads the live state of the BMC for every area the desired state covers.
+func Probe(ctx context.Context, c redfish.Client, st State) (Observed, error) {
+	var obs Observed
+	if len(st.SSHKeys) > 0 {
+		cur, err := c.GetAuthorizedKeys(ctx)
+		if err != nil {
+			return obs, fmt.Errorf("read ssh keys: %w", err)
+		}
+		obs.SSHKeys = sshkeys.Parse(cur)
+	}
+	if len(st.BMC.NTPServers) > 0 {
+		ntp, err := c.GetNTPServers(ctx)
+		if err != nil {
+			return obs, fmt.Errorf("read ntp servers: %w", err)
+		}
+		obs.NTPServers = ntp
+	}
+	if len(st.Accounts) > 0 {
+		accts, err := c.GetAccounts(ctx)
+		if err != nil {
+			return obs, fmt.Errorf("read accounts: %w", err)
+		}
+		obs.Accounts = accts
+	}
+	if len(st.Firmware) > 0 {
+		obs.Firmware = map[string]string{}
+		for _, fw := range st.Firmware {
+			inv, err := c.GetFirmwareInventory(ctx, fw.Target)
+			if err != nil {
+				return obs, fmt.Errorf("read firmware %s: %w", fw.Target, err)
+			}
+			obs.Firmware[fw.Target] = inv.Version
+		}
+	}
+	if len(st.BIOS.Attributes) > 0 {
+		bios, err := c.GetBiosAttributes(ctx)
+		if err != nil {
+			return obs, fmt.Errorf("read bios: %w", err)
+		}
+		obs.BIOS = bios
+	}
+	return obs, nil
+}
+
+// Apply executes changes (as returned by Diff) in order, setting each change's
+// Status and Error. Account drift is reported but skipped. It stops at the first failure since later changes may depend
+// on earlier ones; the remaining changes are left pending. The returned error is
+// the first failure, if any.
+func Apply(ctx context.Context, c redfish.Client, st State, obs Observed, changes []Change) error {
+	firmware := map[string]Firmware{}
+	for _, fw := range st.Firmware {
+		firmware[fw.Target] = fw
+	}
+	biosDone := map[string]error{}
+	for i := range changes {
+		ch := &changes[i]
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var err error
+		switch ch.Kind {
+		case KindSSHKeys:
+			err = c.SetAuthorizedKeys(ctx, sshkeys.Join(sshkeys.Merge(obs.SSHKeys, st.SSHKeys)))
+		case KindNTP:
+			err = c.SetNTPServers(ctx, st.BMC.NTPServers)
+		case KindFirmware:
+			fw := firmware[ch.Target]
+			var proto string
+			if proto, err = redfish.ResolveProtocol(ctx, c, fw.Protocol); err == nil {
+				_, err = c.SimpleUpdate(ctx, fw.ImageURI, []string{fw.Target}, proto, "", true)
+			}
+		case KindBIOS:
+			// All attributes for a system go in a single PATCH
+			var ok bool
+			if err, ok = biosDone[ch.Target]; !ok {
+				err = c.SetBiosAttributes(ctx, ch.Target, st.BIOS.Attributes)
+				biosDone[ch.Target] = err
+			}
+		case KindAccount:
+			// Creating accounts needs credentials the state file does not carry
+			ch.Status = StatusSkipped
+			ch.Error = "account changes must be made manually"
+			continue
+		default:
+			err = fmt.Errorf("unknown change kind %q", ch.Kind)
+		}
+		if err != nil {
+			ch.Status = StatusFailed
+			ch.Error = err.Error()
+			return fmt.Errorf("%s %s: %w", ch.Kind, ch.Target, err)
+		}
+		ch.Status = StatusApplied
+	}
+	return nil
+}
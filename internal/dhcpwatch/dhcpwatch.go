@@ -0,0 +1,180 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package dhcpwatch follows DHCP transactions, from dnsmasq or Kea logs or from
+// packets seen on an interface, and matches them with inventory MACs so it is
+// clear which nodes asked for an address and which stayed silent.
+package dhcpwatch
+
+import (
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Message types, as DHCP option 53 names them.
+const (
+	Discover = "DISCOVER"
+	Offer    = "OFFER"
+	Request  = "REQUEST"
+	Decline  = "DECLINE"
+	Ack      = "ACK"
+	Nak      = "NAK"
+	Release  = "RELEASE"
+	Inform   = "INFORM"
+)
+
+// Event is one DHCP message.
+type Event struct {
+	Time      time.Time
+	Type      string // Discover, Offer, ...
+	MAC       string // client hardware address, lower case
+	IP        string // offered, requested or acknowledged address, if any
+	Interface string // interface the server or sniffer saw it on, if known
+}
+
+var (
+	macRe      = regexp.MustCompile(`(?i)\b[0-9a-f]{2}(?::[0-9a-f]{2}){5}\b`)
+	ipRe       = regexp.MustCompile(`\b\d{1,3}(?:\.\d{1,3}){3}\b`)
+	dnsmasqRe  = regexp.MustCompile(`DHCP(DISCOVER|OFFER|REQUEST|DECLINE|ACK|NAK|RELEASE|INFORM)\(([^)]*)\)\s*(.*)`)
+	keaTypeRe  = regexp.MustCompile(`\bDHCP(DISCOVER|OFFER|REQUEST|DECLINE|ACK|NAK|RELEASE|INFORM) \(type \d+\)`)
+	keaHWRe    = regexp.MustCompile(`hwtype=\d+ ([0-9a-fA-F:]{17})`)
+	keaLeaseRe = regexp.MustCompile(`\blease (\d{1,3}(?:\.\d{1,3}){3})`)
+	keaIfaceRe = regexp.MustCompile(`on interface (\S+)`)
+)
+
+// ParseLine extracts the DHCP event from a dnsmasq (log-dhcp) or Kea DHCPv4 log
+// line. ok is false for lines that are not about a DHCP message.
+//
+//	dnsmasq-dhcp[812]: DHCPOFFER(eth1) 10.42.0.7 aa:bb:cc:00:00:07
+//	DHCP4_PACKET_RECEIVED [hwtype=1 aa:bb:cc:00:00:07], cid=[no info], tid=0x1: DHCPDISCOVER (type 1) received from 0.0.0.0 to 255.255.255.255 on interface eth1
+//	DHCP4_LEASE_ALLOC [hwtype=1 aa:bb:cc:00:00:07], cid=[no info], tid=0x1: lease 10.42.0.7 has been allocated for 3600 seconds
+func ParseLine(line string) (Event, bool) {
+	if m := dnsmasqRe.FindStringSubmatch(line); m != nil {
+		ev := Event{Type: m[1], Interface: m[2]}
+		for _, f := range strings.Fields(m[3]) {
+			switch {
+			case ev.IP == "" && ipRe.MatchString(f) && net.ParseIP(f) != nil:
+				ev.IP = f
+			case ev.MAC == "" && macRe.MatchString(f):
+				ev.MAC = strings.ToLower(macRe.FindString(f))
+			}
+		}
+		return ev, ev.MAC != ""
+	}
+	hw := keaHWRe.FindStringSubmatch(line)
+	if hw == nil {
+		return Event{}, false
+	}
+	ev := Event{MAC: strings.ToLower(hw[1])}
+	switch {
+	case strings.Contains(line, "DHCP4_LEASE_ALLOC"):
+		ev.Type = Ack
+	case strings.Contains(line, "DHCP4_LEASE_OFFER"), strings.Contains(line, "DHCP4_LEASE_ADVERT"):
+		ev.Type = Offer
+	case strings.Contains(line, "DHCP4_PACKET_RECEIVED"), strings.Contains(line, "DHCP4_PACKET_SEND"):
+		m := keaTypeRe.FindStringSubmatch(line)
+		if m == nil {
+			return Event{}, false
+		}
+		ev.Type = m[1]
+		if ev.Type == Ack || ev.Type == Offer {
+			// The lease lines carry the address; avoid counting the reply twice.
+			return Event{}, false
+		}
+	default:
+		return Event{}, false
+	}
+	if m := keaLeaseRe.FindStringSubmatch(line); m != nil {
+		ev.IP = m[1]
+	}
+	if m := keaIfaceRe.FindStringSubmatch(line); m != nil {
+		ev.Interface = m[1]
+	}
+	return ev, true
+}
+
+// State is what has been seen of one inventory MAC.
+type State struct {
+	Name     string // xname the MAC belongs to
+	Alias    string
+	MAC      string
+	Counts   map[string]int // messages seen per type
+	Last     string         // type of the latest message
+	LastSeen time.Time
+	IP       string // latest offered or acknowledged address
+}
+
+// Phase summarises s: "silent", "discovering" (asked but got no offer),
+// "offered", "requesting" or "acked"; "nak" when the latest answer was a NAK.
+func (s State) Phase() string {
+	switch {
+	case s.Last == "":
+		return "silent"
+	case s.Last == Nak:
+		return "nak"
+	case s.Counts[Ack] > 0:
+		return "acked"
+	case s.Counts[Request] > 0:
+		return "requesting"
+	case s.Counts[Offer] > 0:
+		return "offered"
+	}
+	return "discovering"
+}
+
+// Tracker correlates events with the MACs it was given.
+type Tracker struct {
+	states map[string]*State
+	order  []string // MACs in the order added
+}
+
+// NewTracker returns a Tracker with no MACs.
+func NewTracker() *Tracker {
+	return &Tracker{states: map[string]*State{}}
+}
+
+// Add tracks mac as belonging to name.
+func (t *Tracker) Add(name, alias, mac string) {
+	mac = strings.ToLower(mac)
+	if mac == "" {
+		return
+	}
+	if _, ok := t.states[mac]; ok {
+		return
+	}
+	t.states[mac] = &State{Name: name, Alias: alias, MAC: mac, Counts: map[string]int{}}
+	t.order = append(t.order, mac)
+}
+
+// Observe records ev and returns the state of its MAC; ok is false for a MAC
+// that is not tracked.
+func (t *Tracker) Observe(ev Event) (State, bool) {
+	s, ok := t.states[strings.ToLower(ev.MAC)]
+	if !ok {
+		return State{}, false
+	}
+	s.Counts[ev.Type]++
+	s.Last, s.LastSeen = ev.Type, ev.Time
+	if ev.IP != "" && (ev.Type == Offer || ev.Type == Ack || ev.Type == Request) {
+		s.IP = ev.IP
+	}
+	return *s, true
+}
+
+// States returns every tracked MAC in the order added.
+func (t *Tracker) States() []State {
+	out := make([]State, 0, len(t.order))
+	for _, mac := range t.order {
+		out = append(out, *t.states[mac])
+	}
+	return out
+}
+
+// Done reports whether every tracked MAC has been acknowledged.
+func (t *Tracker) Done() bool {
+	return !slices.ContainsFunc(t.States(), func(s State) bool { return s.Phase() != "acked" })
+}
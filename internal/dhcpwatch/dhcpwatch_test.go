@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package dhcpwatch
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseLine(t *testing.T) {
+	for _, tc := range []struct {
+		line string
+		want Event
+		ok   bool
+	}{
+		{"Jun  1 12:00:00 mgmt dnsmasq-dhcp[812]: DHCPDISCOVER(eth1) AA:BB:CC:00:00:07",
+			Event{Type: Discover, MAC: "aa:bb:cc:00:00:07", Interface: "eth1"}, true},
+		{"dnsmasq-dhcp[812]: 3052141 DHCPOFFER(eth1) 10.42.0.7 aa:bb:cc:00:00:07",
+			Event{Type: Offer, MAC: "aa:bb:cc:00:00:07", IP: "10.42.0.7", Interface: "eth1"}, true},
+		{"dnsmasq-dhcp[812]: DHCPACK(eth1) 10.42.0.7 aa:bb:cc:00:00:07 nid000007",
+			Event{Type: Ack, MAC: "aa:bb:cc:00:00:07", IP: "10.42.0.7", Interface: "eth1"}, true},
+		{"dnsmasq-dhcp[812]: DHCPDISCOVER(eth1) aa:bb:cc:00:00:08 no address available",
+			Event{Type: Discover, MAC: "aa:bb:cc:00:00:08", Interface: "eth1"}, true},
+		{"INFO  [kea-dhcp4.packets/77.139] DHCP4_PACKET_RECEIVED [hwtype=1 aa:bb:cc:00:00:07], cid=[no info], tid=0x1f: DHCPDISCOVER (type 1) received from 0.0.0.0 to 255.255.255.255 on interface eth1",
+			Event{Type: Discover, MAC: "aa:bb:cc:00:00:07", Interface: "eth1"}, true},
+		{"INFO  [kea-dhcp4.leases/77.139] DHCP4_LEASE_ALLOC [hwtype=1 aa:bb:cc:00:00:07], cid=[no info], tid=0x1f: lease 10.42.0.7 has been allocated for 3600 seconds",
+			Event{Type: Ack, MAC: "aa:bb:cc:00:00:07", IP: "10.42.0.7"}, true},
+		{"INFO  [kea-dhcp4.packets/77.139] DHCP4_PACKET_SEND [hwtype=1 aa:bb:cc:00:00:07], cid=[no info], tid=0x1f: trying to send packet DHCPACK (type 5) from 10.42.0.1:67 to 10.42.0.7:68 on interface eth1", Event{}, false},
+		{"dnsmasq[812]: query[A] example.com from 10.42.0.7", Event{}, false},
+	} {
+		got, ok := ParseLine(tc.line)
+		if ok != tc.ok || got != tc.want {
+			t.Errorf("ParseLine(%q) = %+v, %v; want %+v, %v", tc.line, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+// dhcpPacket builds an IPv4/UDP packet carrying a DHCP message.
+func dhcpPacket(msgType byte, mac []byte, yiaddr []byte, requested []byte) []byte {
+	msg := make([]byte, 240)
+	msg[0], msg[1], msg[2] = 1, 1, 6
+	copy(msg[16:20], yiaddr)
+	copy(msg[28:34], mac)
+	copy(msg[236:240], magicCookie)
+	msg = append(msg, 53, 1, msgType)
+	if requested != nil {
+		msg = append(msg, 50, 4)
+		msg = append(msg, requested...)
+	}
+	msg = append(msg, 0, 255)
+
+	pkt := make([]byte, 28, 28+len(msg))
+	pkt[0], pkt[9] = 0x45, 17
+	binary.BigEndian.PutUint16(pkt[20:22], 68)
+	binary.BigEndian.PutUint16(pkt[22:24], 67)
+	return append(pkt, msg...)
+}
+
+func TestParsePacket(t *testing.T) {
+	mac := []byte{0xaa, 0xbb, 0xcc, 0, 0, 7}
+	for _, tc := range []struct {
+		pkt  []byte
+		want Event
+	}{
+		{dhcpPacket(1, mac, nil, nil), Event{Type: Discover, MAC: "aa:bb:cc:00:00:07"}},
+		{dhcpPacket(3, mac, nil, []byte{10, 42, 0, 7}), Event{Type: Request, MAC: "aa:bb:cc:00:00:07", IP: "10.42.0.7"}},
+		{dhcpPacket(5, mac, []byte{10, 42, 0, 7}, nil), Event{Type: Ack, MAC: "aa:bb:cc:00:00:07", IP: "10.42.0.7"}},
+	} {
+		payload, ok := udpPayload(tc.pkt)
+		if !ok {
+			t.Fatal("udpPayload: not DHCP")
+		}
+		got, ok := ParsePacket(payload)
+		if !ok || got != tc.want {
+			t.Errorf("ParsePacket = %+v, %v; want %+v", got, ok, tc.want)
+		}
+	}
+	dns := dhcpPacket(1, mac, nil, nil)
+	binary.BigEndian.PutUint16(dns[22:24], 53)
+	if _, ok := udpPayload(dns); ok {
+		t.Error("port 53 accepted as DHCP")
+	}
+	if _, ok := ParsePacket(make([]byte, 300)); ok {
+		t.Error("message without magic cookie accepted")
+	}
+}
+
+func TestTracker(t *testing.T) {
+	tr := NewTracker()
+	tr.Add("x1000c0s0b0n0", "nid000001", "AA:00:00:00:00:01")
+	tr.Add("x1000c0s0b0n1", "", "aa:00:00:00:00:02")
+	tr.Add("x1000c0s0b0n2", "", "aa:00:00:00:00:03")
+
+	for _, ev := range []Event{
+		{Type: Discover, MAC: "aa:00:00:00:00:01"},
+		{Type: Offer, MAC: "aa:00:00:00:00:01", IP: "10.42.0.1"},
+		{Type: Request, MAC: "aa:00:00:00:00:01", IP: "10.42.0.1"},
+		{Type: Ack, MAC: "aa:00:00:00:00:01", IP: "10.42.0.1"},
+		{Type: Discover, MAC: "aa:00:00:00:00:02"},
+		{Type: Discover, MAC: "aa:00:00:00:00:02"},
+	} {
+		if _, ok := tr.Observe(ev); !ok {
+			t.Fatalf("%s not tracked", ev.MAC)
+		}
+	}
+	if _, ok := tr.Observe(Event{Type: Discover, MAC: "ff:00:00:00:00:01"}); ok {
+		t.Error("unknown MAC tracked")
+	}
+	var phases []string
+	for _, s := range tr.States() {
+		phases = append(phases, s.Phase())
+	}
+	if want := []string{"acked", "discovering", "silent"}; !reflect.DeepEqual(phases, want) {
+		t.Errorf("phases = %v, want %v", phases, want)
+	}
+	if st := tr.States(); st[0].IP != "10.42.0.1" || st[1].Counts[Discover] != 2 {
+		t.Errorf("states = %+v", st)
+	}
+	if tr.Done() {
+		t.Error("Done with two MACs not acked")
+	}
+}
+
+func TestFollow(t *testing.T) {
+	old := PollInterval
+	PollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { PollInterval = old })
+
+	path := filepath.Join(t.TempDir(), "dnsmasq.log")
+	if err := os.WriteFile(path, []byte("old line\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	lines := make(chan string, 10)
+	done := make(chan error, 1)
+	go func() { done <- Follow(ctx, path, false, func(l string) { lines <- l }) }()
+
+	next := func() string {
+		select {
+		case l := <-lines:
+			return l
+		case <-time.After(2 * time.Second):
+			return "<timeout>"
+		}
+	}
+	time.Sleep(50 * time.Millisecond) // let Follow seek to the end
+	appendTo := func(s string) {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = f.WriteString(s)
+		f.Close() //nolint:errcheck
+	}
+	appendTo("first\n")
+	if got := next(); got != "first" {
+		t.Errorf("got %q, want first", got)
+	}
+	// Rotation: the file is moved away and a new one created.
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if err := os.WriteFile(path, []byte("after rotate\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := next(); got != "after rotate" {
+		t.Errorf("got %q, want after rotate", got)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,80 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package dhcpwatch
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// PollInterval is how often Follow checks a file for new lines.
+var PollInterval = 500 * time.Millisecond
+
+// Follow calls fn with each line appended to path, like tail -F: it starts at
+// the end of the file (or at the start with fromStart), and reopens the file
+// when it is rotated or truncated. It returns when ctx is done.
+func Follow(ctx context.Context, path string, fromStart bool, fn func(line string)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { f.Close() }() //nolint:errcheck
+	if !fromStart {
+		if _, err := f.Seek(0, io.SeekEnd); err != nil {
+			return err
+		}
+	}
+	r := bufio.NewReader(f)
+	var partial string
+	for {
+		line, err := r.ReadString('\n')
+		partial += line
+		if err == nil {
+			fn(strings.TrimRight(partial, "\r\n"))
+			partial = ""
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(PollInterval):
+		}
+		// Reopen when the file was replaced (rotation) or truncated.
+		pos, _ := f.Seek(0, io.SeekCurrent)
+		cur, err1 := f.Stat()
+		now, err2 := os.Stat(path)
+		if err1 != nil || err2 != nil {
+			continue // rotated and not recreated yet
+		}
+		if !os.SameFile(cur, now) || now.Size() < pos {
+			nf, err := os.Open(path)
+			if err != nil {
+				continue
+			}
+			f.Close() //nolint:errcheck
+			f, r, partial = nf, bufio.NewReader(nf), ""
+		}
+	}
+}
+
+// Scan calls fn with each line of r until r ends or ctx is done, e.g. for
+// journalctl -f output piped to stdin.
+func Scan(ctx context.Context, r io.Reader, fn func(line string)) error {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		if ctx.Err() != nil {
+			return nil
+		}
+		fn(sc.Text())
+	}
+	return sc.Err()
+}
@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package dhcpwatch
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+// messageTypes maps DHCP option 53 values to message types.
+var messageTypes = map[byte]string{
+	1: Discover, 2: Offer, 3: Request, 4: Decline, 5: Ack, 6: Nak, 7: Release, 8: Inform,
+}
+
+var magicCookie = []byte{99, 130, 83, 99}
+
+// ParsePacket decodes a DHCPv4 message (the UDP payload). ok is false for
+// anything that is not a DHCP message with a message type and an Ethernet
+// client address.
+func ParsePacket(b []byte) (Event, bool) {
+	const optionsAt = 240
+	if len(b) < optionsAt || string(b[236:240]) != string(magicCookie) {
+		return Event{}, false
+	}
+	htype, hlen := b[1], b[2]
+	if htype != 1 || hlen != 6 {
+		return Event{}, false
+	}
+	ev := Event{MAC: net.HardwareAddr(b[28:34]).String()}
+	yiaddr := net.IP(b[16:20])
+	var requested net.IP
+	for i := optionsAt; i < len(b); {
+		code := b[i]
+		if code == 0 { // pad
+			i++
+			continue
+		}
+		if code == 255 || i+1 >= len(b) { // end
+			break
+		}
+		n := int(b[i+1])
+		if i+2+n > len(b) {
+			break
+		}
+		val := b[i+2 : i+2+n]
+		switch {
+		case code == 53 && n == 1:
+			ev.Type = messageTypes[val[0]]
+		case code == 50 && n == 4:
+			requested = net.IP(val)
+		}
+		i += 2 + n
+	}
+	if ev.Type == "" {
+		return Event{}, false
+	}
+	switch {
+	case !yiaddr.IsUnspecified():
+		ev.IP = yiaddr.String()
+	case requested != nil:
+		ev.IP = requested.String()
+	}
+	return ev, true
+}
+
+// udpPayload returns the payload of an IPv4 packet carrying UDP between the
+// DHCP ports 67 and 68.
+func udpPayload(pkt []byte) ([]byte, bool) {
+	if len(pkt) < 20 || pkt[0]>>4 != 4 || pkt[9] != 17 { // IPv4, UDP
+		return nil, false
+	}
+	ihl := int(pkt[0]&0x0f) * 4
+	if len(pkt) < ihl+8 {
+		return nil, false
+	}
+	udp := pkt[ihl:]
+	src, dst := binary.BigEndian.Uint16(udp[0:2]), binary.BigEndian.Uint16(udp[2:4])
+	if (src != 67 && src != 68) || (dst != 67 && dst != 68) {
+		return nil, false
+	}
+	return udp[8:], true
+}
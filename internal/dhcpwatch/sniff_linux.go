@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package dhcpwatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+// Sniff reads the DHCP messages on iface from an AF_PACKET socket, which needs
+// CAP_NET_RAW, and calls fn with each one until ctx is done. It sees the
+// traffic of any DHCP server on the segment, not only one running locally.
+func Sniff(ctx context.Context, iface string, fn func(Event)) error {
+	ifi, err := net.InterfaceByName(iface)
+	if err != nil {
+		return err
+	}
+	proto := htons(syscall.ETH_P_IP)
+	fd, err := syscall.Socket(syscall.AF_PACKET, syscall.SOCK_DGRAM, int(proto))
+	if err != nil {
+		return fmt.Errorf("open packet socket on %s (needs root or CAP_NET_RAW): %w", iface, err)
+	}
+	if err := syscall.Bind(fd, &syscall.SockaddrLinklayer{Protocol: proto, Ifindex: ifi.Index}); err != nil {
+		syscall.Close(fd) //nolint:errcheck
+		return fmt.Errorf("bind packet socket to %s: %w", iface, err)
+	}
+	if err := syscall.SetNonblock(fd, true); err != nil {
+		syscall.Close(fd) //nolint:errcheck
+		return err
+	}
+	f := os.NewFile(uintptr(fd), "packet:"+iface)
+	defer f.Close() //nolint:errcheck
+	stop := context.AfterFunc(ctx, func() { _ = f.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	buf := make([]byte, 65536)
+	for {
+		n, err := f.Read(buf)
+		if err != nil {
+			if ctx.Err() != nil && errors.Is(err, os.ErrDeadlineExceeded) {
+				return nil
+			}
+			return err
+		}
+		payload, ok := udpPayload(buf[:n])
+		if !ok {
+			continue
+		}
+		if ev, ok := ParsePacket(payload); ok {
+			ev.Time, ev.Interface = time.Now(), iface
+			fn(ev)
+		}
+	}
+}
+
+func htons(v uint16) uint16 { return v<<8 | v>>8 }
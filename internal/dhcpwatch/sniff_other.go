@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+//go:build !linux
+
+package dhcpwatch
+
+import (
+	"context"
+	"errors"
+)
+
+// Sniff is only supported on Linux; elsewhere, follow the DHCP server log.
+func Sniff(ctx context.Context, iface string, fn func(Event)) error {
+	return errors.New("sniffing DHCP is only supported on Linux; follow the DHCP server log instead")
+}
@@ -8,15 +8,18 @@ package diag
 import (
 	"fmt"
 	"os"
+
+	"bootstrap/internal/redact"
 )
 
 // Debug enables extra logging when true.
 var Debug bool
 
-// Logf writes formatted debug logs to stderr when Debug is true.
+// Logf writes formatted debug logs to stderr when Debug is true, with secrets
+// masked (see package redact).
 func Logf(format string, args ...any) {
 	if !Debug {
 		return
 	}
-	fmt.Fprintf(os.Stderr, "[DEBUG] "+format+"\n", args...)
+	fmt.Fprintln(os.Stderr, "[DEBUG] "+redact.String(fmt.Sprintf(format, args...)))
 }
@@ -10,22 +10,26 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
-	"bootstrap/internal/inventory"
-	"bootstrap/internal/netalloc"
-	"bootstrap/internal/redfish"
 	"bootstrap/internal/xname"
+	"bootstrap/pkg/inventory"
+	"bootstrap/pkg/netalloc"
+	"bootstrap/pkg/redfish"
 )
 
-// UpdateNodes reads existing nodes for reservations, discovers bootable NICs per BMC,
-// allocates IPs, and returns the new nodes list.
-// nodeStartIP is an optional IP address to start node allocation from (skips all IPs before it)
-func UpdateNodes(doc *inventory.FileFormat, bmcSubnet, nodeSubnet, nodeStartIP string, user, pass string, insecure bool, timeout time.Duration) ([]inventory.Entry, error) {
+// UpdateNodes discovers the nodes behind the BMCs of doc, allocating node IPs
+// from nodeSubnet (from nodeStartIP on, when set), and returns the new nodes
+// list, the BMCs that could not be discovered keyed by xname, and the systems
+// whose interfaces could not be read. It records the MAC of each BMC in
+// doc.BMCs. If ctx is cancelled it stops contacting BMCs and returns ctx.Err().
+func UpdateNodes(ctx context.Context, doc *inventory.FileFormat, bmcSubnet, nodeSubnet, nodeStartIP string, connect func(host string) redfish.Discoverer, prefer redfish.NICPreference, timeout time.Duration) (nodes []inventory.Entry, failed map[string]error, partial []SystemError, err error) {
 	// Create allocator for node IPs
 	nodeAlloc, err := netalloc.NewAllocator(nodeSubnet)
 	if err != nil {
-		return nil, fmt.Errorf("node ipam init: %w", err)
+		return nil, nil, nil, fmt.Errorf("node ipam init: %w", err)
 	}
 
 	// Reserve existing node IPs that are within the node subnet
@@ -38,7 +42,7 @@ func UpdateNodes(doc *inventory.FileFormat, bmcSubnet, nodeSubnet, nodeStartIP s
 	// Reserve all IPs before the start IP if specified
 	if nodeStartIP != "" {
 		if err := nodeAlloc.ReserveUpTo(nodeStartIP); err != nil {
-			return nil, fmt.Errorf("reserve up to node start IP: %w", err)
+			return nil, nil, nil, fmt.Errorf("reserve up to node start IP: %w", err)
 		}
 	}
 
@@ -49,7 +53,7 @@ func UpdateNodes(doc *inventory.FileFormat, bmcSubnet, nodeSubnet, nodeStartIP s
 	} else {
 		bmcAlloc, err = netalloc.NewAllocator(bmcSubnet)
 		if err != nil {
-			return nil, fmt.Errorf("bmc ipam init: %w", err)
+			return nil, nil, nil, fmt.Errorf("bmc ipam init: %w", err)
 		}
 		// Reserve existing BMC IPs that are within the BMC subnet
 		for _, b := range doc.BMCs {
@@ -60,58 +64,260 @@ func UpdateNodes(doc *inventory.FileFormat, bmcSubnet, nodeSubnet, nodeStartIP s
 	}
 
 	out := make([]inventory.Entry, 0, len(doc.BMCs))
+	failed = map[string]error{}
 
-	for _, b := range doc.BMCs {
+	for i, b := range doc.BMCs {
+		if err := ctx.Err(); err != nil {
+			return out, failed, partial, err
+		}
+		// Node xnames derive from the BMC's: without a valid one the BMC is
+		// neither contacted nor updated
+		if err := xname.CheckBMC(b.Xname); err != nil {
+			fmt.Fprintf(os.Stderr, "WARN: %s: discover: %v\n", b.Xname, err)
+			failed[b.Xname] = err
+			continue
+		}
 		host := b.IP
 		if host == "" {
 			host = b.Xname
 		}
-		ctx, cancel := context.WithTimeout(context.Background(), timeout)
-		systemMACs, err := redfish.DiscoverAllBootableMACs(ctx, host, user, pass, insecure, timeout)
-		cancel()
+		systemMACs, err := bmcSystemMACs(ctx, connect(host), prefer, timeout)
 		if err != nil {
+			if ctx.Err() != nil {
+				return out, failed, partial, ctx.Err()
+			}
 			fmt.Fprintf(os.Stderr, "WARN: %s: discover: %v\n", b.Xname, err)
+			failed[b.Xname] = err
 			continue
 		}
 		if len(systemMACs) == 0 {
 			fmt.Fprintf(os.Stderr, "WARN: %s: no systems discovered\n", b.Xname)
 			continue
 		}
+		systems, mac := bmcMetadata(ctx, connect(host), b, timeout)
+		if mac != "" {
+			doc.BMCs[i].MAC = mac
+		}
+		paths := make([]string, len(systemMACs))
+		for j, s := range systemMACs {
+			paths[j] = s.SystemPath
+		}
+		nodeXs, err := systemNodes(b.Xname, paths)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "WARN: %s: discover: %v\n", b.Xname, err)
+			failed[b.Xname] = err
+			continue
+		}
+		bmcNodes, bmcPartial, err := systemEntries(b.Xname, nodeXs, systemMACs, systems, doc.Nodes, nodeAlloc)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		out = append(out, bmcNodes...)
+		partial = append(partial, bmcPartial...)
+	}
+	return out, failed, partial, nil
+}
+
+// withTimeout bounds one discovery round against a BMC; 0 means no limit.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return ctx, func() {}
+}
+
+// bmcSystemMACs reads the bootable NICs of every system of a BMC, ranked by
+// prefer, within timeout. A BMC none of whose systems could be read is an
+// error; one with some unreadable systems is not, and the failed systems keep
+// their Err.
+func bmcSystemMACs(ctx context.Context, d redfish.Discoverer, prefer redfish.NICPreference, timeout time.Duration) ([]redfish.SystemMACs, error) {
+	ctx, cancel := withTimeout(ctx, timeout)
+	defer cancel()
+	systemMACs, err := d.DiscoverAllBootableMACs(ctx, prefer)
+	if err != nil {
+		return nil, err
+	}
+	if len(systemMACs) > 0 {
+		if err := AllFailed(systemMACs); err != nil {
+			return nil, err
+		}
+	}
+	return systemMACs, nil
+}
+
+// bmcMetadata reads the asset metadata of the systems of BMC b and the BMC's
+// own MAC from its manager interfaces, within a second round of timeout. Both
+// are best effort: failures are warned about and leave the results empty, so
+// nodes are still recorded without them. A MAC that differs from the one in
+// the inventory is returned with a warning.
+func bmcMetadata(ctx context.Context, d redfish.Discoverer, b inventory.Entry, timeout time.Duration) (systems []redfish.System, mac string) {
+	ctx, cancel := withTimeout(ctx, timeout)
+	defer cancel()
+	systems, err := d.GetSystems(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARN: %s: system metadata: %v\n", b.Xname, err)
+	}
+	nics, err := d.DiscoverManagerNICs(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARN: %s: BMC MAC: %v\n", b.Xname, err)
+		return systems, ""
+	}
+	mac = ManagerMAC(nics, b.IP)
+	if mac != "" && b.MAC != "" && !strings.EqualFold(b.MAC, mac) {
+		fmt.Fprintf(os.Stderr, "WARN: %s: BMC reports MAC %s but inventory has %s (blade moved?); updating bmcs[]\n", b.Xname, mac, b.MAC)
+	}
+	return systems, mac
+}
+
+// systemEntries returns the node entries, named nodeXs, for the systems of BMC
+// bmcX. A system whose interfaces could not be read keeps its entry in
+// existing, if any, and is returned in partial. Any other system gets its
+// first bootable MAC, its existing IP when that is in the node subnet or else
+// the next free one from alloc, and the asset metadata of systems.
+func systemEntries(bmcX string, nodeXs []string, systemMACs []redfish.SystemMACs, systems []redfish.System, existing []inventory.Entry, alloc *netalloc.Allocator) (nodes []inventory.Entry, partial []SystemError, err error) {
+	for sysIdx, sysMacs := range systemMACs {
+		// Node1 stays n1 even when Node0 fails or is listed after it
+		nodeX := nodeXs[sysIdx]
+		prev := findByXname(existing, nodeX)
+		if sysMacs.Err != nil {
+			fmt.Fprintf(os.Stderr, "WARN: %s %s: interfaces: %v; keeping its existing node entry\n", bmcX, sysMacs.SystemPath, sysMacs.Err)
+			partial = append(partial, SystemError{BMC: bmcX, SystemPath: sysMacs.SystemPath, Err: sysMacs.Err})
+			if prev != nil {
+				nodes = append(nodes, *prev)
+			}
+			continue
+		}
+		if len(sysMacs.MACs) == 0 {
+			fmt.Fprintf(os.Stderr, "WARN: %s %s: no NICs discovered\n", bmcX, sysMacs.SystemPath)
+			continue
+		}
+
+		// Use only the first bootable MAC for PXE booting
+		mac := sysMacs.MACs[0]
 
-		// Process each system (e.g., Node0, Node1) found on this BMC
-		for sysIdx, sysMacs := range systemMACs {
-			if len(sysMacs.MACs) == 0 {
-				fmt.Fprintf(os.Stderr, "WARN: %s %s: no NICs discovered\n", b.Xname, sysMacs.SystemPath)
-				continue
+		ipStr := ""
+		// Only reuse existing IP if it's valid and within the node subnet
+		if prev != nil && net.ParseIP(prev.IP) != nil && alloc.Contains(prev.IP) {
+			ipStr = prev.IP
+			alloc.Reserve(ipStr)
+		} else {
+			ipStr, err = alloc.Next()
+			if err != nil {
+				return nil, nil, fmt.Errorf("ip allocate for %s: %w", nodeX, err)
 			}
+		}
+		node := inventory.Entry{Xname: nodeX, MAC: mac, IP: ipStr}
+		if prev != nil {
+			copyMetadata(&node, *prev)
+		}
+		if sys := findSystem(systems, sysMacs.SystemPath); sys != nil {
+			setMetadata(&node, *sys)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, partial, nil
+}
 
-			// Use only the first bootable MAC for PXE booting
-			mac := sysMacs.MACs[0]
-
-			// Generate node xname with proper node number
-			// For single-system BMCs, use node 0
-			// For multi-system BMCs, use the system index as node number
-			nodeX := xname.BMCXnameToNodeN(b.Xname, sysIdx)
-
-			existing := findByXname(doc.Nodes, nodeX)
-			ipStr := ""
-			// Only reuse existing IP if it's valid and within the node subnet
-			if existing != nil && net.ParseIP(existing.IP) != nil && nodeAlloc.Contains(existing.IP) {
-				ipStr = existing.IP
-				nodeAlloc.Reserve(ipStr)
-			} else {
-				var err error
-				ipStr, err = nodeAlloc.Next()
-				if err != nil {
-					return nil, fmt.Errorf("ip allocate for %s: %w", nodeX, err)
-				}
+// systemNodes returns the node xnames of the systems of a BMC, numbered as
+// redfish.NodeNumbers does, warning when the Systems collection is not listed
+// in node order. It fails when bmcX is not a BMC xname.
+func systemNodes(bmcX string, systemPaths []string) ([]string, error) {
+	nums := redfish.NodeNumbers(systemPaths)
+	if err := redfish.VerifySystemMapping(systemPaths); err != nil {
+		how := "position"
+		for i, n := range nums {
+			if n != i {
+				how = "system Id"
 			}
-			out = append(out, inventory.Entry{Xname: nodeX, MAC: mac, IP: ipStr})
 		}
+		fmt.Fprintf(os.Stderr, "WARN: %s: %v; numbering nodes by %s\n", bmcX, err, how)
+	}
+	out := make([]string, len(nums))
+	for i, n := range nums {
+		x, err := xname.BMCXnameToNodeN(bmcX, n)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = x
 	}
 	return out, nil
 }
 
+// SystemError is a system of a discovered BMC whose interfaces could not be read.
+type SystemError struct {
+	BMC        string // xname
+	SystemPath string
+	Err        error
+}
+
+// AllFailed returns the first error when no system on a BMC could be read.
+func AllFailed(systems []redfish.SystemMACs) error {
+	for _, s := range systems {
+		if s.Err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: %w", systems[0].SystemPath, systems[0].Err)
+}
+
+// ManagerMAC picks the MAC of a BMC reached at ip from its manager interfaces:
+// the interface holding ip, else the only interface, else the first one with an
+// IPv4 address.
+func ManagerMAC(nics []redfish.ManagerNIC, ip string) string {
+	for _, n := range nics {
+		for _, a := range n.IPv4 {
+			if ip != "" && a == ip {
+				return n.MAC
+			}
+		}
+	}
+	if len(nics) == 1 {
+		return nics[0].MAC
+	}
+	for _, n := range nics {
+		if len(n.IPv4) > 0 {
+			return n.MAC
+		}
+	}
+	return ""
+}
+
+func findSystem(systems []redfish.System, path string) *redfish.System {
+	for i := range systems {
+		if strings.TrimSuffix(systems[i].ODataID, "/") == strings.TrimSuffix(path, "/") {
+			return &systems[i]
+		}
+	}
+	return nil
+}
+
+// setMetadata records the asset fields of sys on a node entry.
+func setMetadata(e *inventory.Entry, sys redfish.System) {
+	e.Model = sys.Model
+	e.SerialNumber = sys.SerialNumber
+	e.SKU = sys.SKU
+	e.BiosVersion = sys.BiosVersion
+	e.ProcessorSummary = ""
+	if p := sys.ProcessorSummary; p.Count > 0 || p.Model != "" {
+		e.ProcessorSummary = strings.TrimSpace(fmt.Sprintf("%dx %s", p.Count, p.Model))
+		if p.LogicalProcessorCount > 0 {
+			e.ProcessorSummary += fmt.Sprintf(" (%d threads)", p.LogicalProcessorCount)
+		}
+	}
+	e.MemorySummary = ""
+	if gib := sys.MemorySummary.TotalSystemMemoryGiB; gib > 0 {
+		e.MemorySummary = strconv.FormatFloat(gib, 'f', -1, 64) + " GiB"
+	}
+}
+
+// copyMetadata keeps the alias, previously recorded asset fields and HSN interfaces when a BMC does not report them again.
+func copyMetadata(dst *inventory.Entry, src inventory.Entry) {
+	dst.Alias, dst.Tags = src.Alias, src.Tags
+	dst.Model, dst.SerialNumber, dst.SKU = src.Model, src.SerialNumber, src.SKU
+	dst.BiosVersion, dst.ProcessorSummary, dst.MemorySummary = src.BiosVersion, src.ProcessorSummary, src.MemorySummary
+	dst.HSN = src.HSN
+}
+
 func findByXname(list []inventory.Entry, x string) *inventory.Entry {
 	for i := range list {
 		if list[i].Xname == x {
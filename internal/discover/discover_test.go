@@ -5,9 +5,21 @@
 package discover
 
 import (
+	"context"
+	"errors"
+	"maps"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
-	"bootstrap/internal/inventory"
+	"bootstrap/internal/initbmcs"
+	"bootstrap/pkg/inventory"
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
 )
 
 func TestFindByXname(t *testing.T) {
@@ -60,3 +72,295 @@ func TestFindByXname(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateNodesWithMockClient(t *testing.T) {
+	doc := &inventory.FileFormat{
+		BMCs: []inventory.Entry{
+			{Xname: "x1000c0s0b0", IP: "10.1.0.2", MAC: "02:00:00:00:00:01"},
+			{Xname: "x1000c0s1b0", IP: "10.1.0.3"},
+		},
+		Nodes: []inventory.Entry{{Xname: "x1000c0s0b0n1", IP: "10.2.0.50"}},
+	}
+	mocks := map[string]*redfishtest.MockClient{
+		"10.1.0.2": {
+			DiscoverAllBootableMACsFunc: func(context.Context, redfish.NICPreference) ([]redfish.SystemMACs, error) {
+				return []redfish.SystemMACs{
+					{SystemPath: "/redfish/v1/Systems/Node0", MACs: []string{"aa:00:00:00:00:01", "aa:00:00:00:00:02"}},
+					{SystemPath: "/redfish/v1/Systems/Node1", MACs: []string{"aa:00:00:00:00:03"}},
+				}, nil
+			},
+			DiscoverManagerNICsFunc: func(context.Context) ([]redfish.ManagerNIC, error) {
+				return []redfish.ManagerNIC{
+					{ID: "usb0", MAC: "02:00:00:00:00:99"},
+					{ID: "eth0", MAC: "02:00:00:00:00:02", IPv4: []string{"10.1.0.2"}},
+				}, nil
+			},
+			GetSystemsFunc: func(context.Context) ([]redfish.System, error) {
+				sys := redfish.System{Model: "EX425", SerialNumber: "SN0", SKU: "sku-1", BiosVersion: "bios.1.2"}
+				sys.ODataID = "/redfish/v1/Systems/Node0"
+				sys.ProcessorSummary.Count = 2
+				sys.ProcessorSummary.Model = "AMD EPYC 7763"
+				sys.ProcessorSummary.LogicalProcessorCount = 256
+				sys.MemorySummary.TotalSystemMemoryGiB = 512
+				return []redfish.System{sys}, nil
+			},
+		},
+		"10.1.0.3": {DiscoverAllBootableMACsFunc: func(context.Context, redfish.NICPreference) ([]redfish.SystemMACs, error) {
+			return nil, errors.New("connection refused")
+		}},
+	}
+	connect := func(host string) redfish.Discoverer { return mocks[host] }
+
+	nodes, failed, _, err := UpdateNodes(context.Background(), doc, "10.1.0.0/24", "10.2.0.0/24", "", connect, nil, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []inventory.Entry{
+		{Xname: "x1000c0s0b0n0", MAC: "aa:00:00:00:00:01", IP: "10.2.0.1",
+			Model: "EX425", SerialNumber: "SN0", SKU: "sku-1", BiosVersion: "bios.1.2",
+			ProcessorSummary: "2x AMD EPYC 7763 (256 threads)", MemorySummary: "512 GiB"},
+		{Xname: "x1000c0s0b0n1", MAC: "aa:00:00:00:00:03", IP: "10.2.0.50"},
+	}
+	if len(nodes) != len(want) {
+		t.Fatalf("got nodes %+v", nodes)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(nodes[i], want[i]) {
+			t.Errorf("node %d = %+v, want %+v", i, nodes[i], want[i])
+		}
+	}
+	if doc.BMCs[0].MAC != "02:00:00:00:00:02" {
+		t.Errorf("BMC MAC = %s, want the one reported on the BMC's own interface", doc.BMCs[0].MAC)
+	}
+	if _, ok := failed["x1000c0s1b0"]; !ok || len(failed) != 1 {
+		t.Errorf("failed = %v", failed)
+	}
+}
+
+func TestUpdateNodesKeepsSystemThatFailed(t *testing.T) {
+	doc := &inventory.FileFormat{
+		BMCs: []inventory.Entry{
+			{Xname: "x1000c0s0b0", IP: "10.1.0.2"},
+			{Xname: "x1000c0s1b0", IP: "10.1.0.3"},
+		},
+		Nodes: []inventory.Entry{
+			{Xname: "x1000c0s0b0n0", MAC: "aa:00:00:00:00:01", IP: "10.2.0.10"},
+			{Xname: "x1000c0s0b0n1", MAC: "aa:00:00:00:00:02", IP: "10.2.0.11"},
+		},
+	}
+	boom := errors.New("500 Internal Server Error")
+	mocks := map[string]*redfishtest.MockClient{
+		"10.1.0.2": {DiscoverAllBootableMACsFunc: func(context.Context, redfish.NICPreference) ([]redfish.SystemMACs, error) {
+			return []redfish.SystemMACs{
+				{SystemPath: "/redfish/v1/Systems/Node0", Err: boom},
+				{SystemPath: "/redfish/v1/Systems/Node1", MACs: []string{"aa:00:00:00:00:12"}},
+			}, nil
+		}},
+		"10.1.0.3": {DiscoverAllBootableMACsFunc: func(context.Context, redfish.NICPreference) ([]redfish.SystemMACs, error) {
+			return []redfish.SystemMACs{{SystemPath: "/redfish/v1/Systems/Node0", Err: boom}}, nil
+		}},
+	}
+	connect := func(host string) redfish.Discoverer { return mocks[host] }
+
+	nodes, failed, partial, err := UpdateNodes(context.Background(), doc, "10.1.0.0/24", "10.2.0.0/24", "", connect, nil, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []inventory.Entry{
+		{Xname: "x1000c0s0b0n0", MAC: "aa:00:00:00:00:01", IP: "10.2.0.10"},
+		{Xname: "x1000c0s0b0n1", MAC: "aa:00:00:00:00:12", IP: "10.2.0.11"},
+	}
+	if !reflect.DeepEqual(nodes, want) {
+		t.Errorf("nodes = %+v, want %+v", nodes, want)
+	}
+	if len(partial) != 1 || partial[0].BMC != "x1000c0s0b0" || partial[0].SystemPath != "/redfish/v1/Systems/Node0" || !errors.Is(partial[0].Err, boom) {
+		t.Errorf("partial = %+v", partial)
+	}
+	if err, ok := failed["x1000c0s1b0"]; !ok || len(failed) != 1 || !errors.Is(err, boom) {
+		t.Errorf("failed = %v, want the BMC none of whose systems could be read", failed)
+	}
+}
+
+func TestUpdateHSN(t *testing.T) {
+	doc := &inventory.FileFormat{
+		BMCs: []inventory.Entry{{Xname: "x1000c0s0b0", IP: "10.1.0.2"}},
+		Nodes: []inventory.Entry{
+			{Xname: "x1000c0s0b0n0", MAC: "aa:00:00:00:00:01"},
+			{Xname: "x1000c0s0b0n1", MAC: "aa:00:00:00:00:03"},
+		},
+	}
+	mock := &redfishtest.MockClient{
+		DiscoverSystemNICsFunc: func(context.Context) ([]redfish.SystemNIC, error) {
+			return []redfish.SystemNIC{
+				{SystemPath: "/redfish/v1/Systems/Node0", ID: "ManagementEthernet", MAC: "aa:00:00:00:00:01"},
+				{SystemPath: "/redfish/v1/Systems/Node0", ID: "HPCNet0", Description: "SS11 200Gb", MAC: "bb:00:00:00:00:01"},
+				{SystemPath: "/redfish/v1/Systems/Node1", ID: "ManagementEthernet", MAC: "aa:00:00:00:00:03"},
+			}, nil
+		},
+	}
+	connect := func(string) redfish.Discoverer { return mock }
+	pattern := regexp.MustCompile(DefaultHSNPattern)
+
+	updated, failed, err := UpdateHSN(context.Background(), doc, connect, pattern, time.Second)
+	if err != nil || len(failed) != 0 {
+		t.Fatalf("err=%v failed=%v", err, failed)
+	}
+	if updated != 1 {
+		t.Errorf("updated = %d, want 1", updated)
+	}
+	want := []inventory.NIC{{ID: "HPCNet0", MAC: "bb:00:00:00:00:01", Description: "SS11 200Gb"}}
+	if !reflect.DeepEqual(doc.Nodes[0].HSN, want) {
+		t.Errorf("node0 hsn = %+v, want %+v", doc.Nodes[0].HSN, want)
+	}
+	if doc.Nodes[1].HSN != nil {
+		t.Errorf("node1 hsn = %+v, want none", doc.Nodes[1].HSN)
+	}
+}
+
+func TestCompareExpected(t *testing.T) {
+	g := initbmcs.Geometry{Name: "test", Slots: 2, BladesPerSlot: 1, NodesPerBMC: 2}
+	bmcs := []inventory.Entry{
+		{Xname: "x1000c0s0b0"},
+		{Xname: "x1000c0s1b0"},
+		{Xname: "x1000c0s1b0"}, // listed twice
+		{Xname: "x1000c0s2b0"}, // past the slots of the geometry
+		{Xname: "x1000c1s0b0"},
+		{Xname: "x1000c1s1b0"},
+	}
+	found := []inventory.Entry{
+		{Xname: "x1000c0s0b0n0"},
+		{Xname: "x1000c0s0b0n1"},
+		{Xname: "x1000c0s1b0n1"},
+		{Xname: "x1000c0s1b0n2"},
+		{Xname: "x1000c0s2b0n0"},
+	}
+	unreachable := errors.New("no route to host")
+	r := CompareExpected(g, bmcs, found, map[string]error{"x1000c1s1b0": unreachable})
+
+	if r.Expected != 6 || r.Found != 5 {
+		t.Errorf("expected %d, found %d; want 6 and 5", r.Expected, r.Found)
+	}
+	if want := []string{"x1000c0s1b0n0"}; !reflect.DeepEqual(r.Missing, want) {
+		t.Errorf("Missing = %v, want %v", r.Missing, want)
+	}
+	if want := []string{"x1000c0s1b0n2", "x1000c0s2b0n0"}; !reflect.DeepEqual(r.Unexpected, want) {
+		t.Errorf("Unexpected = %v, want %v", r.Unexpected, want)
+	}
+	if want := []string{"x1000c1s0b0"}; !reflect.DeepEqual(r.Empty, want) {
+		t.Errorf("Empty = %v, want %v", r.Empty, want)
+	}
+	if len(r.Unreached) != 1 || r.Unreached["x1000c1s1b0"] != unreachable {
+		t.Errorf("Unreached = %v", r.Unreached)
+	}
+}
+
+func TestUpdateNodesSystemsOutOfOrder(t *testing.T) {
+	doc := &inventory.FileFormat{BMCs: []inventory.Entry{{Xname: "x1000c0s0b0", IP: "10.1.0.2"}}}
+	m := &redfishtest.MockClient{
+		DiscoverAllBootableMACsFunc: func(context.Context, redfish.NICPreference) ([]redfish.SystemMACs, error) {
+			return []redfish.SystemMACs{
+				{SystemPath: "/redfish/v1/Systems/Node1", MACs: []string{"aa:00:00:00:00:03"}},
+				{SystemPath: "/redfish/v1/Systems/Node0", MACs: []string{"aa:00:00:00:00:01"}},
+			}, nil
+		},
+	}
+	connect := func(string) redfish.Discoverer { return m }
+	nodes, _, _, err := UpdateNodes(context.Background(), doc, "10.1.0.0/24", "10.2.0.0/24", "", connect, nil, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, n := range nodes {
+		got[n.Xname] = n.MAC
+	}
+	if want := map[string]string{"x1000c0s0b0n0": "aa:00:00:00:00:01", "x1000c0s0b0n1": "aa:00:00:00:00:03"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("nodes = %v, want %v", got, want)
+	}
+}
+
+func TestUpdateNodesMalformedXname(t *testing.T) {
+	doc := &inventory.FileFormat{BMCs: []inventory.Entry{{Xname: "x1000c0s0", IP: "10.1.0.2"}, {Xname: "blade-7", IP: "10.1.0.3", MAC: "02:00:00:00:00:07"}}}
+	m := &redfishtest.MockClient{
+		DiscoverAllBootableMACsFunc: func(context.Context, redfish.NICPreference) ([]redfish.SystemMACs, error) {
+			return []redfish.SystemMACs{{SystemPath: "/redfish/v1/Systems/Node0", MACs: []string{"aa:00:00:00:00:01"}}}, nil
+		},
+		DiscoverManagerNICsFunc: func(context.Context) ([]redfish.ManagerNIC, error) {
+			return []redfish.ManagerNIC{{ID: "eth0", MAC: "02:00:00:00:00:99"}}, nil
+		},
+	}
+	var contacted []string
+	connect := func(host string) redfish.Discoverer {
+		contacted = append(contacted, host)
+		return m
+	}
+	nodes, failed, _, err := UpdateNodes(context.Background(), doc, "10.1.0.0/24", "10.2.0.0/24", "", connect, nil, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 || nodes[0].Xname != "x1000c0s0n0" {
+		t.Errorf("nodes = %+v", nodes)
+	}
+	if _, ok := failed["blade-7"]; !ok || len(failed) != 1 {
+		t.Errorf("failed = %v, want blade-7", failed)
+	}
+	// The malformed entry is neither contacted nor updated
+	for _, h := range contacted {
+		if h == "10.1.0.3" {
+			t.Error("BMC with a malformed xname was contacted")
+		}
+	}
+	if doc.BMCs[1].MAC != "02:00:00:00:00:07" {
+		t.Errorf("MAC of the malformed entry = %s", doc.BMCs[1].MAC)
+	}
+}
+
+func TestUpdateNodesSimulatedBMCs(t *testing.T) {
+	// A single-node server, a dual-node blade and a riser card with four nodes
+	// that lists them last to first
+	riser := redfishtest.NewSimBMC(4, "aa:00:00:00:03")
+	slices.Reverse(riser.Systems)
+	sims := map[string]*redfishtest.SimBMC{
+		"x1000c0s0b0": redfishtest.NewSimBMC(1, "aa:00:00:00:01"),
+		"x1000c0s1b0": redfishtest.NewSimBMC(2, "aa:00:00:00:02"),
+		"x1000c0s2b0": riser,
+	}
+	hosts := map[string]string{}
+	doc := &inventory.FileFormat{}
+	for _, x := range slices.Sorted(maps.Keys(sims)) {
+		ts := httptest.NewTLSServer(sims[x])
+		defer ts.Close()
+		hosts[x] = strings.TrimPrefix(ts.URL, "https://")
+		doc.BMCs = append(doc.BMCs, inventory.Entry{Xname: x})
+	}
+	connect := func(x string) redfish.Discoverer { return redfish.New(hosts[x], "u", "p", true, time.Second) }
+
+	nodes, failed, _, err := UpdateNodes(context.Background(), doc, "10.1.0.0/24", "10.2.0.0/24", "", connect, nil, 5*time.Second)
+	if err != nil || len(failed) > 0 {
+		t.Fatal(err, failed)
+	}
+	got := map[string]string{}
+	for _, n := range nodes {
+		got[n.Xname] = n.MAC
+	}
+	want := map[string]string{
+		"x1000c0s0b0n0": "aa:00:00:00:01:00",
+		"x1000c0s1b0n0": "aa:00:00:00:02:00",
+		"x1000c0s1b0n1": "aa:00:00:00:02:01",
+		"x1000c0s2b0n0": "aa:00:00:00:03:00",
+		"x1000c0s2b0n1": "aa:00:00:00:03:01",
+		"x1000c0s2b0n2": "aa:00:00:00:03:02",
+		"x1000c0s2b0n3": "aa:00:00:00:03:03",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("nodes = %v, want %v", got, want)
+	}
+
+	g := initbmcs.Geometry{Name: "riser", Slots: 3, BladesPerSlot: 1, NodesPerBMC: 4}
+	r := CompareExpected(g, doc.BMCs, nodes, failed)
+	if r.Expected != 12 || r.Found != 7 || len(r.Unexpected) != 0 {
+		t.Errorf("report = %+v", r)
+	}
+	if want := []string{"x1000c0s0b0n1", "x1000c0s0b0n2", "x1000c0s0b0n3", "x1000c0s1b0n2", "x1000c0s1b0n3"}; !reflect.DeepEqual(r.Missing, want) {
+		t.Errorf("Missing = %v, want %v", r.Missing, want)
+	}
+}
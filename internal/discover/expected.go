@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package discover
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"bootstrap/internal/initbmcs"
+	"bootstrap/internal/xname"
+	"bootstrap/pkg/inventory"
+)
+
+var (
+	bladePos = regexp.MustCompile(`s(\d+)b(\d+)$`)
+	nodeNum  = regexp.MustCompile(`n(\d+)$`)
+)
+
+// ExpectedReport compares the nodes a geometry puts on the discovered BMCs with
+// the nodes discovery found on them.
+type ExpectedReport struct {
+	Geometry string
+	Expected int // nodes the geometry puts on the blades that were read
+	Found    int
+	// Missing are nodes the geometry expects on a blade that was read but that
+	// discovery did not find.
+	Missing []string
+	// Unexpected are nodes found where the geometry has no place for them: a node
+	// number past nodes_per_bmc, or a blade outside the slots of the chassis.
+	Unexpected []string
+	// Empty are blades that answered without any system to record.
+	Empty []string
+	// Unreached are blades that could not be read, keyed by xname: an empty slot
+	// or a broken BMC.
+	Unreached map[string]error
+}
+
+// CompareExpected builds the ExpectedReport of a discovery run over bmcs, which
+// found the nodes in found and failed on the BMCs in failed (keyed by xname).
+func CompareExpected(g initbmcs.Geometry, bmcs, found []inventory.Entry, failed map[string]error) ExpectedReport {
+	r := ExpectedReport{Geometry: g.Name, Unreached: map[string]error{}}
+	byBlade := map[string][]inventory.Entry{}
+	for _, n := range found {
+		b := xname.NodeToBMCXname(n.Xname)
+		byBlade[b] = append(byBlade[b], n)
+	}
+	seen := map[string]bool{}
+	for _, b := range bmcs {
+		if seen[b.Xname] {
+			continue
+		}
+		seen[b.Xname] = true
+		if err, ok := failed[b.Xname]; ok {
+			r.Unreached[b.Xname] = err
+			continue
+		}
+		nodes := byBlade[b.Xname]
+		r.Found += len(nodes)
+		if len(nodes) == 0 {
+			r.Empty = append(r.Empty, b.Xname)
+		}
+		placed := inGeometry(g, b.Xname)
+		if placed {
+			r.Expected += g.NodesPerBMC
+		}
+		have := map[int]bool{}
+		for _, n := range nodes {
+			num := -1
+			if m := nodeNum.FindStringSubmatch(n.Xname); m != nil {
+				num, _ = strconv.Atoi(m[1])
+			}
+			have[num] = true
+			if !placed || num < 0 || num >= g.NodesPerBMC {
+				r.Unexpected = append(r.Unexpected, n.Xname)
+			}
+		}
+		if placed && len(nodes) > 0 {
+			for i := 0; i < g.NodesPerBMC; i++ {
+				// Blades with nodes have a valid xname; discover fails the others
+				if x, err := xname.BMCXnameToNodeN(b.Xname, i); err == nil && !have[i] {
+					r.Missing = append(r.Missing, x)
+				}
+			}
+		}
+	}
+	sort.Strings(r.Missing)
+	sort.Strings(r.Unexpected)
+	sort.Strings(r.Empty)
+	return r
+}
+
+// inGeometry reports whether the slot and blade numbers of a BMC xname fit g.
+// A BMC whose xname has none is taken to fit.
+func inGeometry(g initbmcs.Geometry, bmcX string) bool {
+	m := bladePos.FindStringSubmatch(bmcX)
+	if m == nil {
+		return true
+	}
+	slot, _ := strconv.Atoi(m[1])
+	blade, _ := strconv.Atoi(m[2])
+	return slot < g.Slots && blade < g.BladesPerSlot
+}
+
+// Write prints the report, one line per category that is not empty.
+func (r ExpectedReport) Write(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Expected vs found (geometry %s): %d node(s) expected, %d found\n", r.Geometry, r.Expected, r.Found)
+	list := func(label string, xs []string) {
+		if len(xs) > 0 {
+			fmt.Fprintf(&b, "  %s (%d): %s\n", label, len(xs), strings.Join(xs, ", "))
+		}
+	}
+	list("missing nodes", r.Missing)
+	list("unexpected nodes", r.Unexpected)
+	list("empty blades", r.Empty)
+	if len(r.Unreached) > 0 {
+		xs := make([]string, 0, len(r.Unreached))
+		for x := range r.Unreached {
+			xs = append(xs, x)
+		}
+		sort.Strings(xs)
+		fmt.Fprintf(&b, "  unreachable blades (%d), empty slots or failed BMCs:\n", len(xs))
+		for _, x := range xs {
+			fmt.Fprintf(&b, "    %s: %v\n", x, r.Unreached[x])
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
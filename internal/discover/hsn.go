@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package discover
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"bootstrap/pkg/inventory"
+	"bootstrap/pkg/redfish"
+)
+
+// DefaultHSNPattern matches the Id or Name of high-speed network interfaces.
+const DefaultHSNPattern = `(?i)hpcnet|hsn`
+
+// UpdateHSN re-reads the interfaces of every system behind doc.BMCs (through the
+// client connect returns for its host) and records those whose Id, Name or
+// Description match pattern as the hsn[] of the matching node in doc.Nodes. Nodes
+// are named from the BMC xname and system node number, as in UpdateNodes. It is
+// meant to run after the nodes have booted, when their HSN MACs are known.
+// timeout bounds each BMC (0 = no limit). BMCs that could not be queried are
+// returned in failed, keyed by xname; updated counts the nodes whose hsn[] was set.
+func UpdateHSN(ctx context.Context, doc *inventory.FileFormat, connect func(host string) redfish.Discoverer, pattern *regexp.Regexp, timeout time.Duration) (updated int, failed map[string]error, err error) {
+	failed = map[string]error{}
+	for _, b := range doc.BMCs {
+		if err := ctx.Err(); err != nil {
+			return updated, failed, err
+		}
+		host := b.IP
+		if host == "" {
+			host = b.Xname
+		}
+		bmcCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout > 0 {
+			bmcCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		nics, err := connect(host).DiscoverSystemNICs(bmcCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				return updated, failed, ctx.Err()
+			}
+			fmt.Fprintf(os.Stderr, "WARN: %s: discover HSN: %v\n", b.Xname, err)
+			failed[b.Xname] = err
+			continue
+		}
+
+		// Group by system in the order the BMC lists them
+		var order []string
+		bySystem := map[string][]inventory.NIC{}
+		for _, n := range nics {
+			if _, seen := bySystem[n.SystemPath]; !seen {
+				order = append(order, n.SystemPath)
+				bySystem[n.SystemPath] = nil
+			}
+			if pattern.MatchString(n.ID) || pattern.MatchString(n.Name) || pattern.MatchString(n.Description) {
+				bySystem[n.SystemPath] = append(bySystem[n.SystemPath], inventory.NIC{ID: n.ID, MAC: n.MAC, Description: n.Description})
+			}
+		}
+		nodeXs, err := systemNodes(b.Xname, order)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "WARN: %s: discover HSN: %v\n", b.Xname, err)
+			failed[b.Xname] = err
+			continue
+		}
+		for sysIdx, sysPath := range order {
+			nodeX := nodeXs[sysIdx]
+			node := findByXname(doc.Nodes, nodeX)
+			if node == nil {
+				fmt.Fprintf(os.Stderr, "WARN: %s: %s is not in nodes[]; run discover first\n", nodeX, sysPath)
+				continue
+			}
+			hsn := bySystem[sysPath]
+			if len(hsn) == 0 {
+				fmt.Fprintf(os.Stderr, "WARN: %s: no HSN MACs reported yet (has the node booted?)\n", nodeX)
+				continue
+			}
+			node.HSN = hsn
+			updated++
+		}
+	}
+	return updated, failed, nil
+}
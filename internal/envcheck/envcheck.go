@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package envcheck decides whether the environment of a liquid-cooled cabinet
+// (coolant temperatures, leak detectors) is safe for powering blades on.
+package envcheck
+
+import (
+	"fmt"
+	"strings"
+
+	"bootstrap/pkg/redfish"
+)
+
+// Limits are coolant temperature bounds in °C, on top of the critical thresholds
+// the sensors report themselves. Zero means no bound.
+type Limits struct {
+	MinCoolantC float64
+	MaxCoolantC float64
+}
+
+// Problem is a reading that blocks power-on.
+type Problem struct {
+	Host    string
+	Chassis string
+	Name    string // sensor or leak detector Id
+	Reason  string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s %s/%s: %s", p.Host, p.Chassis, p.Name, p.Reason)
+}
+
+// Result is the outcome of checking the environment of one or more BMCs.
+type Result struct {
+	Sensors       int // sensors looked at
+	Coolant       int // of which coolant temperature sensors
+	LeakDetectors int
+	Problems      []Problem
+}
+
+// Coolant reports whether s measures a coolant temperature: a temperature sensor
+// at a liquid inlet or outlet, or one whose name mentions coolant.
+func Coolant(s redfish.Sensor) bool {
+	if s.ReadingType != "Temperature" {
+		return false
+	}
+	switch s.PhysicalContext {
+	case "LiquidInlet", "LiquidOutlet", "Coolant":
+		return true
+	}
+	return strings.Contains(strings.ToLower(s.ID+" "+s.Name), "coolant")
+}
+
+// Check adds the readings of env, read from host, to r. Blocking are: a leak
+// detector that is not OK, a sensor whose health is Critical, a reading past
+// the sensor's own critical thresholds, and a coolant temperature outside lim.
+func (r *Result) Check(host string, env redfish.Environment, lim Limits) {
+	add := func(chassis, name, format string, args ...any) {
+		r.Problems = append(r.Problems, Problem{Host: host, Chassis: chassis, Name: name, Reason: fmt.Sprintf(format, args...)})
+	}
+	for _, d := range env.LeakDetectors {
+		r.LeakDetectors++
+		if st := d.DetectorState; st != "" && st != "OK" {
+			add(d.Chassis, d.ID, "leak detector %s", st)
+		} else if d.Status.Health == "Critical" {
+			add(d.Chassis, d.ID, "leak detector health Critical")
+		}
+	}
+	for _, s := range env.Sensors {
+		r.Sensors++
+		coolant := Coolant(s.Sensor)
+		if coolant {
+			r.Coolant++
+		}
+		if s.Status.Health == "Critical" {
+			add(s.Chassis, s.ID, "health Critical")
+			continue
+		}
+		if s.Reading == nil {
+			continue
+		}
+		v, unit := *s.Reading, s.ReadingUnits
+		switch {
+		case s.Thresholds.UpperCritical.Reading != nil && v >= *s.Thresholds.UpperCritical.Reading:
+			add(s.Chassis, s.ID, "%g %s at or above critical %g", v, unit, *s.Thresholds.UpperCritical.Reading)
+		case s.Thresholds.LowerCritical.Reading != nil && v <= *s.Thresholds.LowerCritical.Reading:
+			add(s.Chassis, s.ID, "%g %s at or below critical %g", v, unit, *s.Thresholds.LowerCritical.Reading)
+		case coolant && lim.MaxCoolantC != 0 && v > lim.MaxCoolantC:
+			add(s.Chassis, s.ID, "coolant %g Cel above the limit of %g", v, lim.MaxCoolantC)
+		case coolant && lim.MinCoolantC != 0 && v < lim.MinCoolantC:
+			add(s.Chassis, s.ID, "coolant %g Cel below the limit of %g", v, lim.MinCoolantC)
+		}
+	}
+}
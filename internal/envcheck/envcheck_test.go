@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package envcheck
+
+import (
+	"reflect"
+	"testing"
+
+	"bootstrap/pkg/redfish"
+)
+
+func TestCheck(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	sensor := func(id, kind, ctx string, v *float64) redfish.EnvSensor {
+		s := redfish.EnvSensor{Chassis: "Enclosure", Sensor: redfish.Sensor{ReadingType: kind, PhysicalContext: ctx, Reading: v, ReadingUnits: "Cel"}}
+		s.ID = id
+		return s
+	}
+	hot := sensor("Inlet", "Temperature", "LiquidInlet", f(41))
+	hot.Thresholds.UpperCritical.Reading = f(40)
+	warm := sensor("CoolantReturn", "Temperature", "", f(33))
+	air := sensor("Ambient", "Temperature", "Intake", f(33))
+	cold := sensor("Outlet", "Temperature", "LiquidOutlet", f(12))
+	unread := sensor("Pressure", "PressurePa", "", nil)
+	unread.Status.Health = "Critical"
+	leak := redfish.EnvLeakDetector{Chassis: "Enclosure", LeakDetector: redfish.LeakDetector{DetectorState: "Critical"}}
+	leak.ID = "Tray0"
+	dry := redfish.EnvLeakDetector{Chassis: "Enclosure", LeakDetector: redfish.LeakDetector{DetectorState: "OK"}}
+	dry.ID = "Tray1"
+
+	var r Result
+	r.Check("cmm", redfish.Environment{
+		Sensors:       []redfish.EnvSensor{hot, warm, air, cold, unread},
+		LeakDetectors: []redfish.EnvLeakDetector{leak, dry},
+	}, Limits{MinCoolantC: 15, MaxCoolantC: 32})
+
+	var got []string
+	for _, p := range r.Problems {
+		got = append(got, p.String())
+	}
+	want := []string{
+		"cmm Enclosure/Tray0: leak detector Critical",
+		"cmm Enclosure/Inlet: 41 Cel at or above critical 40",
+		"cmm Enclosure/CoolantReturn: coolant 33 Cel above the limit of 32",
+		"cmm Enclosure/Outlet: coolant 12 Cel below the limit of 15",
+		"cmm Enclosure/Pressure: health Critical",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("problems:\n%q\nwant:\n%q", got, want)
+	}
+	if r.Sensors != 5 || r.Coolant != 3 || r.LeakDetectors != 2 {
+		t.Errorf("counts = %d sensors, %d coolant, %d leak detectors", r.Sensors, r.Coolant, r.LeakDetectors)
+	}
+}
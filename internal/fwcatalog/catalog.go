@@ -0,0 +1,317 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package fwcatalog reads vendor firmware catalogs and picks the image of each
+// FirmwareInventory component. A catalog is a YAML or JSON file, a directory of
+// such files (one component or catalog per file), or a Dell Catalog.xml; it may
+// be read from a local path or an http(s) URL.
+package fwcatalog
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf16"
+
+	"bootstrap/internal/fwversion"
+	"bootstrap/pkg/redfish"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Entry is one firmware image in a catalog.
+type Entry struct {
+	Name string `yaml:"name" json:"name"`
+	// Component is a glob matched against the Id and Name of FirmwareInventory
+	// members (case-insensitive), e.g. "BIOS" or "Node*.BIOS".
+	Component string `yaml:"component" json:"component"`
+	// SoftwareIDs match the SoftwareId of FirmwareInventory members (the Dell
+	// component IDs).
+	SoftwareIDs []string `yaml:"software_ids" json:"software_ids"`
+	// Models are globs matched against the Model of the BMC's systems and
+	// managers; none means any model.
+	Models   []string `yaml:"models" json:"models"`
+	Version  string   `yaml:"version" json:"version"`
+	ImageURI string   `yaml:"image_uri" json:"image_uri"` // relative to the catalog's base
+	// Reset is the reset a firmware apply phase of this image runs: none,
+	// manager or system.
+	Reset string `yaml:"reset" json:"reset"`
+}
+
+// Catalog is the list of firmware images a site can flash.
+type Catalog struct {
+	// BaseURI resolves relative image URIs; it defaults to the catalog URL.
+	BaseURI string  `yaml:"base_uri" json:"base_uri"`
+	Entries []Entry `yaml:"components" json:"components"`
+}
+
+// Load reads the catalog at src, a local file or directory or an http(s) URL,
+// fetched with hc (http.DefaultClient when nil). A non-empty base overrides the
+// catalog's own base URI, e.g. for a local mirror of a vendor repository.
+func Load(ctx context.Context, hc *http.Client, src, base string) (Catalog, error) {
+	var c Catalog
+	var err error
+	switch {
+	case strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://"):
+		var raw []byte
+		if raw, err = fetch(ctx, hc, src); err == nil {
+			c, err = parse(raw)
+		}
+		if c.BaseURI == "" {
+			c.BaseURI = src
+		}
+	default:
+		c, err = loadPath(src)
+	}
+	if err != nil {
+		return Catalog{}, fmt.Errorf("%s: %w", src, err)
+	}
+	if base != "" {
+		c.BaseURI = base
+		if !strings.HasSuffix(base, "/") {
+			c.BaseURI += "/"
+		}
+	}
+	for i := range c.Entries {
+		if err := c.resolve(&c.Entries[i]); err != nil {
+			return Catalog{}, fmt.Errorf("%s: %w", src, err)
+		}
+	}
+	return c, nil
+}
+
+func fetch(ctx context.Context, hc *http.Client, src string) ([]byte, error) {
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET: %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func loadPath(src string) (Catalog, error) {
+	fi, err := os.Stat(src)
+	if err != nil {
+		return Catalog{}, err
+	}
+	if !fi.IsDir() {
+		raw, err := os.ReadFile(src)
+		if err != nil {
+			return Catalog{}, err
+		}
+		return parse(raw)
+	}
+	var files []string
+	for _, pat := range []string{"*.json", "*.yaml", "*.yml"} {
+		m, _ := filepath.Glob(filepath.Join(src, pat))
+		files = append(files, m...)
+	}
+	slices.Sort(files)
+	var c Catalog
+	for _, f := range files {
+		raw, err := os.ReadFile(f)
+		if err != nil {
+			return Catalog{}, err
+		}
+		sub, err := parse(raw)
+		if err != nil {
+			return Catalog{}, fmt.Errorf("%s: %w", filepath.Base(f), err)
+		}
+		if len(sub.Entries) == 0 {
+			// A file describing one image
+			var e Entry
+			if err := yaml.Unmarshal(raw, &e); err != nil {
+				return Catalog{}, fmt.Errorf("%s: %w", filepath.Base(f), err)
+			}
+			sub.Entries = []Entry{e}
+		}
+		c.BaseURI = cmp.Or(c.BaseURI, sub.BaseURI)
+		c.Entries = append(c.Entries, sub.Entries...)
+	}
+	if len(c.Entries) == 0 {
+		return Catalog{}, fmt.Errorf("no *.json or *.yaml catalog files")
+	}
+	return c, nil
+}
+
+// parse decodes a Dell Catalog.xml or a YAML/JSON catalog.
+func parse(raw []byte) (Catalog, error) {
+	raw = toUTF8(raw)
+	if t := bytes.TrimSpace(raw); len(t) > 0 && t[0] == '<' {
+		return parseDell(t)
+	}
+	var c Catalog
+	if err := yaml.Unmarshal(raw, &c); err != nil {
+		return Catalog{}, err
+	}
+	return c, nil
+}
+
+// toUTF8 converts UTF-16 input (as Dell ships Catalog.xml) and drops a UTF-8 BOM.
+func toUTF8(raw []byte) []byte {
+	var order binary.ByteOrder
+	switch {
+	case bytes.HasPrefix(raw, []byte{0xff, 0xfe}):
+		order = binary.LittleEndian
+	case bytes.HasPrefix(raw, []byte{0xfe, 0xff}):
+		order = binary.BigEndian
+	default:
+		return bytes.TrimPrefix(raw, []byte{0xef, 0xbb, 0xbf})
+	}
+	u := make([]uint16, 0, len(raw)/2)
+	for i := 2; i+1 < len(raw); i += 2 {
+		u = append(u, order.Uint16(raw[i:]))
+	}
+	return []byte(string(utf16.Decode(u)))
+}
+
+type dellDisplay struct {
+	Text string `xml:"Display"`
+}
+
+type dellCatalog struct {
+	BaseLocation string `xml:"baseLocation,attr"`
+	Components   []struct {
+		Path          string      `xml:"path,attr"`
+		VendorVersion string      `xml:"vendorVersion,attr"`
+		Name          dellDisplay `xml:"Name"`
+		Devices       []struct {
+			ComponentID string `xml:"componentID,attr"`
+		} `xml:"SupportedDevices>Device"`
+		Brands []struct {
+			Display string        `xml:"Display"`
+			Models  []dellDisplay `xml:"Model"`
+		} `xml:"SupportedSystems>Brand"`
+	} `xml:"SoftwareComponent"`
+}
+
+// parseDell reads the SoftwareComponents of a Dell Catalog.xml. Only the
+// Windows update packages (.exe) are kept: they are what iDRAC flashes.
+func parseDell(raw []byte) (Catalog, error) {
+	var dc dellCatalog
+	d := xml.NewDecoder(bytes.NewReader(raw))
+	d.CharsetReader = func(_ string, r io.Reader) (io.Reader, error) { return r, nil } // already UTF-8
+	if err := d.Decode(&dc); err != nil {
+		return Catalog{}, err
+	}
+	var c Catalog
+	if dc.BaseLocation != "" {
+		c.BaseURI = "https://" + strings.TrimSuffix(dc.BaseLocation, "/") + "/"
+	}
+	for _, sc := range dc.Components {
+		if !strings.EqualFold(path.Ext(sc.Path), ".exe") || len(sc.Devices) == 0 {
+			continue
+		}
+		e := Entry{Name: strings.TrimSpace(sc.Name.Text), Version: sc.VendorVersion, ImageURI: sc.Path}
+		for _, dev := range sc.Devices {
+			e.SoftwareIDs = append(e.SoftwareIDs, dev.ComponentID)
+		}
+		for _, b := range sc.Brands {
+			for _, m := range b.Models {
+				e.Models = append(e.Models, strings.TrimSpace(b.Display+" "+m.Text))
+			}
+		}
+		c.Entries = append(c.Entries, e)
+	}
+	return c, nil
+}
+
+// resolve validates e and makes its image URI absolute.
+func (c Catalog) resolve(e *Entry) error {
+	name := cmp.Or(e.Name, e.Component, e.ImageURI)
+	if e.Version == "" || e.ImageURI == "" {
+		return fmt.Errorf("component %s: version and image_uri are required", name)
+	}
+	if e.Component == "" && len(e.SoftwareIDs) == 0 {
+		return fmt.Errorf("component %s: component or software_ids is required", name)
+	}
+	for _, g := range append([]string{e.Component}, e.Models...) {
+		if _, err := path.Match(strings.ToLower(g), ""); err != nil {
+			return fmt.Errorf("component %s: bad pattern %q", name, g)
+		}
+	}
+	switch e.Reset {
+	case "", "none", "manager", "system":
+	default:
+		return fmt.Errorf("component %s: reset must be none, manager or system", name)
+	}
+	u, err := url.Parse(e.ImageURI)
+	if err != nil {
+		return fmt.Errorf("component %s: %w", name, err)
+	}
+	if u.IsAbs() {
+		return nil
+	}
+	if c.BaseURI == "" {
+		return fmt.Errorf("component %s: relative image_uri %s needs a base_uri", name, e.ImageURI)
+	}
+	base, err := url.Parse(c.BaseURI)
+	if err != nil {
+		return fmt.Errorf("base_uri: %w", err)
+	}
+	e.ImageURI = base.ResolveReference(u).String()
+	return nil
+}
+
+// Match returns the index of the entry for comp on a BMC whose systems and
+// managers report models, or -1 when there is none. When several entries match,
+// the newest version wins, then the first listed.
+func (c Catalog) Match(comp redfish.FirmwareComponent, models []string) int {
+	best := -1
+	for i, e := range c.Entries {
+		if !e.matches(comp, models) {
+			continue
+		}
+		if best < 0 {
+			best = i
+		} else if n, err := fwversion.Compare(e.Version, c.Entries[best].Version); err == nil && n > 0 {
+			best = i
+		}
+	}
+	return best
+}
+
+func (e Entry) matches(comp redfish.FirmwareComponent, models []string) bool {
+	if len(e.Models) > 0 && !slices.ContainsFunc(models, func(m string) bool { return globAny(e.Models, m) }) {
+		return false
+	}
+	if comp.SoftwareID != "" && slices.Contains(e.SoftwareIDs, comp.SoftwareID) {
+		return true
+	}
+	return e.Component != "" && (globAny([]string{e.Component}, comp.ID) || globAny([]string{e.Component}, comp.Name))
+}
+
+// globAny reports whether s matches any of the case-insensitive globs.
+func globAny(globs []string, s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, g := range globs {
+		if ok, _ := path.Match(strings.ToLower(g), strings.ToLower(s)); ok {
+			return true
+		}
+	}
+	return false
+}
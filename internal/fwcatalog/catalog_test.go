@@ -0,0 +1,163 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package fwcatalog
+
+import (
+	"context"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"bootstrap/pkg/redfish"
+)
+
+const native = `base_uri: http://repo.example/fw/
+components:
+  - name: node BIOS
+    component: "Node*.BIOS"
+    models: ["EX425*"]
+    version: "1.2.0"
+    image_uri: bios-1.2.0.bin
+    reset: system
+  - component: BMC
+    version: nc.1.10.1
+    image_uri: https://other.example/nc-1.10.1.tar.gz
+  - component: BMC
+    version: nc.1.9.8
+    image_uri: nc-1.9.8.tar.gz
+`
+
+func TestLoadAndMatch(t *testing.T) {
+	dir := t.TempDir()
+	f := filepath.Join(dir, "catalog.yaml")
+	if err := os.WriteFile(f, []byte(native), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Load(context.Background(), nil, f, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Entries[0].ImageURI; got != "http://repo.example/fw/bios-1.2.0.bin" {
+		t.Errorf("image = %s", got)
+	}
+	bios := redfish.FirmwareComponent{ID: "Node0.BIOS", Version: "1.1.0"}
+	if i := c.Match(bios, []string{"EX425 Blade"}); i != 0 {
+		t.Errorf("BIOS on EX425 matched %d", i)
+	}
+	if i := c.Match(bios, []string{"EX235n"}); i != -1 {
+		t.Errorf("BIOS on another model matched %d", i)
+	}
+	// The newest of several matching entries wins
+	if i := c.Match(redfish.FirmwareComponent{ID: "bmc"}, nil); i != 1 {
+		t.Errorf("BMC matched %d", i)
+	}
+
+	// --image-base points relative images at a mirror
+	c, err = Load(context.Background(), nil, f, "http://mirror.local/fw")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Entries[2].ImageURI; got != "http://mirror.local/fw/nc-1.9.8.tar.gz" {
+		t.Errorf("mirrored image = %s", got)
+	}
+
+	if err := os.WriteFile(f, []byte("components:\n  - component: BMC\n    version: \"1\"\n    image_uri: a.bin\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(context.Background(), nil, f, ""); err == nil || !strings.Contains(err.Error(), "needs a base_uri") {
+		t.Errorf("relative image without a base: %v", err)
+	}
+}
+
+func TestLoadDirectoryAndURL(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"10-bmc.json":  `{"component":"BMC","version":"2.0","image_uri":"bmc.bin"}`,
+		"20-bios.yaml": "components:\n  - component: BIOS\n    version: \"3.0\"\n    image_uri: /fw/bios.bin\n",
+		"README":       "ignored",
+	}
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c, err := Load(context.Background(), nil, dir, "http://repo.example/fw/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range c.Entries {
+		got = append(got, e.Component+" "+e.ImageURI)
+	}
+	if want := []string{"BMC http://repo.example/fw/bmc.bin", "BIOS http://repo.example/fw/bios.bin"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("entries = %v, want %v", got, want)
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repo/catalog.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("components:\n  - component: BMC\n    version: \"2.0\"\n    image_uri: images/bmc.bin\n"))
+	}))
+	defer ts.Close()
+	c, err = Load(context.Background(), ts.Client(), ts.URL+"/repo/catalog.yaml", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Entries[0].ImageURI; got != ts.URL+"/repo/images/bmc.bin" {
+		t.Errorf("image from URL catalog = %s", got)
+	}
+	if _, err := Load(context.Background(), ts.Client(), ts.URL+"/missing.yaml", ""); err == nil {
+		t.Error("missing catalog loaded")
+	}
+}
+
+func TestDellCatalog(t *testing.T) {
+	xml := `<?xml version="1.0" encoding="utf-16"?>
+<Manifest baseLocation="downloads.dell.com" version="24.01.00">
+  <SoftwareComponent path="FOLDER01/1/BIOS_ABCD_WN64_1.12.1.EXE" vendorVersion="1.12.1">
+    <Name><Display lang="en">Dell Server BIOS PowerEdge R650</Display></Name>
+    <SupportedDevices><Device componentID="159"><Display lang="en">BIOS</Display></Device></SupportedDevices>
+    <SupportedSystems><Brand key="3" prefix="PE"><Display lang="en">PowerEdge</Display>
+      <Model systemID="0A3F"><Display lang="en">R650</Display></Model></Brand></SupportedSystems>
+  </SoftwareComponent>
+  <SoftwareComponent path="FOLDER01/2/BIOS_ABCD_LN64_1.12.1.BIN" vendorVersion="1.12.1">
+    <SupportedDevices><Device componentID="159"/></SupportedDevices>
+  </SoftwareComponent>
+</Manifest>`
+	// Dell ships Catalog.xml as UTF-16 with a byte order mark
+	raw := []byte{0xff, 0xfe}
+	for _, u := range utf16.Encode([]rune(xml)) {
+		raw = binary.LittleEndian.AppendUint16(raw, u)
+	}
+	f := filepath.Join(t.TempDir(), "Catalog.xml")
+	if err := os.WriteFile(f, raw, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Load(context.Background(), nil, f, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Entry{{
+		Name:        "Dell Server BIOS PowerEdge R650",
+		SoftwareIDs: []string{"159"},
+		Models:      []string{"PowerEdge R650"},
+		Version:     "1.12.1",
+		ImageURI:    "https://downloads.dell.com/FOLDER01/1/BIOS_ABCD_WN64_1.12.1.EXE",
+	}}
+	if !reflect.DeepEqual(c.Entries, want) {
+		t.Errorf("entries = %+v, want %+v", c.Entries, want)
+	}
+	if i := c.Match(redfish.FirmwareComponent{ID: "Current-159-1.10.2__BIOS.Setup.1-1", SoftwareID: "159"}, []string{"PowerEdge R650"}); i != 0 {
+		t.Errorf("Dell BIOS matched %d", i)
+	}
+}
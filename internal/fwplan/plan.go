@@ -0,0 +1,349 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package fwplan sequences multi-component firmware updates on a BMC: each
+// phase is flashed, waited on and optionally followed by a reset before the next
+// phase starts.
+package fwplan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"bootstrap/internal/fwversion"
+	"bootstrap/pkg/redfish"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Reset modes run after a phase
+const (
+	ResetNone    = "none"
+	ResetManager = "manager"
+	ResetSystem  = "system"
+)
+
+const (
+	defaultPollInterval = 15 * time.Second
+	defaultPhaseTimeout = 30 * time.Minute
+	defaultResetType    = "GracefulRestart"
+)
+
+// Phase is one SimpleUpdate in a plan.
+type Phase struct {
+	Name     string   `yaml:"name"`
+	Targets  []string `yaml:"targets"`
+	ImageURI string   `yaml:"image_uri"`
+	Protocol string   `yaml:"protocol,omitempty"`
+	// Version, when set, skips the phase if every target already reports it and
+	// is checked after the phase completes.
+	Version string `yaml:"version,omitempty"`
+	// Wait for the update task to finish before moving on (default true).
+	Wait *bool `yaml:"wait,omitempty"`
+	// Reset is one of none, manager or system.
+	Reset     string `yaml:"reset,omitempty"`
+	ResetType string `yaml:"reset_type,omitempty"`
+	// Settle is how long to wait after a reset before polling the BMC again.
+	Settle time.Duration `yaml:"settle,omitempty"`
+	// Task is the task monitor SimpleUpdate returned for the phase, if any; set
+	// when the phase runs.
+	Task string `yaml:"-"`
+}
+
+// Plan is an ordered list of phases applied to every host.
+type Plan struct {
+	Phases       []Phase       `yaml:"phases"`
+	PollInterval time.Duration `yaml:"poll_interval,omitempty"`
+	PhaseTimeout time.Duration `yaml:"phase_timeout,omitempty"`
+	// AllowDowngrade permits phases whose version is older than the installed one.
+	AllowDowngrade bool `yaml:"allow_downgrade,omitempty"`
+}
+
+// Load reads and validates a plan file, filling in defaults.
+func Load(path string) (Plan, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Plan{}, err
+	}
+	var p Plan
+	if err := yaml.Unmarshal(raw, &p); err != nil {
+		return Plan{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return p, p.normalize()
+}
+
+func (p *Plan) normalize() error {
+	if len(p.Phases) == 0 {
+		return fmt.Errorf("plan has no phases")
+	}
+	if p.PollInterval <= 0 {
+		p.PollInterval = defaultPollInterval
+	}
+	if p.PhaseTimeout <= 0 {
+		p.PhaseTimeout = defaultPhaseTimeout
+	}
+	for i := range p.Phases {
+		ph := &p.Phases[i]
+		if ph.Name == "" {
+			ph.Name = fmt.Sprintf("phase%d", i+1)
+		}
+		if len(ph.Targets) == 0 || ph.ImageURI == "" {
+			return fmt.Errorf("phase %s: targets and image_uri are required", ph.Name)
+		}
+		if ph.Protocol == "" {
+			ph.Protocol = "HTTP"
+		}
+		if ph.Wait == nil {
+			wait := true
+			ph.Wait = &wait
+		}
+		switch ph.Reset {
+		case "":
+			ph.Reset = ResetNone
+		case ResetNone, ResetManager, ResetSystem:
+		default:
+			return fmt.Errorf("phase %s: reset must be none, manager or system", ph.Name)
+		}
+		if ph.ResetType == "" {
+			ph.ResetType = defaultResetType
+		}
+	}
+	return nil
+}
+
+// Phase outcomes
+const (
+	StatusUpdated = "updated"
+	StatusSkipped = "skipped"
+	StatusFailed  = "failed"
+	StatusNotRun  = "not-run"
+)
+
+// PhaseResult is the outcome of one phase on one host.
+type PhaseResult struct {
+	Phase  string
+	Status string
+	Err    error
+}
+
+// Run applies the plan's phases in order through c. A failed phase stops the
+// run; the remaining phases are reported as not-run. logf receives progress
+// messages.
+func Run(ctx context.Context, c redfish.Client, p Plan, logf func(format string, args ...any)) []PhaseResult {
+	out := make([]PhaseResult, 0, len(p.Phases))
+	failed := false
+	for _, ph := range p.Phases {
+		if failed {
+			out = append(out, PhaseResult{Phase: ph.Name, Status: StatusNotRun})
+			continue
+		}
+		r := runPhase(ctx, c, p, ph, logf)
+		failed = r.Status == StatusFailed
+		out = append(out, r)
+	}
+	return out
+}
+
+func runPhase(ctx context.Context, c redfish.Client, p Plan, ph Phase, logf func(string, ...any)) PhaseResult {
+	fail := func(format string, args ...any) PhaseResult {
+		return PhaseResult{Phase: ph.Name, Status: StatusFailed, Err: fmt.Errorf(format, args...)}
+	}
+	if ph.Version != "" {
+		if ok, _ := atVersion(ctx, c, ph.Targets, ph.Version); ok {
+			return PhaseResult{Phase: ph.Name, Status: StatusSkipped}
+		}
+		if !p.AllowDowngrade {
+			if err := checkDowngrade(ctx, c, ph.Targets, ph.Version); err != nil {
+				return fail("%w", err)
+			}
+		}
+	}
+	proto, err := redfish.ResolveProtocol(ctx, c, ph.Protocol)
+	if err != nil {
+		return fail("%w", err)
+	}
+	logf("%s: SimpleUpdate %s -> %s", ph.Name, ph.ImageURI, strings.Join(ph.Targets, ","))
+	up, err := c.SimpleUpdate(ctx, ph.ImageURI, ph.Targets, proto, "", true)
+	if err != nil {
+		return fail("update: %w", err)
+	}
+	ph.Task = up.TaskURI
+	if ph.Reset != ResetNone {
+		logf("%s: %s reset (%s) once the update completes", ph.Name, ph.Reset, ph.ResetType)
+	}
+	if err := Activate(ctx, c, p, ph); err != nil {
+		return fail("%w", err)
+	}
+	return PhaseResult{Phase: ph.Name, Status: StatusUpdated}
+}
+
+// Activate finishes a phase after its SimpleUpdate was posted: it waits for the
+// update to complete, performs the phase's reset and waits for the BMC to answer
+// again, then checks ph.Version when set. Zero plan intervals use the defaults.
+func Activate(ctx context.Context, c redfish.Client, p Plan, ph Phase) error {
+	interval, timeout := p.PollInterval, p.PhaseTimeout
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	if timeout <= 0 {
+		timeout = defaultPhaseTimeout
+	}
+	if ph.Wait == nil || *ph.Wait {
+		if err := waitIdle(ctx, c, ph.Targets, interval, timeout); err != nil {
+			return fmt.Errorf("wait for update: %w", err)
+		}
+		if err := taskErr(ctx, c, ph.Task); err != nil {
+			return fmt.Errorf("update: %w", err)
+		}
+	}
+	if ph.Reset != "" && ph.Reset != ResetNone {
+		resetType := ph.ResetType
+		if resetType == "" {
+			resetType = defaultResetType
+		}
+		var err error
+		if ph.Reset == ResetManager {
+			err = c.ResetManager(ctx, resetType)
+		} else {
+			err = c.ResetSystem(ctx, "", resetType)
+		}
+		if err != nil {
+			return fmt.Errorf("%s reset: %w", ph.Reset, err)
+		}
+		if err := sleep(ctx, ph.Settle); err != nil {
+			return err
+		}
+		if err := waitReachable(ctx, c, interval, timeout); err != nil {
+			return fmt.Errorf("wait after reset: %w", err)
+		}
+	}
+	if ph.Version != "" {
+		ok, err := atVersion(ctx, c, ph.Targets, ph.Version)
+		if err != nil {
+			return fmt.Errorf("verify version: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("verify version: targets not at %s after update", ph.Version)
+		}
+	}
+	return nil
+}
+
+// checkDowngrade fails if any target runs a newer version than version.
+// Versions that cannot be compared are not treated as downgrades.
+func checkDowngrade(ctx context.Context, c redfish.Client, targets []string, version string) error {
+	for _, t := range targets {
+		inv, err := c.GetFirmwareInventory(ctx, t)
+		if err != nil {
+			continue
+		}
+		if cmp, err := fwversion.Compare(version, inv.Version); err == nil && cmp < 0 {
+			return fmt.Errorf("refusing to downgrade %s from %s to %s (set allow_downgrade)", t, inv.Version, version)
+		}
+	}
+	return nil
+}
+
+// atVersion reports whether every target reports version.
+func atVersion(ctx context.Context, c redfish.Client, targets []string, version string) (bool, error) {
+	for _, t := range targets {
+		inv, err := c.GetFirmwareInventory(ctx, t)
+		if err != nil {
+			return false, err
+		}
+		if inv.Version != version {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// waitIdle polls until the BMC reports no running update tasks and none of the
+// targets is updating. Query errors are tolerated (the BMC is often busy while
+// flashing) until timeout.
+func waitIdle(ctx context.Context, c redfish.Client, targets []string, interval, timeout time.Duration) error {
+	return poll(ctx, interval, timeout, func() (bool, error) {
+		tasks, err := c.GetActiveUpdateTasks(ctx)
+		if err != nil || len(tasks) > 0 {
+			return false, err
+		}
+		for _, t := range targets {
+			inv, err := c.GetFirmwareInventory(ctx, t)
+			if err != nil {
+				return false, err
+			}
+			if strings.EqualFold(inv.State, "Updating") {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
+
+// taskErr returns the *redfish.TaskFailedError of the task at uri if it ended
+// without completing. Tasks that cannot be listed or found are not an error:
+// many BMCs drop finished tasks.
+func taskErr(ctx context.Context, c redfish.Client, uri string) error {
+	if uri == "" {
+		return nil
+	}
+	tasks, err := c.GetTasks(ctx)
+	if err != nil {
+		return nil
+	}
+	for _, t := range tasks {
+		for _, p := range []string{t.ODataID, t.TaskMonitor} {
+			if p = strings.TrimSuffix(p, "/"); p != "" && strings.HasSuffix(strings.TrimSuffix(uri, "/"), p) {
+				return t.Err()
+			}
+		}
+	}
+	return nil
+}
+
+// waitReachable polls until the BMC answers Redfish requests again.
+func waitReachable(ctx context.Context, c redfish.Client, interval, timeout time.Duration) error {
+	return poll(ctx, interval, timeout, func() (bool, error) {
+		_, err := c.GetUpdateServiceStatus(ctx)
+		return err == nil, err
+	})
+}
+
+// poll calls check every interval until it reports done, ctx ends or timeout
+// passes, in which case the last check error (if any) is included.
+func poll(ctx context.Context, interval, timeout time.Duration, check func() (bool, error)) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		done, err := check()
+		if done {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			if err != nil {
+				return fmt.Errorf("timed out after %s: %w", timeout, err)
+			}
+			return fmt.Errorf("timed out after %s", timeout)
+		}
+		if err := sleep(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package fwplan
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+)
+
+const (
+	bmcTarget  = "/redfish/v1/UpdateService/FirmwareInventory/BMC"
+	biosTarget = "/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS"
+)
+
+// fakeBMC flashes images instantly but keeps one update task running for a
+// single poll after each SimpleUpdate.
+func fakeBMC(versions map[string]string, images map[string]string) (*redfishtest.MockClient, *[]string) {
+	var mu sync.Mutex
+	var steps []string
+	busy := false
+	m := &redfishtest.MockClient{
+		SimpleUpdateFunc: func(_ context.Context, uri string, targets []string, _, _ string, _ bool) (redfish.UpdateResult, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			steps = append(steps, "update "+uri)
+			for _, t := range targets {
+				versions[t] = images[uri]
+			}
+			busy = true
+			return redfish.UpdateResult{}, nil
+		},
+		GetActiveUpdateTasksFunc: func(context.Context) ([]string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if busy {
+				busy = false
+				return []string{"1"}, nil
+			}
+			return nil, nil
+		},
+		GetFirmwareInventoryFunc: func(_ context.Context, t string) (redfish.FirmwareInventory, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			return redfish.FirmwareInventory{Version: versions[t], State: "Enabled"}, nil
+		},
+		ResetManagerFunc: func(_ context.Context, rt string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			steps = append(steps, "reset manager "+rt)
+			return nil
+		},
+		ResetSystemFunc: func(_ context.Context, _, rt string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			steps = append(steps, "reset system "+rt)
+			return nil
+		},
+		GetUpdateServiceStatusFunc: func(context.Context) (redfish.UpdateServiceStatus, error) {
+			return redfish.UpdateServiceStatus{Health: "OK"}, nil
+		},
+	}
+	return m, &steps
+}
+
+func testPlan(t *testing.T) Plan {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "plan.yaml")
+	content := `poll_interval: 1ms
+phase_timeout: 1s
+phases:
+  - name: nc
+    targets: [` + bmcTarget + `]
+    image_uri: http://repo/nc.bin
+    version: nc.1.10.1
+    reset: manager
+  - name: bios
+    targets: [` + biosTarget + `]
+    image_uri: http://repo/bios.bin
+    version: "1.5"
+    reset: system
+    reset_type: ForceRestart
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func noLog(string, ...any) {}
+
+func TestRunOrdersPhases(t *testing.T) {
+	versions := map[string]string{bmcTarget: "nc.1.9.0", biosTarget: "1.4"}
+	m, steps := fakeBMC(versions, map[string]string{"http://repo/nc.bin": "nc.1.10.1", "http://repo/bios.bin": "1.5"})
+
+	results := Run(context.Background(), m, testPlan(t), noLog)
+	for _, r := range results {
+		if r.Status != StatusUpdated {
+			t.Errorf("phase %s: %s %v", r.Phase, r.Status, r.Err)
+		}
+	}
+	want := "update http://repo/nc.bin|reset manager GracefulRestart|update http://repo/bios.bin|reset system ForceRestart"
+	if got := strings.Join(*steps, "|"); got != want {
+		t.Errorf("steps = %s\nwant     %s", got, want)
+	}
+
+	// Rerun: everything is already current
+	*steps = nil
+	for _, r := range Run(context.Background(), m, testPlan(t), noLog) {
+		if r.Status != StatusSkipped {
+			t.Errorf("rerun phase %s: %s", r.Phase, r.Status)
+		}
+	}
+	if len(*steps) != 0 {
+		t.Errorf("rerun made changes: %v", *steps)
+	}
+}
+
+func TestRunStopsAfterFailure(t *testing.T) {
+	versions := map[string]string{bmcTarget: "nc.1.9.0", biosTarget: "1.4"}
+	m, steps := fakeBMC(versions, map[string]string{"http://repo/nc.bin": "nc.1.10.1"})
+	m.ResetManagerFunc = func(context.Context, string) error { return errors.New("reset refused") }
+
+	results := Run(context.Background(), m, testPlan(t), noLog)
+	if results[0].Status != StatusFailed || !strings.Contains(results[0].Err.Error(), "reset refused") {
+		t.Errorf("nc phase = %+v", results[0])
+	}
+	if results[1].Status != StatusNotRun {
+		t.Errorf("bios phase = %+v", results[1])
+	}
+	if len(*steps) != 1 {
+		t.Errorf("unexpected steps %v", *steps)
+	}
+}
+
+func TestRunFailsOnFailedTask(t *testing.T) {
+	versions := map[string]string{bmcTarget: "nc.1.9.0", biosTarget: "1.4"}
+	m, steps := fakeBMC(versions, map[string]string{"http://repo/nc.bin": "nc.1.10.1"})
+	update := m.SimpleUpdateFunc
+	m.SimpleUpdateFunc = func(ctx context.Context, uri string, targets []string, proto, version string, force bool) (redfish.UpdateResult, error) {
+		res, err := update(ctx, uri, targets, proto, version, force)
+		res.TaskURI = "https://bmc/redfish/v1/TaskService/Tasks/7"
+		return res, err
+	}
+	m.GetTasksFunc = func(context.Context) ([]redfish.Task, error) {
+		task := redfish.Task{TaskState: "Exception", Messages: []redfish.Message{{Message: "image signature invalid"}}}
+		task.ODataID = "/redfish/v1/TaskService/Tasks/7"
+		return []redfish.Task{task}, nil
+	}
+
+	results := Run(context.Background(), m, testPlan(t), noLog)
+	var tf *redfish.TaskFailedError
+	if results[0].Status != StatusFailed || !errors.As(results[0].Err, &tf) || tf.State != "Exception" || !strings.Contains(tf.Error(), "image signature invalid") {
+		t.Errorf("nc phase = %+v", results[0])
+	}
+	if len(*steps) != 1 {
+		t.Errorf("reset after a failed task: %v", *steps)
+	}
+}
+
+func TestLoadValidates(t *testing.T) {
+	for _, bad := range []string{
+		"phases: []",
+		"phases:\n  - name: x\n    image_uri: http://a\n",
+		"phases:\n  - targets: [a]\n    image_uri: http://a\n    reset: chassis\n",
+	} {
+		p := filepath.Join(t.TempDir(), "p.yaml")
+		if err := os.WriteFile(p, []byte(bad), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(p); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestRunRefusesDowngrade(t *testing.T) {
+	versions := map[string]string{bmcTarget: "nc.1.11.0", biosTarget: "1.4"}
+	m, steps := fakeBMC(versions, map[string]string{"http://repo/nc.bin": "nc.1.10.1"})
+	p := testPlan(t)
+	results := Run(context.Background(), m, p, noLog)
+	if results[0].Status != StatusFailed || !strings.Contains(results[0].Err.Error(), "downgrade") || len(*steps) != 0 {
+		t.Fatalf("expected refused downgrade, got %+v steps %v", results[0], *steps)
+	}
+
+	p.AllowDowngrade = true
+	p.Phases = p.Phases[:1]
+	m.ResetManagerFunc = func(context.Context, string) error { return nil }
+	if results := Run(context.Background(), m, p, noLog); results[0].Status != StatusUpdated {
+		t.Fatalf("expected downgrade with allow_downgrade, got %+v", results[0])
+	}
+}
@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package fwversion compares vendor firmware version strings such as
+// "nc.1.10.1", "cc.1.9.8" or "2.30".
+package fwversion
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// split breaks a version into its leading component prefix (e.g. "nc", empty for
+// purely numeric versions) and the remaining dot/dash/underscore separated parts.
+func split(v string) (string, []string) {
+	v = strings.TrimSpace(v)
+	if len(v) > 1 && (v[0] == 'v' || v[0] == 'V') && unicode.IsDigit(rune(v[1])) {
+		v = v[1:]
+	}
+	parts := strings.FieldsFunc(v, func(r rune) bool {
+		return r == '.' || r == '-' || r == '_' || r == ' '
+	})
+	prefix := ""
+	if len(parts) > 0 && !startsWithDigit(parts[0]) && len(parts) > 1 {
+		prefix, parts = strings.ToLower(parts[0]), parts[1:]
+	}
+	return prefix, parts
+}
+
+func startsWithDigit(s string) bool {
+	return s != "" && unicode.IsDigit(rune(s[0]))
+}
+
+// Compare returns -1, 0 or 1 as a is older than, equal to or newer than b.
+// Numeric parts compare numerically (so 1.10 > 1.9) and other parts lexically; a
+// version with extra trailing parts is newer. Versions for different components
+// (e.g. "nc.1.2" vs "cc.1.3") cannot be compared and return an error.
+func Compare(a, b string) (int, error) {
+	pa, xa := split(a)
+	pb, xb := split(b)
+	if pa != pb {
+		return 0, fmt.Errorf("cannot compare %q with %q: different components", a, b)
+	}
+	if len(xa) == 0 || len(xb) == 0 {
+		return 0, fmt.Errorf("cannot compare %q with %q: empty version", a, b)
+	}
+	for i := 0; i < len(xa) && i < len(xb); i++ {
+		if c := comparePart(xa[i], xb[i]); c != 0 {
+			return c, nil
+		}
+	}
+	switch {
+	case len(xa) < len(xb):
+		return -1, nil
+	case len(xa) > len(xb):
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func comparePart(a, b string) int {
+	na, ea := strconv.Atoi(a)
+	nb, eb := strconv.Atoi(b)
+	switch {
+	case ea == nil && eb == nil:
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+		return 0
+	case ea == nil:
+		return 1 // numeric releases sort after pre-release tags like "rc1"
+	case eb == nil:
+		return -1
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// AllowList is a set of firmware versions approved for a site.
+type AllowList map[string]bool
+
+// LoadAllowList reads one version per line; blank lines and '#' comments are
+// ignored.
+func LoadAllowList(path string) (AllowList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // nolint:errcheck
+	out := AllowList{}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			out[line] = true
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%s: allow-list is empty", path)
+	}
+	return out, nil
+}
+
+// Allows reports whether version is on the list.
+func (l AllowList) Allows(version string) bool {
+	return l[strings.TrimSpace(version)]
+}
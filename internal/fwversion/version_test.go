@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package fwversion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"nc.1.10.1", "nc.1.9.8", 1},
+		{"nc.1.9.8", "nc.1.10.1", -1},
+		{"nc.1.10.1", "NC.1.10.1", 0},
+		{"1.2", "1.2.1", -1},
+		{"2.30", "2.4", 1},
+		{"1.5.0-rc1", "1.5.0-rc2", -1},
+		{"1.5.0-rc1", "1.5.0-1", -1},
+		{"v1.2", "v1.10", -1},
+		{"v1.2", "2.0", -1},
+	}
+	for _, tt := range tests {
+		got, err := Compare(tt.a, tt.b)
+		if err != nil {
+			t.Errorf("Compare(%q, %q): %v", tt.a, tt.b, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+	for _, bad := range [][2]string{{"nc.1.2", "cc.1.3"}, {"", "1.0"}} {
+		if _, err := Compare(bad[0], bad[1]); err == nil {
+			t.Errorf("Compare(%q, %q): expected error", bad[0], bad[1])
+		}
+	}
+}
+
+func TestAllowList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "allowed.txt")
+	content := "# approved 2025-10\nnc.1.10.1\n  nc.1.9.8  # previous\n\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	l, err := LoadAllowList(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !l.Allows("nc.1.10.1") || !l.Allows("nc.1.9.8") || l.Allows("nc.1.11.0") {
+		t.Errorf("unexpected allow-list %v", l)
+	}
+}
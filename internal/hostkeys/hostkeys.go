@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package hostkeys collects the SSH host keys of nodes, like ssh-keyscan, and
+// formats them as known_hosts lines.
+package hostkeys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Types maps the short key type names accepted on the command line to the host
+// key algorithm offered to the server.
+var Types = map[string]string{
+	"ed25519": ssh.KeyAlgoED25519,
+	"ecdsa":   ssh.KeyAlgoECDSA256,
+	"rsa":     ssh.KeyAlgoRSASHA512,
+}
+
+// Algorithms returns the host key algorithms for the short type names in names.
+func Algorithms(names []string) ([]string, error) {
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		alg, ok := Types[strings.ToLower(strings.TrimSpace(n))]
+		if !ok {
+			return nil, fmt.Errorf("unknown key type %q (want ed25519, ecdsa or rsa)", n)
+		}
+		out = append(out, alg)
+	}
+	return out, nil
+}
+
+// Dialer opens the TCP connection to a node; a zero net.Dialer's DialContext fits.
+type Dialer func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// errGotKey ends the handshake once the host key has been seen.
+var errGotKey = errors.New("host key received")
+
+// Scan returns the host key addr presents for each algorithm in algs, one
+// handshake per algorithm. An algorithm the server does not offer is skipped;
+// an error is returned only when no key was collected.
+func Scan(ctx context.Context, dial Dialer, addr string, algs []string) ([]ssh.PublicKey, error) {
+	if dial == nil {
+		var d net.Dialer
+		dial = d.DialContext
+	}
+	var keys []ssh.PublicKey
+	var errs []error
+	for _, alg := range algs {
+		key, err := scanOne(ctx, dial, addr, alg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", alg, err))
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, errors.Join(errs...)
+	}
+	return keys, nil
+}
+
+func scanOne(ctx context.Context, dial Dialer, addr, alg string) (ssh.PublicKey, error) {
+	conn, err := dial(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close() //nolint:errcheck
+	if dl, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(dl)
+	}
+	var key ssh.PublicKey
+	cfg := &ssh.ClientConfig{
+		User:              "hostkeys",
+		HostKeyAlgorithms: []string{alg},
+		HostKeyCallback: func(_ string, _ net.Addr, k ssh.PublicKey) error {
+			key = k
+			return errGotKey
+		},
+	}
+	_, _, _, err = ssh.NewClientConn(conn, addr, cfg)
+	if key != nil {
+		return key, nil
+	}
+	if err == nil {
+		err = errors.New("no host key presented")
+	}
+	return nil, err
+}
+
+// Line formats key as a known_hosts line for names (host names or IPs, with
+// ":port" when not 22). With hash, each name is hashed as ssh-keygen -H does and
+// gets a line of its own.
+func Line(names []string, key ssh.PublicKey, hash bool) string {
+	if !hash {
+		return knownhosts.Line(names, key)
+	}
+	lines := make([]string, len(names))
+	for i, n := range names {
+		lines[i] = knownhosts.Line([]string{knownhosts.HashHostname(knownhosts.Normalize(n))}, key)
+	}
+	return strings.Join(lines, "\n")
+}
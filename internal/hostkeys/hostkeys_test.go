@@ -0,0 +1,119 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package hostkeys
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// serve runs an SSH server with the given host keys that rejects every login.
+func serve(t *testing.T, keys ...any) string {
+	t.Helper()
+	cfg := &ssh.ServerConfig{PasswordCallback: func(ssh.ConnMetadata, []byte) (*ssh.Permissions, error) {
+		return nil, ssh.ErrNoAuth
+	}}
+	for _, k := range keys {
+		s, err := ssh.NewSignerFromKey(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.AddHostKey(s)
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() }) //nolint:errcheck
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, _, _, _ = ssh.NewServerConn(conn, cfg)
+				conn.Close() //nolint:errcheck
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestScan(t *testing.T) {
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	addr := serve(t, edKey, ecKey)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	algs, err := Algorithms([]string{"ed25519", "ecdsa", "rsa"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := Scan(ctx, nil, addr, algs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || keys[0].Type() != ssh.KeyAlgoED25519 || keys[1].Type() != ssh.KeyAlgoECDSA256 {
+		t.Fatalf("keys = %v", keys)
+	}
+	edPub, _ := ssh.NewPublicKey(edKey.Public())
+	if !bytes.Equal(keys[0].Marshal(), edPub.Marshal()) {
+		t.Error("ed25519 key differs from the server's")
+	}
+
+	if _, err := Scan(ctx, nil, addr, []string{ssh.KeyAlgoRSASHA512}); err == nil {
+		t.Error("rsa only: want error")
+	}
+	if _, err := Algorithms([]string{"dsa"}); err == nil {
+		t.Error("dsa: want error")
+	}
+}
+
+func TestLine(t *testing.T) {
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	key, _ := ssh.NewPublicKey(edKey.Public())
+	names := []string{"nid000001", "x1000c0s0b0n0", "10.42.0.1:2222"}
+
+	line := Line(names, key, false)
+	if !strings.HasPrefix(line, "nid000001,x1000c0s0b0n0,[10.42.0.1]:2222 ssh-ed25519 ") {
+		t.Errorf("line = %q", line)
+	}
+
+	hashed := strings.Split(Line(names, key, true), "\n")
+	if len(hashed) != 3 {
+		t.Fatalf("hashed lines = %q", hashed)
+	}
+	for _, l := range hashed {
+		if !strings.HasPrefix(l, "|1|") {
+			t.Errorf("not hashed: %q", l)
+		}
+	}
+	// The hashed file must verify the plain names.
+	f := t.TempDir() + "/known_hosts"
+	if err := os.WriteFile(f, []byte(strings.Join(hashed, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cb, err := knownhosts.New(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cb("x1000c0s0b0n0:22", &net.TCPAddr{IP: net.ParseIP("10.42.0.1"), Port: 22}, key); err != nil {
+		t.Errorf("hashed entry does not match: %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package imageserver serves firmware images to BMCs over HTTP behind one-time
+// tokens: every update gets its own URL, which only answers the BMC it was
+// granted to and stops working once the image was downloaded or the token
+// expired, so nothing else on the management network can fetch the images.
+package imageserver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxPartial bounds the HEAD and Range requests one grant answers: enough for
+// a BMC to probe the image and resume an interrupted download a few times.
+const maxPartial = 8
+
+// grant is the right of one BMC to download one file once.
+type grant struct {
+	file    string
+	host    string
+	addrs   []netip.Addr // addresses the BMC may download from
+	expires time.Time
+	used    bool // a whole-file GET was accepted
+	partial int  // HEAD and Range requests accepted
+}
+
+// from reports whether a request from addr is the BMC's.
+func (g *grant) from(addr netip.Addr) bool {
+	for _, a := range g.addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// Server serves the files of a directory to holders of a grant.
+type Server struct {
+	root *os.Root
+	ttl  time.Duration
+	now  func() time.Time
+	// lookup resolves a BMC host name to the addresses it downloads from
+	lookup func(host string) ([]netip.Addr, error)
+
+	mu     sync.Mutex
+	grants map[string]*grant
+}
+
+// New returns a server for the files under dir whose tokens expire after ttl.
+func New(dir string, ttl time.Duration) (*Server, error) {
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{root: root, ttl: ttl, now: time.Now, lookup: lookupHost, grants: map[string]*grant{}}, nil
+}
+
+// lookupHost resolves host, an address or a DNS name.
+func lookupHost(host string) ([]netip.Addr, error) {
+	if a, err := netip.ParseAddr(host); err == nil {
+		return []netip.Addr{a.Unmap()}, nil
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(context.Background(), "ip", host)
+	for i := range addrs {
+		addrs[i] = addrs[i].Unmap()
+	}
+	return addrs, err
+}
+
+// hostOnly strips the scheme, port and path a BMC host may be given with.
+func hostOnly(host string) string {
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return strings.Trim(host, "[]")
+}
+
+// Close releases the image directory.
+func (s *Server) Close() error {
+	return s.root.Close()
+}
+
+// Digest returns the hex SHA-256 of file, a path relative to the image
+// directory.
+func (s *Server) Digest(file string) (string, error) {
+	f, err := s.root.Open(strings.TrimPrefix(path.Clean("/"+file), "/"))
+	if err != nil {
+		return "", fmt.Errorf("image %s: %w", file, err)
+	}
+	defer f.Close() //nolint:errcheck
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("image %s: %w", file, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Grant lets host download file, a path relative to the image directory, once.
+// Only requests from the addresses host resolves to are answered. It returns
+// the URL path to give the BMC: /<token>/<file>.
+func (s *Server) Grant(file, host string) (string, error) {
+	file = strings.TrimPrefix(path.Clean("/"+file), "/")
+	fi, err := s.root.Stat(file)
+	if err != nil {
+		return "", fmt.Errorf("image %s: %w", file, err)
+	}
+	if !fi.Mode().IsRegular() {
+		return "", fmt.Errorf("image %s: not a regular file", file)
+	}
+	addrs, err := s.lookup(hostOnly(host))
+	if err != nil || len(addrs) == 0 {
+		return "", fmt.Errorf("image %s: cannot resolve %s to restrict the download to it: %v", file, host, err)
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.grants[token] = &grant{file: file, host: host, addrs: addrs, expires: s.now().Add(s.ttl)}
+	return (&url.URL{Path: "/" + token + "/" + file}).EscapedPath(), nil
+}
+
+// Revoke ends the grant of a path returned by Grant, e.g. when the update it
+// was for was not posted.
+func (s *Server) Revoke(urlPath string) {
+	token, _, _ := strings.Cut(strings.TrimPrefix(urlPath, "/"), "/")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.grants, token)
+}
+
+// Pending returns the hosts holding a grant that was neither used nor expired.
+func (s *Server) Pending() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []string
+	now := s.now()
+	for _, g := range s.grants {
+		if !g.used && now.Before(g.expires) {
+			out = append(out, g.host)
+		}
+	}
+	return out
+}
+
+// Wait polls every interval until no grant is pending or ctx ends.
+func (s *Server) Wait(ctx context.Context, interval time.Duration) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for len(s.Pending()) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	return nil
+}
+
+// ServeHTTP serves GET and HEAD requests for /<token>/<file> from the BMC the
+// token was granted to. The token is taken when the request is accepted: a
+// whole-file GET uses it up, and HEAD and Range requests (which some BMCs send
+// before a GET or to resume one) are answered up to maxPartial times. Other
+// clients and unknown, used or expired tokens get 403 Forbidden.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	token, file, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	src, err := netip.ParseAddrPort(r.RemoteAddr)
+	whole := r.Method == http.MethodGet && r.Header.Get("Range") == ""
+	s.mu.Lock()
+	g := s.grants[token]
+	ok := err == nil && g != nil && !g.used && g.file == file && s.now().Before(g.expires) && g.from(src.Addr().Unmap())
+	switch {
+	case !ok:
+	case whole:
+		g.used = true
+	case g.partial < maxPartial:
+		g.partial++
+	default:
+		ok = false
+	}
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	f, err := s.root.Open(file)
+	if err != nil {
+		http.Error(w, "image unavailable", http.StatusInternalServerError)
+		return
+	}
+	defer f.Close() //nolint:errcheck
+	fi, err := f.Stat()
+	if err != nil {
+		http.Error(w, "image unavailable", http.StatusInternalServerError)
+		return
+	}
+	http.ServeContent(w, r, fi.Name(), fi.ModTime(), f)
+}
@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package imageserver
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "nc"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "nc", "nc 1.10.bin"), []byte("firmware"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "bios.bin"), []byte("bios"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close() //nolint:errcheck
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	get := func(method, path string, hdr ...string) (int, string) {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+path, nil)
+		if len(hdr) == 2 {
+			req.Header.Set(hdr[0], hdr[1])
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close() //nolint:errcheck
+		b, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+
+	p, err := s.Grant("nc/nc 1.10.bin", "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Pending(); len(got) != 1 || got[0] != "127.0.0.1" {
+		t.Errorf("pending = %v", got)
+	}
+	// HEAD and Range requests leave the token valid for the whole file
+	if code, _ := get(http.MethodHead, p); code != http.StatusOK {
+		t.Errorf("HEAD: %d", code)
+	}
+	if code, body := get(http.MethodGet, p, "Range", "bytes=0-3"); code != http.StatusPartialContent || body != "firm" {
+		t.Errorf("range: %d %q", code, body)
+	}
+	// Another file with the same token is refused
+	if code, _ := get(http.MethodGet, p[:33]+"/bios.bin"); code != http.StatusForbidden {
+		t.Errorf("other file: %d", code)
+	}
+	if code, body := get(http.MethodGet, p); code != http.StatusOK || body != "firmware" {
+		t.Errorf("GET: %d %q", code, body)
+	}
+	if code, _ := get(http.MethodGet, p); code != http.StatusForbidden {
+		t.Errorf("second GET: %d", code)
+	}
+	if len(s.Pending()) != 0 {
+		t.Errorf("pending after download = %v", s.Pending())
+	}
+	if err := s.Wait(context.Background(), time.Millisecond); err != nil {
+		t.Error(err)
+	}
+
+	// Expired and revoked tokens
+	p, _ = s.Grant("bios.bin", "127.0.0.1")
+	now = now.Add(2 * time.Hour)
+	if code, _ := get(http.MethodGet, p); code != http.StatusForbidden {
+		t.Errorf("expired: %d", code)
+	}
+	p, _ = s.Grant("bios.bin", "127.0.0.1")
+	s.Revoke(p)
+	if code, _ := get(http.MethodGet, p); code != http.StatusForbidden || len(s.Pending()) != 0 {
+		t.Errorf("revoked: %d", code)
+	}
+
+	if _, err := s.Grant("../etc/passwd", "127.0.0.1"); err == nil {
+		t.Error("granted a file outside the directory")
+	}
+	if _, err := s.Grant("nc", "127.0.0.1"); err == nil {
+		t.Error("granted a directory")
+	}
+}
+
+// TestServerReuse checks that a token cannot be used to download the image
+// more than once: not by another client, not by HEAD or Range requests beyond
+// the few a resume needs, and not by concurrent requests.
+func TestServerReuse(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "bios.bin"), []byte("bios image"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close() //nolint:errcheck
+	s.lookup = func(host string) ([]netip.Addr, error) {
+		if host == "x1000c0s0b0" {
+			return []netip.Addr{netip.MustParseAddr("127.0.0.1")}, nil
+		}
+		return lookupHost(host)
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	get := func(method, path, rng string) int {
+		req, _ := http.NewRequest(method, ts.URL+path, nil)
+		if rng != "" {
+			req.Header.Set("Range", rng)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Error(err)
+			return 0
+		}
+		defer resp.Body.Close()        //nolint:errcheck
+		io.Copy(io.Discard, resp.Body) //nolint:errcheck
+		return resp.StatusCode
+	}
+
+	// Another client
+	p, err := s.Grant("bios.bin", "https://10.254.1.1:443")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := get(http.MethodGet, p, ""); code != http.StatusForbidden {
+		t.Errorf("GET from another host: %d", code)
+	}
+	if _, err := s.Grant("bios.bin", "bmc.invalid"); err == nil {
+		t.Error("granted to a host that does not resolve")
+	}
+
+	// HEAD and whole-file Range requests are bounded
+	p, _ = s.Grant("bios.bin", "x1000c0s0b0")
+	for i := range maxPartial {
+		method, rng := http.MethodHead, ""
+		if i%2 == 1 {
+			method, rng = http.MethodGet, "bytes=0-"
+		}
+		if code := get(method, p, rng); code != http.StatusOK && code != http.StatusPartialContent {
+			t.Fatalf("request %d (%s %s): %d", i, method, rng, code)
+		}
+	}
+	if code := get(http.MethodHead, p, ""); code != http.StatusForbidden {
+		t.Errorf("HEAD beyond the limit: %d", code)
+	}
+	if code := get(http.MethodGet, p, "bytes=0-"); code != http.StatusForbidden {
+		t.Errorf("Range beyond the limit: %d", code)
+	}
+	if code := get(http.MethodGet, p, ""); code != http.StatusOK {
+		t.Errorf("GET after the partial requests: %d", code)
+	}
+
+	// Concurrent GETs: one gets the image
+	p, _ = s.Grant("bios.bin", "127.0.0.1")
+	var wg sync.WaitGroup
+	var ok atomic.Int32
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if get(http.MethodGet, p, "") == http.StatusOK {
+				ok.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if ok.Load() != 1 {
+		t.Errorf("%d concurrent GETs succeeded, want 1", ok.Load())
+	}
+	if code := get(http.MethodGet, p, "bytes=0-"); code != http.StatusForbidden {
+		t.Errorf("Range after the download: %d", code)
+	}
+}
@@ -7,23 +7,14 @@ package initbmcs
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
-	"bootstrap/internal/inventory"
-	"bootstrap/internal/netalloc"
+	"bootstrap/pkg/inventory"
+	"bootstrap/pkg/netalloc"
 )
 
 func getBmcID(n int) int { return (n + 1) / 2 } //nolint:unused
-func getSlot(n int) int  { return ((n - 1) / 4) % 8 }
-func getBlade(n int) int { return ((n - 1) / 2) % 2 }
-
-func getNCXname(chassis string, n int) string {
-	return fmt.Sprintf("%ss%db%d", chassis, getSlot(n), getBlade(n))
-}
-
-func getNCMAC(macStart string, n int) string {
-	return fmt.Sprintf("%s:%d%d:%d0", macStart, 3, getSlot(n), getBlade(n))
-}
 
 // ParseChassisSpec parses a chassis specification string into a map of chassis xnames to MAC prefixes.
 func ParseChassisSpec(spec string) map[string]string {
@@ -47,10 +38,20 @@ func ParseChassisSpec(spec string) map[string]string {
 	return out
 }
 
-// Generate creates the BMC entries for an initial inventory.
+// Generate creates the BMC entries for an initial inventory using the default geometry.
 // bmcSubnet should be in CIDR notation, e.g. "192.168.100.0/24"
 // startIP is an optional IP address to start allocation from (skips all IPs before it)
 func Generate(chassis map[string]string, nodesPerChassis, nodesPerBMC, startNID int, bmcSubnet, startIP string) ([]inventory.Entry, error) {
+	geo := builtinGeometries[DefaultGeometry]
+	geo.NodesPerBMC = nodesPerBMC
+	return GenerateGeometry(chassis, geo, nodesPerChassis, startNID, bmcSubnet, startIP)
+}
+
+// GenerateGeometry creates the BMC entries for an initial inventory laid out per geo.
+func GenerateGeometry(chassis map[string]string, geo Geometry, nodesPerChassis, startNID int, bmcSubnet, startIP string) ([]inventory.Entry, error) {
+	if err := geo.Validate(); err != nil {
+		return nil, err
+	}
 	alloc, err := netalloc.NewAllocator(bmcSubnet)
 	if err != nil {
 		return nil, fmt.Errorf("bmc subnet init: %w", err)
@@ -63,17 +64,39 @@ func Generate(chassis map[string]string, nodesPerChassis, nodesPerBMC, startNID
 		}
 	}
 
+	// Walk chassis in sorted xname order so output and IP assignment are stable
+	// between runs, and give each chassis a fixed-size block of addresses so that
+	// adding a chassis (or populating more of one) does not shift the others.
+	names := make([]string, 0, len(chassis))
+	for c := range chassis {
+		names = append(names, c)
+	}
+	sort.Strings(names)
+	perChassis := (nodesPerChassis + geo.NodesPerBMC - 1) / geo.NodesPerBMC
+	blockSize := max(perChassis, geo.Slots*geo.BladesPerSlot)
+
 	var bmcs []inventory.Entry
 	nid := startNID
-	for c, macPref := range chassis {
-		for i := nid; i < nid+nodesPerChassis; i += nodesPerBMC {
-			x := getNCXname(c, i)
+	for _, c := range names {
+		macPref := chassis[c]
+		if err := geo.checkPrefix(macPref); err != nil {
+			return nil, fmt.Errorf("chassis %s: %w", c, err)
+		}
+		block := make([]string, 0, blockSize)
+		for range blockSize {
 			ip, err := alloc.Next()
 			if err != nil {
-				return nil, fmt.Errorf("allocate IP for %s: %w", x, err)
+				if len(block) >= perChassis {
+					break
+				}
+				return nil, fmt.Errorf("allocate IP block for %s: %w", c, err)
 			}
-			mac := strings.ToLower(getNCMAC(macPref, i))
-			bmcs = append(bmcs, inventory.Entry{Xname: x, MAC: mac, IP: ip})
+			block = append(block, ip)
+		}
+		for j, i := 0, nid; i < nid+nodesPerChassis; j, i = j+1, i+geo.NodesPerBMC {
+			x := geo.bmcXname(c, i)
+			mac := strings.ToLower(geo.bmcMAC(macPref, i))
+			bmcs = append(bmcs, inventory.Entry{Xname: x, MAC: mac, IP: block[j]})
 		}
 		nid = nid + nodesPerChassis
 	}
@@ -5,10 +5,13 @@
 package initbmcs
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
-	"bootstrap/internal/inventory"
+	"bootstrap/pkg/inventory"
 )
 
 func TestParseChassisSpec(t *testing.T) {
@@ -53,3 +56,213 @@ func TestGenerateWithStartIP(t *testing.T) {
 		t.Fatalf("Generate result mismatch:\n got: %#v\nwant: %#v", bmcs, want)
 	}
 }
+
+func TestLoadGeometryBuiltin(t *testing.T) {
+	g, err := LoadGeometry("")
+	if err != nil {
+		t.Fatalf("LoadGeometry failed: %v", err)
+	}
+	if g.Name != DefaultGeometry || g.NodesPerChassis() != 32 {
+		t.Fatalf("unexpected default geometry: %#v", g)
+	}
+	if _, err := LoadGeometry("does-not-exist"); err == nil {
+		t.Fatal("expected error for unknown geometry")
+	}
+}
+
+func TestGenerateCustomGeometry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "geo.yaml")
+	content := "name: test\nslots: 2\nblades_per_slot: 1\nnodes_per_bmc: 4\nmac_format: \"{prefix}:{slot}{blade}:00\"\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	geo, err := LoadGeometry(path)
+	if err != nil {
+		t.Fatalf("LoadGeometry failed: %v", err)
+	}
+	bmcs, err := GenerateGeometry(map[string]string{"x1000c0": "02:00:00:00"}, geo, geo.NodesPerChassis(), 1, "10.0.0.0/24", "")
+	if err != nil {
+		t.Fatalf("GenerateGeometry failed: %v", err)
+	}
+
+	want := []inventory.Entry{
+		{Xname: "x1000c0s0b0", MAC: "02:00:00:00:00:00", IP: "10.0.0.1"},
+		{Xname: "x1000c0s1b0", MAC: "02:00:00:00:10:00", IP: "10.0.0.2"},
+	}
+	if !reflect.DeepEqual(bmcs, want) {
+		t.Fatalf("GenerateGeometry result mismatch:\n got: %#v\nwant: %#v", bmcs, want)
+	}
+}
+
+func TestBuiltinGeometries(t *testing.T) {
+	cases := []struct {
+		name  string
+		nodes int
+		bmcs  int
+		last  inventory.Entry // last BMC of a full chassis
+	}{
+		{"ex4000", 32, 16, inventory.Entry{Xname: "x1000c0s7b1", MAC: "02:00:00:00:37:10"}},
+		{"ex3000", 16, 16, inventory.Entry{Xname: "x1000c0s7b1", MAC: "02:00:00:00:37:10"}},
+		{"ex2500", 16, 8, inventory.Entry{Xname: "x1000c0s3b1", MAC: "02:00:00:00:33:10"}},
+	}
+	for _, c := range cases {
+		geo, err := LoadGeometry(c.name)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if err := geo.Validate(); err != nil {
+			t.Errorf("%s: %v", c.name, err)
+		}
+		if geo.NodesPerChassis() != c.nodes {
+			t.Errorf("%s: %d nodes per chassis, want %d", c.name, geo.NodesPerChassis(), c.nodes)
+		}
+		bmcs, err := GenerateGeometry(map[string]string{"x1000c0": "02:00:00:00"}, geo, geo.NodesPerChassis(), 1, "10.0.0.0/24", "")
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if len(bmcs) != c.bmcs {
+			t.Fatalf("%s: %d BMCs, want %d", c.name, len(bmcs), c.bmcs)
+		}
+		if last := bmcs[len(bmcs)-1]; last.Xname != c.last.Xname || last.MAC != c.last.MAC {
+			t.Errorf("%s: last BMC %s %s, want %s %s", c.name, last.Xname, last.MAC, c.last.Xname, c.last.MAC)
+		}
+	}
+}
+
+func TestLoadGeometryRejectsInvalidMAC(t *testing.T) {
+	// Slot 10 would give the octet 310
+	path := filepath.Join(t.TempDir(), "geo.yaml")
+	if err := os.WriteFile(path, []byte("name: wide\nslots: 12\nblades_per_slot: 2\nnodes_per_bmc: 2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadGeometry(path); err == nil || !strings.Contains(err.Error(), "slot 10") {
+		t.Fatalf("LoadGeometry = %v, want an invalid MAC error", err)
+	}
+}
+
+func TestGeometryPrefixOctets(t *testing.T) {
+	// A 3-octet prefix needs a format filling the other 3 octets
+	dir := t.TempDir()
+	short := filepath.Join(dir, "short.yaml")
+	if err := os.WriteFile(short, []byte("name: short\nslots: 2\nblades_per_slot: 1\nnodes_per_bmc: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadGeometry(short); err != nil {
+		t.Fatalf("4-octet default: %v", err)
+	}
+	three := filepath.Join(dir, "three.yaml")
+	if err := os.WriteFile(three, []byte("name: three\nslots: 2\nblades_per_slot: 1\nnodes_per_bmc: 1\nprefix_octets: 3\nmac_format: \"{prefix}:3{slot}:{blade}0\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadGeometry(three); err == nil {
+		t.Fatal("5-octet MAC accepted")
+	}
+	if err := os.WriteFile(three, []byte("name: three\nslots: 2\nblades_per_slot: 1\nnodes_per_bmc: 1\nprefix_octets: 3\nmac_format: \"{prefix}:01:3{slot}:{blade}0\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	geo, err := LoadGeometry(three)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bmcs, err := GenerateGeometry(map[string]string{"x1000c1": "02:23:28"}, geo, 2, 1, "10.0.0.0/24", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bmcs[1].MAC != "02:23:28:01:31:00" {
+		t.Errorf("MAC = %s", bmcs[1].MAC)
+	}
+	// The prefixes given must have the length the format expects
+	if _, err := GenerateGeometry(map[string]string{"x1000c1": "02:23:28:01"}, geo, 2, 1, "10.0.0.0/24", ""); err == nil {
+		t.Error("4-octet prefix accepted for a 3-octet geometry")
+	}
+	if _, err := Generate(map[string]string{"x1000c1": "02:23:28"}, 4, 2, 1, "10.0.0.0/24", ""); err == nil {
+		t.Error("3-octet prefix accepted for the default geometry")
+	}
+	macs := []string{"02:23:28:01:31:00", "02:23:28:01:01:31:00"}
+	learned, err := LearnChassisPrefixes(macs, geo, "x1000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(learned, map[string]string{"x1000c40": "02:23:28"}) {
+		t.Errorf("learned = %v", learned)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	existing := []inventory.Entry{
+		{Xname: "x9000c1s0b0", MAC: "02:23:28:01:30:00", IP: "192.168.100.50"},
+		{Xname: "x9000c1s0b1", MAC: "02:23:28:01:30:10", IP: "192.168.100.2"},
+	}
+	generated := []inventory.Entry{
+		{Xname: "x9000c1s0b0", MAC: "02:23:28:01:30:00", IP: "192.168.100.1"},
+		{Xname: "x9000c1s0b1", MAC: "02:23:28:01:30:10", IP: "192.168.100.2"},
+		{Xname: "x9000c3s0b0", MAC: "02:23:28:03:30:00", IP: "192.168.100.2"},
+		{Xname: "x9000c3s0b1", MAC: "02:23:28:03:30:10", IP: "192.168.100.4"},
+	}
+	got, err := Merge(existing, generated, "192.168.100.0/24", "")
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	want := []inventory.Entry{
+		existing[0],
+		existing[1],
+		{Xname: "x9000c3s0b0", MAC: "02:23:28:03:30:00", IP: "192.168.100.1"},
+		{Xname: "x9000c3s0b1", MAC: "02:23:28:03:30:10", IP: "192.168.100.4"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Merge result mismatch:\n got: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestMergeWithStartIP(t *testing.T) {
+	existing := []inventory.Entry{{Xname: "x9000c1s0b0", MAC: "02:23:28:01:30:00", IP: "192.168.100.10"}}
+	generated := []inventory.Entry{{Xname: "x9000c3s0b0", MAC: "02:23:28:03:30:00", IP: "192.168.100.10"}}
+	got, err := Merge(existing, generated, "192.168.100.0/24", "192.168.100.10")
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	// The colliding entry moves past --start-ip, not to the start of the subnet
+	if ip := got[1].IP; ip != "192.168.100.11" {
+		t.Fatalf("re-allocated IP = %s, want 192.168.100.11", ip)
+	}
+}
+
+func TestGenerateDeterministicAcrossChassis(t *testing.T) {
+	chassis := map[string]string{
+		"x9000c3": "02:23:28:03",
+		"x9000c1": "02:23:28:01",
+	}
+	first, err := Generate(chassis, 4, 2, 1, "192.168.100.0/24", "")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	for range 10 {
+		again, err := Generate(chassis, 4, 2, 1, "192.168.100.0/24", "")
+		if err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+		if !reflect.DeepEqual(first, again) {
+			t.Fatalf("Generate is not deterministic:\n got: %#v\nwant: %#v", again, first)
+		}
+	}
+
+	want := []inventory.Entry{
+		{Xname: "x9000c1s0b0", MAC: "02:23:28:01:30:00", IP: "192.168.100.1"},
+		{Xname: "x9000c1s0b1", MAC: "02:23:28:01:30:10", IP: "192.168.100.2"},
+		{Xname: "x9000c3s1b0", MAC: "02:23:28:03:31:00", IP: "192.168.100.17"},
+		{Xname: "x9000c3s1b1", MAC: "02:23:28:03:31:10", IP: "192.168.100.18"},
+	}
+	if !reflect.DeepEqual(first, want) {
+		t.Fatalf("Generate result mismatch:\n got: %#v\nwant: %#v", first, want)
+	}
+
+	// Adding a chassis that sorts last must not move existing addresses
+	chassis["x9000c5"] = "02:23:28:05"
+	grown, err := Generate(chassis, 4, 2, 1, "192.168.100.0/24", "")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if !reflect.DeepEqual(grown[:len(first)], first) {
+		t.Fatalf("adding a chassis shifted addresses:\n got: %#v\nwant: %#v", grown[:len(first)], first)
+	}
+}
@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package initbmcs
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultGeometry is the profile used when no --geometry is given. It matches the
+// historical hardcoded layout of init-bmcs.
+const DefaultGeometry = "ex4000"
+
+// Geometry describes how node IDs map onto slots, blades and BMC MACs in a chassis.
+type Geometry struct {
+	Name string `yaml:"name"`
+	// Slots is the number of blade slots per chassis.
+	Slots int `yaml:"slots"`
+	// BladesPerSlot is the number of node cards (one BMC each) per slot.
+	BladesPerSlot int `yaml:"blades_per_slot"`
+	// NodesPerBMC is the number of nodes managed by each BMC.
+	NodesPerBMC int `yaml:"nodes_per_bmc"`
+	// MACFormat builds the BMC MAC from the chassis prefix. Supported placeholders:
+	// {prefix}, {slot}, {blade}. E.g. "{prefix}:3{slot}:{blade}0".
+	MACFormat string `yaml:"mac_format"`
+	// PrefixOctets is the number of octets of the chassis MAC prefix {prefix}
+	// stands for (4 when unset), e.g. 02:23:28:01.
+	PrefixOctets int `yaml:"prefix_octets"`
+}
+
+var builtinGeometries = map[string]Geometry{
+	// EX4000: liquid-cooled cabinet; 8 compute slots per chassis, 2 node cards
+	// per blade and 2 nodes per node card (32 nodes).
+	"ex4000": {Name: "ex4000", Slots: 8, BladesPerSlot: 2, NodesPerBMC: 2, MACFormat: "{prefix}:3{slot}:{blade}0"},
+	// EX3000: accelerator cabinet; 8 compute slots per chassis, 2 node cards
+	// per blade and 1 node per node card (16 nodes).
+	"ex3000": {Name: "ex3000", Slots: 8, BladesPerSlot: 2, NodesPerBMC: 1, MACFormat: "{prefix}:3{slot}:{blade}0"},
+	// EX2500: compact cabinet; 4 compute slots per chassis, 2 node cards per
+	// blade and 2 nodes per node card (16 nodes).
+	"ex2500": {Name: "ex2500", Slots: 4, BladesPerSlot: 2, NodesPerBMC: 2, MACFormat: "{prefix}:3{slot}:{blade}0"},
+}
+
+// GeometryNames returns the names of the built-in geometry profiles, sorted.
+func GeometryNames() []string {
+	names := make([]string, 0, len(builtinGeometries))
+	for n := range builtinGeometries {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadGeometry returns a built-in geometry by name, or loads a custom geometry
+// from a YAML file when spec is a path to an existing file.
+func LoadGeometry(spec string) (Geometry, error) {
+	if strings.TrimSpace(spec) == "" {
+		spec = DefaultGeometry
+	}
+	if g, ok := builtinGeometries[strings.ToLower(spec)]; ok {
+		return g, nil
+	}
+	raw, err := os.ReadFile(spec)
+	if err != nil {
+		return Geometry{}, fmt.Errorf("unknown geometry %q (use one of %s or a YAML file): %w", spec, strings.Join(GeometryNames(), "|"), err)
+	}
+	var g Geometry
+	if err := yaml.Unmarshal(raw, &g); err != nil {
+		return Geometry{}, fmt.Errorf("parse geometry %s: %w", spec, err)
+	}
+	if g.Name == "" {
+		g.Name = spec
+	}
+	if g.MACFormat == "" {
+		g.MACFormat = builtinGeometries[DefaultGeometry].MACFormat
+	}
+	if err := g.Validate(); err != nil {
+		return Geometry{}, err
+	}
+	return g, nil
+}
+
+// Validate checks that the geometry dimensions are usable and that MACFormat
+// gives a valid MAC for every slot and blade.
+func (g Geometry) Validate() error {
+	if g.Slots <= 0 || g.BladesPerSlot <= 0 || g.NodesPerBMC <= 0 {
+		return fmt.Errorf("geometry %s: slots, blades_per_slot and nodes_per_bmc must be > 0", g.Name)
+	}
+	if g.PrefixOctets < 0 || g.PrefixOctets > 5 {
+		return fmt.Errorf("geometry %s: prefix_octets must be 1-5", g.Name)
+	}
+	prefix := strings.Repeat("00:", g.prefixOctets()-1) + "00"
+	for n := 1; n <= g.NodesPerChassis(); n += g.NodesPerBMC {
+		mac := g.bmcMAC(prefix, n)
+		if hw, err := net.ParseMAC(mac); err != nil || len(hw) != 6 {
+			return fmt.Errorf("geometry %s: mac_format %q gives %s for slot %d blade %d, not a MAC", g.Name, g.MACFormat, mac, g.slot(n), g.blade(n))
+		}
+	}
+	return nil
+}
+
+func (g Geometry) prefixOctets() int {
+	if g.PrefixOctets > 0 {
+		return g.PrefixOctets
+	}
+	return 4
+}
+
+// checkPrefix returns an error unless prefix is a chassis MAC prefix of the
+// length {prefix} stands for in g's MAC format.
+func (g Geometry) checkPrefix(prefix string) error {
+	octets := strings.Split(prefix, ":")
+	ok := len(octets) == g.prefixOctets()
+	for _, o := range octets {
+		if _, err := strconv.ParseUint(o, 16, 8); len(o) != 2 || err != nil {
+			ok = false
+		}
+	}
+	if !ok {
+		return fmt.Errorf("MAC prefix %q is not %d hex octets as geometry %s expects", prefix, g.prefixOctets(), g.Name)
+	}
+	return nil
+}
+
+// NodesPerChassis returns the number of nodes a fully populated chassis holds.
+func (g Geometry) NodesPerChassis() int {
+	return g.Slots * g.BladesPerSlot * g.NodesPerBMC
+}
+
+func (g Geometry) slot(n int) int  { return ((n - 1) / (g.NodesPerBMC * g.BladesPerSlot)) % g.Slots }
+func (g Geometry) blade(n int) int { return ((n - 1) / g.NodesPerBMC) % g.BladesPerSlot }
+
+func (g Geometry) bmcXname(chassis string, n int) string {
+	return fmt.Sprintf("%ss%db%d", chassis, g.slot(n), g.blade(n))
+}
+
+func (g Geometry) bmcMAC(macStart string, n int) string {
+	r := strings.NewReplacer(
+		"{prefix}", macStart,
+		"{slot}", strconv.Itoa(g.slot(n)),
+		"{blade}", strconv.Itoa(g.blade(n)),
+	)
+	return r.Replace(g.MACFormat)
+}
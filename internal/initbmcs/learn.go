@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package initbmcs
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// macPattern turns a geometry MAC format into a regexp that captures {prefix}.
+func (g Geometry) macPattern() (*regexp.Regexp, error) {
+	expr := regexp.QuoteMeta(strings.ToLower(g.MACFormat))
+	// QuoteMeta escapes the braces of the placeholders
+	expr = strings.Replace(expr, `\{prefix\}`, fmt.Sprintf(`((?:[0-9a-f]{2}:){%d}[0-9a-f]{2})`, g.prefixOctets()-1), 1)
+	expr = strings.ReplaceAll(expr, `\{slot\}`, `\d+`)
+	expr = strings.ReplaceAll(expr, `\{blade\}`, `\d+`)
+	if !strings.Contains(expr, "(") {
+		return nil, fmt.Errorf("geometry %s: mac_format has no {prefix} placeholder", g.Name)
+	}
+	return regexp.Compile("^" + expr + "$")
+}
+
+// LearnChassisPrefixes infers per-chassis MAC prefixes from observed BMC MACs (e.g.
+// from DHCP leases). MACs that do not fit the geometry's MAC format are ignored. The
+// chassis number is the value of the last (hex) octet of the prefix, so
+// 02:23:28:03 in cabinet x9000 becomes x9000c3 and 02:23:28:0a becomes x9000c10.
+// The result can be passed to GenerateGeometry.
+func LearnChassisPrefixes(macs []string, geo Geometry, cabinet string) (map[string]string, error) {
+	re, err := geo.macPattern()
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]string{}
+	for _, mac := range macs {
+		m := re.FindStringSubmatch(strings.ToLower(mac))
+		if m == nil {
+			continue
+		}
+		prefix := m[1]
+		n, err := strconv.ParseUint(prefix[len(prefix)-2:], 16, 8)
+		if err != nil {
+			continue
+		}
+		c := fmt.Sprintf("%sc%d", cabinet, n)
+		if prev, ok := out[c]; ok && prev != prefix {
+			return nil, fmt.Errorf("conflicting MAC prefixes for %s: %s and %s", c, prev, prefix)
+		}
+		out[c] = prefix
+	}
+	return out, nil
+}
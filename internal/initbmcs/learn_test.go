@@ -0,0 +1,44 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package initbmcs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLearnChassisPrefixes(t *testing.T) {
+	geo, _ := LoadGeometry(DefaultGeometry)
+	macs := []string{
+		"02:23:28:01:30:00",
+		"02:23:28:01:37:10",
+		"02:23:28:03:30:10",
+		"aa:bb:cc:dd:ee:ff", // not a BMC MAC for this geometry
+	}
+	got, err := LearnChassisPrefixes(macs, geo, "x9000")
+	if err != nil {
+		t.Fatalf("LearnChassisPrefixes failed: %v", err)
+	}
+	want := map[string]string{
+		"x9000c1": "02:23:28:01",
+		"x9000c3": "02:23:28:03",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("LearnChassisPrefixes mismatch: got=%v want=%v", got, want)
+	}
+
+	// The octet is hex: 0a is chassis 10 and 10 is chassis 16
+	got, err = LearnChassisPrefixes([]string{"02:23:28:0A:30:00", "02:23:28:10:30:00"}, geo, "x9000")
+	if err != nil {
+		t.Fatalf("LearnChassisPrefixes failed: %v", err)
+	}
+	if want := map[string]string{"x9000c10": "02:23:28:0a", "x9000c16": "02:23:28:10"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("hex octets: got=%v want=%v", got, want)
+	}
+
+	if _, err := LearnChassisPrefixes([]string{"02:23:28:01:30:00", "02:24:28:01:30:00"}, geo, "x9000"); err == nil {
+		t.Fatal("expected error for conflicting prefixes")
+	}
+}
@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package initbmcs
+
+import (
+	"fmt"
+	"strings"
+
+	"bootstrap/pkg/inventory"
+	"bootstrap/pkg/netalloc"
+)
+
+// Merge appends generated BMC entries to existing ones. A generated entry whose xname
+// or MAC already exists is dropped so the existing record (and its IP) is preserved.
+// New entries whose IP collides with one already in use are re-allocated from bmcSubnet,
+// at or after startIP when it is set as for Generate.
+func Merge(existing, generated []inventory.Entry, bmcSubnet, startIP string) ([]inventory.Entry, error) {
+	alloc, err := netalloc.NewAllocator(bmcSubnet)
+	if err != nil {
+		return nil, fmt.Errorf("bmc subnet init: %w", err)
+	}
+	if startIP != "" {
+		if err := alloc.ReserveUpTo(startIP); err != nil {
+			return nil, fmt.Errorf("reserve up to start IP: %w", err)
+		}
+	}
+	used := map[string]bool{}
+	xnames := map[string]bool{}
+	macs := map[string]bool{}
+	for _, e := range existing {
+		xnames[e.Xname] = true
+		if e.MAC != "" {
+			macs[strings.ToLower(e.MAC)] = true
+		}
+		if e.IP != "" {
+			used[e.IP] = true
+			if alloc.Contains(e.IP) {
+				alloc.Reserve(e.IP)
+			}
+		}
+	}
+
+	// First pass: keep new entries and reserve their IPs when free
+	var added []inventory.Entry
+	var collide []int
+	for _, g := range generated {
+		if xnames[g.Xname] || macs[strings.ToLower(g.MAC)] {
+			continue
+		}
+		xnames[g.Xname] = true
+		macs[strings.ToLower(g.MAC)] = true
+		if used[g.IP] {
+			collide = append(collide, len(added))
+		} else {
+			used[g.IP] = true
+			alloc.Reserve(g.IP)
+		}
+		added = append(added, g)
+	}
+
+	// Second pass: move colliding entries to free addresses
+	for _, i := range collide {
+		ip, err := alloc.Next()
+		if err != nil {
+			return nil, fmt.Errorf("allocate IP for %s: %w", added[i].Xname, err)
+		}
+		added[i].IP = ip
+	}
+
+	out := make([]inventory.Entry, 0, len(existing)+len(added))
+	out = append(out, existing...)
+	return append(out, added...), nil
+}
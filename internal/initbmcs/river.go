@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package initbmcs
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"bootstrap/pkg/inventory"
+	"bootstrap/pkg/netalloc"
+)
+
+// RackUnit identifies a rackmount (river) node position within a cabinet.
+type RackUnit struct {
+	Cabinet string
+	Unit    int
+}
+
+// Label returns the elevation form of the position, e.g. x3000u01.
+func (r RackUnit) Label() string {
+	return fmt.Sprintf("%su%02d", r.Cabinet, r.Unit)
+}
+
+// BMCXname returns the BMC xname for the position, e.g. x3000c0s1b0.
+func (r RackUnit) BMCXname() string {
+	return fmt.Sprintf("%sc0s%db0", r.Cabinet, r.Unit)
+}
+
+var rackSpecRe = regexp.MustCompile(`^(x\d+)u(\d+)(?:-u?(\d+))?$`)
+
+// ParseRackSpec parses a comma-separated rack elevation spec such as
+// "x3000u01-u40,x3001u05" into the list of rack units it covers.
+func ParseRackSpec(spec string) ([]RackUnit, error) {
+	var out []RackUnit
+	for _, p := range strings.Split(spec, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		m := rackSpecRe.FindStringSubmatch(p)
+		if m == nil {
+			return nil, fmt.Errorf("invalid rack spec %q (expected e.g. x3000u01-u40)", p)
+		}
+		first, _ := strconv.Atoi(m[2])
+		last := first
+		if m[3] != "" {
+			last, _ = strconv.Atoi(m[3])
+		}
+		if first < 1 || last < first {
+			return nil, fmt.Errorf("invalid rack unit range in %q", p)
+		}
+		for u := first; u <= last; u++ {
+			out = append(out, RackUnit{Cabinet: m[1], Unit: u})
+		}
+	}
+	return out, nil
+}
+
+// LoadMACCSV reads "position,mac" rows from path. The position may be either the
+// rack elevation (x3000u01) or the BMC xname (x3000c0s1b0). Lines starting with '#'
+// and an optional header row are ignored. Keys are returned as-is, MACs in lowercase colon form; a value that is not a
+// 48-bit MAC is an error.
+func LoadMACCSV(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // nolint:errcheck
+	r := csv.NewReader(f)
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	out := map[string]string{}
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", path, err)
+		}
+		if len(rec) < 2 {
+			continue
+		}
+		k := strings.TrimSpace(rec[0])
+		v := strings.TrimSpace(rec[1])
+		if k == "" || v == "" || strings.EqualFold(v, "mac") {
+			continue
+		}
+		hw, err := net.ParseMAC(v)
+		if err != nil || len(hw) != 6 {
+			line, _ := r.FieldPos(1)
+			return nil, fmt.Errorf("%s:%d: %q is not a MAC", path, line, v)
+		}
+		out[k] = hw.String()
+	}
+	return out, nil
+}
+
+// GenerateRiver creates BMC entries for rackmount nodes. Each unit's MAC is looked
+// up in macs by elevation label or BMC xname; units without a MAC are an error.
+func GenerateRiver(units []RackUnit, macs map[string]string, bmcSubnet, startIP string) ([]inventory.Entry, error) {
+	alloc, err := netalloc.NewAllocator(bmcSubnet)
+	if err != nil {
+		return nil, fmt.Errorf("bmc subnet init: %w", err)
+	}
+	if startIP != "" {
+		if err := alloc.ReserveUpTo(startIP); err != nil {
+			return nil, fmt.Errorf("reserve up to start IP: %w", err)
+		}
+	}
+
+	var missing []string
+	bmcs := make([]inventory.Entry, 0, len(units))
+	for _, u := range units {
+		x := u.BMCXname()
+		mac, ok := macs[u.Label()]
+		if !ok {
+			mac, ok = macs[x]
+		}
+		if !ok {
+			missing = append(missing, u.Label())
+			continue
+		}
+		ip, err := alloc.Next()
+		if err != nil {
+			return nil, fmt.Errorf("allocate IP for %s: %w", x, err)
+		}
+		bmcs = append(bmcs, inventory.Entry{Xname: x, MAC: mac, IP: ip})
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("no BMC MAC provided for: %s", strings.Join(missing, ", "))
+	}
+	return bmcs, nil
+}
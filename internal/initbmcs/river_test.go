@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package initbmcs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bootstrap/pkg/inventory"
+)
+
+func TestParseRackSpec(t *testing.T) {
+	got, err := ParseRackSpec("x3000u01-u03, x3001u10")
+	if err != nil {
+		t.Fatalf("ParseRackSpec failed: %v", err)
+	}
+	want := []RackUnit{
+		{Cabinet: "x3000", Unit: 1},
+		{Cabinet: "x3000", Unit: 2},
+		{Cabinet: "x3000", Unit: 3},
+		{Cabinet: "x3001", Unit: 10},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseRackSpec mismatch: got=%v want=%v", got, want)
+	}
+	if _, err := ParseRackSpec("x3000u05-u01"); err == nil {
+		t.Fatal("expected error for descending range")
+	}
+	if _, err := ParseRackSpec("rack1"); err == nil {
+		t.Fatal("expected error for malformed spec")
+	}
+}
+
+func TestGenerateRiver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "macs.csv")
+	content := "position,mac\nx3000u01,AA:BB:CC:DD:EE:01\n# comment\nx3000c0s2b0,aa:bb:cc:dd:ee:02\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	macs, err := LoadMACCSV(path)
+	if err != nil {
+		t.Fatalf("LoadMACCSV failed: %v", err)
+	}
+	units, _ := ParseRackSpec("x3000u01-u02")
+	bmcs, err := GenerateRiver(units, macs, "10.1.0.0/24", "")
+	if err != nil {
+		t.Fatalf("GenerateRiver failed: %v", err)
+	}
+	want := []inventory.Entry{
+		{Xname: "x3000c0s1b0", MAC: "aa:bb:cc:dd:ee:01", IP: "10.1.0.1"},
+		{Xname: "x3000c0s2b0", MAC: "aa:bb:cc:dd:ee:02", IP: "10.1.0.2"},
+	}
+	if !reflect.DeepEqual(bmcs, want) {
+		t.Fatalf("GenerateRiver result mismatch:\n got: %#v\nwant: %#v", bmcs, want)
+	}
+
+	units, _ = ParseRackSpec("x3000u01-u03")
+	if _, err := GenerateRiver(units, macs, "10.1.0.0/24", ""); err == nil {
+		t.Fatal("expected error for unit without MAC")
+	}
+	// A value that is not a MAC names its line
+	for _, bad := range []string{"aa:bb:cc", "SN12345678", "aa:bb:cc:dd:ee:ff:00:11"} {
+		if err := os.WriteFile(path, []byte(content+"x3000u03,"+bad+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadMACCSV(path); err == nil || !strings.Contains(err.Error(), path+":5: ") {
+			t.Errorf("%s: err = %v, want an error at line 5", bad, err)
+		}
+	}
+}
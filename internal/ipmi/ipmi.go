@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package ipmi controls BMCs over IPMI-over-LAN (RMCP+) with ipmitool. It is the
+// fallback for BMCs whose Redfish service is missing or unreliable.
+package ipmi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Runner runs ipmitool with args and the extra environment env, returning its
+// combined output.
+type Runner func(ctx context.Context, args, env []string) ([]byte, error)
+
+// Client talks to one BMC. The password is passed to ipmitool through the
+// environment (-E), never on the command line.
+type Client struct {
+	Host    string
+	User    string
+	Pass    string
+	Timeout time.Duration // per ipmitool invocation; 0 = none
+	Run     Runner        // nil runs the ipmitool binary
+}
+
+// Power actions accepted by Client.Power.
+const (
+	PowerOn    = "on"
+	PowerOff   = "off"
+	PowerSoft  = "soft"
+	PowerCycle = "cycle"
+	PowerReset = "reset"
+)
+
+// PowerStatus returns "On" or "Off".
+func (c *Client) PowerStatus(ctx context.Context) (string, error) {
+	out, err := c.exec(ctx, "chassis", "power", "status")
+	if err != nil {
+		return "", err
+	}
+	s := strings.ToLower(out)
+	switch {
+	case strings.HasSuffix(s, " on"):
+		return "On", nil
+	case strings.HasSuffix(s, " off"):
+		return "Off", nil
+	}
+	return "", fmt.Errorf("ipmi %s: unexpected power status %q", c.Host, out)
+}
+
+// Power runs a chassis power action: on, off, soft, cycle or reset.
+func (c *Client) Power(ctx context.Context, action string) error {
+	switch action {
+	case PowerOn, PowerOff, PowerSoft, PowerCycle, PowerReset:
+	default:
+		return fmt.Errorf("ipmi: unknown power action %q", action)
+	}
+	_, err := c.exec(ctx, "chassis", "power", action)
+	return err
+}
+
+// Identify turns the chassis identify light on (until turned off) or off.
+func (c *Client) Identify(ctx context.Context, on bool) error {
+	arg := "0"
+	if on {
+		arg = "force"
+	}
+	_, err := c.exec(ctx, "chassis", "identify", arg)
+	return err
+}
+
+func (c *Client) exec(ctx context.Context, cmd ...string) (string, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	args := append([]string{"-I", "lanplus", "-H", c.Host, "-U", c.User, "-E"}, cmd...)
+	run := c.Run
+	if run == nil {
+		run = runIPMITool
+	}
+	out, err := run(ctx, args, []string{"IPMI_PASSWORD=" + c.Pass})
+	text := strings.TrimSpace(string(out))
+	if err != nil {
+		if text != "" {
+			return "", fmt.Errorf("ipmi %s: %s: %w", c.Host, text, err)
+		}
+		return "", fmt.Errorf("ipmi %s: %w", c.Host, err)
+	}
+	return text, nil
+}
+
+func runIPMITool(ctx context.Context, args, env []string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "ipmitool", args...)
+	cmd.Env = append(os.Environ(), env...)
+	return cmd.CombinedOutput()
+}
@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package ipmi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestClientCommands(t *testing.T) {
+	var got []string
+	var gotEnv []string
+	c := &Client{Host: "10.1.0.2", User: "admin", Pass: "secret", Run: func(_ context.Context, args, env []string) ([]byte, error) {
+		got, gotEnv = args, env
+		return []byte("Chassis Power is on\n"), nil
+	}}
+
+	st, err := c.PowerStatus(context.Background())
+	if err != nil || st != "On" {
+		t.Fatalf("PowerStatus = %q, %v", st, err)
+	}
+	if want := "-I lanplus -H 10.1.0.2 -U admin -E chassis power status"; strings.Join(got, " ") != want {
+		t.Errorf("args = %v", got)
+	}
+	if len(gotEnv) != 1 || gotEnv[0] != "IPMI_PASSWORD=secret" {
+		t.Errorf("env = %v", gotEnv)
+	}
+	for _, a := range got {
+		if a == "secret" {
+			t.Error("password passed on the command line")
+		}
+	}
+
+	if err := c.Identify(context.Background(), true); err != nil || got[len(got)-1] != "force" {
+		t.Errorf("Identify on: %v, args %v", err, got)
+	}
+	if err := c.Power(context.Background(), "explode"); err == nil {
+		t.Error("expected error for unknown action")
+	}
+}
+
+func TestClientError(t *testing.T) {
+	c := &Client{Host: "10.1.0.2", Run: func(context.Context, []string, []string) ([]byte, error) {
+		return []byte("Error: Unable to establish IPMI v2 / RMCP+ session\n"), errors.New("exit status 1")
+	}}
+	_, err := c.PowerStatus(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "RMCP+ session") {
+		t.Errorf("err = %v", err)
+	}
+}
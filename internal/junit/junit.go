@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package junit writes JUnit XML reports, the test result format CI systems
+// such as GitLab and Jenkins show natively, so a check of the fleet can gate a
+// pipeline with one test case per host or target.
+package junit
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Suite is a named group of test cases, e.g. one command run.
+type Suite struct {
+	Name     string
+	Time     time.Time     // when the run started; zero leaves it out
+	Duration time.Duration // of the run; 0 adds up the cases
+	Cases    []Case
+}
+
+// Case is one check: a host, or a target of a host.
+type Case struct {
+	Class    string // the host, shown as the group of the case
+	Name     string // what was checked, e.g. the firmware target
+	Duration time.Duration
+	Failure  string // why the case failed; "" passed
+	Type     string // kind of failure, e.g. a severity
+	Skipped  string // why the case was not run; "" ran
+	Output   string // details kept with the case
+}
+
+type xmlSuites struct {
+	XMLName  xml.Name   `xml:"testsuites"`
+	Tests    int        `xml:"tests,attr"`
+	Failures int        `xml:"failures,attr"`
+	Skipped  int        `xml:"skipped,attr"`
+	Suites   []xmlSuite `xml:"testsuite"`
+}
+
+type xmlSuite struct {
+	Name      string    `xml:"name,attr"`
+	Tests     int       `xml:"tests,attr"`
+	Failures  int       `xml:"failures,attr"`
+	Errors    int       `xml:"errors,attr"`
+	Skipped   int       `xml:"skipped,attr"`
+	Time      string    `xml:"time,attr"`
+	Timestamp string    `xml:"timestamp,attr,omitempty"`
+	Cases     []xmlCase `xml:"testcase"`
+}
+
+type xmlCase struct {
+	Class   string      `xml:"classname,attr"`
+	Name    string      `xml:"name,attr"`
+	Time    string      `xml:"time,attr"`
+	Failure *xmlFailure `xml:"failure,omitempty"`
+	Skipped *xmlSkipped `xml:"skipped,omitempty"`
+	Output  string      `xml:"system-out,omitempty"`
+}
+
+type xmlFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+type xmlSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// Write writes suites as a JUnit XML document.
+func Write(w io.Writer, suites ...Suite) error {
+	doc := xmlSuites{}
+	for _, s := range suites {
+		xs := xmlSuite{Name: s.Name, Tests: len(s.Cases)}
+		if !s.Time.IsZero() {
+			xs.Timestamp = s.Time.UTC().Format("2006-01-02T15:04:05")
+		}
+		var total time.Duration
+		for _, c := range s.Cases {
+			total += c.Duration
+			xc := xmlCase{Class: c.Class, Name: c.Name, Time: seconds(c.Duration), Output: c.Output}
+			switch {
+			case c.Failure != "":
+				xc.Failure = &xmlFailure{Message: c.Failure, Type: c.Type, Text: c.Failure}
+				xs.Failures++
+			case c.Skipped != "":
+				xc.Skipped = &xmlSkipped{Message: c.Skipped}
+				xs.Skipped++
+			}
+			xs.Cases = append(xs.Cases, xc)
+		}
+		if s.Duration > 0 {
+			total = s.Duration
+		}
+		xs.Time = seconds(total)
+		doc.Tests += xs.Tests
+		doc.Failures += xs.Failures
+		doc.Skipped += xs.Skipped
+		doc.Suites = append(doc.Suites, xs)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// seconds formats d as JUnit times are: seconds with millisecond precision.
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
@@ -0,0 +1,64 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package junit
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, Suite{
+		Name:     "firmware status",
+		Time:     time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		Duration: 2500 * time.Millisecond,
+		Cases: []Case{
+			{Class: "x1000c0s0b0", Name: "BMC", Output: "version 1.2"},
+			{Class: "x1000c0s1b0", Name: "BMC", Failure: "health: Critical <flash>", Type: "Critical"},
+			{Class: "x1000c0s2b0", Name: "BMC", Skipped: "update in progress"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<testsuites tests="3" failures="1" skipped="1">`,
+		`<testsuite name="firmware status" tests="3" failures="1" errors="0" skipped="1" time="2.500" timestamp="2025-03-01T12:00:00">`,
+		`<testcase classname="x1000c0s0b0" name="BMC" time="0.000">`,
+		`<system-out>version 1.2</system-out>`,
+		`<failure message="health: Critical &lt;flash&gt;" type="Critical">health: Critical &lt;flash&gt;</failure>`,
+		`<skipped message="update in progress"></skipped>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %s in:\n%s", want, out)
+		}
+	}
+	var doc struct {
+		Suites []struct {
+			Cases []struct {
+				Name string `xml:"name,attr"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil || len(doc.Suites) != 1 || len(doc.Suites[0].Cases) != 3 {
+		t.Errorf("does not parse back: %v %+v", err, doc)
+	}
+}
+
+func TestWriteSumsCaseTimes(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, Suite{Name: "verify-boot", Cases: []Case{{Name: "a", Duration: time.Second}, {Name: "b", Duration: 1500 * time.Millisecond}}}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `time="2.500">`) || strings.Contains(buf.String(), "timestamp") {
+		t.Errorf("got:\n%s", buf.String())
+	}
+}
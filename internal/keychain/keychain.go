@@ -0,0 +1,31 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package keychain reads secrets from the operating system's credential store:
+// the login keychain on macOS, the Secret Service (GNOME Keyring, KWallet) on
+// Linux and other Unix systems, and Credential Manager on Windows.
+//
+// A secret is addressed by a service and an account. On macOS and Linux these
+// are the item's service and account attributes; on Windows the generic
+// credential whose target name is "service:account".
+package keychain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotFound is returned by Get when the store has no such secret.
+var ErrNotFound = errors.New("not found in the OS keychain")
+
+// Get returns the secret stored for service and account, without a trailing
+// newline.
+func Get(service, account string) (string, error) {
+	secret, err := lookup(service, account)
+	if err != nil {
+		return "", fmt.Errorf("keychain %s/%s: %w", service, account, err)
+	}
+	return strings.TrimRight(secret, "\r\n"), nil
+}
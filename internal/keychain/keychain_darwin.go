@@ -0,0 +1,11 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package keychain
+
+// lookup asks the security tool for a generic password; it exits 44 when the
+// keychain has none.
+func lookup(service, account string) (string, error) {
+	return run(44, "security", "find-generic-password", "-s", service, "-a", account, "-w")
+}
@@ -0,0 +1,46 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+
+package keychain
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeSecretTool puts a secret-tool on PATH that knows one secret.
+func fakeSecretTool(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	script := `#!/bin/sh
+[ "$1 $2 $3 $4 $5" = "lookup service bootstrap account REDFISH_PASSWORD" ] || exit 1
+printf 's3cr3t!\n'
+`
+	if err := os.WriteFile(filepath.Join(dir, "secret-tool"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+}
+
+func TestGet(t *testing.T) {
+	fakeSecretTool(t)
+	got, err := Get("bootstrap", "REDFISH_PASSWORD")
+	if err != nil || got != "s3cr3t!" {
+		t.Fatalf("Get = %q, %v", got, err)
+	}
+	if _, err := Get("bootstrap", "IPMI_PASSWORD"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing secret: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetWithoutTool(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	if _, err := Get("bootstrap", "REDFISH_PASSWORD"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("err = %v, want a missing secret-tool error", err)
+	}
+}
@@ -0,0 +1,17 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+//go:build !darwin && !windows
+
+package keychain
+
+// lookup asks the Secret Service through secret-tool (libsecret), which exits 1
+// with no output when nothing matches.
+func lookup(service, account string) (string, error) {
+	out, err := run(1, "secret-tool", "lookup", "service", service, "account", account)
+	if err == nil && out == "" {
+		return "", ErrNotFound
+	}
+	return out, err
+}
@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package keychain
+
+import (
+	"errors"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+)
+
+var (
+	advapi32     = syscall.NewLazyDLL("advapi32.dll")
+	procCredRead = advapi32.NewProc("CredReadW")
+	procCredFree = advapi32.NewProc("CredFree")
+)
+
+const (
+	credTypeGeneric = 1
+	errorNotFound   = syscall.Errno(1168)
+)
+
+// credential mirrors the Win32 CREDENTIALW structure.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// lookup reads the generic credential "service:account" from Credential
+// Manager, e.g. one stored with cmdkey /generic:service:account /pass.
+func lookup(service, account string) (string, error) {
+	target, err := syscall.UTF16PtrFromString(service + ":" + account)
+	if err != nil {
+		return "", err
+	}
+	var cred *credential
+	r, _, err := procCredRead.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if r == 0 {
+		if errors.Is(err, errorNotFound) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred))) //nolint:errcheck
+	if cred.CredentialBlobSize == 0 {
+		return "", nil
+	}
+	return decodeBlob(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
+
+// decodeBlob returns the secret in blob. cmdkey and the Credential Manager
+// dialog store UTF-16LE; other tools store the bytes as given.
+func decodeBlob(blob []byte) string {
+	if len(blob)%2 != 0 {
+		return string(blob)
+	}
+	units := make([]uint16, len(blob)/2)
+	for i := range units {
+		if blob[2*i+1] != 0 {
+			return string(blob)
+		}
+		units[i] = uint16(blob[2*i])
+	}
+	return string(utf16.Decode(units))
+}
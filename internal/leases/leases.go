@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package leases parses DHCP server lease files.
+package leases
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"bootstrap/pkg/inventory"
+)
+
+// Lease is a single DHCP lease binding a MAC to an IP.
+type Lease struct {
+	MAC      string
+	IP       string
+	Hostname string
+}
+
+// ParseDnsmasq parses a dnsmasq leases file. Each line has the form
+// "<expiry> <mac> <ip> <hostname> <client-id>"; DUID and IPv6 lines are skipped.
+func ParseDnsmasq(r io.Reader) ([]Lease, error) {
+	var out []Lease
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) < 3 || f[0] == "duid" {
+			continue
+		}
+		mac, err := net.ParseMAC(f[1])
+		if err != nil {
+			continue
+		}
+		ip := net.ParseIP(f[2])
+		if ip == nil || ip.To4() == nil {
+			continue
+		}
+		l := Lease{MAC: mac.String(), IP: ip.String()}
+		if len(f) > 3 && f[3] != "*" {
+			l.Hostname = f[3]
+		}
+		out = append(out, l)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ParseISC parses an ISC dhcpd.leases file. Later lease blocks for the same IP
+// supersede earlier ones, as dhcpd appends to the file; leases whose binding state
+// is not active are dropped.
+func ParseISC(r io.Reader) ([]Lease, error) {
+	byIP := map[string]Lease{}
+	var order []string
+	var cur *Lease
+	active := true
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		line = strings.TrimSuffix(line, ";")
+		f := strings.Fields(line)
+		switch {
+		case len(f) >= 3 && f[0] == "lease" && f[2] == "{":
+			cur = &Lease{IP: f[1]}
+			active = true
+		case cur == nil:
+			continue
+		case len(f) >= 3 && f[0] == "hardware" && f[1] == "ethernet":
+			if mac, err := net.ParseMAC(f[2]); err == nil {
+				cur.MAC = mac.String()
+			}
+		case len(f) >= 2 && f[0] == "client-hostname":
+			cur.Hostname = strings.Trim(f[1], `"`)
+		case len(f) >= 3 && f[0] == "binding" && f[1] == "state":
+			active = f[2] == "active"
+		case line == "}":
+			if _, seen := byIP[cur.IP]; !seen {
+				order = append(order, cur.IP)
+			}
+			if active && cur.MAC != "" && net.ParseIP(cur.IP) != nil {
+				byIP[cur.IP] = *cur
+			} else {
+				delete(byIP, cur.IP)
+			}
+			cur = nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	var out []Lease
+	for _, ip := range order {
+		if l, ok := byIP[ip]; ok {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+// ParseKea parses a Kea DHCPv4 memfile lease CSV. Columns are located by the header
+// row; only leases in the default (0, assigned) state are returned.
+func ParseKea(r io.Reader) ([]Lease, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+	col := map[string]int{}
+	for i, h := range header {
+		col[strings.TrimSpace(h)] = i
+	}
+	ai, ok1 := col["address"]
+	hi, ok2 := col["hwaddr"]
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("kea lease file: missing address/hwaddr columns")
+	}
+	field := func(rec []string, name string) string {
+		if i, ok := col[name]; ok && i < len(rec) {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+	var out []Lease
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ai >= len(rec) || hi >= len(rec) {
+			continue
+		}
+		if st := field(rec, "state"); st != "" && st != "0" {
+			continue
+		}
+		mac, err := net.ParseMAC(rec[hi])
+		if err != nil {
+			continue
+		}
+		ip := net.ParseIP(rec[ai])
+		if ip == nil {
+			continue
+		}
+		out = append(out, Lease{MAC: mac.String(), IP: ip.String(), Hostname: field(rec, "hostname")})
+	}
+	return out, nil
+}
+
+// Load reads a lease file of the given format. Supported formats: dnsmasq, isc, kea.
+func Load(path, format string) ([]Lease, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // nolint:errcheck
+	switch strings.ToLower(format) {
+	case "", "dnsmasq":
+		return ParseDnsmasq(f)
+	case "isc", "dhcpd":
+		return ParseISC(f)
+	case "kea":
+		return ParseKea(f)
+	default:
+		return nil, fmt.Errorf("unknown lease format: %s (use dnsmasq|isc|kea)", format)
+	}
+}
+
+// Apply sets the IP of each entry whose MAC has a lease, returning the xnames whose
+// IP changed. MACs are compared case-insensitively.
+func Apply(entries []inventory.Entry, ls []Lease) []string {
+	byMAC := make(map[string]string, len(ls))
+	for _, l := range ls {
+		byMAC[strings.ToLower(l.MAC)] = l.IP
+	}
+	var changed []string
+	for i := range entries {
+		ip, ok := byMAC[strings.ToLower(entries[i].MAC)]
+		if !ok || ip == entries[i].IP {
+			continue
+		}
+		entries[i].IP = ip
+		changed = append(changed, entries[i].Xname)
+	}
+	return changed
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package leases
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"bootstrap/pkg/inventory"
+)
+
+func TestParseDnsmasq(t *testing.T) {
+	in := `1700000000 02:23:28:01:30:00 192.168.100.10 x9000c1s0b0 01:02:23:28:01:30:00
+1700000000 02:23:28:01:30:10 192.168.100.11 * *
+duid 00:01:00:01:2c:aa:bb:cc
+1700000000 not-a-mac 192.168.100.12 * *
+`
+	got, err := ParseDnsmasq(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ParseDnsmasq failed: %v", err)
+	}
+	want := []Lease{
+		{MAC: "02:23:28:01:30:00", IP: "192.168.100.10", Hostname: "x9000c1s0b0"},
+		{MAC: "02:23:28:01:30:10", IP: "192.168.100.11"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseDnsmasq mismatch:\n got: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestParseISC(t *testing.T) {
+	in := `lease 10.0.0.5 {
+  starts 4 2025/01/01 00:00:00;
+  binding state active;
+  hardware ethernet 02:23:28:01:30:00;
+  client-hostname "bmc1";
+}
+lease 10.0.0.6 {
+  binding state free;
+  hardware ethernet 02:23:28:01:30:10;
+}
+lease 10.0.0.5 {
+  binding state active;
+  hardware ethernet 02:23:28:01:31:00;
+}
+`
+	got, err := ParseISC(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ParseISC failed: %v", err)
+	}
+	want := []Lease{{MAC: "02:23:28:01:31:00", IP: "10.0.0.5"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseISC mismatch:\n got: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestParseKea(t *testing.T) {
+	in := `address,hwaddr,client_id,valid_lifetime,expire,subnet_id,fqdn_fwd,fqdn_rev,hostname,state
+10.0.0.7,02:23:28:01:30:00,,3600,1700000000,1,0,0,bmc1,0
+10.0.0.8,02:23:28:01:30:10,,3600,1700000000,1,0,0,,2
+`
+	got, err := ParseKea(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ParseKea failed: %v", err)
+	}
+	want := []Lease{{MAC: "02:23:28:01:30:00", IP: "10.0.0.7", Hostname: "bmc1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseKea mismatch:\n got: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestApply(t *testing.T) {
+	entries := []inventory.Entry{
+		{Xname: "x9000c1s0b0", MAC: "02:23:28:01:30:00", IP: "192.168.100.1"},
+		{Xname: "x9000c1s0b1", MAC: "02:23:28:01:30:10", IP: "192.168.100.2"},
+	}
+	changed := Apply(entries, []Lease{
+		{MAC: "02:23:28:01:30:00", IP: "10.0.0.7"},
+		{MAC: "02:23:28:01:30:10", IP: "192.168.100.2"},
+	})
+	if !reflect.DeepEqual(changed, []string{"x9000c1s0b0"}) {
+		t.Fatalf("unexpected changed list: %v", changed)
+	}
+	if entries[0].IP != "10.0.0.7" {
+		t.Fatalf("expected leased IP to be applied, got %s", entries[0].IP)
+	}
+}
@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package ledger is an append-only record of the write operations sent to BMCs.
+// Each operation carries an idempotency key derived from the host, the
+// operation and its payload, so a retried command can tell which operations a
+// BMC already accepted.
+package ledger
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry states
+const (
+	Submitted = "submitted" // about to be sent; no outcome recorded yet
+	Accepted  = "accepted"  // the BMC accepted it
+	Rejected  = "rejected"  // the BMC refused it, or nothing was sent
+	Unknown   = "unknown"   // the connection failed; the BMC may have received it
+)
+
+// Entry is one line of the ledger.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Host    string    `json:"host"`
+	Op      string    `json:"op"`     // SimpleUpdate, SetBiosAttributes, ...
+	Target  string    `json:"target"` // what the operation acts on, for people reading the ledger
+	Key     string    `json:"key"`
+	State   string    `json:"state"`
+	TaskURI string    `json:"task_uri,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// Key returns the idempotency key of op with payload on host.
+func Key(host, op string, payload any) string {
+	b, _ := json.Marshal(struct {
+		Host    string `json:"host"`
+		Op      string `json:"op"`
+		Payload any    `json:"payload"`
+	}{host, op, payload})
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:16])
+}
+
+// Ledger is a JSON-lines file. Appends are single writes to a file opened with
+// O_APPEND, so commands running side by side can share one ledger.
+type Ledger struct {
+	path string
+	mu   sync.Mutex
+	last map[string]Entry // by key, as of Open plus this process's records
+	torn bool             // the file ended in a torn line at Open
+}
+
+// Open reads the ledger at path, creating it (and its directory) if needed.
+func Open(path string) (*Ledger, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	l := &Ledger{path: path, last: map[string]Entry{}}
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1<<20)
+	for n := 1; sc.Scan(); n++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			// A torn last line from a crash is expected; anything else is not
+			fmt.Fprintf(os.Stderr, "WARN: %s:%d: skipping unreadable entry: %v\n", path, n, err)
+			continue
+		}
+		l.last[e.Key] = e
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	// The next record must not be appended to a torn line, or it is lost with it
+	if st, err := f.Stat(); err == nil && st.Size() > 0 {
+		end := make([]byte, 1)
+		if _, err := f.ReadAt(end, st.Size()-1); err == nil {
+			l.torn = end[0] != '\n'
+		}
+	}
+	return l, nil
+}
+
+// Last returns the latest entry for key.
+func (l *Ledger) Last(key string) (Entry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e, ok := l.last[key]
+	return e, ok
+}
+
+// Record appends e, stamping its time when unset.
+func (l *Ledger) Record(e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	line := append(b, '\n')
+	if l.torn {
+		line = append([]byte{'\n'}, line...)
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	l.torn = false
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	l.last[e.Key] = e
+	return f.Close()
+}
@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package ledger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordAndReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "ledger.jsonl")
+	l, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := Key("10.0.0.1", "SimpleUpdate", map[string]any{"ImageURI": "http://x/cc.bin"})
+	if key == Key("10.0.0.2", "SimpleUpdate", map[string]any{"ImageURI": "http://x/cc.bin"}) {
+		t.Fatal("key does not depend on the host")
+	}
+	if _, ok := l.Last(key); ok {
+		t.Fatal("empty ledger has an entry")
+	}
+	for _, st := range []string{Submitted, Accepted} {
+		if err := l.Record(Entry{Host: "10.0.0.1", Op: "SimpleUpdate", Key: key, State: st}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A torn line from a crash is skipped
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"key": "`) //nolint:errcheck
+	f.Close()
+
+	l, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := l.Last(key); !ok || e.State != Accepted || e.Time.IsZero() {
+		t.Errorf("last = %+v, %v", e, ok)
+	}
+
+	// The retry after the crash is not appended to the torn line
+	retry := Key("10.0.0.2", "SimpleUpdate", map[string]any{"ImageURI": "http://x/cc.bin"})
+	if err := l.Record(Entry{Host: "10.0.0.2", Op: "SimpleUpdate", Key: retry, State: Submitted}); err != nil {
+		t.Fatal(err)
+	}
+	l, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := l.Last(retry); !ok || e.State != Submitted {
+		t.Errorf("record after a torn line: last = %+v, %v", e, ok)
+	}
+	if l.torn {
+		t.Error("ledger still ends in a torn line")
+	}
+}
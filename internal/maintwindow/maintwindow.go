@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package maintwindow parses weekly maintenance windows such as
+// "Sat 00:00-06:00 America/Denver" and tells whether a time falls in one, so
+// destructive operations only run when the site expects them to.
+package maintwindow
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Window is a time range repeated on some days of the week.
+type Window struct {
+	days  [7]bool // by time.Weekday; the day the window opens
+	start int     // minutes after midnight
+	end   int     // minutes after midnight, up to 24:00; <= start ends the next day
+	loc   *time.Location
+	spec  string
+}
+
+// Parse reads a window: optional days, a time range and an optional time zone
+// (the local one by default). Days are names (Sat, Saturday), lists (Sat,Sun)
+// and ranges (Mon-Fri); without them the window is open every day. A range
+// ending at or before its start runs past midnight: "Fri 22:00-02:00" closes
+// on Saturday.
+func Parse(s string) (Window, error) {
+	w := Window{loc: time.Local, spec: s}
+	f := strings.Fields(s)
+	if len(f) > 0 && !strings.Contains(f[0], ":") {
+		if err := w.parseDays(f[0]); err != nil {
+			return Window{}, fmt.Errorf("window %q: %w", s, err)
+		}
+		f = f[1:]
+	} else {
+		w.days = [7]bool{true, true, true, true, true, true, true}
+	}
+	if len(f) == 0 || len(f) > 2 {
+		return Window{}, fmt.Errorf("window %q: want [DAYS] HH:MM-HH:MM [TIME ZONE]", s)
+	}
+	from, to, ok := strings.Cut(f[0], "-")
+	if !ok {
+		return Window{}, fmt.Errorf("window %q: time range %q is not HH:MM-HH:MM", s, f[0])
+	}
+	var err error
+	if w.start, err = clock(from, false); err != nil {
+		return Window{}, fmt.Errorf("window %q: %w", s, err)
+	}
+	if w.end, err = clock(to, true); err != nil {
+		return Window{}, fmt.Errorf("window %q: %w", s, err)
+	}
+	if len(f) == 2 {
+		if w.loc, err = time.LoadLocation(f[1]); err != nil {
+			return Window{}, fmt.Errorf("window %q: %w", s, err)
+		}
+	}
+	return w, nil
+}
+
+func (w *Window) parseDays(s string) error {
+	for _, part := range strings.Split(s, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		a, err := weekday(from)
+		if err != nil {
+			return err
+		}
+		b := a
+		if isRange {
+			if b, err = weekday(to); err != nil {
+				return err
+			}
+		}
+		for d := a; ; d = (d + 1) % 7 {
+			w.days[d] = true
+			if d == b {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func weekday(s string) (time.Weekday, error) {
+	l := strings.ToLower(s)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if len(l) >= 3 && strings.HasPrefix(strings.ToLower(d.String()), l) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown day %q", s)
+}
+
+// clock parses HH:MM into minutes after midnight; 24:00 only as an end.
+func clock(s string, end bool) (int, error) {
+	h, m, ok := strings.Cut(s, ":")
+	hh, err1 := strconv.Atoi(h)
+	mm, err2 := strconv.Atoi(m)
+	if !ok || err1 != nil || err2 != nil || hh < 0 || mm < 0 || mm > 59 || hh > 24 || hh == 24 && (mm != 0 || !end) {
+		return 0, fmt.Errorf("time %q is not HH:MM", s)
+	}
+	return hh*60 + mm, nil
+}
+
+// String returns the window as it was given.
+func (w Window) String() string { return w.spec }
+
+// opening returns the opening of w that starts on the day of t (in w's time
+// zone) plus offset days, and whether w opens on that day.
+func (w Window) opening(t time.Time, offset int) (start, end time.Time, ok bool) {
+	t = t.In(w.loc)
+	y, mo, d := t.Date()
+	day := time.Date(y, mo, d+offset, 0, 0, 0, 0, w.loc)
+	if !w.days[day.Weekday()] {
+		return time.Time{}, time.Time{}, false
+	}
+	start = time.Date(y, mo, d+offset, 0, w.start, 0, 0, w.loc)
+	end = time.Date(y, mo, d+offset, 0, w.end, 0, 0, w.loc)
+	if w.end <= w.start {
+		end = time.Date(y, mo, d+offset+1, 0, w.end, 0, 0, w.loc)
+	}
+	return start, end, true
+}
+
+// Open reports whether t falls in w and, if so, when that opening closes.
+func (w Window) Open(t time.Time) (time.Time, bool) {
+	for _, offset := range []int{0, -1} {
+		if start, end, ok := w.opening(t, offset); ok && !t.Before(start) && t.Before(end) {
+			return end, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Next returns the first opening of w after t, or the zero time when w opens
+// on no day.
+func (w Window) Next(t time.Time) time.Time {
+	for offset := 0; offset <= 8; offset++ {
+		if start, _, ok := w.opening(t, offset); ok && start.After(t) {
+			return start
+		}
+	}
+	return time.Time{}
+}
+
+// Set is a list of windows; a time is in the set when it is in any of them.
+type Set []Window
+
+// ParseSet parses each spec with Parse.
+func ParseSet(specs []string) (Set, error) {
+	var s Set
+	for _, spec := range specs {
+		w, err := Parse(spec)
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, w)
+	}
+	return s, nil
+}
+
+// File is the YAML form of a set:
+//
+//	windows:
+//	  - Sat 00:00-06:00 America/Denver
+type File struct {
+	Windows []string `yaml:"windows"`
+}
+
+// Load reads a set from a YAML file.
+func Load(path string) (Set, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f File
+	if err := yaml.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if len(f.Windows) == 0 {
+		return nil, fmt.Errorf("%s lists no windows", path)
+	}
+	s, err := ParseSet(f.Windows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Open reports whether t falls in a window of s and, if so, when it closes.
+// Windows that overlap or follow each other without a gap count as one, so
+// "Sat 00:00-24:00" and "Sun 00:00-06:00" close on Sunday at 06:00. A set open
+// around the clock closes a week after t.
+func (s Set) Open(t time.Time) (time.Time, bool) {
+	closes, open := time.Time{}, false
+	for _, w := range s {
+		if end, ok := w.Open(t); ok && end.After(closes) {
+			closes, open = end, true
+		}
+	}
+	for open && closes.Sub(t) < 7*24*time.Hour {
+		next := closes
+		for _, w := range s {
+			if end, ok := w.Open(closes); ok && end.After(next) {
+				next = end
+			}
+		}
+		if next.Equal(closes) {
+			break
+		}
+		closes = next
+	}
+	if open && closes.Sub(t) > 7*24*time.Hour {
+		closes = t.Add(7 * 24 * time.Hour)
+	}
+	return closes, open
+}
+
+// Next returns the first time after t a window of s opens, or the zero time.
+func (s Set) Next(t time.Time) time.Time {
+	var next time.Time
+	for _, w := range s {
+		if n := w.Next(t); !n.IsZero() && (next.IsZero() || n.Before(next)) {
+			next = n
+		}
+	}
+	return next
+}
+
+// String joins the windows of s with "; ".
+func (s Set) String() string {
+	specs := make([]string, len(s))
+	for i, w := range s {
+		specs[i] = w.String()
+	}
+	return strings.Join(specs, "; ")
+}
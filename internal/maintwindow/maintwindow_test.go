@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package maintwindow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWindowOpen(t *testing.T) {
+	denver, err := time.LoadLocation("America/Denver")
+	if err != nil {
+		t.Skip("no time zone data:", err)
+	}
+	at := func(s string) time.Time {
+		tm, err := time.ParseInLocation("Mon 2006-01-02 15:04", s, denver)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+	cases := []struct {
+		spec   string
+		t      string
+		open   bool
+		closes string
+	}{
+		{"Sat 00:00-06:00 America/Denver", "Sat 2025-07-05 00:00", true, "Sat 2025-07-05 06:00"},
+		{"Sat 00:00-06:00 America/Denver", "Sat 2025-07-05 05:59", true, "Sat 2025-07-05 06:00"},
+		{"Sat 00:00-06:00 America/Denver", "Sat 2025-07-05 06:00", false, ""},
+		{"Sat 00:00-06:00 America/Denver", "Wed 2025-07-02 02:00", false, ""},
+		{"saturday 00:00-06:00 America/Denver", "Sat 2025-07-05 01:00", true, "Sat 2025-07-05 06:00"},
+		{"Fri 22:00-02:00 America/Denver", "Sat 2025-07-05 01:00", true, "Sat 2025-07-05 02:00"},
+		{"Fri 22:00-02:00 America/Denver", "Fri 2025-07-04 21:59", false, ""},
+		{"Mon-Fri 20:00-24:00 America/Denver", "Wed 2025-07-02 23:00", true, "Thu 2025-07-03 00:00"},
+		{"Mon-Fri 20:00-24:00 America/Denver", "Sat 2025-07-05 23:00", false, ""},
+		{"Fri-Mon 01:00-02:00 America/Denver", "Sun 2025-07-06 01:30", true, "Sun 2025-07-06 02:00"},
+		{"Tue,Thu 01:00-02:00 America/Denver", "Thu 2025-07-03 01:30", true, "Thu 2025-07-03 02:00"},
+		{"Tue,Thu 01:00-02:00 America/Denver", "Wed 2025-07-02 01:30", false, ""},
+		// 06:00 in Denver is 12:00 UTC in summer
+		{"Sat 10:00-12:00 UTC", "Sat 2025-07-05 05:00", true, "Sat 2025-07-05 06:00"},
+	}
+	for _, tc := range cases {
+		w, err := Parse(tc.spec)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.spec, err)
+		}
+		closes, open := w.Open(at(tc.t))
+		if open != tc.open || open && !closes.Equal(at(tc.closes)) {
+			t.Errorf("%s at %s: open %v until %s, want %v until %s", tc.spec, tc.t, open, closes.In(denver), tc.open, tc.closes)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{"", "Sat", "Sat 06:00", "Sat 24:00-06:00", "Sat 00:00-06:60", "Someday 00:00-06:00", "Sat 00:00-06:00 Mars/Olympus", "Sat 00:00-06:00 UTC extra"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded", spec)
+		}
+	}
+}
+
+func TestSetOpenAndNext(t *testing.T) {
+	s, err := ParseSet([]string{"Sat 00:00-24:00 UTC", "Sun 00:00-06:00 UTC", "Wed 22:00-02:00 UTC"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sat := time.Date(2025, 7, 5, 12, 0, 0, 0, time.UTC)
+	if closes, open := s.Open(sat); !open || !closes.Equal(time.Date(2025, 7, 6, 6, 0, 0, 0, time.UTC)) {
+		t.Errorf("Saturday: open %v until %s, want the Sunday 06:00 close", open, closes)
+	}
+	mon := time.Date(2025, 7, 7, 12, 0, 0, 0, time.UTC)
+	if _, open := s.Open(mon); open {
+		t.Error("Monday: open")
+	}
+	if next := s.Next(mon); !next.Equal(time.Date(2025, 7, 9, 22, 0, 0, 0, time.UTC)) {
+		t.Errorf("Monday: next window %s, want Wednesday 22:00", next)
+	}
+
+	always, _ := ParseSet([]string{"00:00-24:00 UTC"})
+	if closes, open := always.Open(mon); !open || closes.Sub(mon) != 7*24*time.Hour {
+		t.Errorf("always open: open %v until %s", open, closes)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "windows.yaml")
+	if err := os.WriteFile(path, []byte("windows:\n  - Sat 00:00-06:00 UTC\n  - Sun 00:00-06:00 UTC\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(path)
+	if err != nil || len(s) != 2 || s.String() != "Sat 00:00-06:00 UTC; Sun 00:00-06:00 UTC" {
+		t.Fatalf("Load = %v, %v", s, err)
+	}
+	empty := filepath.Join(dir, "empty.yaml")
+	if err := os.WriteFile(empty, []byte("windows: []\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(empty); err == nil {
+		t.Error("a file without windows loaded")
+	}
+}
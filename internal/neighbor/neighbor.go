@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package neighbor reads the local ARP/neighbor table to map MACs to live IPs.
+package neighbor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"bootstrap/internal/diag"
+)
+
+// Entry is a resolved IP to MAC binding.
+type Entry struct {
+	IP     string
+	MAC    string
+	Device string
+}
+
+// ParseProcARP parses the /proc/net/arp format. Incomplete entries (flags 0x0 or an
+// all-zero MAC) are skipped. If device is non-empty only entries on it are returned.
+func ParseProcARP(r io.Reader, device string) ([]Entry, error) {
+	var out []Entry
+	sc := bufio.NewScanner(r)
+	first := true
+	for sc.Scan() {
+		if first {
+			// header: IP address HW type Flags HW address Mask Device
+			first = false
+			continue
+		}
+		f := strings.Fields(sc.Text())
+		if len(f) < 6 {
+			continue
+		}
+		if f[2] == "0x0" || f[3] == "00:00:00:00:00:00" {
+			continue
+		}
+		if device != "" && f[5] != device {
+			continue
+		}
+		mac, err := net.ParseMAC(f[3])
+		if err != nil {
+			continue
+		}
+		out = append(out, Entry{IP: f[0], MAC: mac.String(), Device: f[5]})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReadTable reads and parses the ARP table at path (DefaultARPTable when empty).
+// Only Linux has a default; elsewhere path must name a file in /proc/net/arp
+// format, e.g. one copied from the admin node.
+func ReadTable(path, device string) ([]Entry, error) {
+	if path == "" {
+		path = DefaultARPTable
+	}
+	if path == "" {
+		return nil, fmt.Errorf("no kernel ARP table to read on %s; give a file in /proc/net/arp format", runtime.GOOS)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // nolint:errcheck
+	return ParseProcARP(f, device)
+}
+
+// Sweep sends a single UDP datagram to every host address in cidr so the kernel
+// resolves (and caches) their MACs in the neighbor table. It does not wait for
+// replies; callers should pause briefly before reading the table.
+func Sweep(ctx context.Context, cidr string) error {
+	ip, n, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return err
+	}
+	ip = ip.Mask(n.Mask).To4()
+	if ip == nil {
+		return &net.ParseError{Type: "IPv4 CIDR", Text: cidr}
+	}
+	d := net.Dialer{Timeout: time.Second}
+	for cur := next(ip); n.Contains(cur) && !isBroadcast(cur, n); cur = next(cur) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		conn, err := d.DialContext(ctx, "udp4", net.JoinHostPort(cur.String(), "9"))
+		if err != nil {
+			diag.Logf("sweep %s: %v", cur, err)
+			continue
+		}
+		_, _ = conn.Write([]byte{0})
+		_ = conn.Close()
+	}
+	return nil
+}
+
+func next(ip net.IP) net.IP {
+	out := make(net.IP, len(ip))
+	copy(out, ip)
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i]++
+		if out[i] != 0 {
+			break
+		}
+	}
+	return out
+}
+
+func isBroadcast(ip net.IP, n *net.IPNet) bool {
+	for i := range ip {
+		if ip[i]|n.Mask[i] != 0xff {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package neighbor
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseProcARP(t *testing.T) {
+	in := `IP address       HW type     Flags       HW address            Mask     Device
+192.168.100.10   0x1         0x2         02:23:28:01:30:00     *        eth1
+192.168.100.11   0x1         0x0         00:00:00:00:00:00     *        eth1
+10.0.0.1         0x1         0x2         aa:bb:cc:dd:ee:ff     *        eth0
+`
+	got, err := ParseProcARP(strings.NewReader(in), "eth1")
+	if err != nil {
+		t.Fatalf("ParseProcARP failed: %v", err)
+	}
+	want := []Entry{{IP: "192.168.100.10", MAC: "02:23:28:01:30:00", Device: "eth1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseProcARP mismatch:\n got: %#v\nwant: %#v", got, want)
+	}
+
+	all, _ := ParseProcARP(strings.NewReader(in), "")
+	if len(all) != 2 {
+		t.Fatalf("expected 2 complete entries without device filter, got %d", len(all))
+	}
+}
+
+func TestIsBroadcast(t *testing.T) {
+	_, n, _ := net.ParseCIDR("10.0.0.0/30")
+	if !isBroadcast(net.ParseIP("10.0.0.3").To4(), n) {
+		t.Fatal("expected .3 to be broadcast in /30")
+	}
+	if isBroadcast(net.ParseIP("10.0.0.2").To4(), n) {
+		t.Fatal("did not expect .2 to be broadcast in /30")
+	}
+}
@@ -0,0 +1,8 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package neighbor
+
+// DefaultARPTable is the Linux kernel's IPv4 neighbor table.
+const DefaultARPTable = "/proc/net/arp"
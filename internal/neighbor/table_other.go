@@ -0,0 +1,11 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+//go:build !linux
+
+package neighbor
+
+// DefaultARPTable is empty where the kernel does not expose its neighbor table
+// as a file; ReadTable then needs an explicit path.
+const DefaultARPTable = ""
@@ -0,0 +1,124 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package netdial builds the dialers used to reach BMC networks that the admin
+// node cannot route to directly: SOCKS5 and HTTP CONNECT proxies and SSH jump hosts.
+package netdial
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// DialContextFunc opens a network connection, like net.Dialer.DialContext.
+type DialContextFunc = func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// Direct returns a dialer that connects without any proxy.
+func Direct() DialContextFunc {
+	return Bound(nil)
+}
+
+// Proxy returns a dialer that connects through the proxy at rawURL, reached with
+// forward. Supported schemes are socks5 and socks5h (SOCKS5, optionally with
+// user:password) and http (HTTP CONNECT, optionally with basic auth).
+func Proxy(rawURL string, forward DialContextFunc) (DialContextFunc, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("proxy %q: %w", rawURL, err)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy %q: missing host", rawURL)
+	}
+	switch u.Scheme {
+	case "socks5", "socks5h":
+		d, err := proxy.FromURL(u, forwardDialer(forward))
+		if err != nil {
+			return nil, fmt.Errorf("proxy %q: %w", rawURL, err)
+		}
+		return d.(proxy.ContextDialer).DialContext, nil
+	case "http":
+		return httpConnect(u, forward), nil
+	default:
+		return nil, fmt.Errorf("proxy %q: unsupported scheme %q (use socks5, socks5h or http)", rawURL, u.Scheme)
+	}
+}
+
+// forwardDialer adapts a DialContextFunc to the proxy package's dialer interfaces.
+type forwardDialer DialContextFunc
+
+func (f forwardDialer) Dial(network, addr string) (net.Conn, error) {
+	return f(context.Background(), network, addr)
+}
+
+func (f forwardDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return f(ctx, network, addr)
+}
+
+// httpConnect tunnels each connection through an HTTP proxy with CONNECT.
+func httpConnect(u *url.URL, forward DialContextFunc) DialContextFunc {
+	proxyAddr := u.Host
+	if u.Port() == "" {
+		proxyAddr = net.JoinHostPort(u.Hostname(), "80")
+	}
+	var auth string
+	if u.User != nil {
+		p, _ := u.User.Password()
+		auth = "Basic " + base64.StdEncoding.EncodeToString([]byte(u.User.Username()+":"+p))
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		c, err := forward(ctx, network, proxyAddr)
+		if err != nil {
+			return nil, fmt.Errorf("connect to proxy %s: %w", proxyAddr, err)
+		}
+		if dl, ok := ctx.Deadline(); ok {
+			_ = c.SetDeadline(dl)
+		}
+		req := &http.Request{
+			Method: http.MethodConnect,
+			URL:    &url.URL{Opaque: addr},
+			Host:   addr,
+			Header: http.Header{},
+		}
+		if auth != "" {
+			req.Header.Set("Proxy-Authorization", auth)
+		}
+		if err := req.Write(c); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("proxy %s: %w", proxyAddr, err)
+		}
+		br := bufio.NewReader(c)
+		resp, err := http.ReadResponse(br, req)
+		if err != nil {
+			c.Close()
+			return nil, fmt.Errorf("proxy %s: %w", proxyAddr, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			c.Close()
+			return nil, fmt.Errorf("proxy %s: CONNECT %s: %s", proxyAddr, addr, resp.Status)
+		}
+		_ = c.SetDeadline(time.Time{})
+		if br.Buffered() > 0 {
+			return &bufferedConn{Conn: c, r: br}, nil
+		}
+		return c, nil
+	}
+}
+
+// bufferedConn returns bytes the proxy sent right after its CONNECT response
+// before reading from the connection again.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (b *bufferedConn) Read(p []byte) (int, error) { return b.r.Read(p) }
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package netdial
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+)
+
+// connectProxy accepts one CONNECT request, checks it and then echoes the tunnel.
+func connectProxy(t *testing.T, wantTarget, wantAuth string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		br := bufio.NewReader(c)
+		req, err := http.ReadRequest(br)
+		if err != nil {
+			return
+		}
+		if req.Method != http.MethodConnect || req.Host != wantTarget || req.Header.Get("Proxy-Authorization") != wantAuth {
+			_, _ = io.WriteString(c, "HTTP/1.1 403 Forbidden\r\n\r\n")
+			return
+		}
+		_, _ = io.WriteString(c, "HTTP/1.1 200 Connection established\r\n\r\n")
+		_, _ = io.Copy(c, br)
+	}()
+	return ln.Addr().String()
+}
+
+func TestProxyHTTPConnect(t *testing.T) {
+	addr := connectProxy(t, "10.1.0.2:443", "Basic YWRtaW46c2VjcmV0")
+	dial, err := Proxy("http://admin:secret@"+addr, Direct())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := dial(context.Background(), "tcp", "10.1.0.2:443")
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer c.Close()
+	if _, err := io.WriteString(c, "ping"); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(c, buf); err != nil || string(buf) != "ping" {
+		t.Fatalf("echo = %q, %v", buf, err)
+	}
+}
+
+func TestProxyHTTPConnectRefused(t *testing.T) {
+	addr := connectProxy(t, "10.1.0.2:443", "")
+	dial, err := Proxy("http://"+addr, Direct())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dial(context.Background(), "tcp", "10.9.9.9:443"); err == nil {
+		t.Fatal("expected error for refused CONNECT")
+	}
+}
+
+func TestProxyScheme(t *testing.T) {
+	for _, u := range []string{"socks5://127.0.0.1:1080", "socks5h://u:p@gw:1080", "http://gw:3128"} {
+		if _, err := Proxy(u, Direct()); err != nil {
+			t.Errorf("%s: %v", u, err)
+		}
+	}
+	for _, u := range []string{"https://gw:3128", "gw:3128", "socks5://"} {
+		if _, err := Proxy(u, Direct()); err == nil {
+			t.Errorf("%s: expected error", u)
+		}
+	}
+}
+
+func TestParseJump(t *testing.T) {
+	cases := []struct{ spec, user, addr string }{
+		{"admin@gw", "admin", "gw:22"},
+		{"admin@gw:2222", "admin", "gw:2222"},
+		{"root@[fd00::1]", "root", "[fd00::1]:22"},
+	}
+	for _, c := range cases {
+		user, addr, err := ParseJump(c.spec)
+		if err != nil || user != c.user || addr != c.addr {
+			t.Errorf("ParseJump(%q) = %q, %q, %v", c.spec, user, addr, err)
+		}
+	}
+	if _, _, err := ParseJump("gw"); err == nil {
+		t.Error("expected error without user")
+	}
+}
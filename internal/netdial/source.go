@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package netdial
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// SourceRule sends connections to addresses in Subnet from the local address
+// Source. A nil Subnet matches every destination.
+type SourceRule struct {
+	Subnet *net.IPNet
+	Source net.IP
+}
+
+// ParseSourceIP parses a --source-ip value: IP, or CIDR=IP to use IP only for
+// destinations in CIDR.
+func ParseSourceIP(v string) (SourceRule, error) {
+	subnet, addr, err := splitRule(v)
+	if err != nil {
+		return SourceRule{}, err
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return SourceRule{}, fmt.Errorf("source IP %q: not an IP address", addr)
+	}
+	return SourceRule{Subnet: subnet, Source: ip}, nil
+}
+
+// ParseInterface parses an --interface value: NAME, or CIDR=NAME to use the
+// interface only for destinations in CIDR. The rule uses the first IPv4 address
+// of the interface.
+func ParseInterface(v string) (SourceRule, error) {
+	subnet, name, err := splitRule(v)
+	if err != nil {
+		return SourceRule{}, err
+	}
+	ifc, err := net.InterfaceByName(name)
+	if err != nil {
+		return SourceRule{}, fmt.Errorf("interface %q: %w", name, err)
+	}
+	addrs, err := ifc.Addrs()
+	if err != nil {
+		return SourceRule{}, fmt.Errorf("interface %q: %w", name, err)
+	}
+	for _, a := range addrs {
+		if n, ok := a.(*net.IPNet); ok && n.IP.To4() != nil {
+			return SourceRule{Subnet: subnet, Source: n.IP}, nil
+		}
+	}
+	return SourceRule{}, fmt.Errorf("interface %q has no IPv4 address", name)
+}
+
+func splitRule(v string) (*net.IPNet, string, error) {
+	cidr, val, ok := strings.Cut(v, "=")
+	if !ok {
+		return nil, strings.TrimSpace(v), nil
+	}
+	_, subnet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+	if err != nil {
+		return nil, "", err
+	}
+	return subnet, strings.TrimSpace(val), nil
+}
+
+// Bound returns a direct dialer that binds each connection to the source of the
+// most specific rule matching its destination. Destinations given by name only
+// match rules without a subnet; with no matching rule the kernel picks the source.
+func Bound(rules []SourceRule) DialContextFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		d := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		if src := sourceFor(rules, addr); src != nil {
+			d.LocalAddr = &net.TCPAddr{IP: src}
+		}
+		return d.DialContext(ctx, network, addr)
+	}
+}
+
+func sourceFor(rules []SourceRule, addr string) net.IP {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	dst := net.ParseIP(host)
+	var best net.IP
+	bestLen := -1
+	for _, r := range rules {
+		size := 0
+		if r.Subnet != nil {
+			if dst == nil || !r.Subnet.Contains(dst) {
+				continue
+			}
+			size, _ = r.Subnet.Mask.Size()
+		}
+		if size > bestLen {
+			best, bestLen = r.Source, size
+		}
+	}
+	return best
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package netdial
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestSourceFor(t *testing.T) {
+	var rules []SourceRule
+	for _, v := range []string{"192.168.0.5", "10.1.0.0/16=10.1.255.1", "10.1.2.0/24=10.1.2.250"} {
+		r, err := ParseSourceIP(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rules = append(rules, r)
+	}
+	cases := map[string]string{
+		"10.1.2.3:443":   "10.1.2.250",
+		"10.1.9.9:443":   "10.1.255.1",
+		"172.16.0.1:443": "192.168.0.5",
+		"bmc-a:443":      "192.168.0.5",
+	}
+	for addr, want := range cases {
+		if got := sourceFor(rules, addr); got.String() != want {
+			t.Errorf("sourceFor(%s) = %s, want %s", addr, got, want)
+		}
+	}
+	if got := sourceFor(rules[1:], "bmc-a:443"); got != nil {
+		t.Errorf("name with subnet rules only = %s, want none", got)
+	}
+	if _, err := ParseSourceIP("10.0.0.0/8=eth0"); err == nil {
+		t.Error("expected error for non-IP source")
+	}
+}
+
+func TestBoundUsesSource(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	got := make(chan string, 1)
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		got <- c.RemoteAddr().(*net.TCPAddr).IP.String()
+		c.Close()
+	}()
+	r, err := ParseInterface("127.0.0.0/8=lo")
+	if err != nil {
+		t.Skipf("no loopback interface: %v", err)
+	}
+	c, err := Bound([]SourceRule{r})(context.Background(), "tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if ip := <-got; ip != r.Source.String() {
+		t.Errorf("connection from %s, want %s", ip, r.Source)
+	}
+}
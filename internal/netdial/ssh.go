@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package netdial
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SSHJump forwards connections through an SSH jump host (like ssh -J). The SSH
+// connection is opened on the first dial and shared by all later ones; it is
+// reopened if the jump host drops it.
+type SSHJump struct {
+	addr    string
+	config  *ssh.ClientConfig
+	forward DialContextFunc
+
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+// NewSSHJump returns a jump dialer for spec (user@host[:port], port 22 by
+// default), reached with forward. It authenticates with the keys of a running
+// ssh-agent and with keyFile, or the default ~/.ssh/id_* keys when keyFile is
+// empty. The jump host key must be listed in knownHostsFile (default
+// ~/.ssh/known_hosts).
+func NewSSHJump(spec, keyFile, knownHostsFile string, forward DialContextFunc) (*SSHJump, error) {
+	user, addr, err := ParseJump(spec)
+	if err != nil {
+		return nil, err
+	}
+	hostKeys, err := KnownHosts(knownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("ssh jump: %w", err)
+	}
+	config, err := SSHConfig(user, keyFile, hostKeys)
+	if err != nil {
+		return nil, err
+	}
+	return &SSHJump{addr: addr, config: config, forward: forward}, nil
+}
+
+// KnownHosts returns a host key callback that accepts the keys listed in file
+// (default ~/.ssh/known_hosts).
+func KnownHosts(file string) (ssh.HostKeyCallback, error) {
+	if file == "" {
+		home, _ := os.UserHomeDir()
+		file = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	cb, err := knownhosts.New(file)
+	if err != nil {
+		return nil, fmt.Errorf("known hosts: %w", err)
+	}
+	return cb, nil
+}
+
+// SSHConfig returns an SSH client config for user that authenticates with the
+// keys of a running ssh-agent and with keyFile, or the default ~/.ssh/id_* keys
+// when keyFile is empty, and checks host keys with hostKeys.
+func SSHConfig(user, keyFile string, hostKeys ssh.HostKeyCallback) (*ssh.ClientConfig, error) {
+	home, _ := os.UserHomeDir()
+	auth, err := authMethods(keyFile, home)
+	if err != nil {
+		return nil, err
+	}
+	return &ssh.ClientConfig{User: user, Auth: auth, HostKeyCallback: hostKeys}, nil
+}
+
+// ParseJump splits a jump host spec user@host[:port] into the user and a
+// host:port address.
+func ParseJump(spec string) (user, addr string, err error) {
+	user, host, ok := strings.Cut(spec, "@")
+	if !ok || user == "" || host == "" {
+		return "", "", fmt.Errorf("ssh jump %q: want user@host[:port]", spec)
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(strings.Trim(host, "[]"), "22")
+	}
+	return user, host, nil
+}
+
+func authMethods(keyFile, home string) ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+	files := []string{keyFile}
+	if keyFile == "" {
+		files = nil
+		for _, name := range []string{"id_ed25519", "id_ecdsa", "id_rsa"} {
+			files = append(files, filepath.Join(home, ".ssh", name))
+		}
+	}
+	var signers []ssh.Signer
+	for _, f := range files {
+		raw, err := os.ReadFile(f)
+		if err != nil {
+			if keyFile == "" && errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("ssh: %w", err)
+		}
+		s, err := ssh.ParsePrivateKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("ssh: key %s: %w (passphrase-protected keys must be loaded into ssh-agent)", f, err)
+		}
+		signers = append(signers, s)
+	}
+	if len(signers) > 0 {
+		methods = append(methods, ssh.PublicKeys(signers...))
+	}
+	if len(methods) == 0 {
+		return nil, errors.New("ssh: no ssh-agent and no private key found (load one into ssh-agent or name a key file)")
+	}
+	return methods, nil
+}
+
+// DialContext opens a connection to addr from the jump host.
+func (j *SSHJump) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	c, err := j.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.DialContext(ctx, network, addr)
+}
+
+func (j *SSHJump) connect(ctx context.Context) (*ssh.Client, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.client != nil {
+		return j.client, nil
+	}
+	conn, err := j.forward(ctx, "tcp", j.addr)
+	if err != nil {
+		return nil, fmt.Errorf("ssh jump %s: %w", j.addr, err)
+	}
+	if dl, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(dl)
+	}
+	sc, chans, reqs, err := ssh.NewClientConn(conn, j.addr, j.config)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("ssh jump %s: %w", j.addr, err)
+	}
+	_ = conn.SetDeadline(time.Time{})
+	c := ssh.NewClient(sc, chans, reqs)
+	j.client = c
+	go func() {
+		_ = c.Wait()
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		if j.client == c {
+			j.client = nil
+		}
+	}()
+	return c, nil
+}
+
+// Close closes the SSH connection to the jump host, if open.
+func (j *SSHJump) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.client == nil {
+		return nil
+	}
+	err := j.client.Close()
+	j.client = nil
+	return err
+}
@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package notify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"bootstrap/internal/redact"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is a notification config file: the sinks to notify and when.
+type Config struct {
+	Webhooks []WebhookConfig `yaml:"webhooks"`
+	Email    []EmailConfig   `yaml:"email"`
+	Syslog   []SyslogConfig  `yaml:"syslog"`
+	// FailureThreshold is a host count (5) or a share of hosts (10%) that
+	// triggers an early notification.
+	FailureThreshold string `yaml:"failure_threshold"`
+
+	dir string // relative template paths are resolved against it
+}
+
+// WebhookConfig configures a Hook.
+type WebhookConfig struct {
+	URL      string `yaml:"url"`
+	Format   string `yaml:"format"` // json or slack; default from the URL
+	Template string `yaml:"template"`
+}
+
+// EmailConfig configures a Mail sink.
+type EmailConfig struct {
+	SMTP     string   `yaml:"smtp"` // relay host:port
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+	Username string   `yaml:"username"`
+	// PasswordEnv names the environment variable holding the SMTP password, so
+	// the file itself carries no secret.
+	PasswordEnv string `yaml:"password_env"`
+	Template    string `yaml:"template"`
+}
+
+// SyslogConfig configures a Syslog sink.
+type SyslogConfig struct {
+	Network  string `yaml:"network"` // udp or tcp; empty for the local daemon
+	Address  string `yaml:"address"`
+	Tag      string `yaml:"tag"`
+	Facility string `yaml:"facility"` // default user
+	Template string `yaml:"template"`
+}
+
+// LoadConfig reads a notification config file.
+func LoadConfig(path string) (Config, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	var c Config
+	if err := yaml.Unmarshal(raw, &c); err != nil {
+		return Config{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	c.dir = filepath.Dir(path)
+	return c, nil
+}
+
+// Notifiers validates the config and returns its sinks.
+func (c Config) Notifiers() ([]Notifier, error) {
+	var out []Notifier
+	for i, w := range c.Webhooks {
+		if !strings.HasPrefix(w.URL, "http://") && !strings.HasPrefix(w.URL, "https://") {
+			return nil, fmt.Errorf("webhooks[%d]: url must be an http:// or https:// URL", i)
+		}
+		format := w.Format
+		switch format {
+		case "":
+			format = FormatFor(w.URL)
+		case FormatJSON, FormatSlack:
+		default:
+			return nil, fmt.Errorf("webhooks[%d]: format must be json or slack", i)
+		}
+		body, err := c.template(w.Template)
+		if err != nil {
+			return nil, fmt.Errorf("webhooks[%d]: %w", i, err)
+		}
+		out = append(out, Hook{URL: w.URL, Format: format, Body: body})
+	}
+	for i, e := range c.Email {
+		if e.SMTP == "" || e.From == "" || len(e.To) == 0 {
+			return nil, fmt.Errorf("email[%d]: smtp, from and to are required", i)
+		}
+		m := Mail{Addr: e.SMTP, From: e.From, To: e.To, Username: e.Username}
+		if e.PasswordEnv != "" {
+			if m.Password = os.Getenv(e.PasswordEnv); m.Password != "" {
+				redact.Add(m.Password)
+			} else {
+				return nil, fmt.Errorf("email[%d]: $%s is not set", i, e.PasswordEnv)
+			}
+		}
+		var err error
+		if m.Body, err = c.template(e.Template); err != nil {
+			return nil, fmt.Errorf("email[%d]: %w", i, err)
+		}
+		out = append(out, m)
+	}
+	for i, s := range c.Syslog {
+		if (s.Network == "") != (s.Address == "") {
+			return nil, fmt.Errorf("syslog[%d]: network and address go together (leave both empty for the local daemon)", i)
+		}
+		l := Syslog{Network: s.Network, Addr: s.Address, Tag: s.Tag, Facility: Facilities["user"]}
+		if s.Facility != "" {
+			f, ok := Facilities[strings.ToLower(s.Facility)]
+			if !ok {
+				return nil, fmt.Errorf("syslog[%d]: unknown facility %q", i, s.Facility)
+			}
+			l.Facility = f
+		}
+		var err error
+		if l.Body, err = c.template(s.Template); err != nil {
+			return nil, fmt.Errorf("syslog[%d]: %w", i, err)
+		}
+		out = append(out, l)
+	}
+	return out, nil
+}
+
+// template parses the template file at path (nil when path is empty).
+func (c Config) template(path string) (*template.Template, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(c.dir, path)
+	}
+	return ParseTemplate(path)
+}
+
+// ParseTemplate parses a text/template file rendering a message from a Summary.
+func ParseTemplate(path string) (*template.Template, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return template.New(filepath.Base(path)).Parse(string(raw))
+}
@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package notify
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeSMTP accepts one message and sends its DATA on the returned channel.
+func fakeSMTP(t *testing.T) (string, chan string) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { lis.Close() })
+	data := make(chan string, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		rd := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) } //nolint:errcheck
+		reply("220 localhost ESMTP")
+		var msg strings.Builder
+		inData := false
+		for {
+			line, err := rd.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch {
+			case inData && line == ".\r\n":
+				inData = false
+				data <- msg.String()
+				reply("250 OK")
+			case inData:
+				msg.WriteString(line)
+			case strings.HasPrefix(line, "EHLO"):
+				reply("250 localhost")
+			case strings.HasPrefix(line, "DATA"):
+				inData = true
+				reply("354 go ahead")
+			case strings.HasPrefix(line, "QUIT"):
+				reply("221 bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return lis.Addr().String(), data
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestConfigSinks(t *testing.T) {
+	smtpAddr, mails := fakeSMTP(t)
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer udp.Close()
+
+	dir := t.TempDir()
+	writeFile(t, dir, "mail.tmpl", "Rollout {{.Status}}: {{.Failed}} failed\n")
+	path := writeFile(t, dir, "notify.yaml", `
+email:
+  - smtp: `+smtpAddr+`
+    from: bootstrap@example.com
+    to: [ops@example.com, oncall@example.com]
+    template: mail.tmpl
+syslog:
+  - network: udp
+    address: `+udp.LocalAddr().String()+`
+    tag: ochami_bootstrap
+    facility: local3
+failure_threshold: 10%
+`)
+	c, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.FailureThreshold != "10%" {
+		t.Errorf("failure_threshold = %q", c.FailureThreshold)
+	}
+	sinks, err := c.Notifiers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sinks) != 2 {
+		t.Fatalf("got %d sinks, want 2", len(sinks))
+	}
+	s := Summary{Operation: "firmware", Event: EventFinished, Status: "partial", Total: 2, Completed: 2, Failed: 1,
+		FailedHosts: []HostError{{Host: "10.0.0.2", Error: "boom"}}, Runner: "admin1", Duration: "1m0s"}
+	for _, n := range sinks {
+		if err := n.Notify(context.Background(), s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	msg := <-mails
+	for _, want := range []string{"To: ops@example.com, oncall@example.com\r\n", "Subject: [admin1] firmware partial\r\n", "\r\n\r\nRollout partial: 1 failed\r\n"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("mail %q lacks %q", msg, want)
+		}
+	}
+
+	buf := make([]byte, 1024)
+	n, _, err := udp.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// local3 (19) * 8 + warning (4)
+	if line := string(buf[:n]); !strings.HasPrefix(line, "<156>") || !strings.Contains(line, "ochami_bootstrap") ||
+		!strings.Contains(line, "firmware on admin1 partial after 1m0s: 2 of 2 host(s) done, 1 failed; failed: 10.0.0.2") {
+		t.Errorf("syslog line = %q", line)
+	}
+}
+
+func TestConfigValidates(t *testing.T) {
+	dir := t.TempDir()
+	cases := map[string]string{
+		"webhook url":     "webhooks: [{url: ftp://x}]",
+		"webhook format":  "webhooks: [{url: 'http://x', format: xml}]",
+		"email fields":    "email: [{smtp: 'mail:25'}]",
+		"email password":  "email: [{smtp: 'mail:25', from: a@b, to: [c@d], password_env: NOTIFY_TEST_UNSET}]",
+		"syslog address":  "syslog: [{network: udp}]",
+		"syslog facility": "syslog: [{facility: kern2}]",
+		"template":        "syslog: [{template: missing.tmpl}]",
+	}
+	for name, body := range cases {
+		c, err := LoadConfig(writeFile(t, dir, "notify.yaml", body))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if _, err := c.Notifiers(); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
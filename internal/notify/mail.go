@@ -0,0 +1,81 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Mail sends summaries by email through an SMTP relay. It implements Notifier.
+type Mail struct {
+	Addr     string // relay host:port
+	From     string
+	To       []string
+	Username string // PLAIN auth when set; needs TLS unless the relay is localhost
+	Password string
+	Body     *template.Template // renders the message text; nil for the default
+}
+
+// mailSubject is the Subject of every message.
+var mailSubject = template.Must(template.New("subject").Parse(
+	`[{{.Runner}}] {{.Operation}} {{if eq .Event "threshold"}}failure threshold reached{{else}}{{.Status}}{{end}}`))
+
+// message returns the RFC 5322 message for s.
+func (m Mail) message(s Summary) ([]byte, error) {
+	subject, err := render(s, mailSubject)
+	if err != nil {
+		return nil, err
+	}
+	body, err := render(s, headline, details)
+	if m.Body != nil {
+		body, err = render(s, m.Body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", m.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(m.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: 8bit\r\n\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.TrimRight(body, "\n"), "\n", "\r\n"))
+	buf.WriteString("\r\n")
+	return buf.Bytes(), nil
+}
+
+// Notify sends s to the recipients. smtp.SendMail uses STARTTLS when the relay
+// offers it.
+func (m Mail) Notify(ctx context.Context, s Summary) error {
+	msg, err := m.message(s)
+	if err != nil {
+		return fmt.Errorf("email body: %w", err)
+	}
+	var auth smtp.Auth
+	if m.Username != "" {
+		host, _, _ := net.SplitHostPort(m.Addr)
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+	// SendMail has no context; give up waiting once ctx is done
+	done := make(chan error, 1)
+	go func() { done <- smtp.SendMail(m.Addr, auth, m.From, m.To, msg) }()
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err != nil {
+		return fmt.Errorf("email via %s: %w", m.Addr, err)
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package notify sends run summaries to notification sinks: webhooks (Slack
+// incoming webhooks or any endpoint accepting JSON), email and syslog.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"bootstrap/internal/redact"
+)
+
+// Events
+const (
+	EventFinished  = "finished"  // the run is over
+	EventThreshold = "threshold" // the failure threshold was reached mid-run
+)
+
+// Summary describes a run, or its state so far for EventThreshold.
+type Summary struct {
+	Operation   string         `json:"operation"` // discover, firmware, ...
+	Event       string         `json:"event"`
+	Status      string         `json:"status"` // succeeded, partial, failed, interrupted; running for EventThreshold
+	Error       string         `json:"error,omitempty"`
+	Total       int            `json:"total"`
+	Completed   int            `json:"completed"`
+	Failed      int            `json:"failed"`
+	Counts      map[string]int `json:"counts"` // hosts per outcome, e.g. updated: 40, skipped: 2
+	FailedHosts []HostError    `json:"failed_hosts,omitempty"`
+	Started     time.Time      `json:"started"`
+	Duration    string         `json:"duration"`
+	Runner      string         `json:"runner"` // host name of the machine running the command
+}
+
+// HostError is a failed host and why.
+type HostError struct {
+	Host  string `json:"host"`
+	Error string `json:"error"`
+}
+
+// Notifier is a notification sink.
+type Notifier interface {
+	Notify(ctx context.Context, s Summary) error
+}
+
+// Formats
+const (
+	FormatJSON  = "json"
+	FormatSlack = "slack"
+)
+
+// Hook is a webhook endpoint. It implements Notifier.
+type Hook struct {
+	URL    string
+	Format string             // json or slack; see FormatFor
+	Body   *template.Template // renders the request body from a Summary; nil for the default
+}
+
+// FormatFor returns the default format for a webhook URL: slack for Slack
+// incoming webhooks, json otherwise.
+func FormatFor(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && strings.EqualFold(u.Hostname(), "hooks.slack.com") {
+		return FormatSlack
+	}
+	return FormatJSON
+}
+
+// headline is the one-line form of a summary, used by syslog and as the first
+// line of the default Slack and email messages.
+var headline = template.Must(template.New("headline").Parse(
+	`{{if eq .Event "threshold"}}{{.Operation}} on {{.Runner}} has {{.Failed}} failed host(s) of {{.Total}} ({{.Completed}} done so far){{else}}{{.Operation}} on {{.Runner}} {{.Status}} after {{.Duration}}: {{.Completed}} of {{.Total}} host(s) done, {{.Failed}} failed{{end}}`))
+
+// details lists the outcome counts and failed hosts, one per line.
+var details = template.Must(template.New("details").Parse(
+	`{{range $state, $n := .Counts}}
+• {{$state}}: {{$n}}{{end}}
+{{- if .FailedHosts}}
+Failed hosts:{{range .FailedHosts}}
+• {{.Host}}: {{.Error}}{{end}}{{end}}`))
+
+// render executes the templates in turn on s.
+func render(s Summary, ts ...*template.Template) (string, error) {
+	var buf bytes.Buffer
+	for _, t := range ts {
+		if err := t.Execute(&buf, s); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
+
+// slackIcon marks a Slack message by outcome.
+func slackIcon(s Summary) string {
+	switch {
+	case s.Event == EventThreshold:
+		return ":warning: "
+	case s.Status == "succeeded":
+		return ":white_check_mark: "
+	}
+	return ":x: "
+}
+
+// body returns the request body for s.
+func (h Hook) body(s Summary) ([]byte, error) {
+	switch {
+	case h.Body != nil && h.Format == FormatSlack:
+		text, err := render(s, h.Body)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]string{"text": text})
+	case h.Body != nil:
+		text, err := render(s, h.Body)
+		return []byte(text), err
+	case h.Format == FormatSlack:
+		text, err := render(s, headline, details)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]string{"text": slackIcon(s) + text})
+	}
+	return json.Marshal(s)
+}
+
+// Notify posts s to the hook.
+func (h Hook) Notify(ctx context.Context, s Summary) error {
+	body, err := h.body(s)
+	if err != nil {
+		return fmt.Errorf("webhook body: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook %s: %s: %s", redactURL(h.URL), resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// redactURL drops the path and query of a webhook URL, which usually carry its
+// secret, for error messages.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "(invalid URL)"
+	}
+	return u.Scheme + "://" + u.Host + "/..."
+}
+
+// Tracker counts per-host outcomes of a run and notifies the sinks when the
+// failure threshold is first reached and when the run finishes. A nil Tracker
+// does nothing.
+type Tracker struct {
+	sinks     []Notifier
+	op        string
+	total     int
+	threshold int // failed hosts that trigger EventThreshold; 0 disables it
+	started   time.Time
+	sent      chan struct{} // threshold notification done
+
+	mu        sync.Mutex
+	counts    map[string]int
+	failed    []HostError
+	completed int
+	fired     bool
+}
+
+// NewTracker returns a Tracker for op over total hosts, or nil without sinks.
+func NewTracker(sinks []Notifier, op string, total, threshold int) *Tracker {
+	if len(sinks) == 0 {
+		return nil
+	}
+	return &Tracker{sinks: sinks, op: op, total: total, threshold: threshold, started: time.Now(), counts: map[string]int{}}
+}
+
+// Host records the outcome of one host; failed marks it as a failure.
+func (t *Tracker) Host(host, state string, failed bool, err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.completed++
+	t.counts[state]++
+	if !failed {
+		return
+	}
+	he := HostError{Host: host}
+	if err != nil {
+		he.Error = redact.String(err.Error())
+	}
+	t.failed = append(t.failed, he)
+	if t.threshold > 0 && len(t.failed) >= t.threshold && !t.fired {
+		t.fired = true
+		s := t.summary(EventThreshold, "running", nil)
+		t.sent = make(chan struct{})
+		go func() {
+			defer close(t.sent)
+			t.send(s)
+		}()
+	}
+}
+
+// Finish notifies the sinks that the run ended with err (nil on success),
+// after any pending threshold notification.
+func (t *Tracker) Finish(err error, interrupted bool) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	status := "succeeded"
+	switch {
+	case interrupted:
+		status = "interrupted"
+	case len(t.failed) > 0 && len(t.failed) < t.total:
+		status = "partial"
+	case err != nil:
+		status = "failed"
+	}
+	s, sent := t.summary(EventFinished, status, err), t.sent
+	t.mu.Unlock()
+	if sent != nil {
+		<-sent // keep the threshold notification first
+	}
+	t.send(s)
+}
+
+func (t *Tracker) summary(event, status string, err error) Summary {
+	host, _ := os.Hostname()
+	s := Summary{
+		Operation:   t.op,
+		Event:       event,
+		Status:      status,
+		Total:       t.total,
+		Completed:   t.completed,
+		Failed:      len(t.failed),
+		Counts:      make(map[string]int, len(t.counts)),
+		FailedHosts: slices.Clone(t.failed),
+		Started:     t.started.UTC(),
+		Duration:    time.Since(t.started).Round(time.Second).String(),
+		Runner:      host,
+	}
+	for k, v := range t.counts {
+		s.Counts[k] = v
+	}
+	if err != nil {
+		s.Error = redact.String(err.Error())
+	}
+	return s
+}
+
+// send passes s to every sink, warning about the ones that fail: a
+// notification never changes the outcome of the run.
+func (t *Tracker) send(s Summary) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	for _, n := range t.sinks {
+		if err := n.Notify(ctx, s); err != nil {
+			fmt.Fprintf(os.Stderr, "WARN: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+// receiver records the bodies POSTed to it.
+func receiver(t *testing.T, status int) (*httptest.Server, chan string) {
+	t.Helper()
+	bodies := make(chan string, 8)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("%s with Content-Type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		b, _ := io.ReadAll(r.Body)
+		bodies <- string(b)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(ts.Close)
+	return ts, bodies
+}
+
+func TestHookFormats(t *testing.T) {
+	ts, bodies := receiver(t, http.StatusOK)
+	s := Summary{Operation: "firmware", Event: EventFinished, Status: "partial", Total: 3, Completed: 3, Failed: 1,
+		Counts: map[string]int{"updated": 2, "failed": 1}, FailedHosts: []HostError{{Host: "10.0.0.3", Error: "boom"}}, Runner: "admin1", Duration: "6h0m0s"}
+
+	if err := (Hook{URL: ts.URL, Format: FormatJSON}).Notify(context.Background(), s); err != nil {
+		t.Fatal(err)
+	}
+	var got Summary
+	if err := json.Unmarshal([]byte(<-bodies), &got); err != nil || got.Failed != 1 || got.FailedHosts[0].Host != "10.0.0.3" {
+		t.Errorf("json body = %+v (%v)", got, err)
+	}
+
+	if err := (Hook{URL: ts.URL, Format: FormatSlack}).Notify(context.Background(), s); err != nil {
+		t.Fatal(err)
+	}
+	var msg struct{ Text string }
+	if err := json.Unmarshal([]byte(<-bodies), &msg); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"firmware on admin1 partial after 6h0m0s", "3 of 3 host(s) done, 1 failed", "• updated: 2", "• 10.0.0.3: boom"} {
+		if !strings.Contains(msg.Text, want) {
+			t.Errorf("slack text %q lacks %q", msg.Text, want)
+		}
+	}
+
+	tmpl := template.Must(template.New("t").Parse(`{"title": "{{.Operation}} {{.Status}}", "failed": {{.Failed}}}`))
+	if err := (Hook{URL: ts.URL, Format: FormatJSON, Body: tmpl}).Notify(context.Background(), s); err != nil {
+		t.Fatal(err)
+	}
+	if b := <-bodies; b != `{"title": "firmware partial", "failed": 1}` {
+		t.Errorf("templated body = %s", b)
+	}
+}
+
+func TestHookErrorRedactsURL(t *testing.T) {
+	ts, _ := receiver(t, http.StatusForbidden)
+	err := Hook{URL: ts.URL + "/services/T000/B000/secret", Format: FormatJSON}.Notify(context.Background(), Summary{})
+	if err == nil || !strings.Contains(err.Error(), "403") || strings.Contains(err.Error(), "secret") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestFormatFor(t *testing.T) {
+	if got := FormatFor("https://hooks.slack.com/services/T/B/X"); got != FormatSlack {
+		t.Errorf("slack URL: %s", got)
+	}
+	if got := FormatFor("https://ci.example.com/hook"); got != FormatJSON {
+		t.Errorf("other URL: %s", got)
+	}
+}
+
+func TestTracker(t *testing.T) {
+	ts, bodies := receiver(t, http.StatusOK)
+	tr := NewTracker([]Notifier{Hook{URL: ts.URL, Format: FormatJSON}}, "discover", 4, 2)
+	tr.Host("a", "discovered", false, nil)
+	tr.Host("b", "failed", true, errors.New("timeout"))
+	tr.Host("c", "failed", true, errors.New("401"))
+	tr.Host("d", "failed", true, errors.New("401")) // threshold already reported
+	tr.Finish(errors.New("3 of 4 host(s) failed"), false)
+	close(bodies)
+
+	var events []Summary
+	for b := range bodies {
+		var s Summary
+		if err := json.Unmarshal([]byte(b), &s); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, s)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d notifications, want 2: %+v", len(events), events)
+	}
+	th, fin := events[0], events[1]
+	if th.Event != EventThreshold || th.Status != "running" || th.Failed != 2 || th.Completed != 3 {
+		t.Errorf("threshold = %+v", th)
+	}
+	if fin.Event != EventFinished || fin.Status != "partial" || fin.Failed != 3 || fin.Counts["discovered"] != 1 || fin.Error == "" {
+		t.Errorf("finished = %+v", fin)
+	}
+
+	var none *Tracker
+	none.Host("a", "failed", true, nil)
+	none.Finish(nil, false)
+	if NewTracker(nil, "discover", 1, 0) != nil {
+		t.Error("NewTracker without sinks is not nil")
+	}
+}
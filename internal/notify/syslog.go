@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Syslog logs summaries to the local syslog daemon or a remote one. It
+// implements Notifier.
+type Syslog struct {
+	Network  string // udp, tcp or "" for the local daemon
+	Addr     string // host:port; empty for the local daemon
+	Tag      string
+	Facility Facility           // e.g. Facilities["local0"]
+	Body     *template.Template // renders the message; nil for the one-line default
+}
+
+// Facility is a syslog facility code as defined by RFC 5424, already shifted
+// into the priority value like log/syslog's LOG_* constants.
+type Facility int
+
+// Facilities maps facility names to their syslog values.
+var Facilities = map[string]Facility{
+	"user":   1 << 3,
+	"daemon": 3 << 3,
+	"local0": 16 << 3,
+	"local1": 17 << 3,
+	"local2": 18 << 3,
+	"local3": 19 << 3,
+	"local4": 20 << 3,
+	"local5": 21 << 3,
+	"local6": 22 << 3,
+	"local7": 23 << 3,
+}
+
+// severity is the syslog level a summary is logged at.
+type severity int
+
+const (
+	sevErr severity = iota
+	sevWarning
+	sevInfo
+)
+
+// Notify logs s: at info level when the run succeeded, warning when hosts
+// failed or the run was interrupted, and error when it failed outright.
+func (l Syslog) Notify(_ context.Context, s Summary) error {
+	msg, err := render(s, headline)
+	if l.Body != nil {
+		msg, err = render(s, l.Body)
+	}
+	if err != nil {
+		return fmt.Errorf("syslog message: %w", err)
+	}
+	if l.Body == nil && len(s.FailedHosts) > 0 {
+		hosts := make([]string, len(s.FailedHosts))
+		for i, h := range s.FailedHosts {
+			hosts[i] = h.Host
+		}
+		msg += "; failed: " + strings.Join(hosts, ", ")
+	}
+	sev := sevWarning
+	switch {
+	case s.Event == EventFinished && s.Status == "succeeded":
+		sev = sevInfo
+	case s.Event == EventFinished && s.Status == "failed":
+		sev = sevErr
+	}
+	if err := l.send(sev, msg); err != nil {
+		return fmt.Errorf("syslog: %w", err)
+	}
+	return nil
+}
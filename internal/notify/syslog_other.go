@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+//go:build windows || plan9
+
+package notify
+
+import (
+	"errors"
+	"runtime"
+)
+
+func (l Syslog) send(severity, string) error {
+	return errors.New("not supported on " + runtime.GOOS + "; use a webhook or email sink instead")
+}
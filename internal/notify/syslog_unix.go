@@ -0,0 +1,24 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+//go:build !windows && !plan9
+
+package notify
+
+import "log/syslog"
+
+func (l Syslog) send(sev severity, msg string) error {
+	w, err := syslog.Dial(l.Network, l.Addr, syslog.Priority(l.Facility)|syslog.LOG_INFO, l.Tag)
+	if err != nil {
+		return err
+	}
+	defer w.Close() //nolint:errcheck
+	switch sev {
+	case sevInfo:
+		return w.Info(msg)
+	case sevErr:
+		return w.Err(msg)
+	}
+	return w.Warning(msg)
+}
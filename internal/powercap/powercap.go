@@ -0,0 +1,159 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package powercap reads power cap policy files and picks the Redfish power
+// limit a node or chassis is capped through.
+//
+// A policy is an ordered list of rules, each giving the node or chassis xnames
+// it matches (a glob such as x1000c0s*b*n*, or a node alias) a limit in watts.
+// When several rules match, the last one wins, so a file can start broad and
+// narrow down:
+//
+//	caps:
+//	  - match: "x1000c*s*b*n*"
+//	    watts: 550
+//	  - match: x1000c0s0b0n0
+//	    watts: 0        # no cap
+//	  - match: x1000c0
+//	    watts: 60000
+package powercap
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"bootstrap/internal/xname"
+	"bootstrap/pkg/inventory"
+	"bootstrap/pkg/redfish"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule caps the nodes or chassis matching Match at Watts; 0 removes the cap.
+type Rule struct {
+	Match string  `yaml:"match"`
+	Watts float64 `yaml:"watts"`
+}
+
+// Policy is the root of a power cap policy file.
+type Policy struct {
+	Caps []Rule `yaml:"caps"`
+}
+
+// Target is a node or chassis the policy caps.
+type Target struct {
+	Xname   string
+	Chassis bool // a chassis xname (x1000c0) rather than a node
+	Watts   float64
+}
+
+// Load reads and checks a policy file.
+func Load(file string) (Policy, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return Policy{}, err
+	}
+	p, err := Parse(raw)
+	if err != nil {
+		return Policy{}, fmt.Errorf("%s: %w", file, err)
+	}
+	return p, nil
+}
+
+// Parse decodes and checks a policy.
+func Parse(raw []byte) (Policy, error) {
+	var p Policy
+	if err := yaml.Unmarshal(raw, &p); err != nil {
+		return Policy{}, err
+	}
+	if len(p.Caps) == 0 {
+		return Policy{}, fmt.Errorf("no caps")
+	}
+	for i, r := range p.Caps {
+		if r.Match == "" {
+			return Policy{}, fmt.Errorf("caps[%d]: match is empty", i)
+		}
+		if _, err := path.Match(strings.ToLower(r.Match), ""); err != nil {
+			return Policy{}, fmt.Errorf("caps[%d]: match %q: %w", i, r.Match, err)
+		}
+		if r.Watts < 0 {
+			return Policy{}, fmt.Errorf("caps[%d]: watts must not be negative", i)
+		}
+	}
+	return p, nil
+}
+
+// rule returns the index of the last rule matching any of names, or -1.
+func (p Policy) rule(names ...string) int {
+	for i := len(p.Caps) - 1; i >= 0; i-- {
+		pattern := strings.ToLower(p.Caps[i].Match)
+		for _, n := range names {
+			if ok, _ := path.Match(pattern, strings.ToLower(n)); n != "" && ok {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Targets returns the nodes of doc the policy caps, in inventory order, then
+// the chassis of doc's nodes and BMCs it caps, in xname order. Nodes match on
+// xname or alias. unused lists the rules that matched nothing, usually a typo.
+func (p Policy) Targets(doc inventory.FileFormat) (targets []Target, unused []string) {
+	used := make([]bool, len(p.Caps))
+	var chassis []string
+	for _, n := range doc.Nodes {
+		if i := p.rule(n.Xname, n.Alias); i >= 0 {
+			used[i] = true
+			targets = append(targets, Target{Xname: n.Xname, Watts: p.Caps[i].Watts})
+		}
+	}
+	for _, list := range [][]inventory.Entry{doc.Nodes, doc.BMCs} {
+		for _, e := range list {
+			if c := xname.Chassis(strings.ToLower(e.Xname)); c != "" && !slices.Contains(chassis, c) {
+				chassis = append(chassis, c)
+			}
+		}
+	}
+	slices.Sort(chassis)
+	for _, c := range chassis {
+		if i := p.rule(c); i >= 0 {
+			used[i] = true
+			targets = append(targets, Target{Xname: c, Chassis: true, Watts: p.Caps[i].Watts})
+		}
+	}
+	for i, r := range p.Caps {
+		if !used[i] {
+			unused = append(unused, r.Match)
+		}
+	}
+	return targets, unused
+}
+
+// Select returns the limit a target is capped through: for a node, the limit
+// of the chassis holding its system (systemPath), or the only limit of a BMC
+// whose chassis link no systems; for a chassis (systemPath ""), the Enclosure
+// chassis of its chassis controller, or its only limit.
+func Select(limits []redfish.PowerLimit, systemPath string) (redfish.PowerLimit, error) {
+	var found []redfish.PowerLimit
+	for _, l := range limits {
+		if (systemPath != "" && slices.Contains(l.Systems, systemPath)) || (systemPath == "" && l.ChassisType == "Enclosure") {
+			found = append(found, l)
+		}
+	}
+	if len(found) == 0 && len(limits) == 1 && len(limits[0].Systems) == 0 {
+		found = limits
+	}
+	switch len(found) {
+	case 0:
+		return redfish.PowerLimit{}, fmt.Errorf("no power limit for %s (%d chassis with one)", cmp.Or(systemPath, "the enclosure"), len(limits))
+	case 1:
+		return found[0], nil
+	}
+	return redfish.PowerLimit{}, fmt.Errorf("%d chassis hold %s; cannot tell which to cap", len(found), cmp.Or(systemPath, "an enclosure"))
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package powercap
+
+import (
+	"reflect"
+	"testing"
+
+	"bootstrap/pkg/inventory"
+	"bootstrap/pkg/redfish"
+)
+
+func TestTargets(t *testing.T) {
+	p, err := Parse([]byte(`
+caps:
+  - match: "x1000c*s*b*n*"
+    watts: 550
+  - match: nid000002
+    watts: 0
+  - match: X1000C0
+    watts: 60000
+  - match: x9999c0s0b0n0
+    watts: 400
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := inventory.FileFormat{
+		BMCs: []inventory.Entry{{Xname: "x1000c1s0b0"}, {Xname: "x3000c0s9b0"}},
+		Nodes: []inventory.Entry{
+			{Xname: "x1000c0s0b0n0", Alias: "nid000001"},
+			{Xname: "x1000c0s0b0n1", Alias: "nid000002"},
+			{Xname: "x3000c0s9b0n0"},
+		},
+	}
+	targets, unused := p.Targets(doc)
+	want := []Target{
+		{Xname: "x1000c0s0b0n0", Watts: 550},
+		{Xname: "x1000c0s0b0n1", Watts: 0}, // the alias rule comes later
+		{Xname: "x1000c0", Chassis: true, Watts: 60000},
+	}
+	if !reflect.DeepEqual(targets, want) {
+		t.Errorf("targets = %+v\nwant %+v", targets, want)
+	}
+	if !reflect.DeepEqual(unused, []string{"x9999c0s0b0n0"}) {
+		t.Errorf("unused = %v", unused)
+	}
+
+	for _, bad := range []string{``, "caps:\n  - watts: 5\n", "caps:\n  - match: x1\n    watts: -1\n", "caps:\n  - match: \"x[\"\n    watts: 1\n"} {
+		if _, err := Parse([]byte(bad)); err == nil {
+			t.Errorf("%q: want error", bad)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	enc := redfish.PowerLimit{ChassisID: "Enclosure", ChassisType: "Enclosure"}
+	n0 := redfish.PowerLimit{ChassisID: "Node0", Systems: []string{"/redfish/v1/Systems/Node0"}}
+	n1 := redfish.PowerLimit{ChassisID: "Node1", Systems: []string{"/redfish/v1/Systems/Node1"}}
+	rack := redfish.PowerLimit{ChassisID: "1"}
+
+	if l, err := Select([]redfish.PowerLimit{n0, n1}, "/redfish/v1/Systems/Node1"); err != nil || l.ChassisID != "Node1" {
+		t.Errorf("node: %+v %v", l, err)
+	}
+	if l, err := Select([]redfish.PowerLimit{enc, n0}, ""); err != nil || l.ChassisID != "Enclosure" {
+		t.Errorf("enclosure: %+v %v", l, err)
+	}
+	if l, err := Select([]redfish.PowerLimit{rack}, "/redfish/v1/Systems/1"); err != nil || l.ChassisID != "1" {
+		t.Errorf("rack server without chassis links: %+v %v", l, err)
+	}
+	if _, err := Select([]redfish.PowerLimit{n0, n1}, "/redfish/v1/Systems/Node2"); err == nil {
+		t.Error("unknown system: want error")
+	}
+	if _, err := Select([]redfish.PowerLimit{enc, enc}, ""); err == nil {
+		t.Error("two enclosures: want error")
+	}
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package progress reports per-host progress of long-running commands as JSON
+// lines, for front ends (the gRPC API) that render it live.
+package progress
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+
+	"bootstrap/internal/redact"
+)
+
+// Started is the state of a host whose work has begun; the final state is
+// command specific (e.g. updated, skipped, failed, aborted for firmware).
+const Started = "started"
+
+// Event is one progress line.
+type Event struct {
+	Host      string `json:"host"`
+	State     string `json:"state"`
+	Message   string `json:"message,omitempty"`
+	Completed int    `json:"completed"` // hosts finished so far, including this one
+	Total     int    `json:"total"`
+}
+
+// Reporter writes the events of one run. A nil Reporter discards them, so
+// commands can report unconditionally.
+type Reporter struct {
+	mu        sync.Mutex
+	enc       *json.Encoder
+	total     int
+	completed int
+}
+
+// New returns a Reporter writing to w for a run over total hosts, or nil when
+// w is nil.
+func New(w io.Writer, total int) *Reporter {
+	if w == nil {
+		return nil
+	}
+	return &Reporter{enc: json.NewEncoder(w), total: total}
+}
+
+// Start reports that work on host has begun.
+func (r *Reporter) Start(host string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_ = r.enc.Encode(Event{Host: host, State: Started, Completed: r.completed, Total: r.total})
+}
+
+// Done reports that host finished in state. err, if set, becomes the message.
+func (r *Reporter) Done(host, state string, err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.completed++
+	e := Event{Host: host, State: state, Completed: r.completed, Total: r.total}
+	if err != nil {
+		e.Message = redact.String(err.Error())
+	}
+	_ = r.enc.Encode(e)
+}
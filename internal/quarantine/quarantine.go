@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package quarantine keeps the list of BMCs commands should leave alone: hosts
+// that failed several runs in a row, such as BMCs rejecting the credentials or
+// crash-looping, and hosts an operator set aside by hand. The list is a YAML
+// file shared by every run; updates take its lock.
+package quarantine
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"bootstrap/internal/safefile"
+)
+
+// Entry is one host of the list.
+type Entry struct {
+	Host     string    `yaml:"host"`               // BMC xname, or address when it has none
+	Failures int       `yaml:"failures,omitempty"` // failed runs in a row
+	Reason   string    `yaml:"reason,omitempty"`   // last failure, or why it was added
+	Since    time.Time `yaml:"since,omitempty"`    // when it was quarantined; zero while only failing
+	Manual   bool      `yaml:"manual,omitempty"`   // added by hand; only removing it releases it
+}
+
+// Quarantined reports whether the host is skipped.
+func (e Entry) Quarantined() bool { return !e.Since.IsZero() }
+
+// List is the content of a quarantine file.
+type List struct {
+	Hosts []Entry `yaml:"hosts"`
+}
+
+// Load reads the list at path; a missing file is an empty list.
+func Load(path string) (*List, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &List{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var l List
+	if err := yaml.Unmarshal(b, &l); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &l, nil
+}
+
+// Save writes l to path, creating its directory if needed.
+func (l *List) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	slices.SortFunc(l.Hosts, func(a, b Entry) int { return strings.Compare(a.Host, b.Host) })
+	b, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+	return safefile.Write(path, b, 0o644, 0)
+}
+
+// Update loads the list at path under its lock, applies fn and saves the result.
+func Update(path string, fn func(*List) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	unlock, err := safefile.Lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	l, err := Load(path)
+	if err != nil {
+		return err
+	}
+	if err := fn(l); err != nil {
+		return err
+	}
+	return l.Save(path)
+}
+
+// Get returns the entry of host.
+func (l *List) Get(host string) (Entry, bool) {
+	if i := l.index(host); i >= 0 {
+		return l.Hosts[i], true
+	}
+	return Entry{}, false
+}
+
+// Add quarantines host by hand.
+func (l *List) Add(host, reason string, now time.Time) {
+	e := l.entry(host)
+	e.Reason, e.Manual = reason, true
+	if !e.Quarantined() {
+		e.Since = now.UTC()
+	}
+}
+
+// Remove drops host from the list, and reports whether it was there.
+func (l *List) Remove(host string) bool {
+	i := l.index(host)
+	if i < 0 {
+		return false
+	}
+	l.Hosts = slices.Delete(l.Hosts, i, i+1)
+	return true
+}
+
+// Fail records a failed run of host, quarantining it once it failed after runs
+// in a row. It reports whether this failure quarantined the host.
+func (l *List) Fail(host, reason string, after int, now time.Time) bool {
+	e := l.entry(host)
+	e.Failures++
+	if !e.Manual {
+		e.Reason = reason
+	}
+	if e.Quarantined() || e.Failures < max(1, after) {
+		return false
+	}
+	e.Since = now.UTC()
+	return true
+}
+
+// Succeed records a run that reached host: its failures are forgotten and,
+// unless it was added by hand, it is released.
+func (l *List) Succeed(host string) {
+	if i := l.index(host); i >= 0 && !l.Hosts[i].Manual {
+		l.Hosts = slices.Delete(l.Hosts, i, i+1)
+	}
+}
+
+func (l *List) index(host string) int {
+	return slices.IndexFunc(l.Hosts, func(e Entry) bool { return strings.EqualFold(e.Host, host) })
+}
+
+// entry returns the entry of host, adding it when missing.
+func (l *List) entry(host string) *Entry {
+	i := l.index(host)
+	if i < 0 {
+		l.Hosts = append(l.Hosts, Entry{Host: strings.ToLower(host)})
+		i = len(l.Hosts) - 1
+	}
+	return &l.Hosts[i]
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package quarantine
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFailSucceedAndManual(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "quarantine.yaml")
+	now := time.Date(2025, 7, 1, 2, 0, 0, 0, time.UTC)
+	fail := func(host string) (quarantined bool) {
+		err := Update(path, func(l *List) error {
+			quarantined = l.Fail(host, "401 Unauthorized", 3, now)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return quarantined
+	}
+	for i := 1; i <= 2; i++ {
+		if fail("x1000c0s0b0") {
+			t.Fatalf("quarantined after %d failure(s)", i)
+		}
+	}
+	if !fail("x1000c0s0b0") {
+		t.Fatal("not quarantined after the third failure in a row")
+	}
+	if fail("x1000c0s0b0") {
+		t.Fatal("quarantined again")
+	}
+
+	l, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, ok := l.Get("X1000C0S0B0")
+	if !ok || !e.Quarantined() || e.Failures != 4 || e.Reason != "401 Unauthorized" || !e.Since.Equal(now) {
+		t.Fatalf("entry = %+v, %v", e, ok)
+	}
+
+	// A host added by hand stays through successes; the other is released
+	l.Add("10.0.0.9", "crash-looping", now)
+	l.Fail("10.0.0.9", "timeout", 3, now)
+	l.Succeed("x1000c0s0b0")
+	l.Succeed("10.0.0.9")
+	if _, ok := l.Get("x1000c0s0b0"); ok {
+		t.Fatal("success did not release the host")
+	}
+	if e, ok := l.Get("10.0.0.9"); !ok || !e.Manual || e.Reason != "crash-looping" {
+		t.Fatalf("manual entry = %+v, %v", e, ok)
+	}
+	if !l.Remove("10.0.0.9") || l.Remove("10.0.0.9") {
+		t.Fatal("Remove")
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	l, err := Load(filepath.Join(t.TempDir(), "none.yaml"))
+	if err != nil || len(l.Hosts) != 0 {
+		t.Fatalf("Load = %+v, %v", l, err)
+	}
+}
@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package redact removes secrets from text before it is logged, printed or
+// stored: passwords and tokens registered with Add, URL passwords,
+// Authorization header values, password/token fields of JSON and key=value
+// text, and PEM private keys.
+package redact
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Mask replaces each secret.
+const Mask = "[REDACTED]"
+
+// minLen is the shortest registered secret that is masked; shorter values
+// would mask unrelated text.
+const minLen = 4
+
+var (
+	mu      sync.RWMutex
+	secrets []string
+)
+
+var (
+	urlPassRe = regexp.MustCompile(`([A-Za-z][A-Za-z0-9+.-]*://[^/\s:@]*):([^@\s/]+)@`)
+	authRe    = regexp.MustCompile(`(?i)(authorization"?\s*[:=]\s*"?(?:bearer|basic)\s+)[^\s"]+`)
+	bearerRe  = regexp.MustCompile(`(?i)\b(bearer\s+)[A-Za-z0-9._~+/-]*[0-9][A-Za-z0-9._~+/=-]*`)
+	fieldRe   = regexp.MustCompile(`(?i)("?[\w.-]*(?:password|passwd|passphrase|secret|token|api[_-]?key)"?\s*[:=]\s*)(\[REDACTED\]|"(?:[^"\\]|\\.)*"|[^\s,&;}\]]+)`)
+	pemRe     = regexp.MustCompile(`(?s)-----BEGIN ([A-Z0-9 ]*)PRIVATE KEY-----.*?(?:-----END [A-Z0-9 ]*PRIVATE KEY-----|$)`)
+)
+
+// Add registers secret values, e.g. a password read from the environment, to be
+// masked wherever they appear. Values shorter than four characters are ignored.
+func Add(values ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if len(v) < minLen || strings.Contains(v, Mask) {
+			continue
+		}
+		dup := false
+		for _, s := range secrets {
+			dup = dup || s == v
+		}
+		if !dup {
+			secrets = append(secrets, v)
+		}
+	}
+}
+
+// Reset forgets the values registered with Add; for tests.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	secrets = nil
+}
+
+// String returns s with every secret replaced by Mask.
+func String(s string) string {
+	mu.RLock()
+	for _, v := range secrets {
+		s = strings.ReplaceAll(s, v, Mask)
+	}
+	mu.RUnlock()
+	s = pemRe.ReplaceAllString(s, "-----BEGIN ${1}PRIVATE KEY----- "+Mask)
+	s = urlPassRe.ReplaceAllString(s, "${1}:"+Mask+"@")
+	s = authRe.ReplaceAllString(s, "${1}"+Mask)
+	s = bearerRe.ReplaceAllString(s, "${1}"+Mask)
+	return fieldRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := fieldRe.FindStringSubmatch(m)
+		switch {
+		case sub[2] == Mask, sub[2] == `"`+Mask+`"`:
+			return m
+		case strings.HasPrefix(sub[2], `"`):
+			return sub[1] + `"` + Mask + `"`
+		}
+		return sub[1] + Mask
+	})
+}
+
+// Strings returns a copy of list with String applied to each element, e.g. for
+// command arguments recorded in a plan.
+func Strings(list []string) []string {
+	out := make([]string, len(list))
+	for i, s := range list {
+		out[i] = String(s)
+	}
+	return out
+}
+
+// Error returns err with a redacted message; errors.Is and errors.As still see
+// the original chain. It returns nil for nil.
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	if msg := err.Error(); String(msg) != msg {
+		return &redacted{err: err}
+	}
+	return err
+}
+
+type redacted struct{ err error }
+
+func (e *redacted) Error() string { return String(e.err.Error()) }
+func (e *redacted) Unwrap() error { return e.err }
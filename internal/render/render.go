@@ -0,0 +1,200 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package render generates configuration for other tools (dnsmasq, iPXE,
+// cloud-init, Ansible) from the inventory with Go text/template. Built-in
+// templates can be replaced, and new ones added, by files in template
+// directories.
+package render
+
+import (
+	"cmp"
+	"embed"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"bootstrap/internal/topology"
+	"bootstrap/pkg/inventory"
+)
+
+// Ext is the file extension of templates; it is not part of the name.
+const Ext = ".tmpl"
+
+//go:embed templates/*.tmpl
+var builtin embed.FS
+
+// Network is an address range IPs are allocated from, for templates that
+// configure DHCP or static addressing.
+type Network struct {
+	CIDR    string   // e.g. 10.42.0.0/24
+	Network string   // e.g. 10.42.0.0
+	Netmask string   // e.g. 255.255.255.0
+	Prefix  int      // e.g. 24
+	Gateway string   // default router; the first address of the range unless set
+	DNS     []string // name servers
+	Domain  string   // DNS domain
+}
+
+// ParseNetwork describes cidr. An empty cidr gives a zero Network.
+func ParseNetwork(cidr, gateway string, dns []string, domain string) (Network, error) {
+	if cidr == "" {
+		return Network{Gateway: gateway, DNS: dns, Domain: domain}, nil
+	}
+	_, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return Network{}, err
+	}
+	if ipnet.IP.To4() == nil {
+		return Network{}, fmt.Errorf("%s: only IPv4 networks are supported", cidr)
+	}
+	ones, _ := ipnet.Mask.Size()
+	if gateway == "" {
+		gw := slices.Clone(ipnet.IP.To4())
+		gw[3]++
+		gateway = gw.String()
+	}
+	return Network{
+		CIDR:    ipnet.String(),
+		Network: ipnet.IP.String(),
+		Netmask: net.IP(ipnet.Mask).String(),
+		Prefix:  ones,
+		Gateway: gateway,
+		DNS:     dns,
+		Domain:  domain,
+	}, nil
+}
+
+// Context is the data templates are executed with.
+type Context struct {
+	Inventory inventory.FileFormat
+	Nodes     []inventory.Entry  // Inventory.Nodes
+	BMCs      []inventory.Entry  // Inventory.BMCs
+	Topology  *topology.Topology // the inventory as a cabinet/chassis/slot/blade/node tree
+	NodeNet   Network            // where node IPs are allocated
+	BMCNet    Network            // where BMC IPs are allocated
+	Node      *inventory.Entry   // the node being rendered, for per-node templates
+	Keys      []string           // SSH authorized keys
+	Vars      map[string]string  // site values from --set key=value
+}
+
+// NewContext returns a Context for doc.
+func NewContext(doc inventory.FileFormat) Context {
+	return Context{
+		Inventory: doc,
+		Nodes:     doc.Nodes,
+		BMCs:      doc.BMCs,
+		Topology:  topology.Build(doc),
+		Vars:      map[string]string{},
+	}
+}
+
+// Funcs are the functions available to templates besides the text/template
+// built-ins.
+var Funcs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"join":  func(list []string, sep string) string { return strings.Join(list, sep) },
+	// default returns the first non-empty argument: {{default .Vars.x "y"}}.
+	"default": func(vals ...string) string { return cmp.Or(vals...) },
+	// hostname is the alias of an entry, or its xname.
+	"hostname": func(e inventory.Entry) string { return cmp.Or(e.Alias, e.Xname) },
+	// required fails the rendering when val is empty: {{required "boot_url" .Vars.boot_url}}.
+	"required": func(name, val string) (string, error) {
+		if val == "" {
+			return "", fmt.Errorf("%s is required (set it with --set %s=...)", name, name)
+		}
+		return val, nil
+	},
+}
+
+// Engine finds templates by name in its directories, then among the built-ins.
+type Engine struct {
+	dirs []string
+}
+
+// New returns an Engine looking in dirs, earlier ones first, before the
+// built-in templates.
+func New(dirs ...string) *Engine {
+	return &Engine{dirs: dirs}
+}
+
+// Template is a template name and where it comes from.
+type Template struct {
+	Name   string
+	Source string // the file, or "built-in"
+}
+
+// List returns every template name the Engine resolves, sorted, with the
+// source that wins.
+func (e *Engine) List() ([]Template, error) {
+	seen := map[string]string{}
+	for _, dir := range e.dirs {
+		files, err := filepath.Glob(filepath.Join(dir, "*"+Ext))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			name := strings.TrimSuffix(filepath.Base(f), Ext)
+			if _, ok := seen[name]; !ok {
+				seen[name] = f
+			}
+		}
+	}
+	files, err := fs.Glob(builtin, "templates/*"+Ext)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		name := strings.TrimSuffix(filepath.Base(f), Ext)
+		if _, ok := seen[name]; !ok {
+			seen[name] = "built-in"
+		}
+	}
+	out := make([]Template, 0, len(seen))
+	for name, src := range seen {
+		out = append(out, Template{Name: name, Source: src})
+	}
+	slices.SortFunc(out, func(a, b Template) int { return cmp.Compare(a.Name, b.Name) })
+	return out, nil
+}
+
+// Load parses the template called name.
+func (e *Engine) Load(name string) (*template.Template, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+	for _, dir := range e.dirs {
+		raw, err := os.ReadFile(filepath.Join(dir, name+Ext))
+		if err == nil {
+			return template.New(name).Funcs(Funcs).Option("missingkey=zero").Parse(string(raw))
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	raw, err := builtin.ReadFile("templates/" + name + Ext)
+	if err != nil {
+		return nil, fmt.Errorf("no template %q (see render --list)", name)
+	}
+	return template.New(name).Funcs(Funcs).Option("missingkey=zero").Parse(string(raw))
+}
+
+// Render executes the template called name with ctx and writes the result to w.
+func (e *Engine) Render(w io.Writer, name string, ctx Context) error {
+	t, err := e.Load(name)
+	if err != nil {
+		return err
+	}
+	if ctx.Vars == nil {
+		ctx.Vars = map[string]string{}
+	}
+	return t.Execute(w, ctx)
+}
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bootstrap/pkg/inventory"
+)
+
+func testContext(t *testing.T) Context {
+	t.Helper()
+	ctx := NewContext(inventory.FileFormat{
+		BMCs: []inventory.Entry{{Xname: "x1000c0s0b0", MAC: "02:23:28:01:00:00", IP: "192.168.100.10"}},
+		Nodes: []inventory.Entry{
+			{Xname: "x1000c0s0b0n0", Alias: "nid000001", MAC: "AA:00:00:00:00:01", IP: "10.42.0.11"},
+			{Xname: "x1000c0s0b0n1", MAC: "aa:00:00:00:00:02"},
+		},
+	})
+	var err error
+	if ctx.NodeNet, err = ParseNetwork("10.42.0.0/24", "", []string{"10.42.0.2"}, "hpc.local"); err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
+
+func TestParseNetwork(t *testing.T) {
+	got, err := ParseNetwork("10.42.0.7/23", "", nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Network{CIDR: "10.42.0.0/23", Network: "10.42.0.0", Netmask: "255.255.254.0", Prefix: 23, Gateway: "10.42.0.1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if _, err := ParseNetwork("fd00::/64", "", nil, ""); err == nil {
+		t.Error("IPv6: want error")
+	}
+}
+
+func TestRenderBuiltin(t *testing.T) {
+	ctx := testContext(t)
+	var b strings.Builder
+	if err := New().Render(&b, "dnsmasq", ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"dhcp-range=set:node,10.42.0.0,static,255.255.255.0\n",
+		"dhcp-option=tag:node,option:router,10.42.0.1\n",
+		"dhcp-option=tag:node,option:dns-server,10.42.0.2\n",
+		"dhcp-host=02:23:28:01:00:00,192.168.100.10,x1000c0s0b0\n",
+		"dhcp-host=aa:00:00:00:00:01,10.42.0.11,nid000001\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("dnsmasq missing %q:\n%s", want, b.String())
+		}
+	}
+	if strings.Contains(b.String(), "aa:00:00:00:00:02") || strings.Contains(b.String(), "BMC network") {
+		t.Errorf("node without IP or unset BMC network rendered:\n%s", b.String())
+	}
+
+	if err := New().Render(&b, "ipxe", ctx); err == nil || !strings.Contains(err.Error(), "boot_url is required") {
+		t.Errorf("ipxe without boot_url: err = %v", err)
+	}
+
+	b.Reset()
+	ctx.Node, ctx.Keys = &ctx.Nodes[0], []string{"ssh-ed25519 AAAA admin@mgmt"}
+	if err := New().Render(&b, "cloud-init-user-data", ctx); err != nil {
+		t.Fatal(err)
+	}
+	if want := "#cloud-config\nhostname: nid000001\nssh_authorized_keys:\n    - ssh-ed25519 AAAA admin@mgmt\n"; b.String() != want {
+		t.Errorf("user-data:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestTemplateDir(t *testing.T) {
+	site, extra := t.TempDir(), t.TempDir()
+	files := map[string]string{
+		filepath.Join(site, "dnsmasq.tmpl"):  `{{range .Nodes}}{{upper .Xname}} {{default .IP "none"}} {{$.Vars.rack}}{{"\n"}}{{end}}`,
+		filepath.Join(extra, "dnsmasq.tmpl"): "shadowed",
+		filepath.Join(extra, "hosts.tmpl"):   `{{range .Nodes}}{{.IP}} {{hostname .}}{{"\n"}}{{end}}`,
+	}
+	for path, text := range files {
+		if err := os.WriteFile(path, []byte(text), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	e := New(site, extra)
+	ctx := testContext(t)
+	ctx.Vars["rack"] = "r1"
+
+	var b strings.Builder
+	if err := e.Render(&b, "dnsmasq", ctx); err != nil {
+		t.Fatal(err)
+	}
+	if want := "X1000C0S0B0N0 10.42.0.11 r1\nX1000C0S0B0N1 none r1\n"; b.String() != want {
+		t.Errorf("override = %q, want %q", b.String(), want)
+	}
+
+	list, err := e.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources := map[string]string{}
+	for _, tpl := range list {
+		sources[tpl.Name] = tpl.Source
+	}
+	if sources["dnsmasq"] != filepath.Join(site, "dnsmasq.tmpl") || sources["hosts"] != filepath.Join(extra, "hosts.tmpl") || sources["ipxe"] != "built-in" {
+		t.Errorf("List = %+v", list)
+	}
+	if _, err := e.Load("../etc/passwd"); err == nil {
+		t.Error("path as template name: want error")
+	}
+	if _, err := e.Load("nope"); err == nil {
+		t.Error("unknown template: want error")
+	}
+}
@@ -0,0 +1,172 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package report renders firmware status, discovery and boot validation
+// results as one standalone HTML page: no external scripts, styles or fonts,
+// so it can be mailed or attached to a ticket and opened anywhere.
+package report
+
+import (
+	"cmp"
+	_ "embed"
+	"html/template"
+	"io"
+	"maps"
+	"path"
+	"slices"
+	"time"
+
+	"bootstrap/pkg/inventory"
+)
+
+//go:embed report.html.tmpl
+var pageTemplate string
+
+var page = template.Must(template.New("report").Funcs(template.FuncMap{
+	"base": path.Base,
+}).Parse(pageTemplate))
+
+// FirmwareRow is a target of `firmware status --format json`.
+type FirmwareRow struct {
+	Host             string `json:"host"`
+	Alias            string `json:"alias,omitempty"`
+	Xname            string `json:"xname,omitempty"`
+	Chassis          string `json:"chassis,omitempty"`
+	Target           string `json:"target"`
+	ObservedVersion  string `json:"observed_version"`
+	RequestedVersion string `json:"requested_version,omitempty"`
+	Status           string `json:"status"`
+	Severity         string `json:"severity,omitempty"`
+	Error            string `json:"error,omitempty"`
+	PercentComplete  *int   `json:"percent_complete,omitempty"`
+	Phase            string `json:"phase,omitempty"`
+}
+
+// BootRow is a node of `verify-boot --format json`.
+type BootRow struct {
+	Xname  string            `json:"xname"`
+	Alias  string            `json:"alias,omitempty"`
+	IP     string            `json:"ip"`
+	OK     bool              `json:"ok"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Report is what a page shows; sections without data are left out.
+type Report struct {
+	Title     string
+	Generated time.Time
+	Firmware  []FirmwareRow
+	Inventory *inventory.FileFormat
+	Boot      []BootRow
+}
+
+// Bar is one bar of a chart.
+type Bar struct {
+	Label   string
+	Count   int
+	Percent int // of the largest bar, for its width
+}
+
+// Chart counts values, most common first.
+type Chart struct {
+	Title string
+	Bars  []Bar
+}
+
+// WriteHTML renders r as a standalone HTML page.
+func WriteHTML(w io.Writer, r Report) error {
+	return page.Execute(w, view{Report: r})
+}
+
+// view adds the summaries the template shows to a Report.
+type view struct {
+	Report
+}
+
+// FirmwareCharts counts the observed versions of each kind of target (the
+// last element of its path: BMC, BIOS, ...).
+func (v view) FirmwareCharts() []Chart {
+	byKind := map[string][]string{}
+	for _, r := range v.Firmware {
+		kind := path.Base(cmp.Or(r.Target, "firmware"))
+		byKind[kind] = append(byKind[kind], r.ObservedVersion)
+	}
+	var out []Chart
+	for _, kind := range slices.Sorted(maps.Keys(byKind)) {
+		out = append(out, chart(kind+" versions", byKind[kind]))
+	}
+	return out
+}
+
+// FirmwareCounts counts the targets by status.
+func (v view) FirmwareCounts() map[string]int {
+	out := map[string]int{}
+	for _, r := range v.Firmware {
+		out[r.Status]++
+	}
+	return out
+}
+
+// NodeCharts counts the models and BIOS versions discovery recorded.
+func (v view) NodeCharts() []Chart {
+	if v.Inventory == nil {
+		return nil
+	}
+	var models, bios []string
+	for _, n := range v.Inventory.Nodes {
+		if n.Model != "" {
+			models = append(models, n.Model)
+		}
+		if n.BiosVersion != "" {
+			bios = append(bios, n.BiosVersion)
+		}
+	}
+	var out []Chart
+	if len(models) > 0 {
+		out = append(out, chart("Node models", models))
+	}
+	if len(bios) > 0 {
+		out = append(out, chart("Node BIOS versions", bios))
+	}
+	return out
+}
+
+// BootFailed counts the nodes that failed a boot check.
+func (v view) BootFailed() int {
+	n := 0
+	for _, b := range v.Boot {
+		if !b.OK {
+			n++
+		}
+	}
+	return n
+}
+
+// BootChecks is the sorted names of the checks run on any node.
+func (v view) BootChecks() []string {
+	seen := map[string]bool{}
+	for _, b := range v.Boot {
+		for c := range b.Checks {
+			seen[c] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// chart counts values, most common first.
+func chart(title string, values []string) Chart {
+	counts := map[string]int{}
+	for _, v := range values {
+		counts[cmp.Or(v, "(none)")]++
+	}
+	c := Chart{Title: title}
+	for label, n := range counts {
+		c.Bars = append(c.Bars, Bar{Label: label, Count: n})
+	}
+	slices.SortFunc(c.Bars, func(a, b Bar) int { return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Label, b.Label)) })
+	for i := range c.Bars {
+		c.Bars[i].Percent = c.Bars[i].Count * 100 / c.Bars[0].Count
+	}
+	return c
+}
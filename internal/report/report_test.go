@@ -0,0 +1,72 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package report
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"bootstrap/pkg/inventory"
+)
+
+func TestWriteHTML(t *testing.T) {
+	pct := 45
+	r := Report{
+		Title:     "Cabinet 1000 <rollout>",
+		Generated: time.Date(2025, 7, 1, 2, 0, 0, 0, time.UTC),
+		Firmware: []FirmwareRow{
+			{Host: "10.0.0.1", Xname: "x1000c0s0b0", Target: "/redfish/v1/UpdateService/FirmwareInventory/BMC", ObservedVersion: "1.2", Status: "idle"},
+			{Host: "10.0.0.2", Alias: "login01", Target: "/redfish/v1/UpdateService/FirmwareInventory/BMC", ObservedVersion: "1.1", Status: "error", Severity: "Critical", Error: "health: Critical"},
+			{Host: "10.0.0.3", Target: "/redfish/v1/UpdateService/FirmwareInventory/BMC", ObservedVersion: "1.2", Status: "in-progress", Phase: "installing", PercentComplete: &pct},
+		},
+		Inventory: &inventory.FileFormat{
+			BMCs:  []inventory.Entry{{Xname: "x1000c0s0b0", IP: "10.0.0.1"}},
+			Nodes: []inventory.Entry{{Xname: "x1000c0s0b0n0", MAC: "aa:00:00:00:00:01", IP: "10.42.0.1", Model: "EX425"}},
+		},
+		Boot: []BootRow{
+			{Xname: "x1000c0s0b0n0", IP: "10.42.0.1", OK: false, Checks: map[string]string{"ping": "ok", "ssh": "connection refused"}},
+		},
+	}
+	var b bytes.Buffer
+	if err := WriteHTML(&b, r); err != nil {
+		t.Fatal(err)
+	}
+	html := b.String()
+	for _, want := range []string{
+		"<title>Cabinet 1000 &lt;rollout&gt;</title>", // escaped
+		"<h2>Firmware</h2>", "<h2>Discovery</h2>", "<h2>Boot validation</h2>",
+		`<tr class="err">`, `<tr class="prog">`,
+		"installing 45%",
+		`<td class="err">connection refused</td>`,
+		"<h3>BMC versions</h3>", "<h3>Node models</h3>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("page lacks %q", want)
+		}
+	}
+	if strings.Contains(html, "http://") || strings.Contains(html, "https://") {
+		t.Error("page refers to external resources")
+	}
+
+	// Without data a section is left out
+	b.Reset()
+	if err := WriteHTML(&b, Report{Title: "t", Boot: r.Boot}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(b.String(), "<h2>Firmware</h2>") || strings.Contains(b.String(), "<h2>Discovery</h2>") {
+		t.Error("empty sections rendered")
+	}
+}
+
+func TestChart(t *testing.T) {
+	got := chart("v", []string{"1.1", "1.2", "1.2", "", "1.2", "1.1"})
+	want := Chart{Title: "v", Bars: []Bar{{"1.2", 3, 100}, {"1.1", 2, 66}, {"(none)", 1, 33}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("chart = %+v, want %+v", got, want)
+	}
+}
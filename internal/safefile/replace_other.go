@@ -0,0 +1,14 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package safefile
+
+import "os"
+
+// replace renames src over dst.
+func replace(src, dst string) error {
+	return os.Rename(src, dst)
+}
@@ -0,0 +1,34 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package safefile
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"time"
+)
+
+const (
+	errorAccessDenied     = syscall.Errno(5)
+	errorSharingViolation = syscall.Errno(32)
+)
+
+// replace renames src over dst. Windows refuses while another process (an
+// editor, a virus scanner, a backup agent) has dst open, which usually passes
+// within moments, so the rename is retried for up to a second.
+func replace(src, dst string) error {
+	var err error
+	for range 10 {
+		if err = os.Rename(src, dst); err == nil {
+			return nil
+		}
+		if !errors.Is(err, errorAccessDenied) && !errors.Is(err, errorSharingViolation) {
+			return err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return err
+}
@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package safefile writes files so an interruption never leaves them truncated,
+// keeps rotated backups, and guards them with a lock file against concurrent
+// writers.
+package safefile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Write replaces path with data atomically: data is written and synced to a
+// temporary file in the same directory, which is then renamed over path. An
+// existing file keeps its permissions (perm is used for new files) and is kept
+// as path.1, shifting older backups up to path.<backups>; backups = 0 keeps none.
+func Write(path string, data []byte, perm fs.FileMode, backups int) error {
+	if st, err := os.Stat(path); err == nil {
+		perm = st.Mode().Perm()
+	}
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if backups > 0 {
+		if err := rotate(path, backups); err != nil {
+			return fmt.Errorf("back up %s: %w", path, err)
+		}
+	}
+	if err := replace(tmp.Name(), path); err != nil {
+		return err
+	}
+	// Persist the rename itself; not supported on every platform
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		d.Close()
+	}
+	return nil
+}
+
+// rotate shifts path.1..path.(n-1) up by one and copies path to path.1.
+func rotate(path string, n int) error {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	for i := n - 1; i >= 1; i-- {
+		err := os.Rename(Backup(path, i), Backup(path, i+1))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	// A hard link keeps the old contents once path is replaced; copy where
+	// links are unsupported
+	first := Backup(path, 1)
+	_ = os.Remove(first)
+	if err := os.Link(path, first); err == nil {
+		return nil
+	}
+	return copyFile(path, first)
+}
+
+// Backup returns the name of the i-th backup of path (1 = most recent).
+func Backup(path string, i int) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	st, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, st.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// LockedError is returned by Lock when another process holds the lock.
+type LockedError struct {
+	Path  string // the lock file
+	Owner string // the lock file's contents: pid, host and time of the holder
+}
+
+func (e *LockedError) Error() string {
+	owner := strings.TrimSpace(e.Owner)
+	if owner == "" {
+		owner = "unknown owner"
+	}
+	return fmt.Sprintf("%s is locked (%s); if no other run is active, remove the lock file", e.Path, owner)
+}
+
+// Lock takes the lock for path by creating path.lock exclusively, and returns
+// a function that releases it. The lock file records the holder's pid, host and
+// start time so a stale lock left by a killed run can be identified.
+func Lock(path string) (func(), error) {
+	lock := path + ".lock"
+	f, err := os.OpenFile(lock, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			owner, _ := os.ReadFile(lock)
+			return nil, &LockedError{Path: lock, Owner: string(owner)}
+		}
+		return nil, err
+	}
+	host, _ := os.Hostname()
+	fmt.Fprintf(f, "pid %d on %s since %s\n", os.Getpid(), host, time.Now().UTC().Format(time.RFC3339))
+	if err := f.Close(); err != nil {
+		os.Remove(lock)
+		return nil, err
+	}
+	return func() { _ = os.Remove(lock) }, nil
+}
@@ -0,0 +1,55 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package safefile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteRotatesBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.yaml")
+	for _, v := range []string{"v1", "v2", "v3", "v4"} {
+		if err := Write(path, []byte(v), 0o600, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, want := range map[string]string{path: "v4", Backup(path, 1): "v3", Backup(path, 2): "v2"} {
+		got, err := os.ReadFile(name)
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := os.Stat(Backup(path, 3)); !os.IsNotExist(err) {
+		t.Errorf("backup 3 kept: %v", err)
+	}
+	if st, _ := os.Stat(path); st.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v", st.Mode().Perm())
+	}
+	left, _ := filepath.Glob(filepath.Join(filepath.Dir(path), ".*.tmp"))
+	if len(left) != 0 {
+		t.Errorf("temporary files left: %v", left)
+	}
+}
+
+func TestLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.yaml")
+	unlock, err := Lock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var locked *LockedError
+	if _, err := Lock(path); !errors.As(err, &locked) || locked.Owner == "" {
+		t.Fatalf("second Lock = %v, want LockedError with owner", err)
+	}
+	unlock()
+	unlock, err = Lock(path)
+	if err != nil {
+		t.Fatalf("Lock after unlock: %v", err)
+	}
+	unlock()
+}
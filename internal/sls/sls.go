@@ -0,0 +1,298 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package sls converts between the inventory and the hardware and network
+// data of the Cray System Layout Service (SLS), so sites moving from CSM can
+// start from an SLS dump and hand an inventory back to SLS-based tools.
+//
+// Nodes map to SLS Node hardware and BMCs to the NodeBMC that is each node's
+// parent. SLS records no MACs; BMC IPs are the reservations of the HMN networks
+// and node IPs those of the NMN networks, matched by xname or alias.
+package sls
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/netip"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"bootstrap/internal/xname"
+	"bootstrap/pkg/inventory"
+)
+
+// SLS hardware types and classes
+const (
+	TypeNode    = "comptype_node"
+	TypeNodeBMC = "comptype_ncn_box"
+
+	ClassRiver    = "River"
+	ClassMountain = "Mountain"
+	ClassHill     = "Hill"
+)
+
+// Dump is an SLS dump (GET /v1/dumpstate), limited to what the inventory maps.
+type Dump struct {
+	Hardware map[string]Hardware `json:"Hardware"`
+	Networks map[string]Network  `json:"Networks,omitempty"`
+}
+
+// Hardware is an SLS hardware object.
+type Hardware struct {
+	Parent          string         `json:"Parent"`
+	Xname           string         `json:"Xname"`
+	Type            string         `json:"Type"`
+	Class           string         `json:"Class"`
+	TypeString      string         `json:"TypeString"`
+	ExtraProperties map[string]any `json:"ExtraProperties,omitempty"`
+}
+
+// Network is an SLS network.
+type Network struct {
+	Name            string          `json:"Name"`
+	FullName        string          `json:"FullName,omitempty"`
+	IPRanges        []string        `json:"IPRanges"`
+	Type            string          `json:"Type"`
+	ExtraProperties NetworkProperty `json:"ExtraProperties"`
+}
+
+// NetworkProperty holds the subnets of a network.
+type NetworkProperty struct {
+	CIDR    string   `json:"CIDR"`
+	Subnets []Subnet `json:"Subnets"`
+}
+
+// Subnet is a subnet of an SLS network with its IP reservations.
+type Subnet struct {
+	Name           string          `json:"Name"`
+	FullName       string          `json:"FullName,omitempty"`
+	CIDR           string          `json:"CIDR"`
+	Gateway        string          `json:"Gateway,omitempty"`
+	VlanID         int             `json:"VlanID,omitempty"`
+	IPReservations []IPReservation `json:"IPReservations,omitempty"`
+}
+
+// IPReservation is an address reserved in a subnet. CSM names reservations by
+// xname or by alias, with the xname in Comment.
+type IPReservation struct {
+	Name      string   `json:"Name"`
+	IPAddress string   `json:"IPAddress"`
+	Aliases   []string `json:"Aliases,omitempty"`
+	Comment   string   `json:"Comment,omitempty"`
+}
+
+// Parse reads an SLS dump, or the hardware alone as GET /v1/hardware returns
+// it (a list) or as the Hardware object of a dump.
+func Parse(data []byte) (Dump, error) {
+	var d Dump
+	if err := json.Unmarshal(data, &d); err == nil && d.Hardware != nil {
+		return d, nil
+	}
+	var list []Hardware
+	if err := json.Unmarshal(data, &list); err == nil {
+		d = Dump{Hardware: map[string]Hardware{}}
+		for _, h := range list {
+			d.Hardware[h.Xname] = h
+		}
+		return d, nil
+	}
+	var hw map[string]Hardware
+	if err := json.Unmarshal(data, &hw); err != nil {
+		return Dump{}, fmt.Errorf("not an SLS dump or hardware list: %w", err)
+	}
+	for k, h := range hw {
+		if h.Xname == "" {
+			return Dump{}, fmt.Errorf("not an SLS dump or hardware list: %s has no Xname", k)
+		}
+	}
+	return Dump{Hardware: hw}, nil
+}
+
+// ToInventory returns the nodes of d and their BMCs, in xname order.
+func ToInventory(d Dump) inventory.FileFormat {
+	bmcIPs, nodeIPs := reservations(d.Networks, "HMN"), reservations(d.Networks, "NMN")
+	var doc inventory.FileFormat
+	bmcs := map[string]bool{}
+	addBMC := func(x string) {
+		if x == "" || bmcs[x] {
+			return
+		}
+		bmcs[x] = true
+		doc.BMCs = append(doc.BMCs, inventory.Entry{Xname: x, IP: bmcIPs.lookup(x, "")})
+	}
+	for _, x := range slices.Sorted(maps.Keys(d.Hardware)) {
+		h := d.Hardware[x]
+		switch {
+		case h.Type == TypeNode || h.TypeString == "Node":
+			alias := ""
+			if a, ok := h.ExtraProperties["Aliases"].([]any); ok && len(a) > 0 {
+				alias, _ = a[0].(string)
+			}
+			doc.Nodes = append(doc.Nodes, inventory.Entry{Xname: h.Xname, Alias: alias, IP: nodeIPs.lookup(h.Xname, alias)})
+			addBMC(cmp.Or(xname.NodeToBMCXname(h.Xname), h.Parent))
+		case h.Type == TypeNodeBMC || h.TypeString == "NodeBMC":
+			addBMC(h.Xname)
+		}
+	}
+	slices.SortFunc(doc.BMCs, func(a, b inventory.Entry) int { return cmp.Compare(a.Xname, b.Xname) })
+	return doc
+}
+
+// ipIndex maps reservation names, comments and aliases to addresses.
+type ipIndex map[string]string
+
+func (ix ipIndex) lookup(x, alias string) string {
+	if ip, ok := ix[strings.ToLower(x)]; ok {
+		return ip
+	}
+	if alias != "" {
+		return ix[strings.ToLower(alias)]
+	}
+	return ""
+}
+
+// reservations indexes the reservations of the networks whose name starts with
+// prefix (HMN, HMN_RVR, HMN_MTN, ...), the plain one winning.
+func reservations(nets map[string]Network, prefix string) ipIndex {
+	ix := ipIndex{}
+	names := slices.Sorted(maps.Keys(nets))
+	slices.Reverse(names) // HMN last, so it wins
+	for _, n := range names {
+		if !strings.HasPrefix(strings.ToUpper(n), prefix) {
+			continue
+		}
+		for _, s := range nets[n].ExtraProperties.Subnets {
+			for _, r := range s.IPReservations {
+				if r.IPAddress == "" {
+					continue
+				}
+				for _, k := range append([]string{r.Name, r.Comment}, r.Aliases...) {
+					if k != "" {
+						ix[strings.ToLower(k)] = r.IPAddress
+					}
+				}
+			}
+		}
+	}
+	return ix
+}
+
+// Subnets are the networks FromInventory writes reservations into; an empty
+// CIDR leaves that network out.
+type Subnets struct {
+	BMC  string // HMN: the BMC IPs
+	Node string // NMN: the node IPs
+}
+
+// FromInventory returns the SLS hardware of doc's nodes and BMCs and, for the
+// subnets given, HMN and NMN networks reserving their IPs. Entries whose IP is
+// outside the subnet are reported as an error.
+func FromInventory(doc inventory.FileFormat, subnets Subnets) (Dump, error) {
+	d := Dump{Hardware: map[string]Hardware{}}
+	for _, b := range doc.BMCs {
+		d.Hardware[b.Xname] = bmcHardware(b.Xname)
+	}
+	for _, n := range doc.Nodes {
+		parent := xname.NodeToBMCXname(n.Xname)
+		props := map[string]any{"Role": "Compute"}
+		if n.Alias != "" {
+			props["Aliases"] = []string{n.Alias}
+			if nid, ok := nidOf(n.Alias); ok {
+				props["NID"] = nid
+			}
+		}
+		d.Hardware[n.Xname] = Hardware{Parent: parent, Xname: n.Xname, Type: TypeNode, Class: class(n.Xname), TypeString: "Node", ExtraProperties: props}
+		if _, ok := d.Hardware[parent]; parent != "" && !ok {
+			d.Hardware[parent] = bmcHardware(parent)
+		}
+	}
+	for _, net := range []struct {
+		name, full, cidr string
+		entries          []inventory.Entry
+	}{
+		{"HMN", "Hardware Management Network", subnets.BMC, doc.BMCs},
+		{"NMN", "Node Management Network", subnets.Node, doc.Nodes},
+	} {
+		if net.cidr == "" {
+			continue
+		}
+		n, err := network(net.name, net.full, net.cidr, net.entries)
+		if err != nil {
+			return Dump{}, err
+		}
+		if d.Networks == nil {
+			d.Networks = map[string]Network{}
+		}
+		d.Networks[net.name] = n
+	}
+	return d, nil
+}
+
+var (
+	trailingBMC = regexp.MustCompile(`b\d+$`)
+	cabinet     = regexp.MustCompile(`^x(\d+)`)
+	nidAlias    = regexp.MustCompile(`^nid0*(\d+)$`)
+)
+
+func bmcHardware(x string) Hardware {
+	return Hardware{Parent: trailingBMC.ReplaceAllString(x, ""), Xname: x, Type: TypeNodeBMC, Class: class(x), TypeString: "NodeBMC"}
+}
+
+// class follows the CSM cabinet numbering: x1000-x2999 are Mountain, x9000
+// and up Hill, the rest River.
+func class(x string) string {
+	m := cabinet.FindStringSubmatch(x)
+	if m == nil {
+		return ClassRiver
+	}
+	n, _ := strconv.Atoi(m[1])
+	switch {
+	case n >= 1000 && n < 3000:
+		return ClassMountain
+	case n >= 9000:
+		return ClassHill
+	}
+	return ClassRiver
+}
+
+// nidOf returns the node ID of an alias like nid000123.
+func nidOf(alias string) (int, bool) {
+	m := nidAlias.FindStringSubmatch(alias)
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	return n, err == nil
+}
+
+// network builds an SLS network with one bootstrap_dhcp subnet reserving the
+// IPs of entries.
+func network(name, full, cidr string, entries []inventory.Entry) (Network, error) {
+	pfx, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return Network{}, fmt.Errorf("%s subnet: %w", name, err)
+	}
+	pfx = pfx.Masked()
+	s := Subnet{Name: "bootstrap_dhcp", FullName: full + " Bootstrap DHCP Subnet", CIDR: pfx.String(), Gateway: pfx.Addr().Next().String()}
+	for _, e := range entries {
+		if e.IP == "" {
+			continue
+		}
+		ip, err := netip.ParseAddr(e.IP)
+		if err != nil || !pfx.Contains(ip) {
+			return Network{}, fmt.Errorf("%s: IP %s is not in the %s subnet %s", e.Xname, e.IP, name, pfx)
+		}
+		r := IPReservation{Name: e.Xname, IPAddress: e.IP}
+		if e.Alias != "" {
+			r.Aliases = []string{e.Alias}
+		}
+		s.IPReservations = append(s.IPReservations, r)
+	}
+	return Network{Name: name, FullName: full, IPRanges: []string{pfx.String()}, Type: "ethernet",
+		ExtraProperties: NetworkProperty{CIDR: pfx.String(), Subnets: []Subnet{s}}}, nil
+}
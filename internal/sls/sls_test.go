@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package sls
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"bootstrap/pkg/inventory"
+)
+
+const dump = `{
+  "Hardware": {
+    "x3000c0s9b0n0": {"Parent": "x3000c0s9b0", "Xname": "x3000c0s9b0n0", "Type": "comptype_node", "Class": "River", "TypeString": "Node",
+      "ExtraProperties": {"Role": "Management", "Aliases": ["ncn-w001"]}},
+    "x1000c0s0b0n1": {"Parent": "x1000c0s0b0", "Xname": "x1000c0s0b0n1", "Type": "comptype_node", "Class": "Mountain", "TypeString": "Node",
+      "ExtraProperties": {"Role": "Compute", "NID": 2, "Aliases": ["nid000002"]}},
+    "x1000c0s0b0n0": {"Parent": "x1000c0s0b0", "Xname": "x1000c0s0b0n0", "Type": "comptype_node", "Class": "Mountain", "TypeString": "Node",
+      "ExtraProperties": {"Role": "Compute", "NID": 1, "Aliases": ["nid000001"]}},
+    "x3000c0s30b0": {"Parent": "x3000c0s30", "Xname": "x3000c0s30b0", "Type": "comptype_ncn_box", "Class": "River", "TypeString": "NodeBMC"},
+    "x1000c0b0": {"Parent": "x1000c0", "Xname": "x1000c0b0", "Type": "comptype_chassis_bmc", "Class": "Mountain", "TypeString": "ChassisBMC"},
+    "x3000c0w14": {"Parent": "x3000c0", "Xname": "x3000c0w14", "Type": "comptype_mgmt_switch", "Class": "River", "TypeString": "MgmtSwitch"}
+  },
+  "Networks": {
+    "HMN": {"Name": "HMN", "IPRanges": ["10.254.0.0/17"], "Type": "ethernet", "ExtraProperties": {"CIDR": "10.254.0.0/17", "Subnets": [
+      {"Name": "bootstrap_dhcp", "CIDR": "10.254.1.0/24", "IPReservations": [
+        {"Name": "x3000c0s9b0", "IPAddress": "10.254.1.9"},
+        {"Name": "x3000c0s30b0", "IPAddress": "10.254.1.30"}]}]}},
+    "HMN_MTN": {"Name": "HMN_MTN", "IPRanges": ["10.104.0.0/17"], "Type": "ethernet", "ExtraProperties": {"CIDR": "10.104.0.0/17", "Subnets": [
+      {"Name": "cabinet_1000", "CIDR": "10.104.0.0/22", "IPReservations": [
+        {"Name": "x1000c0s0b0", "IPAddress": "10.104.0.10"},
+        {"Name": "x3000c0s9b0", "IPAddress": "10.104.9.9"}]}]}},
+    "NMN": {"Name": "NMN", "IPRanges": ["10.252.0.0/17"], "Type": "ethernet", "ExtraProperties": {"CIDR": "10.252.0.0/17", "Subnets": [
+      {"Name": "bootstrap_dhcp", "CIDR": "10.252.1.0/24", "IPReservations": [
+        {"Name": "ncn-w001", "IPAddress": "10.252.1.7", "Comment": "x3000c0s9b0n0"},
+        {"Name": "nid000001-nmn", "IPAddress": "10.252.2.1", "Aliases": ["nid000001"]}]}]}},
+    "CAN": {"Name": "CAN", "IPRanges": ["10.103.0.0/24"], "Type": "ethernet", "ExtraProperties": {"CIDR": "10.103.0.0/24", "Subnets": [
+      {"Name": "bootstrap_dhcp", "CIDR": "10.103.0.0/24", "IPReservations": [{"Name": "ncn-w001", "IPAddress": "10.103.0.7"}]}]}}
+  }
+}`
+
+func TestToInventory(t *testing.T) {
+	d, err := Parse([]byte(dump))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := ToInventory(d)
+	want := inventory.FileFormat{
+		BMCs: []inventory.Entry{
+			{Xname: "x1000c0s0b0", IP: "10.104.0.10"},
+			{Xname: "x3000c0s30b0", IP: "10.254.1.30"},
+			{Xname: "x3000c0s9b0", IP: "10.254.1.9"}, // HMN wins over HMN_MTN
+		},
+		Nodes: []inventory.Entry{
+			{Xname: "x1000c0s0b0n0", Alias: "nid000001", IP: "10.252.2.1"},
+			{Xname: "x1000c0s0b0n1", Alias: "nid000002"},
+			{Xname: "x3000c0s9b0n0", Alias: "ncn-w001", IP: "10.252.1.7"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestParseHardwareOnly(t *testing.T) {
+	for _, in := range []string{
+		`[{"Parent": "x3000c0s9b0", "Xname": "x3000c0s9b0n0", "Type": "comptype_node", "TypeString": "Node"}]`,
+		`{"x3000c0s9b0n0": {"Parent": "x3000c0s9b0", "Xname": "x3000c0s9b0n0", "Type": "comptype_node", "TypeString": "Node"}}`,
+	} {
+		d, err := Parse([]byte(in))
+		if err != nil {
+			t.Fatalf("%s: %v", in, err)
+		}
+		if got := ToInventory(d); len(got.Nodes) != 1 || len(got.BMCs) != 1 || got.BMCs[0].Xname != "x3000c0s9b0" {
+			t.Errorf("%s: got %+v", in, got)
+		}
+	}
+	if _, err := Parse([]byte(`{"bmcs": []}`)); err == nil {
+		t.Error("an inventory parsed as SLS")
+	}
+}
+
+func TestFromInventory(t *testing.T) {
+	doc := inventory.FileFormat{
+		BMCs: []inventory.Entry{{Xname: "x1000c0s0b0", IP: "10.1.0.10", MAC: "02:00:00:00:00:01"}},
+		Nodes: []inventory.Entry{
+			{Xname: "x1000c0s0b0n0", Alias: "nid000001", IP: "10.2.0.1"},
+			{Xname: "x3000c0s9b0n0", Alias: "ncn-w001"},
+		},
+	}
+	d, err := FromInventory(doc, Subnets{BMC: "10.1.0.5/16", Node: "10.2.0.0/16"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := d.Hardware["x1000c0s0b0n0"], (Hardware{Parent: "x1000c0s0b0", Xname: "x1000c0s0b0n0", Type: TypeNode, Class: ClassMountain, TypeString: "Node",
+		ExtraProperties: map[string]any{"Role": "Compute", "Aliases": []string{"nid000001"}, "NID": 1}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("node hardware %+v\nwant %+v", got, want)
+	}
+	if got := d.Hardware["x3000c0s9b0"]; got.Type != TypeNodeBMC || got.Parent != "x3000c0s9" || got.Class != ClassRiver {
+		t.Errorf("BMC of a node without a bmcs[] entry: %+v", got)
+	}
+	hmn := d.Networks["HMN"].ExtraProperties.Subnets[0]
+	if hmn.CIDR != "10.1.0.0/16" || hmn.Gateway != "10.1.0.1" || len(hmn.IPReservations) != 1 || !reflect.DeepEqual(hmn.IPReservations[0], IPReservation{Name: "x1000c0s0b0", IPAddress: "10.1.0.10"}) {
+		t.Errorf("HMN subnet %+v", hmn)
+	}
+
+	// Round trip through JSON back to the inventory, without the MACs SLS lacks
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := doc
+	want.BMCs = []inventory.Entry{{Xname: "x1000c0s0b0", IP: "10.1.0.10"}, {Xname: "x3000c0s9b0"}}
+	if got := ToInventory(back); !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip %+v\nwant %+v", got, want)
+	}
+
+	if _, err := FromInventory(doc, Subnets{Node: "10.3.0.0/16"}); err == nil || !strings.Contains(err.Error(), "x1000c0s0b0n0") {
+		t.Errorf("IP outside the subnet: %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package smd pushes inventory data to the OpenCHAMI State Management Database.
+package smd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// EthernetInterface is an entry of the SMD EthernetInterfaces inventory.
+type EthernetInterface struct {
+	ID          string      `json:"ID,omitempty"` // see InterfaceID
+	MACAddress  string      `json:"MACAddress"`
+	ComponentID string      `json:"ComponentID"`
+	Description string      `json:"Description,omitempty"`
+	IPAddresses []IPAddress `json:"IPAddresses,omitempty"`
+}
+
+// IPAddress is an address of an EthernetInterface.
+type IPAddress struct {
+	IPAddress string `json:"IPAddress"`
+	Network   string `json:"Network,omitempty"`
+}
+
+// InterfaceID returns the SMD ID of the EthernetInterface with mac: the MAC
+// in lowercase without separators.
+func InterfaceID(mac string) string {
+	return strings.ToLower(strings.NewReplacer(":", "", "-", "", ".", "").Replace(mac))
+}
+
+// Client talks to SMD at URL (e.g. https://smd.example:27779). Token, when set,
+// is sent as a bearer token.
+type Client struct {
+	URL   string
+	Token string
+	HTTP  *http.Client
+}
+
+// New returns a Client for url with the given bearer token and request timeout.
+func New(url, token string, timeout time.Duration) *Client {
+	return &Client{URL: strings.TrimRight(url, "/"), Token: token, HTTP: &http.Client{Timeout: timeout}}
+}
+
+// PutEthernetInterface creates ei in SMD, or updates its component and description
+// when an entry for the MAC already exists.
+func (c *Client) PutEthernetInterface(ctx context.Context, ei EthernetInterface) error {
+	status, err := c.do(ctx, http.MethodPost, "/hsm/v2/Inventory/EthernetInterfaces", ei, nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusConflict {
+		return nil
+	}
+	patch := struct {
+		ComponentID string `json:"ComponentID"`
+		Description string `json:"Description,omitempty"`
+	}{ei.ComponentID, ei.Description}
+	_, err = c.do(ctx, http.MethodPatch, "/hsm/v2/Inventory/EthernetInterfaces/"+InterfaceID(ei.MACAddress), patch, nil)
+	return err
+}
+
+// EthernetInterfaces returns every entry of the EthernetInterfaces inventory.
+func (c *Client) EthernetInterfaces(ctx context.Context) ([]EthernetInterface, error) {
+	var out []EthernetInterface
+	if _, err := c.do(ctx, http.MethodGet, "/hsm/v2/Inventory/EthernetInterfaces", nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateEthernetInterface adds ei; an entry for its MAC must not exist yet.
+func (c *Client) CreateEthernetInterface(ctx context.Context, ei EthernetInterface) error {
+	status, err := c.do(ctx, http.MethodPost, "/hsm/v2/Inventory/EthernetInterfaces", ei, nil)
+	if err == nil && status == http.StatusConflict {
+		err = fmt.Errorf("%s: already in SMD", ei.MACAddress)
+	}
+	return err
+}
+
+// SetIPAddresses replaces the IP addresses of the entry for mac.
+func (c *Client) SetIPAddresses(ctx context.Context, mac string, ips []IPAddress) error {
+	patch := struct {
+		IPAddresses []IPAddress `json:"IPAddresses"`
+	}{ips}
+	status, err := c.do(ctx, http.MethodPatch, "/hsm/v2/Inventory/EthernetInterfaces/"+InterfaceID(mac), patch, nil)
+	if err == nil && status == http.StatusConflict {
+		err = fmt.Errorf("%s: %s", mac, http.StatusText(status))
+	}
+	return err
+}
+
+// do sends body, unless nil, as JSON, decodes the answer into out, unless nil,
+// and returns the status code. 409 is returned without an error so callers can
+// fall back to an update; other non-2xx codes are errors.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) (int, error) {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.URL+path, r)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	hc := c.HTTP
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 && out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("%s %s: %w", method, path, err)
+		}
+	}
+	if resp.StatusCode == http.StatusConflict || resp.StatusCode/100 == 2 {
+		return resp.StatusCode, nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return resp.StatusCode, fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(msg)))
+}
@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package smd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPutEthernetInterface_PatchesOnConflict(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("Authorization = %q", got)
+		}
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["ComponentID"] != "x1000c0s0b0n0" {
+			t.Errorf("ComponentID = %q", body["ComponentID"])
+		}
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL+"/", "tok", 5*time.Second)
+	err := c.PutEthernetInterface(context.Background(), EthernetInterface{MACAddress: "02:00:00:AA:BB:CC", ComponentID: "x1000c0s0b0n0"})
+	if err != nil {
+		t.Fatalf("PutEthernetInterface: %v", err)
+	}
+	want := []string{"POST /hsm/v2/Inventory/EthernetInterfaces", "PATCH /hsm/v2/Inventory/EthernetInterfaces/020000aabbcc"}
+	if len(calls) != 2 || calls[0] != want[0] || calls[1] != want[1] {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestPutEthernetInterface_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "bad token", http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	err := New(srv.URL, "", time.Second).PutEthernetInterface(context.Background(), EthernetInterface{MACAddress: "02:00:00:aa:bb:cc"})
+	if err == nil {
+		t.Fatal("expected error for 401")
+	}
+}
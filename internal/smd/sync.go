@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package smd
+
+import (
+	"slices"
+	"strings"
+)
+
+// Binding is a MAC and IP the inventory allocated to a component.
+type Binding struct {
+	ComponentID string
+	MAC         string
+	IP          string // "" when none is allocated yet
+}
+
+// Sync actions and conflicts
+const (
+	InSync   = "ok"        // SMD has the MAC for the component with the IP
+	Create   = "create"    // SMD has no entry for the MAC
+	AddIP    = "add-ip"    // SMD has the MAC for the component without any IP
+	IPDiffer = "ip"        // SMD has the MAC with other IPs
+	Owner    = "owner"     // SMD has the MAC for another component
+	IPInUse  = "ip-in-use" // SMD has the IP for another MAC
+)
+
+// SyncItem is the outcome of comparing a Binding with SMD.
+type SyncItem struct {
+	Kind        string `json:"kind"`
+	ComponentID string `json:"component_id"`
+	MAC         string `json:"mac"`
+	IP          string `json:"ip,omitempty"`
+	SMD         string `json:"smd,omitempty"` // what SMD has instead, for conflicts
+}
+
+// Conflict reports whether the item needs a person: SMD disagrees with the
+// inventory and is not changed.
+func (s SyncItem) Conflict() bool {
+	return s.Kind == IPDiffer || s.Kind == Owner || s.Kind == IPInUse
+}
+
+// Compare matches bindings to the EthernetInterfaces SMD has, by MAC. An IP
+// SMD already holds for another MAC is IPInUse, and no change is planned for
+// it.
+func Compare(bindings []Binding, have []EthernetInterface) []SyncItem {
+	byID := map[string]EthernetInterface{}
+	ipOwner := map[string]EthernetInterface{}
+	for _, ei := range have {
+		byID[InterfaceID(ei.MACAddress)] = ei
+		for _, ip := range ei.IPAddresses {
+			ipOwner[ip.IPAddress] = ei
+		}
+	}
+	var out []SyncItem
+	for _, b := range bindings {
+		id := InterfaceID(b.MAC)
+		item := SyncItem{ComponentID: b.ComponentID, MAC: strings.ToLower(b.MAC), IP: b.IP}
+		if o, ok := ipOwner[b.IP]; ok && b.IP != "" && InterfaceID(o.MACAddress) != id {
+			item.Kind, item.SMD = IPInUse, o.MACAddress+" ("+o.ComponentID+")"
+			out = append(out, item)
+			continue
+		}
+		ei, ok := byID[id]
+		switch {
+		case !ok:
+			item.Kind = Create
+		case !strings.EqualFold(ei.ComponentID, b.ComponentID):
+			item.Kind, item.SMD = Owner, ei.ComponentID
+		case b.IP == "" || slices.ContainsFunc(ei.IPAddresses, func(ip IPAddress) bool { return ip.IPAddress == b.IP }):
+			item.Kind = InSync
+		case len(ei.IPAddresses) == 0:
+			item.Kind = AddIP
+		default:
+			var ips []string
+			for _, ip := range ei.IPAddresses {
+				ips = append(ips, ip.IPAddress)
+			}
+			item.Kind, item.SMD = IPDiffer, strings.Join(ips, ",")
+		}
+		out = append(out, item)
+	}
+	return out
+}
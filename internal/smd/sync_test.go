@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package smd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCompare(t *testing.T) {
+	have := []EthernetInterface{
+		{MACAddress: "aa:00:00:00:00:01", ComponentID: "x1000c0s0b0n0", IPAddresses: []IPAddress{{IPAddress: "10.2.0.1"}}},
+		{MACAddress: "AA:00:00:00:00:02", ComponentID: "x1000c0s0b0n1"},
+		{MACAddress: "aa:00:00:00:00:03", ComponentID: "x1000c0s1b0n0", IPAddresses: []IPAddress{{IPAddress: "10.2.0.99"}}},
+		{MACAddress: "aa:00:00:00:00:04", ComponentID: "x1000c0s9b0n0"},
+		{MACAddress: "aa:00:00:00:00:09", ComponentID: "x1000c0s9b0n1", IPAddresses: []IPAddress{{IPAddress: "10.2.0.6"}}},
+	}
+	got := Compare([]Binding{
+		{ComponentID: "x1000c0s0b0n0", MAC: "AA:00:00:00:00:01", IP: "10.2.0.1"},
+		{ComponentID: "x1000c0s0b0n1", MAC: "aa:00:00:00:00:02", IP: "10.2.0.2"},
+		{ComponentID: "x1000c0s1b0n0", MAC: "aa:00:00:00:00:03", IP: "10.2.0.3"},
+		{ComponentID: "x1000c0s2b0n0", MAC: "aa:00:00:00:00:04", IP: "10.2.0.4"},
+		{ComponentID: "x1000c0s3b0n0", MAC: "aa:00:00:00:00:05", IP: "10.2.0.5"},
+		{ComponentID: "x1000c0s4b0n0", MAC: "aa:00:00:00:00:06", IP: "10.2.0.6"},
+	}, have)
+	want := []SyncItem{
+		{Kind: InSync, ComponentID: "x1000c0s0b0n0", MAC: "aa:00:00:00:00:01", IP: "10.2.0.1"},
+		{Kind: AddIP, ComponentID: "x1000c0s0b0n1", MAC: "aa:00:00:00:00:02", IP: "10.2.0.2"},
+		{Kind: IPDiffer, ComponentID: "x1000c0s1b0n0", MAC: "aa:00:00:00:00:03", IP: "10.2.0.3", SMD: "10.2.0.99"},
+		{Kind: Owner, ComponentID: "x1000c0s2b0n0", MAC: "aa:00:00:00:00:04", IP: "10.2.0.4", SMD: "x1000c0s9b0n0"},
+		{Kind: Create, ComponentID: "x1000c0s3b0n0", MAC: "aa:00:00:00:00:05", IP: "10.2.0.5"},
+		{Kind: IPInUse, ComponentID: "x1000c0s4b0n0", MAC: "aa:00:00:00:00:06", IP: "10.2.0.6", SMD: "aa:00:00:00:00:09 (x1000c0s9b0n1)"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+	for _, s := range got {
+		if s.Conflict() != (s.Kind == IPDiffer || s.Kind == Owner || s.Kind == IPInUse) {
+			t.Errorf("%s: Conflict() = %v", s.Kind, s.Conflict())
+		}
+	}
+}
+
+func TestEthernetInterfacesAndSetIP(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		calls = append(calls, fmt.Sprintf("%s %s %s", r.Method, r.URL.Path, body))
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode([]EthernetInterface{{ID: "aa0000000001", MACAddress: "aa:00:00:00:00:01", ComponentID: "x1000c0s0b0n0"}})
+		}
+	}))
+	defer srv.Close()
+
+	c := New(srv.URL, "", 5*time.Second)
+	got, err := c.EthernetInterfaces(context.Background())
+	if err != nil || len(got) != 1 || got[0].ComponentID != "x1000c0s0b0n0" {
+		t.Fatalf("EthernetInterfaces = %+v, %v", got, err)
+	}
+	if err := c.SetIPAddresses(context.Background(), "AA:00:00:00:00:01", []IPAddress{{IPAddress: "10.2.0.1"}}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"GET /hsm/v2/Inventory/EthernetInterfaces ",
+		`PATCH /hsm/v2/Inventory/EthernetInterfaces/aa0000000001 {"IPAddresses":[{"IPAddress":"10.2.0.1"}]}`,
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"bootstrap/pkg/redfish"
+)
+
+// BIOSChange is a BIOS attribute whose current value differs from a snapshot.
+type BIOSChange struct {
+	Host      string `json:"host"`
+	System    string `json:"system"`
+	Attribute string `json:"attribute"`
+	Saved     any    `json:"saved"`
+	Current   any    `json:"current"` // nil when the system no longer reports the attribute
+}
+
+// TakeBIOS records only the BIOS attributes of the systems behind c, as a
+// snapshot that Restore and DiffBIOS accept.
+func TakeBIOS(ctx context.Context, c redfish.Client, host string, now time.Time) (Host, error) {
+	bios, err := c.GetBiosAttributes(ctx)
+	if err != nil {
+		return Host{}, err
+	}
+	h := Host{Host: host, TakenAt: now.UTC()}
+	for _, b := range bios {
+		h.Systems = append(h.Systems, System{Path: b.SystemPath, BIOS: b.Attributes})
+	}
+	return h, nil
+}
+
+// DiffBIOS returns the attributes recorded in h whose value in current differs,
+// sorted by system and attribute. Values are compared as text, since numbers
+// read back from a snapshot file are not the float64 JSON decodes.
+func DiffBIOS(h Host, current []redfish.SystemBios) []BIOSChange {
+	now := map[string]map[string]any{}
+	for _, b := range current {
+		now[b.SystemPath] = b.Attributes
+	}
+	var out []BIOSChange
+	for _, s := range h.Systems {
+		for name, saved := range s.BIOS {
+			cur, ok := now[s.Path][name]
+			if ok && fmt.Sprint(cur) == fmt.Sprint(saved) {
+				continue
+			}
+			out = append(out, BIOSChange{Host: h.Host, System: s.Path, Attribute: name, Saved: saved, Current: cur})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].System != out[j].System {
+			return out[i].System < out[j].System
+		}
+		return out[i].Attribute < out[j].Attribute
+	})
+	return out
+}
+
+// ReapplyBIOS writes the saved values of changes back, one settings PATCH per
+// system, staged for the next boot. Attributes the system no longer reports are
+// left out. It returns the number of attributes written.
+func ReapplyBIOS(ctx context.Context, c redfish.Client, changes []BIOSChange) (int, error) {
+	bySystem := map[string]map[string]any{}
+	var systems []string
+	for _, ch := range changes {
+		if ch.Current == nil {
+			continue
+		}
+		if bySystem[ch.System] == nil {
+			bySystem[ch.System] = map[string]any{}
+			systems = append(systems, ch.System)
+		}
+		bySystem[ch.System][ch.Attribute] = ch.Saved
+	}
+	n := 0
+	for _, s := range systems {
+		if err := c.SetBiosAttributes(ctx, s, bySystem[s]); err != nil {
+			return n, fmt.Errorf("%s: %w", s, err)
+		}
+		n += len(bySystem[s])
+	}
+	return n, nil
+}
@@ -0,0 +1,68 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package snapshot
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+)
+
+func TestBIOSBackupDiffReapply(t *testing.T) {
+	const node0, node1 = "/redfish/v1/Systems/Node0", "/redfish/v1/Systems/Node1"
+	bios := []redfish.SystemBios{
+		{SystemPath: node0, Attributes: map[string]any{"SMT": "Enabled", "NumaNodes": float64(4), "BootMode": "Uefi"}},
+		{SystemPath: node1, Attributes: map[string]any{"SMT": "Enabled"}},
+	}
+	patched := map[string]map[string]any{}
+	m := &redfishtest.MockClient{
+		GetBiosAttributesFunc: func(context.Context) ([]redfish.SystemBios, error) { return bios, nil },
+		SetBiosAttributesFunc: func(_ context.Context, path string, attrs map[string]any) error {
+			patched[path] = attrs
+			return nil
+		},
+	}
+	h, err := TakeBIOS(context.Background(), m, "x1000c0s0b0", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A snapshot file turns numbers into ints; they still compare equal
+	dir := t.TempDir()
+	if err := Save(dir, h); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := DiffBIOS(loaded[0], bios); len(got) != 0 {
+		t.Errorf("unchanged BIOS: %v", got)
+	}
+
+	// The update reset SMT on Node0 and dropped an attribute
+	bios = []redfish.SystemBios{
+		{SystemPath: node0, Attributes: map[string]any{"SMT": "Disabled", "NumaNodes": float64(4)}},
+		{SystemPath: node1, Attributes: map[string]any{"SMT": "Enabled"}},
+	}
+	changes := DiffBIOS(loaded[0], bios)
+	want := []BIOSChange{
+		{Host: "x1000c0s0b0", System: node0, Attribute: "BootMode", Saved: "Uefi"},
+		{Host: "x1000c0s0b0", System: node0, Attribute: "SMT", Saved: "Enabled", Current: "Disabled"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("changes = %+v, want %+v", changes, want)
+	}
+	n, err := ReapplyBIOS(context.Background(), m, changes)
+	if err != nil || n != 1 {
+		t.Fatalf("reapply = %d, %v", n, err)
+	}
+	if want := map[string]map[string]any{node0: {"SMT": "Enabled"}}; !reflect.DeepEqual(patched, want) {
+		t.Errorf("patched = %v", patched)
+	}
+}
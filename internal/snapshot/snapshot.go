@@ -0,0 +1,276 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package snapshot records the key configuration of BMCs to disk and re-applies
+// the settings that can be written back.
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"bootstrap/internal/sshkeys"
+	"bootstrap/pkg/redfish"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Protocol is the state of one network service of a BMC.
+type Protocol struct {
+	Enabled *bool `yaml:"enabled,omitempty"`
+	Port    int   `yaml:"port,omitempty"`
+}
+
+// Account is a BMC user account. Passwords are never recorded.
+type Account struct {
+	UserName string `yaml:"username"`
+	Role     string `yaml:"role"`
+	Enabled  bool   `yaml:"enabled"`
+}
+
+// Boot holds the boot settings of one system.
+type Boot struct {
+	OverrideEnabled string   `yaml:"override_enabled,omitempty"`
+	OverrideTarget  string   `yaml:"override_target,omitempty"`
+	OverrideMode    string   `yaml:"override_mode,omitempty"`
+	BootOrder       []string `yaml:"boot_order,omitempty"`
+}
+
+// System holds the settings of one system behind a BMC.
+type System struct {
+	Path        string         `yaml:"path"`
+	BiosVersion string         `yaml:"bios_version,omitempty"`
+	Boot        Boot           `yaml:"boot"`
+	BIOS        map[string]any `yaml:"bios_attributes,omitempty"`
+}
+
+// Host is the recorded configuration of one BMC.
+type Host struct {
+	Host      string              `yaml:"host"`
+	TakenAt   time.Time           `yaml:"taken_at"`
+	HostName  string              `yaml:"hostname,omitempty"`
+	Protocols map[string]Protocol `yaml:"protocols,omitempty"`
+	NTP       []string            `yaml:"ntp_servers,omitempty"`
+	SSHKeys   []string            `yaml:"ssh_keys,omitempty"`
+	Accounts  []Account           `yaml:"accounts,omitempty"`
+	Firmware  map[string]string   `yaml:"firmware,omitempty"` // FirmwareInventory path -> version
+	Systems   []System            `yaml:"systems,omitempty"`
+	// Errors lists the areas that could not be read, so a partial snapshot is
+	// not mistaken for an empty configuration.
+	Errors map[string]string `yaml:"errors,omitempty"`
+}
+
+// Areas of a snapshot
+const (
+	AreaNetwork  = "network"
+	AreaSSHKeys  = "ssh-keys"
+	AreaAccounts = "accounts"
+	AreaFirmware = "firmware"
+	AreaSystems  = "systems"
+	AreaBIOS     = "bios"
+)
+
+// areas lists every area Take reads.
+var areas = []string{AreaNetwork, AreaSSHKeys, AreaAccounts, AreaFirmware, AreaSystems, AreaBIOS}
+
+// Take reads the configuration of the BMC behind c. Areas that cannot be read
+// are recorded in Errors; an error is returned only when nothing could be read.
+func Take(ctx context.Context, c redfish.Client, host string, now time.Time) (Host, error) {
+	h := Host{Host: host, TakenAt: now.UTC(), Errors: map[string]string{}}
+	fail := func(area string, err error) { h.Errors[area] = err.Error() }
+
+	if np, err := c.GetNetworkProtocol(ctx); err != nil {
+		fail(AreaNetwork, err)
+	} else {
+		h.HostName, h.NTP = np.HostName, np.NTPServers
+		h.Protocols = map[string]Protocol{}
+		for name, p := range np.Protocols {
+			h.Protocols[name] = Protocol{Enabled: p.Enabled, Port: p.Port}
+		}
+	}
+	if keys, err := c.GetAuthorizedKeys(ctx); err != nil {
+		fail(AreaSSHKeys, err)
+	} else {
+		h.SSHKeys = sshkeys.Parse(keys)
+	}
+	if accts, err := c.GetAccounts(ctx); err != nil {
+		fail(AreaAccounts, err)
+	} else {
+		for _, a := range accts {
+			h.Accounts = append(h.Accounts, Account{UserName: a.UserName, Role: a.RoleID, Enabled: a.Enabled})
+		}
+	}
+	if fw, err := c.ListFirmware(ctx); err != nil {
+		fail(AreaFirmware, err)
+	} else {
+		h.Firmware = map[string]string{}
+		for _, f := range fw {
+			h.Firmware[f.Path] = f.Version
+		}
+	}
+	systems, err := c.GetSystems(ctx)
+	if err != nil {
+		fail(AreaSystems, err)
+	}
+	for _, s := range systems {
+		h.Systems = append(h.Systems, System{
+			Path:        s.ODataID,
+			BiosVersion: s.BiosVersion,
+			Boot: Boot{
+				OverrideEnabled: s.Boot.BootSourceOverrideEnabled,
+				OverrideTarget:  s.Boot.BootSourceOverrideTarget,
+				OverrideMode:    s.Boot.BootSourceOverrideMode,
+				BootOrder:       s.Boot.BootOrder,
+			},
+		})
+	}
+	if bios, err := c.GetBiosAttributes(ctx); err != nil {
+		fail(AreaBIOS, err)
+	} else {
+		for _, b := range bios {
+			i := h.system(b.SystemPath)
+			h.Systems[i].BIOS = b.Attributes
+		}
+	}
+	if len(h.Errors) == len(areas) {
+		return h, fmt.Errorf("nothing could be read (network protocol: %s)", h.Errors[AreaNetwork])
+	}
+	if len(h.Errors) == 0 {
+		h.Errors = nil
+	}
+	return h, nil
+}
+
+// system returns the index of the system at path, adding it if missing.
+func (h *Host) system(path string) int {
+	for i, s := range h.Systems {
+		if s.Path == path {
+			return i
+		}
+	}
+	h.Systems = append(h.Systems, System{Path: path})
+	return len(h.Systems) - 1
+}
+
+// FileName returns the name of the snapshot file for host.
+func FileName(host string) string {
+	return strings.NewReplacer("/", "_", ":", "_").Replace(host) + ".yaml"
+}
+
+// Save writes h to dir, creating dir if needed.
+func Save(dir string, h Host) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	out, err := yaml.Marshal(&h)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, FileName(h.Host)), out, 0o600)
+}
+
+// Load reads every host snapshot in dir, sorted by host.
+func Load(dir string) ([]Host, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	var out []Host
+	for _, f := range files {
+		raw, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		var h Host
+		if err := yaml.Unmarshal(raw, &h); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", f, err)
+		}
+		if h.Host == "" {
+			return nil, fmt.Errorf("%s: not a host snapshot (missing host)", f)
+		}
+		out = append(out, h)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no host snapshots in %s", dir)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Host < out[j].Host })
+	return out, nil
+}
+
+// Restorable areas, in the order they are applied.
+var Restorable = []string{AreaSSHKeys, AreaNetwork, AreaBIOS}
+
+// Step is one setting re-applied (or planned) by Restore.
+type Step struct {
+	Area   string
+	Target string
+	Detail string
+}
+
+// Plan returns the settings Restore writes for h, limited to the areas in only
+// (all restorable areas when empty). Areas recorded as unreadable are skipped.
+// Accounts, firmware versions, protocol enablement and boot settings are
+// recorded for reference only.
+func Plan(h Host, only []string) []Step {
+	want := map[string]bool{}
+	for _, a := range only {
+		want[a] = true
+	}
+	use := func(area string) bool {
+		if _, failed := h.Errors[area]; failed {
+			return false
+		}
+		return len(want) == 0 || want[area]
+	}
+	var steps []Step
+	if use(AreaSSHKeys) && len(h.SSHKeys) > 0 {
+		steps = append(steps, Step{Area: AreaSSHKeys, Detail: fmt.Sprintf("%d authorized key(s)", len(h.SSHKeys))})
+	}
+	if use(AreaNetwork) && len(h.NTP) > 0 {
+		steps = append(steps, Step{Area: AreaNetwork, Target: "NTP", Detail: strings.Join(h.NTP, ",")})
+	}
+	if use(AreaBIOS) {
+		for _, s := range h.Systems {
+			if len(s.BIOS) > 0 {
+				steps = append(steps, Step{Area: AreaBIOS, Target: s.Path, Detail: fmt.Sprintf("%d attribute(s), staged for next boot", len(s.BIOS))})
+			}
+		}
+	}
+	return steps
+}
+
+// Restore applies steps (as returned by Plan for h) to the BMC behind c and
+// stops at the first failure. The SSH authorized keys are replaced with the
+// recorded set, not merged.
+func Restore(ctx context.Context, c redfish.Client, h Host, steps []Step) error {
+	bios := map[string]map[string]any{}
+	for _, s := range h.Systems {
+		bios[s.Path] = s.BIOS
+	}
+	for _, st := range steps {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var err error
+		switch st.Area {
+		case AreaSSHKeys:
+			err = c.SetAuthorizedKeys(ctx, sshkeys.Join(h.SSHKeys))
+		case AreaNetwork:
+			err = c.SetNTPServers(ctx, h.NTP)
+		case AreaBIOS:
+			err = c.SetBiosAttributes(ctx, st.Target, bios[st.Target])
+		default:
+			err = fmt.Errorf("area %q cannot be restored", st.Area)
+		}
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", st.Area, st.Target, err)
+		}
+	}
+	return nil
+}
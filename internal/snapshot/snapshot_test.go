@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"bootstrap/pkg/redfish"
+	"bootstrap/pkg/redfish/redfishtest"
+)
+
+func TestTakeSaveLoadPlan(t *testing.T) {
+	on := true
+	var sys redfish.System
+	sys.ODataID = "/redfish/v1/Systems/Node0"
+	sys.BiosVersion = "bios.2.0"
+	sys.Boot.BootOrder = []string{"Pxe", "Hdd"}
+	m := &redfishtest.MockClient{
+		GetNetworkProtocolFunc: func(context.Context) (redfish.NetworkProtocol, error) {
+			return redfish.NetworkProtocol{
+				HostName:   "x1000c0s0b0",
+				Protocols:  map[string]redfish.Protocol{"SSH": {Enabled: &on, Port: 22}},
+				NTPServers: []string{"10.0.0.1"},
+			}, nil
+		},
+		GetAuthorizedKeysFunc: func(context.Context) (string, error) { return "ssh-ed25519 AAAA ops\n", nil },
+		GetAccountsFunc: func(context.Context) ([]redfish.Account, error) {
+			return []redfish.Account{{UserName: "root", RoleID: "Administrator", Enabled: true}}, nil
+		},
+		ListFirmwareFunc: func(context.Context) ([]redfish.FirmwareVersion, error) {
+			return nil, errors.New("404 Not Found")
+		},
+		GetSystemsFunc: func(context.Context) ([]redfish.System, error) { return []redfish.System{sys}, nil },
+		GetBiosAttributesFunc: func(context.Context) ([]redfish.SystemBios, error) {
+			return []redfish.SystemBios{{SystemPath: "/redfish/v1/Systems/Node0", Attributes: map[string]any{"SMT": "Enabled"}}}, nil
+		},
+	}
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	h, err := Take(context.Background(), m, "10.1.0.2", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := h.Errors[AreaFirmware]; !ok || len(h.Errors) != 1 {
+		t.Errorf("errors = %v, want only firmware", h.Errors)
+	}
+
+	dir := t.TempDir()
+	if err := Save(dir, h); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded) != 1 || !reflect.DeepEqual(loaded[0], h) {
+		t.Fatalf("loaded %+v, want %+v", loaded, h)
+	}
+
+	steps := Plan(h, nil)
+	var got []string
+	for _, s := range steps {
+		got = append(got, s.Area+" "+s.Target)
+	}
+	want := []string{"ssh-keys ", "network NTP", "bios /redfish/v1/Systems/Node0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("plan = %q, want %q", got, want)
+	}
+	if steps := Plan(h, []string{AreaBIOS}); len(steps) != 1 {
+		t.Errorf("plan bios only = %+v", steps)
+	}
+
+	var ntp []string
+	var keys string
+	bios := map[string]map[string]any{}
+	w := &redfishtest.MockClient{
+		SetAuthorizedKeysFunc: func(_ context.Context, k string) error { keys = k; return nil },
+		SetNTPServersFunc:     func(_ context.Context, s []string) error { ntp = s; return nil },
+		SetBiosAttributesFunc: func(_ context.Context, p string, a map[string]any) error { bios[p] = a; return nil },
+	}
+	if err := Restore(context.Background(), w, loaded[0], steps); err != nil {
+		t.Fatal(err)
+	}
+	if keys != "ssh-ed25519 AAAA ops\n" || !reflect.DeepEqual(ntp, []string{"10.0.0.1"}) || bios["/redfish/v1/Systems/Node0"]["SMT"] != "Enabled" {
+		t.Errorf("restored keys %q, ntp %v, bios %v", keys, ntp, bios)
+	}
+}
+
+func TestTakeNothingReadable(t *testing.T) {
+	if _, err := Take(context.Background(), &redfishtest.MockClient{}, "h", time.Now()); err == nil {
+		t.Error("expected error when no area can be read")
+	}
+}
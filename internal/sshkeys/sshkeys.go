@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package sshkeys handles lists of SSH authorized keys.
+package sshkeys
+
+import (
+	"os"
+	"strings"
+)
+
+// Parse splits authorized_keys text into one key per entry, dropping blank lines
+// and comments.
+func Parse(text string) []string {
+	var out []string
+	for _, l := range strings.Split(text, "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		out = append(out, l)
+	}
+	return out
+}
+
+// ReadFiles reads and concatenates the keys from each public key file.
+func ReadFiles(paths []string) ([]string, error) {
+	var out []string
+	for _, p := range paths {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, Parse(string(b))...)
+	}
+	return out, nil
+}
+
+// identity returns the key type and blob, ignoring the trailing comment, so keys
+// that differ only by comment compare equal.
+func identity(key string) string {
+	f := strings.Fields(key)
+	if len(f) >= 2 {
+		return f[0] + " " + f[1]
+	}
+	return key
+}
+
+// Missing returns the keys in want that are not present in have.
+func Missing(have, want []string) []string {
+	set := map[string]bool{}
+	for _, k := range have {
+		set[identity(k)] = true
+	}
+	var out []string
+	for _, k := range want {
+		if !set[identity(k)] {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// Merge returns have with any keys from add that are not already present appended.
+func Merge(have, add []string) []string {
+	out := append([]string{}, have...)
+	return append(out, Missing(have, add)...)
+}
+
+// Join formats keys as authorized_keys text.
+func Join(keys []string) string {
+	if len(keys) == 0 {
+		return ""
+	}
+	return strings.Join(keys, "\n") + "\n"
+}
@@ -0,0 +1,33 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package sshkeys
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	got := Parse("# admin keys\nssh-ed25519 AAAA1 alice\n\n  ssh-rsa BBBB2 bob  \n")
+	want := []string{"ssh-ed25519 AAAA1 alice", "ssh-rsa BBBB2 bob"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Parse mismatch: got=%q want=%q", got, want)
+	}
+}
+
+func TestMergeAndMissing(t *testing.T) {
+	have := []string{"ssh-ed25519 AAAA1 alice@laptop"}
+	add := []string{"ssh-ed25519 AAAA1 alice", "ssh-rsa BBBB2 bob"}
+	if got := Missing(have, add); !reflect.DeepEqual(got, []string{"ssh-rsa BBBB2 bob"}) {
+		t.Fatalf("Missing mismatch: got=%q", got)
+	}
+	want := []string{"ssh-ed25519 AAAA1 alice@laptop", "ssh-rsa BBBB2 bob"}
+	if got := Merge(have, add); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Merge mismatch: got=%q want=%q", got, want)
+	}
+	if got := Join(want); got != "ssh-ed25519 AAAA1 alice@laptop\nssh-rsa BBBB2 bob\n" {
+		t.Fatalf("Join mismatch: got=%q", got)
+	}
+}
@@ -0,0 +1,201 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package statusdb keeps the results of firmware status runs in a bbolt
+// database, so a run can report what changed since the previous one and the
+// runs add up to trend data.
+package statusdb
+
+import (
+	"cmp"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Buckets: runs holds every run keyed by its start time; latest holds the last
+// known result of each host and target with the times tracked across runs.
+var (
+	runsBucket   = []byte("runs")
+	latestBucket = []byte("latest")
+)
+
+// MaxRuns is how many runs the database keeps; older ones are dropped.
+const MaxRuns = 1000
+
+// Result is the status of one target of one host in a run.
+type Result struct {
+	Host            string `json:"host"`
+	Xname           string `json:"xname,omitempty"`
+	Target          string `json:"target"`
+	Version         string `json:"version"` // "(unknown)" when it could not be read
+	Status          string `json:"status"`  // idle, in-progress or error
+	Error           string `json:"error,omitempty"`
+	PercentComplete *int   `json:"percent_complete,omitempty"`
+}
+
+// Run is one firmware status run.
+type Run struct {
+	Time    time.Time `json:"time"`
+	Results []Result  `json:"results"`
+}
+
+// latest is the last result of a host and target.
+type latest struct {
+	Result
+	Seen            time.Time `json:"seen"`                        // run that reported it
+	InProgressSince time.Time `json:"in_progress_since,omitempty"` // first run of the current in-progress streak
+}
+
+// Change kinds
+const (
+	VersionChanged = "version" // the version differs from the last run
+	NewError       = "error"   // in error, and was not in the last run
+	Recovered      = "ok"      // was in error in the last run, is not now
+	Stuck          = "stuck"   // in progress for longer than the threshold
+)
+
+// Change is a difference from the previous run.
+type Change struct {
+	Kind   string    `json:"kind"`
+	Host   string    `json:"host"`
+	Xname  string    `json:"xname,omitempty"`
+	Target string    `json:"target"`
+	From   string    `json:"from,omitempty"`  // previous version or status
+	To     string    `json:"to,omitempty"`    // current version, status or error
+	Since  time.Time `json:"since,omitempty"` // for Stuck: when the update was first seen in progress
+}
+
+// DB is an open status database.
+type DB struct {
+	db *bolt.DB
+}
+
+// Open opens the database at path, creating it if needed. It waits up to
+// timeout for another run holding it (0 waits indefinitely).
+func Open(path string, timeout time.Duration) (*DB, error) {
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: timeout})
+	if err != nil {
+		if errors.Is(err, bolt.ErrTimeout) {
+			return nil, fmt.Errorf("%s: in use by another process", path)
+		}
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &DB{db: db}, nil
+}
+
+// Close closes the database.
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// Record stores run and returns the time of the previous run (zero for the
+// first one) and the changes since it. Targets in progress for longer than
+// stuckAfter are reported as Stuck; stuckAfter <= 0 disables that.
+func (d *DB) Record(run Run, stuckAfter time.Duration) (time.Time, []Change, error) {
+	var prevTime time.Time
+	var changes []Change
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		runs, err := tx.CreateBucketIfNotExists(runsBucket)
+		if err != nil {
+			return err
+		}
+		last, err := tx.CreateBucketIfNotExists(latestBucket)
+		if err != nil {
+			return err
+		}
+		if k, _ := runs.Cursor().Last(); k != nil {
+			prevTime = time.Unix(0, int64(binary.BigEndian.Uint64(k))).UTC()
+		}
+		for _, r := range run.Results {
+			key := []byte(r.Host + "\x00" + r.Target)
+			cur := latest{Result: r, Seen: run.Time}
+			var prev latest
+			seen := false
+			if b := last.Get(key); b != nil {
+				seen = json.Unmarshal(b, &prev) == nil
+			}
+			if r.Status == "in-progress" {
+				cur.InProgressSince = run.Time
+				if seen && prev.Status == "in-progress" && !prev.InProgressSince.IsZero() {
+					cur.InProgressSince = prev.InProgressSince
+				}
+				if stuckAfter > 0 && run.Time.Sub(cur.InProgressSince) > stuckAfter {
+					changes = append(changes, Change{Kind: Stuck, Host: r.Host, Xname: r.Xname, Target: r.Target, To: r.Version, Since: cur.InProgressSince})
+				}
+			}
+			if seen {
+				changes = append(changes, compare(prev.Result, r)...)
+			}
+			b, err := json.Marshal(cur)
+			if err != nil {
+				return err
+			}
+			if err := last.Put(key, b); err != nil {
+				return err
+			}
+		}
+		b, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+		if err := runs.Put(timeKey(run.Time), b); err != nil {
+			return err
+		}
+		return prune(runs)
+	})
+	slices.SortStableFunc(changes, func(a, b Change) int {
+		return cmp.Or(cmp.Compare(a.Host, b.Host), cmp.Compare(a.Target, b.Target))
+	})
+	return prevTime, changes, err
+}
+
+// compare returns the changes from prev to cur of one host and target.
+func compare(prev, cur Result) []Change {
+	c := Change{Host: cur.Host, Xname: cur.Xname, Target: cur.Target}
+	var out []Change
+	if prev.Version != cur.Version && known(prev.Version) && known(cur.Version) {
+		c.Kind, c.From, c.To = VersionChanged, prev.Version, cur.Version
+		out = append(out, c)
+	}
+	switch {
+	case cur.Status == "error" && prev.Status != "error":
+		c.Kind, c.From, c.To = NewError, prev.Status, cur.Error
+		out = append(out, c)
+	case cur.Status != "error" && prev.Status == "error":
+		c.Kind, c.From, c.To = Recovered, prev.Error, cur.Status
+		out = append(out, c)
+	}
+	return out
+}
+
+func known(version string) bool {
+	return version != "" && version != "(unknown)"
+}
+
+func timeKey(t time.Time) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(t.UnixNano()))
+	return k
+}
+
+// prune drops the oldest runs beyond MaxRuns.
+func prune(runs *bolt.Bucket) error {
+	var old [][]byte
+	n, c := runs.Stats().KeyN, runs.Cursor()
+	for k, _ := c.First(); k != nil && n-len(old) > MaxRuns; k, _ = c.Next() {
+		old = append(old, k)
+	}
+	for _, k := range old {
+		if err := runs.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package statusdb
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecordChanges(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "status.db"), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close() //nolint:errcheck
+	t0 := time.Date(2025, 7, 1, 2, 0, 0, 0, time.UTC)
+	const bmc = "/redfish/v1/UpdateService/FirmwareInventory/BMC"
+	record := func(at time.Time, results ...Result) (time.Time, []Change) {
+		t.Helper()
+		prev, changes, err := db.Record(Run{Time: at, Results: results}, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return prev, changes
+	}
+
+	prev, changes := record(t0,
+		Result{Host: "a", Target: bmc, Version: "1.1", Status: "idle"},
+		Result{Host: "b", Target: bmc, Version: "1.1", Status: "in-progress"},
+		Result{Host: "c", Target: bmc, Version: "(unknown)", Status: "error", Error: "timeout"})
+	if !prev.IsZero() || len(changes) != 0 {
+		t.Fatalf("first run: prev %v, changes %+v", prev, changes)
+	}
+
+	t1 := t0.Add(30 * time.Minute)
+	if prev, changes = record(t1,
+		Result{Host: "a", Target: bmc, Version: "1.2", Status: "error", Error: "health: Critical"},
+		Result{Host: "b", Target: bmc, Version: "1.1", Status: "in-progress"},
+		Result{Host: "c", Target: bmc, Version: "1.2", Status: "idle"}); !prev.Equal(t0) {
+		t.Fatalf("prev = %v, want %v", prev, t0)
+	}
+	want := []Change{
+		{Kind: VersionChanged, Host: "a", Target: bmc, From: "1.1", To: "1.2"},
+		{Kind: NewError, Host: "a", Target: bmc, From: "idle", To: "health: Critical"},
+		{Kind: Recovered, Host: "c", Target: bmc, From: "timeout", To: "idle"},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("changes =\n%+v\nwant\n%+v", changes, want)
+	}
+
+	// b has been in progress since the first run, longer than the hour allowed
+	_, changes = record(t0.Add(90*time.Minute), Result{Host: "b", Target: bmc, Version: "1.1", Status: "in-progress"})
+	want = []Change{{Kind: Stuck, Host: "b", Target: bmc, To: "1.1", Since: t0}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("changes = %+v, want %+v", changes, want)
+	}
+}
@@ -0,0 +1,104 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package topology
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Rollout orders: the sequence in which the BMCs of a batch command are
+// worked through.
+const (
+	// OrderInventory keeps the order the hosts were given in.
+	OrderInventory = "inventory"
+	// OrderRandom shuffles the hosts.
+	OrderRandom = "random"
+	// OrderByChassis takes one BMC from each chassis in turn, so consecutive
+	// hosts (and so one batch) are spread over chassis and cooling groups.
+	OrderByChassis = "by-chassis"
+	// OrderBySlot takes one BMC from each slot in turn, visiting the same slot
+	// of every chassis before the next slot, so the node cards of one blade are
+	// as far apart as the number of slots allows.
+	OrderBySlot = "by-slot-interleaved"
+)
+
+// Orders lists the rollout orders Order accepts.
+var Orders = []string{OrderInventory, OrderRandom, OrderByChassis, OrderBySlot}
+
+// Order returns the positions of xnames in the sequence order puts them in.
+// Entries that are not xnames (hosts outside the inventory) form one group,
+// visited after the others. shuffle is used for OrderRandom (e.g. rand.Shuffle).
+func Order(xnames []string, order string, shuffle func(n int, swap func(i, j int))) ([]int, error) {
+	idx := make([]int, len(xnames))
+	for i := range idx {
+		idx[i] = i
+	}
+	switch order {
+	case "", OrderInventory:
+		return idx, nil
+	case OrderRandom:
+		shuffle(len(idx), func(i, j int) { idx[i], idx[j] = idx[j], idx[i] })
+		return idx, nil
+	case OrderByChassis:
+		return interleave(xnames, 2, func(p []int) []int { return p }), nil
+	case OrderBySlot:
+		// Slot first, then cabinet and chassis
+		return interleave(xnames, 3, func(p []int) []int { return []int{p[2], p[0], p[1]} }), nil
+	}
+	return nil, fmt.Errorf("unknown order %q (want %s)", order, strings.Join(Orders, ", "))
+}
+
+// interleave groups xnames by the first depth indexes of their path (2 for
+// chassis, 3 for slot), sorts the groups by the indexes rank returns, and takes
+// one entry of each group in turn, keeping the given order within a group.
+func interleave(xnames []string, depth int, rank func(path []int) []int) []int {
+	type group struct {
+		rank    []int // nil for entries too shallow to group
+		members []int
+	}
+	var groups []*group
+	byKey := map[string]*group{}
+	for i, x := range xnames {
+		key, r := "", []int(nil)
+		if _, p := parse(x); len(p) >= depth {
+			key, r = fmt.Sprint(p[:depth]), rank(p[:depth])
+		}
+		g := byKey[key]
+		if g == nil {
+			g = &group{rank: r}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.members = append(g.members, i)
+	}
+	slices.SortStableFunc(groups, func(a, b *group) int {
+		if a.rank == nil || b.rank == nil {
+			return boolCompare(a.rank == nil, b.rank == nil)
+		}
+		return slices.Compare(a.rank, b.rank)
+	})
+	out := make([]int, 0, len(xnames))
+	for round := 0; len(out) < len(xnames); round++ {
+		for _, g := range groups {
+			if round < len(g.members) {
+				out = append(out, g.members[round])
+			}
+		}
+	}
+	return out
+}
+
+// boolCompare orders false before true.
+func boolCompare(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
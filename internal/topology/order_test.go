@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package topology
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestOrder(t *testing.T) {
+	xnames := []string{
+		"x1000c0s0b0", "x1000c0s0b1", "x1000c0s1b0", "x1000c0s1b1",
+		"x1000c1s0b0", "x1000c1s0b1",
+		"", // a host outside the inventory
+		"x1001c0s1b0",
+	}
+	pick := func(idx []int) []string {
+		out := make([]string, len(idx))
+		for i, j := range idx {
+			out[i] = xnames[j]
+		}
+		return out
+	}
+	tests := map[string][]string{
+		OrderInventory: xnames,
+		OrderByChassis: {
+			"x1000c0s0b0", "x1000c1s0b0", "x1001c0s1b0", "",
+			"x1000c0s0b1", "x1000c1s0b1",
+			"x1000c0s1b0",
+			"x1000c0s1b1",
+		},
+		OrderBySlot: {
+			"x1000c0s0b0", "x1000c1s0b0", "x1000c0s1b0", "x1001c0s1b0", "",
+			"x1000c0s0b1", "x1000c1s0b1", "x1000c0s1b1",
+		},
+	}
+	for order, want := range tests {
+		idx, err := Order(xnames, order, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := pick(idx); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s:\n got %v\nwant %v", order, got, want)
+		}
+	}
+
+	r := rand.New(rand.NewPCG(1, 2))
+	idx, err := Order(xnames, OrderRandom, r.Shuffle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sorted := slices.Sorted(slices.Values(idx)); !reflect.DeepEqual(sorted, []int{0, 1, 2, 3, 4, 5, 6, 7}) || reflect.DeepEqual(idx, sorted) {
+		t.Errorf("random = %v", idx)
+	}
+	if _, err := Order(xnames, "by-rack", nil); err == nil {
+		t.Error("accepted an unknown order")
+	}
+}
@@ -0,0 +1,256 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package topology arranges inventory entries by xname into the physical tree
+// of a system: cabinet, chassis, slot, blade (a node card with its BMC) and node.
+package topology
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"bootstrap/pkg/inventory"
+)
+
+// Component kinds, from the root of the tree down.
+const (
+	Cabinet = "cabinet"
+	Chassis = "chassis"
+	Slot    = "slot"
+	Blade   = "blade"
+	Node    = "node"
+)
+
+var kinds = []string{Cabinet, Chassis, Slot, Blade, Node}
+
+// xnamePattern matches an xname down to any level: x1000, x1000c0, ...,
+// x1000c0s0b0n0.
+var xnamePattern = regexp.MustCompile(`^x(\d+)(?:c(\d+)(?:s(\d+)(?:b(\d+)(?:n(\d+))?)?)?)?$`)
+
+// Component is one element of the tree.
+type Component struct {
+	Xname    string
+	Kind     string
+	Index    int              // the number of the component within its parent, e.g. 3 for x1000c3
+	Entry    *inventory.Entry // the bmcs[] entry of a blade or nodes[] entry of a node; nil otherwise
+	Children []*Component
+}
+
+// Topology is the tree of an inventory.
+type Topology struct {
+	Cabinets []*Component
+	// Unplaced are the entries whose xname does not name a blade (bmcs[]) or a
+	// node (nodes[]).
+	Unplaced []inventory.Entry
+
+	byXname map[string]*Component
+}
+
+// parse returns the kind of an xname and the indexes along its path, or "" when
+// it is not an xname.
+func parse(x string) (string, []int) {
+	m := xnamePattern.FindStringSubmatch(strings.ToLower(x))
+	if m == nil {
+		return "", nil
+	}
+	var idx []int
+	for _, s := range m[1:] {
+		if s == "" {
+			break
+		}
+		n, _ := strconv.Atoi(s)
+		idx = append(idx, n)
+	}
+	return kinds[len(idx)-1], idx
+}
+
+// Build arranges the bmcs[] and nodes[] of doc into a tree. Cabinets, chassis
+// and slots exist as far as some entry lies in them; a node's blade exists even
+// when bmcs[] does not list its BMC. Children are ordered by index.
+func Build(doc inventory.FileFormat) *Topology {
+	t := &Topology{byXname: map[string]*Component{}}
+	place := func(e inventory.Entry, want string) {
+		kind, idx := parse(e.Xname)
+		if kind != want {
+			t.Unplaced = append(t.Unplaced, e)
+			return
+		}
+		c := t.component(idx)
+		if c.Entry == nil {
+			c.Entry = &e
+		}
+	}
+	for _, b := range doc.BMCs {
+		place(b, Blade)
+	}
+	for _, n := range doc.Nodes {
+		place(n, Node)
+	}
+	t.sort(t.Cabinets)
+	return t
+}
+
+// component returns the component at the path idx, adding it and its parents
+// as needed.
+func (t *Topology) component(idx []int) *Component {
+	var parent *Component
+	var x strings.Builder
+	for level, n := range idx {
+		x.WriteString(string("xcsbn"[level]) + strconv.Itoa(n))
+		c, ok := t.byXname[x.String()]
+		if !ok {
+			c = &Component{Xname: x.String(), Kind: kinds[level], Index: n}
+			t.byXname[c.Xname] = c
+			if parent == nil {
+				t.Cabinets = append(t.Cabinets, c)
+			} else {
+				parent.Children = append(parent.Children, c)
+			}
+		}
+		parent = c
+	}
+	return parent
+}
+
+func (t *Topology) sort(list []*Component) {
+	slices.SortFunc(list, func(a, b *Component) int { return cmp.Compare(a.Index, b.Index) })
+	for _, c := range list {
+		t.sort(c.Children)
+	}
+}
+
+// Find returns the component named by xname, or nil.
+func (t *Topology) Find(xname string) *Component {
+	return t.byXname[strings.ToLower(xname)]
+}
+
+// BMCs returns the bmcs[] entries of the blades at or below the component named
+// by xname, or of the blade holding it when it is a node. It returns nil when
+// xname is not in the tree.
+func (t *Topology) BMCs(xname string) []inventory.Entry {
+	c := t.Find(xname)
+	if c != nil && c.Kind == Node {
+		c = t.Find(c.Xname[:strings.LastIndex(c.Xname, "n")])
+	}
+	var out []inventory.Entry
+	c.walk(func(c *Component) {
+		if c.Kind == Blade && c.Entry != nil {
+			out = append(out, *c.Entry)
+		}
+	})
+	return out
+}
+
+// walk calls fn for c and everything below it, parents first.
+func (c *Component) walk(fn func(*Component)) {
+	if c == nil {
+		return
+	}
+	fn(c)
+	for _, ch := range c.Children {
+		ch.walk(fn)
+	}
+}
+
+// Count returns how many components of kind are at or below c.
+func (c *Component) Count(kind string) int {
+	n := 0
+	c.walk(func(c *Component) {
+		if c.Kind == kind {
+			n++
+		}
+	})
+	return n
+}
+
+// Entries returns the inventory entries of the components of kind (Blade or
+// Node) at or below c, in tree order.
+func (c *Component) Entries(kind string) []inventory.Entry {
+	var out []inventory.Entry
+	c.walk(func(c *Component) {
+		if c.Kind == kind && c.Entry != nil {
+			out = append(out, *c.Entry)
+		}
+	})
+	return out
+}
+
+// Mismatch is a blade holding another number of nodes than expected.
+type Mismatch struct {
+	Blade    string
+	Nodes    int
+	Expected int
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("%s: %d node(s), expected %d", m.Blade, m.Nodes, m.Expected)
+}
+
+// CheckNodes returns the blades listed in bmcs[] whose number of nodes is not
+// perBlade, in tree order. Blades for which skip returns true are left out.
+func (t *Topology) CheckNodes(perBlade int, skip func(blade string) bool) []Mismatch {
+	var out []Mismatch
+	for _, cab := range t.Cabinets {
+		cab.walk(func(c *Component) {
+			if c.Kind != Blade || c.Entry == nil || (skip != nil && skip(c.Xname)) {
+				return
+			}
+			if n := len(c.Children); n != perBlade {
+				out = append(out, Mismatch{Blade: c.Xname, Nodes: n, Expected: perBlade})
+			}
+		})
+	}
+	return out
+}
+
+// Write draws the tree below the components roots (all cabinets when nil) with
+// box-drawing characters. Blades and nodes show their alias and IP; the
+// components above them how many blades and nodes they hold.
+func (t *Topology) Write(w io.Writer, roots []*Component) error {
+	if roots == nil {
+		roots = t.Cabinets
+	}
+	var err error
+	var draw func(c *Component, prefix, branch, indent string)
+	draw = func(c *Component, prefix, branch, indent string) {
+		if err != nil {
+			return
+		}
+		_, err = fmt.Fprintf(w, "%s%s%s\n", prefix, branch, describe(c))
+		for i, ch := range c.Children {
+			if i == len(c.Children)-1 {
+				draw(ch, prefix+indent, "└── ", "    ")
+			} else {
+				draw(ch, prefix+indent, "├── ", "│   ")
+			}
+		}
+	}
+	for _, c := range roots {
+		draw(c, "", "", "")
+	}
+	return err
+}
+
+func describe(c *Component) string {
+	switch c.Kind {
+	case Blade, Node:
+		parts := []string{c.Xname}
+		if c.Entry == nil {
+			return c.Xname + " (not in inventory)"
+		}
+		if c.Entry.Alias != "" {
+			parts = append(parts, c.Entry.Alias)
+		}
+		if c.Entry.IP != "" {
+			parts = append(parts, c.Entry.IP)
+		}
+		return strings.Join(parts, " ")
+	}
+	return fmt.Sprintf("%s (%s: %d blade(s), %d node(s))", c.Xname, c.Kind, c.Count(Blade), c.Count(Node))
+}
@@ -0,0 +1,109 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package topology
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"bootstrap/pkg/inventory"
+)
+
+func testDoc() inventory.FileFormat {
+	return inventory.FileFormat{
+		BMCs: []inventory.Entry{
+			{Xname: "x1000c1s0b0", IP: "10.1.0.3"},
+			{Xname: "x1000c0s0b1", IP: "10.1.0.2"},
+			{Xname: "x1000c0s0b0", IP: "10.1.0.1"},
+			{Xname: "x1001c0s2b0", IP: "10.1.0.4"},
+			{Xname: "bmc-lab", IP: "10.1.0.9"},
+		},
+		Nodes: []inventory.Entry{
+			{Xname: "x1000c0s0b0n1", IP: "10.42.0.2"},
+			{Xname: "x1000c0s0b0n0", IP: "10.42.0.1", Alias: "login01"},
+			{Xname: "x1000c0s0b1n0", IP: "10.42.0.3"},
+			{Xname: "x1000c1s0b0n0", IP: "10.42.0.4"},
+			{Xname: "x1000c1s0b0n1", IP: "10.42.0.5"},
+			{Xname: "x1002c0s0b0n0", IP: "10.42.0.6"}, // its BMC is not in bmcs[]
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	topo := Build(testDoc())
+	var b strings.Builder
+	if err := topo.Write(&b, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := `x1000 (cabinet: 3 blade(s), 5 node(s))
+├── x1000c0 (chassis: 2 blade(s), 3 node(s))
+│   └── x1000c0s0 (slot: 2 blade(s), 3 node(s))
+│       ├── x1000c0s0b0 10.1.0.1
+│       │   ├── x1000c0s0b0n0 login01 10.42.0.1
+│       │   └── x1000c0s0b0n1 10.42.0.2
+│       └── x1000c0s0b1 10.1.0.2
+│           └── x1000c0s0b1n0 10.42.0.3
+└── x1000c1 (chassis: 1 blade(s), 2 node(s))
+    └── x1000c1s0 (slot: 1 blade(s), 2 node(s))
+        └── x1000c1s0b0 10.1.0.3
+            ├── x1000c1s0b0n0 10.42.0.4
+            └── x1000c1s0b0n1 10.42.0.5
+x1001 (cabinet: 1 blade(s), 0 node(s))
+└── x1001c0 (chassis: 1 blade(s), 0 node(s))
+    └── x1001c0s2 (slot: 1 blade(s), 0 node(s))
+        └── x1001c0s2b0 10.1.0.4
+x1002 (cabinet: 1 blade(s), 1 node(s))
+└── x1002c0 (chassis: 1 blade(s), 1 node(s))
+    └── x1002c0s0 (slot: 1 blade(s), 1 node(s))
+        └── x1002c0s0b0 (not in inventory)
+            └── x1002c0s0b0n0 10.42.0.6
+`
+	if b.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+	if len(topo.Unplaced) != 1 || topo.Unplaced[0].Xname != "bmc-lab" {
+		t.Errorf("Unplaced = %+v", topo.Unplaced)
+	}
+	var nodes []string
+	for _, e := range topo.Find("x1000c1").Entries(Node) {
+		nodes = append(nodes, e.Xname)
+	}
+	if want := "x1000c1s0b0n0 x1000c1s0b0n1"; strings.Join(nodes, " ") != want {
+		t.Errorf("Entries(Node) = %v, want %s", nodes, want)
+	}
+}
+
+func TestBMCs(t *testing.T) {
+	topo := Build(testDoc())
+	ips := func(x string) []string {
+		var out []string
+		for _, e := range topo.BMCs(x) {
+			out = append(out, e.IP)
+		}
+		return out
+	}
+	for x, want := range map[string][]string{
+		"x1000":         {"10.1.0.1", "10.1.0.2", "10.1.0.3"},
+		"X1000C0":       {"10.1.0.1", "10.1.0.2"},
+		"x1000c0s0b1":   {"10.1.0.2"},
+		"x1000c1s0b0n1": {"10.1.0.3"},
+		"x1002":         nil, // no bmcs[] entry
+		"x9":            nil,
+	} {
+		if got := ips(x); !reflect.DeepEqual(got, want) {
+			t.Errorf("BMCs(%s) = %v, want %v", x, got, want)
+		}
+	}
+}
+
+func TestCheckNodes(t *testing.T) {
+	topo := Build(testDoc())
+	got := topo.CheckNodes(2, func(blade string) bool { return blade == "x1001c0s2b0" })
+	want := []Mismatch{{Blade: "x1000c0s0b1", Nodes: 1, Expected: 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CheckNodes = %+v, want %+v", got, want)
+	}
+}
@@ -8,9 +8,15 @@ package xname
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 )
 
-var trailingB = regexp.MustCompile(`b(\d+)$`)
+var (
+	trailingB = regexp.MustCompile(`b(\d+)$`)
+	trailingN = regexp.MustCompile(`n(\d+)$`)
+	chassis   = regexp.MustCompile(`^x\d+c\d+`)
+	bmc       = regexp.MustCompile(`^x\d+c\d+s\d+(?:b\d+)?$`)
+)
 
 // BMCXnameToNode converts e.g. x1000c0s0b0 -> x1000c0s0n0, x...b1 -> x...n1.
 // If it does not match, we append "-n0".
@@ -23,7 +29,51 @@ func BMCXnameToNode(bmcX string) string {
 
 // BMCXnameToNodeN converts a BMC xname to a node xname with a specific node number.
 // E.g. x9000c1s0b0 with nodeNum 0 -> x9000c1s0b0n0, with nodeNum 1 -> x9000c1s0b0n1.
-func BMCXnameToNodeN(bmcX string, nodeNum int) string {
+// It returns an error if bmcX is not a BMC (or slot) xname or nodeNum is negative.
+func BMCXnameToNodeN(bmcX string, nodeNum int) (string, error) {
+	if err := CheckBMC(bmcX); err != nil {
+		return "", err
+	}
+	if nodeNum < 0 {
+		return "", fmt.Errorf("%s: invalid node number %d", bmcX, nodeNum)
+	}
 	// Append nY where Y is the nodeNum
-	return fmt.Sprintf("%sn%d", bmcX, nodeNum)
+	return fmt.Sprintf("%sn%d", bmcX, nodeNum), nil
+}
+
+// CheckBMC returns an error if bmcX is not a BMC (or slot) xname.
+func CheckBMC(bmcX string) error {
+	if !bmc.MatchString(bmcX) {
+		return fmt.Errorf("%q is not a BMC xname (xXcCsSbB)", bmcX)
+	}
+	return nil
+}
+
+// NodeToBMCXname is the inverse of BMCXnameToNodeN: x9000c1s0b0n1 -> x9000c1s0b0.
+// It returns "" if nodeX has no node number.
+func NodeToBMCXname(nodeX string) string {
+	if !trailingN.MatchString(nodeX) {
+		return ""
+	}
+	return trailingN.ReplaceAllString(nodeX, "")
+}
+
+// NodeNumber returns the node number of a node xname: x9000c1s0b0n1 -> 1.
+// It returns -1 if nodeX has no node number.
+func NodeNumber(nodeX string) int {
+	m := trailingN.FindStringSubmatch(nodeX)
+	if m == nil {
+		return -1
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// Chassis returns the cabinet and chassis part of an xname: x1000c3s0b0 -> x1000c3.
+// It returns "" if x does not name something in a chassis.
+func Chassis(x string) string {
+	return chassis.FindString(x)
 }
@@ -38,9 +38,77 @@ func TestBMCXnameToNodeN(t *testing.T) {
 		{"x9999c1s2", 1, "x9999c1s2n1"},
 	}
 	for _, c := range cases {
-		got := BMCXnameToNodeN(c.bmcX, c.nodeNum)
-		if got != c.out {
-			t.Fatalf("BMCXnameToNodeN(%q, %d)=%q want %q", c.bmcX, c.nodeNum, got, c.out)
+		got, err := BMCXnameToNodeN(c.bmcX, c.nodeNum)
+		if err != nil || got != c.out {
+			t.Fatalf("BMCXnameToNodeN(%q, %d)=%q, %v want %q", c.bmcX, c.nodeNum, got, err, c.out)
+		}
+	}
+	for _, bad := range []string{"", "x1000c0s0b0n0", "x1000c0", "10.1.0.2", "bmc1", "x1000c0s0b0 ", "X1000c0s0b0", "x1000c0s0b"} {
+		if got, err := BMCXnameToNodeN(bad, 0); err == nil {
+			t.Errorf("BMCXnameToNodeN(%q, 0)=%q, want error", bad, got)
+		}
+	}
+	if _, err := BMCXnameToNodeN("x1000c0s0b0", -1); err == nil {
+		t.Error("BMCXnameToNodeN accepted node number -1")
+	}
+}
+
+func FuzzBMCXnameToNodeN(f *testing.F) {
+	for _, x := range []string{"x9000c1s0b0", "x1000c0s0b1", "x9999c1s2", "x1000c0", "x1000c0s0b0n1", "", "nid000001"} {
+		f.Add(x, 1)
+	}
+	f.Fuzz(func(t *testing.T, bmcX string, n int) {
+		node, err := BMCXnameToNodeN(bmcX, n)
+		if err != nil {
+			return
+		}
+		if NodeToBMCXname(node) != bmcX || NodeNumber(node) != n {
+			t.Fatalf("BMCXnameToNodeN(%q, %d)=%q does not convert back", bmcX, n, node)
+		}
+		if Chassis(node) == "" {
+			t.Fatalf("BMCXnameToNodeN(%q, %d)=%q has no chassis", bmcX, n, node)
+		}
+	})
+}
+
+func TestNodeToBMCXname(t *testing.T) {
+	cases := map[string]string{
+		"x9000c1s0b0n0":  "x9000c1s0b0",
+		"x1000c0s0b1n12": "x1000c0s0b1",
+		"x9999c1s2n1":    "x9999c1s2",
+		"x9000c1s0b0":    "",
+	}
+	for in, want := range cases {
+		if got := NodeToBMCXname(in); got != want {
+			t.Fatalf("NodeToBMCXname(%q)=%q want %q", in, got, want)
+		}
+	}
+}
+
+func TestNodeNumber(t *testing.T) {
+	cases := map[string]int{
+		"x9000c1s0b0n0":  0,
+		"x1000c0s0b1n12": 12,
+		"x9000c1s0b0":    -1,
+	}
+	for in, want := range cases {
+		if got := NodeNumber(in); got != want {
+			t.Fatalf("NodeNumber(%q)=%d want %d", in, got, want)
+		}
+	}
+}
+
+func TestChassis(t *testing.T) {
+	cases := map[string]string{
+		"x1000c3s0b0":    "x1000c3",
+		"x9000c12s4b1n0": "x9000c12",
+		"x1000c3":        "x1000c3",
+		"x1000":          "",
+		"10.0.0.5":       "",
+	}
+	for in, want := range cases {
+		if got := Chassis(in); got != want {
+			t.Fatalf("Chassis(%q)=%q want %q", in, got, want)
 		}
 	}
 }
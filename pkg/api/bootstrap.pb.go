@@ -0,0 +1,962 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        (unknown)
+// source: bootstrap.proto
+
+package api
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type OutputLine_Stream int32
+
+const (
+	OutputLine_STREAM_UNSPECIFIED OutputLine_Stream = 0
+	OutputLine_STREAM_STDOUT      OutputLine_Stream = 1
+	OutputLine_STREAM_STDERR      OutputLine_Stream = 2
+)
+
+// Enum value maps for OutputLine_Stream.
+var (
+	OutputLine_Stream_name = map[int32]string{
+		0: "STREAM_UNSPECIFIED",
+		1: "STREAM_STDOUT",
+		2: "STREAM_STDERR",
+	}
+	OutputLine_Stream_value = map[string]int32{
+		"STREAM_UNSPECIFIED": 0,
+		"STREAM_STDOUT":      1,
+		"STREAM_STDERR":      2,
+	}
+)
+
+func (x OutputLine_Stream) Enum() *OutputLine_Stream {
+	p := new(OutputLine_Stream)
+	*p = x
+	return p
+}
+
+func (x OutputLine_Stream) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OutputLine_Stream) Descriptor() protoreflect.EnumDescriptor {
+	return file_bootstrap_proto_enumTypes[0].Descriptor()
+}
+
+func (OutputLine_Stream) Type() protoreflect.EnumType {
+	return &file_bootstrap_proto_enumTypes[0]
+}
+
+func (x OutputLine_Stream) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OutputLine_Stream.Descriptor instead.
+func (OutputLine_Stream) EnumDescriptor() ([]byte, []int) {
+	return file_bootstrap_proto_rawDescGZIP(), []int{8, 0}
+}
+
+type ListInventoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListInventoryRequest) Reset() {
+	*x = ListInventoryRequest{}
+	mi := &file_bootstrap_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListInventoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInventoryRequest) ProtoMessage() {}
+
+func (x *ListInventoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bootstrap_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInventoryRequest.ProtoReflect.Descriptor instead.
+func (*ListInventoryRequest) Descriptor() ([]byte, []int) {
+	return file_bootstrap_proto_rawDescGZIP(), []int{0}
+}
+
+type NIC struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Mac           string                 `protobuf:"bytes,2,opt,name=mac,proto3" json:"mac,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NIC) Reset() {
+	*x = NIC{}
+	mi := &file_bootstrap_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NIC) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NIC) ProtoMessage() {}
+
+func (x *NIC) ProtoReflect() protoreflect.Message {
+	mi := &file_bootstrap_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NIC.ProtoReflect.Descriptor instead.
+func (*NIC) Descriptor() ([]byte, []int) {
+	return file_bootstrap_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *NIC) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *NIC) GetMac() string {
+	if x != nil {
+		return x.Mac
+	}
+	return ""
+}
+
+func (x *NIC) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type Entry struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Xname            string                 `protobuf:"bytes,1,opt,name=xname,proto3" json:"xname,omitempty"`
+	Mac              string                 `protobuf:"bytes,2,opt,name=mac,proto3" json:"mac,omitempty"`
+	Ip               string                 `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`
+	Model            string                 `protobuf:"bytes,4,opt,name=model,proto3" json:"model,omitempty"`
+	SerialNumber     string                 `protobuf:"bytes,5,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	Sku              string                 `protobuf:"bytes,6,opt,name=sku,proto3" json:"sku,omitempty"`
+	BiosVersion      string                 `protobuf:"bytes,7,opt,name=bios_version,json=biosVersion,proto3" json:"bios_version,omitempty"`
+	ProcessorSummary string                 `protobuf:"bytes,8,opt,name=processor_summary,json=processorSummary,proto3" json:"processor_summary,omitempty"`
+	MemorySummary    string                 `protobuf:"bytes,9,opt,name=memory_summary,json=memorySummary,proto3" json:"memory_summary,omitempty"`
+	Hsn              []*NIC                 `protobuf:"bytes,10,rep,name=hsn,proto3" json:"hsn,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Entry) Reset() {
+	*x = Entry{}
+	mi := &file_bootstrap_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Entry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Entry) ProtoMessage() {}
+
+func (x *Entry) ProtoReflect() protoreflect.Message {
+	mi := &file_bootstrap_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Entry.ProtoReflect.Descriptor instead.
+func (*Entry) Descriptor() ([]byte, []int) {
+	return file_bootstrap_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Entry) GetXname() string {
+	if x != nil {
+		return x.Xname
+	}
+	return ""
+}
+
+func (x *Entry) GetMac() string {
+	if x != nil {
+		return x.Mac
+	}
+	return ""
+}
+
+func (x *Entry) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+func (x *Entry) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Entry) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *Entry) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *Entry) GetBiosVersion() string {
+	if x != nil {
+		return x.BiosVersion
+	}
+	return ""
+}
+
+func (x *Entry) GetProcessorSummary() string {
+	if x != nil {
+		return x.ProcessorSummary
+	}
+	return ""
+}
+
+func (x *Entry) GetMemorySummary() string {
+	if x != nil {
+		return x.MemorySummary
+	}
+	return ""
+}
+
+func (x *Entry) GetHsn() []*NIC {
+	if x != nil {
+		return x.Hsn
+	}
+	return nil
+}
+
+type Inventory struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bmcs          []*Entry               `protobuf:"bytes,1,rep,name=bmcs,proto3" json:"bmcs,omitempty"`
+	Nodes         []*Entry               `protobuf:"bytes,2,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Inventory) Reset() {
+	*x = Inventory{}
+	mi := &file_bootstrap_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Inventory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Inventory) ProtoMessage() {}
+
+func (x *Inventory) ProtoReflect() protoreflect.Message {
+	mi := &file_bootstrap_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Inventory.ProtoReflect.Descriptor instead.
+func (*Inventory) Descriptor() ([]byte, []int) {
+	return file_bootstrap_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Inventory) GetBmcs() []*Entry {
+	if x != nil {
+		return x.Bmcs
+	}
+	return nil
+}
+
+func (x *Inventory) GetNodes() []*Entry {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+type DiscoverRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// BMC hosts or bmcs[] xnames to discover; empty means all of bmcs[].
+	Hosts         []string `protobuf:"bytes,1,rep,name=hosts,proto3" json:"hosts,omitempty"`
+	BmcSubnet     string   `protobuf:"bytes,2,opt,name=bmc_subnet,json=bmcSubnet,proto3" json:"bmc_subnet,omitempty"`
+	NodeSubnet    string   `protobuf:"bytes,3,opt,name=node_subnet,json=nodeSubnet,proto3" json:"node_subnet,omitempty"`
+	DryRun        bool     `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoverRequest) Reset() {
+	*x = DiscoverRequest{}
+	mi := &file_bootstrap_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoverRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoverRequest) ProtoMessage() {}
+
+func (x *DiscoverRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bootstrap_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoverRequest.ProtoReflect.Descriptor instead.
+func (*DiscoverRequest) Descriptor() ([]byte, []int) {
+	return file_bootstrap_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DiscoverRequest) GetHosts() []string {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
+func (x *DiscoverRequest) GetBmcSubnet() string {
+	if x != nil {
+		return x.BmcSubnet
+	}
+	return ""
+}
+
+func (x *DiscoverRequest) GetNodeSubnet() string {
+	if x != nil {
+		return x.NodeSubnet
+	}
+	return ""
+}
+
+func (x *DiscoverRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type UpdateFirmwareRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// BMC hosts to update; empty means all of bmcs[].
+	Hosts []string `protobuf:"bytes,1,rep,name=hosts,proto3" json:"hosts,omitempty"`
+	// Firmware type preset: cc, nc or bios (ignored when targets is set).
+	Type            string   `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Targets         []string `protobuf:"bytes,3,rep,name=targets,proto3" json:"targets,omitempty"`
+	ImageUri        string   `protobuf:"bytes,4,opt,name=image_uri,json=imageUri,proto3" json:"image_uri,omitempty"`
+	Protocol        string   `protobuf:"bytes,5,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	ExpectedVersion string   `protobuf:"bytes,6,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	Force           bool     `protobuf:"varint,7,opt,name=force,proto3" json:"force,omitempty"`
+	BatchSize       int32    `protobuf:"varint,8,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	DryRun          bool     `protobuf:"varint,9,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateFirmwareRequest) Reset() {
+	*x = UpdateFirmwareRequest{}
+	mi := &file_bootstrap_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateFirmwareRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFirmwareRequest) ProtoMessage() {}
+
+func (x *UpdateFirmwareRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bootstrap_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFirmwareRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFirmwareRequest) Descriptor() ([]byte, []int) {
+	return file_bootstrap_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdateFirmwareRequest) GetHosts() []string {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
+func (x *UpdateFirmwareRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *UpdateFirmwareRequest) GetTargets() []string {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *UpdateFirmwareRequest) GetImageUri() string {
+	if x != nil {
+		return x.ImageUri
+	}
+	return ""
+}
+
+func (x *UpdateFirmwareRequest) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *UpdateFirmwareRequest) GetExpectedVersion() string {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return ""
+}
+
+func (x *UpdateFirmwareRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+func (x *UpdateFirmwareRequest) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *UpdateFirmwareRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+// Progress is one message of a streamed run.
+type Progress struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*Progress_Host
+	//	*Progress_Output
+	//	*Progress_Finished
+	Event         isProgress_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Progress) Reset() {
+	*x = Progress{}
+	mi := &file_bootstrap_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Progress) ProtoMessage() {}
+
+func (x *Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_bootstrap_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Progress.ProtoReflect.Descriptor instead.
+func (*Progress) Descriptor() ([]byte, []int) {
+	return file_bootstrap_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Progress) GetEvent() isProgress_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *Progress) GetHost() *HostProgress {
+	if x != nil {
+		if x, ok := x.Event.(*Progress_Host); ok {
+			return x.Host
+		}
+	}
+	return nil
+}
+
+func (x *Progress) GetOutput() *OutputLine {
+	if x != nil {
+		if x, ok := x.Event.(*Progress_Output); ok {
+			return x.Output
+		}
+	}
+	return nil
+}
+
+func (x *Progress) GetFinished() *Finished {
+	if x != nil {
+		if x, ok := x.Event.(*Progress_Finished); ok {
+			return x.Finished
+		}
+	}
+	return nil
+}
+
+type isProgress_Event interface {
+	isProgress_Event()
+}
+
+type Progress_Host struct {
+	Host *HostProgress `protobuf:"bytes,1,opt,name=host,proto3,oneof"`
+}
+
+type Progress_Output struct {
+	Output *OutputLine `protobuf:"bytes,2,opt,name=output,proto3,oneof"`
+}
+
+type Progress_Finished struct {
+	Finished *Finished `protobuf:"bytes,3,opt,name=finished,proto3,oneof"`
+}
+
+func (*Progress_Host) isProgress_Event() {}
+
+func (*Progress_Output) isProgress_Event() {}
+
+func (*Progress_Finished) isProgress_Event() {}
+
+// HostProgress reports that work on a host started or finished.
+type HostProgress struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Host  string                 `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	// "started", then the command's outcome for the host: discovered, failed or
+	// aborted for discovery; planned, updated, skipped, failed or aborted for
+	// firmware.
+	State   string `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// Hosts finished so far, out of total.
+	Completed     int32 `protobuf:"varint,4,opt,name=completed,proto3" json:"completed,omitempty"`
+	Total         int32 `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostProgress) Reset() {
+	*x = HostProgress{}
+	mi := &file_bootstrap_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostProgress) ProtoMessage() {}
+
+func (x *HostProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_bootstrap_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostProgress.ProtoReflect.Descriptor instead.
+func (*HostProgress) Descriptor() ([]byte, []int) {
+	return file_bootstrap_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *HostProgress) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *HostProgress) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *HostProgress) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *HostProgress) GetCompleted() int32 {
+	if x != nil {
+		return x.Completed
+	}
+	return 0
+}
+
+func (x *HostProgress) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// OutputLine is a line the command printed.
+type OutputLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stream        OutputLine_Stream      `protobuf:"varint,1,opt,name=stream,proto3,enum=bootstrap.v1.OutputLine_Stream" json:"stream,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OutputLine) Reset() {
+	*x = OutputLine{}
+	mi := &file_bootstrap_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OutputLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutputLine) ProtoMessage() {}
+
+func (x *OutputLine) ProtoReflect() protoreflect.Message {
+	mi := &file_bootstrap_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutputLine.ProtoReflect.Descriptor instead.
+func (*OutputLine) Descriptor() ([]byte, []int) {
+	return file_bootstrap_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *OutputLine) GetStream() OutputLine_Stream {
+	if x != nil {
+		return x.Stream
+	}
+	return OutputLine_STREAM_UNSPECIFIED
+}
+
+func (x *OutputLine) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// Finished is the last message of a run.
+type Finished struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// succeeded, failed or canceled
+	State         string `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	ExitCode      int32  `protobuf:"varint,2,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`
+	Error         string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Finished) Reset() {
+	*x = Finished{}
+	mi := &file_bootstrap_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Finished) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Finished) ProtoMessage() {}
+
+func (x *Finished) ProtoReflect() protoreflect.Message {
+	mi := &file_bootstrap_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Finished.ProtoReflect.Descriptor instead.
+func (*Finished) Descriptor() ([]byte, []int) {
+	return file_bootstrap_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Finished) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Finished) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *Finished) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_bootstrap_proto protoreflect.FileDescriptor
+
+const file_bootstrap_proto_rawDesc = "" +
+	"\n" +
+	"\x0fbootstrap.proto\x12\fbootstrap.v1\"\x16\n" +
+	"\x14ListInventoryRequest\"I\n" +
+	"\x03NIC\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
+	"\x03mac\x18\x02 \x01(\tR\x03mac\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\"\xa8\x02\n" +
+	"\x05Entry\x12\x14\n" +
+	"\x05xname\x18\x01 \x01(\tR\x05xname\x12\x10\n" +
+	"\x03mac\x18\x02 \x01(\tR\x03mac\x12\x0e\n" +
+	"\x02ip\x18\x03 \x01(\tR\x02ip\x12\x14\n" +
+	"\x05model\x18\x04 \x01(\tR\x05model\x12#\n" +
+	"\rserial_number\x18\x05 \x01(\tR\fserialNumber\x12\x10\n" +
+	"\x03sku\x18\x06 \x01(\tR\x03sku\x12!\n" +
+	"\fbios_version\x18\a \x01(\tR\vbiosVersion\x12+\n" +
+	"\x11processor_summary\x18\b \x01(\tR\x10processorSummary\x12%\n" +
+	"\x0ememory_summary\x18\t \x01(\tR\rmemorySummary\x12#\n" +
+	"\x03hsn\x18\n" +
+	" \x03(\v2\x11.bootstrap.v1.NICR\x03hsn\"_\n" +
+	"\tInventory\x12'\n" +
+	"\x04bmcs\x18\x01 \x03(\v2\x13.bootstrap.v1.EntryR\x04bmcs\x12)\n" +
+	"\x05nodes\x18\x02 \x03(\v2\x13.bootstrap.v1.EntryR\x05nodes\"\x80\x01\n" +
+	"\x0fDiscoverRequest\x12\x14\n" +
+	"\x05hosts\x18\x01 \x03(\tR\x05hosts\x12\x1d\n" +
+	"\n" +
+	"bmc_subnet\x18\x02 \x01(\tR\tbmcSubnet\x12\x1f\n" +
+	"\vnode_subnet\x18\x03 \x01(\tR\n" +
+	"nodeSubnet\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\"\x8d\x02\n" +
+	"\x15UpdateFirmwareRequest\x12\x14\n" +
+	"\x05hosts\x18\x01 \x03(\tR\x05hosts\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x18\n" +
+	"\atargets\x18\x03 \x03(\tR\atargets\x12\x1b\n" +
+	"\timage_uri\x18\x04 \x01(\tR\bimageUri\x12\x1a\n" +
+	"\bprotocol\x18\x05 \x01(\tR\bprotocol\x12)\n" +
+	"\x10expected_version\x18\x06 \x01(\tR\x0fexpectedVersion\x12\x14\n" +
+	"\x05force\x18\a \x01(\bR\x05force\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\b \x01(\x05R\tbatchSize\x12\x17\n" +
+	"\adry_run\x18\t \x01(\bR\x06dryRun\"\xaf\x01\n" +
+	"\bProgress\x120\n" +
+	"\x04host\x18\x01 \x01(\v2\x1a.bootstrap.v1.HostProgressH\x00R\x04host\x122\n" +
+	"\x06output\x18\x02 \x01(\v2\x18.bootstrap.v1.OutputLineH\x00R\x06output\x124\n" +
+	"\bfinished\x18\x03 \x01(\v2\x16.bootstrap.v1.FinishedH\x00R\bfinishedB\a\n" +
+	"\x05event\"\x86\x01\n" +
+	"\fHostProgress\x12\x12\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\x12\x1c\n" +
+	"\tcompleted\x18\x04 \x01(\x05R\tcompleted\x12\x14\n" +
+	"\x05total\x18\x05 \x01(\x05R\x05total\"\xa1\x01\n" +
+	"\n" +
+	"OutputLine\x127\n" +
+	"\x06stream\x18\x01 \x01(\x0e2\x1f.bootstrap.v1.OutputLine.StreamR\x06stream\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\"F\n" +
+	"\x06Stream\x12\x16\n" +
+	"\x12STREAM_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rSTREAM_STDOUT\x10\x01\x12\x11\n" +
+	"\rSTREAM_STDERR\x10\x02\"S\n" +
+	"\bFinished\x12\x14\n" +
+	"\x05state\x18\x01 \x01(\tR\x05state\x12\x1b\n" +
+	"\texit_code\x18\x02 \x01(\x05R\bexitCode\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error2\xef\x01\n" +
+	"\tBootstrap\x12L\n" +
+	"\rListInventory\x12\".bootstrap.v1.ListInventoryRequest\x1a\x17.bootstrap.v1.Inventory\x12C\n" +
+	"\bDiscover\x12\x1d.bootstrap.v1.DiscoverRequest\x1a\x16.bootstrap.v1.Progress0\x01\x12O\n" +
+	"\x0eUpdateFirmware\x12#.bootstrap.v1.UpdateFirmwareRequest\x1a\x16.bootstrap.v1.Progress0\x01B\x13Z\x11bootstrap/pkg/apib\x06proto3"
+
+var (
+	file_bootstrap_proto_rawDescOnce sync.Once
+	file_bootstrap_proto_rawDescData []byte
+)
+
+func file_bootstrap_proto_rawDescGZIP() []byte {
+	file_bootstrap_proto_rawDescOnce.Do(func() {
+		file_bootstrap_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_bootstrap_proto_rawDesc), len(file_bootstrap_proto_rawDesc)))
+	})
+	return file_bootstrap_proto_rawDescData
+}
+
+var file_bootstrap_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_bootstrap_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_bootstrap_proto_goTypes = []any{
+	(OutputLine_Stream)(0),        // 0: bootstrap.v1.OutputLine.Stream
+	(*ListInventoryRequest)(nil),  // 1: bootstrap.v1.ListInventoryRequest
+	(*NIC)(nil),                   // 2: bootstrap.v1.NIC
+	(*Entry)(nil),                 // 3: bootstrap.v1.Entry
+	(*Inventory)(nil),             // 4: bootstrap.v1.Inventory
+	(*DiscoverRequest)(nil),       // 5: bootstrap.v1.DiscoverRequest
+	(*UpdateFirmwareRequest)(nil), // 6: bootstrap.v1.UpdateFirmwareRequest
+	(*Progress)(nil),              // 7: bootstrap.v1.Progress
+	(*HostProgress)(nil),          // 8: bootstrap.v1.HostProgress
+	(*OutputLine)(nil),            // 9: bootstrap.v1.OutputLine
+	(*Finished)(nil),              // 10: bootstrap.v1.Finished
+}
+var file_bootstrap_proto_depIdxs = []int32{
+	2,  // 0: bootstrap.v1.Entry.hsn:type_name -> bootstrap.v1.NIC
+	3,  // 1: bootstrap.v1.Inventory.bmcs:type_name -> bootstrap.v1.Entry
+	3,  // 2: bootstrap.v1.Inventory.nodes:type_name -> bootstrap.v1.Entry
+	8,  // 3: bootstrap.v1.Progress.host:type_name -> bootstrap.v1.HostProgress
+	9,  // 4: bootstrap.v1.Progress.output:type_name -> bootstrap.v1.OutputLine
+	10, // 5: bootstrap.v1.Progress.finished:type_name -> bootstrap.v1.Finished
+	0,  // 6: bootstrap.v1.OutputLine.stream:type_name -> bootstrap.v1.OutputLine.Stream
+	1,  // 7: bootstrap.v1.Bootstrap.ListInventory:input_type -> bootstrap.v1.ListInventoryRequest
+	5,  // 8: bootstrap.v1.Bootstrap.Discover:input_type -> bootstrap.v1.DiscoverRequest
+	6,  // 9: bootstrap.v1.Bootstrap.UpdateFirmware:input_type -> bootstrap.v1.UpdateFirmwareRequest
+	4,  // 10: bootstrap.v1.Bootstrap.ListInventory:output_type -> bootstrap.v1.Inventory
+	7,  // 11: bootstrap.v1.Bootstrap.Discover:output_type -> bootstrap.v1.Progress
+	7,  // 12: bootstrap.v1.Bootstrap.UpdateFirmware:output_type -> bootstrap.v1.Progress
+	10, // [10:13] is the sub-list for method output_type
+	7,  // [7:10] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_bootstrap_proto_init() }
+func file_bootstrap_proto_init() {
+	if File_bootstrap_proto != nil {
+		return
+	}
+	file_bootstrap_proto_msgTypes[6].OneofWrappers = []any{
+		(*Progress_Host)(nil),
+		(*Progress_Output)(nil),
+		(*Progress_Finished)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_bootstrap_proto_rawDesc), len(file_bootstrap_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_bootstrap_proto_goTypes,
+		DependencyIndexes: file_bootstrap_proto_depIdxs,
+		EnumInfos:         file_bootstrap_proto_enumTypes,
+		MessageInfos:      file_bootstrap_proto_msgTypes,
+	}.Build()
+	File_bootstrap_proto = out.File
+	file_bootstrap_proto_goTypes = nil
+	file_bootstrap_proto_depIdxs = nil
+}
@@ -0,0 +1,224 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: bootstrap.proto
+
+package api
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Bootstrap_ListInventory_FullMethodName  = "/bootstrap.v1.Bootstrap/ListInventory"
+	Bootstrap_Discover_FullMethodName       = "/bootstrap.v1.Bootstrap/Discover"
+	Bootstrap_UpdateFirmware_FullMethodName = "/bootstrap.v1.Bootstrap/UpdateFirmware"
+)
+
+// BootstrapClient is the client API for Bootstrap service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Bootstrap exposes the inventory and the long-running discover and firmware
+// operations. Every call needs "authorization: Bearer <token>" metadata.
+type BootstrapClient interface {
+	// ListInventory returns the served inventory.
+	ListInventory(ctx context.Context, in *ListInventoryRequest, opts ...grpc.CallOption) (*Inventory, error)
+	// Discover runs NIC discovery and streams its progress until it finishes.
+	// Cancelling the call interrupts the run.
+	Discover(ctx context.Context, in *DiscoverRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Progress], error)
+	// UpdateFirmware runs a firmware rollout and streams its progress until it
+	// finishes. Cancelling the call interrupts the run.
+	UpdateFirmware(ctx context.Context, in *UpdateFirmwareRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Progress], error)
+}
+
+type bootstrapClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBootstrapClient(cc grpc.ClientConnInterface) BootstrapClient {
+	return &bootstrapClient{cc}
+}
+
+func (c *bootstrapClient) ListInventory(ctx context.Context, in *ListInventoryRequest, opts ...grpc.CallOption) (*Inventory, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Inventory)
+	err := c.cc.Invoke(ctx, Bootstrap_ListInventory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bootstrapClient) Discover(ctx context.Context, in *DiscoverRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Progress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Bootstrap_ServiceDesc.Streams[0], Bootstrap_Discover_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DiscoverRequest, Progress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Bootstrap_DiscoverClient = grpc.ServerStreamingClient[Progress]
+
+func (c *bootstrapClient) UpdateFirmware(ctx context.Context, in *UpdateFirmwareRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Progress], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Bootstrap_ServiceDesc.Streams[1], Bootstrap_UpdateFirmware_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UpdateFirmwareRequest, Progress]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Bootstrap_UpdateFirmwareClient = grpc.ServerStreamingClient[Progress]
+
+// BootstrapServer is the server API for Bootstrap service.
+// All implementations must embed UnimplementedBootstrapServer
+// for forward compatibility.
+//
+// Bootstrap exposes the inventory and the long-running discover and firmware
+// operations. Every call needs "authorization: Bearer <token>" metadata.
+type BootstrapServer interface {
+	// ListInventory returns the served inventory.
+	ListInventory(context.Context, *ListInventoryRequest) (*Inventory, error)
+	// Discover runs NIC discovery and streams its progress until it finishes.
+	// Cancelling the call interrupts the run.
+	Discover(*DiscoverRequest, grpc.ServerStreamingServer[Progress]) error
+	// UpdateFirmware runs a firmware rollout and streams its progress until it
+	// finishes. Cancelling the call interrupts the run.
+	UpdateFirmware(*UpdateFirmwareRequest, grpc.ServerStreamingServer[Progress]) error
+	mustEmbedUnimplementedBootstrapServer()
+}
+
+// UnimplementedBootstrapServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBootstrapServer struct{}
+
+func (UnimplementedBootstrapServer) ListInventory(context.Context, *ListInventoryRequest) (*Inventory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInventory not implemented")
+}
+func (UnimplementedBootstrapServer) Discover(*DiscoverRequest, grpc.ServerStreamingServer[Progress]) error {
+	return status.Errorf(codes.Unimplemented, "method Discover not implemented")
+}
+func (UnimplementedBootstrapServer) UpdateFirmware(*UpdateFirmwareRequest, grpc.ServerStreamingServer[Progress]) error {
+	return status.Errorf(codes.Unimplemented, "method UpdateFirmware not implemented")
+}
+func (UnimplementedBootstrapServer) mustEmbedUnimplementedBootstrapServer() {}
+func (UnimplementedBootstrapServer) testEmbeddedByValue()                   {}
+
+// UnsafeBootstrapServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BootstrapServer will
+// result in compilation errors.
+type UnsafeBootstrapServer interface {
+	mustEmbedUnimplementedBootstrapServer()
+}
+
+func RegisterBootstrapServer(s grpc.ServiceRegistrar, srv BootstrapServer) {
+	// If the following call pancis, it indicates UnimplementedBootstrapServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Bootstrap_ServiceDesc, srv)
+}
+
+func _Bootstrap_ListInventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInventoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BootstrapServer).ListInventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Bootstrap_ListInventory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BootstrapServer).ListInventory(ctx, req.(*ListInventoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bootstrap_Discover_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DiscoverRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BootstrapServer).Discover(m, &grpc.GenericServerStream[DiscoverRequest, Progress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Bootstrap_DiscoverServer = grpc.ServerStreamingServer[Progress]
+
+func _Bootstrap_UpdateFirmware_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(UpdateFirmwareRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BootstrapServer).UpdateFirmware(m, &grpc.GenericServerStream[UpdateFirmwareRequest, Progress]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Bootstrap_UpdateFirmwareServer = grpc.ServerStreamingServer[Progress]
+
+// Bootstrap_ServiceDesc is the grpc.ServiceDesc for Bootstrap service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Bootstrap_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "bootstrap.v1.Bootstrap",
+	HandlerType: (*BootstrapServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListInventory",
+			Handler:    _Bootstrap_ListInventory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Discover",
+			Handler:       _Bootstrap_Discover_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UpdateFirmware",
+			Handler:       _Bootstrap_UpdateFirmware_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "bootstrap.proto",
+}
@@ -0,0 +1,16 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package api holds the gRPC service served by `serve --grpc-listen` and its
+// generated Go client. Dial the server and call it with the API token:
+//
+//	conn, err := grpc.NewClient("bootstrap:9090", grpc.WithTransportCredentials(creds))
+//	c := api.NewBootstrapClient(conn)
+//	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+//	stream, err := c.UpdateFirmware(ctx, &api.UpdateFirmwareRequest{Type: "cc", ImageUri: uri})
+//
+// Regenerate the code after editing bootstrap.proto with `make proto`.
+package api
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative bootstrap.proto
@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package boltstore keeps an inventory in a bbolt database file, for systems
+// where a single YAML file becomes unwieldy. Entries are stored in file order and
+// indexed by xname, MAC and IP.
+package boltstore
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"bootstrap/pkg/inventory"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Buckets: one per section (bmcs, nodes), keyed by position, and an index
+// mapping lookup keys (see inventory.Entry.Keys) to "section/position" references.
+var indexBucket = []byte("index")
+
+// Store is an inventory.Store backed by a bbolt database.
+type Store struct {
+	db *bolt.DB
+}
+
+var _ inventory.Store = (*Store)(nil)
+
+// Open opens the database at path, creating it unless readOnly is set. Writers
+// take an exclusive file lock and readers a shared one; Open waits up to
+// timeout for the lock (0 waits indefinitely).
+func Open(path string, readOnly bool, timeout time.Duration) (*Store, error) {
+	if readOnly {
+		if _, err := os.Stat(path); err != nil {
+			return nil, err
+		}
+	}
+	db, err := bolt.Open(path, 0o644, &bolt.Options{ReadOnly: readOnly, Timeout: timeout})
+	if err != nil {
+		if errors.Is(err, bolt.ErrTimeout) {
+			return nil, fmt.Errorf("%s: in use by another process", path)
+		}
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Load returns the whole inventory.
+func (s *Store) Load() (inventory.FileFormat, error) {
+	var doc inventory.FileFormat
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		if doc.BMCs, err = readSection(tx, "bmcs"); err != nil {
+			return err
+		}
+		doc.Nodes, err = readSection(tx, "nodes")
+		return err
+	})
+	return doc, err
+}
+
+func readSection(tx *bolt.Tx, name string) ([]inventory.Entry, error) {
+	b := tx.Bucket([]byte(name))
+	if b == nil {
+		return nil, nil
+	}
+	var out []inventory.Entry
+	err := b.ForEach(func(_, v []byte) error {
+		var e inventory.Entry
+		if err := json.Unmarshal(v, &e); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		out = append(out, e)
+		return nil
+	})
+	return out, err
+}
+
+// Save replaces the inventory in a single transaction.
+func (s *Store) Save(doc inventory.FileFormat) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		index := map[string][]string{}
+		for _, name := range [][]byte{[]byte("bmcs"), []byte("nodes"), indexBucket} {
+			if err := tx.DeleteBucket(name); err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+				return err
+			}
+		}
+		for _, sec := range []struct {
+			name    string
+			entries []inventory.Entry
+		}{{"bmcs", doc.BMCs}, {"nodes", doc.Nodes}} {
+			b, err := tx.CreateBucket([]byte(sec.name))
+			if err != nil {
+				return err
+			}
+			for i, e := range sec.entries {
+				v, err := json.Marshal(e)
+				if err != nil {
+					return err
+				}
+				if err := b.Put(position(i), v); err != nil {
+					return err
+				}
+				ref := fmt.Sprintf("%s/%d", sec.name, i)
+				for _, k := range e.Keys() {
+					index[k] = append(index[k], ref)
+				}
+			}
+		}
+		ib, err := tx.CreateBucket(indexBucket)
+		if err != nil {
+			return err
+		}
+		for k, refs := range index {
+			v, err := json.Marshal(refs)
+			if err != nil {
+				return err
+			}
+			if err := ib.Put([]byte(k), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Find returns the entries whose xname, MAC, IP or HSN MAC equals key, using
+// the index.
+func (s *Store) Find(key string) ([]inventory.Match, error) {
+	var out []inventory.Match
+	err := s.db.View(func(tx *bolt.Tx) error {
+		ib := tx.Bucket(indexBucket)
+		if ib == nil {
+			return nil
+		}
+		seen := map[string]bool{}
+		for _, k := range inventory.LookupKeys(key) {
+			v := ib.Get([]byte(k))
+			if v == nil {
+				continue
+			}
+			var refs []string
+			if err := json.Unmarshal(v, &refs); err != nil {
+				return err
+			}
+			for _, ref := range refs {
+				if seen[ref] {
+					continue
+				}
+				seen[ref] = true
+				sec, pos, _ := strings.Cut(ref, "/")
+				i, err := strconv.Atoi(pos)
+				b := tx.Bucket([]byte(sec))
+				if err != nil || b == nil {
+					return fmt.Errorf("index %s: bad reference %q", k, ref)
+				}
+				raw := b.Get(position(i))
+				if raw == nil {
+					return fmt.Errorf("index %s: %s not found", k, ref)
+				}
+				var e inventory.Entry
+				if err := json.Unmarshal(raw, &e); err != nil {
+					return err
+				}
+				out = append(out, inventory.Match{Section: sec, Entry: e})
+			}
+		}
+		return nil
+	})
+	return out, err
+}
+
+// position encodes an entry's position so keys sort in file order.
+func position(i int) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(i))
+	return k
+}
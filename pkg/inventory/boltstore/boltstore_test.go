@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package boltstore
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"bootstrap/pkg/inventory"
+)
+
+func TestSaveLoadFind(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.db")
+	s, err := Open(path, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := inventory.FileFormat{
+		BMCs: []inventory.Entry{{Xname: "x1000c0s0b0", MAC: "02:00:00:00:00:01", IP: "10.1.0.2"}},
+		Nodes: []inventory.Entry{
+			{Xname: "x1000c0s0b0n1", MAC: "aa:00:00:00:00:02", IP: "10.2.0.2"},
+			{Xname: "x1000c0s0b0n0", MAC: "AA:00:00:00:00:01", IP: "10.2.0.1", HSN: []inventory.NIC{{ID: "hsn0", MAC: "bb:00:00:00:00:01"}}},
+		},
+	}
+	if err := s.Save(doc); err != nil {
+		t.Fatal(err)
+	}
+	// A second save replaces the first
+	if err := s.Save(doc); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = Open(path, true, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	got, err := s.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, doc) {
+		t.Errorf("Load = %+v, want %+v", got, doc)
+	}
+	for key, want := range map[string]string{
+		"x1000c0s0b0":       "bmcs x1000c0s0b0",
+		"aa:00:00:00:00:01": "nodes x1000c0s0b0n0",
+		"10.2.0.2":          "nodes x1000c0s0b0n1",
+		"BB:00:00:00:00:01": "nodes x1000c0s0b0n0",
+	} {
+		found, err := s.Find(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(found) != 1 || found[0].Section+" "+found[0].Entry.Xname != want {
+			t.Errorf("Find(%s) = %+v, want %s", key, found, want)
+		}
+	}
+	if found, _ := s.Find("10.9.9.9"); len(found) != 0 {
+		t.Errorf("Find(unknown) = %+v", found)
+	}
+}
+
+func TestOpenReadOnlyMissing(t *testing.T) {
+	if _, err := Open(filepath.Join(t.TempDir(), "missing.db"), true, 0); err == nil {
+		t.Error("expected error opening a missing database read-only")
+	}
+}
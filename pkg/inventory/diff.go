@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"sort"
+	"strings"
+)
+
+// Change kinds reported by Diff
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// FieldChange is one field of an entry that differs between two files.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// Change is one entry of bmcs[] or nodes[] that was added, removed or changed.
+// An entry whose xname changed but whose MAC did not is reported as changed,
+// with the xname among its fields.
+type Change struct {
+	Section string        `json:"section"` // bmcs or nodes
+	Kind    string        `json:"kind"`
+	Xname   string        `json:"xname"` // the new xname for added and changed entries
+	Fields  []FieldChange `json:"fields,omitempty"`
+}
+
+// Diff compares two inventory files entry by entry. Entries are matched by
+// xname, then the remaining ones by MAC, so reordering nodes[] is not a change.
+// Changes are sorted by section, then xname.
+func Diff(old, new FileFormat) []Change {
+	out := diffSection("bmcs", old.BMCs, new.BMCs)
+	return append(out, diffSection("nodes", old.Nodes, new.Nodes)...)
+}
+
+func diffSection(section string, old, new []Entry) []Change {
+	var out []Change
+	matched := make([]bool, len(new))
+	byXname := map[string]int{}
+	byMAC := map[string]int{}
+	for i, e := range new {
+		if _, dup := byXname[e.Xname]; !dup && e.Xname != "" {
+			byXname[e.Xname] = i
+		}
+		if m := strings.ToLower(e.MAC); m != "" {
+			if _, dup := byMAC[m]; !dup {
+				byMAC[m] = i
+			}
+		}
+	}
+	var unmatched []Entry
+	pair := func(o Entry, i int) {
+		matched[i] = true
+		if f := entryFields(o, new[i]); len(f) > 0 {
+			out = append(out, Change{Section: section, Kind: Changed, Xname: new[i].Xname, Fields: f})
+		}
+	}
+	for _, o := range old {
+		if i, ok := byXname[o.Xname]; ok && o.Xname != "" && !matched[i] {
+			pair(o, i)
+			continue
+		}
+		unmatched = append(unmatched, o)
+	}
+	for _, o := range unmatched {
+		if i, ok := byMAC[strings.ToLower(o.MAC)]; ok && o.MAC != "" && !matched[i] {
+			pair(o, i)
+			continue
+		}
+		out = append(out, Change{Section: section, Kind: Removed, Xname: o.Xname, Fields: entryFields(o, Entry{})})
+	}
+	for i, e := range new {
+		if !matched[i] {
+			out = append(out, Change{Section: section, Kind: Added, Xname: e.Xname, Fields: entryFields(Entry{}, e)})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Xname < out[j].Xname })
+	return out
+}
+
+// entryFields lists the fields that differ between two entries. MACs compare
+// case-insensitively. The xname is only compared when both entries have one.
+func entryFields(a, b Entry) []FieldChange {
+	var out []FieldChange
+	add := func(field, x, y string) {
+		if x != y {
+			out = append(out, FieldChange{Field: field, Old: x, New: y})
+		}
+	}
+	if a.Xname != "" && b.Xname != "" {
+		add("xname", a.Xname, b.Xname)
+	}
+	add("mac", strings.ToLower(a.MAC), strings.ToLower(b.MAC))
+	add("ip", a.IP, b.IP)
+	add("alias", a.Alias, b.Alias)
+	add("model", a.Model, b.Model)
+	add("serial_number", a.SerialNumber, b.SerialNumber)
+	add("sku", a.SKU, b.SKU)
+	add("bios_version", a.BiosVersion, b.BiosVersion)
+	add("processor_summary", a.ProcessorSummary, b.ProcessorSummary)
+	add("memory_summary", a.MemorySummary, b.MemorySummary)
+	add("hsn", hsnString(a.HSN), hsnString(b.HSN))
+	add("tags", tagString(a.Tags), tagString(b.Tags))
+	return out
+}
+
+// tagString formats tags as "key=value" pairs sorted by key.
+func tagString(tags map[string]string) string {
+	parts := make([]string, 0, len(tags))
+	for k, v := range tags {
+		parts = append(parts, k+"="+v)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// hsnString formats HSN interfaces as "id=mac" pairs sorted by id.
+func hsnString(nics []NIC) string {
+	parts := make([]string, 0, len(nics))
+	for _, n := range nics {
+		parts = append(parts, n.ID+"="+strings.ToLower(n.MAC))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
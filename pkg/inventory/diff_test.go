@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	old := FileFormat{
+		BMCs: []Entry{{Xname: "x1000c0s0b0", MAC: "02:00:00:00:00:01", IP: "10.1.0.2"}},
+		Nodes: []Entry{
+			{Xname: "x1000c0s0b0n0", MAC: "aa:00:00:00:00:01", IP: "10.2.0.1"},
+			{Xname: "x1000c0s0b0n1", MAC: "aa:00:00:00:00:02", IP: "10.2.0.2"},
+			{Xname: "x1000c0s1b0n0", MAC: "aa:00:00:00:00:03", IP: "10.2.0.3"},
+			{Xname: "x1000c0s2b0n0", MAC: "aa:00:00:00:00:04", IP: "10.2.0.4"},
+		},
+	}
+	new := FileFormat{
+		BMCs: []Entry{{Xname: "x1000c0s0b0", MAC: "02:00:00:00:00:01", IP: "10.1.0.2"}},
+		Nodes: []Entry{
+			// reordered, MAC case changed: no change
+			{Xname: "x1000c0s0b0n1", MAC: "AA:00:00:00:00:02", IP: "10.2.0.2"},
+			{Xname: "x1000c0s0b0n0", MAC: "aa:00:00:00:00:01", IP: "10.2.0.9"},
+			// same MAC, new xname
+			{Xname: "x1000c0s3b0n0", MAC: "aa:00:00:00:00:03", IP: "10.2.0.3"},
+			{Xname: "x1000c0s4b0n0", MAC: "aa:00:00:00:00:05", IP: "10.2.0.5"},
+		},
+	}
+	got := Diff(old, new)
+	want := []Change{
+		{Section: "nodes", Kind: Changed, Xname: "x1000c0s0b0n0", Fields: []FieldChange{{Field: "ip", Old: "10.2.0.1", New: "10.2.0.9"}}},
+		{Section: "nodes", Kind: Removed, Xname: "x1000c0s2b0n0", Fields: []FieldChange{
+			{Field: "mac", Old: "aa:00:00:00:00:04"}, {Field: "ip", Old: "10.2.0.4"},
+		}},
+		{Section: "nodes", Kind: Changed, Xname: "x1000c0s3b0n0", Fields: []FieldChange{{Field: "xname", Old: "x1000c0s1b0n0", New: "x1000c0s3b0n0"}}},
+		{Section: "nodes", Kind: Added, Xname: "x1000c0s4b0n0", Fields: []FieldChange{
+			{Field: "mac", New: "aa:00:00:00:00:05"}, {Field: "ip", New: "10.2.0.5"},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff =\n%+v\nwant\n%+v", got, want)
+	}
+	if d := Diff(old, old); len(d) != 0 {
+		t.Errorf("Diff of identical files = %+v", d)
+	}
+}
@@ -0,0 +1,444 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package netbox is an inventory.Source backed by the NetBox DCIM REST API.
+//
+// Each NetBox device is a node. Its xname is the device's "xname" custom field,
+// else the device name; a device name other than the xname becomes the node's
+// alias. The node's MAC and IP are those of the interface holding the device's
+// primary IPv4 address, else of its first interface with a MAC that is not
+// management only, else of its first interface that is not, which Push fills
+// in. The first management-only interface is the node's BMC, whose xname is
+// derived from the node's (x1000c0s0b0n0 -> x1000c0s0b0); nodes sharing a BMC
+// name it once.
+package netbox
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"bootstrap/internal/xname"
+	"bootstrap/pkg/inventory"
+)
+
+// pageSize is the limit asked of each list request; NetBox caps it at its
+// MAX_PAGE_SIZE (1000 by default) and the next link pages through the rest.
+const pageSize = 1000
+
+// idsPerRequest bounds the device_id filters of one request, keeping URLs short.
+const idsPerRequest = 100
+
+// Client reads and updates the devices of a NetBox instance at URL (e.g.
+// https://netbox.example). Token is an API token; Tag, when set, limits the
+// devices to those carrying that tag slug.
+type Client struct {
+	URL   string
+	Token string
+	Tag   string
+	HTTP  *http.Client
+	// Warn is told about devices that cannot be mapped to inventory entries;
+	// nil discards the warnings.
+	Warn func(msg string)
+}
+
+var _ inventory.Source = (*Client)(nil)
+
+// New returns a Client for url with the given API token, device tag and
+// request timeout.
+func New(url, token, tag string, timeout time.Duration) *Client {
+	return &Client{URL: strings.TrimRight(url, "/"), Token: token, Tag: tag, HTTP: &http.Client{Timeout: timeout}}
+}
+
+type ref struct {
+	ID      int    `json:"id"`
+	Address string `json:"address,omitempty"`
+}
+
+type device struct {
+	ID           int            `json:"id"`
+	Name         string         `json:"name"`
+	PrimaryIP4   *ref           `json:"primary_ip4"`
+	CustomFields map[string]any `json:"custom_fields"`
+}
+
+type iface struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Device   ref    `json:"device"`
+	MgmtOnly bool   `json:"mgmt_only"`
+	// MAC before NetBox 4.2; later versions keep MACs as objects of their own
+	// and report the interface's primary one
+	MAC        *string         `json:"mac_address"`
+	PrimaryMAC json.RawMessage `json:"primary_mac_address"`
+}
+
+type ipAddress struct {
+	ID             int    `json:"id"`
+	Address        string `json:"address"` // CIDR, e.g. 10.0.0.5/24
+	AssignedType   string `json:"assigned_object_type"`
+	AssignedObject int    `json:"assigned_object_id"`
+}
+
+// mac returns the MAC of i, lowercased.
+func (i iface) mac() string {
+	if i.MAC != nil && *i.MAC != "" {
+		return strings.ToLower(*i.MAC)
+	}
+	var p struct {
+		MAC string `json:"mac_address"`
+	}
+	if len(i.PrimaryMAC) > 0 && json.Unmarshal(i.PrimaryMAC, &p) == nil {
+		return strings.ToLower(p.MAC)
+	}
+	return ""
+}
+
+// macObjects reports whether the NetBox keeps MACs as objects (4.2 and later).
+func (i iface) macObjects() bool {
+	return i.PrimaryMAC != nil
+}
+
+// slot is where an inventory entry lives in NetBox.
+type slot struct {
+	section string
+	entry   inventory.Entry
+	iface   *iface // nil when the device has no suitable interface
+	ips     []ipAddress
+}
+
+// load reads the devices, their interfaces and IP addresses and maps them to
+// inventory entries.
+func (c *Client) load(ctx context.Context) ([]slot, error) {
+	q := url.Values{}
+	if c.Tag != "" {
+		q.Set("tag", c.Tag)
+	}
+	devices, err := list[device](ctx, c, "/api/dcim/devices/", q)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, len(devices))
+	for i, d := range devices {
+		ids[i] = d.ID
+	}
+	var ifaces []iface
+	var ips []ipAddress
+	for chunk := range slices.Chunk(ids, idsPerRequest) {
+		q := url.Values{}
+		for _, id := range chunk {
+			q.Add("device_id", strconv.Itoa(id))
+		}
+		got, err := list[iface](ctx, c, "/api/dcim/interfaces/", q)
+		if err != nil {
+			return nil, err
+		}
+		ifaces = append(ifaces, got...)
+		addrs, err := list[ipAddress](ctx, c, "/api/ipam/ip-addresses/", q)
+		if err != nil {
+			return nil, err
+		}
+		ips = append(ips, addrs...)
+	}
+	byDevice := map[int][]iface{}
+	for _, i := range ifaces {
+		byDevice[i.Device.ID] = append(byDevice[i.Device.ID], i)
+	}
+	byIface := map[int][]ipAddress{}
+	for _, ip := range ips {
+		if ip.AssignedType == "dcim.interface" {
+			byIface[ip.AssignedObject] = append(byIface[ip.AssignedObject], ip)
+		}
+	}
+
+	var out []slot
+	bmcs := map[string]bool{}
+	for _, d := range devices {
+		x := d.Name
+		if v, ok := d.CustomFields["xname"].(string); ok && v != "" {
+			x = v
+		}
+		if x == "" {
+			c.warn("device %d has neither a name nor an xname custom field; skipped", d.ID)
+			continue
+		}
+		node := slot{section: "nodes", entry: inventory.Entry{Xname: x}}
+		if d.Name != "" && d.Name != x {
+			node.entry.Alias = d.Name
+		}
+		var mgmt, first *iface
+		its := byDevice[d.ID]
+		slices.SortFunc(its, func(a, b iface) int { return cmp.Compare(a.Name, b.Name) })
+		for i := range its {
+			it := &its[i]
+			switch {
+			case it.MgmtOnly:
+				if mgmt == nil {
+					mgmt = it
+				}
+			case d.PrimaryIP4 != nil && hasIP(byIface[it.ID], d.PrimaryIP4.ID):
+				node.iface = it
+			case node.iface == nil && it.mac() != "":
+				node.iface = it
+			}
+			if first == nil && !it.MgmtOnly {
+				first = it
+			}
+		}
+		if node.iface == nil {
+			node.iface = first // where a pushed MAC goes
+		}
+		if node.iface != nil {
+			node.ips = byIface[node.iface.ID]
+			node.entry.MAC = node.iface.mac()
+			node.entry.IP = firstIP(node.ips, d.PrimaryIP4)
+		}
+		out = append(out, node)
+
+		if mgmt == nil {
+			continue
+		}
+		bx := xname.NodeToBMCXname(x)
+		if bx == "" {
+			c.warn("%s: management interface %s skipped: the BMC xname cannot be derived from a node xname without a node number", x, mgmt.Name)
+			continue
+		}
+		if bmcs[bx] {
+			continue
+		}
+		bmcs[bx] = true
+		ipsOf := byIface[mgmt.ID]
+		out = append(out, slot{section: "bmcs", entry: inventory.Entry{Xname: bx, MAC: mgmt.mac(), IP: firstIP(ipsOf, nil)}, iface: mgmt, ips: ipsOf})
+	}
+	return out, nil
+}
+
+func hasIP(ips []ipAddress, id int) bool {
+	return slices.ContainsFunc(ips, func(ip ipAddress) bool { return ip.ID == id })
+}
+
+// firstIP returns the address of primary when it is among ips, else the first
+// of ips, without its prefix length.
+func firstIP(ips []ipAddress, primary *ref) string {
+	if len(ips) == 0 {
+		return ""
+	}
+	pick := ips[0]
+	for _, ip := range ips {
+		if primary != nil && ip.ID == primary.ID {
+			pick = ip
+		}
+	}
+	addr, _, _ := strings.Cut(pick.Address, "/")
+	return addr
+}
+
+func (c *Client) warn(format string, args ...any) {
+	if c.Warn != nil {
+		c.Warn(fmt.Sprintf(format, args...))
+	}
+}
+
+// Pull returns the devices as nodes[] and their management interfaces as
+// bmcs[].
+func (c *Client) Pull(ctx context.Context) (inventory.FileFormat, error) {
+	slots, err := c.load(ctx)
+	if err != nil {
+		return inventory.FileFormat{}, err
+	}
+	var doc inventory.FileFormat
+	for _, s := range slots {
+		if s.section == "bmcs" {
+			doc.BMCs = append(doc.BMCs, s.entry)
+		} else {
+			doc.Nodes = append(doc.Nodes, s.entry)
+		}
+	}
+	return doc, nil
+}
+
+// Push sets the MAC of interfaces without one and assigns the IP to interfaces
+// without an address, with the prefix length of the most specific NetBox
+// prefix containing it (/32 when none does).
+func (c *Client) Push(ctx context.Context, doc inventory.FileFormat, dryRun bool) (inventory.PushResult, error) {
+	var res inventory.PushResult
+	slots, err := c.load(ctx)
+	if err != nil {
+		return res, err
+	}
+	bySlot := map[string]slot{}
+	for _, s := range slots {
+		bySlot[s.section+"/"+strings.ToLower(s.entry.Xname)] = s
+	}
+	for _, sec := range []struct {
+		name    string
+		entries []inventory.Entry
+	}{{"bmcs", doc.BMCs}, {"nodes", doc.Nodes}} {
+		for _, e := range sec.entries {
+			if e.MAC == "" && e.IP == "" {
+				continue
+			}
+			s, ok := bySlot[sec.name+"/"+strings.ToLower(e.Xname)]
+			if !ok || s.iface == nil {
+				res.Missing = append(res.Missing, e.Xname)
+				continue
+			}
+			if e.MAC != "" {
+				u := inventory.FieldUpdate{Section: sec.name, Xname: e.Xname, Field: "mac", Old: s.entry.MAC, New: strings.ToLower(e.MAC)}
+				switch {
+				case s.entry.MAC == "":
+					if !dryRun {
+						if err := c.setMAC(ctx, *s.iface, u.New); err != nil {
+							return res, fmt.Errorf("%s: set MAC of %s: %w", e.Xname, s.iface.Name, err)
+						}
+					}
+					res.Updated = append(res.Updated, u)
+				case s.entry.MAC != u.New:
+					res.Conflicts = append(res.Conflicts, u)
+				}
+			}
+			if e.IP != "" {
+				u := inventory.FieldUpdate{Section: sec.name, Xname: e.Xname, Field: "ip", Old: s.entry.IP, New: e.IP}
+				switch {
+				case len(s.ips) == 0:
+					if !dryRun {
+						if err := c.addIP(ctx, *s.iface, e.IP); err != nil {
+							return res, fmt.Errorf("%s: assign %s to %s: %w", e.Xname, e.IP, s.iface.Name, err)
+						}
+					}
+					res.Updated = append(res.Updated, u)
+				case !slices.ContainsFunc(s.ips, func(ip ipAddress) bool { return strings.HasPrefix(ip.Address, e.IP+"/") }):
+					res.Conflicts = append(res.Conflicts, u)
+				}
+			}
+		}
+	}
+	return res, nil
+}
+
+// setMAC records mac as the MAC of i.
+func (c *Client) setMAC(ctx context.Context, i iface, mac string) error {
+	path := fmt.Sprintf("/api/dcim/interfaces/%d/", i.ID)
+	if !i.macObjects() {
+		return c.do(ctx, http.MethodPatch, path, map[string]any{"mac_address": mac}, nil)
+	}
+	var created ref
+	err := c.do(ctx, http.MethodPost, "/api/dcim/mac-addresses/", map[string]any{
+		"mac_address": mac, "assigned_object_type": "dcim.interface", "assigned_object_id": i.ID,
+	}, &created)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPatch, path, map[string]any{"primary_mac_address": created.ID}, nil)
+}
+
+// addIP creates ip and assigns it to i.
+func (c *Client) addIP(ctx context.Context, i iface, ip string) error {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return err
+	}
+	bits := addr.BitLen()
+	prefixes, err := list[struct {
+		Prefix string `json:"prefix"`
+	}](ctx, c, "/api/ipam/prefixes/", url.Values{"contains": {ip}})
+	if err != nil {
+		return err
+	}
+	best := -1
+	for _, p := range prefixes {
+		if pfx, err := netip.ParsePrefix(p.Prefix); err == nil && pfx.Contains(addr) && pfx.Bits() > best {
+			best = pfx.Bits()
+		}
+	}
+	if best >= 0 {
+		bits = best
+	}
+	return c.do(ctx, http.MethodPost, "/api/ipam/ip-addresses/", map[string]any{
+		"address": fmt.Sprintf("%s/%d", ip, bits), "assigned_object_type": "dcim.interface", "assigned_object_id": i.ID,
+	}, nil)
+}
+
+// list returns every object of the list endpoint path filtered by q,
+// following the pages.
+func list[T any](ctx context.Context, c *Client, path string, q url.Values) ([]T, error) {
+	q.Set("limit", strconv.Itoa(pageSize))
+	next := c.URL + path + "?" + q.Encode()
+	var out []T
+	for next != "" {
+		var page struct {
+			Next    *string `json:"next"`
+			Results []T     `json:"results"`
+		}
+		if err := c.send(ctx, http.MethodGet, next, nil, &page); err != nil {
+			return nil, err
+		}
+		out = append(out, page.Results...)
+		next = ""
+		if page.Next != nil {
+			next = *page.Next
+		}
+	}
+	return out, nil
+}
+
+// do sends body as JSON to the API path and decodes the answer into out
+// unless it is nil.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	return c.send(ctx, method, c.URL+path, body, out)
+}
+
+func (c *Client) send(ctx context.Context, method, rawURL string, body, out any) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		// NetBox 4.5 v2 tokens are bearer tokens; older ones use the Token scheme
+		scheme := "Token "
+		if strings.HasPrefix(c.Token, "nbt_") {
+			scheme = "Bearer "
+		}
+		req.Header.Set("Authorization", scheme+c.Token)
+	}
+	hc := c.HTTP
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: %s: %s", method, req.URL.Path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,184 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package netbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"bootstrap/pkg/inventory"
+)
+
+// fakeNetBox serves the list endpoints from fixed results, paging devices two
+// at a time, and records the writes.
+type fakeNetBox struct {
+	t       *testing.T
+	mu      sync.Mutex
+	writes  []string
+	devices []map[string]any
+	ifaces  []map[string]any
+	ips     []map[string]any
+}
+
+func (f *fakeNetBox) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if got := r.Header.Get("Authorization"); got != "Token secret" {
+		f.t.Errorf("Authorization = %q", got)
+	}
+	if r.Method != http.MethodGet {
+		body, _ := io.ReadAll(r.Body)
+		f.mu.Lock()
+		f.writes = append(f.writes, r.Method+" "+r.URL.Path+" "+strings.TrimSpace(string(body)))
+		f.mu.Unlock()
+		if r.URL.Path == "/api/dcim/mac-addresses/" {
+			fmt.Fprint(w, `{"id": 77}`)
+			return
+		}
+		fmt.Fprint(w, `{}`)
+		return
+	}
+	var results []map[string]any
+	switch r.URL.Path {
+	case "/api/dcim/devices/":
+		if r.URL.Query().Get("tag") != "hpc" {
+			f.t.Errorf("devices query %s", r.URL.RawQuery)
+		}
+		results = f.devices
+	case "/api/dcim/interfaces/":
+		results = f.ifaces
+	case "/api/ipam/ip-addresses/":
+		results = f.ips
+	case "/api/ipam/prefixes/":
+		if r.URL.Query().Get("contains") == "10.1.0.12" {
+			results = []map[string]any{{"prefix": "10.0.0.0/8"}, {"prefix": "10.1.0.0/16"}}
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	page := map[string]any{"next": nil}
+	if r.URL.Path == "/api/dcim/devices/" && r.URL.Query().Get("offset") == "" && len(results) > 2 {
+		page["next"] = "http://" + r.Host + r.URL.Path + "?tag=hpc&offset=2"
+		results = results[:2]
+	} else if r.URL.Query().Get("offset") == "2" {
+		results = results[2:]
+	}
+	page["results"] = results
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+func newFake(t *testing.T) (*fakeNetBox, *Client) {
+	f := &fakeNetBox{t: t,
+		devices: []map[string]any{
+			{"id": 1, "name": "nid000001", "custom_fields": map[string]any{"xname": "x1000c0s0b0n0"}, "primary_ip4": map[string]any{"id": 11}},
+			{"id": 2, "name": "x1000c0s0b0n1", "custom_fields": map[string]any{}},
+			{"id": 3, "name": "x1000c0s1b0n0", "custom_fields": map[string]any{"xname": nil}},
+			{"id": 4, "name": "", "custom_fields": map[string]any{}},
+		},
+		ifaces: []map[string]any{
+			{"id": 101, "name": "eth1", "device": map[string]any{"id": 1}, "mac_address": "AA:00:00:00:00:02"},
+			{"id": 100, "name": "eth0", "device": map[string]any{"id": 1}, "mac_address": "AA:00:00:00:00:01"},
+			{"id": 102, "name": "bmc", "device": map[string]any{"id": 1}, "mgmt_only": true, "mac_address": "02:00:00:00:00:01"},
+			{"id": 200, "name": "eth0", "device": map[string]any{"id": 2}, "mac_address": "AA:00:00:00:00:03"},
+			{"id": 202, "name": "bmc", "device": map[string]any{"id": 2}, "mgmt_only": true, "mac_address": nil},
+			{"id": 300, "name": "eth0", "device": map[string]any{"id": 3}, "mac_address": nil},
+			{"id": 302, "name": "bmc", "device": map[string]any{"id": 3}, "mgmt_only": true, "primary_mac_address": nil},
+		},
+		ips: []map[string]any{
+			{"id": 10, "address": "10.1.0.99/16", "assigned_object_type": "dcim.interface", "assigned_object_id": 100},
+			{"id": 11, "address": "10.1.0.10/16", "assigned_object_type": "dcim.interface", "assigned_object_id": 101},
+			{"id": 12, "address": "192.168.0.10/24", "assigned_object_type": "dcim.interface", "assigned_object_id": 102},
+		},
+	}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	c := New(srv.URL+"/", "secret", "hpc", 5*time.Second)
+	return f, c
+}
+
+func TestPull(t *testing.T) {
+	_, c := newFake(t)
+	var warnings []string
+	c.Warn = func(msg string) { warnings = append(warnings, msg) }
+	got, err := c.Pull(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := inventory.FileFormat{
+		BMCs: []inventory.Entry{
+			{Xname: "x1000c0s0b0", MAC: "02:00:00:00:00:01", IP: "192.168.0.10"},
+			{Xname: "x1000c0s1b0"},
+		},
+		Nodes: []inventory.Entry{
+			// eth1 holds the primary IP
+			{Xname: "x1000c0s0b0n0", Alias: "nid000001", MAC: "aa:00:00:00:00:02", IP: "10.1.0.10"},
+			{Xname: "x1000c0s0b0n1", MAC: "aa:00:00:00:00:03"},
+			{Xname: "x1000c0s1b0n0"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "device 4") {
+		t.Errorf("warnings = %q", warnings)
+	}
+}
+
+func TestPush(t *testing.T) {
+	f, c := newFake(t)
+	doc := inventory.FileFormat{
+		BMCs: []inventory.Entry{
+			{Xname: "x1000c0s0b0", MAC: "02:00:00:00:00:01", IP: "192.168.0.11"}, // IP differs
+			{Xname: "x1000c0s1b0", MAC: "02:00:00:00:00:03"},                     // MAC object
+			{Xname: "x9000c0s0b0", MAC: "02:00:00:00:00:09"},                     // not in NetBox
+		},
+		Nodes: []inventory.Entry{
+			{Xname: "x1000c0s0b0n1", MAC: "AA:00:00:00:00:03"},
+			{Xname: "x1000c0s1b0n0", MAC: "aa:00:00:00:00:05", IP: "10.1.0.12"},
+		},
+	}
+
+	res, err := c.Push(context.Background(), doc, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.writes) != 0 {
+		t.Errorf("dry run wrote %q", f.writes)
+	}
+	wantRes := inventory.PushResult{
+		Updated: []inventory.FieldUpdate{
+			{Section: "bmcs", Xname: "x1000c0s1b0", Field: "mac", New: "02:00:00:00:00:03"},
+			{Section: "nodes", Xname: "x1000c0s1b0n0", Field: "mac", New: "aa:00:00:00:00:05"},
+			{Section: "nodes", Xname: "x1000c0s1b0n0", Field: "ip", New: "10.1.0.12"},
+		},
+		Conflicts: []inventory.FieldUpdate{{Section: "bmcs", Xname: "x1000c0s0b0", Field: "ip", Old: "192.168.0.10", New: "192.168.0.11"}},
+		Missing:   []string{"x9000c0s0b0"},
+	}
+	if !reflect.DeepEqual(res, wantRes) {
+		t.Errorf("got %+v\nwant %+v", res, wantRes)
+	}
+
+	if _, err := c.Push(context.Background(), doc, false); err != nil {
+		t.Fatal(err)
+	}
+	wantWrites := []string{
+		`POST /api/dcim/mac-addresses/ {"assigned_object_id":302,"assigned_object_type":"dcim.interface","mac_address":"02:00:00:00:00:03"}`,
+		`PATCH /api/dcim/interfaces/302/ {"primary_mac_address":77}`,
+		`PATCH /api/dcim/interfaces/300/ {"mac_address":"aa:00:00:00:00:05"}`,
+		`POST /api/ipam/ip-addresses/ {"address":"10.1.0.12/16","assigned_object_id":300,"assigned_object_type":"dcim.interface"}`,
+	}
+	if !slices.Equal(f.writes, wantWrites) {
+		t.Errorf("writes:\n%s\nwant:\n%s", strings.Join(f.writes, "\n"), strings.Join(wantWrites, "\n"))
+	}
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"context"
+	"maps"
+	"strings"
+)
+
+// Source is an external system of record for the inventory, such as a DCIM.
+// Pull reads its bmcs[] and nodes[]; Push records what discovery found where
+// the source has nothing yet, leaving the source authoritative.
+type Source interface {
+	// Pull returns the BMCs and nodes the source knows about.
+	Pull(ctx context.Context) (FileFormat, error)
+	// Push fills in the MACs and IPs of doc the source lacks and reports those
+	// it already has that differ, without changing them. With dryRun nothing
+	// is written.
+	Push(ctx context.Context, doc FileFormat, dryRun bool) (PushResult, error)
+}
+
+// PushResult is the outcome of a Push.
+type PushResult struct {
+	Updated   []FieldUpdate `json:"updated"`   // written (or, with dryRun, to be written)
+	Conflicts []FieldUpdate `json:"conflicts"` // the source's value (Old) differs from the inventory's (New)
+	Missing   []string      `json:"missing"`   // xnames the source has no place for
+}
+
+// FieldUpdate is a value of an entry in the source: Old as the source has it,
+// New as the inventory has it.
+type FieldUpdate struct {
+	Section string `json:"section"` // bmcs or nodes
+	Xname   string `json:"xname"`
+	Field   string `json:"field"` // mac or ip
+	Old     string `json:"old,omitempty"`
+	New     string `json:"new"`
+}
+
+// Merge returns base updated from src, matching entries by xname: the xname,
+// MAC, IP and alias src has for an entry replace those of base and its tags are
+// added to those of base, other fields such as discovered system metadata are
+// kept, entries only in src are added and entries only in base are kept.
+func Merge(base, src FileFormat) FileFormat {
+	return FileFormat{BMCs: mergeEntries(base.BMCs, src.BMCs), Nodes: mergeEntries(base.Nodes, src.Nodes)}
+}
+
+func mergeEntries(base, src []Entry) []Entry {
+	out := append([]Entry(nil), base...)
+	idx := map[string]int{}
+	for i, e := range out {
+		idx[strings.ToLower(e.Xname)] = i
+	}
+	for _, s := range src {
+		i, ok := idx[strings.ToLower(s.Xname)]
+		if !ok {
+			idx[strings.ToLower(s.Xname)] = len(out)
+			out = append(out, s)
+			continue
+		}
+		e := &out[i]
+		if s.MAC != "" {
+			e.MAC = s.MAC
+		}
+		if s.IP != "" {
+			e.IP = s.IP
+		}
+		if s.Alias != "" {
+			e.Alias = s.Alias
+		}
+		if len(s.Tags) > 0 {
+			e.Tags = maps.Clone(e.Tags)
+			if e.Tags == nil {
+				e.Tags = map[string]string{}
+			}
+			maps.Copy(e.Tags, s.Tags)
+		}
+	}
+	return out
+}
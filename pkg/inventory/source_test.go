@@ -0,0 +1,37 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	base := FileFormat{
+		BMCs: []Entry{{Xname: "x1000c0s0b0", MAC: "02:00:00:00:00:01", IP: "10.1.0.2"}},
+		Nodes: []Entry{
+			{Xname: "x1000c0s0b0n0", MAC: "aa:00:00:00:00:01", Model: "XD225v", HSN: []NIC{{ID: "hsn0", MAC: "bb:00:00:00:00:01"}}},
+			{Xname: "x1000c0s1b0n0", IP: "10.2.0.9"},
+		},
+	}
+	src := FileFormat{
+		BMCs:  []Entry{{Xname: "X1000C0S0B0", IP: "10.1.0.3"}, {Xname: "x1000c0s2b0", MAC: "02:00:00:00:00:03"}},
+		Nodes: []Entry{{Xname: "x1000c0s0b0n0", Alias: "nid000001", IP: "10.2.0.1"}},
+	}
+	want := FileFormat{
+		BMCs: []Entry{{Xname: "x1000c0s0b0", MAC: "02:00:00:00:00:01", IP: "10.1.0.3"}, {Xname: "x1000c0s2b0", MAC: "02:00:00:00:00:03"}},
+		Nodes: []Entry{
+			{Xname: "x1000c0s0b0n0", Alias: "nid000001", MAC: "aa:00:00:00:00:01", IP: "10.2.0.1", Model: "XD225v", HSN: []NIC{{ID: "hsn0", MAC: "bb:00:00:00:00:01"}}},
+			{Xname: "x1000c0s1b0n0", IP: "10.2.0.9"},
+		},
+	}
+	if got := Merge(base, src); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+	if base.BMCs[0].IP != "10.1.0.2" {
+		t.Errorf("Merge changed base: %+v", base.BMCs[0])
+	}
+}
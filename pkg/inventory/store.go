@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import "strings"
+
+// Store keeps an inventory. Save replaces the whole inventory atomically, so a
+// reader never sees a partial update.
+type Store interface {
+	Load() (FileFormat, error)
+	Save(doc FileFormat) error
+	// Find returns the entries whose xname, MAC, IP or HSN MAC equals key.
+	Find(key string) ([]Match, error)
+	Close() error
+}
+
+// Match is an entry found by a lookup.
+type Match struct {
+	Section string `json:"section"` // bmcs or nodes
+	Entry   Entry  `json:"entry"`
+}
+
+// Keys returns the lookup keys of an entry: its xname, MAC, IP, alias and HSN
+// MACs, prefixed by their kind (e.g. "mac:02:00:00:00:00:01"). MACs and aliases
+// are lowercased.
+func (e Entry) Keys() []string {
+	var out []string
+	if e.Xname != "" {
+		out = append(out, "xname:"+e.Xname)
+	}
+	if e.MAC != "" {
+		out = append(out, "mac:"+strings.ToLower(e.MAC))
+	}
+	if e.IP != "" {
+		out = append(out, "ip:"+e.IP)
+	}
+	if e.Alias != "" {
+		out = append(out, "alias:"+strings.ToLower(e.Alias))
+	}
+	for _, n := range e.HSN {
+		if n.MAC != "" {
+			out = append(out, "mac:"+strings.ToLower(n.MAC))
+		}
+	}
+	return out
+}
+
+// LookupKeys returns the keys an entry matching key would have, for any kind.
+func LookupKeys(key string) []string {
+	return []string{"xname:" + key, "mac:" + strings.ToLower(key), "ip:" + key, "alias:" + strings.ToLower(key)}
+}
+
+// Find returns the entries of f matching key, bmcs[] first.
+func (f FileFormat) Find(key string) []Match {
+	want := map[string]bool{}
+	for _, k := range LookupKeys(key) {
+		want[k] = true
+	}
+	var out []Match
+	for _, s := range []struct {
+		name    string
+		entries []Entry
+	}{{"bmcs", f.BMCs}, {"nodes", f.Nodes}} {
+		for _, e := range s.entries {
+			for _, k := range e.Keys() {
+				if want[k] {
+					out = append(out, Match{Section: s.name, Entry: e})
+					break
+				}
+			}
+		}
+	}
+	return out
+}
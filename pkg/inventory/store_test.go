@@ -0,0 +1,29 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import "testing"
+
+func TestFind(t *testing.T) {
+	f := FileFormat{
+		BMCs:  []Entry{{Xname: "x1000c0s0b0", MAC: "02:00:00:00:00:01", IP: "10.1.0.2"}},
+		Nodes: []Entry{{Xname: "x1000c0s0b0n0", MAC: "aa:00:00:00:00:01", IP: "10.2.0.1", Alias: "nid000001", HSN: []NIC{{ID: "hsn0", MAC: "bb:00:00:00:00:01"}}}},
+	}
+	for key, want := range map[string]string{
+		"x1000c0s0b0":       "bmcs",
+		"02:00:00:00:00:01": "bmcs",
+		"10.2.0.1":          "nodes",
+		"BB:00:00:00:00:01": "nodes",
+		"NID000001":         "nodes",
+	} {
+		got := f.Find(key)
+		if len(got) != 1 || got[0].Section != want {
+			t.Errorf("Find(%s) = %+v, want one entry in %s", key, got, want)
+		}
+	}
+	if got := f.Find("x9"); len(got) != 0 {
+		t.Errorf("Find(x9) = %+v", got)
+	}
+}
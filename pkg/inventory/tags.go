@@ -0,0 +1,153 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"strings"
+
+	"bootstrap/internal/xname"
+)
+
+// Selector picks entries by tag: key=value (the value may be a glob such as
+// r1*), key!=value, or key alone for entries that carry the tag at all.
+type Selector struct {
+	Key    string
+	Value  string
+	Negate bool // key!=value
+	Exists bool // key alone
+}
+
+// ParseSelector parses one --tag selector.
+func ParseSelector(s string) (Selector, error) {
+	s = strings.TrimSpace(s)
+	var sel Selector
+	switch {
+	case strings.Contains(s, "!="):
+		sel.Key, sel.Value, _ = strings.Cut(s, "!=")
+		sel.Negate = true
+	case strings.Contains(s, "="):
+		sel.Key, sel.Value, _ = strings.Cut(s, "=")
+	default:
+		sel.Key, sel.Exists = s, true
+	}
+	sel.Key, sel.Value = strings.TrimSpace(sel.Key), strings.TrimSpace(sel.Value)
+	if sel.Key == "" {
+		return Selector{}, fmt.Errorf("tag selector %q: want key=value, key!=value or key", s)
+	}
+	if _, err := path.Match(sel.Value, ""); err != nil {
+		return Selector{}, fmt.Errorf("tag selector %q: %w", s, err)
+	}
+	return sel, nil
+}
+
+// ParseSelectors parses selectors that must all match.
+func ParseSelectors(specs []string) ([]Selector, error) {
+	out := make([]Selector, 0, len(specs))
+	for _, s := range specs {
+		sel, err := ParseSelector(s)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, sel)
+	}
+	return out, nil
+}
+
+func (s Selector) String() string {
+	switch {
+	case s.Exists:
+		return s.Key
+	case s.Negate:
+		return s.Key + "!=" + s.Value
+	}
+	return s.Key + "=" + s.Value
+}
+
+// Match reports whether tags satisfy s. Keys compare case-insensitively.
+func (s Selector) Match(tags map[string]string) bool {
+	v, ok := lookupTag(tags, s.Key)
+	if s.Exists {
+		return ok
+	}
+	matched, _ := path.Match(s.Value, v)
+	matched = ok && matched
+	if s.Negate {
+		return !matched
+	}
+	return matched
+}
+
+// MatchAll reports whether tags satisfy every selector of sels.
+func MatchAll(sels []Selector, tags map[string]string) bool {
+	for _, s := range sels {
+		if !s.Match(tags) {
+			return false
+		}
+	}
+	return true
+}
+
+func lookupTag(tags map[string]string, key string) (string, bool) {
+	if v, ok := tags[key]; ok {
+		return v, true
+	}
+	for k, v := range tags {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// NodeTags returns the tags of node merged over those of its BMC, bmc.
+func NodeTags(bmc, node Entry) map[string]string {
+	out := maps.Clone(bmc.Tags)
+	if out == nil {
+		out = map[string]string{}
+	}
+	maps.Copy(out, node.Tags)
+	return out
+}
+
+// SelectBMCs returns the bmcs[] entries that match sels themselves or through
+// one of their nodes, which inherit the BMC's tags.
+func (f FileFormat) SelectBMCs(sels []Selector) []Entry {
+	var out []Entry
+	for _, b := range f.BMCs {
+		ok := MatchAll(sels, b.Tags)
+		for _, n := range f.Nodes {
+			if ok {
+				break
+			}
+			ok = b.Xname != "" && strings.EqualFold(xname.NodeToBMCXname(n.Xname), b.Xname) && MatchAll(sels, NodeTags(b, n))
+		}
+		if ok {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// SelectNodes returns the nodes[] entries whose tags, merged over those of
+// their BMC, match sels.
+func (f FileFormat) SelectNodes(sels []Selector) []Entry {
+	var out []Entry
+	for _, n := range f.Nodes {
+		var bmc Entry
+		for _, b := range f.BMCs {
+			if b.Xname != "" && strings.EqualFold(xname.NodeToBMCXname(n.Xname), b.Xname) {
+				bmc = b
+				break
+			}
+		}
+		if MatchAll(sels, NodeTags(bmc, n)) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package inventory
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelectors(t *testing.T) {
+	tags := map[string]string{"Role": "compute", "rack": "r12"}
+	for spec, want := range map[string]bool{
+		"role=compute":     true,
+		"role=login":       false,
+		"rack=r1*":         true,
+		"rack!=r12":        false,
+		"pool!=canary":     true,
+		"rack":             true,
+		"pool":             false,
+		" role = compute ": true,
+	} {
+		sel, err := ParseSelector(spec)
+		if err != nil {
+			t.Fatalf("%q: %v", spec, err)
+		}
+		if got := sel.Match(tags); got != want {
+			t.Errorf("%q: match = %v, want %v", spec, got, want)
+		}
+	}
+	for _, bad := range []string{"", "=x", "!=x", "rack=r[1"} {
+		if _, err := ParseSelector(bad); err == nil {
+			t.Errorf("%q: parsed", bad)
+		}
+	}
+
+	doc := FileFormat{
+		BMCs: []Entry{
+			{Xname: "x1000c0s0b0", Tags: map[string]string{"rack": "r1"}},
+			{Xname: "x1000c0s1b0", Tags: map[string]string{"rack": "r2"}},
+		},
+		Nodes: []Entry{
+			{Xname: "x1000c0s0b0n0", Tags: map[string]string{"pool": "canary"}},
+			{Xname: "x1000c0s0b0n1"},
+			{Xname: "x1000c0s1b0n0", Tags: map[string]string{"rack": "r1"}}, // overrides its BMC
+		},
+	}
+	sels, err := ParseSelectors([]string{"rack=r1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, b := range doc.SelectBMCs(sels) {
+		got = append(got, b.Xname)
+	}
+	if want := []string{"x1000c0s0b0", "x1000c0s1b0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SelectBMCs = %v, want %v", got, want)
+	}
+	sels, _ = ParseSelectors([]string{"rack=r1", "pool!=canary"})
+	got = nil
+	for _, n := range doc.SelectNodes(sels) {
+		got = append(got, n.Xname)
+	}
+	if want := []string{"x1000c0s0b0n1", "x1000c0s1b0n0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SelectNodes = %v, want %v", got, want)
+	}
+
+	// Merge adds tags without touching base; Diff reports them
+	base := FileFormat{BMCs: []Entry{{Xname: "x1000c0s0b0", Tags: map[string]string{"rack": "r1"}}}}
+	merged := Merge(base, FileFormat{BMCs: []Entry{{Xname: "x1000c0s0b0", Tags: map[string]string{"pool": "canary"}}}})
+	if want := map[string]string{"rack": "r1", "pool": "canary"}; !reflect.DeepEqual(merged.BMCs[0].Tags, want) || len(base.BMCs[0].Tags) != 1 {
+		t.Errorf("merged tags = %v, base %v", merged.BMCs[0].Tags, base.BMCs[0].Tags)
+	}
+	changes := Diff(base, merged)
+	if want := []FieldChange{{Field: "tags", Old: "rack=r1", New: "pool=canary,rack=r1"}}; len(changes) != 1 || !reflect.DeepEqual(changes[0].Fields, want) {
+		t.Errorf("diff = %+v", changes)
+	}
+}
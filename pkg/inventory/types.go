@@ -0,0 +1,75 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package inventory defines types for inventory YAML files.
+package inventory
+
+import "strings"
+
+// Entry represents a BMC or Node record in the YAML file.
+type Entry struct {
+	Xname string `yaml:"xname"`
+	MAC   string `yaml:"mac"`
+	IP    string `yaml:"ip"`
+
+	// Alias is an operator-chosen name such as nid001234 or login01, shown in
+	// command output instead of the xname or IP and accepted by --hosts.
+	Alias string `yaml:"alias,omitempty"`
+
+	// Tags are freeform key=value labels such as role: compute, rack: r1 or
+	// pool: canary, selected with --tag. Nodes inherit the tags of their BMC.
+	Tags map[string]string `yaml:"tags,omitempty"`
+
+	// System metadata recorded by discovery for nodes; empty for BMCs.
+	Model            string `yaml:"model,omitempty"`
+	SerialNumber     string `yaml:"serial_number,omitempty"`
+	SKU              string `yaml:"sku,omitempty"`
+	BiosVersion      string `yaml:"bios_version,omitempty"`
+	ProcessorSummary string `yaml:"processor_summary,omitempty"` // e.g. "2x AMD EPYC 7763 (256 threads)"
+	MemorySummary    string `yaml:"memory_summary,omitempty"`    // e.g. "512 GiB"
+
+	// HSN lists a node's high-speed network interfaces, recorded once the node
+	// has booted and the BMC reports their MACs.
+	HSN []NIC `yaml:"hsn,omitempty"`
+}
+
+// NIC is a network interface of a node.
+type NIC struct {
+	ID          string `yaml:"id"`
+	MAC         string `yaml:"mac"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// FileFormat is the root YAML structure with bmcs and nodes.
+type FileFormat struct {
+	BMCs  []Entry `yaml:"bmcs"`
+	Nodes []Entry `yaml:"nodes"`
+}
+
+// DuplicateMACs returns the MAC addresses assigned to more than one entry across
+// bmcs[], nodes[] and their hsn[] interfaces, each with the xnames (xname/id for
+// HSN interfaces) using it. MACs compare case-insensitively.
+func (f FileFormat) DuplicateMACs() map[string][]string {
+	users := map[string][]string{}
+	add := func(mac, xname string) {
+		if mac != "" {
+			mac = strings.ToLower(mac)
+			users[mac] = append(users[mac], xname)
+		}
+	}
+	for _, list := range [][]Entry{f.BMCs, f.Nodes} {
+		for _, e := range list {
+			add(e.MAC, e.Xname)
+			for _, n := range e.HSN {
+				add(n.MAC, e.Xname+"/"+n.ID)
+			}
+		}
+	}
+	for mac, xs := range users {
+		if len(xs) < 2 {
+			delete(users, mac)
+		}
+	}
+	return users
+}
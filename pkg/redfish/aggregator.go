@@ -0,0 +1,35 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import "strings"
+
+// Aggregator is a Redfish aggregator that serves many BMCs from one endpoint,
+// each under its own path prefix in place of /redfish/v1.
+type Aggregator struct {
+	Host     string            // host[:port] of the aggregator
+	Prefixes map[string]string // BMC host or xname (lower case) -> path prefix, e.g. /redfish/v1/Aggregate/x1000c0s0b0
+	Template string            // prefix of BMCs not in Prefixes, with {host} replaced by the BMC host; empty = contact them directly
+}
+
+// Prefix returns the path prefix under which the aggregator serves host.
+func (a *Aggregator) Prefix(host string) (string, bool) {
+	if p, ok := a.Prefixes[strings.ToLower(host)]; ok {
+		return "/" + strings.Trim(p, "/"), true
+	}
+	if a.Template != "" {
+		return "/" + strings.Trim(strings.ReplaceAll(a.Template, "{host}", host), "/"), true
+	}
+	return "", false
+}
+
+// aggregator is the process-wide aggregator of new clients; nil contacts every BMC directly.
+var aggregator *Aggregator
+
+// SetAggregator makes clients created afterwards reach the BMCs a serves through
+// it. nil restores direct connections.
+func SetAggregator(a *Aggregator) {
+	aggregator = a
+}
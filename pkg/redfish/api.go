@@ -0,0 +1,215 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"time"
+)
+
+// Discoverer reads hardware identity and topology from a BMC.
+type Discoverer interface {
+	DiscoverAllBootableMACs(ctx context.Context, prefer NICPreference) ([]SystemMACs, error)
+	DiscoverBootableMACs(ctx context.Context) ([]string, error)
+	DiscoverManagerNICs(ctx context.Context) ([]ManagerNIC, error)
+	DiscoverSystemNICs(ctx context.Context) ([]SystemNIC, error)
+	GetSystems(ctx context.Context) ([]System, error)
+	GetManagers(ctx context.Context) ([]Manager, error)
+	GetChassis(ctx context.Context) ([]Chassis, error)
+	GetEnvironment(ctx context.Context) (Environment, error)
+	GetConsoles(ctx context.Context) ([]Console, error)
+}
+
+// Updater inspects and triggers firmware updates on a BMC.
+type Updater interface {
+	GetFirmwareInventory(ctx context.Context, target string) (FirmwareInventory, error)
+	GetFirmwareInventories(ctx context.Context, targets []string, concurrency int) (map[string]FirmwareInventory, error)
+	GetUpdateServiceStatus(ctx context.Context) (UpdateServiceStatus, error)
+	GetUpdateService(ctx context.Context) (UpdateService, error)
+	GetActiveUpdateTasks(ctx context.Context) ([]string, error)
+	GetUpdateProgress(ctx context.Context) ([]UpdateProgress, error)
+	GetTasks(ctx context.Context) ([]Task, error)
+	GetMessageRegistry(ctx context.Context, messageID string) (MessageRegistry, error)
+	ListFirmware(ctx context.Context) ([]FirmwareVersion, error)
+	ListFirmwareInventory(ctx context.Context) ([]FirmwareComponent, error)
+	SimpleUpdate(ctx context.Context, imageURI string, targets []string, transferProtocol string, expectedVersion string, force bool) (UpdateResult, error)
+}
+
+// Configurer reads and writes BMC and BIOS settings.
+type Configurer interface {
+	GetAuthorizedKeys(ctx context.Context) (string, error)
+	SetAuthorizedKeys(ctx context.Context, authorizedKey string) error
+	GetNTPServers(ctx context.Context) ([]string, error)
+	SetNTPServers(ctx context.Context, servers []string) error
+	GetNetworkProtocol(ctx context.Context) (NetworkProtocol, error)
+	SetHostName(ctx context.Context, hostName, fqdn string) error
+	GetSyslog(ctx context.Context) (SyslogConfig, error)
+	SetSyslog(ctx context.Context, targets []SyslogTarget) error
+	SendSyslogTest(ctx context.Context, message string) error
+	GetBiosAttributes(ctx context.Context) ([]SystemBios, error)
+	SetBiosAttributes(ctx context.Context, systemPath string, attrs map[string]any) error
+	GetAccounts(ctx context.Context) ([]Account, error)
+	GetLocators(ctx context.Context) ([]Locator, error)
+	SetLocator(ctx context.Context, systemPath string, on bool) error
+	GetPowerLimits(ctx context.Context) ([]PowerLimit, error)
+	SetPowerLimit(ctx context.Context, chassisPath string, watts float64) error
+}
+
+// Resetter restarts a BMC, its systems or the chassis (slots) it controls.
+type Resetter interface {
+	ResetManager(ctx context.Context, resetType string) error
+	ResetSystem(ctx context.Context, systemPath, resetType string) error
+	ResetChassis(ctx context.Context, chassisPath, resetType string) error
+}
+
+// Client is a connection to a single BMC.
+type Client interface {
+	Discoverer
+	Updater
+	Configurer
+	Resetter
+}
+
+var _ Client = (*client)(nil)
+
+// New returns a Client for the BMC at host (host or host:port). With insecure set
+// TLS certificates are not verified; timeout bounds each HTTP request.
+func New(host, user, pass string, insecure bool, timeout time.Duration) Client {
+	return newClient(host, user, pass, insecure, timeout)
+}
+
+// Package-level helpers open a one-off client per call, for callers that talk to
+// a BMC only once.
+
+// GetUpdateServiceStatus calls Client.GetUpdateServiceStatus on a new client for host.
+func GetUpdateServiceStatus(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) (UpdateServiceStatus, error) {
+	return newClient(host, user, pass, insecure, timeout).GetUpdateServiceStatus(ctx)
+}
+
+// GetActiveUpdateTasks calls Client.GetActiveUpdateTasks on a new client for host.
+func GetActiveUpdateTasks(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]string, error) {
+	return newClient(host, user, pass, insecure, timeout).GetActiveUpdateTasks(ctx)
+}
+
+// GetFirmwareInventory calls Client.GetFirmwareInventory on a new client for host.
+func GetFirmwareInventory(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, target string) (FirmwareInventory, error) {
+	return newClient(host, user, pass, insecure, timeout).GetFirmwareInventory(ctx, target)
+}
+
+// GetFirmwareInventories calls Client.GetFirmwareInventories on one new client
+// for host, so every target shares its connections.
+func GetFirmwareInventories(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, targets []string, concurrency int) (map[string]FirmwareInventory, error) {
+	return newClient(host, user, pass, insecure, timeout).GetFirmwareInventories(ctx, targets, concurrency)
+}
+
+// DiscoverAllBootableMACs calls Client.DiscoverAllBootableMACs on a new client for host.
+func DiscoverAllBootableMACs(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, prefer NICPreference) ([]SystemMACs, error) {
+	return newClient(host, user, pass, insecure, timeout).DiscoverAllBootableMACs(ctx, prefer)
+}
+
+// DiscoverBootableMACs calls Client.DiscoverBootableMACs on a new client for host.
+func DiscoverBootableMACs(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]string, error) {
+	return newClient(host, user, pass, insecure, timeout).DiscoverBootableMACs(ctx)
+}
+
+// DiscoverManagerNICs calls Client.DiscoverManagerNICs on a new client for host.
+func DiscoverManagerNICs(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]ManagerNIC, error) {
+	return newClient(host, user, pass, insecure, timeout).DiscoverManagerNICs(ctx)
+}
+
+// DiscoverSystemNICs calls Client.DiscoverSystemNICs on a new client for host.
+func DiscoverSystemNICs(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]SystemNIC, error) {
+	return newClient(host, user, pass, insecure, timeout).DiscoverSystemNICs(ctx)
+}
+
+// SimpleUpdate calls Client.SimpleUpdate on a new client for host.
+func SimpleUpdate(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, imageURI string, targets []string, transferProtocol string, expectedVersion string, force bool) (UpdateResult, error) {
+	return newClient(host, user, pass, insecure, timeout).SimpleUpdate(ctx, imageURI, targets, transferProtocol, expectedVersion, force)
+}
+
+// SetAuthorizedKeys calls Client.SetAuthorizedKeys on a new client for host.
+func SetAuthorizedKeys(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, authorizedKey string) error {
+	return newClient(host, user, pass, insecure, timeout).SetAuthorizedKeys(ctx, authorizedKey)
+}
+
+// GetAuthorizedKeys calls Client.GetAuthorizedKeys on a new client for host.
+func GetAuthorizedKeys(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) (string, error) {
+	return newClient(host, user, pass, insecure, timeout).GetAuthorizedKeys(ctx)
+}
+
+// GetSystems calls Client.GetSystems on a new client for host.
+func GetSystems(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]System, error) {
+	return newClient(host, user, pass, insecure, timeout).GetSystems(ctx)
+}
+
+// GetManagers calls Client.GetManagers on a new client for host.
+func GetManagers(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]Manager, error) {
+	return newClient(host, user, pass, insecure, timeout).GetManagers(ctx)
+}
+
+// GetChassis calls Client.GetChassis on a new client for host.
+func GetChassis(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]Chassis, error) {
+	return newClient(host, user, pass, insecure, timeout).GetChassis(ctx)
+}
+
+// GetUpdateProgress calls Client.GetUpdateProgress on a new client for host.
+func GetUpdateProgress(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]UpdateProgress, error) {
+	return newClient(host, user, pass, insecure, timeout).GetUpdateProgress(ctx)
+}
+
+// GetTasks calls Client.GetTasks on a new client for host.
+func GetTasks(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]Task, error) {
+	return newClient(host, user, pass, insecure, timeout).GetTasks(ctx)
+}
+
+// GetUpdateService calls Client.GetUpdateService on a new client for host.
+func GetUpdateService(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) (UpdateService, error) {
+	return newClient(host, user, pass, insecure, timeout).GetUpdateService(ctx)
+}
+
+// GetBiosAttributes calls Client.GetBiosAttributes on a new client for host.
+func GetBiosAttributes(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]SystemBios, error) {
+	return newClient(host, user, pass, insecure, timeout).GetBiosAttributes(ctx)
+}
+
+// SetBiosAttributes calls Client.SetBiosAttributes on a new client for host.
+func SetBiosAttributes(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, systemPath string, attrs map[string]any) error {
+	return newClient(host, user, pass, insecure, timeout).SetBiosAttributes(ctx, systemPath, attrs)
+}
+
+// GetNTPServers calls Client.GetNTPServers on a new client for host.
+func GetNTPServers(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]string, error) {
+	return newClient(host, user, pass, insecure, timeout).GetNTPServers(ctx)
+}
+
+// SetNTPServers calls Client.SetNTPServers on a new client for host.
+func SetNTPServers(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, servers []string) error {
+	return newClient(host, user, pass, insecure, timeout).SetNTPServers(ctx, servers)
+}
+
+// GetAccounts calls Client.GetAccounts on a new client for host.
+func GetAccounts(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]Account, error) {
+	return newClient(host, user, pass, insecure, timeout).GetAccounts(ctx)
+}
+
+// ListFirmwareInventory calls Client.ListFirmwareInventory on a new client for host.
+func ListFirmwareInventory(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]FirmwareComponent, error) {
+	return newClient(host, user, pass, insecure, timeout).ListFirmwareInventory(ctx)
+}
+
+// ListFirmware calls Client.ListFirmware on a new client for host.
+func ListFirmware(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]FirmwareVersion, error) {
+	return newClient(host, user, pass, insecure, timeout).ListFirmware(ctx)
+}
+
+// SetHostName calls Client.SetHostName on a new client for host.
+func SetHostName(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, hostName, fqdn string) error {
+	return newClient(host, user, pass, insecure, timeout).SetHostName(ctx, hostName, fqdn)
+}
+
+// GetNetworkProtocol calls Client.GetNetworkProtocol on a new client for host.
+func GetNetworkProtocol(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) (NetworkProtocol, error) {
+	return newClient(host, user, pass, insecure, timeout).GetNetworkProtocol(ctx)
+}
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"time"
+
+	"bootstrap/internal/diag"
+)
+
+// responseCache stores raw GET response bodies on disk, keyed by credential and URL.
+type responseCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// respCache is the process-wide cache; nil means caching is disabled.
+var respCache *responseCache
+
+// DefaultCacheDir returns the per-user directory used when no cache dir is given.
+func DefaultCacheDir() string {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "ochami_bootstrap", "redfish")
+}
+
+// EnableCache turns on on-disk caching of inventory/status GET responses for ttl.
+// A ttl <= 0 disables caching. An empty dir uses DefaultCacheDir.
+func EnableCache(dir string, ttl time.Duration) error {
+	if ttl <= 0 {
+		respCache = nil
+		return nil
+	}
+	if dir == "" {
+		dir = DefaultCacheDir()
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	respCache = &responseCache{dir: dir, ttl: ttl}
+	return nil
+}
+
+// key hashes the credential with the URL, so a response fetched with one
+// password is not served to a client holding another (or a wrong one).
+func (rc *responseCache) key(user, pass, url string) string {
+	cred := sha256.Sum256([]byte(user + "\x00" + pass))
+	sum := sha256.Sum256([]byte(hex.EncodeToString(cred[:]) + "\x00" + url))
+	return hex.EncodeToString(sum[:])
+}
+
+func (rc *responseCache) load(key string) ([]byte, bool) {
+	p := filepath.Join(rc.dir, key+".json")
+	st, err := os.Stat(p)
+	if err != nil || time.Since(st.ModTime()) > rc.ttl {
+		return nil, false
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+func (rc *responseCache) store(key string, b []byte) {
+	// write via a temp file so concurrent readers never see a partial body
+	tmp, err := os.CreateTemp(rc.dir, key+".*.tmp")
+	if err != nil {
+		diag.Logf("cache store: %v", err)
+		return
+	}
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	_ = tmp.Close()
+	if err := os.Rename(tmp.Name(), filepath.Join(rc.dir, key+".json")); err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+}
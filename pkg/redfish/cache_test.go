@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetCached(t *testing.T) {
+	var hits int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { //nolint:revive
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"Version":"1.2.3"}`))
+	}))
+	defer ts.Close()
+
+	if err := EnableCache(t.TempDir(), time.Minute); err != nil {
+		t.Fatalf("EnableCache failed: %v", err)
+	}
+	defer EnableCache("", 0) //nolint:errcheck
+
+	c := newClient("example.com", "admin", "password", true, 0)
+	c.base = ts.URL + "/redfish/v1"
+	for range 3 {
+		var fw rfFirmwareInventory
+		if err := c.getCached(context.Background(), "/UpdateService/FirmwareInventory/BMC", &fw); err != nil {
+			t.Fatalf("getCached failed: %v", err)
+		}
+		if fw.Version != "1.2.3" {
+			t.Fatalf("got version %q, want 1.2.3", fw.Version)
+		}
+	}
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Fatalf("expected 1 request with cache enabled, got %d", got)
+	}
+
+	// A different user must not share cache entries
+	c.user = "other"
+	var fw rfFirmwareInventory
+	if err := c.getCached(context.Background(), "/UpdateService/FirmwareInventory/BMC", &fw); err != nil {
+		t.Fatalf("getCached failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Fatalf("expected 2 requests after user change, got %d", got)
+	}
+
+	// Nor a different password: a wrong one must reach the BMC and fail there
+	c.user, c.pass = "admin", "wrong"
+	if err := c.getCached(context.Background(), "/UpdateService/FirmwareInventory/BMC", &fw); err != nil {
+		t.Fatalf("getCached failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 3 {
+		t.Fatalf("expected 3 requests after password change, got %d", got)
+	}
+}
@@ -0,0 +1,1027 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package redfish implements a Redfish client for BMC interactions.
+//
+// New returns a Client for one BMC; its Discoverer, Updater, Configurer and
+// Resetter method sets are the supported API for other tools:
+//
+//	c := redfish.New("10.1.0.2", user, pass, true, 30*time.Second)
+//	macs, err := c.DiscoverAllBootableMACs(ctx)
+package redfish
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"bootstrap/internal/diag"
+)
+
+type client struct {
+	host   string // as given to New; what request stats are recorded under
+	base   string
+	prefix string // aggregator path standing in for /redfish/v1; empty when talking to the BMC itself
+	http   *http.Client
+	user   string
+	pass   string
+
+	featuresOnce sync.Once
+	query        queryFeatures // what features read from the service root
+}
+
+func newClient(host, user, pass string, insecure bool, timeout time.Duration) *client {
+	tr := &http.Transport{MaxIdleConnsPerHost: nicFetchLimit} // keep the connections of concurrent GETs
+	if dialer != nil {
+		tr.DialContext = dialer
+	}
+	if insecure {
+		tr.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	c := &client{
+		host: host,
+		base: "https://" + host + "/redfish/v1",
+		http: &http.Client{Timeout: timeout, Transport: tr},
+		user: user,
+		pass: pass,
+	}
+	if aggregator != nil {
+		if p, ok := aggregator.Prefix(host); ok {
+			c.base, c.prefix = "https://"+aggregator.Host+p, p
+		}
+	}
+	return c
+}
+
+type rfCollection struct {
+	Members []struct {
+		OID string `json:"@odata.id"`
+	} `json:"Members"`
+}
+
+type rfEthernetInterface struct {
+	ID               string `json:"Id"`
+	Name             string `json:"Name"`
+	InterfaceEnabled *bool  `json:"InterfaceEnabled"`
+	Description      string `json:"Description"`
+	MACAddress       string `json:"MACAddress"`
+	PermanentMAC     string `json:"PermanentMACAddress"`
+	UefiDevicePath   string `json:"UefiDevicePath"`
+	IPv4Addresses    []struct {
+		Address string `json:"Address"`
+		Origin  string `json:"AddressOrigin"`
+	} `json:"IPv4Addresses"`
+	LinkStatus string `json:"LinkStatus"`
+	SpeedMbps  *int   `json:"SpeedMbps"`
+	HostName   string `json:"HostName"`
+	FQDN       string `json:"FQDN"`
+	VLAN       struct {
+		VLANEnable *bool `json:"VLANEnable"`
+		VLANID     int   `json:"VLANId"`
+	} `json:"VLAN"`
+}
+
+type rfFirmwareInventory struct {
+	Version string `json:"Version"`
+	Status  struct {
+		Health     string `json:"Health"`
+		State      string `json:"State"`
+		Conditions []struct {
+			Message     string   `json:"Message"`
+			MessageArgs []string `json:"MessageArgs"`
+			MessageID   string   `json:"MessageId"`
+			Severity    string   `json:"Severity"`
+			Timestamp   string   `json:"Timestamp"`
+		} `json:"Conditions"`
+	} `json:"Status"`
+}
+
+type rfUpdateService struct {
+	Status struct {
+		Health     string `json:"Health"`
+		State      string `json:"State"`
+		Conditions []struct {
+			Message     string   `json:"Message"`
+			MessageArgs []string `json:"MessageArgs"`
+			MessageID   string   `json:"MessageId"`
+			Severity    string   `json:"Severity"`
+			Timestamp   string   `json:"Timestamp"`
+		} `json:"Conditions"`
+	} `json:"Status"`
+}
+
+// UpdateCondition represents a simplified condition from UpdateService.Status
+type UpdateCondition struct {
+	Message     string
+	Severity    string
+	Timestamp   string
+	MessageID   string
+	MessageArgs []string
+}
+
+// UpdateServiceStatus is an exported, simplified representation of UpdateService.Status
+type UpdateServiceStatus struct {
+	Health     string
+	State      string
+	Conditions []UpdateCondition
+}
+
+// GetUpdateServiceStatus fetches the UpdateService status for a BMC.
+func (c *client) GetUpdateServiceStatus(ctx context.Context) (UpdateServiceStatus, error) {
+	var rf rfUpdateService
+	if err := c.getCached(ctx, "/UpdateService", &rf); err != nil {
+		return UpdateServiceStatus{}, err
+	}
+	out := UpdateServiceStatus{
+		Health: rf.Status.Health,
+		State:  rf.Status.State,
+	}
+	for _, cnd := range rf.Status.Conditions {
+		out.Conditions = append(out.Conditions, UpdateCondition{
+			Message:     cnd.Message,
+			Severity:    cnd.Severity,
+			Timestamp:   cnd.Timestamp,
+			MessageID:   cnd.MessageID,
+			MessageArgs: cnd.MessageArgs,
+		})
+	}
+	return out, nil
+}
+
+type rfTask struct {
+	ID        string `json:"Id"`
+	Name      string `json:"Name"`
+	TaskState string `json:"TaskState"`
+	Message   string `json:"Message"` // not standard, but some BMCs set it instead of Messages
+	Messages  []struct {
+		Message string `json:"Message"`
+	} `json:"Messages"`
+}
+
+// GetActiveUpdateTasks inspects TaskService tasks and returns a list of task IDs that appear to
+// be running firmware/update jobs. This is a best-effort heuristic that looks for running
+// TaskState values and checks Name/Message for update/firmware keywords.
+func (c *client) GetActiveUpdateTasks(ctx context.Context) ([]string, error) {
+	var out []string
+	for b, err := range c.members(ctx, "/TaskService/Tasks", "Id", "Name", "TaskState", "Messages") {
+		var me *memberError
+		if errors.As(err, &me) {
+			// skip tasks we can't fetch
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var t rfTask
+		if json.Unmarshal(b, &t) != nil {
+			continue
+		}
+		msg := t.Message
+		for _, m := range t.Messages {
+			msg += " " + m.Message
+		}
+		if activeUpdateTask(t.TaskState, t.Name, msg) {
+			out = append(out, t.ID)
+		}
+	}
+	return out, nil
+}
+
+// activeUpdateTask reports whether a task with this TaskState, Name and
+// message text is a running firmware update: a running task whose name or
+// messages mention an update, or say nothing at all.
+func activeUpdateTask(state, name, msg string) bool {
+	switch strings.ToLower(state) {
+	case "running", "starting", "inprogress", "queued":
+	default:
+		return false
+	}
+	name, msg = strings.ToLower(name), strings.ToLower(strings.TrimSpace(msg))
+	if strings.Contains(name, "update") || strings.Contains(name, "firmware") || strings.Contains(msg, "update") || strings.Contains(msg, "firmware") {
+		return true
+	}
+	// A running task without name or messages is counted conservatively
+	return name == "" && msg == ""
+}
+
+// FirmwareCondition represents a simplified status condition from firmware inventory.
+type FirmwareCondition struct {
+	Message     string
+	Severity    string
+	Timestamp   string
+	MessageID   string
+	MessageArgs []string
+}
+
+// FirmwareInventory is an exported, simplified representation of firmware inventory information.
+type FirmwareInventory struct {
+	Version    string
+	State      string
+	Health     string
+	Conditions []FirmwareCondition
+}
+
+// GetFirmwareInventory fetches FirmwareInventory data for a given host and target path.
+func (c *client) GetFirmwareInventory(ctx context.Context, target string) (FirmwareInventory, error) {
+	var rf rfFirmwareInventory
+	if err := c.getCached(ctx, target, &rf); err != nil {
+		return FirmwareInventory{}, err
+	}
+	out := FirmwareInventory{
+		Version: rf.Version,
+		State:   rf.Status.State,
+		Health:  rf.Status.Health,
+	}
+	for _, cond := range rf.Status.Conditions {
+		out.Conditions = append(out.Conditions, FirmwareCondition{
+			Message:     cond.Message,
+			Severity:    cond.Severity,
+			Timestamp:   cond.Timestamp,
+			MessageID:   cond.MessageID,
+			MessageArgs: cond.MessageArgs,
+		})
+	}
+	return out, nil
+}
+
+// TargetErrors maps the targets GetFirmwareInventories could not fetch to the
+// error of each.
+type TargetErrors map[string]error
+
+func (e TargetErrors) Error() string {
+	targets := slices.Sorted(maps.Keys(e))
+	msgs := make([]string, len(targets))
+	for i, t := range targets {
+		msgs[i] = fmt.Sprintf("%s: %v", t, e[t])
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// GetFirmwareInventories fetches the FirmwareInventory of every target over the
+// client's connections, up to concurrency at a time (serially when <= 1, at most
+// nicFetchLimit). The result is keyed by target; targets that could not be
+// fetched are left out and returned as TargetErrors.
+func (c *client) GetFirmwareInventories(ctx context.Context, targets []string, concurrency int) (map[string]FirmwareInventory, error) {
+	out := make(map[string]FirmwareInventory, len(targets))
+	failed := TargetErrors{}
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	sem := make(chan struct{}, min(max(1, concurrency), nicFetchLimit))
+	seen := map[string]bool{}
+	for _, t := range targets {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			inv, err := c.GetFirmwareInventory(ctx, t)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed[t] = err
+				return
+			}
+			out[t] = inv
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return out, err
+	}
+	if len(failed) > 0 {
+		return out, failed
+	}
+	return out, nil
+}
+
+// FirmwareVersion is the version of one member of the UpdateService's FirmwareInventory.
+type FirmwareVersion struct {
+	Path    string
+	Version string
+}
+
+// ListFirmware returns the version of every FirmwareInventory member on a BMC.
+func (c *client) ListFirmware(ctx context.Context) ([]FirmwareVersion, error) {
+	comps, err := c.ListFirmwareInventory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]FirmwareVersion, len(comps))
+	for i, fc := range comps {
+		out[i] = FirmwareVersion{Path: fc.Path, Version: fc.Version}
+	}
+	return out, nil
+}
+
+// FirmwareComponent is one member of the UpdateService's FirmwareInventory.
+type FirmwareComponent struct {
+	Path       string // @odata.id; what SimpleUpdate takes as a target
+	ID         string
+	Name       string
+	Version    string
+	SoftwareID string
+	Updateable bool // false only when the BMC reports Updateable false
+	State      string
+	Health     string
+	// RelatedItems are the resources the component belongs to, such as the
+	// ComputerSystem whose BIOS it is.
+	RelatedItems []string
+}
+
+type rfFirmwareComponent struct {
+	ID         string `json:"Id"`
+	Name       string `json:"Name"`
+	Version    string `json:"Version"`
+	SoftwareID string `json:"SoftwareId"`
+	Updateable *bool  `json:"Updateable"`
+	Status     struct {
+		State  string `json:"State"`
+		Health string `json:"Health"`
+	} `json:"Status"`
+	RelatedItem []Link `json:"RelatedItem"`
+}
+
+// ListFirmwareInventory walks the UpdateService's FirmwareInventory collection
+// and returns every member the BMC reports, in collection order, rather than
+// the fixed BMC/BIOS paths of one vendor.
+func (c *client) ListFirmwareInventory(ctx context.Context) ([]FirmwareComponent, error) {
+	var coll rfCollection
+	if err := c.get(ctx, "/UpdateService/FirmwareInventory", &coll); err != nil {
+		return nil, err
+	}
+	out := make([]FirmwareComponent, 0, len(coll.Members))
+	for _, m := range coll.Members {
+		var rf rfFirmwareComponent
+		if err := c.getCached(ctx, m.OID, &rf); err != nil {
+			return nil, err
+		}
+		fc := FirmwareComponent{
+			Path: m.OID, ID: rf.ID, Name: rf.Name, Version: rf.Version, SoftwareID: rf.SoftwareID,
+			Updateable: rf.Updateable == nil || *rf.Updateable, State: rf.Status.State, Health: rf.Status.Health,
+		}
+		for _, r := range rf.RelatedItem {
+			fc.RelatedItems = append(fc.RelatedItems, r.ODataID)
+		}
+		out = append(out, fc)
+	}
+	return out, nil
+}
+
+func (c *client) get(ctx context.Context, path string, v any) error {
+	b, err := c.getRaw(ctx, path)
+	if err != nil {
+		return err
+	}
+	return decodeJSON(c.resolvePath(path), b, v)
+}
+
+// getCached is like get but serves the response from the on-disk cache when enabled
+// and fresh. Only use it for read-only inventory/status resources.
+func (c *client) getCached(ctx context.Context, path string, v any) error {
+	if respCache == nil {
+		return c.get(ctx, path, v)
+	}
+	url := c.resolvePath(path)
+	key := respCache.key(c.user, c.pass, url)
+	if b, ok := respCache.load(key); ok {
+		diag.Logf("GET %s (cached)", url)
+		return decodeJSON(url, b, v)
+	}
+	b, err := c.getRaw(ctx, path)
+	if err != nil {
+		return err
+	}
+	if err := decodeJSON(url, b, v); err != nil {
+		return err
+	}
+	respCache.store(key, b)
+	return nil
+}
+
+func (c *client) getRaw(ctx context.Context, path string) ([]byte, error) {
+	path = c.resolvePath(path)
+	diag.Logf("GET %s", path)
+	req, err := http.NewRequestWithContext(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(c.user, c.pass)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, &TransportError{Method: "GET", URL: path, Err: err}
+	}
+	defer resp.Body.Close() // nolint:errcheck
+	diag.Logf("GET %s -> %s", path, resp.Status)
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		return nil, &StatusError{Method: "GET", URL: path, StatusCode: resp.StatusCode, Status: resp.Status, Body: errorBody(resp.Header.Get("Content-Type"), b)}
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &TransportError{Method: "GET", URL: path, Err: err}
+	}
+	return checkJSON(path, resp.Header.Get("Content-Type"), b)
+}
+
+func (c *client) post(ctx context.Context, path string, body any) error {
+	_, err := c.postAction(ctx, path, body)
+	return err
+}
+
+// postAction POSTs an action and returns the task monitor URI of the accepted
+// request: the Location header, or the @odata.id of a Task in the response body.
+// It is empty when the BMC completed the action synchronously.
+func (c *client) postAction(ctx context.Context, path string, body any) (string, error) {
+	path = c.resolvePath(path)
+	b, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	diag.Logf("POST %s", path)
+	req, err := http.NewRequestWithContext(ctx, "POST", path, strings.NewReader(string(b)))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(c.user, c.pass)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.do(req)
+	if err != nil {
+		return "", &TransportError{Method: "POST", URL: path, Err: err}
+	}
+	defer resp.Body.Close() // nolint:errcheck
+	diag.Logf("POST %s -> %s", path, resp.Status)
+	rb, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return "", &StatusError{Method: "POST", URL: path, StatusCode: resp.StatusCode, Status: resp.Status, Body: errorBody(resp.Header.Get("Content-Type"), rb)}
+	}
+	if loc := resp.Header.Get("Location"); loc != "" {
+		return loc, nil
+	}
+	var task struct {
+		ODataID string `json:"@odata.id"`
+	}
+	if json.Unmarshal(bytes.TrimPrefix(rb, utf8BOM), &task) == nil && strings.Contains(task.ODataID, "/TaskService/Tasks/") {
+		return task.ODataID, nil
+	}
+	return "", nil
+}
+
+func (c *client) patch(ctx context.Context, path string, body any) error {
+	path = c.resolvePath(path)
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	diag.Logf("PATCH %s", path)
+	req, err := http.NewRequestWithContext(ctx, "PATCH", path, strings.NewReader(string(b)))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.user, c.pass)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.do(req)
+	if err != nil {
+		return &TransportError{Method: "PATCH", URL: path, Err: err}
+	}
+	defer resp.Body.Close() // nolint:errcheck
+	diag.Logf("PATCH %s -> %s", path, resp.Status)
+	if resp.StatusCode >= 300 {
+		rb, _ := io.ReadAll(resp.Body)
+		return &StatusError{Method: "PATCH", URL: path, StatusCode: resp.StatusCode, Status: resp.Status, Body: errorBody(resp.Header.Get("Content-Type"), rb)}
+	}
+	return nil
+}
+
+func (c *client) delete(ctx context.Context, path string) error {
+	path = c.resolvePath(path)
+	diag.Logf("DELETE %s", path)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", path, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.user, c.pass)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.do(req)
+	if err != nil {
+		return &TransportError{Method: "DELETE", URL: path, Err: err}
+	}
+	defer resp.Body.Close() // nolint:errcheck
+	diag.Logf("DELETE %s -> %s", path, resp.Status)
+	if resp.StatusCode >= 300 {
+		rb, _ := io.ReadAll(resp.Body)
+		return &StatusError{Method: "DELETE", URL: path, StatusCode: resp.StatusCode, Status: resp.Status, Body: errorBody(resp.Header.Get("Content-Type"), rb)}
+	}
+	return nil
+}
+
+func (c *client) firstSystemPath(ctx context.Context) (string, error) {
+	var coll rfCollection
+	if err := c.get(ctx, "/Systems", &coll); err != nil {
+		return "", err
+	}
+	if len(coll.Members) == 0 {
+		return "", errors.New("no systems reported by BMC")
+	}
+	return coll.Members[0].OID, nil
+}
+
+func (c *client) listSystemPaths(ctx context.Context) ([]string, error) {
+	var coll rfCollection
+	if err := c.get(ctx, "/Systems", &coll); err != nil {
+		return nil, err
+	}
+	if len(coll.Members) == 0 {
+		return nil, errors.New("no systems reported by BMC")
+	}
+	paths := make([]string, len(coll.Members))
+	for i, member := range coll.Members {
+		paths[i] = member.OID
+	}
+	return paths, nil
+}
+
+// nicFetchLimit bounds the GETs listEthernetInterfaces and GetFirmwareInventories
+// have in flight; BMCs serve few requests at once and some reset connections
+// beyond that.
+const nicFetchLimit = 4
+
+// listEthernetInterfaces fetches the interfaces under sysPath, up to
+// nicFetchLimit at a time, in collection order.
+func (c *client) listEthernetInterfaces(ctx context.Context, sysPath string) ([]rfEthernetInterface, error) {
+	var coll rfCollection
+	if err := c.get(ctx, sysPath+"/EthernetInterfaces", &coll); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	out := make([]rfEthernetInterface, len(coll.Members))
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, nicFetchLimit)
+	for i, m := range coll.Members {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			if err := c.get(ctx, m.OID, &out[i]); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+					cancel() // the others' results are not needed
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func isBootable(n rfEthernetInterface) bool {
+	uefi := strings.ToLower(n.UefiDevicePath)
+	if strings.Contains(uefi, "pxe") || strings.Contains(uefi, "ipv4") || strings.Contains(uefi, "ipv6") || strings.Contains(uefi, "mac(") {
+		return true
+	}
+	for _, a := range n.IPv4Addresses {
+		if strings.EqualFold(a.Origin, "dhcp") {
+			return true
+		}
+	}
+	if n.MACAddress != "" && (n.InterfaceEnabled == nil || *n.InterfaceEnabled) {
+		return true
+	}
+	return false
+}
+
+// isValidMAC checks if a MAC address string is valid
+func isValidMAC(mac string) bool {
+	if mac == "" || strings.EqualFold(mac, "Not Available") {
+		return false
+	}
+	// Basic MAC address format validation (handles both : and - separators)
+	// Valid formats: xx:xx:xx:xx:xx:xx or xx-xx-xx-xx-xx-xx
+	parts := strings.FieldsFunc(mac, func(r rune) bool {
+		return r == ':' || r == '-'
+	})
+	if len(parts) != 6 {
+		return false
+	}
+	for _, part := range parts {
+		if len(part) != 2 {
+			return false
+		}
+		for _, c := range part {
+			if (c < '0' || c > '9') && (c < 'a' || c > 'f') && (c < 'A' || c > 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// SystemMACs represents the bootable MAC addresses for a single system. Err is
+// set, and MACs empty, when the system's interfaces could not be read.
+type SystemMACs struct {
+	SystemPath string
+	MACs       []string
+	Err        error
+}
+
+// DiscoverAllBootableMACs returns bootable MAC addresses for all systems on a BMC.
+// Returns a slice of SystemMACs, one entry per system (e.g., Node0, Node1) in
+// the BMC's order, with the MACs of each ranked by prefer (nil keeps the BMC's
+// interface order). A system whose interfaces cannot be read is returned with
+// its Err set rather than left out, so callers can tell Node1 failed from a BMC
+// with one node; the error return is only for failing to list the systems.
+func (c *client) DiscoverAllBootableMACs(ctx context.Context, prefer NICPreference) ([]SystemMACs, error) {
+	sysPaths, err := c.listSystemPaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]SystemMACs, 0, len(sysPaths))
+	for _, sysPath := range sysPaths {
+		nics, err := c.listEthernetInterfaces(ctx, sysPath)
+		if err != nil {
+			result = append(result, SystemMACs{SystemPath: sysPath, Err: err})
+			continue
+		}
+		result = append(result, SystemMACs{
+			SystemPath: sysPath,
+			MACs:       bootableMACs(nics, prefer),
+		})
+	}
+	return result, nil
+}
+
+// SystemNIC is a network interface of a system with a valid MAC address.
+type SystemNIC struct {
+	SystemPath  string
+	SystemIndex int // node number of the system, see NodeNumbers
+	ID          string
+	Name        string
+	Description string
+	MAC         string
+	Enabled     *bool  // InterfaceEnabled; nil when not reported
+	LinkStatus  string // LinkUp, LinkDown or NoLink; "" when not reported
+	SpeedMbps   int    // 0 when not reported
+	VLAN        int    // VLAN ID when a VLAN is enabled, else 0
+	HostName    string
+	FQDN        string
+}
+
+// DiscoverSystemNICs returns every interface with a valid MAC on every system of
+// a BMC, in system order. Interfaces whose MAC is not available yet (e.g. HSN
+// NICs before the node has booted) are left out.
+func (c *client) DiscoverSystemNICs(ctx context.Context) ([]SystemNIC, error) {
+	sysPaths, err := c.listSystemPaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nodes := NodeNumbers(sysPaths)
+	var out []SystemNIC
+	for i, sysPath := range sysPaths {
+		nics, err := c.listEthernetInterfaces(ctx, sysPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, nic := range nics {
+			if !isValidMAC(nic.MACAddress) {
+				continue
+			}
+			n := SystemNIC{
+				SystemPath: sysPath, SystemIndex: nodes[i],
+				ID: nic.ID, Name: nic.Name, Description: nic.Description, MAC: strings.ToLower(nic.MACAddress),
+				Enabled: nic.InterfaceEnabled, LinkStatus: nic.LinkStatus,
+				HostName: nic.HostName, FQDN: nic.FQDN,
+			}
+			if nic.SpeedMbps != nil {
+				n.SpeedMbps = *nic.SpeedMbps
+			}
+			if nic.VLAN.VLANEnable != nil && *nic.VLAN.VLANEnable {
+				n.VLAN = nic.VLAN.VLANID
+			}
+			out = append(out, n)
+		}
+	}
+	return out, nil
+}
+
+// ManagerNIC is a network interface of a BMC (manager).
+type ManagerNIC struct {
+	ManagerPath string
+	ID          string
+	MAC         string // PermanentMACAddress when reported, else MACAddress
+	IPv4        []string
+}
+
+// DiscoverManagerNICs returns the interfaces of every manager on a BMC that have
+// a valid MAC address. The burned-in PermanentMACAddress is preferred over the
+// current MACAddress, which some BMCs let administrators override.
+func (c *client) DiscoverManagerNICs(ctx context.Context) ([]ManagerNIC, error) {
+	var coll rfCollection
+	if err := c.get(ctx, "/Managers", &coll); err != nil {
+		return nil, err
+	}
+	var out []ManagerNIC
+	for _, m := range coll.Members {
+		nics, err := c.listEthernetInterfaces(ctx, m.OID)
+		if err != nil {
+			return nil, err
+		}
+		for _, nic := range nics {
+			mac := nic.PermanentMAC
+			if !isValidMAC(mac) {
+				mac = nic.MACAddress
+			}
+			if !isValidMAC(mac) {
+				continue
+			}
+			n := ManagerNIC{ManagerPath: m.OID, ID: nic.ID, MAC: strings.ToLower(mac)}
+			for _, a := range nic.IPv4Addresses {
+				if a.Address != "" {
+					n.IPv4 = append(n.IPv4, a.Address)
+				}
+			}
+			out = append(out, n)
+		}
+	}
+	return out, nil
+}
+
+// DiscoverBootableMACs returns MAC addresses of bootable NICs for the first system on a BMC.
+// Deprecated: Use DiscoverAllBootableMACs to discover all systems on a BMC.
+func (c *client) DiscoverBootableMACs(ctx context.Context) ([]string, error) {
+	sysPath, err := c.firstSystemPath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nics, err := c.listEthernetInterfaces(ctx, sysPath)
+	if err != nil {
+		return nil, err
+	}
+	// collect bootable, fallback to first valid MAC if none
+	macs := make([]string, 0, len(nics))
+	for _, nic := range nics {
+		if !isValidMAC(nic.MACAddress) {
+			continue
+		}
+		if isBootable(nic) {
+			macs = append(macs, strings.ToLower(nic.MACAddress))
+		}
+	}
+	if len(macs) == 0 {
+		for _, nic := range nics {
+			if isValidMAC(nic.MACAddress) {
+				macs = append(macs, strings.ToLower(nic.MACAddress))
+				break
+			}
+		}
+	}
+	return macs, nil
+}
+
+// UpdateResult describes what SimpleUpdate posted.
+type UpdateResult struct {
+	TaskURI string   // task monitor of the accepted update, if the BMC returned one
+	Updated []string // targets included in the SimpleUpdate
+	Skipped []string // targets left out because they already run the expected version
+}
+
+// SimpleUpdate triggers a Redfish SimpleUpdate action on the given targets.
+// imageURI is a URL accessible by the BMC (e.g., http/https), targets are the FirmwareInventory targets.
+// transferProtocol is typically "HTTP" or "HTTPS".
+// If expectedVersion is provided and force is false, targets already at that version
+// are left out of the update; when none remain the update is skipped with an error
+// matching ErrAlreadyAtVersion. Conditions the targets report after the update
+// are returned as an *UpdateWarningsError.
+func (c *client) SimpleUpdate(ctx context.Context, imageURI string, targets []string, transferProtocol string, expectedVersion string, force bool) (UpdateResult, error) {
+	var res UpdateResult
+
+	// Filter out targets already at expectedVersion unless forcing
+	if expectedVersion != "" && !force {
+		var versionInfo []string
+		for _, target := range targets {
+			var fw rfFirmwareInventory
+			if err := c.get(ctx, target, &fw); err != nil || fw.Version != expectedVersion {
+				// If we can't get version, proceed with update
+				res.Updated = append(res.Updated, target)
+				continue
+			}
+			res.Skipped = append(res.Skipped, target)
+			versionInfo = append(versionInfo, fmt.Sprintf("%s: %s", target, fw.Version))
+		}
+		if len(res.Updated) == 0 && len(targets) > 0 {
+			return res, fmt.Errorf("%w %s\n%s", ErrAlreadyAtVersion, expectedVersion, strings.Join(versionInfo, "\n"))
+		}
+		targets = res.Updated
+	} else {
+		res.Updated = targets
+	}
+
+	payload := map[string]any{
+		"ImageURI":         imageURI,
+		"TransferProtocol": transferProtocol,
+		"Targets":          targets,
+	}
+	// Vendor path per provided examples
+	task, err := c.postAction(ctx, "/UpdateService/Actions/SimpleUpdate", payload)
+	if err != nil {
+		return res, err
+	}
+	res.TaskURI = task
+
+	// Check firmware inventory status for any conditions/errors
+	// Wait a moment for the status to update
+	time.Sleep(2 * time.Second)
+
+	var statusErrors []string
+	for _, target := range targets {
+		var fw rfFirmwareInventory
+		if err := c.get(ctx, target, &fw); err != nil {
+			// If we can't get status, just skip it (don't fail the whole operation)
+			continue
+		}
+
+		// Check for warning or critical conditions
+		for _, cond := range fw.Status.Conditions {
+			if cond.Severity == "Warning" || cond.Severity == "Critical" {
+				statusErrors = append(statusErrors, fmt.Sprintf("[%s] %s: %s", target, cond.Severity, cond.Message))
+			}
+		}
+	}
+
+	if len(statusErrors) > 0 {
+		return res, &UpdateWarningsError{Conditions: statusErrors}
+	}
+
+	return res, nil
+}
+
+// SetAuthorizedKeys configures the SSH authorized keys on a BMC.
+// The Redfish path used is /Managers/BMC/NetworkProtocol with an OEM payload.
+func (c *client) SetAuthorizedKeys(ctx context.Context, authorizedKey string) error {
+	payload := map[string]any{
+		"Oem": map[string]any{
+			"SSHAdmin": map[string]any{
+				"AuthorizedKeys": authorizedKey,
+			},
+		},
+	}
+	return c.patch(ctx, "/Managers/BMC/NetworkProtocol", payload)
+}
+
+type rfNetworkProtocol struct {
+	Oem struct {
+		// SSHAdmin is an HPE OEM extension; nil when the BMC does not expose it.
+		SSHAdmin *struct {
+			AuthorizedKeys string `json:"AuthorizedKeys"`
+		} `json:"SSHAdmin"`
+	} `json:"Oem"`
+}
+
+type rfAccount struct {
+	UserName string `json:"UserName"`
+	RoleID   string `json:"RoleId"`
+	Enabled  *bool  `json:"Enabled"`
+	Keys     struct {
+		OID string `json:"@odata.id"`
+	} `json:"Keys"`
+}
+
+type rfKey struct {
+	KeyString string `json:"KeyString"`
+	KeyType   string `json:"KeyType"`
+}
+
+// GetAuthorizedKeys reads the SSH authorized keys currently configured on a BMC.
+// It prefers the OEM SSHAdmin property SetAuthorizedKeys writes, and falls back to
+// the standard ManagerAccount Keys collection of the account matching user for
+// BMCs without that extension. Keys are returned one per line.
+func (c *client) GetAuthorizedKeys(ctx context.Context) (string, error) {
+	var np rfNetworkProtocol
+	oemErr := c.get(ctx, "/Managers/BMC/NetworkProtocol", &np)
+	if oemErr == nil && np.Oem.SSHAdmin != nil {
+		return np.Oem.SSHAdmin.AuthorizedKeys, nil
+	}
+	keys, err := c.accountKeys(ctx)
+	if err != nil {
+		if oemErr != nil {
+			return "", oemErr
+		}
+		return "", fmt.Errorf("no SSH authorized keys exposed by BMC: %w", err)
+	}
+	if len(keys) == 0 {
+		return "", nil
+	}
+	return strings.Join(keys, "\n") + "\n", nil
+}
+
+// accountKeys returns the SSH keys of the account logged in as c.user using the
+// standard AccountService Keys collection.
+func (c *client) accountKeys(ctx context.Context) ([]string, error) {
+	var coll rfCollection
+	if err := c.get(ctx, "/AccountService/Accounts", &coll); err != nil {
+		return nil, err
+	}
+	for _, m := range coll.Members {
+		var acct rfAccount
+		if err := c.get(ctx, m.OID, &acct); err != nil {
+			continue
+		}
+		if acct.UserName != c.user {
+			continue
+		}
+		if acct.Keys.OID == "" {
+			return nil, errors.New("account has no Keys collection")
+		}
+		var keys rfCollection
+		if err := c.get(ctx, acct.Keys.OID, &keys); err != nil {
+			return nil, err
+		}
+		var out []string
+		for _, km := range keys.Members {
+			var k rfKey
+			if err := c.get(ctx, km.OID, &k); err != nil {
+				return nil, err
+			}
+			if k.KeyType == "" || strings.EqualFold(k.KeyType, "SSH") {
+				out = append(out, strings.TrimSpace(k.KeyString))
+			}
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("account %s not found", c.user)
+}
+
+func (c *client) resolvePath(path string) string {
+	// If it's already an absolute URL, return as-is
+	if strings.HasPrefix(path, "http") {
+		return path
+	}
+	// If it already has the base prefix, return as-is
+	if strings.HasPrefix(path, c.base) {
+		return path
+	}
+	// Behind an aggregator, paths it already prefixed only need its scheme+host,
+	// and plain Redfish paths are moved under the prefix
+	if c.prefix != "" {
+		if strings.HasPrefix(path, c.prefix) {
+			return strings.TrimSuffix(c.base, c.prefix) + path
+		}
+		if strings.HasPrefix(path, "/redfish/v1") {
+			return c.base + strings.TrimPrefix(path, "/redfish/v1")
+		}
+	}
+	// If it starts with /redfish/v1, it's an absolute Redfish path, so just prepend the scheme+host
+	if strings.HasPrefix(path, "/redfish/v1") {
+		// Extract the scheme+host from c.base
+		baseURL := c.base[:strings.Index(c.base, "/redfish/v1")]
+		return baseURL + path
+	}
+	// Otherwise, it's a relative path, so append to base
+	if strings.HasPrefix(path, "/") {
+		return c.base + path
+	}
+	return c.base + "/" + path
+}
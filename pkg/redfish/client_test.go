@@ -6,8 +6,17 @@ package redfish
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -233,10 +242,55 @@ func TestResolvePath(t *testing.T) {
 	}
 }
 
+func TestResolvePathAggregator(t *testing.T) {
+	c := &client{base: "https://agg:8443/bmc/x1000c0s0b0", prefix: "/bmc/x1000c0s0b0"}
+	cases := map[string]string{
+		"/Systems": "https://agg:8443/bmc/x1000c0s0b0/Systems",
+		"/redfish/v1/UpdateService/FirmwareInventory/BMC": "https://agg:8443/bmc/x1000c0s0b0/UpdateService/FirmwareInventory/BMC",
+		"/bmc/x1000c0s0b0/Systems/Node0":                  "https://agg:8443/bmc/x1000c0s0b0/Systems/Node0",
+	}
+	for path, want := range cases {
+		if got := c.resolvePath(path); got != want {
+			t.Errorf("resolvePath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestAggregatorClient(t *testing.T) {
+	docs := map[string]string{
+		"/redfish/v1/Aggregate/x1000c0s0b0/Managers":     `{"Members":[{"@odata.id":"/redfish/v1/Aggregate/x1000c0s0b0/Managers/BMC"}]}`,
+		"/redfish/v1/Aggregate/x1000c0s0b0/Managers/BMC": `{"Id":"BMC","FirmwareVersion":"nc.1.9"}`,
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := docs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	SetAggregator(&Aggregator{Host: server.URL[len("https://"):], Template: "/redfish/v1/Aggregate/{host}"})
+	defer SetAggregator(nil)
+
+	mgrs, err := New("x1000c0s0b0", "user", "pass", true, 5*time.Second).GetManagers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mgrs) != 1 || mgrs[0].FirmwareVersion != "nc.1.9" {
+		t.Errorf("managers = %+v", mgrs)
+	}
+}
+
 func TestDiscoverBootableMACs(t *testing.T) {
-	var gotPaths []string
+	var (
+		mu       sync.Mutex
+		gotPaths []string
+	)
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
 		gotPaths = append(gotPaths, r.URL.Path)
+		mu.Unlock()
 		w.Header().Set("Content-Type", "application/json")
 		// Return mock Redfish responses
 		switch r.URL.Path {
@@ -315,6 +369,7 @@ func TestDiscoverBootableMACs(t *testing.T) {
 		"/redfish/v1/Systems/Self/EthernetInterfaces/1",
 		"/redfish/v1/Systems/Self/EthernetInterfaces/2",
 	}
+	slices.Sort(gotPaths) // interfaces are fetched concurrently
 	if len(gotPaths) != len(expectedPaths) {
 		t.Errorf("got %d requests, want %d", len(gotPaths), len(expectedPaths))
 	}
@@ -429,10 +484,50 @@ func TestDiscoverAllBootableMACs_MultipleSystems(t *testing.T) {
 	}
 }
 
+func TestDiscoverAllBootableMACs_SystemError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/redfish/v1/Systems":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Systems/Node0"},{"@odata.id":"/redfish/v1/Systems/Node1"}]}`))
+		case "/redfish/v1/Systems/Node0/EthernetInterfaces":
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		case "/redfish/v1/Systems/Node1/EthernetInterfaces":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Systems/Node1/EthernetInterfaces/1"}]}`))
+		case "/redfish/v1/Systems/Node1/EthernetInterfaces/1":
+			_, _ = w.Write([]byte(`{"Id":"1","MACAddress":"aa:bb:cc:dd:ee:02"}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer ts.Close()
+	c := newClient("example.com", "admin", "password", true, 0)
+	c.base = ts.URL + "/redfish/v1"
+
+	systems, err := c.DiscoverAllBootableMACs(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(systems) != 2 {
+		t.Fatalf("got %+v, want both systems", systems)
+	}
+	if systems[0].SystemPath != "/redfish/v1/Systems/Node0" || systems[0].Err == nil || len(systems[0].MACs) != 0 {
+		t.Errorf("Node0 = %+v, want its error", systems[0])
+	}
+	if systems[1].Err != nil || len(systems[1].MACs) != 1 || systems[1].MACs[0] != "aa:bb:cc:dd:ee:02" {
+		t.Errorf("Node1 = %+v", systems[1])
+	}
+}
+
 func TestDiscoverBootableMACs_WithInvalidMACs(t *testing.T) {
-	var gotPaths []string
+	var (
+		mu       sync.Mutex
+		gotPaths []string
+	)
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
 		gotPaths = append(gotPaths, r.URL.Path)
+		mu.Unlock()
 		w.Header().Set("Content-Type", "application/json")
 		// Simulate HPE Cray system with "Not Available" MACs
 		switch r.URL.Path {
@@ -523,6 +618,7 @@ func TestDiscoverBootableMACs_WithInvalidMACs(t *testing.T) {
 		"/redfish/v1/Systems/Node0/EthernetInterfaces/HPCNet3",
 		"/redfish/v1/Systems/Node0/EthernetInterfaces/ManagementEthernet",
 	}
+	slices.Sort(gotPaths) // interfaces are fetched concurrently
 	if len(gotPaths) != len(expectedPaths) {
 		t.Errorf("got %d requests, want %d", len(gotPaths), len(expectedPaths))
 	}
@@ -568,12 +664,16 @@ func TestSimpleUpdate_WithStatusConditions(t *testing.T) {
 
 	ctx := context.Background()
 	host := server.URL[len("https://"):]
-	err := SimpleUpdate(ctx, host, "user", "pass", true, 10*time.Second, "http://example.com/firmware.bin",
+	_, err := SimpleUpdate(ctx, host, "user", "pass", true, 10*time.Second, "http://example.com/firmware.bin",
 		[]string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}, "HTTP", "", false)
 
 	if err == nil {
 		t.Fatal("expected error due to status condition, got nil")
 	}
+	var warn *UpdateWarningsError
+	if !errors.As(err, &warn) {
+		t.Errorf("expected *UpdateWarningsError, got %T", err)
+	}
 	if !contains(err.Error(), "failed to download") && !contains(err.Error(), "Failed to connect") {
 		t.Errorf("expected error message about download failure, got: %v", err)
 	}
@@ -613,20 +713,58 @@ func TestSimpleUpdate_SkipWhenAlreadyAtVersion(t *testing.T) {
 	host := server.URL[len("https://"):]
 
 	// Should skip update when already at expected version
-	err := SimpleUpdate(ctx, host, "user", "pass", true, 10*time.Second, "http://example.com/firmware.bin",
+	_, err := SimpleUpdate(ctx, host, "user", "pass", true, 10*time.Second, "http://example.com/firmware.bin",
 		[]string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}, "HTTP", "nc.1.9.8", false)
 
 	if err == nil {
 		t.Fatal("expected error indicating skipped update, got nil")
 	}
-	if !contains(err.Error(), "skipping update") {
-		t.Errorf("expected 'skipping update' message, got: %v", err)
+	if !errors.Is(err, ErrAlreadyAtVersion) || !contains(err.Error(), "skipping update") {
+		t.Errorf("expected ErrAlreadyAtVersion, got: %v", err)
 	}
 	if !contains(err.Error(), "nc.1.9.8") {
 		t.Errorf("expected version in message, got: %v", err)
 	}
 }
 
+func TestSimpleUpdate_SkipsCurrentTargetsOnly(t *testing.T) {
+	versions := map[string]string{
+		"/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS": "bios.2.0",
+		"/redfish/v1/UpdateService/FirmwareInventory/Node1.BIOS": "bios.1.0",
+	}
+	var posted []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if v, ok := versions[r.URL.Path]; ok && r.Method == "GET" {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"Version": v})
+			return
+		}
+		if r.Method == "POST" && r.URL.Path == "/redfish/v1/UpdateService/Actions/SimpleUpdate" {
+			var body struct{ Targets []string }
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			posted = body.Targets
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	host := server.URL[len("https://"):]
+	res, err := SimpleUpdate(context.Background(), host, "user", "pass", true, 10*time.Second, "http://example.com/bios.cap",
+		[]string{"/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS", "/redfish/v1/UpdateService/FirmwareInventory/Node1.BIOS"}, "HTTP", "bios.2.0", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stale := []string{"/redfish/v1/UpdateService/FirmwareInventory/Node1.BIOS"}
+	if !reflect.DeepEqual(posted, stale) || !reflect.DeepEqual(res.Updated, stale) {
+		t.Errorf("posted %v, result updated %v; want only %v", posted, res.Updated, stale)
+	}
+	if len(res.Skipped) != 1 || res.Skipped[0] != "/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS" {
+		t.Errorf("skipped = %v, want Node0.BIOS", res.Skipped)
+	}
+}
+
 func TestSimpleUpdate_ForceWhenAlreadyAtVersion(t *testing.T) {
 	postCalled := false
 	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -655,7 +793,7 @@ func TestSimpleUpdate_ForceWhenAlreadyAtVersion(t *testing.T) {
 	host := server.URL[len("https://"):]
 
 	// Should force update even when already at expected version
-	err := SimpleUpdate(ctx, host, "user", "pass", true, 10*time.Second, "http://example.com/firmware.bin",
+	_, err := SimpleUpdate(ctx, host, "user", "pass", true, 10*time.Second, "http://example.com/firmware.bin",
 		[]string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}, "HTTP", "nc.1.9.8", true)
 
 	if err != nil {
@@ -683,6 +821,7 @@ func TestSimpleUpdate_UpdateWhenDifferentVersion(t *testing.T) {
 		}
 		if r.Method == "POST" && r.URL.Path == "/redfish/v1/UpdateService/Actions/SimpleUpdate" {
 			postCalled = true
+			w.Header().Set("Location", "/redfish/v1/TaskService/Tasks/7")
 			w.WriteHeader(http.StatusAccepted)
 			return
 		}
@@ -694,13 +833,379 @@ func TestSimpleUpdate_UpdateWhenDifferentVersion(t *testing.T) {
 	host := server.URL[len("https://"):]
 
 	// Should proceed with update when version differs
-	err := SimpleUpdate(ctx, host, "user", "pass", true, 10*time.Second, "http://example.com/firmware.bin",
+	task, err := SimpleUpdate(ctx, host, "user", "pass", true, 10*time.Second, "http://example.com/firmware.bin",
 		[]string{"/redfish/v1/UpdateService/FirmwareInventory/BMC"}, "HTTP", "nc.1.9.8", false)
 
 	if err != nil {
 		t.Fatalf("expected no error when updating to different version, got: %v", err)
 	}
+	if task.TaskURI != "/redfish/v1/TaskService/Tasks/7" {
+		t.Errorf("task URI = %q, want the Location header", task)
+	}
 	if !postCalled {
 		t.Error("expected SimpleUpdate POST to be called when version differs")
 	}
 }
+
+func TestGetAuthorizedKeys(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses map[string]string
+		want      string
+	}{
+		{
+			name: "OEM SSHAdmin",
+			responses: map[string]string{
+				"/redfish/v1/Managers/BMC/NetworkProtocol": `{"Oem":{"SSHAdmin":{"AuthorizedKeys":"ssh-rsa AAAA admin\n"}}}`,
+			},
+			want: "ssh-rsa AAAA admin\n",
+		},
+		{
+			name: "Standard account keys",
+			responses: map[string]string{
+				"/redfish/v1/Managers/BMC/NetworkProtocol":     `{"SSH":{"ProtocolEnabled":true}}`,
+				"/redfish/v1/AccountService/Accounts":          `{"Members":[{"@odata.id":"/redfish/v1/AccountService/Accounts/1"},{"@odata.id":"/redfish/v1/AccountService/Accounts/2"}]}`,
+				"/redfish/v1/AccountService/Accounts/1":        `{"UserName":"root"}`,
+				"/redfish/v1/AccountService/Accounts/2":        `{"UserName":"admin","Keys":{"@odata.id":"/redfish/v1/AccountService/Accounts/2/Keys"}}`,
+				"/redfish/v1/AccountService/Accounts/2/Keys":   `{"Members":[{"@odata.id":"/redfish/v1/AccountService/Accounts/2/Keys/1"}]}`,
+				"/redfish/v1/AccountService/Accounts/2/Keys/1": `{"KeyType":"SSH","KeyString":"ssh-ed25519 BBBB ops"}`,
+			},
+			want: "ssh-ed25519 BBBB ops\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, ok := tt.responses[r.URL.Path]
+				if !ok {
+					http.NotFound(w, r)
+					return
+				}
+				_, _ = w.Write([]byte(body))
+			}))
+			defer ts.Close()
+
+			host := strings.TrimPrefix(ts.URL, "https://")
+			got, err := GetAuthorizedKeys(context.Background(), host, "admin", "password", true, 2*time.Second)
+			if err != nil {
+				t.Fatalf("GetAuthorizedKeys failed: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("GetAuthorizedKeys = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverManagerNICsPrefersPermanentMAC(t *testing.T) {
+	docs := map[string]string{
+		"/redfish/v1/Managers":                             `{"Members":[{"@odata.id":"/redfish/v1/Managers/BMC"}]}`,
+		"/redfish/v1/Managers/BMC/EthernetInterfaces":      `{"Members":[{"@odata.id":"/redfish/v1/Managers/BMC/EthernetInterfaces/eth0"},{"@odata.id":"/redfish/v1/Managers/BMC/EthernetInterfaces/x"}]}`,
+		"/redfish/v1/Managers/BMC/EthernetInterfaces/eth0": `{"Id":"eth0","MACAddress":"02:00:00:00:00:AA","PermanentMACAddress":"00:40:A6:00:00:01","IPv4Addresses":[{"Address":"10.1.0.2"}]}`,
+		"/redfish/v1/Managers/BMC/EthernetInterfaces/x":    `{"Id":"x","MACAddress":""}`,
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := docs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	nics, err := DiscoverManagerNICs(context.Background(), server.URL[len("https://"):], "user", "pass", true, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ManagerNIC{{ManagerPath: "/redfish/v1/Managers/BMC", ID: "eth0", MAC: "00:40:a6:00:00:01", IPv4: []string{"10.1.0.2"}}}
+	if !reflect.DeepEqual(nics, want) {
+		t.Errorf("got %+v, want %+v", nics, want)
+	}
+}
+
+func TestDiscoverSystemNICsLinkFields(t *testing.T) {
+	docs := map[string]string{
+		"/redfish/v1/Systems":                          `{"Members":[{"@odata.id":"/redfish/v1/Systems/Node0"},{"@odata.id":"/redfish/v1/Systems/Node1"}]}`,
+		"/redfish/v1/Systems/Node0/EthernetInterfaces": `{"Members":[]}`,
+		"/redfish/v1/Systems/Node1/EthernetInterfaces": `{"Members":[{"@odata.id":"/redfish/v1/Systems/Node1/EthernetInterfaces/eth0"}]}`,
+		"/redfish/v1/Systems/Node1/EthernetInterfaces/eth0": `{"Id":"eth0","MACAddress":"AA:00:00:00:00:01","InterfaceEnabled":true,"LinkStatus":"LinkDown",
+			"SpeedMbps":25000,"HostName":"nid000002","FQDN":"nid000002.local","VLAN":{"VLANEnable":true,"VLANId":2001}}`,
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := docs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	nics, err := DiscoverSystemNICs(context.Background(), server.URL[len("https://"):], "user", "pass", true, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enabled := true
+	want := []SystemNIC{{
+		SystemPath: "/redfish/v1/Systems/Node1", SystemIndex: 1, ID: "eth0", MAC: "aa:00:00:00:00:01",
+		Enabled: &enabled, LinkStatus: "LinkDown", SpeedMbps: 25000, VLAN: 2001, HostName: "nid000002", FQDN: "nid000002.local",
+	}}
+	if !reflect.DeepEqual(nics, want) {
+		t.Errorf("got %+v, want %+v", nics, want)
+	}
+}
+
+func TestGetNetworkProtocolAndListFirmware(t *testing.T) {
+	docs := map[string]string{
+		"/redfish/v1/Managers/BMC/NetworkProtocol":               `{"HostName":"x1000c0s0b0","SSH":{"ProtocolEnabled":true,"Port":22},"IPMI":{"ProtocolEnabled":false,"Port":623},"NTP":{"ProtocolEnabled":true,"NTPServers":["10.0.0.1"]}}`,
+		"/redfish/v1/UpdateService/FirmwareInventory":            `{"Members":[{"@odata.id":"/redfish/v1/UpdateService/FirmwareInventory/BMC"},{"@odata.id":"/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS"}]}`,
+		"/redfish/v1/UpdateService/FirmwareInventory/BMC":        `{"Version":"nc.1.9.8"}`,
+		"/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS": `{"Version":"bios.2.0"}`,
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := docs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	host := server.URL[len("https://"):]
+
+	np, err := GetNetworkProtocol(context.Background(), host, "user", "pass", true, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if np.HostName != "x1000c0s0b0" || !reflect.DeepEqual(np.NTPServers, []string{"10.0.0.1"}) {
+		t.Errorf("got %+v", np)
+	}
+	if ssh := np.Protocols["SSH"]; ssh.Enabled == nil || !*ssh.Enabled || ssh.Port != 22 {
+		t.Errorf("SSH = %+v", ssh)
+	}
+	if ipmi := np.Protocols["IPMI"]; ipmi.Enabled == nil || *ipmi.Enabled {
+		t.Errorf("IPMI = %+v", ipmi)
+	}
+
+	fw, err := ListFirmware(context.Background(), host, "user", "pass", true, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []FirmwareVersion{
+		{Path: "/redfish/v1/UpdateService/FirmwareInventory/BMC", Version: "nc.1.9.8"},
+		{Path: "/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS", Version: "bios.2.0"},
+	}
+	if !reflect.DeepEqual(fw, want) {
+		t.Errorf("got %+v, want %+v", fw, want)
+	}
+}
+
+// nicServer serves a system with n interfaces, answering each interface GET
+// after delay (a BMC's round trip) and failing interface fail, if any.
+func nicServer(n int, delay time.Duration, fail string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const coll = "/redfish/v1/Systems/Node0/EthernetInterfaces"
+		if r.URL.Path == coll {
+			var members []string
+			for i := range n {
+				members = append(members, fmt.Sprintf(`{"@odata.id":"%s/%d"}`, coll, i))
+			}
+			_, _ = w.Write([]byte(`{"Members":[` + strings.Join(members, ",") + `]}`))
+			return
+		}
+		id := strings.TrimPrefix(r.URL.Path, coll+"/")
+		time.Sleep(delay)
+		if id == fail {
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"Id":"%s","MACAddress":"aa:bb:cc:dd:ee:%02s"}`, id, id)
+	}))
+}
+
+func TestListFirmwareInventory(t *testing.T) {
+	docs := map[string]string{
+		"/redfish/v1/UpdateService/FirmwareInventory":       `{"Members":[{"@odata.id":"/redfish/v1/UpdateService/FirmwareInventory/iDRAC"},{"@odata.id":"/redfish/v1/UpdateService/FirmwareInventory/CPLD"}]}`,
+		"/redfish/v1/UpdateService/FirmwareInventory/iDRAC": `{"Id":"iDRAC","Name":"Integrated Remote Access Controller","Version":"7.00.00.171","SoftwareId":"25227","Updateable":true,"Status":{"State":"Enabled","Health":"OK"}}`,
+		"/redfish/v1/UpdateService/FirmwareInventory/CPLD":  `{"Id":"CPLD","Name":"System CPLD","Version":"1.0.6","Updateable":false,"RelatedItem":[{"@odata.id":"/redfish/v1/Systems/System.Embedded.1"}]}`,
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := docs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	host := server.URL[len("https://"):]
+
+	got, err := ListFirmwareInventory(context.Background(), host, "user", "pass", true, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []FirmwareComponent{
+		{Path: "/redfish/v1/UpdateService/FirmwareInventory/iDRAC", ID: "iDRAC", Name: "Integrated Remote Access Controller", Version: "7.00.00.171", SoftwareID: "25227", Updateable: true, State: "Enabled", Health: "OK"},
+		{Path: "/redfish/v1/UpdateService/FirmwareInventory/CPLD", ID: "CPLD", Name: "System CPLD", Version: "1.0.6", RelatedItems: []string{"/redfish/v1/Systems/System.Embedded.1"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestGetFirmwareInventories(t *testing.T) {
+	var mu sync.Mutex
+	conns := 0
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redfish/v1/UpdateService/FirmwareInventory/BMC":
+			_, _ = w.Write([]byte(`{"Version":"1.2.3","Status":{"State":"Enabled","Health":"OK"}}`))
+		case "/redfish/v1/UpdateService/FirmwareInventory/Node0.BIOS", "/redfish/v1/UpdateService/FirmwareInventory/Node1.BIOS":
+			_, _ = w.Write([]byte(`{"Version":"ex-1.0"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	server.Config.ConnState = func(_ net.Conn, st http.ConnState) {
+		if st == http.StateNew {
+			mu.Lock()
+			conns++
+			mu.Unlock()
+		}
+	}
+	server.StartTLS()
+	defer server.Close()
+	host := server.URL[len("https://"):]
+
+	const fw = "/redfish/v1/UpdateService/FirmwareInventory/"
+	targets := []string{fw + "BMC", fw + "Node0.BIOS", fw + "Node1.BIOS", fw + "CPLD", fw + "BMC"}
+	for _, concurrency := range []int{0, 1, 8} {
+		mu.Lock()
+		conns = 0
+		mu.Unlock()
+		got, err := GetFirmwareInventories(context.Background(), host, "user", "pass", true, 5*time.Second, targets, concurrency)
+		var te TargetErrors
+		if !errors.As(err, &te) || len(te) != 1 || te[fw+"CPLD"] == nil {
+			t.Fatalf("concurrency %d: err = %v, want a TargetErrors for CPLD only", concurrency, err)
+		}
+		if len(got) != 3 || got[fw+"BMC"].Version != "1.2.3" || got[fw+"Node1.BIOS"].Version != "ex-1.0" {
+			t.Errorf("concurrency %d: got %+v", concurrency, got)
+		}
+		mu.Lock()
+		if limit := min(max(1, concurrency), nicFetchLimit); conns > limit {
+			t.Errorf("concurrency %d: %d connections, want at most %d", concurrency, conns, limit)
+		}
+		mu.Unlock()
+	}
+}
+
+func TestSetHostName(t *testing.T) {
+	docs := map[string]string{
+		"/redfish/v1/Managers":                             `{"Members":[{"@odata.id":"/redfish/v1/Managers/BMC"}]}`,
+		"/redfish/v1/Managers/BMC/EthernetInterfaces":      `{"Members":[{"@odata.id":"/redfish/v1/Managers/BMC/EthernetInterfaces/eth0"},{"@odata.id":"/redfish/v1/Managers/BMC/EthernetInterfaces/usb0"}]}`,
+		"/redfish/v1/Managers/BMC/EthernetInterfaces/eth0": `{"Id":"eth0","IPv4Addresses":[{"Address":"10.1.0.5"}]}`,
+		"/redfish/v1/Managers/BMC/EthernetInterfaces/usb0": `{"Id":"usb0","IPv4Addresses":[]}`,
+	}
+	var mu sync.Mutex
+	patches := map[string]string{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			patches[r.URL.Path] = string(body)
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		body, ok := docs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	host := server.URL[len("https://"):]
+
+	if err := SetHostName(context.Background(), host, "user", "pass", true, 5*time.Second, "x1000c0s0b0", "x1000c0s0b0.hsm.local"); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"/redfish/v1/Managers/BMC/NetworkProtocol":         `{"HostName":"x1000c0s0b0"}`,
+		"/redfish/v1/Managers/BMC/EthernetInterfaces/eth0": `{"FQDN":"x1000c0s0b0.hsm.local","HostName":"x1000c0s0b0"}`,
+	}
+	for path, body := range want {
+		if got := strings.TrimSpace(patches[path]); got != body {
+			t.Errorf("PATCH %s = %s, want %s", path, got, body)
+		}
+	}
+	if len(patches) != len(want) {
+		t.Errorf("patched %v", patches)
+	}
+}
+
+func TestListEthernetInterfacesConcurrent(t *testing.T) {
+	ts := nicServer(9, 20*time.Millisecond, "")
+	defer ts.Close()
+	c := newClient("example.com", "admin", "password", true, 0)
+	c.base = ts.URL + "/redfish/v1"
+
+	start := time.Now()
+	nics, err := c.listEthernetInterfaces(context.Background(), "/redfish/v1/Systems/Node0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nics) != 9 {
+		t.Fatalf("got %d interfaces, want 9", len(nics))
+	}
+	for i, n := range nics {
+		if n.ID != fmt.Sprint(i) {
+			t.Errorf("interface %d is %s; want collection order", i, n.ID)
+		}
+	}
+	// 9 round trips, nicFetchLimit at a time, take 3 rounds rather than 9
+	if d := time.Since(start); d > 8*20*time.Millisecond {
+		t.Errorf("took %s; interfaces were not fetched concurrently", d)
+	}
+
+	ts = nicServer(9, time.Millisecond, "5")
+	defer ts.Close()
+	c.base = ts.URL + "/redfish/v1"
+	var se *StatusError
+	if _, err := c.listEthernetInterfaces(context.Background(), "/redfish/v1/Systems/Node0"); !errors.As(err, &se) || se.StatusCode != http.StatusInternalServerError {
+		t.Errorf("err = %v, want the failed interface's status", err)
+	}
+}
+
+// BenchmarkListEthernetInterfaces compares fetching the interfaces of a Cray
+// node card, with 1ms per round trip, one at a time and concurrently.
+func BenchmarkListEthernetInterfaces(b *testing.B) {
+	ts := nicServer(8, time.Millisecond, "")
+	defer ts.Close()
+	c := newClient("example.com", "admin", "password", true, 0)
+	c.base = ts.URL + "/redfish/v1"
+	ctx := context.Background()
+
+	b.Run("serial", func(b *testing.B) {
+		for b.Loop() {
+			var coll rfCollection
+			if err := c.get(ctx, "/redfish/v1/Systems/Node0/EthernetInterfaces", &coll); err != nil {
+				b.Fatal(err)
+			}
+			for _, m := range coll.Members {
+				var nic rfEthernetInterface
+				if err := c.get(ctx, m.OID, &nic); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("concurrent", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := c.listEthernetInterfaces(ctx, "/redfish/v1/Systems/Node0"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
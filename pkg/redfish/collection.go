@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"strings"
+)
+
+// queryFeatures are the query parameters a BMC advertises in the service root's
+// ProtocolFeaturesSupported. BMCs that do not list them may reject the query.
+type queryFeatures struct {
+	expand string // "$expand" value inlining collection members; empty when unsupported
+	sel    bool   // $select
+}
+
+type rfServiceRoot struct {
+	ProtocolFeaturesSupported struct {
+		ExpandQuery struct {
+			NoLinks bool `json:"NoLinks"`
+			Levels  bool `json:"Levels"`
+		} `json:"ExpandQuery"`
+		SelectQuery bool `json:"SelectQuery"`
+	} `json:"ProtocolFeaturesSupported"`
+}
+
+// features reads the service root once per client. A BMC whose root cannot be
+// read is treated as supporting neither query.
+func (c *client) features(ctx context.Context) queryFeatures {
+	c.featuresOnce.Do(func() {
+		var root rfServiceRoot
+		if err := c.get(ctx, "/redfish/v1", &root); err != nil {
+			return
+		}
+		pf := root.ProtocolFeaturesSupported
+		switch {
+		case pf.ExpandQuery.NoLinks && pf.ExpandQuery.Levels:
+			c.query.expand = ".($levels=1)"
+		case pf.ExpandQuery.NoLinks:
+			c.query.expand = "."
+		}
+		c.query.sel = pf.SelectQuery
+	})
+	return c.query
+}
+
+// rfPage is one page of a collection; members are either links or, expanded,
+// the resources themselves.
+type rfPage struct {
+	Members  []json.RawMessage `json:"Members"`
+	NextLink string            `json:"Members@odata.nextLink"`
+}
+
+// members iterates over the members of the collection at path, following
+// Members@odata.nextLink, and yields each member's body as it arrives.
+//
+// With fields and a BMC supporting $select, each member is fetched with only
+// those properties rendered. Otherwise a BMC supporting $expand returns the
+// members inline with the collection, so each is loaded once instead of as a
+// link and then a resource, and any other BMC is asked for each member. A member that
+// cannot be fetched is yielded with a *memberError and iteration may go on; any
+// other error is from reading the collection and ends it.
+func (c *client) members(ctx context.Context, path string, fields ...string) iter.Seq2[json.RawMessage, error] {
+	return func(yield func(json.RawMessage, error) bool) {
+		f := c.features(ctx)
+		var sel string
+		if len(fields) > 0 && f.sel {
+			sel = "?$select=" + strings.Join(fields, ",")
+		}
+		next := path
+		if f.expand != "" && sel == "" {
+			next += "?$expand=" + f.expand
+		}
+		for next != "" {
+			var page rfPage
+			if err := c.get(ctx, next, &page); err != nil {
+				yield(nil, err)
+				return
+			}
+			next = page.NextLink
+			for _, m := range page.Members {
+				var link struct {
+					OID string `json:"@odata.id"`
+				}
+				if err := json.Unmarshal(m, &link); err != nil {
+					if !yield(nil, &memberError{OID: path + " member", Err: err}) {
+						return
+					}
+					continue
+				}
+				if expanded(m) {
+					if !yield(m, nil) {
+						return
+					}
+					continue
+				}
+				b, err := c.getRaw(ctx, link.OID+sel)
+				if err != nil {
+					err = &memberError{OID: link.OID, Err: err}
+				}
+				if !yield(b, err) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// memberError is a collection member that could not be fetched.
+type memberError struct {
+	OID string
+	Err error
+}
+
+func (e *memberError) Error() string { return e.OID + ": " + e.Err.Error() }
+func (e *memberError) Unwrap() error { return e.Err }
+
+// expanded reports whether a collection member carries more than its link.
+func expanded(m json.RawMessage) bool {
+	var props map[string]json.RawMessage
+	if err := json.Unmarshal(m, &props); err != nil {
+		return false
+	}
+	for k := range props {
+		if k != "@odata.id" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// taskServer serves two pages of tasks. root is the service root body; a BMC
+// given an unadvertised query parameter answers 400, as strict ones do.
+func taskServer(t *testing.T, root string) (*client, func() []string) {
+	t.Helper()
+	tasks := map[string]string{
+		"1": `{"Id":"1","Name":"Task 1","TaskState":"Running","Messages":[{"Message":"Firmware update in progress"}],"Oem":{"Big":"payload"}}`,
+		"2": `{"Id":"2","Name":"Task 2","TaskState":"Completed"}`,
+		"3": `{"Id":"3","Name":"Task 3","TaskState":"Running","Messages":[{"Message":"Collecting logs"}]}`,
+	}
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		got = append(got, r.URL.RequestURI())
+		mu.Unlock()
+		q := r.URL.Query()
+		const coll = "/redfish/v1/TaskService/Tasks"
+		switch {
+		case r.URL.Path == "/redfish/v1":
+			if root == "" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte(root))
+		case (q.Has("$expand") || q.Has("$select")) && root == "":
+			http.Error(w, "unsupported query", http.StatusBadRequest)
+		case r.URL.Path == coll && q.Get("$expand") != "":
+			_, _ = w.Write([]byte(`{"Members":[` + tasks["1"] + `,` + tasks["2"] + `],"Members@odata.nextLink":"` + coll + `?$skip=2"}`))
+		case r.URL.Path == coll && q.Get("$skip") == "2":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"` + coll + `/3"}]}`))
+		case r.URL.Path == coll:
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"` + coll + `/1"},{"@odata.id":"` + coll + `/2"}],"Members@odata.nextLink":"` + coll + `?$skip=2"}`))
+		case r.URL.Path == coll+"/2" && root == "":
+			http.Error(w, "gone", http.StatusNotFound)
+		default:
+			body, ok := tasks[r.URL.Path[len(coll)+1:]]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			if q.Get("$select") != "" {
+				body = `{"Id":"` + r.URL.Path[len(coll)+1:] + `","TaskState":"Running","Name":"Firmware update"}`
+			}
+			_, _ = w.Write([]byte(body))
+		}
+	}))
+	t.Cleanup(ts.Close)
+	c := newClient("example.com", "admin", "password", true, 0)
+	c.base = ts.URL + "/redfish/v1"
+	return c, func() []string { mu.Lock(); defer mu.Unlock(); return slices.Clone(got) }
+}
+
+func TestMembers(t *testing.T) {
+	const both = `{"ProtocolFeaturesSupported":{"ExpandQuery":{"NoLinks":true,"Levels":true},"SelectQuery":true}}`
+	tests := []struct {
+		name      string
+		root      string
+		fields    []string
+		wantIDs   []string
+		wantPaths []string
+	}{{
+		name:    "expanded",
+		root:    both,
+		wantIDs: []string{"1", "2", "3"},
+		wantPaths: []string{
+			"/redfish/v1",
+			"/redfish/v1/TaskService/Tasks?$expand=.($levels=1)",
+			"/redfish/v1/TaskService/Tasks?$skip=2",
+			"/redfish/v1/TaskService/Tasks/3",
+		},
+	}, {
+		name:    "selected",
+		root:    both,
+		fields:  []string{"Id", "TaskState"},
+		wantIDs: []string{"1", "2", "3"},
+		wantPaths: []string{
+			"/redfish/v1",
+			"/redfish/v1/TaskService/Tasks",
+			"/redfish/v1/TaskService/Tasks/1?$select=Id,TaskState",
+			"/redfish/v1/TaskService/Tasks/2?$select=Id,TaskState",
+			"/redfish/v1/TaskService/Tasks?$skip=2",
+			"/redfish/v1/TaskService/Tasks/3?$select=Id,TaskState",
+		},
+	}, {
+		name:    "no query support",
+		fields:  []string{"Id", "TaskState"},
+		wantIDs: []string{"1", "", "3"}, // member 2 fails and iteration goes on
+		wantPaths: []string{
+			"/redfish/v1",
+			"/redfish/v1/TaskService/Tasks",
+			"/redfish/v1/TaskService/Tasks/1",
+			"/redfish/v1/TaskService/Tasks/2",
+			"/redfish/v1/TaskService/Tasks?$skip=2",
+			"/redfish/v1/TaskService/Tasks/3",
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, paths := taskServer(t, tt.root)
+			var ids []string
+			for b, err := range c.members(context.Background(), "/TaskService/Tasks", tt.fields...) {
+				var me *memberError
+				if err != nil && !errors.As(err, &me) {
+					t.Fatal(err)
+				}
+				var task rfTask
+				if err == nil {
+					if err := json.Unmarshal(b, &task); err != nil {
+						t.Fatal(err)
+					}
+				}
+				ids = append(ids, task.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", ids, tt.wantIDs)
+			}
+			if got := paths(); !reflect.DeepEqual(got, tt.wantPaths) {
+				t.Errorf("requests:\n got %q\nwant %q", got, tt.wantPaths)
+			}
+		})
+	}
+}
+
+func TestGetActiveUpdateTasksSelectsFields(t *testing.T) {
+	c, paths := taskServer(t, `{"ProtocolFeaturesSupported":{"SelectQuery":true}}`)
+	ids, err := c.GetActiveUpdateTasks(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ids, []string{"1", "2", "3"}) {
+		t.Errorf("ids = %v", ids)
+	}
+	if got := paths(); got[2] != "/redfish/v1/TaskService/Tasks/1?$select=Id,Name,TaskState,Messages" {
+		t.Errorf("requests = %q", got)
+	}
+
+	c, _ = taskServer(t, "")
+	if ids, err = c.GetActiveUpdateTasks(context.Background()); err != nil || !reflect.DeepEqual(ids, []string{"1"}) {
+		t.Errorf("without $select: ids = %v, err = %v; want the running firmware task only", ids, err)
+	}
+}
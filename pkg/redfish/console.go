@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// GraphicalConsole is the GraphicalConsole property of a Manager or System.
+type GraphicalConsole struct {
+	ServiceEnabled        bool     `json:"ServiceEnabled"`
+	MaxConcurrentSessions int      `json:"MaxConcurrentSessions"`
+	ConnectTypesSupported []string `json:"ConnectTypesSupported"` // KVMIP, OEM
+}
+
+// Console is how the graphical console (KVM) of one system is reached.
+type Console struct {
+	SystemPath   string
+	SystemID     string
+	ManagerPath  string
+	Manufacturer string // of the manager, to pick a vendor launch path
+	Model        string
+	Available    bool // a GraphicalConsole was found on the system or its manager
+	Enabled      bool
+	ConnectTypes []string
+	MaxSessions  int
+	URL          string // launch URL from the Oem data of the system or manager; "" when none
+}
+
+// GetConsoles returns the graphical console of every system on the BMC: the
+// system's own GraphicalConsole, else its manager's. Redfish has no standard
+// launch URL, so URL is only set when vendor Oem data names one.
+func (c *client) GetConsoles(ctx context.Context) ([]Console, error) {
+	systems, err := c.GetSystems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	managers, err := c.GetManagers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Console, 0, len(systems))
+	for _, s := range systems {
+		con := Console{SystemPath: s.ODataID, SystemID: s.ID}
+		var m *Manager
+		for i := range managers {
+			if len(s.Links.ManagedBy) > 0 && managers[i].ODataID == s.Links.ManagedBy[0].ODataID {
+				m = &managers[i]
+			}
+		}
+		if m == nil && len(managers) > 0 {
+			m = &managers[0]
+		}
+		gc := s.GraphicalConsole
+		if m != nil {
+			con.ManagerPath, con.Manufacturer, con.Model = m.ODataID, m.Manufacturer, m.Model
+			if gc == nil {
+				gc = m.GraphicalConsole
+			}
+		}
+		if gc != nil {
+			con.Available, con.Enabled = true, gc.ServiceEnabled
+			con.ConnectTypes, con.MaxSessions = gc.ConnectTypesSupported, gc.MaxConcurrentSessions
+		}
+		con.URL = oemConsoleURL(s.Oem)
+		if con.URL == "" && m != nil {
+			con.URL = oemConsoleURL(m.Oem)
+		}
+		if strings.HasPrefix(con.URL, "/") {
+			con.URL = "https://" + c.host + con.URL
+		}
+		out = append(out, con)
+	}
+	return out, nil
+}
+
+// oemConsoleURL returns the first URL or absolute path in oem under a key
+// naming a console, KVM or remote console (IRC).
+func oemConsoleURL(oem map[string]json.RawMessage) string {
+	for _, vendor := range slices.Sorted(maps.Keys(oem)) {
+		var v any
+		if json.Unmarshal(oem[vendor], &v) != nil {
+			continue
+		}
+		if u := walkConsoleURL(v, ""); u != "" {
+			return u
+		}
+	}
+	return ""
+}
+
+func walkConsoleURL(v any, key string) string {
+	switch v := v.(type) {
+	case map[string]any:
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			if u := walkConsoleURL(v[name], name); u != "" {
+				return u
+			}
+		}
+	case string:
+		k := strings.ToLower(key)
+		named := strings.Contains(k, "console") || strings.Contains(k, "kvm") || strings.Contains(k, "irc")
+		if named && (strings.HasPrefix(v, "https://") || strings.HasPrefix(v, "http://") || (strings.HasPrefix(v, "/") && !strings.HasPrefix(v, "/redfish/"))) {
+			return v
+		}
+	}
+	return ""
+}
+
+// GetConsoles calls Client.GetConsoles on a new client for host.
+func GetConsoles(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]Console, error) {
+	return newClient(host, user, pass, insecure, timeout).GetConsoles(ctx)
+}
@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetConsoles(t *testing.T) {
+	docs := map[string]string{
+		"/redfish/v1/Systems":       `{"Members":[{"@odata.id":"/redfish/v1/Systems/Node0"},{"@odata.id":"/redfish/v1/Systems/Node1"}]}`,
+		"/redfish/v1/Systems/Node0": `{"@odata.id":"/redfish/v1/Systems/Node0","Id":"Node0","Links":{"ManagedBy":[{"@odata.id":"/redfish/v1/Managers/BMC"}]}}`,
+		"/redfish/v1/Systems/Node1": `{"@odata.id":"/redfish/v1/Systems/Node1","Id":"Node1",
+			"GraphicalConsole":{"ServiceEnabled":false,"ConnectTypesSupported":["KVMIP"]},
+			"Oem":{"Acme":{"Links":{"KvmLaunch":"https://kvm.example/node1"}}}}`,
+		"/redfish/v1/Managers": `{"Members":[{"@odata.id":"/redfish/v1/Managers/BMC"}]}`,
+		"/redfish/v1/Managers/BMC": `{"@odata.id":"/redfish/v1/Managers/BMC","Id":"BMC","Manufacturer":"Acme","Model":"B1",
+			"GraphicalConsole":{"ServiceEnabled":true,"MaxConcurrentSessions":4,"ConnectTypesSupported":["KVMIP","OEM"]},
+			"Oem":{"Acme":{"LogPath":"/var/log","RemoteConsoleURL":"/html/kvm.html","Manager":{"@odata.id":"/redfish/v1/Managers/BMC/Console"}}}}`,
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := docs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	host := server.URL[len("https://"):]
+
+	cons, err := New(host, "user", "pass", true, 5*time.Second).GetConsoles(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Console{
+		{SystemPath: "/redfish/v1/Systems/Node0", SystemID: "Node0", ManagerPath: "/redfish/v1/Managers/BMC", Manufacturer: "Acme", Model: "B1",
+			Available: true, Enabled: true, ConnectTypes: []string{"KVMIP", "OEM"}, MaxSessions: 4, URL: "https://" + host + "/html/kvm.html"},
+		{SystemPath: "/redfish/v1/Systems/Node1", SystemID: "Node1", ManagerPath: "/redfish/v1/Managers/BMC", Manufacturer: "Acme", Model: "B1",
+			Available: true, ConnectTypes: []string{"KVMIP"}, URL: "https://kvm.example/node1"},
+	}
+	if !reflect.DeepEqual(cons, want) {
+		t.Errorf("GetConsoles =\n%+v\nwant\n%+v", cons, want)
+	}
+}
@@ -0,0 +1,22 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"net"
+)
+
+// DialContextFunc opens a connection to a BMC, like net.Dialer.DialContext.
+type DialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// dialer is the process-wide dialer of new clients; nil dials directly.
+var dialer DialContextFunc
+
+// SetDialer routes the connections of clients created afterwards through dial,
+// e.g. a proxy or an SSH jump host. nil restores direct connections.
+func SetDialer(dial DialContextFunc) {
+	dialer = dial
+}
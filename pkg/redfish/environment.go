@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Threshold is one entry of a sensor's Thresholds.
+type Threshold struct {
+	Reading *float64 `json:"Reading"`
+}
+
+// Sensor is a Sensor resource from a chassis' Sensors collection.
+type Sensor struct {
+	Resource
+	ReadingType     string   `json:"ReadingType"`
+	Reading         *float64 `json:"Reading"`
+	ReadingUnits    string   `json:"ReadingUnits"`
+	PhysicalContext string   `json:"PhysicalContext"`
+	Status          Status   `json:"Status"`
+	Thresholds      struct {
+		UpperCritical Threshold `json:"UpperCritical"`
+		LowerCritical Threshold `json:"LowerCritical"`
+		UpperCaution  Threshold `json:"UpperCaution"`
+		LowerCaution  Threshold `json:"LowerCaution"`
+	} `json:"Thresholds"`
+}
+
+// LeakDetector is a LeakDetector resource of a chassis' ThermalSubsystem.
+type LeakDetector struct {
+	Resource
+	DetectorState   string `json:"DetectorState"` // OK, Warning or Critical
+	PhysicalContext string `json:"PhysicalContext"`
+	Status          Status `json:"Status"`
+}
+
+// Environment holds the environmental readings of every chassis on a BMC, such
+// as the coolant temperatures and leak detectors of a liquid-cooled cabinet.
+type Environment struct {
+	Sensors       []EnvSensor
+	LeakDetectors []EnvLeakDetector
+}
+
+// EnvSensor is a Sensor with the chassis it belongs to.
+type EnvSensor struct {
+	Chassis string // Id of the chassis
+	Sensor
+}
+
+// EnvLeakDetector is a LeakDetector with the chassis it belongs to.
+type EnvLeakDetector struct {
+	Chassis string // Id of the chassis
+	LeakDetector
+}
+
+// GetEnvironment reads the Sensors collection and the leak detectors
+// (ThermalSubsystem/LeakDetection/LeakDetectors) of every chassis. A chassis
+// without them is skipped, so BMCs that expose neither return an empty
+// Environment.
+func (c *client) GetEnvironment(ctx context.Context) (Environment, error) {
+	var env Environment
+	chassis, err := c.GetChassis(ctx)
+	if err != nil {
+		return env, err
+	}
+	for _, ch := range chassis {
+		if ch.Sensors.ODataID != "" {
+			sensors, err := getMembers[Sensor](ctx, c, ch.Sensors.ODataID)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return env, fmt.Errorf("chassis %s sensors: %w", ch.ID, err)
+			}
+			for _, s := range sensors {
+				env.Sensors = append(env.Sensors, EnvSensor{Chassis: ch.ID, Sensor: s})
+			}
+		}
+		if ch.ThermalSubsystem.ODataID == "" {
+			continue
+		}
+		detectors, err := c.leakDetectors(ctx, ch.ThermalSubsystem.ODataID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return env, fmt.Errorf("chassis %s leak detectors: %w", ch.ID, err)
+		}
+		for _, d := range detectors {
+			env.LeakDetectors = append(env.LeakDetectors, EnvLeakDetector{Chassis: ch.ID, LeakDetector: d})
+		}
+	}
+	return env, nil
+}
+
+// leakDetectors follows ThermalSubsystem → LeakDetection → LeakDetectors.
+func (c *client) leakDetectors(ctx context.Context, thermalPath string) ([]LeakDetector, error) {
+	var thermal struct {
+		LeakDetection Link `json:"LeakDetection"`
+	}
+	if err := c.get(ctx, thermalPath, &thermal); err != nil {
+		return nil, err
+	}
+	if thermal.LeakDetection.ODataID == "" {
+		return nil, nil
+	}
+	var detection struct {
+		LeakDetectors Link `json:"LeakDetectors"`
+	}
+	if err := c.get(ctx, thermal.LeakDetection.ODataID, &detection); err != nil {
+		return nil, err
+	}
+	if detection.LeakDetectors.ODataID == "" {
+		return nil, nil
+	}
+	return getMembers[LeakDetector](ctx, c, detection.LeakDetectors.ODataID)
+}
+
+// GetEnvironment calls Client.GetEnvironment on a new client for host.
+func GetEnvironment(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) (Environment, error) {
+	return newClient(host, user, pass, insecure, timeout).GetEnvironment(ctx)
+}
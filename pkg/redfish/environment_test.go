@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetEnvironment(t *testing.T) {
+	docs := map[string]string{
+		"/redfish/v1/Chassis": `{"Members":[{"@odata.id":"/redfish/v1/Chassis/Enclosure"},{"@odata.id":"/redfish/v1/Chassis/Blade0"}]}`,
+		"/redfish/v1/Chassis/Enclosure": `{"@odata.id":"/redfish/v1/Chassis/Enclosure","Id":"Enclosure",
+			"Sensors":{"@odata.id":"/redfish/v1/Chassis/Enclosure/Sensors"},
+			"ThermalSubsystem":{"@odata.id":"/redfish/v1/Chassis/Enclosure/ThermalSubsystem"}}`,
+		"/redfish/v1/Chassis/Blade0":                                                       `{"@odata.id":"/redfish/v1/Chassis/Blade0","Id":"Blade0"}`,
+		"/redfish/v1/Chassis/Enclosure/Sensors":                                            `{"Members":[{"@odata.id":"/redfish/v1/Chassis/Enclosure/Sensors/Inlet"}]}`,
+		"/redfish/v1/Chassis/Enclosure/Sensors/Inlet":                                      `{"Id":"Inlet","ReadingType":"Temperature","Reading":24.5,"ReadingUnits":"Cel","PhysicalContext":"LiquidInlet","Thresholds":{"UpperCritical":{"Reading":40}}}`,
+		"/redfish/v1/Chassis/Enclosure/ThermalSubsystem":                                   `{"LeakDetection":{"@odata.id":"/redfish/v1/Chassis/Enclosure/ThermalSubsystem/LeakDetection"}}`,
+		"/redfish/v1/Chassis/Enclosure/ThermalSubsystem/LeakDetection":                     `{"LeakDetectors":{"@odata.id":"/redfish/v1/Chassis/Enclosure/ThermalSubsystem/LeakDetection/LeakDetectors"}}`,
+		"/redfish/v1/Chassis/Enclosure/ThermalSubsystem/LeakDetection/LeakDetectors":       `{"Members":[{"@odata.id":"/redfish/v1/Chassis/Enclosure/ThermalSubsystem/LeakDetection/LeakDetectors/Tray0"}]}`,
+		"/redfish/v1/Chassis/Enclosure/ThermalSubsystem/LeakDetection/LeakDetectors/Tray0": `{"Id":"Tray0","DetectorState":"OK"}`,
+	}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := docs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	c := New(server.URL[len("https://"):], "user", "pass", true, 5*time.Second)
+
+	env, err := c.GetEnvironment(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(env.Sensors) != 1 || len(env.LeakDetectors) != 1 {
+		t.Fatalf("env = %+v", env)
+	}
+	s := env.Sensors[0]
+	if s.Chassis != "Enclosure" || s.ID != "Inlet" || *s.Reading != 24.5 || *s.Thresholds.UpperCritical.Reading != 40 {
+		t.Errorf("sensor = %+v", s)
+	}
+	if d := env.LeakDetectors[0]; d.Chassis != "Enclosure" || d.ID != "Tray0" || d.DetectorState != "OK" {
+		t.Errorf("leak detector = %+v", d)
+	}
+}
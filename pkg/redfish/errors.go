@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"bootstrap/internal/redact"
+)
+
+// Classes of client errors, for errors.Is:
+//
+//	switch {
+//	case errors.Is(err, redfish.ErrUnauthorized):     // 401/403: fix the credentials
+//	case errors.Is(err, redfish.ErrNotFound):         // 404: the BMC lacks the resource
+//	case errors.Is(err, redfish.ErrDecode):           // an HTML error page, truncated JSON, ...
+//	case errors.Is(err, redfish.ErrTransport):        // no response: refused, timed out, reset
+//	case errors.Is(err, redfish.ErrAlreadyAtVersion): // SimpleUpdate had nothing to do
+//	}
+//
+// *UpdateWarningsError and *TaskFailedError carry details; use errors.As.
+var (
+	ErrUnauthorized = errors.New("redfish: authentication failed")
+	ErrNotFound     = errors.New("redfish: resource not found")
+	ErrDecode       = errors.New("redfish: response is not valid JSON")
+	ErrTransport    = errors.New("redfish: no response")
+	// ErrAlreadyAtVersion is returned by SimpleUpdate when every target already
+	// runs the expected version, so nothing was posted.
+	ErrAlreadyAtVersion = errors.New("skipping update: all targets already at expected version")
+)
+
+// snippetLen is how much of an undecodable body errors quote.
+const snippetLen = 200
+
+// StatusError is returned when a BMC answers a request with a non-success HTTP status.
+type StatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	// GETs historically omit the method in the message. The body is the BMC's
+	// and may echo a request field such as a password, so it is redacted.
+	if e.Method == "GET" {
+		return redact.String(fmt.Sprintf("redfish %s: %s: %s", e.URL, e.Status, e.Body))
+	}
+	return redact.String(fmt.Sprintf("redfish %s %s: %s: %s", e.Method, e.URL, e.Status, e.Body))
+}
+
+// Is reports 401 and 403 as ErrUnauthorized and 404 as ErrNotFound.
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+// IsAuthError reports whether err is a Redfish 401/403 response.
+func IsAuthError(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// DecodeError is a successful response whose body is not the JSON expected,
+// such as the HTML error page of a BMC web server.
+type DecodeError struct {
+	URL         string
+	ContentType string // as sent by the BMC, when it is the reason
+	Snippet     string // the start of the body
+	Err         error
+}
+
+func (e *DecodeError) Error() string {
+	if e.ContentType != "" {
+		return redact.String(fmt.Sprintf("redfish %s: %v (Content-Type %s): %q", e.URL, e.Err, e.ContentType, e.Snippet))
+	}
+	return redact.String(fmt.Sprintf("redfish %s: decode: %v: %q", e.URL, e.Err, e.Snippet))
+}
+
+func (e *DecodeError) Is(target error) bool { return target == ErrDecode }
+func (e *DecodeError) Unwrap() error        { return e.Err }
+
+// TransportError is a request that got no complete response. Its message is
+// that of the underlying error, which names the method and URL.
+type TransportError struct {
+	Method string
+	URL    string
+	Err    error
+}
+
+func (e *TransportError) Error() string        { return redact.String(e.Err.Error()) }
+func (e *TransportError) Is(target error) bool { return target == ErrTransport }
+func (e *TransportError) Unwrap() error        { return e.Err }
+
+// UpdateWarningsError is returned by SimpleUpdate when the BMC accepted the
+// update but a target then reported Warning or Critical status conditions.
+type UpdateWarningsError struct {
+	Conditions []string // "[target] Severity: message"
+}
+
+func (e *UpdateWarningsError) Error() string {
+	return "firmware update completed with warnings/errors:\n" + strings.Join(e.Conditions, "\n")
+}
+
+// TaskFailedError is a task that ended without completing; see Task.Err.
+type TaskFailedError struct {
+	Task     string // @odata.id
+	State    string // Exception, Killed or Cancelled
+	Messages []string
+}
+
+func (e *TaskFailedError) Error() string {
+	msg := fmt.Sprintf("task %s ended in state %s", e.Task, e.State)
+	if len(e.Messages) > 0 {
+		msg += ": " + strings.Join(e.Messages, "; ")
+	}
+	return redact.String(msg)
+}
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// checkJSON strips a byte order mark from the body of a successful response and
+// rejects HTML, which some BMCs serve with a 200 for errors and login pages.
+func checkJSON(url, contentType string, b []byte) ([]byte, error) {
+	b = bytes.TrimPrefix(b, utf8BOM)
+	if isHTML(contentType, b) {
+		return nil, &DecodeError{URL: url, ContentType: contentType, Snippet: snippet(b), Err: errors.New("got HTML, not JSON")}
+	}
+	return b, nil
+}
+
+// decodeJSON unmarshals the body of url into v.
+func decodeJSON(url string, b []byte, v any) error {
+	b = bytes.TrimPrefix(b, utf8BOM)
+	if err := json.Unmarshal(b, v); err != nil {
+		return &DecodeError{URL: url, Snippet: snippet(b), Err: err}
+	}
+	return nil
+}
+
+// errorBody is the body of an error response as StatusError reports it: JSON
+// (a Redfish error object) whole, anything else shortened.
+func errorBody(contentType string, b []byte) string {
+	b = bytes.TrimSpace(bytes.TrimPrefix(b, utf8BOM))
+	if isHTML(contentType, b) || !json.Valid(b) {
+		return snippet(b)
+	}
+	return string(b)
+}
+
+func isHTML(contentType string, b []byte) bool {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil && (mt == "text/html" || mt == "application/xhtml+xml") {
+		return true
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(b), []byte("<"))
+}
+
+// snippet returns the start of b on one line.
+func snippet(b []byte) string {
+	s := strings.Join(strings.Fields(string(b)), " ")
+	if len(s) <= snippetLen {
+		return s
+	}
+	cut := snippetLen
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Locator is the identify LED of one system: on the system itself, or on its
+// chassis when the system has none.
+type Locator struct {
+	SystemPath string
+	SystemID   string
+	Path       string // resource carrying the LED
+	State      string // Lit, Blinking or Off
+}
+
+// locatorResource is the part of a System or Chassis that holds its LED:
+// LocationIndicatorActive (Redfish 2020.3+) or the older IndicatorLED.
+type locatorResource struct {
+	ODataID                 string `json:"@odata.id"`
+	ID                      string `json:"Id"`
+	LocationIndicatorActive *bool  `json:"LocationIndicatorActive"`
+	IndicatorLED            string `json:"IndicatorLED"`
+	Links                   struct {
+		Chassis []Link `json:"Chassis"`
+	} `json:"Links"`
+}
+
+func (r locatorResource) hasLED() bool {
+	return r.LocationIndicatorActive != nil || r.IndicatorLED != ""
+}
+
+func (r locatorResource) state() string {
+	if r.LocationIndicatorActive != nil {
+		if *r.LocationIndicatorActive {
+			return "Lit"
+		}
+		return "Off"
+	}
+	return r.IndicatorLED
+}
+
+// locatorFor returns the Id of the system at path and the resource carrying its LED.
+func (c *client) locatorFor(ctx context.Context, path string) (string, locatorResource, error) {
+	var sys locatorResource
+	if err := c.get(ctx, path, &sys); err != nil {
+		return "", sys, err
+	}
+	if sys.ODataID == "" {
+		sys.ODataID = path
+	}
+	if sys.hasLED() || len(sys.Links.Chassis) == 0 {
+		return sys.ID, sys, nil
+	}
+	var ch locatorResource
+	if err := c.get(ctx, sys.Links.Chassis[0].ODataID, &ch); err != nil {
+		return sys.ID, sys, err
+	}
+	if ch.ODataID == "" {
+		ch.ODataID = sys.Links.Chassis[0].ODataID
+	}
+	return sys.ID, ch, nil
+}
+
+// GetLocators returns the identify LED of every system on the BMC.
+func (c *client) GetLocators(ctx context.Context) ([]Locator, error) {
+	paths, err := c.listSystemPaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Locator, 0, len(paths))
+	for _, p := range paths {
+		id, r, err := c.locatorFor(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, Locator{SystemPath: p, SystemID: id, Path: r.ODataID, State: r.state()})
+	}
+	return out, nil
+}
+
+// SetLocator turns the identify LED of the system at systemPath (every system on
+// the BMC when empty) on or off, using the system's chassis when the system has
+// no LED of its own.
+func (c *client) SetLocator(ctx context.Context, systemPath string, on bool) error {
+	paths := []string{systemPath}
+	if systemPath == "" {
+		var err error
+		if paths, err = c.listSystemPaths(ctx); err != nil {
+			return err
+		}
+	}
+	for _, p := range paths {
+		_, r, err := c.locatorFor(ctx, p)
+		if err != nil {
+			return err
+		}
+		var body map[string]any
+		switch {
+		case r.LocationIndicatorActive != nil:
+			body = map[string]any{"LocationIndicatorActive": on}
+		case r.IndicatorLED != "":
+			led := "Off"
+			if on {
+				led = "Lit"
+			}
+			body = map[string]any{"IndicatorLED": led}
+		default:
+			return fmt.Errorf("%s: no indicator LED", p)
+		}
+		if err := c.patch(ctx, r.ODataID, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetLocators calls Client.GetLocators on a new client for host.
+func GetLocators(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]Locator, error) {
+	return newClient(host, user, pass, insecure, timeout).GetLocators(ctx)
+}
+
+// SetLocator calls Client.SetLocator on a new client for host.
+func SetLocator(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, systemPath string, on bool) error {
+	return newClient(host, user, pass, insecure, timeout).SetLocator(ctx, systemPath, on)
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLocators(t *testing.T) {
+	docs := map[string]string{
+		"/redfish/v1/Systems":        `{"Members":[{"@odata.id":"/redfish/v1/Systems/Node0"},{"@odata.id":"/redfish/v1/Systems/Node1"}]}`,
+		"/redfish/v1/Systems/Node0":  `{"@odata.id":"/redfish/v1/Systems/Node0","Id":"Node0","LocationIndicatorActive":true}`,
+		"/redfish/v1/Systems/Node1":  `{"@odata.id":"/redfish/v1/Systems/Node1","Id":"Node1","Links":{"Chassis":[{"@odata.id":"/redfish/v1/Chassis/Blade1"}]}}`,
+		"/redfish/v1/Chassis/Blade1": `{"@odata.id":"/redfish/v1/Chassis/Blade1","Id":"Blade1","IndicatorLED":"Off"}`,
+	}
+	patches := map[string]map[string]any{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			patches[r.URL.Path] = body
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		body, ok := docs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	c := New(server.URL[len("https://"):], "user", "pass", true, 5*time.Second)
+
+	locs, err := c.GetLocators(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Locator{
+		{SystemPath: "/redfish/v1/Systems/Node0", SystemID: "Node0", Path: "/redfish/v1/Systems/Node0", State: "Lit"},
+		{SystemPath: "/redfish/v1/Systems/Node1", SystemID: "Node1", Path: "/redfish/v1/Chassis/Blade1", State: "Off"},
+	}
+	if !reflect.DeepEqual(locs, want) {
+		t.Errorf("GetLocators = %+v, want %+v", locs, want)
+	}
+
+	if err := c.SetLocator(context.Background(), "", false); err != nil {
+		t.Fatal(err)
+	}
+	wantPatches := map[string]map[string]any{
+		"/redfish/v1/Systems/Node0":  {"LocationIndicatorActive": false},
+		"/redfish/v1/Chassis/Blade1": {"IndicatorLED": "Off"},
+	}
+	if !reflect.DeepEqual(patches, wantPatches) {
+		t.Errorf("patches = %v, want %v", patches, wantPatches)
+	}
+}
@@ -0,0 +1,355 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Typed models for the main Redfish resources. They cover the commonly used
+// properties of each schema; everything else (including vendor OEM blocks) stays
+// reachable through Resource.Raw, Resource.Field and Resource.OemFor.
+
+// Link is a reference to another Redfish resource.
+type Link struct {
+	ODataID string `json:"@odata.id"`
+}
+
+// Condition is an entry of Status.Conditions.
+type Condition struct {
+	Message     string   `json:"Message"`
+	MessageArgs []string `json:"MessageArgs"`
+	MessageID   string   `json:"MessageId"`
+	Severity    string   `json:"Severity"`
+	Timestamp   string   `json:"Timestamp"`
+}
+
+// Status is the common Redfish Status object.
+type Status struct {
+	State        string      `json:"State"`
+	Health       string      `json:"Health"`
+	HealthRollup string      `json:"HealthRollup"`
+	Conditions   []Condition `json:"Conditions"`
+}
+
+// Message is a Redfish Message object as found in tasks and error responses.
+type Message struct {
+	MessageID   string   `json:"MessageId"`
+	Message     string   `json:"Message"`
+	MessageArgs []string `json:"MessageArgs"`
+	Severity    string   `json:"Severity"`
+	Resolution  string   `json:"Resolution"`
+}
+
+// Action is an entry of a resource's Actions object.
+type Action struct {
+	Target string `json:"target"`
+	Title  string `json:"title"`
+}
+
+// Resource holds the properties shared by every Redfish resource plus the raw
+// document it was decoded from.
+type Resource struct {
+	ODataID     string                     `json:"@odata.id"`
+	ODataType   string                     `json:"@odata.type"`
+	ID          string                     `json:"Id"`
+	Name        string                     `json:"Name"`
+	Description string                     `json:"Description"`
+	Oem         map[string]json.RawMessage `json:"Oem"`
+	Raw         json.RawMessage            `json:"-"`
+}
+
+func (r *Resource) setRaw(b []byte) { r.Raw = append(json.RawMessage(nil), b...) }
+
+// Field decodes the top-level property name of the raw document into v. It is
+// the escape hatch for properties the typed model does not cover.
+func (r Resource) Field(name string, v any) error {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(r.Raw, &m); err != nil {
+		return err
+	}
+	f, ok := m[name]
+	if !ok {
+		return fmt.Errorf("%s: no property %q", r.ODataID, name)
+	}
+	return json.Unmarshal(f, v)
+}
+
+// OemFor decodes the Oem block of the given vendor (e.g. "Hpe", "Dell") into v.
+func (r Resource) OemFor(vendor string, v any) error {
+	b, ok := r.Oem[vendor]
+	if !ok {
+		return fmt.Errorf("%s: no Oem.%s block", r.ODataID, vendor)
+	}
+	return json.Unmarshal(b, v)
+}
+
+// System is a ComputerSystem resource.
+type System struct {
+	Resource
+	SystemType   string `json:"SystemType"`
+	Manufacturer string `json:"Manufacturer"`
+	Model        string `json:"Model"`
+	SKU          string `json:"SKU"`
+	SerialNumber string `json:"SerialNumber"`
+	PartNumber   string `json:"PartNumber"`
+	UUID         string `json:"UUID"`
+	AssetTag     string `json:"AssetTag"`
+	HostName     string `json:"HostName"`
+	PowerState   string `json:"PowerState"`
+	IndicatorLED string `json:"IndicatorLED"`
+	BiosVersion  string `json:"BiosVersion"`
+	Status       Status `json:"Status"`
+	Boot         struct {
+		BootSourceOverrideEnabled string   `json:"BootSourceOverrideEnabled"`
+		BootSourceOverrideTarget  string   `json:"BootSourceOverrideTarget"`
+		BootSourceOverrideMode    string   `json:"BootSourceOverrideMode"`
+		AllowableTargets          []string `json:"BootSourceOverrideTarget@Redfish.AllowableValues"`
+		BootOrder                 []string `json:"BootOrder"`
+	} `json:"Boot"`
+	ProcessorSummary struct {
+		Count                 int    `json:"Count"`
+		LogicalProcessorCount int    `json:"LogicalProcessorCount"`
+		Model                 string `json:"Model"`
+		Status                Status `json:"Status"`
+	} `json:"ProcessorSummary"`
+	MemorySummary struct {
+		TotalSystemMemoryGiB float64 `json:"TotalSystemMemoryGiB"`
+		Status               Status  `json:"Status"`
+	} `json:"MemorySummary"`
+	Bios               Link              `json:"Bios"`
+	EthernetInterfaces Link              `json:"EthernetInterfaces"`
+	Processors         Link              `json:"Processors"`
+	Memory             Link              `json:"Memory"`
+	Storage            Link              `json:"Storage"`
+	LogServices        Link              `json:"LogServices"`
+	GraphicalConsole   *GraphicalConsole `json:"GraphicalConsole"`
+	Links              struct {
+		Chassis   []Link `json:"Chassis"`
+		ManagedBy []Link `json:"ManagedBy"`
+	} `json:"Links"`
+	Actions map[string]Action `json:"Actions"`
+}
+
+// Manager is a Manager (BMC) resource.
+type Manager struct {
+	Resource
+	ManagerType        string            `json:"ManagerType"`
+	Manufacturer       string            `json:"Manufacturer"`
+	Model              string            `json:"Model"`
+	SerialNumber       string            `json:"SerialNumber"`
+	UUID               string            `json:"UUID"`
+	FirmwareVersion    string            `json:"FirmwareVersion"`
+	DateTime           string            `json:"DateTime"`
+	DateTimeOffset     string            `json:"DateTimeLocalOffset"`
+	PowerState         string            `json:"PowerState"`
+	Status             Status            `json:"Status"`
+	NetworkProtocol    Link              `json:"NetworkProtocol"`
+	EthernetInterfaces Link              `json:"EthernetInterfaces"`
+	LogServices        Link              `json:"LogServices"`
+	VirtualMedia       Link              `json:"VirtualMedia"`
+	GraphicalConsole   *GraphicalConsole `json:"GraphicalConsole"`
+	Links              struct {
+		ManagerForServers []Link `json:"ManagerForServers"`
+		ManagerForChassis []Link `json:"ManagerForChassis"`
+		ManagerInChassis  Link   `json:"ManagerInChassis"`
+	} `json:"Links"`
+	Actions map[string]Action `json:"Actions"`
+}
+
+// Chassis is a Chassis resource.
+type Chassis struct {
+	Resource
+	ChassisType  string `json:"ChassisType"`
+	Manufacturer string `json:"Manufacturer"`
+	Model        string `json:"Model"`
+	SKU          string `json:"SKU"`
+	SerialNumber string `json:"SerialNumber"`
+	PartNumber   string `json:"PartNumber"`
+	AssetTag     string `json:"AssetTag"`
+	PowerState   string `json:"PowerState"`
+	IndicatorLED string `json:"IndicatorLED"`
+	Status       Status `json:"Status"`
+	Location     struct {
+		PartLocation struct {
+			ServiceLabel         string `json:"ServiceLabel"`
+			LocationType         string `json:"LocationType"`
+			LocationOrdinalValue int    `json:"LocationOrdinalValue"`
+		} `json:"PartLocation"`
+	} `json:"Location"`
+	Power              Link `json:"Power"`
+	Thermal            Link `json:"Thermal"`
+	Sensors            Link `json:"Sensors"`
+	ThermalSubsystem   Link `json:"ThermalSubsystem"`
+	PowerSubsystem     Link `json:"PowerSubsystem"`
+	EnvironmentMetrics Link `json:"EnvironmentMetrics"`
+	Links              struct {
+		ComputerSystems []Link `json:"ComputerSystems"`
+		ManagedBy       []Link `json:"ManagedBy"`
+		Contains        []Link `json:"Contains"`
+		ContainedBy     Link   `json:"ContainedBy"`
+	} `json:"Links"`
+	Actions map[string]Action `json:"Actions"`
+}
+
+// UpdateService is the UpdateService resource.
+type UpdateService struct {
+	Resource
+	ServiceEnabled    *bool  `json:"ServiceEnabled"`
+	HTTPPushURI       string `json:"HttpPushUri"`
+	MaxImageSizeBytes int64  `json:"MaxImageSizeBytes"`
+	Status            Status `json:"Status"`
+	FirmwareInventory Link   `json:"FirmwareInventory"`
+	SoftwareInventory Link   `json:"SoftwareInventory"`
+	Actions           struct {
+		SimpleUpdate struct {
+			Target            string   `json:"target"`
+			TransferProtocols []string `json:"TransferProtocol@Redfish.AllowableValues"`
+		} `json:"#UpdateService.SimpleUpdate"`
+	} `json:"Actions"`
+}
+
+// ProtocolAuto asks ResolveProtocol to pick a TransferProtocol the BMC supports.
+const ProtocolAuto = "auto"
+
+// SelectProtocol returns the TransferProtocol to post to this service. For
+// ProtocolAuto (or "") it picks HTTPS, then HTTP, then the first advertised value.
+// A requested protocol is returned unchanged when the service does not advertise
+// its allowable values, and rejected when it advertises others.
+func (u UpdateService) SelectProtocol(requested string) (string, error) {
+	allowed := u.Actions.SimpleUpdate.TransferProtocols
+	if requested == "" || strings.EqualFold(requested, ProtocolAuto) {
+		for _, p := range []string{"HTTPS", "HTTP"} {
+			if len(allowed) == 0 || slices.Contains(allowed, p) {
+				return p, nil
+			}
+		}
+		return allowed[0], nil
+	}
+	if len(allowed) == 0 {
+		return requested, nil
+	}
+	for _, p := range allowed {
+		if strings.EqualFold(p, requested) {
+			return p, nil
+		}
+	}
+	msg := fmt.Sprintf("TransferProtocol %s not supported by UpdateService (supported: %s)", requested, strings.Join(allowed, ", "))
+	if u.HTTPPushURI != "" {
+		msg += "; the BMC also accepts image uploads at " + u.HTTPPushURI
+	}
+	return "", errors.New(msg)
+}
+
+// ResolveProtocol reads the UpdateService of u and returns the TransferProtocol
+// to use for requested (see UpdateService.SelectProtocol). When the service
+// cannot be read the requested protocol is used as is (HTTP for ProtocolAuto),
+// leaving SimpleUpdate to report the error.
+func ResolveProtocol(ctx context.Context, u Updater, requested string) (string, error) {
+	us, err := u.GetUpdateService(ctx)
+	if err != nil {
+		if requested == "" || strings.EqualFold(requested, ProtocolAuto) {
+			return "HTTP", nil
+		}
+		return requested, nil
+	}
+	return us.SelectProtocol(requested)
+}
+
+// Task is a TaskService task.
+type Task struct {
+	Resource
+	TaskState       string    `json:"TaskState"`
+	TaskStatus      string    `json:"TaskStatus"`
+	PercentComplete *int      `json:"PercentComplete"`
+	StartTime       string    `json:"StartTime"`
+	EndTime         string    `json:"EndTime"`
+	TaskMonitor     string    `json:"TaskMonitor"`
+	Messages        []Message `json:"Messages"`
+}
+
+// Err returns a *TaskFailedError when the task ended without completing, and
+// nil while it runs or once it completed.
+func (t Task) Err() error {
+	switch t.TaskState {
+	case "Exception", "Killed", "Cancelled":
+		e := &TaskFailedError{Task: t.ODataID, State: t.TaskState}
+		for _, m := range t.Messages {
+			if m.Message != "" {
+				e.Messages = append(e.Messages, m.Message)
+			}
+		}
+		return e
+	}
+	return nil
+}
+
+// rawSetter is implemented by every model embedding Resource.
+type rawSetter interface{ setRaw([]byte) }
+
+// getResource fetches path into v, keeping the raw body for pass-through access.
+func (c *client) getResource(ctx context.Context, path string, v rawSetter) error {
+	b, err := c.getRaw(ctx, path)
+	if err != nil {
+		return err
+	}
+	if err := decodeJSON(c.resolvePath(path), b, v); err != nil {
+		return err
+	}
+	v.setRaw(b)
+	return nil
+}
+
+// getMembers fetches and decodes every member of the collection at path.
+func getMembers[T any, PT interface {
+	*T
+	rawSetter
+}](ctx context.Context, c *client, path string) ([]T, error) {
+	out := []T{}
+	for b, err := range c.members(ctx, path) {
+		if err != nil {
+			return nil, err
+		}
+		var item T
+		if err := json.Unmarshal(b, PT(&item)); err != nil {
+			return nil, fmt.Errorf("decode member of %s: %w", path, err)
+		}
+		PT(&item).setRaw(b)
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+// GetSystems returns every ComputerSystem on a BMC.
+func (c *client) GetSystems(ctx context.Context) ([]System, error) {
+	return getMembers[System](ctx, c, "/Systems")
+}
+
+// GetManagers returns every Manager on a BMC.
+func (c *client) GetManagers(ctx context.Context) ([]Manager, error) {
+	return getMembers[Manager](ctx, c, "/Managers")
+}
+
+// GetChassis returns every Chassis on a BMC.
+func (c *client) GetChassis(ctx context.Context) ([]Chassis, error) {
+	return getMembers[Chassis](ctx, c, "/Chassis")
+}
+
+// GetTasks returns every task in the BMC's TaskService.
+func (c *client) GetTasks(ctx context.Context) ([]Task, error) {
+	return getMembers[Task](ctx, c, "/TaskService/Tasks")
+}
+
+// GetUpdateService returns the BMC's UpdateService resource.
+func (c *client) GetUpdateService(ctx context.Context) (UpdateService, error) {
+	var us UpdateService
+	err := c.getResource(ctx, "/UpdateService", &us)
+	return us, err
+}
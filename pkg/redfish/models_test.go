@@ -0,0 +1,113 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTypedModelsPassThrough(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/redfish/v1/Systems":
+			w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Systems/Node0"}]}`)) //nolint:errcheck
+		case "/redfish/v1/Systems/Node0":
+			_, _ = w.Write([]byte(`{
+				"@odata.id":"/redfish/v1/Systems/Node0",
+				"Id":"Node0",
+				"SerialNumber":"SN123",
+				"PowerState":"On",
+				"Status":{"State":"Enabled","Health":"OK"},
+				"ProcessorSummary":{"Count":2,"Model":"AMD EPYC"},
+				"Links":{"Chassis":[{"@odata.id":"/redfish/v1/Chassis/Blade0"}]},
+				"Oem":{"Hpe":{"Slot":3}},
+				"VendorExtra":{"Answer":42}
+			}`))
+		case "/redfish/v1/UpdateService":
+			_, _ = w.Write([]byte(`{
+				"@odata.id":"/redfish/v1/UpdateService",
+				"ServiceEnabled":true,
+				"Actions":{"#UpdateService.SimpleUpdate":{
+					"target":"/redfish/v1/UpdateService/Actions/SimpleUpdate",
+					"TransferProtocol@Redfish.AllowableValues":["HTTP","HTTPS"]}}
+			}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	c := newClient("example.com", "admin", "password", true, 0)
+	c.base = ts.URL + "/redfish/v1"
+	ctx := context.Background()
+
+	systems, err := getMembers[System](ctx, c, "/Systems")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(systems) != 1 {
+		t.Fatalf("got %d systems", len(systems))
+	}
+	s := systems[0]
+	if s.ID != "Node0" || s.SerialNumber != "SN123" || s.Status.Health != "OK" || s.ProcessorSummary.Count != 2 {
+		t.Errorf("unexpected system %+v", s)
+	}
+	if len(s.Links.Chassis) != 1 || s.Links.Chassis[0].ODataID != "/redfish/v1/Chassis/Blade0" {
+		t.Errorf("unexpected chassis links %+v", s.Links.Chassis)
+	}
+	var oem struct{ Slot int }
+	if err := s.OemFor("Hpe", &oem); err != nil || oem.Slot != 3 {
+		t.Errorf("OemFor: %+v, %v", oem, err)
+	}
+	var extra struct{ Answer int }
+	if err := s.Field("VendorExtra", &extra); err != nil || extra.Answer != 42 {
+		t.Errorf("Field: %+v, %v", extra, err)
+	}
+	if err := s.Field("Missing", &extra); err == nil {
+		t.Error("expected error for missing property")
+	}
+
+	var us UpdateService
+	if err := c.getResource(ctx, "/UpdateService", &us); err != nil {
+		t.Fatal(err)
+	}
+	if us.ServiceEnabled == nil || !*us.ServiceEnabled || len(us.Actions.SimpleUpdate.TransferProtocols) != 2 {
+		t.Errorf("unexpected update service %+v", us)
+	}
+}
+
+func TestSelectProtocol(t *testing.T) {
+	var us UpdateService
+	us.Actions.SimpleUpdate.TransferProtocols = []string{"HTTP", "SCP"}
+	us.HTTPPushURI = "/redfish/v1/UpdateService/upload"
+	tests := []struct {
+		requested, want string
+		wantErr         bool
+	}{
+		{"http", "HTTP", false},
+		{"auto", "HTTP", false},
+		{"", "HTTP", false},
+		{"SCP", "SCP", false},
+		{"HTTPS", "", true},
+	}
+	for _, tt := range tests {
+		got, err := us.SelectProtocol(tt.requested)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("SelectProtocol(%q) = %q, %v", tt.requested, got, err)
+		}
+		if err != nil && !strings.Contains(err.Error(), us.HTTPPushURI) {
+			t.Errorf("error should mention HttpPushUri: %v", err)
+		}
+	}
+	// Services that do not advertise allowable values accept anything.
+	if got, err := (UpdateService{}).SelectProtocol("HTTPS"); got != "HTTPS" || err != nil {
+		t.Errorf("unadvertised: %q, %v", got, err)
+	}
+}
@@ -0,0 +1,82 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// NICPreference ranks a system's interfaces when choosing its boot MAC. Each
+// pattern is matched against an interface's Id, Name and Description;
+// interfaces matching an earlier pattern are ranked first, ties broken by Id.
+type NICPreference []*regexp.Regexp
+
+// ParseNICPreference compiles patterns in order of preference.
+func ParseNICPreference(patterns []string) (NICPreference, error) {
+	var p NICPreference
+	for _, s := range patterns {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return nil, fmt.Errorf("nic preference %q: %w", s, err)
+		}
+		p = append(p, re)
+	}
+	return p, nil
+}
+
+// rank returns the index of the first pattern matching n, or len(p) if none does.
+func (p NICPreference) rank(n rfEthernetInterface) int {
+	for i, re := range p {
+		if re.MatchString(n.ID) || re.MatchString(n.Name) || re.MatchString(n.Description) {
+			return i
+		}
+	}
+	return len(p)
+}
+
+// bootableMACs returns the candidate boot MACs of a system, best first: interfaces
+// matching prefer, then the remaining bootable ones, falling back to the first
+// valid MAC when nothing else qualifies.
+func bootableMACs(nics []rfEthernetInterface, prefer NICPreference) []string {
+	var preferred, rest []rfEthernetInterface
+	for _, nic := range nics {
+		if !isValidMAC(nic.MACAddress) {
+			continue
+		}
+		if prefer.rank(nic) < len(prefer) {
+			preferred = append(preferred, nic)
+		} else {
+			rest = append(rest, nic)
+		}
+	}
+	sort.SliceStable(preferred, func(i, j int) bool {
+		ri, rj := prefer.rank(preferred[i]), prefer.rank(preferred[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return preferred[i].ID < preferred[j].ID
+	})
+
+	macs := make([]string, 0, len(nics))
+	for _, nic := range preferred {
+		macs = append(macs, strings.ToLower(nic.MACAddress))
+	}
+	for _, nic := range rest {
+		if isBootable(nic) {
+			macs = append(macs, strings.ToLower(nic.MACAddress))
+		}
+	}
+	if len(macs) == 0 && len(rest) > 0 {
+		macs = append(macs, strings.ToLower(rest[0].MACAddress))
+	}
+	return macs
+}
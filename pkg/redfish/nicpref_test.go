@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBootableMACsPreference(t *testing.T) {
+	nics := []rfEthernetInterface{
+		{ID: "HPCNet1", Name: "HPC Network", MACAddress: "AA:00:00:00:00:01"},
+		{ID: "ManagementEthernet", Description: "Management Ethernet", MACAddress: "aa:00:00:00:00:02"},
+		{ID: "HPCNet0", Name: "HPC Network", MACAddress: "aa:00:00:00:00:03"},
+		{ID: "Broken", MACAddress: "Not Available"},
+	}
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+	}{
+		{"no preference keeps BMC order", nil, []string{"aa:00:00:00:00:01", "aa:00:00:00:00:02", "aa:00:00:00:00:03"}},
+		{"description match first", []string{"Management Ethernet"}, []string{"aa:00:00:00:00:02", "aa:00:00:00:00:01", "aa:00:00:00:00:03"}},
+		{"ties broken by Id", []string{"HPC"}, []string{"aa:00:00:00:00:03", "aa:00:00:00:00:01", "aa:00:00:00:00:02"}},
+		{"ordered list", []string{"^HPCNet1$", "Management"}, []string{"aa:00:00:00:00:01", "aa:00:00:00:00:02", "aa:00:00:00:00:03"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefer, err := ParseNICPreference(tt.patterns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bootableMACs(nics, prefer); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if _, err := ParseNICPreference([]string{"("}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
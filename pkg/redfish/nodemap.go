@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var nodeID = regexp.MustCompile(`(?i)^node(\d+)$`)
+
+// SystemNodeNumber returns the node number carried by the Id of a system path:
+// /redfish/v1/Systems/Node1 -> 1. It reports false for Ids without one (Self,
+// 1, System.Embedded.1).
+func SystemNodeNumber(systemPath string) (int, bool) {
+	m := nodeID.FindStringSubmatch(path.Base(strings.TrimSuffix(systemPath, "/")))
+	if m == nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// NodeNumbers returns the node number of each system of one BMC, given in the
+// order its Systems collection lists them. When every Id carries a distinct
+// node number (Node0, Node1) those are used, so a BMC that lists Node1 first
+// does not swap the nodes; otherwise the position in the collection is.
+func NodeNumbers(systemPaths []string) []int {
+	out := make([]int, len(systemPaths))
+	seen := map[int]bool{}
+	for i, p := range systemPaths {
+		n, ok := SystemNodeNumber(p)
+		if !ok || seen[n] {
+			for i := range out {
+				out[i] = i
+			}
+			return out
+		}
+		seen[n] = true
+		out[i] = n
+	}
+	return out
+}
+
+// VerifySystemMapping checks that the members of a BMC's Systems collection are
+// listed in node order, which numbering nodes by position assumes. It returns
+// an error naming the first system whose Id carries a node number other than
+// its position, or the Ids that repeat a node number, and nil for BMCs whose
+// system Ids carry no node numbers.
+func VerifySystemMapping(systemPaths []string) error {
+	seen := map[int]string{}
+	for i, p := range systemPaths {
+		n, ok := SystemNodeNumber(p)
+		if !ok {
+			continue
+		}
+		if prev, dup := seen[n]; dup {
+			return fmt.Errorf("systems %s and %s both carry node number %d", path.Base(prev), path.Base(p), n)
+		}
+		seen[n] = p
+		if n != i {
+			return fmt.Errorf("system %s is listed as member %d of Systems", path.Base(p), i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNodeNumbers(t *testing.T) {
+	const sys = "/redfish/v1/Systems/"
+	tests := []struct {
+		name   string
+		paths  []string
+		want   []int
+		wantOK bool
+	}{
+		{"in order", []string{sys + "Node0", sys + "Node1"}, []int{0, 1}, true},
+		{"swapped", []string{sys + "Node1", sys + "Node0"}, []int{1, 0}, false},
+		{"single node1", []string{sys + "Node1"}, []int{1}, false},
+		{"no node ids", []string{sys + "Self"}, []int{0}, true},
+		{"numeric ids", []string{sys + "1", sys + "2"}, []int{0, 1}, true},
+		{"duplicate", []string{sys + "Node0", sys + "node0"}, []int{0, 1}, false},
+		{"mixed", []string{sys + "Node1", sys + "Self"}, []int{0, 1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NodeNumbers(tt.paths); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NodeNumbers = %v, want %v", got, tt.want)
+			}
+			if err := VerifySystemMapping(tt.paths); (err == nil) != tt.wantOK {
+				t.Errorf("VerifySystemMapping = %v", err)
+			}
+		})
+	}
+}
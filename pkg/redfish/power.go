@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResetManager resets the BMC itself, e.g. with resetType "GracefulRestart" or
+// "ForceRestart". The BMC is unreachable for a while afterwards.
+func (c *client) ResetManager(ctx context.Context, resetType string) error {
+	return c.post(ctx, "/Managers/BMC/Actions/Manager.Reset", map[string]any{"ResetType": resetType})
+}
+
+// ResetSystem resets the system at systemPath (every system on the BMC when
+// empty), e.g. with resetType "GracefulRestart", "ForceRestart" or "On".
+func (c *client) ResetSystem(ctx context.Context, systemPath, resetType string) error {
+	paths := []string{systemPath}
+	if systemPath == "" {
+		var err error
+		if paths, err = c.listSystemPaths(ctx); err != nil {
+			return err
+		}
+	}
+	for _, p := range paths {
+		if err := c.post(ctx, p+"/Actions/ComputerSystem.Reset", map[string]any{"ResetType": resetType}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResetChassis resets the chassis at chassisPath, e.g. a blade slot of an EX
+// chassis controller (/redfish/v1/Chassis/Blade3) with resetType "On" or
+// "ForceOff". The Chassis.Reset target the chassis advertises is used when it
+// has one.
+func (c *client) ResetChassis(ctx context.Context, chassisPath, resetType string) error {
+	var ch Chassis
+	if err := c.getResource(ctx, chassisPath, &ch); err != nil {
+		return err
+	}
+	target := strings.TrimSuffix(chassisPath, "/") + "/Actions/Chassis.Reset"
+	if a, ok := ch.Actions["#Chassis.Reset"]; ok && a.Target != "" {
+		target = a.Target
+	}
+	return c.post(ctx, target, map[string]any{"ResetType": resetType})
+}
+
+// BladeSlot returns the chassis of blade slot n among the chassis of a chassis
+// controller (see SlotNumber). A member with Id "Blade<n>" wins over one that is
+// only located in slot n.
+func BladeSlot(chassis []Chassis, n int) (Chassis, bool) {
+	var found *Chassis
+	for i, ch := range chassis {
+		if strings.EqualFold(ch.ID, fmt.Sprintf("Blade%d", n)) {
+			return ch, true
+		}
+		if m, ok := SlotNumber(ch); ok && m == n && found == nil {
+			found = &chassis[i]
+		}
+	}
+	if found == nil {
+		return Chassis{}, false
+	}
+	return *found, true
+}
+
+// SlotNumber returns the blade slot a chassis occupies: N for Id "BladeN", else
+// the location ordinal of a Blade chassis located in a slot.
+func SlotNumber(ch Chassis) (int, bool) {
+	if id := strings.ToLower(ch.ID); strings.HasPrefix(id, "blade") {
+		if n, err := strconv.Atoi(id[len("blade"):]); err == nil {
+			return n, true
+		}
+	}
+	if loc := ch.Location.PartLocation; ch.ChassisType == "Blade" && loc.LocationType == "Slot" {
+		return loc.LocationOrdinalValue, true
+	}
+	return 0, false
+}
+
+// ResetManager calls Client.ResetManager on a new client for host.
+func ResetManager(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, resetType string) error {
+	return newClient(host, user, pass, insecure, timeout).ResetManager(ctx, resetType)
+}
+
+// ResetSystem calls Client.ResetSystem on a new client for host.
+func ResetSystem(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, systemPath, resetType string) error {
+	return newClient(host, user, pass, insecure, timeout).ResetSystem(ctx, systemPath, resetType)
+}
+
+// ResetChassis calls Client.ResetChassis on a new client for host.
+func ResetChassis(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, chassisPath, resetType string) error {
+	return newClient(host, user, pass, insecure, timeout).ResetChassis(ctx, chassisPath, resetType)
+}
@@ -0,0 +1,76 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestResetChassis(t *testing.T) {
+	docs := map[string]string{
+		"/redfish/v1/Chassis/Blade3": `{"@odata.id":"/redfish/v1/Chassis/Blade3","Id":"Blade3",
+			"Actions":{"#Chassis.Reset":{"target":"/redfish/v1/Chassis/Blade3/Actions/Oem/Chassis.Reset"}}}`,
+		"/redfish/v1/Chassis/Blade4": `{"@odata.id":"/redfish/v1/Chassis/Blade4","Id":"Blade4"}`,
+	}
+	posts := map[string]any{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			posts[r.URL.Path] = body["ResetType"]
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		body, ok := docs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	c := New(server.URL[len("https://"):], "user", "pass", true, 5*time.Second)
+	ctx := context.Background()
+
+	if err := c.ResetChassis(ctx, "/redfish/v1/Chassis/Blade3", "On"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ResetChassis(ctx, "/redfish/v1/Chassis/Blade4", "ForceOff"); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"/redfish/v1/Chassis/Blade3/Actions/Oem/Chassis.Reset": "On",
+		"/redfish/v1/Chassis/Blade4/Actions/Chassis.Reset":     "ForceOff",
+	}
+	if !reflect.DeepEqual(posts, want) {
+		t.Errorf("posts = %v, want %v", posts, want)
+	}
+	if err := c.ResetChassis(ctx, "/redfish/v1/Chassis/Blade9", "On"); err == nil {
+		t.Error("missing chassis: want error")
+	}
+}
+
+func TestBladeSlot(t *testing.T) {
+	chassis := []Chassis{
+		{Resource: Resource{ID: "Enclosure"}, ChassisType: "Enclosure"},
+		{Resource: Resource{ID: "Blade0"}, ChassisType: "Blade"},
+		{Resource: Resource{ID: "Slot-B"}, ChassisType: "Blade"},
+	}
+	chassis[2].Location.PartLocation.LocationType = "Slot"
+	chassis[2].Location.PartLocation.LocationOrdinalValue = 5
+
+	for n, want := range map[int]string{0: "Blade0", 5: "Slot-B", 3: ""} {
+		ch, ok := BladeSlot(chassis, n)
+		if ok != (want != "") || ch.ID != want {
+			t.Errorf("BladeSlot(%d) = %q, %v; want %q", n, ch.ID, ok, want)
+		}
+	}
+}
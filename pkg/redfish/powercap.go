@@ -0,0 +1,185 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PowerLimit is the power cap of one chassis, read from its EnvironmentMetrics
+// (PowerLimitWatts, Redfish 2020.4+) and PowerSubsystem, or from the older
+// Power resource (PowerControl[0].PowerLimit). Watts are nil when the BMC does
+// not report them.
+type PowerLimit struct {
+	ChassisPath string
+	ChassisID   string
+	ChassisType string   // e.g. Enclosure for an EX chassis, Blade, RackMount
+	Systems     []string // systems in the chassis (Links.ComputerSystems)
+	Path        string   // resource the limit is written to
+	Legacy      bool     // Path is the deprecated Power resource
+	Limit       *float64 // nil when no cap is set
+	Min, Max    *float64 // allowable limits
+	Consumed    *float64
+	Capacity    *float64
+}
+
+type rfControl struct {
+	SetPoint     *float64 `json:"SetPoint"`
+	AllowableMin *float64 `json:"AllowableMin"`
+	AllowableMax *float64 `json:"AllowableMax"`
+	ControlMode  string   `json:"ControlMode"`
+}
+
+type rfEnvironmentMetrics struct {
+	PowerLimitWatts *rfControl `json:"PowerLimitWatts"`
+	PowerWatts      struct {
+		Reading *float64 `json:"Reading"`
+	} `json:"PowerWatts"`
+}
+
+type rfPower struct {
+	PowerControl []struct {
+		PowerConsumedWatts *float64 `json:"PowerConsumedWatts"`
+		PowerCapacityWatts *float64 `json:"PowerCapacityWatts"`
+		PowerLimit         struct {
+			LimitInWatts *float64 `json:"LimitInWatts"`
+		} `json:"PowerLimit"`
+	} `json:"PowerControl"`
+}
+
+// GetPowerLimits returns the power cap of every chassis that has one. Chassis
+// exposing neither PowerLimitWatts nor PowerControl are skipped.
+func (c *client) GetPowerLimits(ctx context.Context) ([]PowerLimit, error) {
+	chassis, err := c.GetChassis(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []PowerLimit
+	for _, ch := range chassis {
+		l, ok, err := c.powerLimit(ctx, ch)
+		if err != nil {
+			return nil, fmt.Errorf("chassis %s: %w", ch.ID, err)
+		}
+		if ok {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+func (c *client) powerLimit(ctx context.Context, ch Chassis) (PowerLimit, bool, error) {
+	l := PowerLimit{ChassisPath: ch.ODataID, ChassisID: ch.ID, ChassisType: ch.ChassisType}
+	for _, s := range ch.Links.ComputerSystems {
+		l.Systems = append(l.Systems, s.ODataID)
+	}
+	if ch.EnvironmentMetrics.ODataID != "" {
+		var m rfEnvironmentMetrics
+		err := c.get(ctx, ch.EnvironmentMetrics.ODataID, &m)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return l, false, err
+		}
+		if err == nil && m.PowerLimitWatts != nil {
+			l.Path = ch.EnvironmentMetrics.ODataID
+			if m.PowerLimitWatts.ControlMode != "Disabled" {
+				l.Limit = m.PowerLimitWatts.SetPoint
+			}
+			l.Min, l.Max, l.Consumed = m.PowerLimitWatts.AllowableMin, m.PowerLimitWatts.AllowableMax, m.PowerWatts.Reading
+			if ch.PowerSubsystem.ODataID != "" {
+				var ps struct {
+					CapacityWatts *float64 `json:"CapacityWatts"`
+				}
+				if err := c.get(ctx, ch.PowerSubsystem.ODataID, &ps); err != nil && !errors.Is(err, ErrNotFound) {
+					return l, false, err
+				}
+				l.Capacity = ps.CapacityWatts
+			}
+			return l, true, nil
+		}
+	}
+	if ch.Power.ODataID == "" {
+		return l, false, nil
+	}
+	var p rfPower
+	if err := c.get(ctx, ch.Power.ODataID, &p); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return l, false, nil
+		}
+		return l, false, err
+	}
+	if len(p.PowerControl) == 0 {
+		return l, false, nil
+	}
+	pc := p.PowerControl[0]
+	l.Path, l.Legacy = ch.Power.ODataID, true
+	l.Limit, l.Consumed, l.Capacity = pc.PowerLimit.LimitInWatts, pc.PowerConsumedWatts, pc.PowerCapacityWatts
+	return l, true, nil
+}
+
+// SetPowerLimit caps the chassis at chassisPath to watts, or removes its cap
+// when watts is 0. A limit outside the range the chassis allows is refused
+// before anything is sent.
+func (c *client) SetPowerLimit(ctx context.Context, chassisPath string, watts float64) error {
+	if watts < 0 {
+		return fmt.Errorf("%s: negative power limit %g W", chassisPath, watts)
+	}
+	var ch Chassis
+	if err := c.getResource(ctx, chassisPath, &ch); err != nil {
+		return err
+	}
+	if ch.ODataID == "" {
+		ch.ODataID = chassisPath
+	}
+	l, ok, err := c.powerLimit(ctx, ch)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s: no power limit control", chassisPath)
+	}
+	if watts > 0 && ((l.Min != nil && watts < *l.Min) || (l.Max != nil && watts > *l.Max)) {
+		return fmt.Errorf("%s: power limit %g W is outside the allowed %s", chassisPath, watts, l.Range())
+	}
+	var body map[string]any
+	switch {
+	case l.Legacy:
+		var limit any
+		if watts > 0 {
+			limit = watts
+		}
+		body = map[string]any{"PowerControl": []any{map[string]any{"PowerLimit": map[string]any{"LimitInWatts": limit}}}}
+	case watts > 0:
+		body = map[string]any{"PowerLimitWatts": map[string]any{"SetPoint": watts, "ControlMode": "Automatic"}}
+	default:
+		body = map[string]any{"PowerLimitWatts": map[string]any{"ControlMode": "Disabled"}}
+	}
+	return c.patch(ctx, l.Path, body)
+}
+
+// Range formats the allowable limits, e.g. "200-900 W".
+func (l PowerLimit) Range() string {
+	w := func(v *float64) string {
+		if v == nil {
+			return "?"
+		}
+		return fmt.Sprintf("%g", *v)
+	}
+	if l.Min == nil && l.Max == nil {
+		return "any"
+	}
+	return w(l.Min) + "-" + w(l.Max) + " W"
+}
+
+// GetPowerLimits calls Client.GetPowerLimits on a new client for host.
+func GetPowerLimits(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration) ([]PowerLimit, error) {
+	return newClient(host, user, pass, insecure, timeout).GetPowerLimits(ctx)
+}
+
+// SetPowerLimit calls Client.SetPowerLimit on a new client for host.
+func SetPowerLimit(ctx context.Context, host, user, pass string, insecure bool, timeout time.Duration, chassisPath string, watts float64) error {
+	return newClient(host, user, pass, insecure, timeout).SetPowerLimit(ctx, chassisPath, watts)
+}
@@ -0,0 +1,89 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPowerLimits(t *testing.T) {
+	docs := map[string]string{
+		"/redfish/v1/Chassis": `{"Members":[{"@odata.id":"/redfish/v1/Chassis/Enclosure"},{"@odata.id":"/redfish/v1/Chassis/Node0"},{"@odata.id":"/redfish/v1/Chassis/Blade0"}]}`,
+		"/redfish/v1/Chassis/Enclosure": `{"@odata.id":"/redfish/v1/Chassis/Enclosure","Id":"Enclosure","ChassisType":"Enclosure",
+			"EnvironmentMetrics":{"@odata.id":"/redfish/v1/Chassis/Enclosure/EnvironmentMetrics"},
+			"PowerSubsystem":{"@odata.id":"/redfish/v1/Chassis/Enclosure/PowerSubsystem"}}`,
+		"/redfish/v1/Chassis/Enclosure/EnvironmentMetrics": `{"PowerWatts":{"Reading":41000},
+			"PowerLimitWatts":{"SetPoint":60000,"AllowableMin":20000,"AllowableMax":90000,"ControlMode":"Disabled"}}`,
+		"/redfish/v1/Chassis/Enclosure/PowerSubsystem": `{"CapacityWatts":96000}`,
+		"/redfish/v1/Chassis/Node0": `{"@odata.id":"/redfish/v1/Chassis/Node0","Id":"Node0","ChassisType":"Blade",
+			"Power":{"@odata.id":"/redfish/v1/Chassis/Node0/Power"},
+			"Links":{"ComputerSystems":[{"@odata.id":"/redfish/v1/Systems/Node0"}]}}`,
+		"/redfish/v1/Chassis/Node0/Power": `{"PowerControl":[{"PowerConsumedWatts":412,"PowerLimit":{"LimitInWatts":600}}]}`,
+		"/redfish/v1/Chassis/Blade0":      `{"@odata.id":"/redfish/v1/Chassis/Blade0","Id":"Blade0","ChassisType":"Blade"}`,
+	}
+	patches := map[string]string{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			var body any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			b, _ := json.Marshal(body)
+			patches[r.URL.Path] = string(b)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		body, ok := docs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	c := New(server.URL[len("https://"):], "user", "pass", true, 5*time.Second)
+	ctx := context.Background()
+
+	limits, err := c.GetPowerLimits(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := func(v float64) *float64 { return &v }
+	want := []PowerLimit{
+		{ChassisPath: "/redfish/v1/Chassis/Enclosure", ChassisID: "Enclosure", ChassisType: "Enclosure",
+			Path: "/redfish/v1/Chassis/Enclosure/EnvironmentMetrics", Min: f(20000), Max: f(90000), Consumed: f(41000), Capacity: f(96000)},
+		{ChassisPath: "/redfish/v1/Chassis/Node0", ChassisID: "Node0", ChassisType: "Blade", Systems: []string{"/redfish/v1/Systems/Node0"},
+			Path: "/redfish/v1/Chassis/Node0/Power", Legacy: true, Limit: f(600), Consumed: f(412)},
+	}
+	if !reflect.DeepEqual(limits, want) {
+		t.Errorf("limits:\n got %+v\nwant %+v", limits, want)
+	}
+
+	if err := c.SetPowerLimit(ctx, "/redfish/v1/Chassis/Enclosure", 50000); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetPowerLimit(ctx, "/redfish/v1/Chassis/Node0", 0); err != nil {
+		t.Fatal(err)
+	}
+	wantPatches := map[string]string{
+		"/redfish/v1/Chassis/Enclosure/EnvironmentMetrics": `{"PowerLimitWatts":{"ControlMode":"Automatic","SetPoint":50000}}`,
+		"/redfish/v1/Chassis/Node0/Power":                  `{"PowerControl":[{"PowerLimit":{"LimitInWatts":null}}]}`,
+	}
+	if !reflect.DeepEqual(patches, wantPatches) {
+		t.Errorf("patches = %v, want %v", patches, wantPatches)
+	}
+
+	if err := c.SetPowerLimit(ctx, "/redfish/v1/Chassis/Enclosure", 100000); err == nil || !strings.Contains(err.Error(), "20000-90000 W") {
+		t.Errorf("limit above AllowableMax: %v", err)
+	}
+	if err := c.SetPowerLimit(ctx, "/redfish/v1/Chassis/Blade0", 500); err == nil {
+		t.Error("chassis without a power limit: want error")
+	}
+}
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UpdateProgress is how far a running firmware update task has come.
+type UpdateProgress struct {
+	Task            string    // @odata.id of the task
+	Name            string    // task Name
+	State           string    // TaskState
+	PercentComplete *int      // nil when neither the task nor its Oem reports it
+	Phase           string    // downloading, verifying or installing; "" when unknown
+	StartTime       time.Time // zero when not reported
+}
+
+// GetUpdateProgress returns the progress of the running firmware update tasks
+// (see GetActiveUpdateTasks). PercentComplete comes from the task, its task
+// monitor or vendor Oem properties such as Oem.Dell.PercentComplete; the phase
+// is read from Oem status properties and the task's latest message.
+func (c *client) GetUpdateProgress(ctx context.Context) ([]UpdateProgress, error) {
+	var out []UpdateProgress
+	for b, err := range c.members(ctx, "/TaskService/Tasks") {
+		var me *memberError
+		if errors.As(err, &me) {
+			continue // skip tasks we can't fetch
+		}
+		if err != nil {
+			return nil, err
+		}
+		var t Task
+		if json.Unmarshal(b, &t) != nil {
+			continue
+		}
+		t.setRaw(b)
+		if !activeUpdateTask(t.TaskState, t.Name, taskText(t)) {
+			continue
+		}
+		p := taskProgress(t)
+		if p.PercentComplete == nil && t.TaskMonitor != "" {
+			// The monitor of a running task answers with the task as it is now
+			var mt Task
+			if mb, err := c.getRaw(ctx, t.TaskMonitor); err == nil && json.Unmarshal(mb, &mt) == nil {
+				mt.setRaw(mb)
+				mp := taskProgress(mt)
+				p.PercentComplete = mp.PercentComplete
+				if mp.Phase != "" {
+					p.Phase = mp.Phase
+				}
+			}
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// taskProgress reads the progress of t from its own properties.
+func taskProgress(t Task) UpdateProgress {
+	p := UpdateProgress{Task: t.ODataID, Name: t.Name, State: t.TaskState, PercentComplete: t.PercentComplete}
+	if st, err := time.Parse(time.RFC3339, t.StartTime); err == nil {
+		p.StartTime = st
+	}
+	var oemTexts []string
+	for _, vendor := range slices.Sorted(maps.Keys(t.Oem)) {
+		var v any
+		if json.Unmarshal(t.Oem[vendor], &v) == nil {
+			walkOem(v, "", &p.PercentComplete, &oemTexts)
+		}
+	}
+	texts := oemTexts
+	for i := len(t.Messages) - 1; i >= 0; i-- {
+		texts = append(texts, t.Messages[i].Message)
+	}
+	var msg string
+	_ = t.Field("Message", &msg)
+	p.Phase = updatePhase(append(texts, msg, t.Name)...)
+	return p
+}
+
+// taskText is the nonstandard Message of t followed by its Messages.
+func taskText(t Task) string {
+	var msg string
+	_ = t.Field("Message", &msg)
+	for _, m := range t.Messages {
+		msg += " " + m.Message
+	}
+	return msg
+}
+
+// walkOem collects from an Oem object the first percentage found under a key
+// mentioning percent or progress, unless percent is already set, and the
+// strings under keys naming a state, status, phase, stage or message.
+func walkOem(v any, key string, percent **int, texts *[]string) {
+	k := strings.ToLower(key)
+	switch v := v.(type) {
+	case map[string]any:
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			walkOem(v[name], name, percent, texts)
+		}
+	case []any:
+		for _, sub := range v {
+			walkOem(sub, key, percent, texts)
+		}
+	case float64:
+		if *percent == nil && (strings.Contains(k, "percent") || strings.Contains(k, "progress")) && v >= 0 && v <= 100 {
+			n := int(v)
+			*percent = &n
+		}
+	case string:
+		if *percent == nil && (strings.Contains(k, "percent") || strings.Contains(k, "progress")) {
+			if n, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(v), "%"))); err == nil && n >= 0 && n <= 100 {
+				*percent = &n
+				return
+			}
+		}
+		for _, w := range []string{"state", "status", "phase", "stage", "message"} {
+			if strings.Contains(k, w) {
+				*texts = append(*texts, v)
+				break
+			}
+		}
+	}
+}
+
+// phaseWords maps the words BMCs use while updating to the phase they name.
+var phaseWords = regexp.MustCompile(`(?i)\b(downloading|transferring|uploading|staging|verifying|validating|authenticating|installing|flashing|writing|programming|applying|updating)\b`)
+
+// updatePhase returns the phase named by the first of texts that names one:
+// the first word of phaseWords in it decides.
+func updatePhase(texts ...string) string {
+	for _, t := range texts {
+		m := phaseWords.FindString(t)
+		switch strings.ToLower(m) {
+		case "":
+			continue
+		case "downloading", "transferring", "uploading", "staging":
+			return "downloading"
+		case "verifying", "validating", "authenticating":
+			return "verifying"
+		default:
+			return "installing"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetUpdateProgress(t *testing.T) {
+	const coll = "/redfish/v1/TaskService/Tasks"
+	bodies := map[string]string{
+		coll: `{"Members":[{"@odata.id":"` + coll + `/1"},{"@odata.id":"` + coll + `/2"},{"@odata.id":"` + coll + `/3"},{"@odata.id":"` + coll + `/4"}]}`,
+		// PercentComplete on the task itself
+		coll + "/1": `{"@odata.id":"` + coll + `/1","Id":"1","Name":"Firmware Update","TaskState":"Running","PercentComplete":45,
+			"StartTime":"2025-07-01T02:00:00Z","Messages":[{"Message":"Image downloaded"},{"Message":"Installing BMC image"}]}`,
+		// Only the task monitor reports it
+		coll + "/2":                  `{"@odata.id":"` + coll + `/2","Id":"2","Name":"Firmware Update","TaskState":"Running","TaskMonitor":"/redfish/v1/TaskMonitors/2"}`,
+		"/redfish/v1/TaskMonitors/2": `{"Id":"2","TaskState":"Running","PercentComplete":80,"Messages":[{"Message":"Verifying image signature"}]}`,
+		// Vendor Oem progress
+		coll + "/3": `{"@odata.id":"` + coll + `/3","Id":"3","Name":"Update job","TaskState":"Running",
+			"Oem":{"Dell":{"JobState":"Downloading","PercentComplete":"30%"}}}`,
+		coll + "/4": `{"@odata.id":"` + coll + `/4","Id":"4","Name":"Firmware Update","TaskState":"Completed","PercentComplete":100}`,
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, ok := bodies[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Path == "/redfish/v1/TaskMonitors/2" {
+			w.WriteHeader(http.StatusAccepted)
+		}
+		_, _ = w.Write([]byte(b))
+	}))
+	defer ts.Close()
+	c := newClient("example.com", "admin", "password", true, 0)
+	c.base = ts.URL + "/redfish/v1"
+
+	got, err := c.GetUpdateProgress(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		percent int
+		phase   string
+	}{{45, "installing"}, {80, "verifying"}, {30, "downloading"}}
+	if len(got) != len(want) {
+		t.Fatalf("got %d task(s), want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].PercentComplete == nil || *got[i].PercentComplete != w.percent || got[i].Phase != w.phase {
+			t.Errorf("task %s: percent %v phase %q, want %d %q", got[i].Task, got[i].PercentComplete, got[i].Phase, w.percent, w.phase)
+		}
+	}
+	if got[0].StartTime.IsZero() {
+		t.Error("StartTime not parsed")
+	}
+}
+
+func TestUpdatePhase(t *testing.T) {
+	for _, tc := range []struct {
+		texts []string
+		want  string
+	}{
+		{[]string{"Downloading image for installation"}, "downloading"},
+		{[]string{"Image downloaded, verifying"}, "verifying"},
+		{[]string{"Flashing SPI", "Downloading"}, "installing"},
+		{[]string{"", "Task in progress"}, ""},
+	} {
+		if got := updatePhase(tc.texts...); got != tc.want {
+			t.Errorf("updatePhase(%q) = %q, want %q", tc.texts, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfishtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// SimSystem is one ComputerSystem of a simulated BMC.
+type SimSystem struct {
+	ID   string   // e.g. Node0
+	MACs []string // one EthernetInterface per MAC, eth0, eth1, ...
+}
+
+// SimBMC is an http.Handler serving the Redfish resources discovery reads
+// (Systems, their EthernetInterfaces, and Managers) for a BMC managing any
+// number of systems, so tests can run the real client against blades, riser
+// cards and partitioned servers alike. Systems are listed in slice order.
+type SimBMC struct {
+	Systems []SimSystem
+}
+
+// NewSimBMC returns a BMC with n systems, Node0 to Node<n-1>, each with one
+// interface whose MAC is macPrefix followed by the node number, e.g.
+// "aa:00:00:00:01" gives aa:00:00:00:01:00, aa:00:00:00:01:01, ...
+func NewSimBMC(n int, macPrefix string) *SimBMC {
+	b := &SimBMC{}
+	for i := range n {
+		b.Systems = append(b.Systems, SimSystem{ID: fmt.Sprintf("Node%d", i), MACs: []string{fmt.Sprintf("%s:%02x", macPrefix, i)}})
+	}
+	return b
+}
+
+func (b *SimBMC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	const systems = "/redfish/v1/Systems"
+	p := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case p == systems:
+		var ids []string
+		for _, s := range b.Systems {
+			ids = append(ids, systems+"/"+s.ID)
+		}
+		writeCollection(w, ids)
+		return
+	case p == "/redfish/v1/Managers":
+		writeCollection(w, nil)
+		return
+	case strings.HasPrefix(p, systems+"/"):
+		id, rest, _ := strings.Cut(strings.TrimPrefix(p, systems+"/"), "/")
+		for _, s := range b.Systems {
+			if s.ID == id {
+				s.serve(w, systems+"/"+id, rest)
+				return
+			}
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// serve answers for the system at sysPath; rest is the path below it.
+func (s SimSystem) serve(w http.ResponseWriter, sysPath, rest string) {
+	nics := sysPath + "/EthernetInterfaces"
+	switch {
+	case rest == "":
+		writeJSON(w, map[string]any{"@odata.id": sysPath, "Id": s.ID, "Name": s.ID, "Model": "Simulated", "PowerState": "On"})
+		return
+	case rest == "EthernetInterfaces":
+		var ids []string
+		for i := range s.MACs {
+			ids = append(ids, fmt.Sprintf("%s/eth%d", nics, i))
+		}
+		writeCollection(w, ids)
+		return
+	case strings.HasPrefix(rest, "EthernetInterfaces/eth"):
+		var i int
+		if _, err := fmt.Sscanf(strings.TrimPrefix(rest, "EthernetInterfaces/eth"), "%d", &i); err == nil && i >= 0 && i < len(s.MACs) {
+			writeJSON(w, map[string]any{
+				"@odata.id": fmt.Sprintf("%s/eth%d", nics, i), "Id": fmt.Sprintf("eth%d", i),
+				"MACAddress": s.MACs[i], "InterfaceEnabled": true, "LinkStatus": "LinkUp",
+			})
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func writeCollection(w http.ResponseWriter, ids []string) {
+	members := make([]map[string]string, 0, len(ids))
+	for _, id := range ids {
+		members = append(members, map[string]string{"@odata.id": id})
+	}
+	writeJSON(w, map[string]any{"Members": members, "Members@odata.count": len(members)})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v) //nolint:errcheck
+}
@@ -0,0 +1,446 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfishtest
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"bootstrap/pkg/redfish"
+)
+
+// ErrNotMocked is returned by MockClient methods whose function field is nil.
+var ErrNotMocked = errors.New("redfishtest: method not mocked")
+
+// MockClient implements redfish.Client with one optional function field per
+// method, so command-layer code can be tested without a network server. Calls
+// records the names of the methods invoked, in order.
+type MockClient struct {
+	DiscoverAllBootableMACsFunc func(ctx context.Context, prefer redfish.NICPreference) ([]redfish.SystemMACs, error)
+	DiscoverBootableMACsFunc    func(ctx context.Context) ([]string, error)
+	DiscoverManagerNICsFunc     func(ctx context.Context) ([]redfish.ManagerNIC, error)
+	DiscoverSystemNICsFunc      func(ctx context.Context) ([]redfish.SystemNIC, error)
+	GetSystemsFunc              func(ctx context.Context) ([]redfish.System, error)
+	GetManagersFunc             func(ctx context.Context) ([]redfish.Manager, error)
+	GetChassisFunc              func(ctx context.Context) ([]redfish.Chassis, error)
+	GetEnvironmentFunc          func(ctx context.Context) (redfish.Environment, error)
+	GetConsolesFunc             func(ctx context.Context) ([]redfish.Console, error)
+	GetFirmwareInventoryFunc    func(ctx context.Context, target string) (redfish.FirmwareInventory, error)
+	GetFirmwareInventoriesFunc  func(ctx context.Context, targets []string, concurrency int) (map[string]redfish.FirmwareInventory, error)
+	GetUpdateServiceStatusFunc  func(ctx context.Context) (redfish.UpdateServiceStatus, error)
+	GetUpdateServiceFunc        func(ctx context.Context) (redfish.UpdateService, error)
+	GetActiveUpdateTasksFunc    func(ctx context.Context) ([]string, error)
+	GetUpdateProgressFunc       func(ctx context.Context) ([]redfish.UpdateProgress, error)
+	GetTasksFunc                func(ctx context.Context) ([]redfish.Task, error)
+	GetMessageRegistryFunc      func(ctx context.Context, messageID string) (redfish.MessageRegistry, error)
+	ListFirmwareFunc            func(ctx context.Context) ([]redfish.FirmwareVersion, error)
+	ListFirmwareInventoryFunc   func(ctx context.Context) ([]redfish.FirmwareComponent, error)
+	SimpleUpdateFunc            func(ctx context.Context, imageURI string, targets []string, transferProtocol string, expectedVersion string, force bool) (redfish.UpdateResult, error)
+	GetAuthorizedKeysFunc       func(ctx context.Context) (string, error)
+	SetAuthorizedKeysFunc       func(ctx context.Context, authorizedKey string) error
+	GetNTPServersFunc           func(ctx context.Context) ([]string, error)
+	SetNTPServersFunc           func(ctx context.Context, servers []string) error
+	GetNetworkProtocolFunc      func(ctx context.Context) (redfish.NetworkProtocol, error)
+	SetHostNameFunc             func(ctx context.Context, hostName, fqdn string) error
+	GetSyslogFunc               func(ctx context.Context) (redfish.SyslogConfig, error)
+	SetSyslogFunc               func(ctx context.Context, targets []redfish.SyslogTarget) error
+	SendSyslogTestFunc          func(ctx context.Context, message string) error
+	GetBiosAttributesFunc       func(ctx context.Context) ([]redfish.SystemBios, error)
+	SetBiosAttributesFunc       func(ctx context.Context, systemPath string, attrs map[string]any) error
+	GetAccountsFunc             func(ctx context.Context) ([]redfish.Account, error)
+	GetLocatorsFunc             func(ctx context.Context) ([]redfish.Locator, error)
+	SetLocatorFunc              func(ctx context.Context, systemPath string, on bool) error
+	GetPowerLimitsFunc          func(ctx context.Context) ([]redfish.PowerLimit, error)
+	SetPowerLimitFunc           func(ctx context.Context, chassisPath string, watts float64) error
+	ResetManagerFunc            func(ctx context.Context, resetType string) error
+	ResetSystemFunc             func(ctx context.Context, systemPath, resetType string) error
+	ResetChassisFunc            func(ctx context.Context, chassisPath, resetType string) error
+
+	mu    sync.Mutex
+	Calls []string
+}
+
+var _ redfish.Client = (*MockClient)(nil)
+
+func (m *MockClient) record(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Calls = append(m.Calls, name)
+}
+
+// DiscoverAllBootableMACs calls DiscoverAllBootableMACsFunc.
+func (m *MockClient) DiscoverAllBootableMACs(ctx context.Context, prefer redfish.NICPreference) ([]redfish.SystemMACs, error) {
+	m.record("DiscoverAllBootableMACs")
+	if m.DiscoverAllBootableMACsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.DiscoverAllBootableMACsFunc(ctx, prefer)
+}
+
+// DiscoverManagerNICs calls DiscoverManagerNICsFunc.
+func (m *MockClient) DiscoverManagerNICs(ctx context.Context) ([]redfish.ManagerNIC, error) {
+	m.record("DiscoverManagerNICs")
+	if m.DiscoverManagerNICsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.DiscoverManagerNICsFunc(ctx)
+}
+
+// DiscoverSystemNICs calls DiscoverSystemNICsFunc.
+func (m *MockClient) DiscoverSystemNICs(ctx context.Context) ([]redfish.SystemNIC, error) {
+	m.record("DiscoverSystemNICs")
+	if m.DiscoverSystemNICsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.DiscoverSystemNICsFunc(ctx)
+}
+
+// DiscoverBootableMACs calls DiscoverBootableMACsFunc.
+func (m *MockClient) DiscoverBootableMACs(ctx context.Context) ([]string, error) {
+	m.record("DiscoverBootableMACs")
+	if m.DiscoverBootableMACsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.DiscoverBootableMACsFunc(ctx)
+}
+
+// GetSystems calls GetSystemsFunc.
+func (m *MockClient) GetSystems(ctx context.Context) ([]redfish.System, error) {
+	m.record("GetSystems")
+	if m.GetSystemsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetSystemsFunc(ctx)
+}
+
+// GetManagers calls GetManagersFunc.
+func (m *MockClient) GetManagers(ctx context.Context) ([]redfish.Manager, error) {
+	m.record("GetManagers")
+	if m.GetManagersFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetManagersFunc(ctx)
+}
+
+// GetChassis calls GetChassisFunc.
+func (m *MockClient) GetChassis(ctx context.Context) ([]redfish.Chassis, error) {
+	m.record("GetChassis")
+	if m.GetChassisFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetChassisFunc(ctx)
+}
+
+// GetEnvironment calls GetEnvironmentFunc.
+func (m *MockClient) GetEnvironment(ctx context.Context) (redfish.Environment, error) {
+	m.record("GetEnvironment")
+	if m.GetEnvironmentFunc == nil {
+		return redfish.Environment{}, ErrNotMocked
+	}
+	return m.GetEnvironmentFunc(ctx)
+}
+
+// GetConsoles calls GetConsolesFunc.
+func (m *MockClient) GetConsoles(ctx context.Context) ([]redfish.Console, error) {
+	m.record("GetConsoles")
+	if m.GetConsolesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetConsolesFunc(ctx)
+}
+
+// GetFirmwareInventory calls GetFirmwareInventoryFunc.
+func (m *MockClient) GetFirmwareInventory(ctx context.Context, target string) (redfish.FirmwareInventory, error) {
+	m.record("GetFirmwareInventory")
+	if m.GetFirmwareInventoryFunc == nil {
+		return redfish.FirmwareInventory{}, ErrNotMocked
+	}
+	return m.GetFirmwareInventoryFunc(ctx, target)
+}
+
+// GetFirmwareInventories calls GetFirmwareInventoriesFunc, or without it
+// GetFirmwareInventory for each target like the real client does.
+func (m *MockClient) GetFirmwareInventories(ctx context.Context, targets []string, concurrency int) (map[string]redfish.FirmwareInventory, error) {
+	m.record("GetFirmwareInventories")
+	if m.GetFirmwareInventoriesFunc != nil {
+		return m.GetFirmwareInventoriesFunc(ctx, targets, concurrency)
+	}
+	out := map[string]redfish.FirmwareInventory{}
+	failed := redfish.TargetErrors{}
+	for _, t := range targets {
+		inv, err := m.GetFirmwareInventory(ctx, t)
+		if err != nil {
+			failed[t] = err
+			continue
+		}
+		out[t] = inv
+	}
+	if len(failed) > 0 {
+		return out, failed
+	}
+	return out, nil
+}
+
+// GetUpdateServiceStatus calls GetUpdateServiceStatusFunc.
+func (m *MockClient) GetUpdateServiceStatus(ctx context.Context) (redfish.UpdateServiceStatus, error) {
+	m.record("GetUpdateServiceStatus")
+	if m.GetUpdateServiceStatusFunc == nil {
+		return redfish.UpdateServiceStatus{}, ErrNotMocked
+	}
+	return m.GetUpdateServiceStatusFunc(ctx)
+}
+
+// GetUpdateService calls GetUpdateServiceFunc.
+func (m *MockClient) GetUpdateService(ctx context.Context) (redfish.UpdateService, error) {
+	m.record("GetUpdateService")
+	if m.GetUpdateServiceFunc == nil {
+		return redfish.UpdateService{}, ErrNotMocked
+	}
+	return m.GetUpdateServiceFunc(ctx)
+}
+
+// GetActiveUpdateTasks calls GetActiveUpdateTasksFunc.
+func (m *MockClient) GetActiveUpdateTasks(ctx context.Context) ([]string, error) {
+	m.record("GetActiveUpdateTasks")
+	if m.GetActiveUpdateTasksFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetActiveUpdateTasksFunc(ctx)
+}
+
+// GetUpdateProgress calls GetUpdateProgressFunc, or without it reports the
+// tasks of GetActiveUpdateTasks with unknown progress.
+func (m *MockClient) GetUpdateProgress(ctx context.Context) ([]redfish.UpdateProgress, error) {
+	m.record("GetUpdateProgress")
+	if m.GetUpdateProgressFunc != nil {
+		return m.GetUpdateProgressFunc(ctx)
+	}
+	tasks, err := m.GetActiveUpdateTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]redfish.UpdateProgress, 0, len(tasks))
+	for _, t := range tasks {
+		out = append(out, redfish.UpdateProgress{Task: t})
+	}
+	return out, nil
+}
+
+// GetTasks calls GetTasksFunc.
+func (m *MockClient) GetTasks(ctx context.Context) ([]redfish.Task, error) {
+	m.record("GetTasks")
+	if m.GetTasksFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetTasksFunc(ctx)
+}
+
+// GetMessageRegistry calls GetMessageRegistryFunc.
+func (m *MockClient) GetMessageRegistry(ctx context.Context, messageID string) (redfish.MessageRegistry, error) {
+	m.record("GetMessageRegistry")
+	if m.GetMessageRegistryFunc == nil {
+		return redfish.MessageRegistry{}, ErrNotMocked
+	}
+	return m.GetMessageRegistryFunc(ctx, messageID)
+}
+
+// ListFirmware calls ListFirmwareFunc.
+func (m *MockClient) ListFirmware(ctx context.Context) ([]redfish.FirmwareVersion, error) {
+	m.record("ListFirmware")
+	if m.ListFirmwareFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.ListFirmwareFunc(ctx)
+}
+
+// ListFirmwareInventory calls ListFirmwareInventoryFunc.
+func (m *MockClient) ListFirmwareInventory(ctx context.Context) ([]redfish.FirmwareComponent, error) {
+	m.record("ListFirmwareInventory")
+	if m.ListFirmwareInventoryFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.ListFirmwareInventoryFunc(ctx)
+}
+
+// SimpleUpdate calls SimpleUpdateFunc.
+func (m *MockClient) SimpleUpdate(ctx context.Context, imageURI string, targets []string, transferProtocol string, expectedVersion string, force bool) (redfish.UpdateResult, error) {
+	m.record("SimpleUpdate")
+	if m.SimpleUpdateFunc == nil {
+		return redfish.UpdateResult{}, ErrNotMocked
+	}
+	return m.SimpleUpdateFunc(ctx, imageURI, targets, transferProtocol, expectedVersion, force)
+}
+
+// GetAuthorizedKeys calls GetAuthorizedKeysFunc.
+func (m *MockClient) GetAuthorizedKeys(ctx context.Context) (string, error) {
+	m.record("GetAuthorizedKeys")
+	if m.GetAuthorizedKeysFunc == nil {
+		return "", ErrNotMocked
+	}
+	return m.GetAuthorizedKeysFunc(ctx)
+}
+
+// SetAuthorizedKeys calls SetAuthorizedKeysFunc.
+func (m *MockClient) SetAuthorizedKeys(ctx context.Context, authorizedKey string) error {
+	m.record("SetAuthorizedKeys")
+	if m.SetAuthorizedKeysFunc == nil {
+		return ErrNotMocked
+	}
+	return m.SetAuthorizedKeysFunc(ctx, authorizedKey)
+}
+
+// GetNTPServers calls GetNTPServersFunc.
+func (m *MockClient) GetNTPServers(ctx context.Context) ([]string, error) {
+	m.record("GetNTPServers")
+	if m.GetNTPServersFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetNTPServersFunc(ctx)
+}
+
+// SetNTPServers calls SetNTPServersFunc.
+func (m *MockClient) SetNTPServers(ctx context.Context, servers []string) error {
+	m.record("SetNTPServers")
+	if m.SetNTPServersFunc == nil {
+		return ErrNotMocked
+	}
+	return m.SetNTPServersFunc(ctx, servers)
+}
+
+// GetNetworkProtocol calls GetNetworkProtocolFunc.
+func (m *MockClient) GetNetworkProtocol(ctx context.Context) (redfish.NetworkProtocol, error) {
+	m.record("GetNetworkProtocol")
+	if m.GetNetworkProtocolFunc == nil {
+		return redfish.NetworkProtocol{}, ErrNotMocked
+	}
+	return m.GetNetworkProtocolFunc(ctx)
+}
+
+// SetHostName calls SetHostNameFunc.
+func (m *MockClient) SetHostName(ctx context.Context, hostName, fqdn string) error {
+	m.record("SetHostName")
+	if m.SetHostNameFunc == nil {
+		return ErrNotMocked
+	}
+	return m.SetHostNameFunc(ctx, hostName, fqdn)
+}
+
+// GetSyslog calls GetSyslogFunc.
+func (m *MockClient) GetSyslog(ctx context.Context) (redfish.SyslogConfig, error) {
+	m.record("GetSyslog")
+	if m.GetSyslogFunc == nil {
+		return redfish.SyslogConfig{}, ErrNotMocked
+	}
+	return m.GetSyslogFunc(ctx)
+}
+
+// SetSyslog calls SetSyslogFunc.
+func (m *MockClient) SetSyslog(ctx context.Context, targets []redfish.SyslogTarget) error {
+	m.record("SetSyslog")
+	if m.SetSyslogFunc == nil {
+		return ErrNotMocked
+	}
+	return m.SetSyslogFunc(ctx, targets)
+}
+
+// SendSyslogTest calls SendSyslogTestFunc.
+func (m *MockClient) SendSyslogTest(ctx context.Context, message string) error {
+	m.record("SendSyslogTest")
+	if m.SendSyslogTestFunc == nil {
+		return ErrNotMocked
+	}
+	return m.SendSyslogTestFunc(ctx, message)
+}
+
+// GetBiosAttributes calls GetBiosAttributesFunc.
+func (m *MockClient) GetBiosAttributes(ctx context.Context) ([]redfish.SystemBios, error) {
+	m.record("GetBiosAttributes")
+	if m.GetBiosAttributesFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetBiosAttributesFunc(ctx)
+}
+
+// SetBiosAttributes calls SetBiosAttributesFunc.
+func (m *MockClient) SetBiosAttributes(ctx context.Context, systemPath string, attrs map[string]any) error {
+	m.record("SetBiosAttributes")
+	if m.SetBiosAttributesFunc == nil {
+		return ErrNotMocked
+	}
+	return m.SetBiosAttributesFunc(ctx, systemPath, attrs)
+}
+
+// GetAccounts calls GetAccountsFunc.
+func (m *MockClient) GetAccounts(ctx context.Context) ([]redfish.Account, error) {
+	m.record("GetAccounts")
+	if m.GetAccountsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetAccountsFunc(ctx)
+}
+
+// GetLocators calls GetLocatorsFunc.
+func (m *MockClient) GetLocators(ctx context.Context) ([]redfish.Locator, error) {
+	m.record("GetLocators")
+	if m.GetLocatorsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetLocatorsFunc(ctx)
+}
+
+// SetLocator calls SetLocatorFunc.
+func (m *MockClient) SetLocator(ctx context.Context, systemPath string, on bool) error {
+	m.record("SetLocator")
+	if m.SetLocatorFunc == nil {
+		return ErrNotMocked
+	}
+	return m.SetLocatorFunc(ctx, systemPath, on)
+}
+
+// GetPowerLimits calls GetPowerLimitsFunc.
+func (m *MockClient) GetPowerLimits(ctx context.Context) ([]redfish.PowerLimit, error) {
+	m.record("GetPowerLimits")
+	if m.GetPowerLimitsFunc == nil {
+		return nil, ErrNotMocked
+	}
+	return m.GetPowerLimitsFunc(ctx)
+}
+
+// SetPowerLimit calls SetPowerLimitFunc.
+func (m *MockClient) SetPowerLimit(ctx context.Context, chassisPath string, watts float64) error {
+	m.record("SetPowerLimit")
+	if m.SetPowerLimitFunc == nil {
+		return ErrNotMocked
+	}
+	return m.SetPowerLimitFunc(ctx, chassisPath, watts)
+}
+
+// ResetManager calls ResetManagerFunc.
+func (m *MockClient) ResetManager(ctx context.Context, resetType string) error {
+	m.record("ResetManager")
+	if m.ResetManagerFunc == nil {
+		return ErrNotMocked
+	}
+	return m.ResetManagerFunc(ctx, resetType)
+}
+
+// ResetSystem calls ResetSystemFunc.
+func (m *MockClient) ResetSystem(ctx context.Context, systemPath, resetType string) error {
+	m.record("ResetSystem")
+	if m.ResetSystemFunc == nil {
+		return ErrNotMocked
+	}
+	return m.ResetSystemFunc(ctx, systemPath, resetType)
+}
+
+// ResetChassis calls ResetChassisFunc.
+func (m *MockClient) ResetChassis(ctx context.Context, chassisPath, resetType string) error {
+	m.record("ResetChassis")
+	if m.ResetChassisFunc == nil {
+		return ErrNotMocked
+	}
+	return m.ResetChassisFunc(ctx, chassisPath, resetType)
+}
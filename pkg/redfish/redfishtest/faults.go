@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+// Package redfishtest provides test doubles for BMCs: MockClient, an in-memory
+// redfish.Client, SimBMC, an HTTP BMC with any number of systems, and fault
+// injection for the httptest-based BMC mocks so retry, timeout and
+// rollout-abort handling can be exercised against realistic BMC failure modes.
+package redfishtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Profile selects the faults a mock BMC injects. The zero value injects none.
+type Profile struct {
+	// Delay is added before every response.
+	Delay time.Duration
+	// Unavailable is the number of leading requests answered with 503.
+	Unavailable int
+	// AuthFail answers every request with 401.
+	AuthFail bool
+	// Truncate cuts JSON response bodies in half.
+	Truncate bool
+	// TaskFailAt makes SimpleUpdate return a task that fails once its progress
+	// reaches this percentage (0 disables).
+	TaskFailAt int
+}
+
+// ParseProfile parses a comma-separated fault spec such as
+// "slow=2s,503=3,auth,truncate,task-fail=60".
+func ParseProfile(spec string) (Profile, error) {
+	var p Profile
+	for _, f := range strings.Split(spec, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		name, val, _ := strings.Cut(f, "=")
+		var err error
+		switch name {
+		case "slow":
+			p.Delay, err = time.ParseDuration(val)
+		case "503":
+			p.Unavailable, err = strconv.Atoi(val)
+		case "auth":
+			p.AuthFail = true
+		case "truncate":
+			p.Truncate = true
+		case "task-fail":
+			p.TaskFailAt, err = strconv.Atoi(val)
+			if err == nil && (p.TaskFailAt < 1 || p.TaskFailAt > 100) {
+				err = fmt.Errorf("percentage must be 1-100")
+			}
+		default:
+			return Profile{}, fmt.Errorf("unknown fault %q", name)
+		}
+		if err != nil {
+			return Profile{}, fmt.Errorf("fault %q: %w", f, err)
+		}
+	}
+	return p, nil
+}
+
+const taskPath = "/redfish/v1/TaskService/Tasks/fault-1"
+
+// taskStep is how far a failing task advances per poll.
+const taskStep = 20
+
+// WithFaults wraps a mock BMC handler so it misbehaves according to p.
+func WithFaults(next http.Handler, p Profile) http.Handler {
+	var mu sync.Mutex
+	served := 0
+	progress := -1 // no task started
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		served++
+		n := served
+		mu.Unlock()
+
+		if p.Delay > 0 {
+			select {
+			case <-time.After(p.Delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if n <= p.Unavailable {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if p.AuthFail {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if p.TaskFailAt > 0 {
+			switch {
+			case r.Method == http.MethodPost && strings.Contains(r.URL.Path, "/UpdateService/Actions/"):
+				mu.Lock()
+				progress = 0
+				mu.Unlock()
+				w.Header().Set("Location", taskPath)
+				w.WriteHeader(http.StatusAccepted)
+				return
+			case r.URL.Path == taskPath:
+				mu.Lock()
+				if progress >= 0 && progress < p.TaskFailAt {
+					progress = min(progress+taskStep, p.TaskFailAt)
+				}
+				pct := progress
+				mu.Unlock()
+				state, status := "Running", "OK"
+				if pct >= p.TaskFailAt {
+					state, status = "Exception", "Critical"
+				}
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]any{ //nolint:errcheck
+					"@odata.id":       taskPath,
+					"Id":              "fault-1",
+					"TaskState":       state,
+					"TaskStatus":      status,
+					"PercentComplete": pct,
+				})
+				return
+			}
+		}
+		if p.Truncate {
+			tw := &truncatingWriter{ResponseWriter: w}
+			next.ServeHTTP(tw, r)
+			tw.flush()
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// truncatingWriter buffers a response and writes only the first half of it.
+type truncatingWriter struct {
+	http.ResponseWriter
+	buf    []byte
+	status int
+}
+
+func (t *truncatingWriter) WriteHeader(code int) { t.status = code }
+
+func (t *truncatingWriter) Write(b []byte) (int, error) {
+	t.buf = append(t.buf, b...)
+	return len(b), nil
+}
+
+func (t *truncatingWriter) flush() {
+	t.ResponseWriter.Header().Del("Content-Length")
+	if t.status != 0 {
+		t.ResponseWriter.WriteHeader(t.status)
+	}
+	t.ResponseWriter.Write(t.buf[:len(t.buf)/2]) //nolint:errcheck
+}
@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfishtest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"bootstrap/pkg/redfish"
+)
+
+func inventoryHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"Id": "BMC", "Version": "1.0.0"}) //nolint:errcheck
+	})
+}
+
+func TestParseProfile(t *testing.T) {
+	p, err := ParseProfile("slow=2s, 503=3,auth,truncate,task-fail=60")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Profile{Delay: 2 * time.Second, Unavailable: 3, AuthFail: true, Truncate: true, TaskFailAt: 60}
+	if p != want {
+		t.Fatalf("got %+v, want %+v", p, want)
+	}
+	for _, bad := range []string{"nope", "slow=x", "task-fail=0"} {
+		if _, err := ParseProfile(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestWithFaultsUnavailableBurst(t *testing.T) {
+	srv := httptest.NewServer(WithFaults(inventoryHandler(), Profile{Unavailable: 2}))
+	defer srv.Close()
+	for i, want := range []int{503, 503, 200} {
+		resp, err := http.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close() //nolint:errcheck
+		if resp.StatusCode != want {
+			t.Errorf("request %d: status %d, want %d", i, resp.StatusCode, want)
+		}
+	}
+}
+
+func TestWithFaultsAuthAndTruncate(t *testing.T) {
+	auth := httptest.NewTLSServer(WithFaults(inventoryHandler(), Profile{AuthFail: true}))
+	defer auth.Close()
+	_, err := redfish.GetFirmwareInventory(context.Background(), auth.Listener.Addr().String(), "u", "p", true, 2*time.Second, "/UpdateService/FirmwareInventory/BMC")
+	if !redfish.IsAuthError(err) {
+		t.Errorf("expected auth error, got %v", err)
+	}
+
+	trunc := httptest.NewTLSServer(WithFaults(inventoryHandler(), Profile{Truncate: true}))
+	defer trunc.Close()
+	if _, err := redfish.GetFirmwareInventory(context.Background(), trunc.Listener.Addr().String(), "u", "p", true, 2*time.Second, "/UpdateService/FirmwareInventory/BMC"); err == nil {
+		t.Error("expected decode error for truncated JSON")
+	}
+}
+
+func TestWithFaultsTaskFails(t *testing.T) {
+	srv := httptest.NewServer(WithFaults(inventoryHandler(), Profile{TaskFailAt: 60}))
+	defer srv.Close()
+	resp, err := http.Post(srv.URL+"/redfish/v1/UpdateService/Actions/SimpleUpdate", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("status %d", resp.StatusCode)
+	}
+	loc := resp.Header.Get("Location")
+	var states []string
+	for i := 0; i < 4; i++ {
+		r, err := http.Get(srv.URL + loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(r.Body)
+		r.Body.Close() //nolint:errcheck
+		var task struct {
+			TaskState       string
+			PercentComplete int
+		}
+		if err := json.Unmarshal(b, &task); err != nil {
+			t.Fatal(err)
+		}
+		states = append(states, task.TaskState)
+		if task.TaskState == "Exception" && task.PercentComplete != 60 {
+			t.Errorf("failed at %d%%, want 60%%", task.PercentComplete)
+		}
+	}
+	if states[0] != "Running" || states[2] != "Exception" || states[3] != "Exception" {
+		t.Errorf("unexpected task states %v", states)
+	}
+}
@@ -0,0 +1,242 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MessageRegistry is a Redfish message registry: the text behind the MessageIds
+// of status conditions and task messages.
+type MessageRegistry struct {
+	ID              string                     `json:"Id"` // e.g. "Update.1.0.2"
+	RegistryPrefix  string                     `json:"RegistryPrefix"`
+	RegistryVersion string                     `json:"RegistryVersion"`
+	Messages        map[string]RegistryMessage `json:"Messages"`
+}
+
+// RegistryMessage is one message of a registry. Message holds %1, %2, ...
+// placeholders for the MessageArgs.
+type RegistryMessage struct {
+	Description  string `json:"Description"`
+	Message      string `json:"Message"`
+	Severity     string `json:"Severity"`
+	NumberOfArgs int    `json:"NumberOfArgs"`
+	Resolution   string `json:"Resolution"`
+}
+
+// ResolvedMessage is a MessageId with its arguments filled into the registry text.
+type ResolvedMessage struct {
+	MessageID  string
+	Message    string
+	Severity   string
+	Resolution string
+}
+
+func (r ResolvedMessage) String() string {
+	s := r.MessageID + ": " + r.Message
+	if res := strings.TrimSpace(r.Resolution); res != "" && !strings.EqualFold(res, "none") && !strings.EqualFold(res, "none.") {
+		s += " (resolution: " + res + ")"
+	}
+	return s
+}
+
+// key is a registry's prefix and major version, which is what MessageIds name
+// and what stays compatible across minor versions.
+func (r MessageRegistry) key() string {
+	v := r.RegistryVersion
+	if v == "" {
+		v = strings.TrimPrefix(r.ID, r.RegistryPrefix+".")
+	}
+	major, _, _ := strings.Cut(v, ".")
+	return r.RegistryPrefix + "." + major
+}
+
+// Registries is a set of message registries safe for concurrent use. Of two
+// registries with the same prefix and major version the newer is kept.
+type Registries struct {
+	mu   sync.RWMutex
+	regs map[string]MessageRegistry
+}
+
+// bundled holds the DMTF registries messages are resolved against without
+// asking a BMC: the messages of DMTF's Base and Update registries.
+//
+//go:embed registries/*.json
+var bundled embed.FS
+
+// NewRegistries returns the bundled registries plus the registry JSON files in
+// dir (none when dir is empty), such as vendor registries downloaded ahead of time.
+func NewRegistries(dir string) (*Registries, error) {
+	r := &Registries{regs: map[string]MessageRegistry{}}
+	files, _ := bundled.ReadDir("registries")
+	for _, f := range files {
+		b, err := bundled.ReadFile("registries/" + f.Name())
+		if err != nil {
+			return nil, err
+		}
+		if err := r.addJSON(b); err != nil {
+			return nil, fmt.Errorf("bundled registry %s: %w", f.Name(), err)
+		}
+	}
+	if dir == "" {
+		return r, nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range paths {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.addJSON(b); err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+	}
+	return r, nil
+}
+
+func (r *Registries) addJSON(b []byte) error {
+	var reg MessageRegistry
+	if err := json.Unmarshal(b, &reg); err != nil {
+		return err
+	}
+	if reg.RegistryPrefix == "" || len(reg.Messages) == 0 {
+		return fmt.Errorf("not a message registry")
+	}
+	r.Add(reg)
+	return nil
+}
+
+// Add adds reg unless a newer version of it is already present.
+func (r *Registries) Add(reg MessageRegistry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if old, ok := r.regs[reg.key()]; ok && !versionLess(old.RegistryVersion, reg.RegistryVersion) {
+		return
+	}
+	r.regs[reg.key()] = reg
+}
+
+// Has reports whether the registry messageID refers to is present.
+func (r *Registries) Has(messageID string) bool {
+	key, _, ok := splitMessageID(messageID)
+	if !ok {
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok = r.regs[key]
+	return ok
+}
+
+// Resolve looks up messageID (Prefix.Major.Minor.Key) and fills args into its
+// text. It reports false for unknown registries and messages.
+func (r *Registries) Resolve(messageID string, args []string) (ResolvedMessage, bool) {
+	key, name, ok := splitMessageID(messageID)
+	if !ok {
+		return ResolvedMessage{}, false
+	}
+	r.mu.RLock()
+	reg, ok := r.regs[key]
+	r.mu.RUnlock()
+	if !ok {
+		return ResolvedMessage{}, false
+	}
+	m, ok := reg.Messages[name]
+	if !ok {
+		return ResolvedMessage{}, false
+	}
+	return ResolvedMessage{MessageID: messageID, Message: fillArgs(m.Message, args), Severity: m.Severity, Resolution: m.Resolution}, true
+}
+
+// splitMessageID returns the registry key (Prefix.Major) and message key of a
+// MessageId such as "Update.1.0.TransferFailed".
+func splitMessageID(id string) (key, name string, ok bool) {
+	parts := strings.Split(id, ".")
+	if len(parts) < 4 {
+		return "", "", false
+	}
+	return parts[0] + "." + parts[1], parts[len(parts)-1], true
+}
+
+// fillArgs replaces %1, %2, ... in msg, from the highest index down so %1
+// does not match the start of %10.
+func fillArgs(msg string, args []string) string {
+	for i := len(args); i >= 1; i-- {
+		msg = strings.ReplaceAll(msg, "%"+strconv.Itoa(i), args[i-1])
+	}
+	return msg
+}
+
+// versionLess compares dotted numeric versions.
+func versionLess(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			return x < y
+		}
+	}
+	return false
+}
+
+type rfRegistryFile struct {
+	Registry string `json:"Registry"` // e.g. "HPEFirmwareUpdate.1.0"
+	Location []struct {
+		Language string `json:"Language"`
+		URI      string `json:"Uri"`
+	} `json:"Location"`
+}
+
+// GetMessageRegistry fetches from the BMC's /Registries the registry that
+// messageID refers to, preferring its English copy. Only registries the BMC
+// hosts itself can be fetched; PublicationUri copies are on the internet.
+func (c *client) GetMessageRegistry(ctx context.Context, messageID string) (MessageRegistry, error) {
+	key, _, ok := splitMessageID(messageID)
+	if !ok {
+		return MessageRegistry{}, fmt.Errorf("malformed MessageId %q", messageID)
+	}
+	for b, err := range c.members(ctx, "/Registries") {
+		if err != nil {
+			return MessageRegistry{}, err
+		}
+		var f rfRegistryFile
+		if json.Unmarshal(b, &f) != nil || (f.Registry != key && !strings.HasPrefix(f.Registry, key+".")) {
+			continue
+		}
+		uri := ""
+		for _, l := range f.Location {
+			if l.URI != "" && (uri == "" || strings.HasPrefix(strings.ToLower(l.Language), "en")) {
+				uri = l.URI
+			}
+		}
+		if uri == "" {
+			break
+		}
+		var reg MessageRegistry
+		if err := c.get(ctx, uri, &reg); err != nil {
+			return MessageRegistry{}, err
+		}
+		return reg, nil
+	}
+	return MessageRegistry{}, fmt.Errorf("registry for %s: %w", messageID, ErrNotFound)
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRegistriesResolve(t *testing.T) {
+	dir := t.TempDir()
+	vendor := `{"Id": "Acme.2.1.0", "RegistryPrefix": "Acme", "RegistryVersion": "2.1.0",
+		"Messages": {"Many": {"Message": "%1-%2-%10", "Resolution": "None"}}}`
+	if err := os.WriteFile(filepath.Join(dir, "acme.json"), []byte(vendor), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	regs, err := NewRegistries(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		id   string
+		args []string
+		want string
+		ok   bool
+	}{
+		// bundled, with a minor version other than the bundled one
+		{"Update.1.0.ApplyFailed", []string{"bios.bin", "BIOS"}, "Update.1.0.ApplyFailed: Installation of image 'bios.bin' to 'BIOS' failed.", true},
+		{"Base.1.18.ServiceTemporarilyUnavailable", []string{"30"}, "Base.1.18.ServiceTemporarilyUnavailable: The service is temporarily unavailable.  Retry in 30 seconds. (resolution: Wait for the indicated retry duration and retry the operation.)", true},
+		// %10 is not mistaken for %1 followed by 0
+		{"Acme.2.0.Many", []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}, "Acme.2.0.Many: a-b-j", true},
+		{"Acme.3.0.Many", nil, "", false},
+		{"Update.1.0.NoSuchMessage", nil, "", false},
+		{"NotAMessageId", nil, "", false},
+	}
+	for _, tc := range tests {
+		got, ok := regs.Resolve(tc.id, tc.args)
+		if ok != tc.ok || (ok && got.String() != tc.want) {
+			t.Errorf("Resolve(%s) = %q, %v; want %q, %v", tc.id, got.String(), ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestRegistriesKeepNewest(t *testing.T) {
+	regs, err := NewRegistries("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	regs.Add(MessageRegistry{RegistryPrefix: "Update", RegistryVersion: "1.0.0", Messages: map[string]RegistryMessage{"TransferFailed": {Message: "old"}}})
+	if r, _ := regs.Resolve("Update.1.0.TransferFailed", nil); r.Message == "old" {
+		t.Error("older registry replaced the bundled one")
+	}
+	regs.Add(MessageRegistry{RegistryPrefix: "Update", RegistryVersion: "1.2.0", Messages: map[string]RegistryMessage{"TransferFailed": {Message: "new"}}})
+	if r, _ := regs.Resolve("Update.1.0.TransferFailed", nil); r.Message != "new" {
+		t.Errorf("got %q, want the newer registry's message", r.Message)
+	}
+}
@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type rfBios struct {
+	Attributes map[string]any `json:"Attributes"`
+	Settings   struct {
+		SettingsObject struct {
+			OID string `json:"@odata.id"`
+		} `json:"SettingsObject"`
+	} `json:"@Redfish.Settings"`
+}
+
+// SystemBios holds the current BIOS attributes of one system on a BMC.
+type SystemBios struct {
+	SystemPath string
+	Attributes map[string]any
+}
+
+// GetBiosAttributes returns the current BIOS attributes for every system on a BMC.
+func (c *client) GetBiosAttributes(ctx context.Context) ([]SystemBios, error) {
+	sysPaths, err := c.listSystemPaths(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]SystemBios, 0, len(sysPaths))
+	for _, sp := range sysPaths {
+		var b rfBios
+		if err := c.get(ctx, sp+"/Bios", &b); err != nil {
+			return nil, err
+		}
+		out = append(out, SystemBios{SystemPath: sp, Attributes: b.Attributes})
+	}
+	return out, nil
+}
+
+// SetBiosAttributes stages BIOS attribute changes for a system. The BMC applies them
+// on the next boot of that system. The settings object advertised by the Bios
+// resource is used when present, otherwise <system>/Bios/Settings.
+func (c *client) SetBiosAttributes(ctx context.Context, systemPath string, attrs map[string]any) error {
+	target := systemPath + "/Bios/Settings"
+	var b rfBios
+	if err := c.get(ctx, systemPath+"/Bios", &b); err == nil && b.Settings.SettingsObject.OID != "" {
+		target = b.Settings.SettingsObject.OID
+	}
+	return c.patch(ctx, target, map[string]any{"Attributes": attrs})
+}
+
+type rfNTP struct {
+	NTP struct {
+		ProtocolEnabled *bool    `json:"ProtocolEnabled,omitempty"`
+		NTPServers      []string `json:"NTPServers"`
+	} `json:"NTP"`
+}
+
+// GetNTPServers returns the NTP servers configured on the BMC manager.
+func (c *client) GetNTPServers(ctx context.Context) ([]string, error) {
+	var np rfNTP
+	if err := c.get(ctx, "/Managers/BMC/NetworkProtocol", &np); err != nil {
+		return nil, err
+	}
+	return np.NTP.NTPServers, nil
+}
+
+// SetNTPServers enables NTP on the BMC manager and sets its server list.
+func (c *client) SetNTPServers(ctx context.Context, servers []string) error {
+	enabled := true
+	var np rfNTP
+	np.NTP.ProtocolEnabled = &enabled
+	np.NTP.NTPServers = servers
+	return c.patch(ctx, "/Managers/BMC/NetworkProtocol", np)
+}
+
+// SetHostName sets the host name of the BMC manager in its NetworkProtocol.
+// With fqdn set, every manager Ethernet interface that has an address also
+// gets HostName and FQDN, since ManagerNetworkProtocol's FQDN is read-only.
+func (c *client) SetHostName(ctx context.Context, hostName, fqdn string) error {
+	if err := c.patch(ctx, "/Managers/BMC/NetworkProtocol", map[string]string{"HostName": hostName}); err != nil {
+		return err
+	}
+	if fqdn == "" {
+		return nil
+	}
+	var managers rfCollection
+	if err := c.get(ctx, "/Managers", &managers); err != nil {
+		return err
+	}
+	patched := 0
+	for _, m := range managers.Members {
+		var nics rfCollection
+		if err := c.get(ctx, m.OID+"/EthernetInterfaces", &nics); err != nil {
+			return err
+		}
+		for _, n := range nics.Members {
+			var nic rfEthernetInterface
+			if err := c.get(ctx, n.OID, &nic); err != nil {
+				return err
+			}
+			if !hasIPv4(nic) {
+				continue
+			}
+			if err := c.patch(ctx, n.OID, map[string]string{"HostName": hostName, "FQDN": fqdn}); err != nil {
+				return fmt.Errorf("%s: %w", n.OID, err)
+			}
+			patched++
+		}
+	}
+	if patched == 0 {
+		return errors.New("no manager Ethernet interface with an IPv4 address to set the FQDN on")
+	}
+	return nil
+}
+
+func hasIPv4(nic rfEthernetInterface) bool {
+	for _, a := range nic.IPv4Addresses {
+		if a.Address != "" && a.Address != "0.0.0.0" {
+			return true
+		}
+	}
+	return false
+}
+
+// Protocol is the state of one network service of the BMC manager.
+type Protocol struct {
+	Enabled *bool `json:"ProtocolEnabled,omitempty"`
+	Port    int   `json:"Port,omitempty"`
+}
+
+// NetworkProtocol holds the network service settings of the BMC manager.
+type NetworkProtocol struct {
+	HostName   string
+	FQDN       string
+	Protocols  map[string]Protocol // by Redfish property name, e.g. SSH, HTTPS, IPMI
+	NTPServers []string
+}
+
+// networkProtocols are the ManagerNetworkProtocol properties read by GetNetworkProtocol.
+var networkProtocols = []string{"HTTP", "HTTPS", "IPMI", "SSH", "SNMP", "SSDP", "Telnet", "KVMIP", "VirtualMedia", "NTP"}
+
+// GetNetworkProtocol returns the network service settings of the BMC manager.
+func (c *client) GetNetworkProtocol(ctx context.Context) (NetworkProtocol, error) {
+	var raw map[string]json.RawMessage
+	if err := c.get(ctx, "/Managers/BMC/NetworkProtocol", &raw); err != nil {
+		return NetworkProtocol{}, err
+	}
+	out := NetworkProtocol{Protocols: map[string]Protocol{}}
+	if h, ok := raw["HostName"]; ok {
+		_ = json.Unmarshal(h, &out.HostName)
+	}
+	if f, ok := raw["FQDN"]; ok {
+		_ = json.Unmarshal(f, &out.FQDN)
+	}
+	for _, name := range networkProtocols {
+		v, ok := raw[name]
+		if !ok {
+			continue
+		}
+		var p Protocol
+		if err := json.Unmarshal(v, &p); err != nil {
+			return NetworkProtocol{}, fmt.Errorf("NetworkProtocol %s: %w", name, err)
+		}
+		out.Protocols[name] = p
+	}
+	var np rfNTP
+	if v, ok := raw["NTP"]; ok {
+		if err := json.Unmarshal(v, &np.NTP); err != nil {
+			return NetworkProtocol{}, fmt.Errorf("NetworkProtocol NTP: %w", err)
+		}
+	}
+	out.NTPServers = np.NTP.NTPServers
+	return out, nil
+}
+
+// Account is a local user account on a BMC.
+type Account struct {
+	UserName string
+	RoleID   string
+	Enabled  bool
+}
+
+// GetAccounts lists the accounts in the BMC's AccountService.
+func (c *client) GetAccounts(ctx context.Context) ([]Account, error) {
+	var coll rfCollection
+	if err := c.get(ctx, "/AccountService/Accounts", &coll); err != nil {
+		return nil, err
+	}
+	var out []Account
+	for _, m := range coll.Members {
+		var acct rfAccount
+		if err := c.get(ctx, m.OID, &acct); err != nil {
+			return nil, err
+		}
+		if acct.UserName == "" {
+			continue // unused account slot
+		}
+		out = append(out, Account{UserName: acct.UserName, RoleID: acct.RoleID, Enabled: acct.Enabled == nil || *acct.Enabled})
+	}
+	return out, nil
+}
@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"cmp"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// HostStats summarizes the requests made to one BMC while stats were enabled.
+type HostStats struct {
+	Host     string
+	Requests int
+	Errors   int         // transport errors and responses with status >= 300
+	Statuses map[int]int // requests by HTTP status; 0 counts transport errors
+	P50      time.Duration
+	P95      time.Duration
+	Max      time.Duration
+}
+
+// requestStats records the latency and status of every request when enabled.
+type requestStats struct {
+	mu    sync.Mutex
+	hosts map[string]*hostSamples
+}
+
+type hostSamples struct {
+	latencies []time.Duration
+	statuses  map[int]int
+	errors    int
+}
+
+// stats is nil unless EnableStats was called.
+var stats *requestStats
+
+// RequestObserver is told the host, latency, HTTP status (0 when no answer
+// came) and transport error of a Redfish request.
+type RequestObserver func(host string, d time.Duration, status int, err error)
+
+// observers are told about every request; see AddRequestObserver.
+var (
+	observersMu sync.Mutex
+	observers   []*RequestObserver
+)
+
+// AddRequestObserver makes every Redfish request report to fn, e.g. to adapt
+// concurrency to how BMCs cope, until the returned function removes it.
+func AddRequestObserver(fn RequestObserver) (remove func()) {
+	p := &fn
+	observersMu.Lock()
+	observers = append(slices.Clone(observers), p)
+	observersMu.Unlock()
+	return func() {
+		observersMu.Lock()
+		defer observersMu.Unlock()
+		observers = slices.DeleteFunc(slices.Clone(observers), func(o *RequestObserver) bool { return o == p })
+	}
+}
+
+// EnableStats starts recording the latency and status of every Redfish request,
+// discarding what was recorded before. It is not safe to call while requests
+// are in flight.
+func EnableStats() {
+	stats = &requestStats{hosts: map[string]*hostSamples{}}
+}
+
+func (s *requestStats) record(host string, d time.Duration, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h := s.hosts[host]
+	if h == nil {
+		h = &hostSamples{statuses: map[int]int{}}
+		s.hosts[host] = h
+	}
+	h.latencies = append(h.latencies, d)
+	h.statuses[status]++
+	if status == 0 || status >= 300 {
+		h.errors++
+	}
+}
+
+// RequestStats returns the stats of every BMC contacted since EnableStats,
+// slowest (by p95 latency) first, or nil when stats are not enabled.
+func RequestStats() []HostStats {
+	s := stats
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]HostStats, 0, len(s.hosts))
+	for host, h := range s.hosts {
+		lat := slices.Clone(h.latencies)
+		slices.Sort(lat)
+		out = append(out, HostStats{
+			Host:     host,
+			Requests: len(lat),
+			Errors:   h.errors,
+			Statuses: maps.Clone(h.statuses),
+			P50:      percentile(lat, 50),
+			P95:      percentile(lat, 95),
+			Max:      lat[len(lat)-1],
+		})
+	}
+	slices.SortFunc(out, func(a, b HostStats) int {
+		return cmp.Or(cmp.Compare(b.P95, a.P95), cmp.Compare(a.Host, b.Host))
+	})
+	return out
+}
+
+// percentile returns the p-th percentile of sorted (nearest rank).
+func percentile(sorted []time.Duration, p int) time.Duration {
+	i := (len(sorted)*p + 99) / 100
+	return sorted[max(i, 1)-1]
+}
+
+// do sends req, reporting its latency and status under the client's host to
+// the stats, when enabled, and the observers.
+func (c *client) do(req *http.Request) (*http.Response, error) {
+	observersMu.Lock()
+	s, obs := stats, observers
+	observersMu.Unlock()
+	if s == nil && len(obs) == 0 {
+		return c.http.Do(req)
+	}
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	d, status := time.Since(start), 0
+	if err == nil {
+		status = resp.StatusCode
+	}
+	if s != nil {
+		s.record(c.host, d, status)
+	}
+	for _, o := range obs {
+		(*o)(c.host, d, status, err)
+	}
+	return resp, err
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequestStats(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redfish/v1/Slow" {
+			time.Sleep(20 * time.Millisecond)
+		}
+		if r.URL.Path == "/redfish/v1/Missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	host := server.URL[len("https://"):]
+
+	stats = nil
+	if RequestStats() != nil {
+		t.Fatal("stats reported while disabled")
+	}
+	EnableStats()
+	defer func() { stats = nil }()
+
+	c := newClient(host, "user", "pass", true, 5*time.Second)
+	var v map[string]any
+	for range 8 {
+		if err := c.get(context.Background(), "/Fast", &v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = c.get(context.Background(), "/Slow", &v)
+	_ = c.get(context.Background(), "/Missing", &v)
+	_ = newClient("127.0.0.1:1", "user", "pass", true, time.Second).get(context.Background(), "/", &v)
+
+	got := RequestStats()
+	if len(got) != 2 {
+		t.Fatalf("got stats for %d hosts, want 2: %+v", len(got), got)
+	}
+	byHost := map[string]HostStats{got[0].Host: got[0], got[1].Host: got[1]}
+	h := byHost[host]
+	if h.Requests != 10 || h.Errors != 1 || h.Statuses[200] != 9 || h.Statuses[404] != 1 {
+		t.Errorf("stats = %+v", h)
+	}
+	if h.Max < 20*time.Millisecond || h.P50 >= 20*time.Millisecond || h.P95 < h.P50 {
+		t.Errorf("latencies p50=%s p95=%s max=%s", h.P50, h.P95, h.Max)
+	}
+	if down := byHost["127.0.0.1:1"]; down.Requests != 1 || down.Errors != 1 || down.Statuses[0] != 1 {
+		t.Errorf("unreachable host stats = %+v", down)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	lat := make([]time.Duration, 20)
+	for i := range lat {
+		lat[i] = time.Duration(i+1) * time.Millisecond
+	}
+	if got := percentile(lat, 50); got != 10*time.Millisecond {
+		t.Errorf("p50 = %s", got)
+	}
+	if got := percentile(lat, 95); got != 19*time.Millisecond {
+		t.Errorf("p95 = %s", got)
+	}
+	if got := percentile(lat[:1], 95); got != time.Millisecond {
+		t.Errorf("p95 of one = %s", got)
+	}
+}
@@ -0,0 +1,270 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Ways a BMC forwards its logs to remote syslog servers.
+const (
+	// SyslogEventService is the standard way (Redfish 2023.3): EventService
+	// subscriptions whose Protocol is SyslogUDP or SyslogTCP.
+	SyslogEventService = "EventService"
+	// SyslogHPE is the RemoteSyslog OEM properties of iLO's NetworkProtocol.
+	SyslogHPE = "Oem.Hpe"
+)
+
+// subscriptionContext marks the EventService subscriptions SetSyslog creates.
+const subscriptionContext = "ochami-bootstrap"
+
+// SyslogTarget is a remote syslog server.
+type SyslogTarget struct {
+	Host     string
+	Port     int    // 514 when zero
+	Protocol string // udp (default) or tcp
+}
+
+// ParseSyslogTarget parses host, host:port or udp:// and tcp:// URLs thereof.
+func ParseSyslogTarget(s string) (SyslogTarget, error) {
+	t := SyslogTarget{Protocol: "udp", Port: 514}
+	rest := s
+	if proto, after, ok := strings.Cut(s, "://"); ok {
+		if proto != "udp" && proto != "tcp" {
+			return SyslogTarget{}, fmt.Errorf("syslog server %q: protocol must be udp or tcp", s)
+		}
+		t.Protocol, rest = proto, after
+	}
+	t.Host = strings.TrimSuffix(strings.TrimPrefix(rest, "["), "]")
+	if h, p, err := net.SplitHostPort(rest); err == nil {
+		port, err := strconv.Atoi(p)
+		if err != nil || port < 1 || port > 65535 {
+			return SyslogTarget{}, fmt.Errorf("syslog server %q: bad port", s)
+		}
+		t.Host, t.Port = h, port
+	}
+	if t.Host == "" || strings.ContainsAny(t.Host, "/ ") {
+		return SyslogTarget{}, fmt.Errorf("syslog server %q: want host, host:port or udp://host:port", s)
+	}
+	return t, nil
+}
+
+// String is e.g. "udp://10.0.0.5:514".
+func (t SyslogTarget) String() string {
+	return cmp.Or(t.Protocol, "udp") + "://" + net.JoinHostPort(t.Host, strconv.Itoa(cmp.Or(t.Port, 514)))
+}
+
+// SyslogConfig is the remote syslog forwarding of a BMC.
+type SyslogConfig struct {
+	Method  string // SyslogEventService or SyslogHPE
+	Targets []SyslogTarget
+}
+
+type rfHPESyslog struct {
+	Oem struct {
+		Hpe *rfHPERemoteSyslog `json:"Hpe,omitempty"`
+	} `json:"Oem"`
+}
+
+type rfHPERemoteSyslog struct {
+	RemoteSyslogEnabled *bool   `json:"RemoteSyslogEnabled,omitempty"`
+	RemoteSyslogServer  *string `json:"RemoteSyslogServer,omitempty"`
+	RemoteSyslogPort    int     `json:"RemoteSyslogPort,omitempty"`
+}
+
+type rfSubscription struct {
+	OID              string `json:"@odata.id,omitempty"`
+	Destination      string `json:"Destination"`
+	Protocol         string `json:"Protocol"`
+	SubscriptionType string `json:"SubscriptionType,omitempty"`
+	Context          string `json:"Context,omitempty"`
+}
+
+// hpeSyslog returns iLO's RemoteSyslog settings, or nil when the BMC has none.
+func (c *client) hpeSyslog(ctx context.Context) (*rfHPESyslog, error) {
+	var np rfHPESyslog
+	if err := c.get(ctx, "/Managers/BMC/NetworkProtocol", &np); err != nil {
+		return nil, err
+	}
+	if np.Oem.Hpe == nil || np.Oem.Hpe.RemoteSyslogServer == nil {
+		return nil, nil
+	}
+	return &np, nil
+}
+
+// syslogSubscriptions returns the EventService subscriptions with a syslog protocol.
+func (c *client) syslogSubscriptions(ctx context.Context) ([]rfSubscription, error) {
+	var coll rfCollection
+	if err := c.get(ctx, "/EventService/Subscriptions", &coll); err != nil {
+		return nil, err
+	}
+	var out []rfSubscription
+	for _, m := range coll.Members {
+		var s rfSubscription
+		if err := c.get(ctx, m.OID, &s); err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(s.Protocol, "Syslog") {
+			s.OID = m.OID
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+// subscriptionTarget converts a syslog subscription to a target.
+func subscriptionTarget(s rfSubscription) SyslogTarget {
+	t, err := ParseSyslogTarget(strings.TrimPrefix(s.Destination, "syslog://"))
+	if err != nil {
+		t = SyslogTarget{Host: s.Destination}
+	}
+	t.Protocol = "udp"
+	if s.Protocol == "SyslogTCP" {
+		t.Protocol = "tcp"
+	}
+	return t
+}
+
+// GetSyslog returns the remote syslog servers the BMC forwards to: iLO's OEM
+// RemoteSyslog settings when the BMC has them, else its syslog EventService
+// subscriptions.
+func (c *client) GetSyslog(ctx context.Context) (SyslogConfig, error) {
+	hpe, err := c.hpeSyslog(ctx)
+	if err != nil {
+		return SyslogConfig{}, err
+	}
+	if hpe != nil {
+		cfg := SyslogConfig{Method: SyslogHPE}
+		h := hpe.Oem.Hpe
+		if (h.RemoteSyslogEnabled == nil || *h.RemoteSyslogEnabled) && *h.RemoteSyslogServer != "" {
+			for _, server := range strings.Split(*h.RemoteSyslogServer, ";") {
+				cfg.Targets = append(cfg.Targets, SyslogTarget{Host: strings.TrimSpace(server), Port: cmp.Or(h.RemoteSyslogPort, 514), Protocol: "udp"})
+			}
+		}
+		return cfg, nil
+	}
+	subs, err := c.syslogSubscriptions(ctx)
+	if err != nil {
+		return SyslogConfig{}, err
+	}
+	cfg := SyslogConfig{Method: SyslogEventService}
+	for _, s := range subs {
+		cfg.Targets = append(cfg.Targets, subscriptionTarget(s))
+	}
+	return cfg, nil
+}
+
+// SetSyslog makes the BMC forward its logs to exactly targets. iLO takes one
+// UDP port for all its servers. Elsewhere syslog EventService subscriptions
+// to other servers are deleted and missing ones created.
+func (c *client) SetSyslog(ctx context.Context, targets []SyslogTarget) error {
+	hpe, err := c.hpeSyslog(ctx)
+	if err != nil {
+		return err
+	}
+	if hpe != nil {
+		return c.setHPESyslog(ctx, targets)
+	}
+	subs, err := c.syslogSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	have := map[string]bool{}
+	for _, s := range subs {
+		t := subscriptionTarget(s)
+		if !containsTarget(targets, t) {
+			if err := c.delete(ctx, s.OID); err != nil {
+				return fmt.Errorf("remove %s: %w", t, err)
+			}
+			continue
+		}
+		have[t.String()] = true
+	}
+	for _, t := range targets {
+		if have[t.String()] {
+			continue
+		}
+		protocol := "SyslogUDP"
+		if t.Protocol == "tcp" {
+			protocol = "SyslogTCP"
+		}
+		sub := rfSubscription{
+			Destination:      "syslog://" + net.JoinHostPort(t.Host, strconv.Itoa(cmp.Or(t.Port, 514))),
+			Protocol:         protocol,
+			SubscriptionType: "Syslog",
+			Context:          subscriptionContext,
+		}
+		if err := c.post(ctx, "/EventService/Subscriptions", sub); err != nil {
+			return fmt.Errorf("subscribe %s: %w", t, err)
+		}
+	}
+	return nil
+}
+
+func (c *client) setHPESyslog(ctx context.Context, targets []SyslogTarget) error {
+	enabled := len(targets) > 0
+	var servers []string
+	port := 514
+	for i, t := range targets {
+		if t.Protocol == "tcp" {
+			return fmt.Errorf("%s: iLO forwards syslog over UDP only", t)
+		}
+		if i > 0 && cmp.Or(t.Port, 514) != port {
+			return errors.New("iLO uses one port for all syslog servers")
+		}
+		port = cmp.Or(t.Port, 514)
+		servers = append(servers, t.Host)
+	}
+	server := strings.Join(servers, ";")
+	var np rfHPESyslog
+	np.Oem.Hpe = &rfHPERemoteSyslog{RemoteSyslogEnabled: &enabled, RemoteSyslogServer: &server, RemoteSyslogPort: port}
+	return c.patch(ctx, "/Managers/BMC/NetworkProtocol", np)
+}
+
+func containsTarget(list []SyslogTarget, t SyslogTarget) bool {
+	for _, x := range list {
+		if x.String() == t.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// SendSyslogTest asks the BMC to emit a test event through its syslog
+// forwarding: iLO's SendTestSyslog action, else EventService.SubmitTestEvent.
+// BMCs without either return a StatusError (typically 404 or 405).
+func (c *client) SendSyslogTest(ctx context.Context, message string) error {
+	hpe, err := c.hpeSyslog(ctx)
+	if err != nil {
+		return err
+	}
+	if hpe != nil {
+		return c.post(ctx, "/Managers/BMC/Actions/Oem/Hpe/HpeiLO.SendTestSyslog", map[string]any{})
+	}
+	var es struct {
+		Actions map[string]json.RawMessage `json:"Actions"`
+	}
+	target := "/EventService/Actions/EventService.SubmitTestEvent"
+	if err := c.get(ctx, "/EventService", &es); err == nil {
+		var a struct {
+			Target string `json:"target"`
+		}
+		if raw, ok := es.Actions["#EventService.SubmitTestEvent"]; ok && json.Unmarshal(raw, &a) == nil && a.Target != "" {
+			target = a.Target
+		}
+	}
+	return c.post(ctx, target, map[string]any{
+		"MessageId": "Base.1.0.Success",
+		"Message":   message,
+		"Severity":  "OK",
+	})
+}
@@ -0,0 +1,128 @@
+// SPDX-FileCopyrightText: 2025 OpenCHAMI Contributors
+//
+// SPDX-License-Identifier: MIT
+
+package redfish
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseSyslogTarget(t *testing.T) {
+	for in, want := range map[string]SyslogTarget{
+		"10.0.0.5":              {Host: "10.0.0.5", Port: 514, Protocol: "udp"},
+		"logs.example.com:1514": {Host: "logs.example.com", Port: 1514, Protocol: "udp"},
+		"tcp://10.0.0.5:6514":   {Host: "10.0.0.5", Port: 6514, Protocol: "tcp"},
+		"udp://[fd00::5]":       {Host: "fd00::5", Port: 514, Protocol: "udp"},
+	} {
+		got, err := ParseSyslogTarget(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSyslogTarget(%q) = %+v, %v; want %+v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "relp://10.0.0.5", "10.0.0.5:99999", "http://x/y"} {
+		if _, err := ParseSyslogTarget(in); err == nil {
+			t.Errorf("ParseSyslogTarget(%q) succeeded", in)
+		}
+	}
+}
+
+// syslogServer serves docs and records the writes it receives.
+func syslogServer(t *testing.T, docs map[string]string) (string, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var writes []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			writes = append(writes, strings.TrimSpace(r.Method+" "+r.URL.Path+" "+string(body)))
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		body, ok := docs[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL[len("https://"):], func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return writes
+	}
+}
+
+func TestSetSyslogEventService(t *testing.T) {
+	host, writes := syslogServer(t, map[string]string{
+		"/redfish/v1/Managers/BMC/NetworkProtocol": `{"HostName":"x1000c0s0b0"}`,
+		"/redfish/v1/EventService/Subscriptions":   `{"Members":[{"@odata.id":"/redfish/v1/EventService/Subscriptions/1"},{"@odata.id":"/redfish/v1/EventService/Subscriptions/2"},{"@odata.id":"/redfish/v1/EventService/Subscriptions/3"}]}`,
+		"/redfish/v1/EventService/Subscriptions/1": `{"Destination":"syslog://10.0.0.4:514","Protocol":"SyslogUDP"}`,
+		"/redfish/v1/EventService/Subscriptions/2": `{"Destination":"syslog://10.0.0.5:514","Protocol":"SyslogUDP"}`,
+		"/redfish/v1/EventService/Subscriptions/3": `{"Destination":"https://collector/events","Protocol":"Redfish"}`,
+	})
+	c := New(host, "user", "pass", true, 5*time.Second)
+	ctx := context.Background()
+
+	cfg, err := c.GetSyslog(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := SyslogConfig{Method: SyslogEventService, Targets: []SyslogTarget{
+		{Host: "10.0.0.4", Port: 514, Protocol: "udp"}, {Host: "10.0.0.5", Port: 514, Protocol: "udp"},
+	}}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("GetSyslog = %+v, want %+v", cfg, want)
+	}
+
+	targets := []SyslogTarget{{Host: "10.0.0.5", Port: 514, Protocol: "udp"}, {Host: "10.0.0.6", Port: 6514, Protocol: "tcp"}}
+	if err := c.SetSyslog(ctx, targets); err != nil {
+		t.Fatal(err)
+	}
+	wantWrites := []string{
+		"DELETE /redfish/v1/EventService/Subscriptions/1",
+		`POST /redfish/v1/EventService/Subscriptions {"Destination":"syslog://10.0.0.6:6514","Protocol":"SyslogTCP","SubscriptionType":"Syslog","Context":"ochami-bootstrap"}`,
+	}
+	if got := writes(); !reflect.DeepEqual(got, wantWrites) {
+		t.Errorf("writes = %q, want %q", got, wantWrites)
+	}
+}
+
+func TestSetSyslogHPE(t *testing.T) {
+	host, writes := syslogServer(t, map[string]string{
+		"/redfish/v1/Managers/BMC/NetworkProtocol": `{"Oem":{"Hpe":{"RemoteSyslogEnabled":false,"RemoteSyslogServer":"","RemoteSyslogPort":514}}}`,
+	})
+	c := New(host, "user", "pass", true, 5*time.Second)
+	ctx := context.Background()
+
+	if cfg, err := c.GetSyslog(ctx); err != nil || cfg.Method != SyslogHPE || len(cfg.Targets) != 0 {
+		t.Fatalf("GetSyslog = %+v, %v", cfg, err)
+	}
+	if err := c.SetSyslog(ctx, []SyslogTarget{{Host: "10.0.0.5", Protocol: "tcp"}}); err == nil {
+		t.Error("TCP accepted by iLO")
+	}
+	if err := c.SetSyslog(ctx, []SyslogTarget{{Host: "10.0.0.5", Port: 514, Protocol: "udp"}, {Host: "10.0.0.6", Port: 514, Protocol: "udp"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SendSyslogTest(ctx, "test"); err != nil {
+		t.Fatal(err)
+	}
+	wantWrites := []string{
+		`PATCH /redfish/v1/Managers/BMC/NetworkProtocol {"Oem":{"Hpe":{"RemoteSyslogEnabled":true,"RemoteSyslogServer":"10.0.0.5;10.0.0.6","RemoteSyslogPort":514}}}`,
+		`POST /redfish/v1/Managers/BMC/Actions/Oem/Hpe/HpeiLO.SendTestSyslog {}`,
+	}
+	if got := writes(); !reflect.DeepEqual(got, wantWrites) {
+		t.Errorf("writes = %q, want %q", got, wantWrites)
+	}
+}